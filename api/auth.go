@@ -0,0 +1,418 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+	"github.com/tedsuo/rata"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/volume"
+)
+
+var ErrUnauthorized = errors.New("unauthorized")
+var ErrForbidden = errors.New("forbidden")
+var ErrAuthorizeFailed = errors.New("failed to authorize request")
+
+// Scope is a kind of operation that a token may be granted.
+type Scope string
+
+const (
+	// ScopeRead allows looking at volumes and their contents' metadata.
+	ScopeRead Scope = "read"
+
+	// ScopeStream allows streaming volumes' contents in and out.
+	ScopeStream Scope = "stream"
+
+	// ScopeCreate allows creating volumes and changing them.
+	ScopeCreate Scope = "create"
+
+	// ScopeDestroy allows destroying volumes and snapshots.
+	ScopeDestroy Scope = "destroy"
+)
+
+// routeScopes is the scope that each route requires. Routes missing from it
+// are refused to every token.
+var routeScopes = map[string]Scope{
+	baggageclaim.ListVolumes:            ScopeRead,
+	baggageclaim.GetVolume:              ScopeRead,
+	baggageclaim.GetPrivileged:          ScopeRead,
+	baggageclaim.GetUsage:               ScopeRead,
+	baggageclaim.GetDigest:              ScopeRead,
+	baggageclaim.GetFile:                ScopeRead,
+	baggageclaim.GetTree:                ScopeRead,
+	baggageclaim.GetDiff:                ScopeRead,
+	baggageclaim.Events:                 ScopeRead,
+	baggageclaim.GetOrphans:             ScopeRead,
+	baggageclaim.ListCorruptedVolumes:   ScopeRead,
+	baggageclaim.ListQuarantinedVolumes: ScopeRead,
+
+	baggageclaim.StreamIn:       ScopeStream,
+	baggageclaim.StreamInOffset: ScopeStream,
+	baggageclaim.StreamOut:      ScopeStream,
+	baggageclaim.StreamP2pOut:   ScopeStream,
+	baggageclaim.GetP2pUrl:      ScopeStream,
+
+	baggageclaim.CreateVolume:            ScopeCreate,
+	baggageclaim.CreateVolumeAsync:       ScopeCreate,
+	baggageclaim.CreateVolumeAsyncCheck:  ScopeCreate,
+	baggageclaim.CreateVolumeAsyncCancel: ScopeCreate,
+	baggageclaim.SetProperty:             ScopeCreate,
+	baggageclaim.SetPrivileged:           ScopeCreate,
+	baggageclaim.SetTTL:                  ScopeCreate,
+	baggageclaim.SetReadOnly:             ScopeCreate,
+	baggageclaim.CreateSnapshot:          ScopeCreate,
+	baggageclaim.RestoreSnapshot:         ScopeCreate,
+	baggageclaim.RenameVolume:            ScopeCreate,
+
+	baggageclaim.DestroyVolume:   ScopeDestroy,
+	baggageclaim.DestroyVolumes:  ScopeDestroy,
+	baggageclaim.DestroySnapshot: ScopeDestroy,
+	baggageclaim.Fsck:            ScopeDestroy,
+
+	baggageclaim.DestroyQuarantinedVolume: ScopeDestroy,
+}
+
+// Token grants its bearer the scopes. If it has properties, it is limited to
+// the volumes carrying them, and to creating volumes carrying them.
+type Token struct {
+	Token      string            `json:"token"`
+	Scopes     []Scope           `json:"scopes"`
+	Properties volume.Properties `json:"properties,omitempty"`
+}
+
+func (token Token) allows(scope Scope) bool {
+	for _, s := range token.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (token Token) restricted() bool {
+	return len(token.Properties) > 0
+}
+
+// LoadTokens reads a JSON list of tokens.
+func LoadTokens(path string) ([]Token, error) {
+	tokensJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []Token
+	err = json.Unmarshal(tokensJSON, &tokens)
+	if err != nil {
+		return nil, fmt.Errorf("malformed tokens: %w", err)
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no tokens found in %s", path)
+	}
+
+	err = validateTokens(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// validateTokens checks that each token is given once, is not empty and only
+// grants known scopes.
+func validateTokens(tokens []Token) error {
+	seen := map[string]bool{}
+
+	for i, token := range tokens {
+		if token.Token == "" {
+			return fmt.Errorf("token %d is empty", i)
+		}
+
+		for _, scope := range token.Scopes {
+			switch scope {
+			case ScopeRead, ScopeStream, ScopeCreate, ScopeDestroy:
+			default:
+				return fmt.Errorf("token %d has unknown scope: %s", i, scope)
+			}
+		}
+
+		if seen[token.Token] {
+			return fmt.Errorf("token %d is given more than once", i)
+		}
+
+		seen[token.Token] = true
+	}
+
+	return nil
+}
+
+type tokenAuth struct {
+	tokens map[[sha256.Size]byte]Token
+
+	volumeRepo     volume.Repository
+	streamInSigner *StreamInSigner
+
+	// asyncCreation returns the properties that a pending async creation was
+	// requested with
+	asyncCreation func(handle string) (volume.Properties, bool)
+
+	logger lager.Logger
+}
+
+func newTokenAuth(logger lager.Logger, tokens []Token, volumeRepo volume.Repository, asyncCreation func(string) (volume.Properties, bool), streamInSigner *StreamInSigner) (*tokenAuth, error) {
+	auth := &tokenAuth{
+		tokens:         map[[sha256.Size]byte]Token{},
+		volumeRepo:     volumeRepo,
+		asyncCreation:  asyncCreation,
+		streamInSigner: streamInSigner,
+		logger:         logger,
+	}
+
+	err := validateTokens(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range tokens {
+		// tokens are looked up by their digest so that the lookup does not
+		// leak how much of a guess was right
+		auth.tokens[sha256.Sum256([]byte(token.Token))] = token
+	}
+
+	return auth, nil
+}
+
+// wrap refuses requests for the route unless they present a token granting
+// the route's scope over the volumes that the request touches.
+func (auth *tokenAuth) wrap(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		hLog := auth.logger.Session("authorize", lager.Data{
+			"route": route,
+		})
+
+		// peers cannot hold tokens, so the stream-in urls handed to them are
+		// authorized by their signature, which is verified by the handler
+		if route == baggageclaim.StreamIn && auth.streamInSigner != nil && isSignedStreamIn(req.URL.Query()) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		token, found := auth.authenticate(req)
+		if !found {
+			hLog.Info("unauthorized")
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			RespondWithError(w, ErrUnauthorized, http.StatusUnauthorized)
+			return
+		}
+
+		scope, known := routeScopes[route]
+		if !known || !token.allows(scope) {
+			hLog.Info("missing-scope", lager.Data{"scope": scope})
+			RespondWithError(w, ErrForbidden, http.StatusForbidden)
+			return
+		}
+
+		if token.restricted() {
+			ctx := lagerctx.NewContext(req.Context(), hLog)
+
+			allowed, err := auth.allowedVolumes(ctx, route, token, req)
+			if err != nil {
+				hLog.Error("failed-to-authorize", err)
+				RespondWithError(w, ErrAuthorizeFailed, http.StatusInternalServerError)
+				return
+			}
+
+			if !allowed {
+				hLog.Info("outside-properties")
+				RespondWithError(w, ErrForbidden, http.StatusForbidden)
+				return
+			}
+		}
+
+		handler.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), authenticatedKey{}, true)))
+	})
+}
+
+type authenticatedKey struct{}
+
+// authenticated returns whether the request presented a token granting its
+// route, as opposed to being let through by a signed stream-in url or by
+// there being no tokens configured.
+func authenticated(req *http.Request) bool {
+	_, found := req.Context().Value(authenticatedKey{}).(bool)
+	return found
+}
+
+func (auth *tokenAuth) authenticate(req *http.Request) (Token, bool) {
+	header := req.Header.Get("Authorization")
+
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return Token{}, false
+	}
+
+	token, found := auth.tokens[sha256.Sum256([]byte(header[len(prefix):]))]
+	return token, found
+}
+
+// allowedVolumes returns whether the request only touches volumes carrying
+// the token's properties. Requests that are not about particular volumes are
+// refused, as they could reveal or affect any volume, as are requests whose
+// body cannot be made sense of.
+func (auth *tokenAuth) allowedVolumes(ctx context.Context, route string, token Token, req *http.Request) (bool, error) {
+	switch route {
+	case baggageclaim.ListVolumes:
+		return auth.restrictListing(token, req), nil
+
+	case baggageclaim.CreateVolume, baggageclaim.CreateVolumeAsync:
+		return auth.allowedCreation(ctx, token, req)
+
+	case baggageclaim.DestroyVolumes:
+		var handles []string
+		err := peekJSON(req, &handles)
+		if err != nil {
+			return false, nil
+		}
+
+		return auth.allowedHandles(ctx, token, handles...)
+
+	case baggageclaim.SetProperty:
+		property := rata.Param(req, "property")
+		if value, limited := token.Properties[property]; limited {
+			var request baggageclaim.PropertyRequest
+			err := peekJSON(req, &request)
+			if err != nil {
+				return false, nil
+			}
+
+			// the volume must not be handed to anyone else
+			if request.Value != value {
+				return false, nil
+			}
+		}
+
+		return auth.allowedHandles(ctx, token, rata.Param(req, "handle"))
+
+	case baggageclaim.CreateVolumeAsyncCheck, baggageclaim.CreateVolumeAsyncCancel:
+		handle := rata.Param(req, "handle")
+
+		// the volume does not exist until its creation succeeds
+		properties, found := auth.asyncCreation(handle)
+		if found {
+			return properties.HasProperties(token.Properties), nil
+		}
+
+		return auth.allowedHandles(ctx, token, handle)
+
+	case baggageclaim.GetP2pUrl:
+		handle := req.URL.Query().Get("handle")
+		if handle == "" {
+			return true, nil
+		}
+
+		return auth.allowedHandles(ctx, token, handle)
+
+	case baggageclaim.Events, baggageclaim.GetOrphans, baggageclaim.ListCorruptedVolumes, baggageclaim.Fsck,
+		baggageclaim.ListQuarantinedVolumes, baggageclaim.DestroyQuarantinedVolume:
+		return false, nil
+	}
+
+	return auth.allowedHandles(ctx, token, rata.Param(req, "handle"))
+}
+
+// restrictListing limits the listing to the volumes carrying the token's
+// properties, refusing it if it asks for others.
+func (auth *tokenAuth) restrictListing(token Token, req *http.Request) bool {
+	query := req.URL.Query()
+
+	for name, value := range token.Properties {
+		if asked, found := query[name]; found && (len(asked) != 1 || asked[0] != value) {
+			return false
+		}
+
+		query.Set(name, value)
+	}
+
+	req.URL.RawQuery = query.Encode()
+
+	return true
+}
+
+func (auth *tokenAuth) allowedCreation(ctx context.Context, token Token, req *http.Request) (bool, error) {
+	var request baggageclaim.VolumeRequest
+	err := peekJSON(req, &request)
+	if err != nil {
+		return false, nil
+	}
+
+	if !volume.Properties(request.Properties).HasProperties(token.Properties) {
+		return false, nil
+	}
+
+	if request.Strategy == nil {
+		return true, nil
+	}
+
+	var strategy struct {
+		Type   string `json:"type"`
+		Volume string `json:"volume"`
+	}
+
+	err = json.Unmarshal(*request.Strategy, &strategy)
+	if err != nil {
+		return false, nil
+	}
+
+	switch strategy.Type {
+	case volume.StrategyEmpty:
+		return true, nil
+	case volume.StrategyCopyOnWrite:
+		return auth.allowedHandles(ctx, token, strategy.Volume)
+	}
+
+	// the other strategies read from any volume, or from the host; layers are
+	// applied from a path on the host even when their parent is allowed
+	return false, nil
+}
+
+// allowedHandles returns whether the volumes exist and carry the token's
+// properties. Volumes that cannot be seen, such as those being created or
+// destroyed, are refused, as there is no telling whose they are.
+func (auth *tokenAuth) allowedHandles(ctx context.Context, token Token, handles ...string) (bool, error) {
+	for _, handle := range handles {
+		vol, found, err := auth.volumeRepo.GetVolume(ctx, handle)
+		if err != nil {
+			return false, err
+		}
+
+		if !found || !vol.Properties.HasProperties(token.Properties) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// peekJSON decodes the request body, leaving it to be read again by the
+// handler.
+func peekJSON(req *http.Request, dest interface{}) error {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return json.Unmarshal(body, dest)
+}
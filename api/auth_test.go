@@ -0,0 +1,424 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/api"
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+)
+
+var _ = Describe("Authenticating requests", func() {
+	var (
+		fakeRepo       *volumefakes.FakeRepository
+		streamInSigner *api.StreamInSigner
+		tokens         []api.Token
+
+		handler http.Handler
+	)
+
+	BeforeEach(func() {
+		fakeRepo = new(volumefakes.FakeRepository)
+		fakeRepo.GetVolumeStub = func(_ context.Context, handle string) (volume.Volume, bool, error) {
+			switch handle {
+			case "ours":
+				return volume.Volume{Handle: handle, Properties: volume.Properties{"team": "main"}}, true, nil
+			case "theirs":
+				return volume.Volume{Handle: handle, Properties: volume.Properties{"team": "other"}}, true, nil
+			}
+
+			return volume.Volume{}, false, nil
+		}
+
+		streamInSigner = nil
+
+		tokens = []api.Token{
+			{Token: "reader", Scopes: []api.Scope{api.ScopeRead}},
+			{Token: "admin", Scopes: []api.Scope{api.ScopeRead, api.ScopeStream, api.ScopeCreate, api.ScopeDestroy}},
+			{
+				Token:      "main-team",
+				Scopes:     []api.Scope{api.ScopeRead, api.ScopeStream, api.ScopeCreate, api.ScopeDestroy},
+				Properties: volume.Properties{"team": "main"},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		handler, err = api.NewHandler(
+			lagertest.NewTestLogger("auth"),
+			volume.NewStrategerizer(),
+			fakeRepo,
+			regexp.MustCompile("lo"),
+			4,
+			7766,
+			false,
+			streamInSigner,
+			tokens,
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	serve := func(method string, path string, token string, body io.Reader) *httptest.ResponseRecorder {
+		if body == nil {
+			body = &bytes.Buffer{}
+		}
+
+		request, err := http.NewRequest(method, path, body)
+		Expect(err).NotTo(HaveOccurred())
+
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	encode := func(value interface{}) io.Reader {
+		body := &bytes.Buffer{}
+		Expect(json.NewEncoder(body).Encode(value)).To(Succeed())
+		return body
+	}
+
+	It("refuses requests without a token", func() {
+		recorder := serve("GET", "/volumes", "", nil)
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		Expect(recorder.Header().Get("WWW-Authenticate")).To(Equal("Bearer"))
+		Expect(recorder.Body).To(MatchJSON(`{"error":"unauthorized"}`))
+
+		Expect(fakeRepo.VisitVolumesCallCount()).To(BeZero())
+	})
+
+	It("refuses requests with an unknown token", func() {
+		recorder := serve("GET", "/volumes", "bogus", nil)
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("serves requests within the token's scopes", func() {
+		recorder := serve("GET", "/volumes", "reader", nil)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+	})
+
+	It("refuses requests outside of the token's scopes", func() {
+		recorder := serve("DELETE", "/volumes/ours", "reader", nil)
+		Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		Expect(recorder.Body).To(MatchJSON(`{"error":"forbidden"}`))
+
+		Expect(fakeRepo.DestroyVolumeAndDescendantsCallCount()).To(BeZero())
+		Expect(fakeRepo.DestroyVolumeCallCount()).To(BeZero())
+	})
+
+	Context("when there are no tokens", func() {
+		BeforeEach(func() {
+			tokens = nil
+		})
+
+		It("serves every request", func() {
+			recorder := serve("GET", "/volumes", "", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("when a token has an unknown scope", func() {
+		It("fails to create the handler", func() {
+			_, err := api.NewHandler(
+				lagertest.NewTestLogger("auth"),
+				volume.NewStrategerizer(),
+				fakeRepo,
+				regexp.MustCompile("lo"),
+				4,
+				7766,
+				false,
+				nil,
+				[]api.Token{{Token: "some-token", Scopes: []api.Scope{"everything"}}},
+			)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the token is limited to volumes carrying properties", func() {
+		It("serves requests for those volumes", func() {
+			recorder := serve("GET", "/volumes/ours", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("refuses requests for other volumes", func() {
+			recorder := serve("GET", "/volumes/theirs", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+
+			recorder = serve("DELETE", "/volumes/theirs", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeRepo.DestroyVolumeCallCount()).To(BeZero())
+		})
+
+		It("limits listings to those volumes", func() {
+			recorder := serve("GET", "/volumes?some=property", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			_, query, _ := fakeRepo.VisitVolumesArgsForCall(0)
+			Expect(query.Properties).To(Equal(volume.Properties{"some": "property", "team": "main"}))
+		})
+
+		It("pages through restricted listings", func() {
+			recorder := serve("GET", "/volumes?"+baggageclaim.LimitQueryKey+"=1", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			_, query, _ := fakeRepo.VisitVolumesArgsForCall(0)
+			Expect(query.Properties).To(Equal(volume.Properties{"team": "main"}))
+			Expect(query.Limit).ToNot(BeZero())
+		})
+
+		It("refuses listings of other volumes", func() {
+			recorder := serve("GET", "/volumes?team=other", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeRepo.VisitVolumesCallCount()).To(BeZero())
+		})
+
+		It("only creates volumes carrying the properties", func() {
+			recorder := serve("POST", "/volumes", "main-team", encode(baggageclaim.VolumeRequest{
+				Handle:     "new",
+				Strategy:   encStrategy(map[string]string{"type": "empty"}),
+				Properties: baggageclaim.VolumeProperties{"team": "main"},
+			}))
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+
+			recorder = serve("POST", "/volumes", "main-team", encode(baggageclaim.VolumeRequest{
+				Handle:     "new",
+				Strategy:   encStrategy(map[string]string{"type": "empty"}),
+				Properties: baggageclaim.VolumeProperties{"team": "other"},
+			}))
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+
+			Expect(fakeRepo.CreateVolumeCallCount()).To(Equal(1))
+		})
+
+		It("only creates copies of those volumes", func() {
+			recorder := serve("POST", "/volumes", "main-team", encode(baggageclaim.VolumeRequest{
+				Handle:     "new",
+				Strategy:   encStrategy(map[string]string{"type": "cow", "volume": "ours"}),
+				Properties: baggageclaim.VolumeProperties{"team": "main"},
+			}))
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+
+			recorder = serve("POST", "/volumes", "main-team", encode(baggageclaim.VolumeRequest{
+				Handle:     "new",
+				Strategy:   encStrategy(map[string]string{"type": "cow", "volume": "theirs"}),
+				Properties: baggageclaim.VolumeProperties{"team": "main"},
+			}))
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("refuses layers applied from the host, even onto those volumes", func() {
+			recorder := serve("POST", "/volumes", "main-team", encode(baggageclaim.VolumeRequest{
+				Handle:     "new",
+				Strategy:   encStrategy(map[string]string{"type": "layer", "volume": "ours", "path": "/etc"}),
+				Properties: baggageclaim.VolumeProperties{"team": "main"},
+			}))
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeRepo.CreateVolumeCallCount()).To(BeZero())
+		})
+
+		It("refuses strategies that could read from anywhere", func() {
+			recorder := serve("POST", "/volumes", "main-team", encode(baggageclaim.VolumeRequest{
+				Handle:     "new",
+				Strategy:   encStrategy(map[string]string{"type": "import", "path": "/etc"}),
+				Properties: baggageclaim.VolumeProperties{"team": "main"},
+			}))
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("refuses to hand volumes to others by setting their properties", func() {
+			recorder := serve("PUT", "/volumes/ours/properties/team", "main-team", encode(baggageclaim.PropertyRequest{Value: "other"}))
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+
+			recorder = serve("PUT", "/volumes/ours/properties/some", "main-team", encode(baggageclaim.PropertyRequest{Value: "thing"}))
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+			Expect(fakeRepo.SetPropertyCallCount()).To(Equal(1))
+		})
+
+		It("only destroys those volumes in bulk", func() {
+			recorder := serve("DELETE", "/volumes/destroy", "main-team", encode([]string{"ours", "theirs"}))
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeRepo.DestroyVolumeCallCount()).To(BeZero())
+		})
+
+		It("refuses requests for volumes that cannot be seen", func() {
+			recorder := serve("GET", "/volumes/missing", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+
+			recorder = serve("DELETE", "/volumes/missing", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeRepo.DestroyVolumeCallCount()).To(BeZero())
+
+			recorder = serve("GET", "/volumes-async/missing", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+
+		Context("when volumes are being created asynchronously", func() {
+			var created chan struct{}
+
+			BeforeEach(func() {
+				created = make(chan struct{})
+				fakeRepo.CreateVolumeStub = func(_ context.Context, handle string, spec volume.VolumeSpec) (volume.Volume, error) {
+					<-created
+					return volume.Volume{Handle: handle, Properties: spec.Properties}, nil
+				}
+			})
+
+			AfterEach(func() {
+				close(created)
+			})
+
+			createAsync := func(handle string, team string) {
+				recorder := serve("POST", "/volumes-async", "admin", encode(baggageclaim.VolumeRequest{
+					Handle:     handle,
+					Strategy:   encStrategy(map[string]string{"type": "empty"}),
+					Properties: baggageclaim.VolumeProperties{"team": team},
+				}))
+				Expect(recorder.Code).To(Equal(http.StatusCreated))
+			}
+
+			It("lets the token check on and cancel those carrying the properties", func() {
+				createAsync("pending", "main")
+
+				recorder := serve("GET", "/volumes-async/pending", "main-team", nil)
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+				recorder = serve("DELETE", "/volumes-async/pending", "main-team", nil)
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			})
+
+			It("refuses to check on or cancel others", func() {
+				createAsync("pending", "other")
+
+				recorder := serve("GET", "/volumes-async/pending", "main-team", nil)
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+
+				recorder = serve("DELETE", "/volumes-async/pending", "main-team", nil)
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+
+				recorder = serve("GET", "/volumes-async/pending", "admin", nil)
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+
+		It("refuses requests that could reveal any volume", func() {
+			recorder := serve("GET", "/events", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+
+			recorder = serve("GET", "/orphans", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+
+			recorder = serve("GET", "/volumes/corrupted", "main-team", nil)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Context("when stream-in urls handed to peers are signed", func() {
+		BeforeEach(func() {
+			streamInSigner = api.NewStreamInSigner([]byte("some-secret"), time.Minute)
+		})
+
+		It("lets peers stream in with a signed url", func() {
+			query := streamInSigner.Sign("ours", "some-path").Encode()
+
+			recorder := serve("PUT", "/volumes/ours/stream-in?"+query, "", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			Expect(fakeRepo.StreamInCallCount()).To(Equal(1))
+		})
+
+		It("refuses peers with a forged url", func() {
+			recorder := serve("PUT", "/volumes/ours/stream-in?path=some-path&expires=9999999999&signature=forged", "", nil)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeRepo.StreamInCallCount()).To(BeZero())
+		})
+
+		It("refuses an unsigned stream-in without a token", func() {
+			recorder := serve("PUT", "/volumes/ours/stream-in?path=some-path", "", nil)
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(fakeRepo.StreamInCallCount()).To(BeZero())
+		})
+
+		It("lets token holders stream in without a signed url", func() {
+			recorder := serve("PUT", "/volumes/ours/stream-in?path=some-path", "admin", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			Expect(fakeRepo.StreamInCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("LoadTokens", func() {
+		var tokensPath string
+
+		BeforeEach(func() {
+			tempDir, err := ioutil.TempDir("", "baggageclaim-tokens")
+			Expect(err).NotTo(HaveOccurred())
+
+			tokensPath = filepath.Join(tempDir, "tokens.json")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(filepath.Dir(tokensPath))
+		})
+
+		It("reads the tokens", func() {
+			err := ioutil.WriteFile(tokensPath, []byte(`[{"token":"some-token","scopes":["read"],"properties":{"team":"main"}}]`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(api.LoadTokens(tokensPath)).To(Equal([]api.Token{{
+				Token:      "some-token",
+				Scopes:     []api.Scope{api.ScopeRead},
+				Properties: volume.Properties{"team": "main"},
+			}}))
+		})
+
+		It("fails when there are none", func() {
+			err := ioutil.WriteFile(tokensPath, []byte(`[]`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = api.LoadTokens(tokensPath)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails when a token is empty", func() {
+			err := ioutil.WriteFile(tokensPath, []byte(`[{"token":"","scopes":["read"]}]`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = api.LoadTokens(tokensPath)
+			Expect(err).To(MatchError("token 0 is empty"))
+		})
+
+		It("fails when a token has an unknown scope", func() {
+			err := ioutil.WriteFile(tokensPath, []byte(`[{"token":"some-token","scopes":["everything"]}]`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = api.LoadTokens(tokensPath)
+			Expect(err).To(MatchError("token 0 has unknown scope: everything"))
+		})
+
+		It("fails when a token is given more than once", func() {
+			err := ioutil.WriteFile(tokensPath, []byte(`[{"token":"some-token","scopes":["read"]},{"token":"some-token","scopes":["stream"]}]`), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = api.LoadTokens(tokensPath)
+			Expect(err).To(MatchError("token 1 is given more than once"))
+		})
+	})
+})
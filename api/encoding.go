@@ -0,0 +1,67 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/concourse/baggageclaim/volume"
+)
+
+// NegotiateEncoding picks the registered encoding to stream a volume out with
+// from an Accept-Encoding header, preferring higher quality values and then
+// whichever was listed first. A wildcard selects gzip. No header at all means
+// the client takes the stream uncompressed.
+func NegotiateEncoding(acceptEncoding string) (string, bool) {
+	if strings.TrimSpace(acceptEncoding) == "" {
+		return volume.IdentityEncoding, true
+	}
+
+	var best string
+	var bestQuality float64
+
+	for _, entry := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(entry, ";")
+
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		if name == "*" {
+			name = volume.GzipEncoding
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err != nil {
+				q = 0
+			}
+
+			quality = q
+		}
+
+		if quality <= bestQuality {
+			continue
+		}
+
+		if _, found := volume.LookupEncoding(name); !found {
+			continue
+		}
+
+		best, bestQuality = name, quality
+	}
+
+	return best, best != ""
+}
+
+// contentEncoding returns the encoding of a stream sent with the given
+// Content-Encoding header, which is uncompressed if the header is absent.
+func contentEncoding(header string) string {
+	if header == "" {
+		return volume.IdentityEncoding
+	}
+
+	return strings.ToLower(strings.TrimSpace(header))
+}
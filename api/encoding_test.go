@@ -0,0 +1,56 @@
+package api_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/api"
+)
+
+var _ = Describe("Encoding negotiation", func() {
+	negotiate := func(acceptEncoding string) string {
+		encoding, ok := api.NegotiateEncoding(acceptEncoding)
+		if !ok {
+			return "<none>"
+		}
+
+		return encoding
+	}
+
+	It("picks the only encoding given", func() {
+		Expect(negotiate("zstd")).To(Equal("zstd"))
+	})
+
+	It("picks the encoding with the highest quality", func() {
+		Expect(negotiate("gzip;q=0.2, lz4;q=0.9, xz;q=0.5")).To(Equal("lz4"))
+	})
+
+	It("picks the first listed encoding between equal qualities", func() {
+		Expect(negotiate("xz, gzip")).To(Equal("xz"))
+	})
+
+	It("ignores unknown encodings", func() {
+		Expect(negotiate("br, gzip;q=0.1")).To(Equal("gzip"))
+	})
+
+	It("never picks an encoding with a quality of zero", func() {
+		Expect(negotiate("gzip;q=0")).To(Equal("<none>"))
+		Expect(negotiate("gzip;q=0, identity;q=0.1")).To(Equal("identity"))
+	})
+
+	It("is case and whitespace insensitive", func() {
+		Expect(negotiate("  GZIP ; q=0.5 ,Zstd;q=0.4")).To(Equal("gzip"))
+	})
+
+	It("picks gzip for a wildcard", func() {
+		Expect(negotiate("*")).To(Equal("gzip"))
+	})
+
+	It("picks identity when no encoding is requested", func() {
+		Expect(negotiate("")).To(Equal("identity"))
+	})
+
+	It("fails when nothing acceptable is supported", func() {
+		Expect(negotiate("br, compress")).To(Equal("<none>"))
+	})
+})
@@ -19,11 +19,15 @@ func NewHandler(
 	p2pInterfacePattern *regexp.Regexp,
 	p2pInterfaceFamily int,
 	p2pStreamPort uint16,
+	p2pTLS bool,
+	streamInSigner *StreamInSigner,
+	tokens []Token,
 ) (http.Handler, error) {
 	volumeServer := NewVolumeServer(
 		logger.Session("volume-server"),
 		strategerizer,
 		volumeRepo,
+		streamInSigner,
 	)
 
 	p2pServer := NewP2pServer(
@@ -31,6 +35,8 @@ func NewHandler(
 		p2pInterfacePattern,
 		p2pInterfaceFamily,
 		p2pStreamPort,
+		p2pTLS,
+		streamInSigner,
 	)
 
 	handlers := rata.Handlers{
@@ -43,13 +49,49 @@ func NewHandler(
 		baggageclaim.SetProperty:             http.HandlerFunc(volumeServer.SetProperty),
 		baggageclaim.GetPrivileged:           http.HandlerFunc(volumeServer.GetPrivileged),
 		baggageclaim.SetPrivileged:           http.HandlerFunc(volumeServer.SetPrivileged),
+		baggageclaim.SetTTL:                  http.HandlerFunc(volumeServer.SetTTL),
+		baggageclaim.SetReadOnly:             http.HandlerFunc(volumeServer.SetReadOnly),
+		baggageclaim.GetUsage:                http.HandlerFunc(volumeServer.GetUsage),
+		baggageclaim.GetDigest:               http.HandlerFunc(volumeServer.GetDigest),
 		baggageclaim.StreamIn:                http.HandlerFunc(volumeServer.StreamIn),
+		baggageclaim.StreamInOffset:          http.HandlerFunc(volumeServer.StreamInOffset),
 		baggageclaim.StreamOut:               http.HandlerFunc(volumeServer.StreamOut),
 		baggageclaim.StreamP2pOut:            http.HandlerFunc(volumeServer.StreamP2pOut),
+		baggageclaim.GetFile:                 http.HandlerFunc(volumeServer.GetFile),
+		baggageclaim.GetTree:                 http.HandlerFunc(volumeServer.GetTree),
+		baggageclaim.GetDiff:                 http.HandlerFunc(volumeServer.GetDiff),
+		baggageclaim.CreateSnapshot:          http.HandlerFunc(volumeServer.CreateSnapshot),
+		baggageclaim.RestoreSnapshot:         http.HandlerFunc(volumeServer.RestoreSnapshot),
+		baggageclaim.DestroySnapshot:         http.HandlerFunc(volumeServer.DestroySnapshot),
 		baggageclaim.DestroyVolume:           http.HandlerFunc(volumeServer.DestroyVolume),
 		baggageclaim.DestroyVolumes:          http.HandlerFunc(volumeServer.DestroyVolumes),
+		baggageclaim.RenameVolume:            http.HandlerFunc(volumeServer.RenameVolume),
 
 		baggageclaim.GetP2pUrl: http.HandlerFunc(p2pServer.GetP2pUrl),
+
+		baggageclaim.Events: http.HandlerFunc(volumeServer.Events),
+
+		baggageclaim.GetOrphans:               http.HandlerFunc(volumeServer.GetOrphans),
+		baggageclaim.ListCorruptedVolumes:     http.HandlerFunc(volumeServer.ListCorruptedVolumes),
+		baggageclaim.ListQuarantinedVolumes:   http.HandlerFunc(volumeServer.ListQuarantinedVolumes),
+		baggageclaim.DestroyQuarantinedVolume: http.HandlerFunc(volumeServer.DestroyQuarantinedVolume),
+		baggageclaim.Fsck:                     http.HandlerFunc(volumeServer.Fsck),
+	}
+
+	// requests are let through unauthenticated if there are no tokens
+	if tokens != nil {
+		auth, err := newTokenAuth(logger.Session("auth"), tokens, volumeRepo, volumeServer.asyncCreation, streamInSigner)
+		if err != nil {
+			return nil, err
+		}
+
+		for route, handler := range handlers {
+			handlers[route] = auth.wrap(route, handler)
+		}
+	}
+
+	for route, handler := range handlers {
+		handlers[route] = instrumentHandler(route, handler)
 	}
 
 	return rata.NewRouter(baggageclaim.Routes, handlers)
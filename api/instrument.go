@@ -0,0 +1,55 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/concourse/baggageclaim/metrics"
+)
+
+func instrumentHandler(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+
+		recorder := &statusRecorder{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+
+		handler.ServeHTTP(recorder, req)
+
+		metrics.RequestDuration.WithLabelValues(route).Observe(time.Since(start).Seconds())
+		metrics.RequestsTotal.WithLabelValues(route, strconv.Itoa(recorder.status)).Inc()
+	})
+}
+
+// statusRecorder captures the status code written by a handler. It passes
+// flushes through so that streaming responses keep working, and ReadFrom so
+// that copying a file into the response can still use sendfile.
+type statusRecorder struct {
+	http.ResponseWriter
+
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (recorder *statusRecorder) ReadFrom(r io.Reader) (int64, error) {
+	if readerFrom, ok := recorder.ResponseWriter.(io.ReaderFrom); ok {
+		return readerFrom.ReadFrom(r)
+	}
+
+	// hide ReadFrom from io.Copy, which would otherwise call back into it
+	return io.Copy(struct{ io.Writer }{recorder.ResponseWriter}, r)
+}
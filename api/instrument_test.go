@@ -0,0 +1,51 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("instrumentHandler", func() {
+	It("lets handlers copy into the response with ReadFrom", func() {
+		writer := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+		handler := instrumentHandler("some-route", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := io.Copy(w, struct{ io.Reader }{strings.NewReader("some-contents")})
+			Expect(err).ToNot(HaveOccurred())
+		}))
+
+		handler.ServeHTTP(writer, httptest.NewRequest("GET", "/", nil))
+
+		Expect(writer.readFrom).To(BeTrue())
+		Expect(writer.Body.String()).To(Equal("some-contents"))
+	})
+
+	It("still copies into a response that cannot ReadFrom", func() {
+		writer := httptest.NewRecorder()
+
+		handler := instrumentHandler("some-route", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := io.Copy(w, struct{ io.Reader }{strings.NewReader("some-contents")})
+			Expect(err).ToNot(HaveOccurred())
+		}))
+
+		handler.ServeHTTP(writer, httptest.NewRequest("GET", "/", nil))
+
+		Expect(writer.Body.String()).To(Equal("some-contents"))
+	})
+})
+
+type readerFromRecorder struct {
+	*httptest.ResponseRecorder
+
+	readFrom bool
+}
+
+func (recorder *readerFromRecorder) ReadFrom(r io.Reader) (int64, error) {
+	recorder.readFrom = true
+	return io.Copy(recorder.ResponseRecorder, r)
+}
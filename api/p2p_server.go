@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/tedsuo/rata"
 )
 
 var ErrGetP2pUrlFailed = errors.New("failed to get p2p url")
@@ -17,11 +21,15 @@ func NewP2pServer(
 	p2pInterfacePattern *regexp.Regexp,
 	p2pInterfaceFamily int,
 	p2pStreamPort uint16,
+	p2pTLS bool,
+	streamInSigner *StreamInSigner,
 ) *P2pServer {
 	return &P2pServer{
 		p2pInterfacePattern: p2pInterfacePattern,
 		p2pInterfaceFamily:  p2pInterfaceFamily,
 		p2pStreamPort:       p2pStreamPort,
+		p2pTLS:              p2pTLS,
+		streamInSigner:      streamInSigner,
 		logger:              logger,
 	}
 }
@@ -31,6 +39,12 @@ type P2pServer struct {
 	p2pInterfaceFamily  int
 	p2pStreamPort       uint16
 
+	// whether peers must stream to the port over TLS
+	p2pTLS bool
+
+	// signs the stream-in urls handed to peers, if configured
+	streamInSigner *StreamInSigner
+
 	logger lager.Logger
 }
 
@@ -39,6 +53,14 @@ func (server *P2pServer) GetP2pUrl(w http.ResponseWriter, req *http.Request) {
 	hLog.Debug("start")
 	defer hLog.Debug("done")
 
+	// a signed url lets whoever holds it stream in, so only token holders
+	// may have one minted
+	if server.streamInSigner != nil && req.URL.Query().Get("handle") != "" && !authenticated(req) {
+		hLog.Info("unauthenticated")
+		RespondWithError(w, ErrForbidden, http.StatusForbidden)
+		return
+	}
+
 	ifaces, err := net.Interfaces()
 	if err != nil {
 		RespondWithError(w, ErrGetP2pUrlFailed, http.StatusInternalServerError)
@@ -76,7 +98,38 @@ func (server *P2pServer) GetP2pUrl(w http.ResponseWriter, req *http.Request) {
 			}
 			hLog.Debug("found-ip", lager.Data{"ip": ip.String()})
 
-			fmt.Fprintf(w, "http://%s:%d", ip.String(), server.p2pStreamPort)
+			scheme := "http"
+			if server.p2pTLS {
+				scheme = "https"
+			}
+
+			p2pUrl := url.URL{
+				Scheme: scheme,
+				Host:   net.JoinHostPort(ip.String(), fmt.Sprint(server.p2pStreamPort)),
+			}
+
+			// when asked for a volume, hand out the whole stream-in url so
+			// that it can be signed
+			query := req.URL.Query()
+			if handle := query.Get("handle"); handle != "" {
+				path, err := baggageclaim.Routes.CreatePathForRoute(baggageclaim.StreamIn, rata.Params{
+					"handle": handle,
+				})
+				if err != nil {
+					RespondWithError(w, ErrGetP2pUrlFailed, http.StatusInternalServerError)
+					return
+				}
+
+				p2pUrl.Path = path
+
+				if server.streamInSigner != nil {
+					p2pUrl.RawQuery = server.streamInSigner.Sign(handle, query.Get("path")).Encode()
+				} else {
+					p2pUrl.RawQuery = url.Values{"path": []string{query.Get("path")}}.Encode()
+				}
+			}
+
+			fmt.Fprint(w, p2pUrl.String())
 			return
 		}
 	}
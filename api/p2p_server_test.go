@@ -3,8 +3,10 @@ package api_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"regexp"
 	"runtime"
+	"time"
 
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/baggageclaim/api"
@@ -16,13 +18,22 @@ var _ = Describe("P2P Server", func() {
 	var (
 		handler http.Handler
 		infc    string
+		p2pTLS  bool
+		signer  *api.StreamInSigner
+		tokens  []api.Token
 	)
 
+	BeforeEach(func() {
+		p2pTLS = false
+		signer = nil
+		tokens = nil
+	})
+
 	JustBeforeEach(func() {
 		var err error
 		logger := lagertest.NewTestLogger("p2p-server")
 		re := regexp.MustCompile(infc)
-		handler, err = api.NewHandler(logger, nil, nil, re, 4, 7766)
+		handler, err = api.NewHandler(logger, nil, nil, re, 4, 7766, p2pTLS, signer, tokens)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -30,12 +41,22 @@ var _ = Describe("P2P Server", func() {
 		var (
 			request  *http.Request
 			recorder *httptest.ResponseRecorder
+			query    string
+			token    string
 		)
+		BeforeEach(func() {
+			query = ""
+			token = ""
+		})
 		JustBeforeEach(func() {
 			var err error
-			request, err = http.NewRequest("GET", "/p2p-url", nil)
+			request, err = http.NewRequest("GET", "/p2p-url"+query, nil)
 			Expect(err).NotTo(HaveOccurred())
 
+			if token != "" {
+				request.Header.Set("Authorization", "Bearer "+token)
+			}
+
 			recorder = httptest.NewRecorder()
 			handler.ServeHTTP(recorder, request)
 		})
@@ -52,6 +73,56 @@ var _ = Describe("P2P Server", func() {
 				Expect(recorder.Code).To(Equal(200))
 				Expect(recorder.Body.String()).To(Equal("http://127.0.0.1:7766"))
 			})
+
+			Context("when peers must stream over TLS", func() {
+				BeforeEach(func() {
+					p2pTLS = true
+				})
+
+				It("returns an https url", func() {
+					Expect(recorder.Code).To(Equal(200))
+					Expect(recorder.Body.String()).To(Equal("https://127.0.0.1:7766"))
+				})
+			})
+
+			Context("when asked for a volume's stream-in url", func() {
+				BeforeEach(func() {
+					query = "?handle=some-handle&path=some%2Fpath"
+				})
+
+				It("returns the whole url", func() {
+					Expect(recorder.Code).To(Equal(200))
+					Expect(recorder.Body.String()).To(Equal("http://127.0.0.1:7766/volumes/some-handle/stream-in?path=some%2Fpath"))
+				})
+
+				Context("when stream-in urls are signed", func() {
+					BeforeEach(func() {
+						signer = api.NewStreamInSigner([]byte("some-secret"), time.Minute)
+					})
+
+					It("refuses to sign a url for an unauthenticated caller", func() {
+						Expect(recorder.Code).To(Equal(403))
+					})
+
+					Context("when the caller is authenticated", func() {
+						BeforeEach(func() {
+							tokens = []api.Token{{Token: "some-token", Scopes: []api.Scope{api.ScopeStream}}}
+							token = "some-token"
+						})
+
+						It("returns a url signed for the volume and path", func() {
+							Expect(recorder.Code).To(Equal(200))
+
+							streamInUrl, err := url.Parse(recorder.Body.String())
+							Expect(err).NotTo(HaveOccurred())
+							Expect(streamInUrl.Host).To(Equal("127.0.0.1:7766"))
+							Expect(streamInUrl.Path).To(Equal("/volumes/some-handle/stream-in"))
+							Expect(streamInUrl.Query().Get("path")).To(Equal("some/path"))
+							Expect(signer.Verify("some-handle", "some/path", streamInUrl.Query())).To(Succeed())
+						})
+					})
+				})
+			})
 		})
 
 		Context("when an invalid interface name is given", func() {
@@ -0,0 +1,22 @@
+package api_test
+
+import (
+	"os"
+	"syscall"
+
+	. "github.com/onsi/gomega"
+)
+
+// allocatedBytes totals the blocks allocated to the given files, which is
+// what volume usage is measured in.
+func allocatedBytes(paths ...string) uint64 {
+	var bytes uint64
+	for _, path := range paths {
+		info, err := os.Lstat(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		bytes += uint64(info.Sys().(*syscall.Stat_t).Blocks) * 512
+	}
+
+	return bytes
+}
@@ -0,0 +1,25 @@
+// +build !linux
+
+package api_test
+
+import (
+	"os"
+
+	. "github.com/onsi/gomega"
+)
+
+// allocatedBytes totals the apparent size of the given files, which is what
+// volume usage is measured in on this platform.
+func allocatedBytes(paths ...string) uint64 {
+	var bytes uint64
+	for _, path := range paths {
+		info, err := os.Lstat(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		if info.Mode().IsRegular() {
+			bytes += uint64(info.Size())
+		}
+	}
+
+	return bytes
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/volume"
+)
+
+var ErrListVolumesInvalidLimit = errors.New("limit must be a positive integer")
+var ErrListVolumesInvalidSort = errors.New("volumes can only be sorted by handle, created, or size")
+var ErrListVolumesInvalidContinue = errors.New("invalid continue token")
+var ErrListVolumesInvalidFields = errors.New("unknown volume field")
+var ErrListVolumesReservedQuery = errors.New("unknown listing parameter; property filters cannot start with " + baggageclaim.ReservedQueryPrefix)
+
+// volumeFields are the JSON fields of a volume that may be selected.
+var volumeFields = map[string]bool{
+	"handle":     true,
+	"path":       true,
+	"properties": true,
+	"privileged": true,
+	"usage":      true,
+	"ttl":        true,
+	"snapshots":  true,
+	"read_only":  true,
+	"created_at": true,
+}
+
+// volumeListing is how a listing of volumes was asked to be paged, sorted,
+// and trimmed.
+type volumeListing struct {
+	query  volume.VolumeQuery
+	fields []string
+}
+
+// parseVolumeListing takes the listing's reserved keys out of the query,
+// leaving only the property filters.
+func parseVolumeListing(query url.Values) (volumeListing, error) {
+	listing := volumeListing{
+		query: volume.VolumeQuery{SortBy: volume.SortByHandle},
+	}
+
+	if sortBy := query.Get(baggageclaim.SortQueryKey); sortBy != "" {
+		if strings.HasPrefix(sortBy, "-") {
+			listing.query.Descending = true
+			sortBy = sortBy[1:]
+		}
+
+		switch volume.VolumeSortKey(sortBy) {
+		case volume.SortByHandle, volume.SortByCreatedAt, volume.SortBySize:
+			listing.query.SortBy = volume.VolumeSortKey(sortBy)
+		default:
+			return volumeListing{}, ErrListVolumesInvalidSort
+		}
+	}
+
+	if limit := query.Get(baggageclaim.LimitQueryKey); limit != "" {
+		var err error
+		listing.query.Limit, err = strconv.Atoi(limit)
+		if err != nil || listing.query.Limit <= 0 {
+			return volumeListing{}, ErrListVolumesInvalidLimit
+		}
+	}
+
+	if token := query.Get(baggageclaim.ContinueQueryKey); token != "" {
+		cursor, err := decodeContinueToken(token, listing.query)
+		if err != nil {
+			return volumeListing{}, err
+		}
+
+		listing.query.After = &cursor
+	}
+
+	if fields := query.Get(baggageclaim.FieldsQueryKey); fields != "" {
+		for _, field := range strings.Split(fields, ",") {
+			if !volumeFields[field] {
+				return volumeListing{}, ErrListVolumesInvalidFields
+			}
+
+			listing.fields = append(listing.fields, field)
+		}
+	}
+
+	query.Del(baggageclaim.SortQueryKey)
+	query.Del(baggageclaim.LimitQueryKey)
+	query.Del(baggageclaim.ContinueQueryKey)
+	query.Del(baggageclaim.FieldsQueryKey)
+
+	// anything else with the prefix may mean something to a later version,
+	// so it is refused rather than taken for a property
+	for key := range query {
+		if strings.HasPrefix(key, baggageclaim.ReservedQueryPrefix) {
+			return volumeListing{}, ErrListVolumesReservedQuery
+		}
+	}
+
+	return listing, nil
+}
+
+// nextPage returns the link to the page of the listing after cursor.
+func nextPage(req *http.Request, query volume.VolumeQuery, cursor volume.VolumeCursor) string {
+	next := req.URL.Query()
+	next.Set(baggageclaim.ContinueQueryKey, encodeContinueToken(query, cursor))
+
+	return fmt.Sprintf(`<%s?%s>; rel="next"`, req.URL.Path, next.Encode())
+}
+
+// selects reports whether the listing includes the field.
+func (listing volumeListing) selects(field string) bool {
+	if len(listing.fields) == 0 {
+		return true
+	}
+
+	for _, selected := range listing.fields {
+		if selected == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trim leaves only the selected fields of the volume.
+func (listing volumeListing) trim(vol volume.Volume) (interface{}, error) {
+	if len(listing.fields) == 0 {
+		return vol, nil
+	}
+
+	payload, err := json.Marshal(vol)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(payload, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := make(map[string]json.RawMessage, len(listing.fields))
+	for _, field := range listing.fields {
+		if value, found := fields[field]; found {
+			trimmed[field] = value
+		}
+	}
+
+	return trimmed, nil
+}
+
+// continueToken is where a listing left off. It is handed to clients
+// base64-encoded, and is opaque to them.
+type continueToken struct {
+	SortBy     volume.VolumeSortKey `json:"sort"`
+	Descending bool                 `json:"descending,omitempty"`
+
+	Handle    string    `json:"handle"`
+	CreatedAt time.Time `json:"created_at"`
+	Size      uint64    `json:"size,omitempty"`
+}
+
+func encodeContinueToken(query volume.VolumeQuery, cursor volume.VolumeCursor) string {
+	payload, _ := json.Marshal(continueToken{
+		SortBy:     query.SortBy,
+		Descending: query.Descending,
+
+		Handle:    cursor.Handle,
+		CreatedAt: cursor.CreatedAt,
+		Size:      cursor.Size,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// decodeContinueToken only accepts tokens from listings sorted the same way
+// as the query, as the position would be meaningless in any other order.
+func decodeContinueToken(token string, query volume.VolumeQuery) (volume.VolumeCursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return volume.VolumeCursor{}, ErrListVolumesInvalidContinue
+	}
+
+	var decoded continueToken
+	err = json.Unmarshal(payload, &decoded)
+	if err != nil {
+		return volume.VolumeCursor{}, ErrListVolumesInvalidContinue
+	}
+
+	if decoded.SortBy != query.SortBy || decoded.Descending != query.Descending {
+		return volume.VolumeCursor{}, ErrListVolumesInvalidContinue
+	}
+
+	return volume.VolumeCursor{
+		Handle:    decoded.Handle,
+		CreatedAt: decoded.CreatedAt,
+		Size:      decoded.Size,
+	}, nil
+}
+
+// acceptsNDJSON reports whether the client asked for the listing to be
+// streamed as newline-delimited JSON.
+func acceptsNDJSON(req *http.Request) bool {
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.Split(accept, ";")[0])
+		if mediaType == "application/x-ndjson" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,14 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagerctx"
 	"github.com/concourse/baggageclaim"
 	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/oci"
 	uuid "github.com/nu7hatch/gouuid"
 	"github.com/tedsuo/rata"
 )
@@ -21,20 +27,66 @@ const httpUnprocessableEntity = 422
 var ErrListVolumesFailed = errors.New("failed to list volumes")
 var ErrGetVolumeFailed = errors.New("failed to get volume")
 var ErrCreateVolumeFailed = errors.New("failed to create volume")
+var ErrCreateVolumeDigestNotFound = errors.New("no volume found with digest")
 var ErrDestroyVolumeFailed = errors.New("failed to destroy volume")
+var ErrRenameVolumeFailed = errors.New("failed to rename volume")
+var ErrVolumeAlreadyExists = errors.New("volume already exists")
+var ErrReservedVolumeHandle = errors.New("volume handle is reserved")
 var ErrSetPropertyFailed = errors.New("failed to set property on volume")
 var ErrGetPrivilegedFailed = errors.New("failed to get privileged status of volume")
 var ErrSetPrivilegedFailed = errors.New("failed to change privileged status of volume")
+var ErrSetTTLFailed = errors.New("failed to set ttl on volume")
+var ErrSetReadOnlyFailed = errors.New("failed to make volume read-only")
+var ErrVolumeIsReadOnly = errors.New("volume is read-only")
+var ErrGetUsageFailed = errors.New("failed to get usage of volume")
+var ErrGetDigestFailed = errors.New("failed to get digest of volume")
+var ErrGetOrphansFailed = errors.New("failed to get orphaned volumes")
+var ErrFsckFailed = errors.New("failed to check volumes")
+var ErrListQuarantinedVolumesFailed = errors.New("failed to list quarantined volumes")
+var ErrDestroyQuarantinedVolumeFailed = errors.New("failed to destroy quarantined volume")
 var ErrStreamInFailed = errors.New("failed to stream in to volume")
+var ErrStreamInQuotaExceeded = errors.New("volume quota exceeded")
+var ErrStreamInOffsetMismatch = errors.New("stream does not resume from the upload offset")
+var ErrStreamInUploadConflict = errors.New("upload session belongs to a different stream")
+var ErrStreamInUploadTooLarge = errors.New("upload exceeds the maximum upload size")
+var ErrGetStreamInOffsetFailed = errors.New("failed to get upload offset")
 var ErrStreamOutFailed = errors.New("failed to stream out from volume")
 var ErrStreamOutNotFound = errors.New("no such file or directory")
+var ErrStreamOutUnknownFormat = errors.New("unknown stream-out format")
+var ErrStreamOutOCIPath = errors.New("an oci image holds the whole volume")
 var ErrStreamP2pOutFailed = errors.New("failed to stream p2p out from volume")
+var ErrGetFileFailed = errors.New("failed to get file from volume")
+var ErrGetFileNotRegular = errors.New("not a regular file")
+var ErrGetFileOutsideVolume = errors.New("path leads outside of the volume")
+var ErrGetTreeFailed = errors.New("failed to list volume contents")
+var ErrGetTreeInvalidDepth = errors.New("depth must be a non-negative integer")
+var ErrGetTreeOutsideVolume = errors.New("path leads outside of the volume")
+var ErrGetTreeTooLarge = errors.New("too many entries to describe; ask for a shallower tree")
+var ErrGetDiffFailed = errors.New("failed to diff volume")
+var ErrVolumeHasNoParent = errors.New("volume has no parent")
+var ErrCreateSnapshotFailed = errors.New("failed to create snapshot of volume")
+var ErrRestoreSnapshotFailed = errors.New("failed to restore volume from snapshot")
+var ErrDestroySnapshotFailed = errors.New("failed to destroy snapshot of volume")
+var ErrSnapshotAlreadyExists = errors.New("snapshot already exists")
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+var ErrVolumeHasChildren = errors.New("volume has children")
+var ErrVolumeInUse = errors.New("volume is in use")
+var ErrSnapshotCorrupted = errors.New("snapshot has changed since it was taken")
 
 type VolumeServer struct {
 	strategerizer  volume.Strategerizer
 	volumeRepo     volume.Repository
 	volumePromises volume.PromiseList
 
+	// the properties that async creations were requested with, kept for as
+	// long as their promises so that they can be authorized before their
+	// volumes exist
+	asyncProperties  map[string]volume.Properties
+	asyncPropertiesL sync.Mutex
+
+	// verifies the stream-in urls handed to peers, if configured
+	streamInSigner *StreamInSigner
+
 	logger lager.Logger
 }
 
@@ -42,12 +94,16 @@ func NewVolumeServer(
 	logger lager.Logger,
 	strategerizer volume.Strategerizer,
 	volumeRepo volume.Repository,
+	streamInSigner *StreamInSigner,
 ) *VolumeServer {
 	return &VolumeServer{
 		strategerizer:  strategerizer,
 		volumeRepo:     volumeRepo,
+		streamInSigner: streamInSigner,
 		volumePromises: volume.NewPromiseList(),
 		logger:         logger,
+
+		asyncProperties: map[string]volume.Properties{},
 	}
 }
 
@@ -104,6 +160,10 @@ func (vs *VolumeServer) CreateVolumeAsync(w http.ResponseWriter, req *http.Reque
 		return
 	}
 
+	vs.asyncPropertiesL.Lock()
+	vs.asyncProperties[handle] = volume.Properties(request.Properties)
+	vs.asyncPropertiesL.Unlock()
+
 	go vs.doCreate(ctx, w, request, handle, strategy, hLog, handlers)
 
 	w.Header().Set("Content-Type", "application/json")
@@ -147,6 +207,10 @@ func (vs *VolumeServer) CreateVolumeAsyncCancel(w http.ResponseWriter, req *http
 
 	vs.volumePromises.RemovePromise(handle)
 
+	vs.asyncPropertiesL.Lock()
+	delete(vs.asyncProperties, handle)
+	vs.asyncPropertiesL.Unlock()
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -197,6 +261,16 @@ func (vs *VolumeServer) CreateVolumeAsyncCheck(w http.ResponseWriter, req *http.
 	}
 }
 
+// asyncCreation returns the properties that the async creation of the volume
+// was requested with, if it is known.
+func (vs *VolumeServer) asyncCreation(handle string) (volume.Properties, bool) {
+	vs.asyncPropertiesL.Lock()
+	defer vs.asyncPropertiesL.Unlock()
+
+	properties, found := vs.asyncProperties[handle]
+	return properties, found
+}
+
 func (vs *VolumeServer) DestroyVolume(w http.ResponseWriter, req *http.Request) {
 	handle := rata.Param(req, "handle")
 
@@ -280,6 +354,60 @@ func (vs *VolumeServer) DestroyVolumes(w http.ResponseWriter, req *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RenameVolume moves a volume to the handle given in the request, responding
+// with the volume as it is under its new handle.
+func (vs *VolumeServer) RenameVolume(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	handle := rata.Param(req, "handle")
+
+	hLog := vs.logger.Session("rename-volume", lager.Data{
+		"volume": handle,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	var request baggageclaim.RenameRequest
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		RespondWithError(w, ErrRenameVolumeFailed, http.StatusBadRequest)
+		return
+	}
+
+	if reservedHandle(request.Handle) {
+		hLog.Info("reserved-handle")
+		RespondWithError(w, ErrReservedVolumeHandle, http.StatusBadRequest)
+		return
+	}
+
+	renamed, err := vs.volumeRepo.RenameVolume(ctx, handle, request.Handle)
+	if err != nil {
+		switch err {
+		case volume.ErrVolumeDoesNotExist:
+			RespondWithError(w, ErrRenameVolumeFailed, http.StatusNotFound)
+		case volume.ErrVolumeAlreadyExists:
+			RespondWithError(w, ErrVolumeAlreadyExists, http.StatusConflict)
+		case volume.ErrInvalidVolumeHandle:
+			RespondWithError(w, ErrRenameVolumeFailed, http.StatusBadRequest)
+		default:
+			hLog.Error("failed-to-rename-volume", err)
+			RespondWithError(w, ErrRenameVolumeFailed, http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(renamed); err != nil {
+		hLog.Error("failed-to-encode", err)
+	}
+}
+
+// ListVolumes responds with a JSON array of volumes, or streams them as
+// NDJSON if asked to. Listings that stop at their limit link to the next
+// page; see baggageclaim.ReservedQueryPrefix.
 func (vs *VolumeServer) ListVolumes(w http.ResponseWriter, req *http.Request) {
 	hLog := vs.logger.Session("list-volumes")
 
@@ -290,20 +418,124 @@ func (vs *VolumeServer) ListVolumes(w http.ResponseWriter, req *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	properties, err := ConvertQueryToProperties(req.URL.Query())
+	query := req.URL.Query()
+
+	includeUsage := query.Get(baggageclaim.IncludeUsageQueryKey) == "true"
+	query.Del(baggageclaim.IncludeUsageQueryKey)
+
+	listing, err := parseVolumeListing(query)
+	if err != nil {
+		RespondWithError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	if len(listing.fields) > 0 {
+		includeUsage = listing.selects("usage")
+	}
+
+	properties, err := ConvertQueryToProperties(query)
 	if err != nil {
 		RespondWithError(w, err, httpUnprocessableEntity)
 		return
 	}
 
-	volumes, _, err := vs.volumeRepo.ListVolumes(ctx, properties)
+	listing.query.Properties = properties
+
+	// visit one volume past the limit to find out whether there is another
+	// page to continue to
+	limit := listing.query.Limit
+	if limit > 0 {
+		listing.query.Limit++
+	}
+
+	stream := acceptsNDJSON(req)
+	streaming := false
+
+	flusher, canFlush := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+
+	// the headers are only switched over once there is a volume to stream, so
+	// that failing before then can still respond with an error
+	startStreaming := func() {
+		if !streaming {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Trailer", "Link")
+			streaming = true
+		}
+	}
+
+	volumes := []interface{}{}
+	visited := 0
+	var last volume.Volume
+	var hasMore bool
+
+	err = vs.volumeRepo.VisitVolumes(ctx, listing.query, func(vol volume.Volume) error {
+		if limit > 0 && visited == limit {
+			hasMore = true
+			return nil
+		}
+
+		if includeUsage && vol.Usage == nil {
+			usage, err := vs.volumeRepo.GetUsage(ctx, vol.Handle)
+			if err != nil {
+				// the volume may have been destroyed in the meantime; leave the
+				// usage out rather than failing the whole listing
+				hLog.Info("failed-to-get-usage", lager.Data{
+					"volume": vol.Handle,
+					"error":  err.Error(),
+				})
+			} else {
+				vol.Usage = &usage
+			}
+		}
+
+		visited++
+		last = vol
+
+		trimmed, err := listing.trim(vol)
+		if err != nil {
+			return err
+		}
+
+		if !stream {
+			volumes = append(volumes, trimmed)
+			return nil
+		}
+
+		startStreaming()
+
+		err = encoder.Encode(trimmed)
+		if err != nil {
+			return err
+		}
+
+		if canFlush {
+			flusher.Flush()
+		}
+
+		return nil
+	})
 	if err != nil {
 		hLog.Error("failed-to-list-volumes", err)
-		RespondWithError(w, ErrListVolumesFailed, http.StatusInternalServerError)
+
+		// once streaming has begun, all that can be done is to stop
+		if !streaming {
+			RespondWithError(w, ErrListVolumesFailed, http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	if hasMore {
+		w.Header().Set("Link", nextPage(req, listing.query, last.Cursor()))
+	}
+
+	if stream {
+		startStreaming()
 		return
 	}
 
-	if err := json.NewEncoder(w).Encode(volumes); err != nil {
+	if err := encoder.Encode(volumes); err != nil {
 		hLog.Error("failed-to-encode", err)
 	}
 }
@@ -442,6 +674,8 @@ func (vs *VolumeServer) SetPrivileged(w http.ResponseWriter, req *http.Request)
 
 		if err == volume.ErrVolumeDoesNotExist {
 			RespondWithError(w, ErrSetPrivilegedFailed, http.StatusNotFound)
+		} else if err == volume.ErrVolumeIsReadOnly {
+			RespondWithError(w, ErrVolumeIsReadOnly, http.StatusConflict)
 		} else {
 			RespondWithError(w, ErrSetPrivilegedFailed, http.StatusInternalServerError)
 		}
@@ -452,10 +686,12 @@ func (vs *VolumeServer) SetPrivileged(w http.ResponseWriter, req *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (vs *VolumeServer) StreamIn(w http.ResponseWriter, req *http.Request) {
+// SetReadOnly seals the volume's contents. There is no way to unseal them, so
+// that a populated cache cannot be tampered with later.
+func (vs *VolumeServer) SetReadOnly(w http.ResponseWriter, req *http.Request) {
 	handle := rata.Param(req, "handle")
 
-	hLog := vs.logger.Session("stream-in", lager.Data{
+	hLog := vs.logger.Session("set-read-only", lager.Data{
 		"volume": handle,
 	})
 
@@ -464,43 +700,26 @@ func (vs *VolumeServer) StreamIn(w http.ResponseWriter, req *http.Request) {
 
 	ctx := lagerctx.NewContext(req.Context(), hLog)
 
-	var subPath string
-	if queryPath, ok := req.URL.Query()["path"]; ok {
-		subPath = queryPath[0]
-	}
-
-	badStream, err := vs.volumeRepo.StreamIn(ctx, handle, subPath, req.Header.Get("Content-Encoding"), req.Body)
+	err := vs.volumeRepo.SetReadOnly(ctx, handle)
 	if err != nil {
-		if err == volume.ErrVolumeDoesNotExist {
-			hLog.Info("volume-not-found")
-			RespondWithError(w, ErrStreamInFailed, http.StatusNotFound)
-			return
-		}
-
-		if err == volume.ErrUnsupportedStreamEncoding {
-			hLog.Info("unsupported-stream-encoding")
-			RespondWithError(w, ErrStreamInFailed, http.StatusBadRequest)
-			return
-		}
+		hLog.Error("failed-to-set-read-only", err)
 
-		if badStream {
-			hLog.Info("bad-stream-payload", lager.Data{"error": err.Error()})
-			RespondWithError(w, ErrStreamInFailed, http.StatusBadRequest)
-			return
+		if err == volume.ErrVolumeDoesNotExist {
+			RespondWithError(w, ErrSetReadOnlyFailed, http.StatusNotFound)
+		} else {
+			RespondWithError(w, ErrSetReadOnlyFailed, http.StatusInternalServerError)
 		}
 
-		hLog.Error("failed-to-stream-into-volume", err)
-		RespondWithError(w, ErrStreamInFailed, http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (vs *VolumeServer) StreamOut(w http.ResponseWriter, req *http.Request) {
+func (vs *VolumeServer) SetTTL(w http.ResponseWriter, req *http.Request) {
 	handle := rata.Param(req, "handle")
 
-	hLog := vs.logger.Session("stream-out", lager.Data{
+	hLog := vs.logger.Session("set-ttl", lager.Data{
 		"volume": handle,
 	})
 
@@ -509,41 +728,39 @@ func (vs *VolumeServer) StreamOut(w http.ResponseWriter, req *http.Request) {
 
 	ctx := lagerctx.NewContext(req.Context(), hLog)
 
-	var subPath string
-	if queryPath, ok := req.URL.Query()["path"]; ok {
-		subPath = queryPath[0]
+	var request baggageclaim.TTLRequest
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		RespondWithError(w, ErrSetTTLFailed, http.StatusBadRequest)
+		return
 	}
 
-	err := vs.volumeRepo.StreamOut(ctx, handle, subPath, req.Header.Get("Accept-Encoding"), w)
-	if err != nil {
-		if err == volume.ErrVolumeDoesNotExist {
-			hLog.Info("volume-not-found")
-			RespondWithError(w, ErrStreamOutFailed, http.StatusNotFound)
-			return
-		}
+	ttl := time.Duration(request.Value) * time.Second
 
-		if err == volume.ErrUnsupportedStreamEncoding {
-			hLog.Info("unsupported-stream-encoding")
-			RespondWithError(w, ErrStreamOutFailed, http.StatusBadRequest)
-			return
-		}
+	hLog.Debug("setting-ttl", lager.Data{"ttl": request.Value})
 
-		if os.IsNotExist(err) {
-			hLog.Info("source-path-not-found")
-			RespondWithError(w, ErrStreamOutNotFound, http.StatusNotFound)
-			return
+	err = vs.volumeRepo.SetTTL(ctx, handle, ttl)
+	if err != nil {
+		hLog.Error("failed-to-set-ttl", err)
+
+		if err == volume.ErrVolumeDoesNotExist {
+			RespondWithError(w, ErrSetTTLFailed, http.StatusNotFound)
+		} else {
+			RespondWithError(w, ErrSetTTLFailed, http.StatusInternalServerError)
 		}
 
-		hLog.Error("failed-to-stream-out", err)
-		RespondWithError(w, ErrStreamOutFailed, http.StatusInternalServerError)
 		return
 	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func (vs *VolumeServer) StreamP2pOut(w http.ResponseWriter, req *http.Request) {
+func (vs *VolumeServer) GetUsage(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
 	handle := rata.Param(req, "handle")
 
-	hLog := vs.logger.Session("stream-p2p-out", lager.Data{
+	hLog := vs.logger.Session("get-usage", lager.Data{
 		"volume": handle,
 	})
 
@@ -552,123 +769,667 @@ func (vs *VolumeServer) StreamP2pOut(w http.ResponseWriter, req *http.Request) {
 
 	ctx := lagerctx.NewContext(req.Context(), hLog)
 
-	var subPath, streamInURL, encoding string
-	if queryPath, ok := req.URL.Query()["path"]; ok {
-		subPath = queryPath[0]
-	}
-	if subPath == "" {
-		hLog.Info("missing-param-path")
-		RespondWithError(w, ErrStreamP2pOutFailed, http.StatusBadRequest)
-		return
-	}
-	if queryPath, ok := req.URL.Query()["streamInURL"]; ok {
-		streamInURL = queryPath[0]
-	}
-	if streamInURL == "" {
-		hLog.Info("missing-param-streamInURL")
-		RespondWithError(w, ErrStreamP2pOutFailed, http.StatusBadRequest)
-		return
-	}
-	if queryPath, ok := req.URL.Query()["encoding"]; ok {
-		encoding = queryPath[0]
-	}
-	if encoding == "" {
-		hLog.Info("missing-param-encoding")
-		RespondWithError(w, ErrStreamP2pOutFailed, http.StatusBadRequest)
-		return
-	}
-
-	err := vs.volumeRepo.StreamP2pOut(ctx, handle, subPath, encoding, streamInURL)
+	usage, err := vs.volumeRepo.GetUsage(ctx, handle)
 	if err != nil {
-		if err == volume.ErrVolumeDoesNotExist {
-			hLog.Info("volume-not-found")
-			RespondWithError(w, ErrStreamOutNotFound, http.StatusNotFound)
-			return
-		}
+		hLog.Error("failed-to-get-usage", err)
 
-		if err == volume.ErrUnsupportedStreamEncoding {
-			hLog.Info("unsupported-stream-encoding")
-			RespondWithError(w, ErrStreamP2pOutFailed, http.StatusBadRequest)
-			return
-		}
-
-		if os.IsNotExist(err) {
-			hLog.Info("source-path-not-found")
-			RespondWithError(w, ErrStreamOutNotFound, http.StatusNotFound)
-			return
+		if err == volume.ErrVolumeDoesNotExist {
+			RespondWithError(w, ErrGetUsageFailed, http.StatusNotFound)
+		} else {
+			RespondWithError(w, ErrGetUsageFailed, http.StatusInternalServerError)
 		}
 
-		hLog.Error("failed-to-stream-out", err)
-		RespondWithError(w, ErrStreamP2pOutFailed, http.StatusInternalServerError)
 		return
 	}
-}
 
-func (vs *VolumeServer) generateHandle() (string, error) {
-	handle, err := uuid.NewV4()
-	if err != nil {
-		return "", err
+	if err := json.NewEncoder(w).Encode(usage); err != nil {
+		hLog.Error("failed-to-encode", err)
 	}
-
-	return handle.String(), nil
 }
 
-func (vs *VolumeServer) prepareCreate(w http.ResponseWriter, req *http.Request, hLog lager.Logger) (baggageclaim.VolumeRequest, string, volume.Strategy, lager.Logger, error) {
-	var request baggageclaim.VolumeRequest
-	err := json.NewDecoder(req.Body).Decode(&request)
-	if err != nil {
-		hLog.Error("failed-to-decode-request", err)
-		RespondWithError(w, ErrCreateVolumeFailed, http.StatusBadRequest)
-		return baggageclaim.VolumeRequest{}, "", nil, hLog, err
-	}
+func (vs *VolumeServer) GetDigest(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
-	handle := request.Handle
-	if handle == "" {
-		handle, err = vs.generateHandle()
-		if err != nil {
-			hLog.Error("failed-to-generate-handle", err)
-			RespondWithError(w, ErrCreateVolumeFailed, http.StatusBadRequest)
-			return baggageclaim.VolumeRequest{}, "", nil, hLog, err
-		}
-	}
+	handle := rata.Param(req, "handle")
 
-	hLog = hLog.WithData(lager.Data{
-		"handle":     handle,
-		"privileged": request.Privileged,
-		"strategy":   request.Strategy,
+	hLog := vs.logger.Session("get-digest", lager.Data{
+		"volume": handle,
 	})
 
-	strategy, err := vs.strategerizer.StrategyFor(request)
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	digest, err := vs.volumeRepo.GetDigest(ctx, handle)
 	if err != nil {
-		hLog.Error("could-not-produce-strategy", err)
-		RespondWithError(w, ErrCreateVolumeFailed, httpUnprocessableEntity)
-		return baggageclaim.VolumeRequest{}, "", nil, hLog, err
+		hLog.Error("failed-to-get-digest", err)
+
+		if err == volume.ErrVolumeDoesNotExist {
+			RespondWithError(w, ErrGetDigestFailed, http.StatusNotFound)
+		} else {
+			RespondWithError(w, ErrGetDigestFailed, http.StatusInternalServerError)
+		}
+
+		return
 	}
 
-	return request, handle, strategy, hLog, nil
+	if err := json.NewEncoder(w).Encode(baggageclaim.DigestResponse{Digest: digest}); err != nil {
+		hLog.Error("failed-to-encode", err)
+	}
 }
 
-func (vs *VolumeServer) doCreate(ctx context.Context, w http.ResponseWriter, request baggageclaim.VolumeRequest, handle string, strategy volume.Strategy, hLog lager.Logger, handlers volumeCreationHandler) (volume.Volume, error) {
-	hLog.Debug("creating")
+func (vs *VolumeServer) GetOrphans(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	hLog := vs.logger.Session("get-orphans")
 
-	createdVolume, err := vs.volumeRepo.CreateVolume(
-		ctx,
-		handle,
-		strategy,
-		volume.Properties(request.Properties),
-		request.Privileged,
-	)
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
 
+	stats, err := vs.volumeRepo.GetOrphanStats(ctx)
 	if err != nil {
-		hLog.Error("failed-to-create", err)
-		return handlers.creationFailed(w, err)
+		hLog.Error("failed-to-get-orphan-stats", err)
+		RespondWithError(w, ErrGetOrphansFailed, http.StatusInternalServerError)
+		return
 	}
 
-	hLog = hLog.WithData(lager.Data{
-		"volume": createdVolume.Handle,
-	})
-
-	hLog.Debug("created")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		hLog.Error("failed-to-encode", err)
+	}
+}
+
+func (vs *VolumeServer) ListCorruptedVolumes(w http.ResponseWriter, req *http.Request) {
+	hLog := vs.logger.Session("list-corrupted-volumes")
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_, corruptedHandles, err := vs.volumeRepo.ListVolumes(ctx, volume.Properties{})
+	if err != nil {
+		hLog.Error("failed-to-list-volumes", err)
+		RespondWithError(w, ErrListVolumesFailed, http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(corruptedHandles); err != nil {
+		hLog.Error("failed-to-encode", err)
+	}
+}
+
+func (vs *VolumeServer) ListQuarantinedVolumes(w http.ResponseWriter, req *http.Request) {
+	hLog := vs.logger.Session("list-quarantined-volumes")
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	handles, err := vs.volumeRepo.ListQuarantinedVolumes(ctx)
+	if err != nil {
+		hLog.Error("failed-to-list-quarantined-volumes", err)
+		RespondWithError(w, ErrListQuarantinedVolumesFailed, http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(handles); err != nil {
+		hLog.Error("failed-to-encode", err)
+	}
+}
+
+func (vs *VolumeServer) DestroyQuarantinedVolume(w http.ResponseWriter, req *http.Request) {
+	handle := rata.Param(req, "handle")
+
+	hLog := vs.logger.Session("destroy-quarantined-volume", lager.Data{
+		"volume": handle,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	err := vs.volumeRepo.DestroyQuarantinedVolume(ctx, handle)
+	if err != nil {
+		if err == volume.ErrVolumeDoesNotExist {
+			hLog.Info("volume-does-not-exist")
+			RespondWithError(w, ErrDestroyQuarantinedVolumeFailed, http.StatusNotFound)
+		} else {
+			hLog.Error("failed-to-destroy", err)
+			RespondWithError(w, ErrDestroyQuarantinedVolumeFailed, http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	hLog.Info("destroyed")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (vs *VolumeServer) Fsck(w http.ResponseWriter, req *http.Request) {
+	hLog := vs.logger.Session("fsck")
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// an empty body only checks the volumes
+	var request baggageclaim.FsckRequest
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil && err != io.EOF {
+		RespondWithError(w, ErrFsckFailed, http.StatusBadRequest)
+		return
+	}
+
+	report, err := vs.volumeRepo.Fsck(ctx, volume.FsckOptions{
+		Repair:     request.Repair,
+		Quarantine: request.Quarantine,
+	})
+	if err != nil {
+		hLog.Error("failed-to-fsck", err)
+		RespondWithError(w, ErrFsckFailed, http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		hLog.Error("failed-to-encode", err)
+	}
+}
+
+func (vs *VolumeServer) StreamIn(w http.ResponseWriter, req *http.Request) {
+	handle := rata.Param(req, "handle")
+
+	hLog := vs.logger.Session("stream-in", lager.Data{
+		"volume": handle,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	var subPath string
+	if queryPath, ok := req.URL.Query()["path"]; ok {
+		subPath = queryPath[0]
+	}
+
+	if vs.streamInSigner != nil && isSignedStreamIn(req.URL.Query()) {
+		err := vs.streamInSigner.Verify(handle, subPath, req.URL.Query())
+		if err != nil {
+			hLog.Info("rejected-stream-in-url", lager.Data{"reason": err.Error()})
+			RespondWithError(w, err, http.StatusForbidden)
+			return
+		}
+	} else if vs.streamInSigner != nil && !authenticated(req) {
+		// once urls are signed, only token holders may stream in without one
+		hLog.Info("unsigned-stream-in")
+		RespondWithError(w, ErrUnsignedStreamIn, http.StatusForbidden)
+		return
+	}
+
+	encoding := contentEncoding(req.Header.Get("Content-Encoding"))
+
+	var badStream bool
+	var err error
+
+	if session, ok := req.URL.Query()["session"]; ok {
+		offset, ok := contentRangeOffset(req.Header.Get("Content-Range"))
+		if !ok {
+			hLog.Info("invalid-content-range")
+			RespondWithError(w, ErrStreamInFailed, http.StatusBadRequest)
+			return
+		}
+
+		badStream, err = vs.volumeRepo.StreamInResumable(ctx, handle, subPath, encoding, session[0], offset, req.Body)
+	} else {
+		badStream, err = vs.volumeRepo.StreamIn(ctx, handle, subPath, encoding, req.Body)
+	}
+
+	if err != nil {
+		if err == volume.ErrVolumeDoesNotExist {
+			hLog.Info("volume-not-found")
+			RespondWithError(w, ErrStreamInFailed, http.StatusNotFound)
+			return
+		}
+
+		if err == volume.ErrUnsupportedStreamEncoding {
+			hLog.Info("unsupported-stream-encoding")
+			RespondWithError(w, ErrStreamInFailed, http.StatusBadRequest)
+			return
+		}
+
+		if err == volume.ErrVolumeIsReadOnly {
+			hLog.Info("volume-is-read-only")
+			RespondWithError(w, ErrVolumeIsReadOnly, http.StatusConflict)
+			return
+		}
+
+		if err == volume.ErrQuotaExceeded {
+			hLog.Info("quota-exceeded")
+			RespondWithError(w, ErrStreamInQuotaExceeded, http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if err == volume.ErrUploadTooLarge {
+			hLog.Info("upload-too-large")
+			RespondWithError(w, ErrStreamInUploadTooLarge, http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if err == volume.ErrInvalidUploadSession {
+			hLog.Info("invalid-upload-session")
+			RespondWithError(w, ErrStreamInFailed, http.StatusBadRequest)
+			return
+		}
+
+		if err == volume.ErrUploadConflict {
+			hLog.Info("upload-conflict")
+			RespondWithError(w, ErrStreamInUploadConflict, http.StatusConflict)
+			return
+		}
+
+		if err == volume.ErrUploadIncomplete {
+			hLog.Info("upload-interrupted")
+			RespondWithError(w, ErrStreamInFailed, http.StatusBadRequest)
+			return
+		}
+
+		var offsetErr volume.UploadOffsetError
+		if errors.As(err, &offsetErr) {
+			hLog.Info("upload-offset-mismatch", lager.Data{"offset": offsetErr.Offset})
+			w.Header().Set(baggageclaim.UploadOffsetHeader, strconv.FormatInt(offsetErr.Offset, 10))
+			RespondWithError(w, ErrStreamInOffsetMismatch, http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+
+		if badStream {
+			hLog.Info("bad-stream-payload", lager.Data{"error": err.Error()})
+			RespondWithError(w, ErrStreamInFailed, http.StatusBadRequest)
+			return
+		}
+
+		hLog.Error("failed-to-stream-into-volume", err)
+		RespondWithError(w, ErrStreamInFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (vs *VolumeServer) StreamInOffset(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	handle := rata.Param(req, "handle")
+	session := req.URL.Query().Get("session")
+
+	hLog := vs.logger.Session("stream-in-offset", lager.Data{
+		"volume":  handle,
+		"session": session,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	offset, err := vs.volumeRepo.UploadOffset(ctx, handle, session)
+	if err != nil {
+		if err == volume.ErrVolumeDoesNotExist {
+			hLog.Info("volume-not-found")
+			RespondWithError(w, ErrGetStreamInOffsetFailed, http.StatusNotFound)
+			return
+		}
+
+		if err == volume.ErrUploadTooLarge {
+			hLog.Info("upload-too-large")
+			RespondWithError(w, ErrStreamInUploadTooLarge, http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if err == volume.ErrInvalidUploadSession {
+			hLog.Info("invalid-upload-session")
+			RespondWithError(w, ErrGetStreamInOffsetFailed, http.StatusBadRequest)
+			return
+		}
+
+		hLog.Error("failed-to-get-upload-offset", err)
+		RespondWithError(w, ErrGetStreamInOffsetFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(baggageclaim.UploadOffsetHeader, strconv.FormatInt(offset, 10))
+
+	if err := json.NewEncoder(w).Encode(baggageclaim.StreamInOffsetResponse{Offset: offset}); err != nil {
+		hLog.Error("failed-to-encode", err)
+	}
+}
+
+// contentRangeOffset returns where in the stream a resumed stream-in starts
+// from its Content-Range header, e.g. "bytes 1024-*/*". The end and the
+// total length are not known up front, so they are ignored. No header at all
+// means the stream starts from the beginning.
+func contentRangeOffset(header string) (int64, bool) {
+	if header == "" {
+		return 0, true
+	}
+
+	if !strings.HasPrefix(header, "bytes ") {
+		return 0, false
+	}
+
+	rangeSpec := strings.TrimPrefix(header, "bytes ")
+
+	dash := strings.Index(rangeSpec, "-")
+	if dash == -1 {
+		return 0, false
+	}
+
+	offset, err := strconv.ParseInt(rangeSpec[:dash], 10, 64)
+	if err != nil || offset < 0 {
+		return 0, false
+	}
+
+	return offset, true
+}
+
+func (vs *VolumeServer) StreamOut(w http.ResponseWriter, req *http.Request) {
+	handle := rata.Param(req, "handle")
+
+	hLog := vs.logger.Session("stream-out", lager.Data{
+		"volume": handle,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	var subPath string
+	if queryPath, ok := req.URL.Query()["path"]; ok {
+		subPath = queryPath[0]
+	}
+
+	encoding, acceptable := NegotiateEncoding(req.Header.Get("Accept-Encoding"))
+	if !acceptable {
+		hLog.Info("unsupported-stream-encoding", lager.Data{
+			"accept-encoding": req.Header.Get("Accept-Encoding"),
+		})
+
+		RespondWithError(w, ErrStreamOutFailed, http.StatusBadRequest)
+		return
+	}
+
+	if encoding != volume.IdentityEncoding {
+		w.Header().Set("Content-Encoding", encoding)
+	}
+
+	var err error
+	switch req.URL.Query().Get("format") {
+	case "", "tar":
+		if req.URL.Query().Get("diff") == "true" {
+			err = vs.volumeRepo.StreamOutDiff(ctx, handle, subPath, encoding, w)
+		} else {
+			err = vs.volumeRepo.StreamOut(ctx, handle, subPath, encoding, w)
+		}
+	case "oci":
+		if subPath != "" && subPath != "." {
+			hLog.Info("oci-stream-out-of-sub-path")
+			w.Header().Del("Content-Encoding")
+			RespondWithError(w, ErrStreamOutOCIPath, http.StatusBadRequest)
+			return
+		}
+
+		chain := req.URL.Query().Get("chain") == "true"
+		err = vs.volumeRepo.StreamOutOCI(ctx, handle, chain, encoding, w)
+	default:
+		hLog.Info("unknown-stream-out-format", lager.Data{
+			"format": req.URL.Query().Get("format"),
+		})
+
+		w.Header().Del("Content-Encoding")
+		RespondWithError(w, ErrStreamOutUnknownFormat, http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		// the error response is not encoded
+		w.Header().Del("Content-Encoding")
+
+		if err == volume.ErrVolumeDoesNotExist {
+			hLog.Info("volume-not-found")
+			RespondWithError(w, ErrStreamOutFailed, http.StatusNotFound)
+			return
+		}
+
+		if err == volume.ErrVolumeHasNoParent {
+			hLog.Info("volume-has-no-parent")
+			RespondWithError(w, ErrVolumeHasNoParent, http.StatusBadRequest)
+			return
+		}
+
+		if err == volume.ErrUnsupportedStreamEncoding {
+			hLog.Info("unsupported-stream-encoding")
+			RespondWithError(w, ErrStreamOutFailed, http.StatusBadRequest)
+			return
+		}
+
+		if os.IsNotExist(err) {
+			hLog.Info("source-path-not-found")
+			RespondWithError(w, ErrStreamOutNotFound, http.StatusNotFound)
+			return
+		}
+
+		hLog.Error("failed-to-stream-out", err)
+		RespondWithError(w, ErrStreamOutFailed, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (vs *VolumeServer) StreamP2pOut(w http.ResponseWriter, req *http.Request) {
+	handle := rata.Param(req, "handle")
+
+	hLog := vs.logger.Session("stream-p2p-out", lager.Data{
+		"volume": handle,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	var subPath, streamInURL, encoding string
+	if queryPath, ok := req.URL.Query()["path"]; ok {
+		subPath = queryPath[0]
+	}
+	if subPath == "" {
+		hLog.Info("missing-param-path")
+		RespondWithError(w, ErrStreamP2pOutFailed, http.StatusBadRequest)
+		return
+	}
+	if queryPath, ok := req.URL.Query()["streamInURL"]; ok {
+		streamInURL = queryPath[0]
+	}
+	if streamInURL == "" {
+		hLog.Info("missing-param-streamInURL")
+		RespondWithError(w, ErrStreamP2pOutFailed, http.StatusBadRequest)
+		return
+	}
+	if queryPath, ok := req.URL.Query()["encoding"]; ok {
+		encoding = queryPath[0]
+	}
+	if encoding == "" {
+		hLog.Info("missing-param-encoding")
+		RespondWithError(w, ErrStreamP2pOutFailed, http.StatusBadRequest)
+		return
+	}
+
+	err := vs.volumeRepo.StreamP2pOut(ctx, handle, subPath, encoding, streamInURL)
+	if err != nil {
+		if err == volume.ErrVolumeDoesNotExist {
+			hLog.Info("volume-not-found")
+			RespondWithError(w, ErrStreamOutNotFound, http.StatusNotFound)
+			return
+		}
+
+		if err == volume.ErrUnsupportedStreamEncoding {
+			hLog.Info("unsupported-stream-encoding")
+			RespondWithError(w, ErrStreamP2pOutFailed, http.StatusBadRequest)
+			return
+		}
+
+		if os.IsNotExist(err) {
+			hLog.Info("source-path-not-found")
+			RespondWithError(w, ErrStreamOutNotFound, http.StatusNotFound)
+			return
+		}
+
+		var peerErr volume.P2pStreamError
+		if errors.As(err, &peerErr) {
+			// pass the peer's explanation back to the caller
+			hLog.Info("peer-rejected-stream", lager.Data{"error": peerErr.Error()})
+			RespondWithError(w, peerErr, http.StatusBadGateway)
+			return
+		}
+
+		hLog.Error("failed-to-stream-out", err)
+		RespondWithError(w, ErrStreamP2pOutFailed, http.StatusInternalServerError)
+		return
+	}
+}
+
+// Events streams volume lifecycle events as newline-delimited JSON until the
+// client goes away. The response ends early if the client cannot keep up, in
+// which case it should reconnect and re-list volumes.
+func (vs *VolumeServer) Events(w http.ResponseWriter, req *http.Request) {
+	hLog := vs.logger.Session("events")
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	events := vs.volumeRepo.Subscribe(req.Context())
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, canFlush := w.(http.Flusher)
+	if canFlush {
+		flusher.Flush()
+	}
+
+	encoder := json.NewEncoder(w)
+	for event := range events {
+		err := encoder.Encode(event)
+		if err != nil {
+			hLog.Info("failed-to-write-event", lager.Data{"error": err.Error()})
+			return
+		}
+
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+}
+
+func (vs *VolumeServer) generateHandle() (string, error) {
+	handle, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+
+	return handle.String(), nil
+}
+
+// reservedHandle reports whether a handle is taken by a route beneath
+// /volumes, such as /volumes/corrupted, so that a volume given it could not be
+// reached.
+func reservedHandle(handle string) bool {
+	for _, route := range baggageclaim.Routes {
+		if route.Path == "/volumes/"+handle {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (vs *VolumeServer) prepareCreate(w http.ResponseWriter, req *http.Request, hLog lager.Logger) (baggageclaim.VolumeRequest, string, volume.Strategy, lager.Logger, error) {
+	var request baggageclaim.VolumeRequest
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		hLog.Error("failed-to-decode-request", err)
+		RespondWithError(w, ErrCreateVolumeFailed, http.StatusBadRequest)
+		return baggageclaim.VolumeRequest{}, "", nil, hLog, err
+	}
+
+	handle := request.Handle
+	if handle == "" {
+		handle, err = vs.generateHandle()
+		if err != nil {
+			hLog.Error("failed-to-generate-handle", err)
+			RespondWithError(w, ErrCreateVolumeFailed, http.StatusBadRequest)
+			return baggageclaim.VolumeRequest{}, "", nil, hLog, err
+		}
+	}
+
+	if reservedHandle(handle) {
+		hLog.Info("reserved-handle", lager.Data{"handle": handle})
+		RespondWithError(w, ErrReservedVolumeHandle, http.StatusBadRequest)
+		return baggageclaim.VolumeRequest{}, "", nil, hLog, ErrReservedVolumeHandle
+	}
+
+	hLog = hLog.WithData(lager.Data{
+		"handle":     handle,
+		"privileged": request.Privileged,
+		"strategy":   request.Strategy,
+		"quota":      request.QuotaBytes,
+		"ttl":        request.TTLInSeconds,
+		"read-only":  request.ReadOnly,
+	})
+
+	strategy, err := vs.strategerizer.StrategyFor(request)
+	if err != nil {
+		hLog.Error("could-not-produce-strategy", err)
+		RespondWithError(w, ErrCreateVolumeFailed, httpUnprocessableEntity)
+		return baggageclaim.VolumeRequest{}, "", nil, hLog, err
+	}
+
+	return request, handle, strategy, hLog, nil
+}
+
+func (vs *VolumeServer) doCreate(ctx context.Context, w http.ResponseWriter, request baggageclaim.VolumeRequest, handle string, strategy volume.Strategy, hLog lager.Logger, handlers volumeCreationHandler) (volume.Volume, error) {
+	hLog.Debug("creating")
+
+	createdVolume, err := vs.volumeRepo.CreateVolume(ctx, handle, volume.VolumeSpec{
+		Strategy:   strategy,
+		Properties: volume.Properties(request.Properties),
+		Privileged: request.Privileged,
+		QuotaBytes: request.QuotaBytes,
+		TTL:        time.Duration(request.TTLInSeconds) * time.Second,
+		ReadOnly:   request.ReadOnly,
+	})
+
+	if err != nil {
+		hLog.Error("failed-to-create", err)
+		return handlers.creationFailed(w, err)
+	}
+
+	hLog = hLog.WithData(lager.Data{
+		"volume": createdVolume.Handle,
+	})
+
+	hLog.Debug("created")
 
 	return handlers.creationSucceeded(ctx, createdVolume, hLog)
 }
@@ -685,6 +1446,22 @@ func (vs *VolumeServer) creationFailed(w http.ResponseWriter, err error) (volume
 		code = httpUnprocessableEntity
 	case volume.ErrNoParentVolumeProvided:
 		code = httpUnprocessableEntity
+	case volume.ErrQuotaNotSupported:
+		code = httpUnprocessableEntity
+	case volume.ErrNoDigestProvided:
+		code = httpUnprocessableEntity
+	case volume.ErrNoLayerProvided:
+		code = httpUnprocessableEntity
+	case volume.ErrNoImageLayoutProvided:
+		code = httpUnprocessableEntity
+	case oci.ErrImageNotFound:
+		code = httpUnprocessableEntity
+	case oci.ErrAmbiguousImage:
+		code = httpUnprocessableEntity
+	case volume.ErrDigestNotFound:
+		// let clients tell this apart so they can fall back to fetching
+		RespondWithError(w, ErrCreateVolumeDigestNotFound, httpUnprocessableEntity)
+		return volume.Volume{}, err
 	default:
 		code = http.StatusInternalServerError
 	}
@@ -735,3 +1512,309 @@ func (h *createVolumeHandlerAsync) creationSucceeded(ctx context.Context, create
 
 	return createdVolume, nil
 }
+
+// GetFile responds with the raw contents of a single file in a volume,
+// supporting Range requests and conditional requests against its ETag.
+func (vs *VolumeServer) GetFile(w http.ResponseWriter, req *http.Request) {
+	handle := rata.Param(req, "handle")
+	path := req.URL.Query().Get("path")
+
+	hLog := vs.logger.Session("get-file", lager.Data{
+		"volume":   handle,
+		"sub-path": path,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	file, err := vs.volumeRepo.OpenFile(ctx, handle, path)
+	if err != nil {
+		if err == volume.ErrVolumeDoesNotExist {
+			hLog.Info("volume-not-found")
+			RespondWithError(w, ErrGetFileFailed, http.StatusNotFound)
+			return
+		}
+
+		if os.IsNotExist(err) {
+			hLog.Info("file-not-found")
+			RespondWithError(w, ErrStreamOutNotFound, http.StatusNotFound)
+			return
+		}
+
+		if err == volume.ErrNotRegularFile {
+			hLog.Info("not-a-regular-file")
+			RespondWithError(w, ErrGetFileNotRegular, http.StatusBadRequest)
+			return
+		}
+
+		if err == volume.ErrPathOutsideVolume {
+			hLog.Info("path-outside-volume")
+			RespondWithError(w, ErrGetFileOutsideVolume, http.StatusBadRequest)
+			return
+		}
+
+		hLog.Error("failed-to-open-file", err)
+		RespondWithError(w, ErrGetFileFailed, http.StatusInternalServerError)
+		return
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		hLog.Error("failed-to-stat-file", err)
+		RespondWithError(w, ErrGetFileFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("ETag", fileETag(info))
+
+	// handles Range, If-Range, If-None-Match and the like
+	http.ServeContent(w, req, "", info.ModTime(), file)
+}
+
+// fileETag identifies a version of a file by when it was modified and how
+// big it is, which is enough to tell whether it has changed without reading
+// it.
+func fileETag(info os.FileInfo) string {
+	return fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size())
+}
+
+// GetTree describes the contents of a volume beneath a path, by default
+// listing just the entries directly within it.
+func (vs *VolumeServer) GetTree(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	handle := rata.Param(req, "handle")
+	path := req.URL.Query().Get("path")
+
+	hLog := vs.logger.Session("get-tree", lager.Data{
+		"volume":   handle,
+		"sub-path": path,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	depth := 1
+	if queryDepth := req.URL.Query().Get("depth"); queryDepth != "" {
+		var err error
+		depth, err = strconv.Atoi(queryDepth)
+		if err != nil || depth < 0 {
+			RespondWithError(w, ErrGetTreeInvalidDepth, http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := vs.volumeRepo.Tree(ctx, handle, path, depth)
+	if err != nil {
+		if err == volume.ErrVolumeDoesNotExist {
+			hLog.Info("volume-not-found")
+			RespondWithError(w, ErrGetTreeFailed, http.StatusNotFound)
+			return
+		}
+
+		if err == volume.ErrPathOutsideVolume {
+			hLog.Info("path-outside-volume")
+			RespondWithError(w, ErrGetTreeOutsideVolume, http.StatusBadRequest)
+			return
+		}
+
+		if err == volume.ErrTreeTooLarge || err == volume.ErrTreeTooDeep {
+			hLog.Info("tree-too-large")
+			RespondWithError(w, ErrGetTreeTooLarge, http.StatusBadRequest)
+			return
+		}
+
+		if os.IsNotExist(err) {
+			hLog.Info("path-not-found")
+			RespondWithError(w, ErrStreamOutNotFound, http.StatusNotFound)
+			return
+		}
+
+		hLog.Error("failed-to-get-tree", err)
+		RespondWithError(w, ErrGetTreeFailed, http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		hLog.Error("failed-to-encode", err)
+	}
+}
+
+// GetDiff lists what has changed in a copy-on-write volume since it was
+// created from its parent.
+func (vs *VolumeServer) GetDiff(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	handle := rata.Param(req, "handle")
+
+	hLog := vs.logger.Session("get-diff", lager.Data{
+		"volume": handle,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	changes, err := vs.volumeRepo.Diff(ctx, handle)
+	if err != nil {
+		if err == volume.ErrVolumeDoesNotExist {
+			hLog.Info("volume-not-found")
+			RespondWithError(w, ErrGetDiffFailed, http.StatusNotFound)
+			return
+		}
+
+		if err == volume.ErrVolumeHasNoParent {
+			hLog.Info("volume-has-no-parent")
+			RespondWithError(w, ErrVolumeHasNoParent, http.StatusBadRequest)
+			return
+		}
+
+		hLog.Error("failed-to-get-diff", err)
+		RespondWithError(w, ErrGetDiffFailed, http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(changes); err != nil {
+		hLog.Error("failed-to-encode", err)
+	}
+}
+
+func (vs *VolumeServer) CreateSnapshot(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	handle := rata.Param(req, "handle")
+
+	hLog := vs.logger.Session("create-snapshot", lager.Data{
+		"volume": handle,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	// the request body is optional
+	var request baggageclaim.SnapshotRequest
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil && err != io.EOF {
+		RespondWithError(w, ErrCreateSnapshotFailed, http.StatusBadRequest)
+		return
+	}
+
+	if request.Handle == "" {
+		snapshotHandle, err := uuid.NewV4()
+		if err != nil {
+			hLog.Error("failed-to-generate-handle", err)
+			RespondWithError(w, ErrCreateSnapshotFailed, http.StatusInternalServerError)
+			return
+		}
+
+		request.Handle = snapshotHandle.String()
+	}
+
+	snapshot, err := vs.volumeRepo.CreateSnapshot(ctx, handle, request.Handle)
+	if err != nil {
+		switch err {
+		case volume.ErrVolumeDoesNotExist:
+			RespondWithError(w, ErrCreateSnapshotFailed, http.StatusNotFound)
+		case volume.ErrSnapshotAlreadyExists:
+			RespondWithError(w, ErrSnapshotAlreadyExists, http.StatusConflict)
+		case volume.ErrInvalidSnapshotHandle:
+			RespondWithError(w, ErrCreateSnapshotFailed, http.StatusBadRequest)
+		case volume.ErrVolumeInUse:
+			RespondWithError(w, ErrVolumeInUse, http.StatusConflict)
+		default:
+			hLog.Error("failed-to-create-snapshot", err)
+			RespondWithError(w, ErrCreateSnapshotFailed, http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		hLog.Error("failed-to-encode", err)
+	}
+}
+
+func (vs *VolumeServer) RestoreSnapshot(w http.ResponseWriter, req *http.Request) {
+	handle := rata.Param(req, "handle")
+	snapshotHandle := rata.Param(req, "snapshot")
+
+	hLog := vs.logger.Session("restore-snapshot", lager.Data{
+		"volume":   handle,
+		"snapshot": snapshotHandle,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	err := vs.volumeRepo.RestoreSnapshot(ctx, handle, snapshotHandle)
+	if err != nil {
+		switch err {
+		case volume.ErrVolumeDoesNotExist:
+			RespondWithError(w, ErrRestoreSnapshotFailed, http.StatusNotFound)
+		case volume.ErrSnapshotDoesNotExist:
+			RespondWithError(w, ErrSnapshotNotFound, http.StatusNotFound)
+		case volume.ErrVolumeHasChildren:
+			RespondWithError(w, ErrVolumeHasChildren, http.StatusConflict)
+		case volume.ErrVolumeIsReadOnly:
+			RespondWithError(w, ErrVolumeIsReadOnly, http.StatusConflict)
+		case volume.ErrVolumeInUse:
+			RespondWithError(w, ErrVolumeInUse, http.StatusConflict)
+		case volume.ErrSnapshotCorrupted:
+			hLog.Error("snapshot-corrupted", err)
+			RespondWithError(w, ErrSnapshotCorrupted, http.StatusInternalServerError)
+		default:
+			hLog.Error("failed-to-restore-snapshot", err)
+			RespondWithError(w, ErrRestoreSnapshotFailed, http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (vs *VolumeServer) DestroySnapshot(w http.ResponseWriter, req *http.Request) {
+	handle := rata.Param(req, "handle")
+	snapshotHandle := rata.Param(req, "snapshot")
+
+	hLog := vs.logger.Session("destroy-snapshot", lager.Data{
+		"volume":   handle,
+		"snapshot": snapshotHandle,
+	})
+
+	hLog.Debug("start")
+	defer hLog.Debug("done")
+
+	ctx := lagerctx.NewContext(req.Context(), hLog)
+
+	err := vs.volumeRepo.DestroySnapshot(ctx, handle, snapshotHandle)
+	if err != nil {
+		switch err {
+		case volume.ErrVolumeDoesNotExist:
+			RespondWithError(w, ErrDestroySnapshotFailed, http.StatusNotFound)
+		case volume.ErrSnapshotDoesNotExist:
+			RespondWithError(w, ErrSnapshotNotFound, http.StatusNotFound)
+		default:
+			hLog.Error("failed-to-destroy-snapshot", err)
+			RespondWithError(w, ErrDestroySnapshotFailed, http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -8,6 +8,7 @@ import (
 	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -71,12 +72,14 @@ var _ = Describe("Volume Server", func() {
 			volume.NewLockManager(),
 			privilegedNamespacer,
 			unprivilegedNamespacer,
+			nil,
+			0,
 		)
 
 		strategerizer := volume.NewStrategerizer()
 
 		re := regexp.MustCompile("eth0")
-		handler, err = api.NewHandler(logger, strategerizer, repo, re, 4, 7766)
+		handler, err = api.NewHandler(logger, strategerizer, repo, re, 4, 7766, false, nil, nil)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -159,6 +162,36 @@ var _ = Describe("Volume Server", func() {
 					Expect(sysStat.Uid).To(Equal(uint32(maxUID)))
 					Expect(sysStat.Gid).To(Equal(uint32(maxGID)))
 				})
+
+				It("maps ownership back out of the namespace when streaming out", func() {
+					request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-in?path=%s", myVolume.Handle, "dest-path"), tgzBuffer)
+					request.Header.Set("Content-Encoding", "gzip")
+					recorder := httptest.NewRecorder()
+					handler.ServeHTTP(recorder, request)
+					Expect(recorder.Code).To(Equal(204))
+
+					request, _ = http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out?path=%s", myVolume.Handle, "dest-path"), nil)
+					request.Header.Set("Accept-Encoding", string(baggageclaim.GzipEncoding))
+					recorder = httptest.NewRecorder()
+					handler.ServeHTTP(recorder, request)
+					Expect(recorder.Code).To(Equal(200))
+
+					gzReader, err := gzip.NewReader(recorder.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					tarReader := tar.NewReader(gzReader)
+
+					for {
+						hdr, err := tarReader.Next()
+						if err == io.EOF {
+							break
+						}
+
+						Expect(err).ToNot(HaveOccurred())
+						Expect(hdr.Uid).To(Equal(0))
+						Expect(hdr.Gid).To(Equal(0))
+					}
+				})
 			})
 
 			Context("when volume privileged", func() {
@@ -4,28 +4,33 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/concourse/go-archive/tarfs"
 	"github.com/klauspost/compress/zstd"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/go-archive/tgzfs"
 
 	"github.com/concourse/baggageclaim"
 	"github.com/concourse/baggageclaim/api"
+	"github.com/concourse/baggageclaim/metrics"
 	"github.com/concourse/baggageclaim/uidgid"
 	"github.com/concourse/baggageclaim/volume"
 	"github.com/concourse/baggageclaim/volume/driver"
@@ -35,6 +40,8 @@ var _ = Describe("Volume Server", func() {
 	var (
 		handler http.Handler
 
+		streamInSigner *api.StreamInSigner
+
 		volumeDir string
 		tempDir   string
 	)
@@ -42,6 +49,8 @@ var _ = Describe("Volume Server", func() {
 	BeforeEach(func() {
 		var err error
 
+		streamInSigner = nil
+
 		tempDir, err = ioutil.TempDir("", fmt.Sprintf("baggageclaim_volume_dir_%d", GinkgoParallelNode()))
 		Expect(err).NotTo(HaveOccurred())
 
@@ -80,12 +89,14 @@ var _ = Describe("Volume Server", func() {
 			volume.NewLockManager(),
 			privilegedNamespacer,
 			unprivilegedNamespacer,
+			nil,
+			0,
 		)
 
 		strategerizer := volume.NewStrategerizer()
 
 		re := regexp.MustCompile("lo")
-		handler, err = api.NewHandler(logger, strategerizer, repo, re, 4, 7766)
+		handler, err = api.NewHandler(logger, strategerizer, repo, re, 4, 7766, false, streamInSigner, nil)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -114,6 +125,77 @@ var _ = Describe("Volume Server", func() {
 		})
 	})
 
+	Describe("recording metrics", func() {
+		It("counts requests by route and status code", func() {
+			ok := metrics.RequestsTotal.WithLabelValues(baggageclaim.ListVolumes, "200")
+			notFound := metrics.RequestsTotal.WithLabelValues(baggageclaim.GetVolume, "404")
+
+			okBefore := testutil.ToFloat64(ok)
+			notFoundBefore := testutil.ToFloat64(notFound)
+
+			request, _ := http.NewRequest("GET", "/volumes", nil)
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			request, _ = http.NewRequest("GET", "/volumes/bogus-handle", nil)
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			Expect(testutil.ToFloat64(ok)).To(Equal(okBefore + 1))
+			Expect(testutil.ToFloat64(notFound)).To(Equal(notFoundBefore + 1))
+		})
+
+		It("counts bytes streamed in and out by encoding", func() {
+			body := &bytes.Buffer{}
+			err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+				Handle:   "some-handle",
+				Strategy: encStrategy(map[string]string{"type": "empty"}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, _ := http.NewRequest("POST", "/volumes", body)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(201))
+
+			streamedIn := metrics.StreamedBytes.WithLabelValues("in", "gzip")
+			streamedOut := metrics.StreamedBytes.WithLabelValues("out", "gzip")
+
+			inBefore := testutil.ToFloat64(streamedIn)
+			outBefore := testutil.ToFloat64(streamedOut)
+
+			tgzBuffer := new(bytes.Buffer)
+			gzWriter := gzip.NewWriter(tgzBuffer)
+			tarWriter := tar.NewWriter(gzWriter)
+			err = tarWriter.WriteHeader(&tar.Header{
+				Name: "some-file",
+				Mode: 0600,
+				Size: int64(len("file-content")),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = tarWriter.Write([]byte("file-content"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tarWriter.Close()).To(Succeed())
+			Expect(gzWriter.Close()).To(Succeed())
+
+			streamSize := tgzBuffer.Len()
+
+			request, _ = http.NewRequest("PUT", "/volumes/some-handle/stream-in", tgzBuffer)
+			request.Header.Set("Content-Encoding", string(baggageclaim.GzipEncoding))
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(204))
+
+			Expect(testutil.ToFloat64(streamedIn)).To(BeNumerically(">=", inBefore+float64(streamSize)))
+
+			request, _ = http.NewRequest("PUT", "/volumes/some-handle/stream-out?path=some-file", nil)
+			request.Header.Set("Accept-Encoding", string(baggageclaim.GzipEncoding))
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(200))
+
+			Expect(testutil.ToFloat64(streamedOut)).To(Equal(outBefore + float64(recorder.Body.Len())))
+		})
+	})
+
 	Describe("querying for volumes with properties", func() {
 		props := baggageclaim.VolumeProperties{
 			"property-query": "value",
@@ -171,6 +253,218 @@ var _ = Describe("Volume Server", func() {
 		})
 	})
 
+	Describe("paging through the volumes", func() {
+		listVolumes := func(query url.Values, accept ...string) *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", "/volumes?"+query.Encode(), nil)
+			for _, mediaType := range accept {
+				request.Header.Set("Accept", mediaType)
+			}
+
+			handler.ServeHTTP(recorder, request)
+			return recorder
+		}
+
+		handlesIn := func(recorder *httptest.ResponseRecorder) []string {
+			var volumes volume.Volumes
+			err := json.NewDecoder(recorder.Body).Decode(&volumes)
+			Expect(err).NotTo(HaveOccurred())
+
+			handles := []string{}
+			for _, vol := range volumes {
+				handles = append(handles, vol.Handle)
+			}
+
+			return handles
+		}
+
+		// nextPage returns the query of the link to the listing's next page
+		nextPage := func(link string) url.Values {
+			Expect(link).To(MatchRegexp(`^<.*>; rel="next"$`))
+
+			next, err := url.Parse(link[1:strings.Index(link, ">")])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next.Path).To(Equal("/volumes"))
+
+			return next.Query()
+		}
+
+		JustBeforeEach(func() {
+			for _, handle := range []string{"handle-c", "handle-a", "handle-b"} {
+				body := &bytes.Buffer{}
+				err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+					Handle: handle,
+					Strategy: encStrategy(map[string]string{
+						"type": "empty",
+					}),
+					Properties: baggageclaim.VolumeProperties{"some": "property"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				recorder := httptest.NewRecorder()
+				request, _ := http.NewRequest("POST", "/volumes", body)
+				handler.ServeHTTP(recorder, request)
+				Expect(recorder.Code).To(Equal(201))
+			}
+		})
+
+		It("lists the volumes in order of their handles", func() {
+			recorder := listVolumes(nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(handlesIn(recorder)).To(Equal([]string{"handle-a", "handle-b", "handle-c"}))
+			Expect(recorder.Header().Get("Link")).To(BeEmpty())
+		})
+
+		It("links a limited listing to where it left off", func() {
+			recorder := listVolumes(url.Values{baggageclaim.LimitQueryKey: {"2"}})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(handlesIn(recorder)).To(Equal([]string{"handle-a", "handle-b"}))
+
+			next := nextPage(recorder.Header().Get("Link"))
+			Expect(next.Get(baggageclaim.LimitQueryKey)).To(Equal("2"))
+			Expect(next.Get(baggageclaim.ContinueQueryKey)).ToNot(BeEmpty())
+
+			recorder = listVolumes(next)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(handlesIn(recorder)).To(Equal([]string{"handle-c"}))
+			Expect(recorder.Header().Get("Link")).To(BeEmpty())
+		})
+
+		It("does not continue a listing that ends exactly at its limit", func() {
+			recorder := listVolumes(url.Values{baggageclaim.LimitQueryKey: {"3"}})
+			Expect(handlesIn(recorder)).To(HaveLen(3))
+			Expect(recorder.Header().Get("Link")).To(BeEmpty())
+		})
+
+		It("pages through volumes filtered by their properties", func() {
+			recorder := listVolumes(url.Values{
+				"some":                     {"property"},
+				baggageclaim.LimitQueryKey: {"1"},
+				baggageclaim.SortQueryKey:  {"-handle"},
+			})
+			Expect(handlesIn(recorder)).To(Equal([]string{"handle-c"}))
+
+			next := nextPage(recorder.Header().Get("Link"))
+			Expect(next.Get("some")).To(Equal("property"))
+
+			next.Set(baggageclaim.LimitQueryKey, "5")
+			Expect(handlesIn(listVolumes(next))).To(Equal([]string{"handle-b", "handle-a"}))
+		})
+
+		It("filters by properties named like the listing's parameters without their prefix", func() {
+			recorder := listVolumes(url.Values{"limit": {"2"}, "sort": {"created"}})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(handlesIn(recorder)).To(BeEmpty())
+		})
+
+		It("returns 400 when given an unknown parameter with the listing's prefix", func() {
+			recorder := listVolumes(url.Values{baggageclaim.ReservedQueryPrefix + "some": {"property"}})
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("sorts the volumes by when they were created", func() {
+			recorder := listVolumes(url.Values{baggageclaim.SortQueryKey: {"created"}})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(handlesIn(recorder)).To(Equal([]string{"handle-c", "handle-a", "handle-b"}))
+
+			recorder = listVolumes(url.Values{baggageclaim.SortQueryKey: {"-created"}})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(handlesIn(recorder)).To(Equal([]string{"handle-b", "handle-a", "handle-c"}))
+		})
+
+		It("sorts the volumes by size, including their usage", func() {
+			recorder := listVolumes(url.Values{baggageclaim.SortQueryKey: {"size"}})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var volumes volume.Volumes
+			err := json.NewDecoder(recorder.Body).Decode(&volumes)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(volumes).To(HaveLen(3))
+			Expect(volumes[0].Usage).ToNot(BeNil())
+		})
+
+		It("includes when the volumes were created", func() {
+			var volumes volume.Volumes
+			err := json.NewDecoder(listVolumes(nil).Body).Decode(&volumes)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(volumes[0].CreatedAt).ToNot(BeNil())
+		})
+
+		It("only includes the selected fields", func() {
+			recorder := listVolumes(url.Values{baggageclaim.LimitQueryKey: {"1"}, baggageclaim.FieldsQueryKey: {"handle,properties"}})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body).To(MatchJSON(`[{"handle":"handle-a","properties":{"some":"property"}}]`))
+		})
+
+		It("includes the usage when it is selected", func() {
+			recorder := listVolumes(url.Values{baggageclaim.LimitQueryKey: {"1"}, baggageclaim.FieldsQueryKey: {"handle,usage"}})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var volumes []map[string]interface{}
+			err := json.NewDecoder(recorder.Body).Decode(&volumes)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(volumes).To(HaveLen(1))
+			Expect(volumes[0]).To(HaveLen(2))
+			Expect(volumes[0]).To(HaveKeyWithValue("handle", "handle-a"))
+			Expect(volumes[0]).To(HaveKeyWithValue("usage", HaveKeyWithValue("inodes", BeNumerically("==", 1))))
+		})
+
+		Context("when asked for NDJSON", func() {
+			It("streams one volume per line", func() {
+				recorder := listVolumes(url.Values{baggageclaim.FieldsQueryKey: {"handle"}}, "application/x-ndjson")
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+				Expect(recorder.Header().Get("Content-Type")).To(Equal("application/x-ndjson"))
+				Expect(recorder.Body.String()).To(Equal(
+					`{"handle":"handle-a"}` + "\n" +
+						`{"handle":"handle-b"}` + "\n" +
+						`{"handle":"handle-c"}` + "\n",
+				))
+			})
+
+			It("sends the link to the next page as a trailer", func() {
+				recorder := listVolumes(url.Values{baggageclaim.FieldsQueryKey: {"handle"}, baggageclaim.LimitQueryKey: {"1"}}, "application/x-ndjson")
+				Expect(recorder.Body.String()).To(Equal(`{"handle":"handle-a"}` + "\n"))
+
+				result := recorder.Result()
+				Expect(result.Header.Get("Trailer")).To(Equal("Link"))
+				Expect(nextPage(result.Trailer.Get("Link")).Get(baggageclaim.ContinueQueryKey)).ToNot(BeEmpty())
+			})
+
+			It("streams nothing when there are no volumes to list", func() {
+				recorder := listVolumes(url.Values{"bogus": {"property"}}, "application/x-ndjson")
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+				Expect(recorder.Header().Get("Content-Type")).To(Equal("application/x-ndjson"))
+				Expect(recorder.Body.String()).To(BeEmpty())
+			})
+		})
+
+		It("returns 400 when the limit is not a positive integer", func() {
+			Expect(listVolumes(url.Values{baggageclaim.LimitQueryKey: {"0"}}).Code).To(Equal(http.StatusBadRequest))
+			Expect(listVolumes(url.Values{baggageclaim.LimitQueryKey: {"lots"}}).Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("returns 400 when sorting by anything else", func() {
+			Expect(listVolumes(url.Values{baggageclaim.SortQueryKey: {"path"}}).Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("returns 400 when selecting an unknown field", func() {
+			Expect(listVolumes(url.Values{baggageclaim.FieldsQueryKey: {"handle,bogus"}}).Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("returns 400 when the continue token is invalid", func() {
+			Expect(listVolumes(url.Values{baggageclaim.ContinueQueryKey: {"bogus"}}).Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("returns 400 when continuing a listing that was sorted differently", func() {
+			next := nextPage(listVolumes(url.Values{baggageclaim.LimitQueryKey: {"1"}}).Header().Get("Link"))
+			next.Set(baggageclaim.SortQueryKey, "created")
+			Expect(listVolumes(next).Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
 	Describe("streaming tar files into volumes", func() {
 		var (
 			myVolume     volume.Volume
@@ -235,6 +529,74 @@ var _ = Describe("Volume Server", func() {
 					Expect(ioutil.ReadFile(tarContentsPath)).To(Equal([]byte("file-content")))
 				})
 
+				Context("when stream-in urls handed to peers are signed", func() {
+					BeforeEach(func() {
+						streamInSigner = api.NewStreamInSigner([]byte("some-secret"), time.Minute)
+					})
+
+					streamIn := func(query string) *httptest.ResponseRecorder {
+						request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-in?%s", myVolume.Handle, query), tgzBuffer)
+						request.Header.Set("Content-Encoding", string(baggageclaim.GzipEncoding))
+						recorder := httptest.NewRecorder()
+						handler.ServeHTTP(recorder, request)
+						return recorder
+					}
+
+					It("extracts the tar stream through a signed url", func() {
+						recorder := streamIn(streamInSigner.Sign(myVolume.Handle, "dest-path").Encode())
+						Expect(recorder.Code).To(Equal(204))
+
+						tarContentsPath := filepath.Join(volumeDir, "live", myVolume.Handle, "volume", "dest-path", "some-file")
+						Expect(ioutil.ReadFile(tarContentsPath)).To(Equal([]byte("file-content")))
+					})
+
+					It("refuses a url signed for another path", func() {
+						query := streamInSigner.Sign(myVolume.Handle, "other-path")
+						query.Set("path", "dest-path")
+
+						recorder := streamIn(query.Encode())
+						Expect(recorder.Code).To(Equal(403))
+						Expect(recorder.Body).To(MatchJSON(`{"error":"invalid stream-in signature"}`))
+
+						Expect(filepath.Join(volumeDir, "live", myVolume.Handle, "volume", "dest-path")).ToNot(BeADirectory())
+					})
+
+					It("refuses a url signed for another volume", func() {
+						recorder := streamIn(streamInSigner.Sign("other-handle", "dest-path").Encode())
+						Expect(recorder.Code).To(Equal(403))
+					})
+
+					It("refuses a url signed with another secret", func() {
+						otherSigner := api.NewStreamInSigner([]byte("other-secret"), time.Minute)
+
+						recorder := streamIn(otherSigner.Sign(myVolume.Handle, "dest-path").Encode())
+						Expect(recorder.Code).To(Equal(403))
+					})
+
+					It("refuses a url whose expiry has been extended", func() {
+						query := streamInSigner.Sign(myVolume.Handle, "dest-path")
+						query.Set("expires", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+
+						recorder := streamIn(query.Encode())
+						Expect(recorder.Code).To(Equal(403))
+					})
+
+					It("refuses a url that has expired", func() {
+						expiredSigner := api.NewStreamInSigner([]byte("some-secret"), -time.Minute)
+
+						recorder := streamIn(expiredSigner.Sign(myVolume.Handle, "dest-path").Encode())
+						Expect(recorder.Code).To(Equal(403))
+						Expect(recorder.Body).To(MatchJSON(`{"error":"stream-in url has expired"}`))
+					})
+
+					It("refuses an unsigned stream-in when no tokens are configured", func() {
+						recorder := streamIn("path=dest-path")
+						Expect(recorder.Code).To(Equal(403))
+						Expect(recorder.Body).To(MatchJSON(`{"error":"stream-in url is not signed"}`))
+
+						Expect(filepath.Join(volumeDir, "live", myVolume.Handle, "volume", "dest-path")).ToNot(BeADirectory())
+					})
+				})
 			})
 
 			Context("when using zstd encoding", func() {
@@ -313,6 +675,125 @@ var _ = Describe("Volume Server", func() {
 			})
 		})
 
+		Context("when streaming in with an upload session", func() {
+			var (
+				tarStream []byte
+				dataPath  string
+			)
+
+			BeforeEach(func() {
+				buf := new(bytes.Buffer)
+				tarWriter := tar.NewWriter(buf)
+
+				contents := bytes.Repeat([]byte("file-content\n"), 1024)
+				err := tarWriter.WriteHeader(&tar.Header{
+					Name: "some-file",
+					Mode: 0600,
+					Size: int64(len(contents)),
+				})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = tarWriter.Write(contents)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tarWriter.Close()).To(Succeed())
+
+				tarStream = buf.Bytes()
+			})
+
+			JustBeforeEach(func() {
+				dataPath = filepath.Join(volumeDir, "live", myVolume.Handle, "volume", "dest-path", "some-file")
+			})
+
+			streamIn := func(session string, contentRange string, body io.Reader) *httptest.ResponseRecorder {
+				request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-in?path=dest-path&session=%s", myVolume.Handle, session), body)
+				if contentRange != "" {
+					request.Header.Set("Content-Range", contentRange)
+				}
+
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+				return recorder
+			}
+
+			uploadOffset := func(handle string, session string) *httptest.ResponseRecorder {
+				request, _ := http.NewRequest("GET", fmt.Sprintf("/volumes/%s/stream-in?session=%s", handle, session), nil)
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+				return recorder
+			}
+
+			It("extracts the tar stream into the volume's DataPath", func() {
+				recorder := streamIn("some-session", "", bytes.NewReader(tarStream))
+				Expect(recorder.Code).To(Equal(204))
+
+				Expect(ioutil.ReadFile(dataPath)).To(HaveLen(13 * 1024))
+			})
+
+			Context("when the stream is interrupted", func() {
+				var sent int
+
+				JustBeforeEach(func() {
+					sent = len(tarStream) / 2
+
+					recorder := streamIn("some-session", "", io.MultiReader(bytes.NewReader(tarStream[:sent]), interruptedReader{}))
+					Expect(recorder.Code).To(Equal(400))
+				})
+
+				It("reports how much of the stream was received", func() {
+					recorder := uploadOffset(myVolume.Handle, "some-session")
+					Expect(recorder.Code).To(Equal(200))
+					Expect(recorder.Header().Get("Upload-Offset")).To(Equal(fmt.Sprintf("%d", sent)))
+
+					var response baggageclaim.StreamInOffsetResponse
+					err := json.NewDecoder(recorder.Body).Decode(&response)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response.Offset).To(Equal(int64(sent)))
+				})
+
+				It("extracts the whole stream once the rest is sent", func() {
+					recorder := streamIn("some-session", fmt.Sprintf("bytes %d-*/*", sent), bytes.NewReader(tarStream[sent:]))
+					Expect(recorder.Code).To(Equal(204))
+
+					Expect(ioutil.ReadFile(dataPath)).To(Equal(bytes.Repeat([]byte("file-content\n"), 1024)))
+				})
+
+				It("returns 416 with the offset to resume from when resuming from elsewhere", func() {
+					recorder := streamIn("some-session", fmt.Sprintf("bytes %d-*/*", sent+1), bytes.NewReader(tarStream[sent+1:]))
+					Expect(recorder.Code).To(Equal(http.StatusRequestedRangeNotSatisfiable))
+					Expect(recorder.Header().Get("Upload-Offset")).To(Equal(fmt.Sprintf("%d", sent)))
+				})
+
+				It("returns 409 when resuming to a different path", func() {
+					request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-in?path=other-path&session=some-session", myVolume.Handle), bytes.NewReader(tarStream[sent:]))
+					request.Header.Set("Content-Range", fmt.Sprintf("bytes %d-*/*", sent))
+
+					recorder := httptest.NewRecorder()
+					handler.ServeHTTP(recorder, request)
+					Expect(recorder.Code).To(Equal(http.StatusConflict))
+				})
+			})
+
+			It("returns 400 when the Content-Range is invalid", func() {
+				recorder := streamIn("some-session", "bytes=nope", bytes.NewReader(tarStream))
+				Expect(recorder.Code).To(Equal(400))
+			})
+
+			It("returns 400 when the session is invalid", func() {
+				recorder := streamIn("..", "", bytes.NewReader(tarStream))
+				Expect(recorder.Code).To(Equal(400))
+			})
+
+			It("reports that an unknown session has received nothing", func() {
+				recorder := uploadOffset(myVolume.Handle, "unknown-session")
+				Expect(recorder.Code).To(Equal(200))
+				Expect(recorder.Header().Get("Upload-Offset")).To(Equal("0"))
+			})
+
+			It("returns 404 for the offset when the volume is not found", func() {
+				recorder := uploadOffset("invalid-handle", "some-session")
+				Expect(recorder.Code).To(Equal(404))
+			})
+		})
+
 		It("returns 404 when volume is not found", func() {
 			tgzBuffer = new(bytes.Buffer)
 			request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-in", "invalid-handle"), tgzBuffer)
@@ -322,15 +803,15 @@ var _ = Describe("Volume Server", func() {
 		})
 	})
 
-	Describe("streaming tar out of a volume", func() {
+	Describe("getting a file from a volume", func() {
 		var (
-			myVolume  volume.Volume
-			tarBuffer *bytes.Buffer
-			encoding  string
+			myVolume   volume.Volume
+			dataPath   string
+			privileged bool
 		)
 
 		BeforeEach(func() {
-			tarBuffer = new(bytes.Buffer)
+			privileged = false
 		})
 
 		JustBeforeEach(func() {
@@ -341,6 +822,7 @@ var _ = Describe("Volume Server", func() {
 				Strategy: encStrategy(map[string]string{
 					"type": "empty",
 				}),
+				Privileged: privileged,
 			})
 			Expect(err).NotTo(HaveOccurred())
 
@@ -353,40 +835,446 @@ var _ = Describe("Volume Server", func() {
 
 			err = json.NewDecoder(recorder.Body).Decode(&myVolume)
 			Expect(err).NotTo(HaveOccurred())
+
+			dataPath = filepath.Join(volumeDir, "live", myVolume.Handle, "volume")
+
+			err = os.MkdirAll(filepath.Join(dataPath, "some-dir"), 0755)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(dataPath, "some-dir", "version.json"), []byte(`{"ref":"some-ref"}`), 0644)
+			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("returns 404 when source path is invalid", func() {
-			request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out?path=%s", myVolume.Handle, "bogus-path"), nil)
-			request.Header.Set("Accept-Encoding", string(baggageclaim.GzipEncoding))
+		getFile := func(handle string, path string, header http.Header) *httptest.ResponseRecorder {
+			request, _ := http.NewRequest("GET", fmt.Sprintf("/volumes/%s/files?path=%s", handle, path), nil)
+			for name, values := range header {
+				request.Header[name] = values
+			}
+
 			recorder := httptest.NewRecorder()
 			handler.ServeHTTP(recorder, request)
-			Expect(recorder.Code).To(Equal(404))
+			return recorder
+		}
 
-			var responseError *api.ErrorResponse
-			err := json.NewDecoder(recorder.Body).Decode(&responseError)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(responseError.Message).To(Equal("no such file or directory"))
+		It("responds with the raw contents of the file", func() {
+			recorder := getFile(myVolume.Handle, "some-dir/version.json", nil)
+			Expect(recorder.Code).To(Equal(200))
+			Expect(recorder.Body.String()).To(Equal(`{"ref":"some-ref"}`))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/octet-stream"))
+			Expect(recorder.Header().Get("Content-Length")).To(Equal("18"))
+			Expect(recorder.Header().Get("Accept-Ranges")).To(Equal("bytes"))
+			Expect(recorder.Header().Get("ETag")).ToNot(BeEmpty())
 		})
 
-		Context("when streaming a file", func() {
+		It("responds with part of the file given a Range", func() {
+			recorder := getFile(myVolume.Handle, "some-dir/version.json", http.Header{"Range": {"bytes=8-15"}})
+			Expect(recorder.Code).To(Equal(http.StatusPartialContent))
+			Expect(recorder.Body.String()).To(Equal("some-ref"))
+			Expect(recorder.Header().Get("Content-Range")).To(Equal("bytes 8-15/18"))
+			Expect(recorder.Header().Get("Content-Length")).To(Equal("8"))
+		})
 
-			JustBeforeEach(func() {
-				streamInRequest, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-in?path=%s", myVolume.Handle, "dest-path"), tarBuffer)
-				streamInRequest.Header.Set("Content-Encoding", encoding)
-				streamInRecorder := httptest.NewRecorder()
-				handler.ServeHTTP(streamInRecorder, streamInRequest)
-				Expect(streamInRecorder.Code).To(Equal(204))
+		It("responds with 304 when the file matches the ETag", func() {
+			etag := getFile(myVolume.Handle, "some-dir/version.json", nil).Header().Get("ETag")
 
-				tarContentsPath := filepath.Join(volumeDir, "live", myVolume.Handle, "volume", "dest-path", "some-file")
-				Expect(tarContentsPath).To(BeAnExistingFile())
+			recorder := getFile(myVolume.Handle, "some-dir/version.json", http.Header{"If-None-Match": {etag}})
+			Expect(recorder.Code).To(Equal(http.StatusNotModified))
+		})
 
-				Expect(ioutil.ReadFile(tarContentsPath)).To(Equal([]byte("file-content")))
-			})
+		It("changes the ETag when the file changes", func() {
+			etag := getFile(myVolume.Handle, "some-dir/version.json", nil).Header().Get("ETag")
 
-			Context("when using gzip encoding", func() {
-				BeforeEach(func() {
-					gzWriter := gzip.NewWriter(tarBuffer)
-					defer gzWriter.Close()
+			err := ioutil.WriteFile(filepath.Join(dataPath, "some-dir", "version.json"), []byte(`{"ref":"other-ref"}`), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := getFile(myVolume.Handle, "some-dir/version.json", http.Header{"If-None-Match": {etag}})
+			Expect(recorder.Code).To(Equal(200))
+			Expect(recorder.Header().Get("ETag")).ToNot(Equal(etag))
+		})
+
+		It("returns 404 when the file does not exist", func() {
+			recorder := getFile(myVolume.Handle, "some-dir/bogus", nil)
+			Expect(recorder.Code).To(Equal(404))
+			Expect(recorder.Body.String()).To(ContainSubstring(api.ErrStreamOutNotFound.Error()))
+		})
+
+		It("returns 400 when the path is a directory", func() {
+			recorder := getFile(myVolume.Handle, "some-dir", nil)
+			Expect(recorder.Code).To(Equal(400))
+		})
+
+		It("returns 404 when the volume does not exist", func() {
+			recorder := getFile("bogus-handle", "some-dir/version.json", nil)
+			Expect(recorder.Code).To(Equal(404))
+		})
+
+		Context("when the volume is privileged", func() {
+			BeforeEach(func() {
+				privileged = true
+			})
+
+			It("returns 400 when a symlink may lead onto the host", func() {
+				err := os.Symlink("/some-dir/version.json", filepath.Join(dataPath, "absolute-link"))
+				Expect(err).NotTo(HaveOccurred())
+
+				recorder := getFile(myVolume.Handle, "absolute-link", nil)
+				Expect(recorder.Code).To(Equal(400))
+				Expect(recorder.Body.String()).To(ContainSubstring(api.ErrGetFileOutsideVolume.Error()))
+			})
+		})
+	})
+
+	Describe("listing the contents of a volume", func() {
+		var myVolume volume.Volume
+
+		JustBeforeEach(func() {
+			body := &bytes.Buffer{}
+
+			err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+				Handle: "some-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := http.NewRequest("POST", "/volumes", body)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(201))
+
+			err = json.NewDecoder(recorder.Body).Decode(&myVolume)
+			Expect(err).NotTo(HaveOccurred())
+
+			dataPath := filepath.Join(volumeDir, "live", myVolume.Handle, "volume")
+
+			err = os.MkdirAll(filepath.Join(dataPath, "some-dir", "nested-dir"), 0755)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(dataPath, "some-dir", "some-file"), []byte("some-contents"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		getTree := func(handle string, query string) *httptest.ResponseRecorder {
+			request, _ := http.NewRequest("GET", fmt.Sprintf("/volumes/%s/tree?%s", handle, query), nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder
+		}
+
+		decode := func(recorder *httptest.ResponseRecorder) []baggageclaim.TreeEntry {
+			var entries []baggageclaim.TreeEntry
+			err := json.NewDecoder(recorder.Body).Decode(&entries)
+			Expect(err).NotTo(HaveOccurred())
+			return entries
+		}
+
+		It("lists the entries directly within the path", func() {
+			recorder := getTree(myVolume.Handle, "path=some-dir")
+			Expect(recorder.Code).To(Equal(200))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+			entries := decode(recorder)
+			Expect(entries).To(HaveLen(3))
+
+			Expect(entries[0].Name).To(Equal("."))
+			Expect(entries[0].Type).To(Equal("directory"))
+
+			Expect(entries[1].Name).To(Equal("nested-dir"))
+
+			Expect(entries[2].Name).To(Equal("some-file"))
+			Expect(entries[2].Type).To(Equal("file"))
+			Expect(entries[2].Size).To(Equal(int64(len("some-contents"))))
+			Expect(entries[2].Mode).To(Equal(uint32(0644)))
+		})
+
+		It("lists as deep as asked", func() {
+			recorder := getTree(myVolume.Handle, "depth=0")
+			Expect(recorder.Code).To(Equal(200))
+			Expect(decode(recorder)).To(HaveLen(1))
+		})
+
+		It("returns 400 when the depth is invalid", func() {
+			recorder := getTree(myVolume.Handle, "depth=-1")
+			Expect(recorder.Code).To(Equal(400))
+
+			recorder = getTree(myVolume.Handle, "depth=deep")
+			Expect(recorder.Code).To(Equal(400))
+		})
+
+		It("returns 400 when the path leads outside of the volume", func() {
+			recorder := getTree(myVolume.Handle, "path=../../")
+			Expect(recorder.Code).To(Equal(400))
+			Expect(recorder.Body.String()).To(ContainSubstring(api.ErrGetTreeOutsideVolume.Error()))
+		})
+
+		It("returns 404 when the path does not exist", func() {
+			recorder := getTree(myVolume.Handle, "path=bogus")
+			Expect(recorder.Code).To(Equal(404))
+			Expect(recorder.Body.String()).To(ContainSubstring(api.ErrStreamOutNotFound.Error()))
+		})
+
+		It("returns 404 when the volume does not exist", func() {
+			recorder := getTree("bogus-handle", "")
+			Expect(recorder.Code).To(Equal(404))
+		})
+	})
+
+	Describe("diffing a volume against its parent", func() {
+		var parentVolume, childVolume volume.Volume
+
+		createVolume := func(request baggageclaim.VolumeRequest) volume.Volume {
+			body := &bytes.Buffer{}
+			err := json.NewEncoder(body).Encode(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			req, err := http.NewRequest("POST", "/volumes", body)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			Expect(recorder.Code).To(Equal(201))
+
+			var created volume.Volume
+			err = json.NewDecoder(recorder.Body).Decode(&created)
+			Expect(err).NotTo(HaveOccurred())
+
+			return created
+		}
+
+		JustBeforeEach(func() {
+			parentVolume = createVolume(baggageclaim.VolumeRequest{
+				Handle: "parent-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+				Privileged: true,
+			})
+
+			parentPath := filepath.Join(volumeDir, "live", parentVolume.Handle, "volume")
+
+			err := ioutil.WriteFile(filepath.Join(parentPath, "doomed-file"), []byte("doomed"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			childVolume = createVolume(baggageclaim.VolumeRequest{
+				Handle: "child-handle",
+				Strategy: encStrategy(map[string]string{
+					"type":   "cow",
+					"volume": parentVolume.Handle,
+				}),
+				Privileged: true,
+			})
+
+			childPath := filepath.Join(volumeDir, "live", childVolume.Handle, "volume")
+
+			err = os.Remove(filepath.Join(childPath, "doomed-file"))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(childPath, "new-file"), []byte("new"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		get := func(path string) *httptest.ResponseRecorder {
+			request, _ := http.NewRequest("GET", path, nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder
+		}
+
+		It("lists the changes", func() {
+			recorder := get("/volumes/child-handle/diff")
+			Expect(recorder.Code).To(Equal(200))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+			var changes []baggageclaim.Change
+			err := json.NewDecoder(recorder.Body).Decode(&changes)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(changes).To(Equal([]baggageclaim.Change{
+				{Path: "doomed-file", Kind: "deleted"},
+				{Path: "new-file", Kind: "added"},
+			}))
+		})
+
+		It("streams out only the changes when asked to", func() {
+			request, _ := http.NewRequest("PUT", "/volumes/child-handle/stream-out?diff=true", nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(200))
+
+			names := []string{}
+
+			tarReader := tar.NewReader(recorder.Body)
+			for {
+				hdr, err := tarReader.Next()
+				if err == io.EOF {
+					break
+				}
+
+				Expect(err).NotTo(HaveOccurred())
+				names = append(names, hdr.Name)
+			}
+
+			Expect(names).To(Equal([]string{".wh.doomed-file", "new-file"}))
+		})
+
+		It("can recreate the volume from its parent and its diff", func() {
+			request, _ := http.NewRequest("PUT", "/volumes/child-handle/stream-out?diff=true", nil)
+			request.Header.Set("Accept-Encoding", "gzip")
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(200))
+
+			layerPath := filepath.Join(tempDir, "layer.tgz")
+			err := ioutil.WriteFile(layerPath, recorder.Body.Bytes(), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			recreated := createVolume(baggageclaim.VolumeRequest{
+				Handle: "recreated-handle",
+				Strategy: encStrategy(map[string]string{
+					"type":   "layer",
+					"volume": parentVolume.Handle,
+					"path":   layerPath,
+				}),
+				Privileged: true,
+			})
+
+			recreatedPath := filepath.Join(volumeDir, "live", recreated.Handle, "volume")
+			Expect(filepath.Join(recreatedPath, "doomed-file")).ToNot(BeAnExistingFile())
+
+			content, err := ioutil.ReadFile(filepath.Join(recreatedPath, "new-file"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("new"))
+		})
+
+		It("can recreate the volume from an oci image of its chain", func() {
+			request, _ := http.NewRequest("PUT", "/volumes/child-handle/stream-out?format=oci&chain=true", nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(200))
+
+			imagePath := filepath.Join(tempDir, "image")
+			err := tarfs.Extract(recorder.Body, imagePath)
+			Expect(err).NotTo(HaveOccurred())
+
+			recreated := createVolume(baggageclaim.VolumeRequest{
+				Handle: "recreated-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "oci",
+					"path": imagePath,
+					"ref":  "child-handle",
+				}),
+				Privileged: true,
+			})
+
+			recreatedPath := filepath.Join(volumeDir, "live", recreated.Handle, "volume")
+			Expect(filepath.Join(recreatedPath, "doomed-file")).ToNot(BeAnExistingFile())
+
+			content, err := ioutil.ReadFile(filepath.Join(recreatedPath, "new-file"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("new"))
+		})
+
+		It("returns 400 when streaming out part of a volume as an oci image", func() {
+			request, _ := http.NewRequest("PUT", "/volumes/child-handle/stream-out?format=oci&path=some-dir", nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(400))
+			Expect(recorder.Body.String()).To(ContainSubstring(api.ErrStreamOutOCIPath.Error()))
+		})
+
+		It("returns 400 for an unknown stream-out format", func() {
+			request, _ := http.NewRequest("PUT", "/volumes/child-handle/stream-out?format=bogus", nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(400))
+			Expect(recorder.Body.String()).To(ContainSubstring(api.ErrStreamOutUnknownFormat.Error()))
+		})
+
+		It("returns 400 when the volume has no parent", func() {
+			recorder := get("/volumes/parent-handle/diff")
+			Expect(recorder.Code).To(Equal(400))
+			Expect(recorder.Body.String()).To(ContainSubstring(api.ErrVolumeHasNoParent.Error()))
+
+			request, _ := http.NewRequest("PUT", "/volumes/parent-handle/stream-out?diff=true", nil)
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(400))
+		})
+
+		It("returns 404 when the volume does not exist", func() {
+			recorder := get("/volumes/bogus-handle/diff")
+			Expect(recorder.Code).To(Equal(404))
+		})
+	})
+
+	Describe("streaming tar out of a volume", func() {
+		var (
+			myVolume  volume.Volume
+			tarBuffer *bytes.Buffer
+			encoding  string
+		)
+
+		BeforeEach(func() {
+			tarBuffer = new(bytes.Buffer)
+		})
+
+		JustBeforeEach(func() {
+			body := &bytes.Buffer{}
+
+			err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+				Handle: "some-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := http.NewRequest("POST", "/volumes", body)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(201))
+
+			err = json.NewDecoder(recorder.Body).Decode(&myVolume)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns 404 when source path is invalid", func() {
+			request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out?path=%s", myVolume.Handle, "bogus-path"), nil)
+			request.Header.Set("Accept-Encoding", string(baggageclaim.GzipEncoding))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(404))
+
+			var responseError *api.ErrorResponse
+			err := json.NewDecoder(recorder.Body).Decode(&responseError)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(responseError.Message).To(Equal("no such file or directory"))
+		})
+
+		Context("when streaming a file", func() {
+
+			JustBeforeEach(func() {
+				streamInRequest, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-in?path=%s", myVolume.Handle, "dest-path"), tarBuffer)
+				streamInRequest.Header.Set("Content-Encoding", encoding)
+				streamInRecorder := httptest.NewRecorder()
+				handler.ServeHTTP(streamInRecorder, streamInRequest)
+				Expect(streamInRecorder.Code).To(Equal(204))
+
+				tarContentsPath := filepath.Join(volumeDir, "live", myVolume.Handle, "volume", "dest-path", "some-file")
+				Expect(tarContentsPath).To(BeAnExistingFile())
+
+				Expect(ioutil.ReadFile(tarContentsPath)).To(Equal([]byte("file-content")))
+			})
+
+			Context("when using gzip encoding", func() {
+				BeforeEach(func() {
+					gzWriter := gzip.NewWriter(tarBuffer)
+					defer gzWriter.Close()
 
 					tarWriter := tar.NewWriter(gzWriter)
 					defer tarWriter.Close()
@@ -477,203 +1365,1224 @@ var _ = Describe("Volume Server", func() {
 			})
 		})
 
-		Context("when streaming a directory", func() {
-			var tarDir string
+		Context("when streaming a directory", func() {
+			var tarDir string
+
+			BeforeEach(func() {
+				tarDir = filepath.Join(tempDir, "tar-dir")
+
+				err := os.MkdirAll(filepath.Join(tarDir, "sub"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ioutil.WriteFile(filepath.Join(tarDir, "sub", "some-file"), []byte("some-file-content"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ioutil.WriteFile(filepath.Join(tarDir, "other-file"), []byte("other-file-content"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				tarBuffer = new(bytes.Buffer)
+			})
+
+			JustBeforeEach(func() {
+				streamInRequest, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-in?path=%s", myVolume.Handle, "dest-path"), tarBuffer)
+				streamInRequest.Header.Set("Content-Encoding", encoding)
+				streamInRecorder := httptest.NewRecorder()
+				handler.ServeHTTP(streamInRecorder, streamInRequest)
+				Expect(streamInRecorder.Code).To(Equal(204))
+
+				tarContentsPath := filepath.Join(volumeDir, "live", myVolume.Handle, "volume", "dest-path")
+				Expect(tarContentsPath).To(BeADirectory())
+			})
+
+			Context("when using gzip encoding", func() {
+				BeforeEach(func() {
+					err := tgzfs.Compress(tarBuffer, tarDir, ".")
+					Expect(err).NotTo(HaveOccurred())
+					encoding = string(baggageclaim.GzipEncoding)
+				})
+
+				It("creates a tar", func() {
+					request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out?path=%s", myVolume.Handle, "dest-path"), nil)
+					request.Header.Set("Accept-Encoding", string(baggageclaim.GzipEncoding))
+					recorder := httptest.NewRecorder()
+					handler.ServeHTTP(recorder, request)
+					Expect(recorder.Code).To(Equal(200))
+
+					unpackedDir := filepath.Join(tempDir, "unpacked-dir")
+					err := os.MkdirAll(unpackedDir, os.ModePerm)
+					Expect(err).NotTo(HaveOccurred())
+					defer os.RemoveAll(unpackedDir)
+
+					err = tgzfs.Extract(recorder.Body, unpackedDir)
+					Expect(err).NotTo(HaveOccurred())
+
+					fileInfo, err := os.Stat(filepath.Join(unpackedDir, "other-file"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fileInfo.IsDir()).To(BeFalse())
+					Expect(fileInfo.Size()).To(Equal(int64(len("other-file-content"))))
+
+					contents, err := ioutil.ReadFile(filepath.Join(unpackedDir, "other-file"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(Equal("other-file-content"))
+
+					dirInfo, err := os.Stat(filepath.Join(unpackedDir, "sub"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dirInfo.IsDir()).To(BeTrue())
+
+					fileInfo, err = os.Stat(filepath.Join(unpackedDir, "sub/some-file"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fileInfo.IsDir()).To(BeFalse())
+					Expect(fileInfo.Size()).To(Equal(int64(len("some-file-content"))))
+
+					contents, err = ioutil.ReadFile(filepath.Join(unpackedDir, "sub/some-file"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(Equal("some-file-content"))
+				})
+			})
+
+			Context("when using zstd encoding", func() {
+				BeforeEach(func() {
+					zstdWriter, err := zstd.NewWriter(tarBuffer)
+					Expect(err).ToNot(HaveOccurred())
+					err = tarfs.Compress(zstdWriter, tarDir, ".")
+					Expect(err).NotTo(HaveOccurred())
+					zstdWriter.Close()
+					encoding = string(baggageclaim.ZstdEncoding)
+				})
+
+				It("creates a tar", func() {
+					request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out?path=%s", myVolume.Handle, "dest-path"), nil)
+					request.Header.Set("Accept-Encoding", string(baggageclaim.ZstdEncoding))
+					recorder := httptest.NewRecorder()
+					handler.ServeHTTP(recorder, request)
+					Expect(recorder.Code).To(Equal(200))
+
+					unpackedDir := filepath.Join(tempDir, "unpacked-dir")
+					err := os.MkdirAll(unpackedDir, os.ModePerm)
+					Expect(err).NotTo(HaveOccurred())
+					defer os.RemoveAll(unpackedDir)
+
+					tarByteReader, err := zstd.NewReader(recorder.Body)
+					Expect(err).NotTo(HaveOccurred())
+					err = tarfs.Extract(tarByteReader, unpackedDir)
+					Expect(err).NotTo(HaveOccurred())
+					tarByteReader.Close()
+					fileInfo, err := os.Stat(filepath.Join(unpackedDir, "other-file"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fileInfo.IsDir()).To(BeFalse())
+					Expect(fileInfo.Size()).To(Equal(int64(len("other-file-content"))))
+
+					contents, err := ioutil.ReadFile(filepath.Join(unpackedDir, "other-file"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(Equal("other-file-content"))
+
+					dirInfo, err := os.Stat(filepath.Join(unpackedDir, "sub"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dirInfo.IsDir()).To(BeTrue())
+
+					fileInfo, err = os.Stat(filepath.Join(unpackedDir, "sub/some-file"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fileInfo.IsDir()).To(BeFalse())
+					Expect(fileInfo.Size()).To(Equal(int64(len("some-file-content"))))
+
+					contents, err = ioutil.ReadFile(filepath.Join(unpackedDir, "sub/some-file"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(Equal("some-file-content"))
+				})
+			})
+		})
+
+		Context("when using any other registered encoding", func() {
+			var streamOutRecorder *httptest.ResponseRecorder
+
+			streamInAndOut := func(contentEncoding string, acceptEncoding string) {
+				enc, found := volume.LookupEncoding(contentEncoding)
+				Expect(found).To(BeTrue())
+
+				encoder, err := enc.NewWriter(tarBuffer)
+				Expect(err).NotTo(HaveOccurred())
+
+				tarWriter := tar.NewWriter(encoder)
+				Expect(tarWriter.WriteHeader(&tar.Header{
+					Name: "some-file",
+					Mode: 0600,
+					Size: int64(len("file-content")),
+				})).To(Succeed())
+				_, err = tarWriter.Write([]byte("file-content"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tarWriter.Close()).To(Succeed())
+				Expect(encoder.Close()).To(Succeed())
+
+				streamInRequest, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-in?path=%s", myVolume.Handle, "dest-path"), tarBuffer)
+				streamInRequest.Header.Set("Content-Encoding", contentEncoding)
+				streamInRecorder := httptest.NewRecorder()
+				handler.ServeHTTP(streamInRecorder, streamInRequest)
+				Expect(streamInRecorder.Code).To(Equal(204))
+
+				request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out?path=%s", myVolume.Handle, "dest-path/some-file"), nil)
+				request.Header.Set("Accept-Encoding", acceptEncoding)
+				streamOutRecorder = httptest.NewRecorder()
+				handler.ServeHTTP(streamOutRecorder, request)
+				Expect(streamOutRecorder.Code).To(Equal(200))
+			}
+
+			expectStreamedOut := func(encoding string) {
+				enc, found := volume.LookupEncoding(encoding)
+				Expect(found).To(BeTrue())
+
+				decoder, err := enc.NewReader(streamOutRecorder.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				tarReader := tar.NewReader(decoder)
+
+				hdr, err := tarReader.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hdr.Name).To(Equal("some-file"))
+
+				Expect(ioutil.ReadAll(tarReader)).To(Equal([]byte("file-content")))
+			}
+
+			for _, encoding := range []baggageclaim.Encoding{
+				baggageclaim.IdentityEncoding,
+				baggageclaim.Lz4Encoding,
+				baggageclaim.XzEncoding,
+			} {
+				encoding := string(encoding)
+
+				It("streams in and out with "+encoding, func() {
+					streamInAndOut(encoding, encoding)
+					expectStreamedOut(encoding)
+				})
+			}
+
+			It("responds with the most preferred supported encoding", func() {
+				streamInAndOut(string(baggageclaim.GzipEncoding), "bogus;q=1.0, gzip;q=0.5, xz;q=0.8")
+				Expect(streamOutRecorder.Header().Get("Content-Encoding")).To(Equal("xz"))
+				expectStreamedOut("xz")
+			})
+
+			It("does not stream out with an encoding given a quality of zero", func() {
+				streamInAndOut(string(baggageclaim.GzipEncoding), "zstd;q=0, lz4")
+				Expect(streamOutRecorder.Header().Get("Content-Encoding")).To(Equal("lz4"))
+				expectStreamedOut("lz4")
+			})
+
+			It("streams out uncompressed when no encoding is requested", func() {
+				streamInAndOut(string(baggageclaim.GzipEncoding), "")
+				Expect(streamOutRecorder.Header().Get("Content-Encoding")).To(BeEmpty())
+				expectStreamedOut("identity")
+			})
+		})
+
+		Context("when using an unsupported encoding", func() {
+			It("returns 400 when err is UnsupportedEncodingError", func() {
+				request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out?path=%s", myVolume.Handle, "dest-path"), nil)
+				request.Header.Set("Accept-Encoding", "unsupported")
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+				Expect(recorder.Code).To(Equal(400))
+			})
+		})
+
+		It("returns 404 when volume is not found", func() {
+			request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out", "invalid-handle"), nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(404))
+		})
+	})
+
+	Describe("updating a volume", func() {
+		It("can have it's properties updated", func() {
+			body := &bytes.Buffer{}
+
+			err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+				Handle: "some-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+				Properties: baggageclaim.VolumeProperties{
+					"property-name": "property-val",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/volumes", body)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(201))
+
+			recorder = httptest.NewRecorder()
+			request, _ = http.NewRequest("GET", "/volumes?property-name=property-val", nil)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(200))
+
+			var volumes volume.Volumes
+			err = json.NewDecoder(recorder.Body).Decode(&volumes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(volumes).To(HaveLen(1))
+
+			err = json.NewEncoder(body).Encode(baggageclaim.PropertyRequest{
+				Value: "other-val",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder = httptest.NewRecorder()
+			request, _ = http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/properties/property-name", volumes[0].Handle), body)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			Expect(recorder.Body.String()).To(BeEmpty())
+
+			recorder = httptest.NewRecorder()
+			request, _ = http.NewRequest("GET", "/volumes?property-name=other-val", nil)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(200))
+
+			err = json.NewDecoder(recorder.Body).Decode(&volumes)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(volumes).To(HaveLen(1))
+		})
+
+	})
+
+	Describe("setting the ttl of a volume", func() {
+		var myVolume volume.Volume
+
+		JustBeforeEach(func() {
+			body := &bytes.Buffer{}
+
+			err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+				Handle: "some-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+				TTLInSeconds: 60,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/volumes", body)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(201))
+
+			err = json.NewDecoder(recorder.Body).Decode(&myVolume)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		getTTL := func() *uint {
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", fmt.Sprintf("/volumes/%s", myVolume.Handle), nil)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var vol volume.Volume
+			err := json.NewDecoder(recorder.Body).Decode(&vol)
+			Expect(err).NotTo(HaveOccurred())
+
+			return vol.TTL
+		}
+
+		setTTL := func(handle string, ttl uint) int {
+			body := &bytes.Buffer{}
+			err := json.NewEncoder(body).Encode(baggageclaim.TTLRequest{Value: ttl})
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/ttl", handle), body)
+			handler.ServeHTTP(recorder, request)
+
+			return recorder.Code
+		}
+
+		It("reports the remaining ttl given at creation", func() {
+			Expect(myVolume.TTL).ToNot(BeNil())
+			Expect(*getTTL()).To(BeNumerically("~", 60, 1))
+		})
+
+		It("can have its ttl changed", func() {
+			Expect(setTTL(myVolume.Handle, 3600)).To(Equal(http.StatusNoContent))
+			Expect(*getTTL()).To(BeNumerically("~", 3600, 1))
+		})
+
+		It("can have its ttl removed", func() {
+			Expect(setTTL(myVolume.Handle, 0)).To(Equal(http.StatusNoContent))
+			Expect(getTTL()).To(BeNil())
+		})
+
+		It("returns 404 when the volume does not exist", func() {
+			Expect(setTTL("bogus-handle", 10)).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("streaming volume events", func() {
+		var (
+			server *httptest.Server
+			cancel context.CancelFunc
+			events *json.Decoder
+		)
+
+		JustBeforeEach(func() {
+			server = httptest.NewServer(handler)
+
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+
+			request, err := http.NewRequest("GET", server.URL+"/events", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err := http.DefaultClient.Do(request.WithContext(ctx))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(response.Header.Get("Content-Type")).To(Equal("application/x-ndjson"))
+
+			events = json.NewDecoder(response.Body)
+		})
+
+		AfterEach(func() {
+			cancel()
+			server.Close()
+		})
+
+		nextEvent := func() volume.Event {
+			var event volume.Event
+			Expect(events.Decode(&event)).To(Succeed())
+			return event
+		}
+
+		It("emits events as volumes change", func() {
+			body := &bytes.Buffer{}
+			err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+				Handle: "some-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/volumes", body)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(201))
+
+			event := nextEvent()
+			Expect(event.Type).To(Equal(volume.EventCreated))
+			Expect(event.Handle).To(Equal("some-handle"))
+
+			event = nextEvent()
+			Expect(event.Type).To(Equal(volume.EventInitialized))
+			Expect(event.Handle).To(Equal("some-handle"))
+
+			err = json.NewEncoder(body).Encode(baggageclaim.PropertyRequest{Value: "some-value"})
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder = httptest.NewRecorder()
+			request, _ = http.NewRequest("PUT", "/volumes/some-handle/properties/some-property", body)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+			event = nextEvent()
+			Expect(event.Type).To(Equal(volume.EventPropertySet))
+			Expect(event.Property).To(Equal("some-property"))
+			Expect(event.Value).To(Equal("some-value"))
+
+			recorder = httptest.NewRecorder()
+			request, _ = http.NewRequest("DELETE", "/volumes/some-handle", nil)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+			event = nextEvent()
+			Expect(event.Type).To(Equal(volume.EventDestroyed))
+			Expect(event.Handle).To(Equal("some-handle"))
+		})
+	})
+
+	Describe("getting the usage of a volume", func() {
+		var myVolume volume.Volume
+
+		JustBeforeEach(func() {
+			body := &bytes.Buffer{}
+
+			err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+				Handle: "some-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+				Properties: baggageclaim.VolumeProperties{
+					"property-name": "property-val",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/volumes", body)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(201))
+
+			err = json.NewDecoder(recorder.Body).Decode(&myVolume)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(myVolume.Path, "some-file"), []byte("file-content"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports the bytes and inodes used by the volume", func() {
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", fmt.Sprintf("/volumes/%s/usage", myVolume.Handle), nil)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var usage volume.VolumeUsage
+			err := json.NewDecoder(recorder.Body).Decode(&usage)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(usage).To(Equal(volume.VolumeUsage{
+				ExclusiveBytes: allocatedBytes(myVolume.Path, filepath.Join(myVolume.Path, "some-file")),
+				SharedBytes:    0,
+				Inodes:         2,
+			}))
+		})
+
+		It("includes the usage when listing volumes with $include_usage", func() {
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", "/volumes?property-name=property-val&$include_usage=true", nil)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var volumes volume.Volumes
+			err := json.NewDecoder(recorder.Body).Decode(&volumes)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(volumes).To(HaveLen(1))
+			Expect(volumes[0].Usage).ToNot(BeNil())
+			Expect(volumes[0].Usage.ExclusiveBytes).To(Equal(allocatedBytes(myVolume.Path, filepath.Join(myVolume.Path, "some-file"))))
+		})
+
+		It("omits the usage when listing volumes without $include_usage", func() {
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", "/volumes", nil)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var volumes volume.Volumes
+			err := json.NewDecoder(recorder.Body).Decode(&volumes)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(volumes).To(HaveLen(1))
+			Expect(volumes[0].Usage).To(BeNil())
+		})
+
+		It("returns 404 when the volume does not exist", func() {
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", "/volumes/bogus-handle/usage", nil)
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("deduplicating volumes by digest", func() {
+		createVolume := func(handle string, strategy *json.RawMessage) *httptest.ResponseRecorder {
+			body := &bytes.Buffer{}
+			err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+				Handle:   handle,
+				Strategy: strategy,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, _ := http.NewRequest("POST", "/volumes", body)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder
+		}
+
+		getDigest := func(handle string) *httptest.ResponseRecorder {
+			request, _ := http.NewRequest("GET", fmt.Sprintf("/volumes/%s/digest", handle), nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder
+		}
+
+		serve := func(method string, path string, body io.Reader) *httptest.ResponseRecorder {
+			request, _ := http.NewRequest(method, path, body)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder
+		}
+
+		var digest string
+
+		JustBeforeEach(func() {
+			recorder := createVolume("original-volume", encStrategy(map[string]string{"type": "empty"}))
+			Expect(recorder.Code).To(Equal(201))
+
+			someFile := filepath.Join(volumeDir, "live", "original-volume", "volume", "some-file")
+			err := ioutil.WriteFile(someFile, []byte("some-content"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			// own it as though the unprivileged volume's container had written it
+			if runtime.GOOS == "linux" {
+				info, err := os.Lstat(someFile)
+				Expect(err).NotTo(HaveOccurred())
+
+				translator := uidgid.NewTranslator(uidgid.NewUnprivilegedMapper())
+				Expect(translator.TranslatePath(someFile, info, nil)).To(Succeed())
+			}
+
+			Expect(serve("PUT", "/volumes/original-volume/readonly", nil).Code).To(Equal(http.StatusNoContent))
+
+			recorder = getDigest("original-volume")
+			Expect(recorder.Code).To(Equal(200))
+
+			var response baggageclaim.DigestResponse
+			err = json.NewDecoder(recorder.Body).Decode(&response)
+			Expect(err).NotTo(HaveOccurred())
+
+			digest = response.Digest
+		})
+
+		It("does not record the digest in the volume's properties", func() {
+			Expect(digest).To(HavePrefix("sha256:"))
+
+			request, _ := http.NewRequest("GET", "/volumes/original-volume", nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			var vol volume.Volume
+			err := json.NewDecoder(recorder.Body).Decode(&vol)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vol.Properties).To(BeEmpty())
+		})
+
+		It("creates copies of the volume with that digest", func() {
+			recorder := createVolume("deduped-volume", baggageclaim.DedupeStrategy{Digest: digest}.Encode())
+			Expect(recorder.Code).To(Equal(201))
+
+			content, err := ioutil.ReadFile(filepath.Join(volumeDir, "live", "deduped-volume", "volume", "some-file"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+
+			recorder = getDigest("deduped-volume")
+			Expect(recorder.Body).To(MatchJSON(fmt.Sprintf(`{"digest":%q}`, digest)))
+		})
+
+		It("fails to create a volume for an unknown digest", func() {
+			recorder := createVolume("deduped-volume", baggageclaim.DedupeStrategy{Digest: "sha256:bogus"}.Encode())
+			Expect(recorder.Code).To(Equal(422))
+			Expect(recorder.Body).To(MatchJSON(`{"error":"no volume found with digest"}`))
+		})
+
+		It("does not deduplicate against writable volumes, whose contents may change", func() {
+			recorder := createVolume("writable-volume", encStrategy(map[string]string{"type": "empty"}))
+			Expect(recorder.Code).To(Equal(201))
+
+			err := ioutil.WriteFile(filepath.Join(volumeDir, "live", "writable-volume", "volume", "other-file"), []byte("other-content"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder = getDigest("writable-volume")
+			Expect(recorder.Code).To(Equal(200))
+
+			var response baggageclaim.DigestResponse
+			err = json.NewDecoder(recorder.Body).Decode(&response)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder = createVolume("deduped-volume", baggageclaim.DedupeStrategy{Digest: response.Digest}.Encode())
+			Expect(recorder.Code).To(Equal(422))
+		})
+
+		It("creates copies of imported volumes by their digest", func() {
+			importDir, err := ioutil.TempDir("", "import")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(importDir)
+
+			err = ioutil.WriteFile(filepath.Join(importDir, "imported-file"), []byte("imported-content"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := createVolume("imported-volume", encStrategy(map[string]string{"type": "import", "path": importDir}))
+			Expect(recorder.Code).To(Equal(201))
+
+			importedDigest, err := volume.TreeDigest(filepath.Join(volumeDir, "live", "imported-volume", "volume"))
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder = createVolume("deduped-volume", baggageclaim.DedupeStrategy{Digest: importedDigest}.Encode())
+			Expect(recorder.Code).To(Equal(201))
+
+			content, err := ioutil.ReadFile(filepath.Join(volumeDir, "live", "deduped-volume", "volume", "imported-file"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("imported-content"))
+		})
+
+		Context("when a volume has been streamed in to", func() {
+			var streamedDigest string
+
+			JustBeforeEach(func() {
+				recorder := createVolume("streamed-volume", encStrategy(map[string]string{"type": "empty"}))
+				Expect(recorder.Code).To(Equal(201))
+
+				tarBuffer := new(bytes.Buffer)
+				tarWriter := tar.NewWriter(tarBuffer)
+				err := tarWriter.WriteHeader(&tar.Header{
+					Name: "streamed-file",
+					Mode: 0644,
+					Size: int64(len("streamed-content")),
+				})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = tarWriter.Write([]byte("streamed-content"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tarWriter.Close()).To(Succeed())
+
+				request, _ := http.NewRequest("PUT", "/volumes/streamed-volume/stream-in?path=.", tarBuffer)
+				request.Header.Set("Content-Encoding", string(baggageclaim.IdentityEncoding))
+				recorder = httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+				Expect(recorder.Code).To(Equal(204))
+
+				streamedDigest, err = volume.TreeDigest(filepath.Join(volumeDir, "live", "streamed-volume", "volume"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("creates copies of it by its digest", func() {
+				recorder := createVolume("deduped-volume", baggageclaim.DedupeStrategy{Digest: streamedDigest}.Encode())
+				Expect(recorder.Code).To(Equal(201))
+
+				content, err := ioutil.ReadFile(filepath.Join(volumeDir, "live", "deduped-volume", "volume", "streamed-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("streamed-content"))
+			})
+
+			It("does not deduplicate against it once its contents have changed", func() {
+				err := ioutil.WriteFile(filepath.Join(volumeDir, "live", "streamed-volume", "volume", "other-file"), []byte("other-content"), 0644)
+				Expect(err).NotTo(HaveOccurred())
+
+				recorder := createVolume("deduped-volume", baggageclaim.DedupeStrategy{Digest: streamedDigest}.Encode())
+				Expect(recorder.Code).To(Equal(422))
+			})
+
+			It("finds it under its new handle once it is renamed", func() {
+				recorder := serve("POST", "/volumes/streamed-volume/rename", bytes.NewBufferString(`{"handle":"renamed-volume"}`))
+				Expect(recorder.Code).To(Equal(200))
+
+				recorder = createVolume("deduped-volume", baggageclaim.DedupeStrategy{Digest: streamedDigest}.Encode())
+				Expect(recorder.Code).To(Equal(201))
+
+				Expect(os.Readlink(filepath.Join(volumeDir, "live", "deduped-volume", "parent"))).To(Equal(filepath.Join(volumeDir, "live", "renamed-volume")))
+			})
+		})
+
+		It("does not trust a digest set as a property", func() {
+			recorder := createVolume("forged-volume", encStrategy(map[string]string{"type": "empty"}))
+			Expect(recorder.Code).To(Equal(201))
+
+			recorder = serve("PUT", "/volumes/forged-volume/properties/digest", bytes.NewBufferString(`{"value":"sha256:forged"}`))
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+			recorder = createVolume("deduped-volume", baggageclaim.DedupeStrategy{Digest: "sha256:forged"}.Encode())
+			Expect(recorder.Code).To(Equal(422))
+		})
+
+		It("returns 404 for the digest of a volume that does not exist", func() {
+			recorder := getDigest("bogus-volume")
+			Expect(recorder.Code).To(Equal(404))
+		})
+	})
+
+	Describe("getting orphaned volumes", func() {
+		var recorder *httptest.ResponseRecorder
+
+		JustBeforeEach(func() {
+			body := &bytes.Buffer{}
+			err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+				Handle:   "live-volume",
+				Strategy: encStrategy(map[string]string{"type": "empty"}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, _ := http.NewRequest("POST", "/volumes", body)
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(201))
+
+			err = os.MkdirAll(filepath.Join(volumeDir, "init", "init-orphan", "volume"), 0755)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = os.MkdirAll(filepath.Join(volumeDir, "dead", "dead-orphan", "volume"), 0755)
+			Expect(err).NotTo(HaveOccurred())
+
+			request, _ = http.NewRequest("GET", "/orphans", nil)
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+		})
+
+		It("reports the volumes left behind in the init and dead directories", func() {
+			Expect(recorder.Code).To(Equal(200))
+			Expect(recorder.Body).To(MatchJSON(`{"pending":2,"reclaimed":0}`))
+		})
+	})
+
+	Describe("checking volume integrity", func() {
+		JustBeforeEach(func() {
+			for _, handle := range []string{"healthy-volume", "corrupted-volume"} {
+				body := &bytes.Buffer{}
+				err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+					Handle:   handle,
+					Strategy: encStrategy(map[string]string{"type": "empty"}),
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, _ := http.NewRequest("POST", "/volumes", body)
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+				Expect(recorder.Code).To(Equal(201))
+			}
+
+			err := ioutil.WriteFile(filepath.Join(volumeDir, "live", "corrupted-volume", "properties.json"), []byte("{"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("lists the corrupted volumes", func() {
+			request, _ := http.NewRequest("GET", "/volumes/corrupted", nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(200))
+			Expect(recorder.Body).To(MatchJSON(`["corrupted-volume"]`))
+		})
+
+		It("reports the problems found by fsck", func() {
+			request, _ := http.NewRequest("POST", "/fsck", http.NoBody)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(200))
+
+			var report volume.FsckReport
+			err := json.NewDecoder(recorder.Body).Decode(&report)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(report.Checked).To(Equal(2))
+			Expect(report.Corrupted).To(HaveLen(1))
+			Expect(report.Corrupted[0].Handle).To(Equal("corrupted-volume"))
+			Expect(report.Corrupted[0].Problems).To(ConsistOf(ContainSubstring("malformed properties.json")))
+			Expect(report.Corrupted[0].Quarantined).To(BeFalse())
+
+			Expect(filepath.Join(volumeDir, "live", "corrupted-volume")).To(BeADirectory())
+		})
+
+		It("quarantines corrupted volumes when asked to", func() {
+			request, _ := http.NewRequest("POST", "/fsck", bytes.NewBufferString(`{"quarantine":true}`))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(200))
+
+			var report volume.FsckReport
+			err := json.NewDecoder(recorder.Body).Decode(&report)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(report.Corrupted).To(HaveLen(1))
+			Expect(report.Corrupted[0].Quarantined).To(BeTrue())
+
+			Expect(filepath.Join(volumeDir, "live", "corrupted-volume")).ToNot(BeADirectory())
+			Expect(filepath.Join(volumeDir, "quarantine", "corrupted-volume")).To(BeADirectory())
+
+			request, _ = http.NewRequest("GET", "/volumes/corrupted", nil)
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Body).To(MatchJSON(`[]`))
+		})
+
+		Context("once a volume is quarantined", func() {
+			JustBeforeEach(func() {
+				request, _ := http.NewRequest("POST", "/fsck", bytes.NewBufferString(`{"quarantine":true}`))
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+				Expect(recorder.Code).To(Equal(200))
+			})
+
+			It("lists it as quarantined", func() {
+				request, _ := http.NewRequest("GET", "/quarantined-volumes", nil)
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+
+				Expect(recorder.Code).To(Equal(200))
+				Expect(recorder.Body).To(MatchJSON(`["corrupted-volume"]`))
+			})
+
+			It("keeps its handle from being reused until it is destroyed", func() {
+				create := func() int {
+					body := &bytes.Buffer{}
+					err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+						Handle:   "corrupted-volume",
+						Strategy: encStrategy(map[string]string{"type": "empty"}),
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					request, _ := http.NewRequest("POST", "/volumes", body)
+					recorder := httptest.NewRecorder()
+					handler.ServeHTTP(recorder, request)
+
+					return recorder.Code
+				}
+
+				Expect(create()).ToNot(Equal(http.StatusCreated))
+
+				request, _ := http.NewRequest("DELETE", "/quarantined-volumes/corrupted-volume", nil)
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+				Expect(filepath.Join(volumeDir, "quarantine", "corrupted-volume")).ToNot(BeADirectory())
+
+				Expect(create()).To(Equal(http.StatusCreated))
+			})
+
+			It("returns 404 when destroying a volume that is not quarantined", func() {
+				request, _ := http.NewRequest("DELETE", "/quarantined-volumes/healthy-volume", nil)
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+
+				Expect(recorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		It("rejects a malformed request", func() {
+			request, _ := http.NewRequest("POST", "/fsck", bytes.NewBufferString(`{`))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(400))
+		})
+	})
+
+	Describe("snapshotting a volume", func() {
+		var dataPath string
+
+		serve := func(method string, path string, body io.Reader) *httptest.ResponseRecorder {
+			request, err := http.NewRequest(method, path, body)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder
+		}
+
+		createVolume := func(request baggageclaim.VolumeRequest) {
+			body := &bytes.Buffer{}
+			err := json.NewEncoder(body).Encode(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(serve("POST", "/volumes", body).Code).To(Equal(201))
+		}
+
+		createSnapshot := func(handle string) *httptest.ResponseRecorder {
+			body := &bytes.Buffer{}
+			err := json.NewEncoder(body).Encode(baggageclaim.SnapshotRequest{Handle: handle})
+			Expect(err).NotTo(HaveOccurred())
+
+			return serve("POST", "/volumes/some-handle/snapshots", body)
+		}
+
+		JustBeforeEach(func() {
+			createVolume(baggageclaim.VolumeRequest{
+				Handle: "some-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+				Privileged: true,
+			})
+
+			dataPath = filepath.Join(volumeDir, "live", "some-handle", "volume")
+
+			err := ioutil.WriteFile(filepath.Join(dataPath, "some-file"), []byte("before"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("can restore the volume to a snapshot", func() {
+			recorder := createSnapshot("some-snapshot")
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+
+			var snapshot baggageclaim.Snapshot
+			err := json.NewDecoder(recorder.Body).Decode(&snapshot)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshot.Handle).To(Equal("some-snapshot"))
+			Expect(snapshot.CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+			err = ioutil.WriteFile(filepath.Join(dataPath, "some-file"), []byte("after"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder = serve("POST", "/volumes/some-handle/restore/some-snapshot", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+			Expect(ioutil.ReadFile(filepath.Join(dataPath, "some-file"))).To(Equal([]byte("before")))
+		})
+
+		It("lists the snapshots with the volume", func() {
+			Expect(createSnapshot("some-snapshot").Code).To(Equal(http.StatusCreated))
+
+			recorder := serve("GET", "/volumes/some-handle", nil)
+			Expect(recorder.Code).To(Equal(200))
+
+			var response baggageclaim.VolumeResponse
+			err := json.NewDecoder(recorder.Body).Decode(&response)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response.Snapshots).To(HaveLen(1))
+			Expect(response.Snapshots[0].Handle).To(Equal("some-snapshot"))
+		})
+
+		It("generates a handle when none is given", func() {
+			recorder := serve("POST", "/volumes/some-handle/snapshots", &bytes.Buffer{})
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+
+			var snapshot baggageclaim.Snapshot
+			err := json.NewDecoder(recorder.Body).Decode(&snapshot)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshot.Handle).NotTo(BeEmpty())
+		})
+
+		It("returns 409 when the snapshot already exists", func() {
+			Expect(createSnapshot("some-snapshot").Code).To(Equal(http.StatusCreated))
+
+			recorder := createSnapshot("some-snapshot")
+			Expect(recorder.Code).To(Equal(http.StatusConflict))
+			Expect(recorder.Body).To(MatchJSON(`{"error": "snapshot already exists"}`))
+		})
+
+		It("returns 400 when the snapshot handle is not a plain name", func() {
+			Expect(createSnapshot("../some-snapshot").Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("returns 404 when restoring a snapshot that does not exist", func() {
+			recorder := serve("POST", "/volumes/some-handle/restore/bogus", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+			Expect(recorder.Body).To(MatchJSON(`{"error": "snapshot not found"}`))
+		})
 
-			BeforeEach(func() {
-				tarDir = filepath.Join(tempDir, "tar-dir")
+		It("returns 404 when snapshotting a volume that does not exist", func() {
+			recorder := serve("POST", "/volumes/bogus/snapshots", &bytes.Buffer{})
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
 
-				err := os.MkdirAll(filepath.Join(tarDir, "sub"), os.ModePerm)
-				Expect(err).NotTo(HaveOccurred())
+		It("returns 409 when restoring a volume with children", func() {
+			Expect(createSnapshot("some-snapshot").Code).To(Equal(http.StatusCreated))
 
-				err = ioutil.WriteFile(filepath.Join(tarDir, "sub", "some-file"), []byte("some-file-content"), os.ModePerm)
-				Expect(err).NotTo(HaveOccurred())
+			createVolume(baggageclaim.VolumeRequest{
+				Handle: "child-handle",
+				Strategy: encStrategy(map[string]string{
+					"type":   "cow",
+					"volume": "some-handle",
+				}),
+				Privileged: true,
+			})
 
-				err = ioutil.WriteFile(filepath.Join(tarDir, "other-file"), []byte("other-file-content"), os.ModePerm)
-				Expect(err).NotTo(HaveOccurred())
+			recorder := serve("POST", "/volumes/some-handle/restore/some-snapshot", nil)
+			Expect(recorder.Code).To(Equal(http.StatusConflict))
+			Expect(recorder.Body).To(MatchJSON(`{"error": "volume has children"}`))
+		})
 
-				tarBuffer = new(bytes.Buffer)
-			})
+		It("can destroy a snapshot", func() {
+			Expect(createSnapshot("some-snapshot").Code).To(Equal(http.StatusCreated))
 
-			JustBeforeEach(func() {
-				streamInRequest, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-in?path=%s", myVolume.Handle, "dest-path"), tarBuffer)
-				streamInRequest.Header.Set("Content-Encoding", encoding)
-				streamInRecorder := httptest.NewRecorder()
-				handler.ServeHTTP(streamInRecorder, streamInRequest)
-				Expect(streamInRecorder.Code).To(Equal(204))
+			recorder := serve("DELETE", "/volumes/some-handle/snapshots/some-snapshot", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
 
-				tarContentsPath := filepath.Join(volumeDir, "live", myVolume.Handle, "volume", "dest-path")
-				Expect(tarContentsPath).To(BeADirectory())
-			})
+			recorder = serve("DELETE", "/volumes/some-handle/snapshots/some-snapshot", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
 
-			Context("when using gzip encoding", func() {
-				BeforeEach(func() {
-					err := tgzfs.Compress(tarBuffer, tarDir, ".")
-					Expect(err).NotTo(HaveOccurred())
-					encoding = string(baggageclaim.GzipEncoding)
-				})
+	Describe("making a volume read-only", func() {
+		serve := func(method string, path string, body io.Reader) *httptest.ResponseRecorder {
+			request, err := http.NewRequest(method, path, body)
+			Expect(err).NotTo(HaveOccurred())
 
-				It("creates a tar", func() {
-					request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out?path=%s", myVolume.Handle, "dest-path"), nil)
-					request.Header.Set("Accept-Encoding", string(baggageclaim.GzipEncoding))
-					recorder := httptest.NewRecorder()
-					handler.ServeHTTP(recorder, request)
-					Expect(recorder.Code).To(Equal(200))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder
+		}
 
-					unpackedDir := filepath.Join(tempDir, "unpacked-dir")
-					err := os.MkdirAll(unpackedDir, os.ModePerm)
-					Expect(err).NotTo(HaveOccurred())
-					defer os.RemoveAll(unpackedDir)
+		encode := func(v interface{}) io.Reader {
+			body := &bytes.Buffer{}
+			err := json.NewEncoder(body).Encode(v)
+			Expect(err).NotTo(HaveOccurred())
+			return body
+		}
 
-					err = tgzfs.Extract(recorder.Body, unpackedDir)
-					Expect(err).NotTo(HaveOccurred())
+		isReadOnly := func(handle string) bool {
+			recorder := serve("GET", "/volumes/"+handle, nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
 
-					fileInfo, err := os.Stat(filepath.Join(unpackedDir, "other-file"))
-					Expect(err).NotTo(HaveOccurred())
-					Expect(fileInfo.IsDir()).To(BeFalse())
-					Expect(fileInfo.Size()).To(Equal(int64(len("other-file-content"))))
+			var response baggageclaim.VolumeResponse
+			err := json.NewDecoder(recorder.Body).Decode(&response)
+			Expect(err).NotTo(HaveOccurred())
 
-					contents, err := ioutil.ReadFile(filepath.Join(unpackedDir, "other-file"))
-					Expect(err).NotTo(HaveOccurred())
-					Expect(string(contents)).To(Equal("other-file-content"))
+			return response.ReadOnly
+		}
 
-					dirInfo, err := os.Stat(filepath.Join(unpackedDir, "sub"))
-					Expect(err).NotTo(HaveOccurred())
-					Expect(dirInfo.IsDir()).To(BeTrue())
+		JustBeforeEach(func() {
+			recorder := serve("POST", "/volumes", encode(baggageclaim.VolumeRequest{
+				Handle: "some-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+			}))
+			Expect(recorder.Code).To(Equal(201))
+		})
 
-					fileInfo, err = os.Stat(filepath.Join(unpackedDir, "sub/some-file"))
-					Expect(err).NotTo(HaveOccurred())
-					Expect(fileInfo.IsDir()).To(BeFalse())
-					Expect(fileInfo.Size()).To(Equal(int64(len("some-file-content"))))
+		It("seals the volume against changes to its contents", func() {
+			Expect(isReadOnly("some-handle")).To(BeFalse())
 
-					contents, err = ioutil.ReadFile(filepath.Join(unpackedDir, "sub/some-file"))
-					Expect(err).NotTo(HaveOccurred())
-					Expect(string(contents)).To(Equal("some-file-content"))
-				})
-			})
+			recorder := serve("PUT", "/volumes/some-handle/readonly", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			Expect(recorder.Body.String()).To(BeEmpty())
 
-			Context("when using zstd encoding", func() {
-				BeforeEach(func() {
-					zstdWriter, err := zstd.NewWriter(tarBuffer)
-					Expect(err).ToNot(HaveOccurred())
-					err = tarfs.Compress(zstdWriter, tarDir, ".")
-					Expect(err).NotTo(HaveOccurred())
-					zstdWriter.Close()
-					encoding = string(baggageclaim.ZstdEncoding)
-				})
+			Expect(isReadOnly("some-handle")).To(BeTrue())
 
-				It("creates a tar", func() {
-					request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out?path=%s", myVolume.Handle, "dest-path"), nil)
-					request.Header.Set("Accept-Encoding", string(baggageclaim.ZstdEncoding))
-					recorder := httptest.NewRecorder()
-					handler.ServeHTTP(recorder, request)
-					Expect(recorder.Code).To(Equal(200))
+			recorder = serve("PUT", "/volumes/some-handle/stream-in", &bytes.Buffer{})
+			Expect(recorder.Code).To(Equal(http.StatusConflict))
+			Expect(recorder.Body).To(MatchJSON(`{"error": "volume is read-only"}`))
 
-					unpackedDir := filepath.Join(tempDir, "unpacked-dir")
-					err := os.MkdirAll(unpackedDir, os.ModePerm)
-					Expect(err).NotTo(HaveOccurred())
-					defer os.RemoveAll(unpackedDir)
+			recorder = serve("PUT", "/volumes/some-handle/privileged", encode(baggageclaim.PrivilegedRequest{Value: true}))
+			Expect(recorder.Code).To(Equal(http.StatusConflict))
+			Expect(recorder.Body).To(MatchJSON(`{"error": "volume is read-only"}`))
+		})
 
-					tarByteReader, err := zstd.NewReader(recorder.Body)
-					Expect(err).NotTo(HaveOccurred())
-					err = tarfs.Extract(tarByteReader, unpackedDir)
-					Expect(err).NotTo(HaveOccurred())
-					tarByteReader.Close()
-					fileInfo, err := os.Stat(filepath.Join(unpackedDir, "other-file"))
-					Expect(err).NotTo(HaveOccurred())
-					Expect(fileInfo.IsDir()).To(BeFalse())
-					Expect(fileInfo.Size()).To(Equal(int64(len("other-file-content"))))
+		It("still allows its properties to be set", func() {
+			Expect(serve("PUT", "/volumes/some-handle/readonly", nil).Code).To(Equal(http.StatusNoContent))
 
-					contents, err := ioutil.ReadFile(filepath.Join(unpackedDir, "other-file"))
-					Expect(err).NotTo(HaveOccurred())
-					Expect(string(contents)).To(Equal("other-file-content"))
+			recorder := serve("PUT", "/volumes/some-handle/properties/some-property", encode(baggageclaim.PropertyRequest{Value: "some-value"}))
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+		})
 
-					dirInfo, err := os.Stat(filepath.Join(unpackedDir, "sub"))
-					Expect(err).NotTo(HaveOccurred())
-					Expect(dirInfo.IsDir()).To(BeTrue())
+		It("can create volumes that are read-only from the start", func() {
+			recorder := serve("POST", "/volumes", encode(baggageclaim.VolumeRequest{
+				Handle: "sealed-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+				ReadOnly: true,
+			}))
+			Expect(recorder.Code).To(Equal(201))
 
-					fileInfo, err = os.Stat(filepath.Join(unpackedDir, "sub/some-file"))
-					Expect(err).NotTo(HaveOccurred())
-					Expect(fileInfo.IsDir()).To(BeFalse())
-					Expect(fileInfo.Size()).To(Equal(int64(len("some-file-content"))))
+			var response baggageclaim.VolumeResponse
+			err := json.NewDecoder(recorder.Body).Decode(&response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.ReadOnly).To(BeTrue())
 
-					contents, err = ioutil.ReadFile(filepath.Join(unpackedDir, "sub/some-file"))
-					Expect(err).NotTo(HaveOccurred())
-					Expect(string(contents)).To(Equal("some-file-content"))
-				})
-			})
+			Expect(isReadOnly("sealed-handle")).To(BeTrue())
 		})
 
-		Context("when using an unsupported encoding", func() {
-			It("returns 400 when err is UnsupportedEncodingError", func() {
-				request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out?path=%s", myVolume.Handle, "dest-path"), nil)
-				request.Header.Set("Accept-Encoding", "unsupported")
-				recorder := httptest.NewRecorder()
-				handler.ServeHTTP(recorder, request)
-				Expect(recorder.Code).To(Equal(400))
-			})
+		It("returns 404 when the volume does not exist", func() {
+			recorder := serve("PUT", "/volumes/bogus/readonly", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
 		})
+	})
+
+	Describe("renaming a volume", func() {
+		serve := func(method string, path string, body io.Reader) *httptest.ResponseRecorder {
+			request, err := http.NewRequest(method, path, body)
+			Expect(err).NotTo(HaveOccurred())
 
-		It("returns 404 when volume is not found", func() {
-			request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-out", "invalid-handle"), nil)
 			recorder := httptest.NewRecorder()
 			handler.ServeHTTP(recorder, request)
-			Expect(recorder.Code).To(Equal(404))
-		})
-	})
+			return recorder
+		}
 
-	Describe("updating a volume", func() {
-		It("can have it's properties updated", func() {
+		encode := func(v interface{}) io.Reader {
 			body := &bytes.Buffer{}
+			err := json.NewEncoder(body).Encode(v)
+			Expect(err).NotTo(HaveOccurred())
+			return body
+		}
 
-			err := json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+		createVolume := func(request baggageclaim.VolumeRequest) {
+			Expect(serve("POST", "/volumes", encode(request)).Code).To(Equal(201))
+		}
+
+		rename := func(handle string, newHandle string) *httptest.ResponseRecorder {
+			return serve("POST", "/volumes/"+handle+"/rename", encode(baggageclaim.RenameRequest{Handle: newHandle}))
+		}
+
+		JustBeforeEach(func() {
+			createVolume(baggageclaim.VolumeRequest{
 				Handle: "some-handle",
 				Strategy: encStrategy(map[string]string{
 					"type": "empty",
 				}),
 				Properties: baggageclaim.VolumeProperties{
-					"property-name": "property-val",
+					"some": "property",
 				},
 			})
-			Expect(err).NotTo(HaveOccurred())
 
-			recorder := httptest.NewRecorder()
-			request, _ := http.NewRequest("POST", "/volumes", body)
-			handler.ServeHTTP(recorder, request)
-			Expect(recorder.Code).To(Equal(201))
+			err := ioutil.WriteFile(filepath.Join(volumeDir, "live", "some-handle", "volume", "some-file"), []byte("some-content"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+		})
 
-			recorder = httptest.NewRecorder()
-			request, _ = http.NewRequest("GET", "/volumes?property-name=property-val", nil)
-			handler.ServeHTTP(recorder, request)
-			Expect(recorder.Code).To(Equal(200))
+		It("moves the volume to the new handle", func() {
+			recorder := rename("some-handle", "new-handle")
+			Expect(recorder.Code).To(Equal(http.StatusOK))
 
-			var volumes volume.Volumes
-			err = json.NewDecoder(recorder.Body).Decode(&volumes)
+			var response baggageclaim.VolumeResponse
+			err := json.NewDecoder(recorder.Body).Decode(&response)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(volumes).To(HaveLen(1))
 
-			err = json.NewEncoder(body).Encode(baggageclaim.PropertyRequest{
-				Value: "other-val",
+			Expect(response.Handle).To(Equal("new-handle"))
+			Expect(response.Path).To(Equal(filepath.Join(volumeDir, "live", "new-handle", "volume")))
+			Expect(response.Properties).To(Equal(baggageclaim.VolumeProperties{"some": "property"}))
+
+			Expect(ioutil.ReadFile(filepath.Join(response.Path, "some-file"))).To(Equal([]byte("some-content")))
+
+			Expect(serve("GET", "/volumes/some-handle", nil).Code).To(Equal(http.StatusNotFound))
+			Expect(serve("GET", "/volumes/new-handle", nil).Code).To(Equal(http.StatusOK))
+		})
+
+		It("keeps the volume's children linked to it", func() {
+			createVolume(baggageclaim.VolumeRequest{
+				Handle: "child-handle",
+				Strategy: encStrategy(map[string]string{
+					"type":   "cow",
+					"volume": "some-handle",
+				}),
 			})
-			Expect(err).NotTo(HaveOccurred())
 
-			recorder = httptest.NewRecorder()
-			request, _ = http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/properties/property-name", volumes[0].Handle), body)
-			handler.ServeHTTP(recorder, request)
-			Expect(recorder.Code).To(Equal(http.StatusNoContent))
-			Expect(recorder.Body.String()).To(BeEmpty())
+			before := serve("GET", "/volumes/child-handle/diff", nil)
+			Expect(before.Code).To(Equal(http.StatusOK))
 
-			recorder = httptest.NewRecorder()
-			request, _ = http.NewRequest("GET", "/volumes?property-name=other-val", nil)
-			handler.ServeHTTP(recorder, request)
-			Expect(recorder.Code).To(Equal(200))
+			Expect(rename("some-handle", "new-handle").Code).To(Equal(http.StatusOK))
 
-			err = json.NewDecoder(recorder.Body).Decode(&volumes)
-			Expect(err).NotTo(HaveOccurred())
+			// a child that lost its parent would have nothing to diff against
+			after := serve("GET", "/volumes/child-handle/diff", nil)
+			Expect(after.Code).To(Equal(http.StatusOK))
+			Expect(after.Body).To(MatchJSON(before.Body.String()))
+		})
 
-			Expect(volumes).To(HaveLen(1))
+		It("returns 400 when the new handle is taken by a route", func() {
+			recorder := rename("some-handle", "corrupted")
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(serve("GET", "/volumes/some-handle", nil).Code).To(Equal(http.StatusOK))
+		})
+
+		It("returns 409 when the new handle is taken", func() {
+			createVolume(baggageclaim.VolumeRequest{
+				Handle: "other-handle",
+				Strategy: encStrategy(map[string]string{
+					"type": "empty",
+				}),
+			})
+
+			recorder := rename("some-handle", "other-handle")
+			Expect(recorder.Code).To(Equal(http.StatusConflict))
+			Expect(recorder.Body).To(MatchJSON(`{"error": "volume already exists"}`))
+
+			Expect(serve("GET", "/volumes/some-handle", nil).Code).To(Equal(http.StatusOK))
+		})
+
+		It("returns 400 when the new handle is not a plain name", func() {
+			Expect(rename("some-handle", "../escape").Code).To(Equal(http.StatusBadRequest))
 		})
 
+		It("returns 404 when the volume does not exist", func() {
+			Expect(rename("bogus", "new-handle").Code).To(Equal(http.StatusNotFound))
+		})
 	})
 
 	Describe("destroying a volume", func() {
@@ -822,6 +2731,23 @@ var _ = Describe("Volume Server", func() {
 			})
 		})
 
+		Context("when the handle is taken by a route", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				_ = json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+					Handle: "corrupted",
+					Strategy: encStrategy(map[string]string{
+						"type": "empty",
+					}),
+				})
+			})
+
+			It("returns 400 without creating the volume", func() {
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(filepath.Join(volumeDir, "live", "corrupted")).ToNot(BeADirectory())
+			})
+		})
+
 		Context("when there are no properties given", func() {
 			BeforeEach(func() {
 				body = &bytes.Buffer{}
@@ -855,6 +2781,29 @@ var _ = Describe("Volume Server", func() {
 				})
 			})
 
+			Context("when a quota is requested but the driver cannot enforce it", func() {
+				BeforeEach(func() {
+					body = &bytes.Buffer{}
+					_ = json.NewEncoder(body).Encode(baggageclaim.VolumeRequest{
+						Strategy: encStrategy(map[string]string{
+							"type": "empty",
+						}),
+						QuotaBytes: 1024,
+					})
+				})
+
+				It("returns a 422 Unprocessable Entity response", func() {
+					Expect(recorder.Code).To(Equal(422))
+				})
+
+				It("does not create a volume", func() {
+					getRecorder := httptest.NewRecorder()
+					getReq, _ := http.NewRequest("GET", "/volumes", nil)
+					handler.ServeHTTP(getRecorder, getReq)
+					Expect(getRecorder.Body).To(MatchJSON("[]"))
+				})
+			})
+
 			Context("when invalid JSON is submitted", func() {
 				BeforeEach(func() {
 					body = bytes.NewBufferString("{{{{{{")
@@ -1142,6 +3091,24 @@ var _ = Describe("Volume Server", func() {
 			Expect(responseError.Message).To(Equal("no such file or directory"))
 		})
 
+		It("returns 502 with the peer's error when the peer rejects the stream", func() {
+			filePath := filepath.Join(volumeDir, "live", myVolume.Handle, "volume", "some-file")
+			err := ioutil.WriteFile(filePath, []byte("some-file-content"), os.ModePerm)
+			Expect(err).ToNot(HaveOccurred())
+
+			streamInP2pURL := fmt.Sprintf("%s/volumes/%s/stream-in?path=dest-path", otherWorker.URL, "bogus-handle")
+			request, _ := http.NewRequest("PUT", fmt.Sprintf("/volumes/%s/stream-p2p-out?path=%s&streamInURL=%s&encoding=gzip", myVolume.Handle, "some-file", streamInP2pURL), nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusBadGateway))
+
+			var responseError *api.ErrorResponse
+			err = json.NewDecoder(recorder.Body).Decode(&responseError)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(responseError.Message).To(HavePrefix("p2p streaming error 404 after "))
+			Expect(responseError.Message).To(ContainSubstring(api.ErrStreamInFailed.Error()))
+		})
+
 		Context("when streaming a file", func() {
 			JustBeforeEach(func() {
 				// Create a file in the volume.
@@ -1240,3 +3207,11 @@ func encStrategy(strategy map[string]string) *json.RawMessage {
 
 	return &msg
 }
+
+// interruptedReader fails as a request body does when the client goes away
+// partway through sending it.
+type interruptedReader struct{}
+
+func (interruptedReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
@@ -1,16 +1,23 @@
 package baggageclaimcmd
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"regexp"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/baggageclaim/api"
+	"github.com/concourse/baggageclaim/client"
+	"github.com/concourse/baggageclaim/metrics"
 	"github.com/concourse/baggageclaim/uidgid"
 	"github.com/concourse/baggageclaim/volume"
 	"github.com/concourse/flag"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/tedsuo/ifrit"
 	"github.com/tedsuo/ifrit/grouper"
 	"github.com/tedsuo/ifrit/http_server"
@@ -23,12 +30,29 @@ type BaggageclaimCommand struct {
 	BindIP   flag.IP `long:"bind-ip"   default:"127.0.0.1" description:"IP address on which to listen for API traffic."`
 	BindPort uint16  `long:"bind-port" default:"7788"      description:"Port on which to listen for API traffic."`
 
-	DebugBindIP   flag.IP `long:"debug-bind-ip"   default:"127.0.0.1" description:"IP address on which to listen for the pprof debugger endpoints."`
-	DebugBindPort uint16  `long:"debug-bind-port" default:"7787"      description:"Port on which to listen for the pprof debugger endpoints."`
+	BindSocket      string `long:"bind-socket"       description:"Path of a Unix socket on which to listen for API traffic from the same host, in addition to TCP. It is served without TLS."`
+	BindSocketMode  uint32 `long:"bind-socket-mode"  default:"0660" base:"8" description:"Permissions to give the Unix socket."`
+	BindSocketOwner string `long:"bind-socket-owner" description:"User, by name or ID, to own the Unix socket."`
+	BindSocketGroup string `long:"bind-socket-group" description:"Group, by name or ID, to own the Unix socket."`
+
+	DisableTCP bool `long:"disable-tcp" description:"Only listen for API traffic on the Unix socket. Peers will not be able to stream to this server."`
+
+	TLSCert              flag.File `long:"tls-cert"                description:"File containing a certificate with which to serve API and p2p traffic over TLS. It is also presented to peers when streaming to them."`
+	TLSKey               flag.File `long:"tls-key"                 description:"File containing the private key for the TLS certificate."`
+	TLSCACert            flag.File `long:"tls-ca-cert"             description:"File containing CA certificates with which to verify peers when streaming to them, and client certificates if they are required."`
+	TLSVerifyClientCerts bool      `long:"tls-verify-client-certs" description:"Require clients and peers to present a certificate signed by the TLS CA."`
+
+	AuthTokensFile flag.File `long:"auth-tokens-file" description:"File containing a JSON list of the bearer tokens that API requests must present, each with the scopes it grants (read, stream, create, destroy) and optionally the properties of the only volumes it may touch. Peers stream in without a token, so p2p streaming also needs --p2p-secret."`
+
+	DebugBindIP   flag.IP `long:"debug-bind-ip"   default:"127.0.0.1" description:"IP address on which to listen for the pprof debugger and metrics endpoints."`
+	DebugBindPort uint16  `long:"debug-bind-port" default:"7787"      description:"Port on which to listen for the pprof debugger and metrics endpoints."`
 
 	P2pInterfaceNamePattern string `long:"p2p-interface-name-pattern" default:"eth0" description:"Regular expression to match a network interface for p2p streaming"`
 	P2pInterfaceFamily int `long:"p2p-interface-family" default:"4" choice:"4" choice:"6" description:"4 for IPv4 and 6 for IPv6"`
 
+	P2pSecret string        `long:"p2p-secret" description:"Secret with which to sign the stream-in URLs handed to peers. Streams into signed URLs are refused if the signature does not match or the URL has expired, and streams into unsigned URLs are refused unless they present a token, so it requires --auth-tokens-file."`
+	P2pUrlTTL time.Duration `long:"p2p-url-ttl" default:"10m" description:"How long a signed stream-in URL may be used for."`
+
 	VolumesDir flag.Dir `long:"volumes" required:"true" description:"Directory in which to place volume data."`
 
 	Driver string `long:"driver" default:"detect" choice:"detect" choice:"naive" choice:"btrfs" choice:"overlay" description:"Driver to use for managing volumes."`
@@ -36,9 +60,17 @@ type BaggageclaimCommand struct {
 	BtrfsBin string `long:"btrfs-bin" default:"btrfs" description:"Path to btrfs binary"`
 	MkfsBin  string `long:"mkfs-bin" default:"mkfs.btrfs" description:"Path to mkfs.btrfs binary"`
 
+	BtrfsEnableQuotas bool `long:"btrfs-enable-quotas" description:"Enable quotas on the btrfs filesystem holding the volumes, to account for data shared between volumes and snapshots and to enforce volume quotas. Quotas are enabled for the whole filesystem, which can slow it down."`
+
 	OverlaysDir string `long:"overlays-dir" description:"Path to directory in which to store overlay data"`
 
 	DisableUserNamespaces bool `long:"disable-user-namespaces" description:"Disable remapping of user/group IDs in unprivileged volumes."`
+
+	ReapInterval             time.Duration `long:"reap-interval"              default:"10s" description:"Interval on which to destroy volumes whose TTL has expired."`
+	OrphanCollectionInterval time.Duration `long:"orphan-collection-interval" default:"5m"  description:"Interval on which to destroy volumes orphaned in the init and dead directories, e.g. by a crash. They are also collected at startup."`
+
+	UploadTTL     time.Duration `long:"upload-ttl"      default:"1h" description:"How long a resumable upload may go without receiving anything before what it has received is discarded. Set to 0 to keep uploads until their volume is destroyed."`
+	MaxUploadSize uint64        `long:"max-upload-size" default:"0"  description:"Number of bytes a resumable upload may spool before it is refused, or 0 for no limit. Uploads are also limited by the volume's quota."`
 }
 
 func (cmd *BaggageclaimCommand) Execute(args []string) error {
@@ -55,6 +87,20 @@ func (cmd *BaggageclaimCommand) Runner(args []string) (ifrit.Runner, error) {
 
 	listenAddr := fmt.Sprintf("%s:%d", cmd.BindIP.IP, cmd.BindPort)
 
+	if cmd.DisableTCP && cmd.BindSocket == "" {
+		return nil, errors.New("--disable-tcp requires --bind-socket")
+	}
+
+	if cmd.P2pSecret != "" && cmd.AuthTokensFile == "" {
+		return nil, errors.New("--p2p-secret requires --auth-tokens-file")
+	}
+
+	serverTLSConfig, p2pClient, err := cmd.tlsConfig()
+	if err != nil {
+		logger.Error("failed-to-configure-tls", err)
+		return nil, err
+	}
+
 	var privilegedNamespacer, unprivilegedNamespacer uidgid.Namespacer
 
 	if !cmd.DisableUserNamespaces && uidgid.Supported() {
@@ -97,13 +143,42 @@ func (cmd *BaggageclaimCommand) Runner(args []string) (ifrit.Runner, error) {
 		locker,
 		privilegedNamespacer,
 		unprivilegedNamespacer,
+		p2pClient,
+		int64(cmd.MaxUploadSize),
 	)
 
+	err = prometheus.Register(metrics.NewVolumeStateCollector(func() (map[string]int, error) {
+		return volume.CountVolumes(cmd.VolumesDir.Path())
+	}))
+	if err != nil {
+		logger.Error("failed-to-register-volume-metrics", err)
+		return nil, err
+	}
+
+	debugHandler := http.NewServeMux()
+	debugHandler.Handle("/metrics", promhttp.Handler())
+	debugHandler.Handle("/", http.DefaultServeMux)
+
 	re, err := regexp.Compile(cmd.P2pInterfaceNamePattern)
 	if err != nil {
 		logger.Error("failed-to-compile-p2p-interface-name-pattern", err)
 		return nil, err
 	}
+
+	var streamInSigner *api.StreamInSigner
+	if cmd.P2pSecret != "" {
+		streamInSigner = api.NewStreamInSigner([]byte(cmd.P2pSecret), cmd.P2pUrlTTL)
+	}
+
+	var tokens []api.Token
+	if cmd.AuthTokensFile != "" {
+		tokens, err = api.LoadTokens(cmd.AuthTokensFile.Path())
+		if err != nil {
+			logger.Error("failed-to-load-auth-tokens", err)
+			return nil, err
+		}
+	}
+
 	apiHandler, err := api.NewHandler(
 		logger.Session("api"),
 		volume.NewStrategerizer(),
@@ -111,23 +186,65 @@ func (cmd *BaggageclaimCommand) Runner(args []string) (ifrit.Runner, error) {
 		re,
 		cmd.P2pInterfaceFamily,
 		cmd.BindPort,
+		serverTLSConfig != nil,
+		streamInSigner,
+		tokens,
 	)
 	if err != nil {
-		logger.Fatal("failed-to-create-handler", err)
+		logger.Error("failed-to-create-handler", err)
+		return nil, err
 	}
 
-	members := []grouper.Member{
-		{Name: "api", Runner: http_server.New(listenAddr, apiHandler)},
+	members := []grouper.Member{}
+
+	if !cmd.DisableTCP {
+		apiServer := http_server.New(listenAddr, apiHandler)
+		if serverTLSConfig != nil {
+			apiServer = http_server.NewTLSServer(listenAddr, apiHandler, serverTLSConfig)
+		}
+
+		members = append(members, grouper.Member{Name: "api", Runner: apiServer})
+	}
+
+	if cmd.BindSocket != "" {
+		socketServer, err := cmd.socketServer(apiHandler)
+		if err != nil {
+			logger.Error("failed-to-configure-socket", err)
+			return nil, err
+		}
+
+		members = append(members, grouper.Member{Name: "api-socket", Runner: socketServer})
+	}
+
+	members = append(members, []grouper.Member{
 		{Name: "debug-server", Runner: http_server.New(
 			cmd.debugBindAddr(),
-			http.DefaultServeMux,
+			debugHandler,
 		)},
-	}
+		{Name: "reaper", Runner: volume.NewReaper(
+			logger.Session("reaper"),
+			volumeRepo,
+			cmd.ReapInterval,
+			cmd.UploadTTL,
+		)},
+		{Name: "orphan-collector", Runner: volume.NewOrphanCollector(
+			logger.Session("orphan-collector"),
+			volumeRepo,
+			cmd.OrphanCollectionInterval,
+		)},
+	}...)
 
 	return onReady(grouper.NewParallel(os.Interrupt, members), func() {
-		logger.Info("listening", lager.Data{
-			"addr": listenAddr,
-		})
+		data := lager.Data{}
+		if !cmd.DisableTCP {
+			data["addr"] = listenAddr
+		}
+
+		if cmd.BindSocket != "" {
+			data["socket"] = cmd.BindSocket
+		}
+
+		logger.Info("listening", data)
 	}), nil
 }
 
@@ -137,6 +254,70 @@ func (cmd *BaggageclaimCommand) constructLogger() (lager.Logger, *lager.Reconfig
 	return logger, reconfigurableSink
 }
 
+// tlsConfig returns the configuration to serve the API with, or nil if it is
+// to be served without TLS, and the client to stream to peers with, which
+// presents the same certificate and trusts the same CA.
+func (cmd *BaggageclaimCommand) tlsConfig() (*tls.Config, *http.Client, error) {
+	if (cmd.TLSCert == "") != (cmd.TLSKey == "") {
+		return nil, nil, errors.New("--tls-cert and --tls-key must be given together")
+	}
+
+	if cmd.TLSVerifyClientCerts && (cmd.TLSCert == "" || cmd.TLSCACert == "") {
+		return nil, nil, errors.New("--tls-verify-client-certs requires --tls-cert, --tls-key and --tls-ca-cert")
+	}
+
+	if cmd.TLSCert == "" && cmd.TLSCACert == "" {
+		return nil, nil, nil
+	}
+
+	peerConfig, err := client.NewTLSConfig(cmd.TLSCert.Path(), cmd.TLSKey.Path(), cmd.TLSCACert.Path())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = peerConfig
+
+	p2pClient := &http.Client{Transport: transport}
+
+	if cmd.TLSCert == "" {
+		// peers may serve TLS even though this server does not
+		return nil, p2pClient, nil
+	}
+
+	serverConfig := &tls.Config{
+		Certificates: peerConfig.Certificates,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cmd.TLSVerifyClientCerts {
+		serverConfig.ClientCAs = peerConfig.RootCAs
+		serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return serverConfig, p2pClient, nil
+}
+
+func (cmd *BaggageclaimCommand) socketServer(handler http.Handler) (ifrit.Runner, error) {
+	uid, err := lookupID(cmd.BindSocketOwner, lookupUID)
+	if err != nil {
+		return nil, fmt.Errorf("look up socket owner: %w", err)
+	}
+
+	gid, err := lookupID(cmd.BindSocketGroup, lookupGID)
+	if err != nil {
+		return nil, fmt.Errorf("look up socket group: %w", err)
+	}
+
+	return unixSocketServer{
+		path:    cmd.BindSocket,
+		mode:    os.FileMode(cmd.BindSocketMode),
+		uid:     uid,
+		gid:     gid,
+		handler: handler,
+	}, nil
+}
+
 func (cmd *BaggageclaimCommand) debugBindAddr() string {
 	return fmt.Sprintf("%s:%d", cmd.DebugBindIP, cmd.DebugBindPort)
 }
@@ -77,6 +77,13 @@ func (cmd *BaggageclaimCommand) driver(logger lager.Logger) (volume.Driver, erro
 		}
 	}
 
+	if cmd.Driver == "btrfs" && cmd.BtrfsEnableQuotas {
+		err = exec.Command(cmd.BtrfsBin, "quota", "enable", volumesDir).Run()
+		if err != nil {
+			return nil, fmt.Errorf("failed to enable btrfs quotas: %s", err)
+		}
+	}
+
 	if cmd.Driver == "overlay" && !kernelSupportsOverlay {
 		return nil, errors.New("overlay driver requires kernel version >= 4.0.0")
 	}
@@ -88,7 +95,7 @@ func (cmd *BaggageclaimCommand) driver(logger lager.Logger) (volume.Driver, erro
 	case "overlay":
 		d = driver.NewOverlayDriver(cmd.OverlaysDir)
 	case "btrfs":
-		d = driver.NewBtrFSDriver(logger.Session("driver"), cmd.BtrfsBin)
+		d = driver.NewBtrFSDriver(logger.Session("driver"), cmd.BtrfsBin, cmd.BtrfsEnableQuotas)
 	case "naive":
 		d = &driver.NaiveDriver{}
 	default:
@@ -0,0 +1,160 @@
+package baggageclaimcmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+)
+
+// unixSocketServer serves the handler on a Unix socket, which is given its
+// ownership and permissions before anyone can connect to it.
+type unixSocketServer struct {
+	path    string
+	mode    os.FileMode
+	uid     int
+	gid     int
+	handler http.Handler
+}
+
+func (server unixSocketServer) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	err := removeStaleSocket(server.path)
+	if err != nil {
+		return err
+	}
+
+	listener, err := server.listen()
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(server.path)
+
+	httpServer := &http.Server{Handler: server.handler}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	close(ready)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-signals:
+		return httpServer.Shutdown(context.Background())
+	}
+}
+
+// listen creates the socket in a directory only this process can reach, and
+// only moves it into place once it has its ownership and permissions, so
+// that nobody can connect in between.
+func (server unixSocketServer) listen() (net.Listener, error) {
+	dir, err := ioutil.TempDir(filepath.Dir(server.path), ".socket-")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filepath.Base(server.path))
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// the socket is removed from where it ends up instead
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	err = os.Chmod(path, server.mode)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	if server.uid != -1 || server.gid != -1 {
+		err = os.Chown(path, server.uid, server.gid)
+		if err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+
+	err = os.Rename(path, server.path)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// removeStaleSocket removes a socket left behind by a process that did not
+// get to clean up after itself. Sockets that are still being served, and
+// anything that is not a socket, are left alone.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is already being served", path)
+	}
+
+	return os.Remove(path)
+}
+
+// lookupID resolves a user or group name to its ID, which may also be given
+// directly. An empty name resolves to -1, leaving the ownership unchanged.
+func lookupID(name string, lookup func(string) (string, error)) (int, error) {
+	if name == "" {
+		return -1, nil
+	}
+
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+
+	id, err := lookup(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(id)
+}
+
+func lookupUID(name string) (string, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	return u.Uid, nil
+}
+
+func lookupGID(name string) (string, error) {
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return "", err
+	}
+
+	return g.Gid, nil
+}
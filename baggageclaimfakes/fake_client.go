@@ -62,6 +62,20 @@ type FakeClient struct {
 		result1 baggageclaim.Volumes
 		result2 error
 	}
+	ListVolumesWithUsageStub        func(lager.Logger, baggageclaim.VolumeProperties) (baggageclaim.Volumes, error)
+	listVolumesWithUsageMutex       sync.RWMutex
+	listVolumesWithUsageArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 baggageclaim.VolumeProperties
+	}
+	listVolumesWithUsageReturns struct {
+		result1 baggageclaim.Volumes
+		result2 error
+	}
+	listVolumesWithUsageReturnsOnCall map[int]struct {
+		result1 baggageclaim.Volumes
+		result2 error
+	}
 	LookupVolumeStub        func(lager.Logger, string) (baggageclaim.Volume, bool, error)
 	lookupVolumeMutex       sync.RWMutex
 	lookupVolumeArgsForCall []struct {
@@ -90,15 +104,16 @@ func (fake *FakeClient) CreateVolume(arg1 lager.Logger, arg2 string, arg3 baggag
 		arg2 string
 		arg3 baggageclaim.VolumeSpec
 	}{arg1, arg2, arg3})
+	stub := fake.CreateVolumeStub
+	fakeReturns := fake.createVolumeReturns
 	fake.recordInvocation("CreateVolume", []interface{}{arg1, arg2, arg3})
 	fake.createVolumeMutex.Unlock()
-	if fake.CreateVolumeStub != nil {
-		return fake.CreateVolumeStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.createVolumeReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -154,15 +169,16 @@ func (fake *FakeClient) DestroyVolume(arg1 lager.Logger, arg2 string) error {
 		arg1 lager.Logger
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.DestroyVolumeStub
+	fakeReturns := fake.destroyVolumeReturns
 	fake.recordInvocation("DestroyVolume", []interface{}{arg1, arg2})
 	fake.destroyVolumeMutex.Unlock()
-	if fake.DestroyVolumeStub != nil {
-		return fake.DestroyVolumeStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.destroyVolumeReturns
 	return fakeReturns.result1
 }
 
@@ -220,15 +236,16 @@ func (fake *FakeClient) DestroyVolumes(arg1 lager.Logger, arg2 []string) error {
 		arg1 lager.Logger
 		arg2 []string
 	}{arg1, arg2Copy})
+	stub := fake.DestroyVolumesStub
+	fakeReturns := fake.destroyVolumesReturns
 	fake.recordInvocation("DestroyVolumes", []interface{}{arg1, arg2Copy})
 	fake.destroyVolumesMutex.Unlock()
-	if fake.DestroyVolumesStub != nil {
-		return fake.DestroyVolumesStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.destroyVolumesReturns
 	return fakeReturns.result1
 }
 
@@ -281,15 +298,16 @@ func (fake *FakeClient) ListVolumes(arg1 lager.Logger, arg2 baggageclaim.VolumeP
 		arg1 lager.Logger
 		arg2 baggageclaim.VolumeProperties
 	}{arg1, arg2})
+	stub := fake.ListVolumesStub
+	fakeReturns := fake.listVolumesReturns
 	fake.recordInvocation("ListVolumes", []interface{}{arg1, arg2})
 	fake.listVolumesMutex.Unlock()
-	if fake.ListVolumesStub != nil {
-		return fake.ListVolumesStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.listVolumesReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -338,6 +356,71 @@ func (fake *FakeClient) ListVolumesReturnsOnCall(i int, result1 baggageclaim.Vol
 	}{result1, result2}
 }
 
+func (fake *FakeClient) ListVolumesWithUsage(arg1 lager.Logger, arg2 baggageclaim.VolumeProperties) (baggageclaim.Volumes, error) {
+	fake.listVolumesWithUsageMutex.Lock()
+	ret, specificReturn := fake.listVolumesWithUsageReturnsOnCall[len(fake.listVolumesWithUsageArgsForCall)]
+	fake.listVolumesWithUsageArgsForCall = append(fake.listVolumesWithUsageArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 baggageclaim.VolumeProperties
+	}{arg1, arg2})
+	stub := fake.ListVolumesWithUsageStub
+	fakeReturns := fake.listVolumesWithUsageReturns
+	fake.recordInvocation("ListVolumesWithUsage", []interface{}{arg1, arg2})
+	fake.listVolumesWithUsageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListVolumesWithUsageCallCount() int {
+	fake.listVolumesWithUsageMutex.RLock()
+	defer fake.listVolumesWithUsageMutex.RUnlock()
+	return len(fake.listVolumesWithUsageArgsForCall)
+}
+
+func (fake *FakeClient) ListVolumesWithUsageCalls(stub func(lager.Logger, baggageclaim.VolumeProperties) (baggageclaim.Volumes, error)) {
+	fake.listVolumesWithUsageMutex.Lock()
+	defer fake.listVolumesWithUsageMutex.Unlock()
+	fake.ListVolumesWithUsageStub = stub
+}
+
+func (fake *FakeClient) ListVolumesWithUsageArgsForCall(i int) (lager.Logger, baggageclaim.VolumeProperties) {
+	fake.listVolumesWithUsageMutex.RLock()
+	defer fake.listVolumesWithUsageMutex.RUnlock()
+	argsForCall := fake.listVolumesWithUsageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) ListVolumesWithUsageReturns(result1 baggageclaim.Volumes, result2 error) {
+	fake.listVolumesWithUsageMutex.Lock()
+	defer fake.listVolumesWithUsageMutex.Unlock()
+	fake.ListVolumesWithUsageStub = nil
+	fake.listVolumesWithUsageReturns = struct {
+		result1 baggageclaim.Volumes
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListVolumesWithUsageReturnsOnCall(i int, result1 baggageclaim.Volumes, result2 error) {
+	fake.listVolumesWithUsageMutex.Lock()
+	defer fake.listVolumesWithUsageMutex.Unlock()
+	fake.ListVolumesWithUsageStub = nil
+	if fake.listVolumesWithUsageReturnsOnCall == nil {
+		fake.listVolumesWithUsageReturnsOnCall = make(map[int]struct {
+			result1 baggageclaim.Volumes
+			result2 error
+		})
+	}
+	fake.listVolumesWithUsageReturnsOnCall[i] = struct {
+		result1 baggageclaim.Volumes
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) LookupVolume(arg1 lager.Logger, arg2 string) (baggageclaim.Volume, bool, error) {
 	fake.lookupVolumeMutex.Lock()
 	ret, specificReturn := fake.lookupVolumeReturnsOnCall[len(fake.lookupVolumeArgsForCall)]
@@ -345,15 +428,16 @@ func (fake *FakeClient) LookupVolume(arg1 lager.Logger, arg2 string) (baggagecla
 		arg1 lager.Logger
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.LookupVolumeStub
+	fakeReturns := fake.lookupVolumeReturns
 	fake.recordInvocation("LookupVolume", []interface{}{arg1, arg2})
 	fake.lookupVolumeMutex.Unlock()
-	if fake.LookupVolumeStub != nil {
-		return fake.LookupVolumeStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.lookupVolumeReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -416,6 +500,8 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.destroyVolumesMutex.RUnlock()
 	fake.listVolumesMutex.RLock()
 	defer fake.listVolumesMutex.RUnlock()
+	fake.listVolumesWithUsageMutex.RLock()
+	defer fake.listVolumesWithUsageMutex.RUnlock()
 	fake.lookupVolumeMutex.RLock()
 	defer fake.lookupVolumeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -5,11 +5,26 @@ import (
 	"context"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/concourse/baggageclaim"
 )
 
 type FakeVolume struct {
+	CreateSnapshotStub        func(context.Context, string) (baggageclaim.Snapshot, error)
+	createSnapshotMutex       sync.RWMutex
+	createSnapshotArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	createSnapshotReturns struct {
+		result1 baggageclaim.Snapshot
+		result2 error
+	}
+	createSnapshotReturnsOnCall map[int]struct {
+		result1 baggageclaim.Snapshot
+		result2 error
+	}
 	DestroyStub        func() error
 	destroyMutex       sync.RWMutex
 	destroyArgsForCall []struct {
@@ -20,6 +35,43 @@ type FakeVolume struct {
 	destroyReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DestroySnapshotStub        func(context.Context, string) error
+	destroySnapshotMutex       sync.RWMutex
+	destroySnapshotArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	destroySnapshotReturns struct {
+		result1 error
+	}
+	destroySnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DiffStub        func(context.Context) ([]baggageclaim.Change, error)
+	diffMutex       sync.RWMutex
+	diffArgsForCall []struct {
+		arg1 context.Context
+	}
+	diffReturns struct {
+		result1 []baggageclaim.Change
+		result2 error
+	}
+	diffReturnsOnCall map[int]struct {
+		result1 []baggageclaim.Change
+		result2 error
+	}
+	DigestStub        func() (string, error)
+	digestMutex       sync.RWMutex
+	digestArgsForCall []struct {
+	}
+	digestReturns struct {
+		result1 string
+		result2 error
+	}
+	digestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	GetPrivilegedStub        func() (bool, error)
 	getPrivilegedMutex       sync.RWMutex
 	getPrivilegedArgsForCall []struct {
@@ -56,6 +108,20 @@ type FakeVolume struct {
 	handleReturnsOnCall map[int]struct {
 		result1 string
 	}
+	OpenFileStub        func(context.Context, string) (io.ReadCloser, error)
+	openFileMutex       sync.RWMutex
+	openFileArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	openFileReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	openFileReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
 	PathStub        func() string
 	pathMutex       sync.RWMutex
 	pathArgsForCall []struct {
@@ -78,6 +144,56 @@ type FakeVolume struct {
 		result1 baggageclaim.VolumeProperties
 		result2 error
 	}
+	ReadFileStub        func(context.Context, string) ([]byte, error)
+	readFileMutex       sync.RWMutex
+	readFileArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	readFileReturns struct {
+		result1 []byte
+		result2 error
+	}
+	readFileReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	ReadOnlyStub        func() (bool, error)
+	readOnlyMutex       sync.RWMutex
+	readOnlyArgsForCall []struct {
+	}
+	readOnlyReturns struct {
+		result1 bool
+		result2 error
+	}
+	readOnlyReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	RenameStub        func(context.Context, string) error
+	renameMutex       sync.RWMutex
+	renameArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	renameReturns struct {
+		result1 error
+	}
+	renameReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RestoreSnapshotStub        func(context.Context, string) error
+	restoreSnapshotMutex       sync.RWMutex
+	restoreSnapshotArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	restoreSnapshotReturns struct {
+		result1 error
+	}
+	restoreSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetPrivilegedStub        func(bool) error
 	setPrivilegedMutex       sync.RWMutex
 	setPrivilegedArgsForCall []struct {
@@ -101,6 +217,39 @@ type FakeVolume struct {
 	setPropertyReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetReadOnlyStub        func() error
+	setReadOnlyMutex       sync.RWMutex
+	setReadOnlyArgsForCall []struct {
+	}
+	setReadOnlyReturns struct {
+		result1 error
+	}
+	setReadOnlyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetTTLStub        func(time.Duration) error
+	setTTLMutex       sync.RWMutex
+	setTTLArgsForCall []struct {
+		arg1 time.Duration
+	}
+	setTTLReturns struct {
+		result1 error
+	}
+	setTTLReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SnapshotsStub        func() ([]baggageclaim.Snapshot, error)
+	snapshotsMutex       sync.RWMutex
+	snapshotsArgsForCall []struct {
+	}
+	snapshotsReturns struct {
+		result1 []baggageclaim.Snapshot
+		result2 error
+	}
+	snapshotsReturnsOnCall map[int]struct {
+		result1 []baggageclaim.Snapshot
+		result2 error
+	}
 	StreamInStub        func(context.Context, string, baggageclaim.Encoding, io.Reader) error
 	streamInMutex       sync.RWMutex
 	streamInArgsForCall []struct {
@@ -115,6 +264,20 @@ type FakeVolume struct {
 	streamInReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StreamInResumableStub        func(context.Context, string, baggageclaim.Encoding, io.Reader) error
+	streamInResumableMutex       sync.RWMutex
+	streamInResumableArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 baggageclaim.Encoding
+		arg4 io.Reader
+	}
+	streamInResumableReturns struct {
+		result1 error
+	}
+	streamInResumableReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StreamOutStub        func(context.Context, string, baggageclaim.Encoding) (io.ReadCloser, error)
 	streamOutMutex       sync.RWMutex
 	streamOutArgsForCall []struct {
@@ -130,6 +293,36 @@ type FakeVolume struct {
 		result1 io.ReadCloser
 		result2 error
 	}
+	StreamOutDiffStub        func(context.Context, string, baggageclaim.Encoding) (io.ReadCloser, error)
+	streamOutDiffMutex       sync.RWMutex
+	streamOutDiffArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 baggageclaim.Encoding
+	}
+	streamOutDiffReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	streamOutDiffReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	StreamOutOCIStub        func(context.Context, bool, baggageclaim.Encoding) (io.ReadCloser, error)
+	streamOutOCIMutex       sync.RWMutex
+	streamOutOCIArgsForCall []struct {
+		arg1 context.Context
+		arg2 bool
+		arg3 baggageclaim.Encoding
+	}
+	streamOutOCIReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	streamOutOCIReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
 	StreamP2pOutStub        func(context.Context, string, string, baggageclaim.Encoding) error
 	streamP2pOutMutex       sync.RWMutex
 	streamP2pOutArgsForCall []struct {
@@ -144,24 +337,117 @@ type FakeVolume struct {
 	streamP2pOutReturnsOnCall map[int]struct {
 		result1 error
 	}
+	TreeStub        func(context.Context, string, int) ([]baggageclaim.TreeEntry, error)
+	treeMutex       sync.RWMutex
+	treeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}
+	treeReturns struct {
+		result1 []baggageclaim.TreeEntry
+		result2 error
+	}
+	treeReturnsOnCall map[int]struct {
+		result1 []baggageclaim.TreeEntry
+		result2 error
+	}
+	UsageStub        func() (baggageclaim.VolumeUsage, error)
+	usageMutex       sync.RWMutex
+	usageArgsForCall []struct {
+	}
+	usageReturns struct {
+		result1 baggageclaim.VolumeUsage
+		result2 error
+	}
+	usageReturnsOnCall map[int]struct {
+		result1 baggageclaim.VolumeUsage
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeVolume) CreateSnapshot(arg1 context.Context, arg2 string) (baggageclaim.Snapshot, error) {
+	fake.createSnapshotMutex.Lock()
+	ret, specificReturn := fake.createSnapshotReturnsOnCall[len(fake.createSnapshotArgsForCall)]
+	fake.createSnapshotArgsForCall = append(fake.createSnapshotArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CreateSnapshotStub
+	fakeReturns := fake.createSnapshotReturns
+	fake.recordInvocation("CreateSnapshot", []interface{}{arg1, arg2})
+	fake.createSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVolume) CreateSnapshotCallCount() int {
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	return len(fake.createSnapshotArgsForCall)
+}
+
+func (fake *FakeVolume) CreateSnapshotCalls(stub func(context.Context, string) (baggageclaim.Snapshot, error)) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = stub
+}
+
+func (fake *FakeVolume) CreateSnapshotArgsForCall(i int) (context.Context, string) {
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	argsForCall := fake.createSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVolume) CreateSnapshotReturns(result1 baggageclaim.Snapshot, result2 error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = nil
+	fake.createSnapshotReturns = struct {
+		result1 baggageclaim.Snapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) CreateSnapshotReturnsOnCall(i int, result1 baggageclaim.Snapshot, result2 error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = nil
+	if fake.createSnapshotReturnsOnCall == nil {
+		fake.createSnapshotReturnsOnCall = make(map[int]struct {
+			result1 baggageclaim.Snapshot
+			result2 error
+		})
+	}
+	fake.createSnapshotReturnsOnCall[i] = struct {
+		result1 baggageclaim.Snapshot
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeVolume) Destroy() error {
 	fake.destroyMutex.Lock()
 	ret, specificReturn := fake.destroyReturnsOnCall[len(fake.destroyArgsForCall)]
 	fake.destroyArgsForCall = append(fake.destroyArgsForCall, struct {
 	}{})
+	stub := fake.DestroyStub
+	fakeReturns := fake.destroyReturns
 	fake.recordInvocation("Destroy", []interface{}{})
 	fake.destroyMutex.Unlock()
-	if fake.DestroyStub != nil {
-		return fake.DestroyStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.destroyReturns
 	return fakeReturns.result1
 }
 
@@ -200,20 +486,203 @@ func (fake *FakeVolume) DestroyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeVolume) DestroySnapshot(arg1 context.Context, arg2 string) error {
+	fake.destroySnapshotMutex.Lock()
+	ret, specificReturn := fake.destroySnapshotReturnsOnCall[len(fake.destroySnapshotArgsForCall)]
+	fake.destroySnapshotArgsForCall = append(fake.destroySnapshotArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DestroySnapshotStub
+	fakeReturns := fake.destroySnapshotReturns
+	fake.recordInvocation("DestroySnapshot", []interface{}{arg1, arg2})
+	fake.destroySnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVolume) DestroySnapshotCallCount() int {
+	fake.destroySnapshotMutex.RLock()
+	defer fake.destroySnapshotMutex.RUnlock()
+	return len(fake.destroySnapshotArgsForCall)
+}
+
+func (fake *FakeVolume) DestroySnapshotCalls(stub func(context.Context, string) error) {
+	fake.destroySnapshotMutex.Lock()
+	defer fake.destroySnapshotMutex.Unlock()
+	fake.DestroySnapshotStub = stub
+}
+
+func (fake *FakeVolume) DestroySnapshotArgsForCall(i int) (context.Context, string) {
+	fake.destroySnapshotMutex.RLock()
+	defer fake.destroySnapshotMutex.RUnlock()
+	argsForCall := fake.destroySnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVolume) DestroySnapshotReturns(result1 error) {
+	fake.destroySnapshotMutex.Lock()
+	defer fake.destroySnapshotMutex.Unlock()
+	fake.DestroySnapshotStub = nil
+	fake.destroySnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolume) DestroySnapshotReturnsOnCall(i int, result1 error) {
+	fake.destroySnapshotMutex.Lock()
+	defer fake.destroySnapshotMutex.Unlock()
+	fake.DestroySnapshotStub = nil
+	if fake.destroySnapshotReturnsOnCall == nil {
+		fake.destroySnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.destroySnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolume) Diff(arg1 context.Context) ([]baggageclaim.Change, error) {
+	fake.diffMutex.Lock()
+	ret, specificReturn := fake.diffReturnsOnCall[len(fake.diffArgsForCall)]
+	fake.diffArgsForCall = append(fake.diffArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.DiffStub
+	fakeReturns := fake.diffReturns
+	fake.recordInvocation("Diff", []interface{}{arg1})
+	fake.diffMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVolume) DiffCallCount() int {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	return len(fake.diffArgsForCall)
+}
+
+func (fake *FakeVolume) DiffCalls(stub func(context.Context) ([]baggageclaim.Change, error)) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = stub
+}
+
+func (fake *FakeVolume) DiffArgsForCall(i int) context.Context {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	argsForCall := fake.diffArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVolume) DiffReturns(result1 []baggageclaim.Change, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	fake.diffReturns = struct {
+		result1 []baggageclaim.Change
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) DiffReturnsOnCall(i int, result1 []baggageclaim.Change, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	if fake.diffReturnsOnCall == nil {
+		fake.diffReturnsOnCall = make(map[int]struct {
+			result1 []baggageclaim.Change
+			result2 error
+		})
+	}
+	fake.diffReturnsOnCall[i] = struct {
+		result1 []baggageclaim.Change
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) Digest() (string, error) {
+	fake.digestMutex.Lock()
+	ret, specificReturn := fake.digestReturnsOnCall[len(fake.digestArgsForCall)]
+	fake.digestArgsForCall = append(fake.digestArgsForCall, struct {
+	}{})
+	stub := fake.DigestStub
+	fakeReturns := fake.digestReturns
+	fake.recordInvocation("Digest", []interface{}{})
+	fake.digestMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVolume) DigestCallCount() int {
+	fake.digestMutex.RLock()
+	defer fake.digestMutex.RUnlock()
+	return len(fake.digestArgsForCall)
+}
+
+func (fake *FakeVolume) DigestCalls(stub func() (string, error)) {
+	fake.digestMutex.Lock()
+	defer fake.digestMutex.Unlock()
+	fake.DigestStub = stub
+}
+
+func (fake *FakeVolume) DigestReturns(result1 string, result2 error) {
+	fake.digestMutex.Lock()
+	defer fake.digestMutex.Unlock()
+	fake.DigestStub = nil
+	fake.digestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) DigestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.digestMutex.Lock()
+	defer fake.digestMutex.Unlock()
+	fake.DigestStub = nil
+	if fake.digestReturnsOnCall == nil {
+		fake.digestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.digestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeVolume) GetPrivileged() (bool, error) {
 	fake.getPrivilegedMutex.Lock()
 	ret, specificReturn := fake.getPrivilegedReturnsOnCall[len(fake.getPrivilegedArgsForCall)]
 	fake.getPrivilegedArgsForCall = append(fake.getPrivilegedArgsForCall, struct {
 	}{})
+	stub := fake.GetPrivilegedStub
+	fakeReturns := fake.getPrivilegedReturns
 	fake.recordInvocation("GetPrivileged", []interface{}{})
 	fake.getPrivilegedMutex.Unlock()
-	if fake.GetPrivilegedStub != nil {
-		return fake.GetPrivilegedStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getPrivilegedReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -262,15 +731,16 @@ func (fake *FakeVolume) GetStreamInP2pUrl(arg1 context.Context, arg2 string) (st
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.GetStreamInP2pUrlStub
+	fakeReturns := fake.getStreamInP2pUrlReturns
 	fake.recordInvocation("GetStreamInP2pUrl", []interface{}{arg1, arg2})
 	fake.getStreamInP2pUrlMutex.Unlock()
-	if fake.GetStreamInP2pUrlStub != nil {
-		return fake.GetStreamInP2pUrlStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getStreamInP2pUrlReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -324,15 +794,16 @@ func (fake *FakeVolume) Handle() string {
 	ret, specificReturn := fake.handleReturnsOnCall[len(fake.handleArgsForCall)]
 	fake.handleArgsForCall = append(fake.handleArgsForCall, struct {
 	}{})
+	stub := fake.HandleStub
+	fakeReturns := fake.handleReturns
 	fake.recordInvocation("Handle", []interface{}{})
 	fake.handleMutex.Unlock()
-	if fake.HandleStub != nil {
-		return fake.HandleStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.handleReturns
 	return fakeReturns.result1
 }
 
@@ -371,30 +842,96 @@ func (fake *FakeVolume) HandleReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
-func (fake *FakeVolume) Path() string {
-	fake.pathMutex.Lock()
-	ret, specificReturn := fake.pathReturnsOnCall[len(fake.pathArgsForCall)]
-	fake.pathArgsForCall = append(fake.pathArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Path", []interface{}{})
-	fake.pathMutex.Unlock()
-	if fake.PathStub != nil {
-		return fake.PathStub()
+func (fake *FakeVolume) OpenFile(arg1 context.Context, arg2 string) (io.ReadCloser, error) {
+	fake.openFileMutex.Lock()
+	ret, specificReturn := fake.openFileReturnsOnCall[len(fake.openFileArgsForCall)]
+	fake.openFileArgsForCall = append(fake.openFileArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.OpenFileStub
+	fakeReturns := fake.openFileReturns
+	fake.recordInvocation("OpenFile", []interface{}{arg1, arg2})
+	fake.openFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.pathReturns
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeVolume) PathCallCount() int {
-	fake.pathMutex.RLock()
-	defer fake.pathMutex.RUnlock()
-	return len(fake.pathArgsForCall)
+func (fake *FakeVolume) OpenFileCallCount() int {
+	fake.openFileMutex.RLock()
+	defer fake.openFileMutex.RUnlock()
+	return len(fake.openFileArgsForCall)
 }
 
-func (fake *FakeVolume) PathCalls(stub func() string) {
+func (fake *FakeVolume) OpenFileCalls(stub func(context.Context, string) (io.ReadCloser, error)) {
+	fake.openFileMutex.Lock()
+	defer fake.openFileMutex.Unlock()
+	fake.OpenFileStub = stub
+}
+
+func (fake *FakeVolume) OpenFileArgsForCall(i int) (context.Context, string) {
+	fake.openFileMutex.RLock()
+	defer fake.openFileMutex.RUnlock()
+	argsForCall := fake.openFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVolume) OpenFileReturns(result1 io.ReadCloser, result2 error) {
+	fake.openFileMutex.Lock()
+	defer fake.openFileMutex.Unlock()
+	fake.OpenFileStub = nil
+	fake.openFileReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) OpenFileReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.openFileMutex.Lock()
+	defer fake.openFileMutex.Unlock()
+	fake.OpenFileStub = nil
+	if fake.openFileReturnsOnCall == nil {
+		fake.openFileReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.openFileReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) Path() string {
+	fake.pathMutex.Lock()
+	ret, specificReturn := fake.pathReturnsOnCall[len(fake.pathArgsForCall)]
+	fake.pathArgsForCall = append(fake.pathArgsForCall, struct {
+	}{})
+	stub := fake.PathStub
+	fakeReturns := fake.pathReturns
+	fake.recordInvocation("Path", []interface{}{})
+	fake.pathMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVolume) PathCallCount() int {
+	fake.pathMutex.RLock()
+	defer fake.pathMutex.RUnlock()
+	return len(fake.pathArgsForCall)
+}
+
+func (fake *FakeVolume) PathCalls(stub func() string) {
 	fake.pathMutex.Lock()
 	defer fake.pathMutex.Unlock()
 	fake.PathStub = stub
@@ -428,15 +965,16 @@ func (fake *FakeVolume) Properties() (baggageclaim.VolumeProperties, error) {
 	ret, specificReturn := fake.propertiesReturnsOnCall[len(fake.propertiesArgsForCall)]
 	fake.propertiesArgsForCall = append(fake.propertiesArgsForCall, struct {
 	}{})
+	stub := fake.PropertiesStub
+	fakeReturns := fake.propertiesReturns
 	fake.recordInvocation("Properties", []interface{}{})
 	fake.propertiesMutex.Unlock()
-	if fake.PropertiesStub != nil {
-		return fake.PropertiesStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.propertiesReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -478,21 +1016,267 @@ func (fake *FakeVolume) PropertiesReturnsOnCall(i int, result1 baggageclaim.Volu
 	}{result1, result2}
 }
 
+func (fake *FakeVolume) ReadFile(arg1 context.Context, arg2 string) ([]byte, error) {
+	fake.readFileMutex.Lock()
+	ret, specificReturn := fake.readFileReturnsOnCall[len(fake.readFileArgsForCall)]
+	fake.readFileArgsForCall = append(fake.readFileArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ReadFileStub
+	fakeReturns := fake.readFileReturns
+	fake.recordInvocation("ReadFile", []interface{}{arg1, arg2})
+	fake.readFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVolume) ReadFileCallCount() int {
+	fake.readFileMutex.RLock()
+	defer fake.readFileMutex.RUnlock()
+	return len(fake.readFileArgsForCall)
+}
+
+func (fake *FakeVolume) ReadFileCalls(stub func(context.Context, string) ([]byte, error)) {
+	fake.readFileMutex.Lock()
+	defer fake.readFileMutex.Unlock()
+	fake.ReadFileStub = stub
+}
+
+func (fake *FakeVolume) ReadFileArgsForCall(i int) (context.Context, string) {
+	fake.readFileMutex.RLock()
+	defer fake.readFileMutex.RUnlock()
+	argsForCall := fake.readFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVolume) ReadFileReturns(result1 []byte, result2 error) {
+	fake.readFileMutex.Lock()
+	defer fake.readFileMutex.Unlock()
+	fake.ReadFileStub = nil
+	fake.readFileReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) ReadFileReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.readFileMutex.Lock()
+	defer fake.readFileMutex.Unlock()
+	fake.ReadFileStub = nil
+	if fake.readFileReturnsOnCall == nil {
+		fake.readFileReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.readFileReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) ReadOnly() (bool, error) {
+	fake.readOnlyMutex.Lock()
+	ret, specificReturn := fake.readOnlyReturnsOnCall[len(fake.readOnlyArgsForCall)]
+	fake.readOnlyArgsForCall = append(fake.readOnlyArgsForCall, struct {
+	}{})
+	stub := fake.ReadOnlyStub
+	fakeReturns := fake.readOnlyReturns
+	fake.recordInvocation("ReadOnly", []interface{}{})
+	fake.readOnlyMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVolume) ReadOnlyCallCount() int {
+	fake.readOnlyMutex.RLock()
+	defer fake.readOnlyMutex.RUnlock()
+	return len(fake.readOnlyArgsForCall)
+}
+
+func (fake *FakeVolume) ReadOnlyCalls(stub func() (bool, error)) {
+	fake.readOnlyMutex.Lock()
+	defer fake.readOnlyMutex.Unlock()
+	fake.ReadOnlyStub = stub
+}
+
+func (fake *FakeVolume) ReadOnlyReturns(result1 bool, result2 error) {
+	fake.readOnlyMutex.Lock()
+	defer fake.readOnlyMutex.Unlock()
+	fake.ReadOnlyStub = nil
+	fake.readOnlyReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) ReadOnlyReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.readOnlyMutex.Lock()
+	defer fake.readOnlyMutex.Unlock()
+	fake.ReadOnlyStub = nil
+	if fake.readOnlyReturnsOnCall == nil {
+		fake.readOnlyReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.readOnlyReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) Rename(arg1 context.Context, arg2 string) error {
+	fake.renameMutex.Lock()
+	ret, specificReturn := fake.renameReturnsOnCall[len(fake.renameArgsForCall)]
+	fake.renameArgsForCall = append(fake.renameArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RenameStub
+	fakeReturns := fake.renameReturns
+	fake.recordInvocation("Rename", []interface{}{arg1, arg2})
+	fake.renameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVolume) RenameCallCount() int {
+	fake.renameMutex.RLock()
+	defer fake.renameMutex.RUnlock()
+	return len(fake.renameArgsForCall)
+}
+
+func (fake *FakeVolume) RenameCalls(stub func(context.Context, string) error) {
+	fake.renameMutex.Lock()
+	defer fake.renameMutex.Unlock()
+	fake.RenameStub = stub
+}
+
+func (fake *FakeVolume) RenameArgsForCall(i int) (context.Context, string) {
+	fake.renameMutex.RLock()
+	defer fake.renameMutex.RUnlock()
+	argsForCall := fake.renameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVolume) RenameReturns(result1 error) {
+	fake.renameMutex.Lock()
+	defer fake.renameMutex.Unlock()
+	fake.RenameStub = nil
+	fake.renameReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolume) RenameReturnsOnCall(i int, result1 error) {
+	fake.renameMutex.Lock()
+	defer fake.renameMutex.Unlock()
+	fake.RenameStub = nil
+	if fake.renameReturnsOnCall == nil {
+		fake.renameReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renameReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolume) RestoreSnapshot(arg1 context.Context, arg2 string) error {
+	fake.restoreSnapshotMutex.Lock()
+	ret, specificReturn := fake.restoreSnapshotReturnsOnCall[len(fake.restoreSnapshotArgsForCall)]
+	fake.restoreSnapshotArgsForCall = append(fake.restoreSnapshotArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RestoreSnapshotStub
+	fakeReturns := fake.restoreSnapshotReturns
+	fake.recordInvocation("RestoreSnapshot", []interface{}{arg1, arg2})
+	fake.restoreSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVolume) RestoreSnapshotCallCount() int {
+	fake.restoreSnapshotMutex.RLock()
+	defer fake.restoreSnapshotMutex.RUnlock()
+	return len(fake.restoreSnapshotArgsForCall)
+}
+
+func (fake *FakeVolume) RestoreSnapshotCalls(stub func(context.Context, string) error) {
+	fake.restoreSnapshotMutex.Lock()
+	defer fake.restoreSnapshotMutex.Unlock()
+	fake.RestoreSnapshotStub = stub
+}
+
+func (fake *FakeVolume) RestoreSnapshotArgsForCall(i int) (context.Context, string) {
+	fake.restoreSnapshotMutex.RLock()
+	defer fake.restoreSnapshotMutex.RUnlock()
+	argsForCall := fake.restoreSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVolume) RestoreSnapshotReturns(result1 error) {
+	fake.restoreSnapshotMutex.Lock()
+	defer fake.restoreSnapshotMutex.Unlock()
+	fake.RestoreSnapshotStub = nil
+	fake.restoreSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolume) RestoreSnapshotReturnsOnCall(i int, result1 error) {
+	fake.restoreSnapshotMutex.Lock()
+	defer fake.restoreSnapshotMutex.Unlock()
+	fake.RestoreSnapshotStub = nil
+	if fake.restoreSnapshotReturnsOnCall == nil {
+		fake.restoreSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeVolume) SetPrivileged(arg1 bool) error {
 	fake.setPrivilegedMutex.Lock()
 	ret, specificReturn := fake.setPrivilegedReturnsOnCall[len(fake.setPrivilegedArgsForCall)]
 	fake.setPrivilegedArgsForCall = append(fake.setPrivilegedArgsForCall, struct {
 		arg1 bool
 	}{arg1})
+	stub := fake.SetPrivilegedStub
+	fakeReturns := fake.setPrivilegedReturns
 	fake.recordInvocation("SetPrivileged", []interface{}{arg1})
 	fake.setPrivilegedMutex.Unlock()
-	if fake.SetPrivilegedStub != nil {
-		return fake.SetPrivilegedStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.setPrivilegedReturns
 	return fakeReturns.result1
 }
 
@@ -545,15 +1329,16 @@ func (fake *FakeVolume) SetProperty(arg1 string, arg2 string) error {
 		arg1 string
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.SetPropertyStub
+	fakeReturns := fake.setPropertyReturns
 	fake.recordInvocation("SetProperty", []interface{}{arg1, arg2})
 	fake.setPropertyMutex.Unlock()
-	if fake.SetPropertyStub != nil {
-		return fake.SetPropertyStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.setPropertyReturns
 	return fakeReturns.result1
 }
 
@@ -599,6 +1384,176 @@ func (fake *FakeVolume) SetPropertyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeVolume) SetReadOnly() error {
+	fake.setReadOnlyMutex.Lock()
+	ret, specificReturn := fake.setReadOnlyReturnsOnCall[len(fake.setReadOnlyArgsForCall)]
+	fake.setReadOnlyArgsForCall = append(fake.setReadOnlyArgsForCall, struct {
+	}{})
+	stub := fake.SetReadOnlyStub
+	fakeReturns := fake.setReadOnlyReturns
+	fake.recordInvocation("SetReadOnly", []interface{}{})
+	fake.setReadOnlyMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVolume) SetReadOnlyCallCount() int {
+	fake.setReadOnlyMutex.RLock()
+	defer fake.setReadOnlyMutex.RUnlock()
+	return len(fake.setReadOnlyArgsForCall)
+}
+
+func (fake *FakeVolume) SetReadOnlyCalls(stub func() error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = stub
+}
+
+func (fake *FakeVolume) SetReadOnlyReturns(result1 error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = nil
+	fake.setReadOnlyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolume) SetReadOnlyReturnsOnCall(i int, result1 error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = nil
+	if fake.setReadOnlyReturnsOnCall == nil {
+		fake.setReadOnlyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setReadOnlyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolume) SetTTL(arg1 time.Duration) error {
+	fake.setTTLMutex.Lock()
+	ret, specificReturn := fake.setTTLReturnsOnCall[len(fake.setTTLArgsForCall)]
+	fake.setTTLArgsForCall = append(fake.setTTLArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	stub := fake.SetTTLStub
+	fakeReturns := fake.setTTLReturns
+	fake.recordInvocation("SetTTL", []interface{}{arg1})
+	fake.setTTLMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVolume) SetTTLCallCount() int {
+	fake.setTTLMutex.RLock()
+	defer fake.setTTLMutex.RUnlock()
+	return len(fake.setTTLArgsForCall)
+}
+
+func (fake *FakeVolume) SetTTLCalls(stub func(time.Duration) error) {
+	fake.setTTLMutex.Lock()
+	defer fake.setTTLMutex.Unlock()
+	fake.SetTTLStub = stub
+}
+
+func (fake *FakeVolume) SetTTLArgsForCall(i int) time.Duration {
+	fake.setTTLMutex.RLock()
+	defer fake.setTTLMutex.RUnlock()
+	argsForCall := fake.setTTLArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeVolume) SetTTLReturns(result1 error) {
+	fake.setTTLMutex.Lock()
+	defer fake.setTTLMutex.Unlock()
+	fake.SetTTLStub = nil
+	fake.setTTLReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolume) SetTTLReturnsOnCall(i int, result1 error) {
+	fake.setTTLMutex.Lock()
+	defer fake.setTTLMutex.Unlock()
+	fake.SetTTLStub = nil
+	if fake.setTTLReturnsOnCall == nil {
+		fake.setTTLReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setTTLReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolume) Snapshots() ([]baggageclaim.Snapshot, error) {
+	fake.snapshotsMutex.Lock()
+	ret, specificReturn := fake.snapshotsReturnsOnCall[len(fake.snapshotsArgsForCall)]
+	fake.snapshotsArgsForCall = append(fake.snapshotsArgsForCall, struct {
+	}{})
+	stub := fake.SnapshotsStub
+	fakeReturns := fake.snapshotsReturns
+	fake.recordInvocation("Snapshots", []interface{}{})
+	fake.snapshotsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVolume) SnapshotsCallCount() int {
+	fake.snapshotsMutex.RLock()
+	defer fake.snapshotsMutex.RUnlock()
+	return len(fake.snapshotsArgsForCall)
+}
+
+func (fake *FakeVolume) SnapshotsCalls(stub func() ([]baggageclaim.Snapshot, error)) {
+	fake.snapshotsMutex.Lock()
+	defer fake.snapshotsMutex.Unlock()
+	fake.SnapshotsStub = stub
+}
+
+func (fake *FakeVolume) SnapshotsReturns(result1 []baggageclaim.Snapshot, result2 error) {
+	fake.snapshotsMutex.Lock()
+	defer fake.snapshotsMutex.Unlock()
+	fake.SnapshotsStub = nil
+	fake.snapshotsReturns = struct {
+		result1 []baggageclaim.Snapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) SnapshotsReturnsOnCall(i int, result1 []baggageclaim.Snapshot, result2 error) {
+	fake.snapshotsMutex.Lock()
+	defer fake.snapshotsMutex.Unlock()
+	fake.SnapshotsStub = nil
+	if fake.snapshotsReturnsOnCall == nil {
+		fake.snapshotsReturnsOnCall = make(map[int]struct {
+			result1 []baggageclaim.Snapshot
+			result2 error
+		})
+	}
+	fake.snapshotsReturnsOnCall[i] = struct {
+		result1 []baggageclaim.Snapshot
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeVolume) StreamIn(arg1 context.Context, arg2 string, arg3 baggageclaim.Encoding, arg4 io.Reader) error {
 	fake.streamInMutex.Lock()
 	ret, specificReturn := fake.streamInReturnsOnCall[len(fake.streamInArgsForCall)]
@@ -608,15 +1563,16 @@ func (fake *FakeVolume) StreamIn(arg1 context.Context, arg2 string, arg3 baggage
 		arg3 baggageclaim.Encoding
 		arg4 io.Reader
 	}{arg1, arg2, arg3, arg4})
+	stub := fake.StreamInStub
+	fakeReturns := fake.streamInReturns
 	fake.recordInvocation("StreamIn", []interface{}{arg1, arg2, arg3, arg4})
 	fake.streamInMutex.Unlock()
-	if fake.StreamInStub != nil {
-		return fake.StreamInStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.streamInReturns
 	return fakeReturns.result1
 }
 
@@ -662,6 +1618,70 @@ func (fake *FakeVolume) StreamInReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeVolume) StreamInResumable(arg1 context.Context, arg2 string, arg3 baggageclaim.Encoding, arg4 io.Reader) error {
+	fake.streamInResumableMutex.Lock()
+	ret, specificReturn := fake.streamInResumableReturnsOnCall[len(fake.streamInResumableArgsForCall)]
+	fake.streamInResumableArgsForCall = append(fake.streamInResumableArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 baggageclaim.Encoding
+		arg4 io.Reader
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.StreamInResumableStub
+	fakeReturns := fake.streamInResumableReturns
+	fake.recordInvocation("StreamInResumable", []interface{}{arg1, arg2, arg3, arg4})
+	fake.streamInResumableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVolume) StreamInResumableCallCount() int {
+	fake.streamInResumableMutex.RLock()
+	defer fake.streamInResumableMutex.RUnlock()
+	return len(fake.streamInResumableArgsForCall)
+}
+
+func (fake *FakeVolume) StreamInResumableCalls(stub func(context.Context, string, baggageclaim.Encoding, io.Reader) error) {
+	fake.streamInResumableMutex.Lock()
+	defer fake.streamInResumableMutex.Unlock()
+	fake.StreamInResumableStub = stub
+}
+
+func (fake *FakeVolume) StreamInResumableArgsForCall(i int) (context.Context, string, baggageclaim.Encoding, io.Reader) {
+	fake.streamInResumableMutex.RLock()
+	defer fake.streamInResumableMutex.RUnlock()
+	argsForCall := fake.streamInResumableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeVolume) StreamInResumableReturns(result1 error) {
+	fake.streamInResumableMutex.Lock()
+	defer fake.streamInResumableMutex.Unlock()
+	fake.StreamInResumableStub = nil
+	fake.streamInResumableReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolume) StreamInResumableReturnsOnCall(i int, result1 error) {
+	fake.streamInResumableMutex.Lock()
+	defer fake.streamInResumableMutex.Unlock()
+	fake.StreamInResumableStub = nil
+	if fake.streamInResumableReturnsOnCall == nil {
+		fake.streamInResumableReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamInResumableReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeVolume) StreamOut(arg1 context.Context, arg2 string, arg3 baggageclaim.Encoding) (io.ReadCloser, error) {
 	fake.streamOutMutex.Lock()
 	ret, specificReturn := fake.streamOutReturnsOnCall[len(fake.streamOutArgsForCall)]
@@ -670,15 +1690,16 @@ func (fake *FakeVolume) StreamOut(arg1 context.Context, arg2 string, arg3 baggag
 		arg2 string
 		arg3 baggageclaim.Encoding
 	}{arg1, arg2, arg3})
+	stub := fake.StreamOutStub
+	fakeReturns := fake.streamOutReturns
 	fake.recordInvocation("StreamOut", []interface{}{arg1, arg2, arg3})
 	fake.streamOutMutex.Unlock()
-	if fake.StreamOutStub != nil {
-		return fake.StreamOutStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.streamOutReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -727,6 +1748,138 @@ func (fake *FakeVolume) StreamOutReturnsOnCall(i int, result1 io.ReadCloser, res
 	}{result1, result2}
 }
 
+func (fake *FakeVolume) StreamOutDiff(arg1 context.Context, arg2 string, arg3 baggageclaim.Encoding) (io.ReadCloser, error) {
+	fake.streamOutDiffMutex.Lock()
+	ret, specificReturn := fake.streamOutDiffReturnsOnCall[len(fake.streamOutDiffArgsForCall)]
+	fake.streamOutDiffArgsForCall = append(fake.streamOutDiffArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 baggageclaim.Encoding
+	}{arg1, arg2, arg3})
+	stub := fake.StreamOutDiffStub
+	fakeReturns := fake.streamOutDiffReturns
+	fake.recordInvocation("StreamOutDiff", []interface{}{arg1, arg2, arg3})
+	fake.streamOutDiffMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVolume) StreamOutDiffCallCount() int {
+	fake.streamOutDiffMutex.RLock()
+	defer fake.streamOutDiffMutex.RUnlock()
+	return len(fake.streamOutDiffArgsForCall)
+}
+
+func (fake *FakeVolume) StreamOutDiffCalls(stub func(context.Context, string, baggageclaim.Encoding) (io.ReadCloser, error)) {
+	fake.streamOutDiffMutex.Lock()
+	defer fake.streamOutDiffMutex.Unlock()
+	fake.StreamOutDiffStub = stub
+}
+
+func (fake *FakeVolume) StreamOutDiffArgsForCall(i int) (context.Context, string, baggageclaim.Encoding) {
+	fake.streamOutDiffMutex.RLock()
+	defer fake.streamOutDiffMutex.RUnlock()
+	argsForCall := fake.streamOutDiffArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeVolume) StreamOutDiffReturns(result1 io.ReadCloser, result2 error) {
+	fake.streamOutDiffMutex.Lock()
+	defer fake.streamOutDiffMutex.Unlock()
+	fake.StreamOutDiffStub = nil
+	fake.streamOutDiffReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) StreamOutDiffReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.streamOutDiffMutex.Lock()
+	defer fake.streamOutDiffMutex.Unlock()
+	fake.StreamOutDiffStub = nil
+	if fake.streamOutDiffReturnsOnCall == nil {
+		fake.streamOutDiffReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.streamOutDiffReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) StreamOutOCI(arg1 context.Context, arg2 bool, arg3 baggageclaim.Encoding) (io.ReadCloser, error) {
+	fake.streamOutOCIMutex.Lock()
+	ret, specificReturn := fake.streamOutOCIReturnsOnCall[len(fake.streamOutOCIArgsForCall)]
+	fake.streamOutOCIArgsForCall = append(fake.streamOutOCIArgsForCall, struct {
+		arg1 context.Context
+		arg2 bool
+		arg3 baggageclaim.Encoding
+	}{arg1, arg2, arg3})
+	stub := fake.StreamOutOCIStub
+	fakeReturns := fake.streamOutOCIReturns
+	fake.recordInvocation("StreamOutOCI", []interface{}{arg1, arg2, arg3})
+	fake.streamOutOCIMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVolume) StreamOutOCICallCount() int {
+	fake.streamOutOCIMutex.RLock()
+	defer fake.streamOutOCIMutex.RUnlock()
+	return len(fake.streamOutOCIArgsForCall)
+}
+
+func (fake *FakeVolume) StreamOutOCICalls(stub func(context.Context, bool, baggageclaim.Encoding) (io.ReadCloser, error)) {
+	fake.streamOutOCIMutex.Lock()
+	defer fake.streamOutOCIMutex.Unlock()
+	fake.StreamOutOCIStub = stub
+}
+
+func (fake *FakeVolume) StreamOutOCIArgsForCall(i int) (context.Context, bool, baggageclaim.Encoding) {
+	fake.streamOutOCIMutex.RLock()
+	defer fake.streamOutOCIMutex.RUnlock()
+	argsForCall := fake.streamOutOCIArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeVolume) StreamOutOCIReturns(result1 io.ReadCloser, result2 error) {
+	fake.streamOutOCIMutex.Lock()
+	defer fake.streamOutOCIMutex.Unlock()
+	fake.StreamOutOCIStub = nil
+	fake.streamOutOCIReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) StreamOutOCIReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.streamOutOCIMutex.Lock()
+	defer fake.streamOutOCIMutex.Unlock()
+	fake.StreamOutOCIStub = nil
+	if fake.streamOutOCIReturnsOnCall == nil {
+		fake.streamOutOCIReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.streamOutOCIReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeVolume) StreamP2pOut(arg1 context.Context, arg2 string, arg3 string, arg4 baggageclaim.Encoding) error {
 	fake.streamP2pOutMutex.Lock()
 	ret, specificReturn := fake.streamP2pOutReturnsOnCall[len(fake.streamP2pOutArgsForCall)]
@@ -736,15 +1889,16 @@ func (fake *FakeVolume) StreamP2pOut(arg1 context.Context, arg2 string, arg3 str
 		arg3 string
 		arg4 baggageclaim.Encoding
 	}{arg1, arg2, arg3, arg4})
+	stub := fake.StreamP2pOutStub
+	fakeReturns := fake.streamP2pOutReturns
 	fake.recordInvocation("StreamP2pOut", []interface{}{arg1, arg2, arg3, arg4})
 	fake.streamP2pOutMutex.Unlock()
-	if fake.StreamP2pOutStub != nil {
-		return fake.StreamP2pOutStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.streamP2pOutReturns
 	return fakeReturns.result1
 }
 
@@ -790,31 +1944,187 @@ func (fake *FakeVolume) StreamP2pOutReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeVolume) Tree(arg1 context.Context, arg2 string, arg3 int) ([]baggageclaim.TreeEntry, error) {
+	fake.treeMutex.Lock()
+	ret, specificReturn := fake.treeReturnsOnCall[len(fake.treeArgsForCall)]
+	fake.treeArgsForCall = append(fake.treeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.TreeStub
+	fakeReturns := fake.treeReturns
+	fake.recordInvocation("Tree", []interface{}{arg1, arg2, arg3})
+	fake.treeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVolume) TreeCallCount() int {
+	fake.treeMutex.RLock()
+	defer fake.treeMutex.RUnlock()
+	return len(fake.treeArgsForCall)
+}
+
+func (fake *FakeVolume) TreeCalls(stub func(context.Context, string, int) ([]baggageclaim.TreeEntry, error)) {
+	fake.treeMutex.Lock()
+	defer fake.treeMutex.Unlock()
+	fake.TreeStub = stub
+}
+
+func (fake *FakeVolume) TreeArgsForCall(i int) (context.Context, string, int) {
+	fake.treeMutex.RLock()
+	defer fake.treeMutex.RUnlock()
+	argsForCall := fake.treeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeVolume) TreeReturns(result1 []baggageclaim.TreeEntry, result2 error) {
+	fake.treeMutex.Lock()
+	defer fake.treeMutex.Unlock()
+	fake.TreeStub = nil
+	fake.treeReturns = struct {
+		result1 []baggageclaim.TreeEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) TreeReturnsOnCall(i int, result1 []baggageclaim.TreeEntry, result2 error) {
+	fake.treeMutex.Lock()
+	defer fake.treeMutex.Unlock()
+	fake.TreeStub = nil
+	if fake.treeReturnsOnCall == nil {
+		fake.treeReturnsOnCall = make(map[int]struct {
+			result1 []baggageclaim.TreeEntry
+			result2 error
+		})
+	}
+	fake.treeReturnsOnCall[i] = struct {
+		result1 []baggageclaim.TreeEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) Usage() (baggageclaim.VolumeUsage, error) {
+	fake.usageMutex.Lock()
+	ret, specificReturn := fake.usageReturnsOnCall[len(fake.usageArgsForCall)]
+	fake.usageArgsForCall = append(fake.usageArgsForCall, struct {
+	}{})
+	stub := fake.UsageStub
+	fakeReturns := fake.usageReturns
+	fake.recordInvocation("Usage", []interface{}{})
+	fake.usageMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVolume) UsageCallCount() int {
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
+	return len(fake.usageArgsForCall)
+}
+
+func (fake *FakeVolume) UsageCalls(stub func() (baggageclaim.VolumeUsage, error)) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = stub
+}
+
+func (fake *FakeVolume) UsageReturns(result1 baggageclaim.VolumeUsage, result2 error) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = nil
+	fake.usageReturns = struct {
+		result1 baggageclaim.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVolume) UsageReturnsOnCall(i int, result1 baggageclaim.VolumeUsage, result2 error) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = nil
+	if fake.usageReturnsOnCall == nil {
+		fake.usageReturnsOnCall = make(map[int]struct {
+			result1 baggageclaim.VolumeUsage
+			result2 error
+		})
+	}
+	fake.usageReturnsOnCall[i] = struct {
+		result1 baggageclaim.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeVolume) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
 	fake.destroyMutex.RLock()
 	defer fake.destroyMutex.RUnlock()
+	fake.destroySnapshotMutex.RLock()
+	defer fake.destroySnapshotMutex.RUnlock()
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	fake.digestMutex.RLock()
+	defer fake.digestMutex.RUnlock()
 	fake.getPrivilegedMutex.RLock()
 	defer fake.getPrivilegedMutex.RUnlock()
 	fake.getStreamInP2pUrlMutex.RLock()
 	defer fake.getStreamInP2pUrlMutex.RUnlock()
 	fake.handleMutex.RLock()
 	defer fake.handleMutex.RUnlock()
+	fake.openFileMutex.RLock()
+	defer fake.openFileMutex.RUnlock()
 	fake.pathMutex.RLock()
 	defer fake.pathMutex.RUnlock()
 	fake.propertiesMutex.RLock()
 	defer fake.propertiesMutex.RUnlock()
+	fake.readFileMutex.RLock()
+	defer fake.readFileMutex.RUnlock()
+	fake.readOnlyMutex.RLock()
+	defer fake.readOnlyMutex.RUnlock()
+	fake.renameMutex.RLock()
+	defer fake.renameMutex.RUnlock()
+	fake.restoreSnapshotMutex.RLock()
+	defer fake.restoreSnapshotMutex.RUnlock()
 	fake.setPrivilegedMutex.RLock()
 	defer fake.setPrivilegedMutex.RUnlock()
 	fake.setPropertyMutex.RLock()
 	defer fake.setPropertyMutex.RUnlock()
+	fake.setReadOnlyMutex.RLock()
+	defer fake.setReadOnlyMutex.RUnlock()
+	fake.setTTLMutex.RLock()
+	defer fake.setTTLMutex.RUnlock()
+	fake.snapshotsMutex.RLock()
+	defer fake.snapshotsMutex.RUnlock()
 	fake.streamInMutex.RLock()
 	defer fake.streamInMutex.RUnlock()
+	fake.streamInResumableMutex.RLock()
+	defer fake.streamInResumableMutex.RUnlock()
 	fake.streamOutMutex.RLock()
 	defer fake.streamOutMutex.RUnlock()
+	fake.streamOutDiffMutex.RLock()
+	defer fake.streamOutDiffMutex.RUnlock()
+	fake.streamOutOCIMutex.RLock()
+	defer fake.streamOutOCIMutex.RUnlock()
 	fake.streamP2pOutMutex.RLock()
 	defer fake.streamP2pOutMutex.RUnlock()
+	fake.treeMutex.RLock()
+	defer fake.treeMutex.RUnlock()
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -4,14 +4,33 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 )
 
 type Encoding string
 
+type VolumeEventType string
+
+const (
+	VolumeCreated           VolumeEventType = "created"
+	VolumeInitialized       VolumeEventType = "initialized"
+	VolumePropertySet       VolumeEventType = "property-set"
+	VolumePrivilegedChanged VolumeEventType = "privileged-changed"
+	VolumeDestroyed         VolumeEventType = "destroyed"
+	VolumeStreamedIn        VolumeEventType = "streamed-in"
+	VolumeQuarantined       VolumeEventType = "quarantined"
+	VolumeRestored          VolumeEventType = "restored"
+	VolumeMadeReadOnly      VolumeEventType = "made-read-only"
+	VolumeRenamed           VolumeEventType = "renamed"
+)
+
+const IdentityEncoding Encoding = "identity"
 const GzipEncoding Encoding = "gzip"
 const ZstdEncoding Encoding = "zstd"
+const Lz4Encoding Encoding = "lz4"
+const XzEncoding Encoding = "xz"
 
 //go:generate counterfeiter . Client
 
@@ -39,6 +58,14 @@ type Client interface {
 	// could not be listed.
 	ListVolumes(lager.Logger, VolumeProperties) (Volumes, error)
 
+	// ListVolumesWithUsage behaves like ListVolumes, but additionally has the
+	// server report the disk usage of each volume. The reported usage is
+	// returned by each volume's Usage method without a further request.
+	//
+	// You are required to pass in a logger to the call to retain context across
+	// the library boundary.
+	ListVolumesWithUsage(lager.Logger, VolumeProperties) (Volumes, error)
+
 	// LookupVolume finds a volume that is present on the server. It takes a
 	// string that corresponds to the Handle of the Volume.
 	//
@@ -93,16 +120,100 @@ type Volume interface {
 	// GetPrivileged returns a bool indicating if the volume is privileged.
 	GetPrivileged() (bool, error)
 
+	// SetTTL changes how long from now the volume may live before the server
+	// destroys it, rounded up to the second. A TTL of zero removes any expiry.
+	SetTTL(time.Duration) error
+
+	// SetReadOnly seals the volume's contents. Streaming in, changing the
+	// volume's privileges and restoring its snapshots fail with
+	// ErrVolumeIsReadOnly from then on, though its properties may still be
+	// set. A read-only volume cannot be made writable again.
+	SetReadOnly() error
+
+	// ReadOnly returns whether the volume's contents have been sealed.
+	ReadOnly() (bool, error)
+
+	// Usage returns the amount of disk space and inodes consumed by the
+	// volume, split into what is exclusive to it and what is shared with its
+	// parent.
+	Usage() (VolumeUsage, error)
+
+	// Digest returns a deterministic hash of the volume's contents. The server
+	// records the digests of volumes that were imported, streamed in to or
+	// made read-only, so that they can be copied by giving the digest to a
+	// DedupeStrategy.
+	Digest() (string, error)
+
 	// StreamIn calls BaggageClaim API endpoint in order to initialize tarStream
 	// to stream the contents of the Reader into this volume at the specified path.
 	StreamIn(ctx context.Context, path string, encoding Encoding, tarStream io.Reader) error
 
+	// StreamInResumable is like StreamIn, but sends the stream as an upload
+	// session which, if the connection drops, is resumed from however much
+	// the server received rather than failing. The server keeps what it has
+	// received on disk until the upload finishes, and streams which cannot
+	// be seeked have their last 32MB kept in memory so they can be sent again.
+	StreamInResumable(ctx context.Context, path string, encoding Encoding, tarStream io.Reader) error
+
 	StreamOut(ctx context.Context, path string, encoding Encoding) (io.ReadCloser, error)
 
+	// Diff lists what has been added, modified or deleted in the volume since
+	// it was created as a copy-on-write child of its parent.
+	// ErrVolumeHasNoParent is returned if it has no parent.
+	Diff(ctx context.Context) ([]Change, error)
+
+	// StreamOutDiff is like StreamOut, but only includes what Diff lists.
+	// Deleted paths are included as empty ".wh."-prefixed whiteout files, so
+	// a LayerStrategy can apply the stream to a copy of the parent.
+	StreamOutDiff(ctx context.Context, path string, encoding Encoding) (io.ReadCloser, error)
+
+	// StreamOutOCI streams out the volume as a tar stream of an OCI image
+	// layout. If chain is set the image has a layer for each volume in the
+	// volume's copy-on-write chain rather than a single layer.
+	StreamOutOCI(ctx context.Context, chain bool, encoding Encoding) (io.ReadCloser, error)
+
+	// OpenFile returns the contents of a single file in the volume, without
+	// archiving it as StreamOut would. ErrFileNotFound is returned if there is
+	// no such file.
+	OpenFile(ctx context.Context, path string) (io.ReadCloser, error)
+
+	// ReadFile is like OpenFile, but reads the whole file into memory.
+	ReadFile(ctx context.Context, path string) ([]byte, error)
+
+	// Tree describes the file at path and, if it is a directory, everything
+	// beneath it up to depth levels down. A depth of zero describes only the
+	// file itself. Trees that are too deep or hold too many entries are
+	// refused; ask for less and descend into directories separately.
+	Tree(ctx context.Context, path string, depth int) ([]TreeEntry, error)
+
 	// Properties returns the currently set properties for a Volume. An error is
 	// returned if these could not be retrieved.
 	Properties() (VolumeProperties, error)
 
+	// CreateSnapshot takes a read-only copy of the volume's contents which it
+	// can later be restored to. A handle is generated for the snapshot if none
+	// is given. ErrSnapshotAlreadyExists is returned if the handle is taken.
+	CreateSnapshot(ctx context.Context, handle string) (Snapshot, error)
+
+	// Snapshots lists the volume's snapshots, oldest first.
+	Snapshots() ([]Snapshot, error)
+
+	// RestoreSnapshot replaces the volume's contents with the snapshot's.
+	// ErrVolumeHasChildren is returned if the volume has copy-on-write
+	// children, which may be layered on top of its contents. The volume must
+	// not be in use, e.g. mounted into a container, as its old contents are
+	// removed; ErrVolumeInUse is returned where this can be detected.
+	RestoreSnapshot(ctx context.Context, handle string) error
+
+	// DestroySnapshot removes a snapshot. Snapshots are also removed when
+	// the volume is destroyed.
+	DestroySnapshot(ctx context.Context, handle string) error
+
+	// Rename moves the volume to the given handle, after which the Volume
+	// refers to it by its new handle and path. Copy-on-write children follow
+	// it. ErrVolumeAlreadyExists is returned if the handle is taken.
+	Rename(ctx context.Context, handle string) error
+
 	// Destroy removes the volume and its contents. Note that it does not
 	// safeguard against child volumes being present.
 	Destroy() error
@@ -147,6 +258,56 @@ func (v Volumes) Handles() []string {
 // VolumeProperties represents the properties for a particular volume.
 type VolumeProperties map[string]string
 
+// VolumeUsage represents the disk space consumed by a volume.
+type VolumeUsage struct {
+	// ExclusiveBytes is the number of bytes only referenced by this volume;
+	// roughly what would be reclaimed by destroying it.
+	ExclusiveBytes uint64 `json:"exclusive_bytes"`
+
+	// SharedBytes is the number of bytes the volume shares with its parent
+	// volume(s).
+	SharedBytes uint64 `json:"shared_bytes"`
+
+	// Inodes is the number of files, directories and links in the volume.
+	Inodes uint64 `json:"inodes"`
+}
+
+// TreeEntry describes a file within a volume.
+type TreeEntry struct {
+	// Name is the path of the entry relative to the path that was listed,
+	// which is itself named ".".
+	Name string `json:"name"`
+
+	// Type is one of "file", "directory", "symlink", "fifo", "socket",
+	// "char-device" or "block-device".
+	Type string `json:"type"`
+
+	Size int64 `json:"size"`
+
+	// Mode holds the permission bits, along with the setuid, setgid and
+	// sticky bits, e.g. 04755.
+	Mode uint32 `json:"mode"`
+
+	// UID and GID are the ownership as seen from within the volume.
+	UID int `json:"uid"`
+	GID int `json:"gid"`
+
+	ModTime time.Time `json:"mtime"`
+
+	// LinkTarget is set for symlinks.
+	LinkTarget string `json:"link_target,omitempty"`
+}
+
+// Change describes a path that differs between a volume and its parent.
+type Change struct {
+	// Path is relative to the root of the volume.
+	Path string `json:"path"`
+
+	// Kind is one of "added", "modified" or "deleted". Nothing beneath a
+	// deleted directory is listed.
+	Kind string `json:"kind"`
+}
+
 // VolumeSpec is a specification representing the kind of volume that you'd
 // like from the server.
 type VolumeSpec struct {
@@ -161,6 +322,20 @@ type VolumeSpec struct {
 	// translation of the files in the volume so that they can be read by a
 	// non-privileged user.
 	Privileged bool
+
+	// QuotaBytes limits how many bytes may be written to the volume, not
+	// counting data shared with a parent volume. Zero means no limit. Creating
+	// a volume with a quota fails if the server's driver cannot enforce it.
+	QuotaBytes uint64
+
+	// TTL is how long the volume may live before the server destroys it, along
+	// with any of its children. Zero means the volume lives until it is
+	// destroyed. It is rounded up to the second.
+	TTL time.Duration
+
+	// ReadOnly seals the volume once its strategy has populated it, as with
+	// Volume.SetReadOnly.
+	ReadOnly bool
 }
 
 type Strategy interface {
@@ -212,6 +387,81 @@ func (strategy COWStrategy) Encode() *json.RawMessage {
 	return &msg
 }
 
+// LayerStrategy creates a Copy-On-Write layer of another Volume and applies
+// the changes in a layer to it, such as one produced by Volume.StreamOutDiff.
+type LayerStrategy struct {
+	// The parent volume that we should base the new volume on.
+	Parent Volume
+
+	// The location on the host of the layer, a .tar.gz file in which deleted
+	// paths are recorded as ".wh."-prefixed whiteout files and directories
+	// replacing those in the parent contain a ".wh..wh..opq" file.
+	Path string
+}
+
+func (strategy LayerStrategy) Encode() *json.RawMessage {
+	payload, _ := json.Marshal(struct {
+		Type   string `json:"type"`
+		Volume string `json:"volume"`
+		Path   string `json:"path"`
+	}{
+		Type:   "layer",
+		Volume: strategy.Parent.Handle(),
+		Path:   strategy.Path,
+	})
+
+	msg := json.RawMessage(payload)
+	return &msg
+}
+
+// OCIStrategy creates a Volume from an image in an OCI image layout on the
+// host, such as one produced by Volume.StreamOutOCI and unpacked, applying
+// each of its gzipped layers in turn.
+type OCIStrategy struct {
+	// The location on the host of the image layout directory.
+	Path string
+
+	// The name the image is annotated with in the layout's index. It may be
+	// left empty if the layout holds a single image.
+	Ref string
+}
+
+func (strategy OCIStrategy) Encode() *json.RawMessage {
+	payload, _ := json.Marshal(struct {
+		Type string `json:"type"`
+		Path string `json:"path"`
+		Ref  string `json:"ref,omitempty"`
+	}{
+		Type: "oci",
+		Path: strategy.Path,
+		Ref:  strategy.Ref,
+	})
+
+	msg := json.RawMessage(payload)
+	return &msg
+}
+
+// DedupeStrategy creates a Copy-On-Write layer of an existing volume with the
+// given digest. If there is no such volume, creating the volume fails with
+// ErrDigestNotFound.
+type DedupeStrategy struct {
+	// The digest of the content, as returned by Volume.Digest.
+	Digest string
+}
+
+func (strategy DedupeStrategy) Encode() *json.RawMessage {
+	payload, _ := json.Marshal(struct {
+		Type   string `json:"type"`
+		Digest string `json:"digest"`
+	}{
+		Type:   "dedupe",
+		Digest: strategy.Digest,
+	})
+
+	msg := json.RawMessage(payload)
+	return &msg
+}
+
 // EmptyStrategy created a new empty volume.
 type EmptyStrategy struct{}
 
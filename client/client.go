@@ -7,8 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"code.cloudfoundry.org/lager"
@@ -23,6 +26,12 @@ var ErrVolumeDeletion = errors.New("failed-to-delete-volume")
 
 type Client interface {
 	baggageclaim.Client
+
+	Events(ctx context.Context) <-chan baggageclaim.VolumeEvent
+
+	// ListVolumePages lists the volumes a page at a time, fetching each page
+	// as the iterator reaches it.
+	ListVolumePages(lager.Logger, ListVolumesOptions) *VolumeIterator
 }
 
 type client struct {
@@ -34,7 +43,18 @@ type client struct {
 	givenHttpClient *http.Client
 }
 
+// New returns a Client for the API at apiURL, which may be a unix:// URL of
+// the path of a Unix socket. For a Unix socket, nestedRoundTripper must be an
+// *http.Transport or nil, which is made to dial the socket; with any other,
+// every request fails.
 func New(apiURL string, nestedRoundTripper http.RoundTripper) Client {
+	if strings.HasPrefix(apiURL, unixURLPrefix) {
+		socketPath := strings.TrimPrefix(apiURL, unixURLPrefix)
+
+		apiURL = unixAPIURL
+		nestedRoundTripper = unixRoundTripper(socketPath, nestedRoundTripper)
+	}
+
 	return &client{
 		requestGenerator: rata.NewRequestGenerator(apiURL, baggageclaim.Routes),
 
@@ -73,10 +93,13 @@ func (c *client) CreateVolume(logger lager.Logger, handle string, volumeSpec bag
 
 	buffer := &bytes.Buffer{}
 	json.NewEncoder(buffer).Encode(baggageclaim.VolumeRequest{
-		Handle:     handle,
-		Strategy:   strategy.Encode(),
-		Properties: volumeSpec.Properties,
-		Privileged: volumeSpec.Privileged,
+		Handle:       handle,
+		Strategy:     strategy.Encode(),
+		Properties:   volumeSpec.Properties,
+		Privileged:   volumeSpec.Privileged,
+		QuotaBytes:   volumeSpec.QuotaBytes,
+		TTLInSeconds: ttlInSeconds(volumeSpec.TTL),
+		ReadOnly:     volumeSpec.ReadOnly,
 	})
 
 	request, _ := c.requestGenerator.CreateRequest(baggageclaim.CreateVolumeAsync, nil, buffer)
@@ -117,50 +140,104 @@ func (c *client) CreateVolume(logger lager.Logger, handle string, volumeSpec bag
 }
 
 func (c *client) ListVolumes(logger lager.Logger, properties baggageclaim.VolumeProperties) (baggageclaim.Volumes, error) {
-	if properties == nil {
-		properties = baggageclaim.VolumeProperties{}
+	return c.listVolumes(logger, properties, false)
+}
+
+func (c *client) ListVolumesWithUsage(logger lager.Logger, properties baggageclaim.VolumeProperties) (baggageclaim.Volumes, error) {
+	return c.listVolumes(logger, properties, true)
+}
+
+func (c *client) listVolumes(logger lager.Logger, properties baggageclaim.VolumeProperties, includeUsage bool) (baggageclaim.Volumes, error) {
+	queryString := url.Values{}
+	for key, val := range properties {
+		queryString.Add(key, val)
 	}
 
-	request, err := c.requestGenerator.CreateRequest(baggageclaim.ListVolumes, nil, nil)
+	if includeUsage {
+		queryString.Set(baggageclaim.IncludeUsageQueryKey, "true")
+	}
+
+	volumesResponse, _, err := c.requestVolumes(logger, queryString)
 	if err != nil {
 		return nil, err
 	}
 
-	queryString := request.URL.Query()
-	for key, val := range properties {
-		queryString.Add(key, val)
+	var volumes baggageclaim.Volumes
+	for _, vr := range volumesResponse {
+		v := c.newVolume(logger, vr)
+		volumes = append(volumes, v)
+	}
+
+	return volumes, nil
+}
+
+// requestVolumes lists the volumes matching the query, returning the query for
+// the next page if the listing was cut short by a limit.
+func (c *client) requestVolumes(logger lager.Logger, queryString url.Values) ([]baggageclaim.VolumeResponse, url.Values, error) {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.ListVolumes, nil, nil)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	request.URL.RawQuery = queryString.Encode()
 
 	response, err := c.httpClient(logger).Do(request)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer response.Body.Close()
 
 	if response.StatusCode != 200 {
-		return nil, getError(response)
+		return nil, nil, getError(response)
 	}
 
 	if header := response.Header.Get("Content-Type"); header != "application/json" {
-		return nil, fmt.Errorf("unexpected content-type of: %s", header)
+		return nil, nil, fmt.Errorf("unexpected content-type of: %s", header)
 	}
 
 	var volumesResponse []baggageclaim.VolumeResponse
 	err = json.NewDecoder(response.Body).Decode(&volumesResponse)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var volumes baggageclaim.Volumes
-	for _, vr := range volumesResponse {
-		v := c.newVolume(logger, vr)
-		volumes = append(volumes, v)
+	next, err := nextPageQuery(response.Header)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return volumes, nil
+	return volumesResponse, next, nil
+}
+
+// nextPageQuery returns the query of the Link header whose rel is "next", or
+// nil if there is none.
+func nextPageQuery(header http.Header) (url.Values, error) {
+	for _, links := range header.Values("Link") {
+		for _, link := range strings.Split(links, ",") {
+			params := strings.Split(link, ";")
+
+			target := strings.TrimSpace(params[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range params[1:] {
+				if strings.TrimSpace(param) != `rel="next"` {
+					continue
+				}
+
+				next, err := url.Parse(strings.Trim(target, "<>"))
+				if err != nil {
+					return nil, fmt.Errorf("malformed link to the next page: %s", err)
+				}
+
+				return next.Query(), nil
+			}
+		}
+	}
+
+	return nil, nil
 }
 
 func (c *client) LookupVolume(logger lager.Logger, handle string) (baggageclaim.Volume, bool, error) {
@@ -231,6 +308,7 @@ func (c *client) newVolume(logger lager.Logger, apiVolume baggageclaim.VolumeRes
 
 		handle: apiVolume.Handle,
 		path:   apiVolume.Path,
+		usage:  apiVolume.Usage,
 
 		bcClient: c,
 	}
@@ -270,6 +348,12 @@ func (c *client) getStreamInP2pUrl(ctx context.Context, logger lager.Logger, des
 		return "", err
 	}
 
+	// ask for the whole stream-in url, so that it can be signed
+	request.URL.RawQuery = url.Values{
+		"handle": []string{destHandle},
+		"path":   []string{path},
+	}.Encode()
+
 	request = request.WithContext(ctx)
 
 	response, err := c.httpClient(logger).Do(request)
@@ -283,19 +367,25 @@ func (c *client) getStreamInP2pUrl(ctx context.Context, logger lager.Logger, des
 		return "", err
 	}
 
-	respBytes := make([]byte, 1024)
-	n, err := response.Body.Read(respBytes)
-	if err != nil && !(n > 0 && err == io.EOF) {
+	respBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
 		return "", err
 	}
-	respBytes = respBytes[:n]
 
 	destUrl, err := url.Parse(string(respBytes))
 	if err != nil {
 		return "", err
 	}
 
-	// Then build a StreamIn URL and replace with dest worker's host.
+	if destUrl.Path != "" {
+		// the query is left out of the logs as it may be signed
+		logger.Debug("get-stream-in-p2p-url", lager.Data{"url": destUrl.Scheme + "://" + destUrl.Host + destUrl.Path})
+		return destUrl.String(), nil
+	}
+
+	// Older servers only return their address.
+
+	// So build a StreamIn URL and replace with dest worker's host.
 	streamInRequest, err := c.requestGenerator.CreateRequest(baggageclaim.StreamIn, rata.Params{
 		"handle": destHandle,
 	}, nil)
@@ -338,6 +428,259 @@ func (c *client) streamOut(ctx context.Context, logger lager.Logger, srcHandle s
 	return response.Body, nil
 }
 
+func (c *client) streamOutDiff(ctx context.Context, logger lager.Logger, srcHandle string, encoding baggageclaim.Encoding, path string) (io.ReadCloser, error) {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.StreamOut, rata.Params{
+		"handle": srcHandle,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.URL.RawQuery = url.Values{
+		"path": []string{path},
+		"diff": []string{"true"},
+	}.Encode()
+	request.Header.Set("Accept-Encoding", string(encoding))
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		return nil, getError(response)
+	}
+
+	return response.Body, nil
+}
+
+func (c *client) streamOutOCI(ctx context.Context, logger lager.Logger, srcHandle string, encoding baggageclaim.Encoding, chain bool) (io.ReadCloser, error) {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.StreamOut, rata.Params{
+		"handle": srcHandle,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.URL.RawQuery = url.Values{
+		"format": []string{"oci"},
+		"chain":  []string{strconv.FormatBool(chain)},
+	}.Encode()
+	request.Header.Set("Accept-Encoding", string(encoding))
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		return nil, getError(response)
+	}
+
+	return response.Body, nil
+}
+
+func (c *client) getDiff(ctx context.Context, logger lager.Logger, handle string) ([]baggageclaim.Change, error) {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.GetDiff, rata.Params{
+		"handle": handle,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, getError(response)
+	}
+
+	var changes []baggageclaim.Change
+	err = json.NewDecoder(response.Body).Decode(&changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (c *client) renameVolume(ctx context.Context, logger lager.Logger, handle string, newHandle string) (baggageclaim.VolumeResponse, error) {
+	buffer := &bytes.Buffer{}
+	json.NewEncoder(buffer).Encode(baggageclaim.RenameRequest{
+		Handle: newHandle,
+	})
+
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.RenameVolume, rata.Params{
+		"handle": handle,
+	}, buffer)
+	if err != nil {
+		return baggageclaim.VolumeResponse{}, err
+	}
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return baggageclaim.VolumeResponse{}, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return baggageclaim.VolumeResponse{}, getError(response)
+	}
+
+	var volumeResponse baggageclaim.VolumeResponse
+	err = json.NewDecoder(response.Body).Decode(&volumeResponse)
+	if err != nil {
+		return baggageclaim.VolumeResponse{}, err
+	}
+
+	return volumeResponse, nil
+}
+
+func (c *client) createSnapshot(ctx context.Context, logger lager.Logger, handle string, snapshotHandle string) (baggageclaim.Snapshot, error) {
+	buffer := &bytes.Buffer{}
+	json.NewEncoder(buffer).Encode(baggageclaim.SnapshotRequest{
+		Handle: snapshotHandle,
+	})
+
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.CreateSnapshot, rata.Params{
+		"handle": handle,
+	}, buffer)
+	if err != nil {
+		return baggageclaim.Snapshot{}, err
+	}
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return baggageclaim.Snapshot{}, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated {
+		return baggageclaim.Snapshot{}, getError(response)
+	}
+
+	var snapshot baggageclaim.Snapshot
+	err = json.NewDecoder(response.Body).Decode(&snapshot)
+	if err != nil {
+		return baggageclaim.Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+func (c *client) restoreSnapshot(ctx context.Context, logger lager.Logger, handle string, snapshotHandle string) error {
+	return c.snapshotRequest(ctx, logger, baggageclaim.RestoreSnapshot, handle, snapshotHandle)
+}
+
+func (c *client) destroySnapshot(ctx context.Context, logger lager.Logger, handle string, snapshotHandle string) error {
+	return c.snapshotRequest(ctx, logger, baggageclaim.DestroySnapshot, handle, snapshotHandle)
+}
+
+// snapshotRequest makes a request about an existing snapshot which responds
+// with no content.
+func (c *client) snapshotRequest(ctx context.Context, logger lager.Logger, route string, handle string, snapshotHandle string) error {
+	request, err := c.requestGenerator.CreateRequest(route, rata.Params{
+		"handle":   handle,
+		"snapshot": snapshotHandle,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent {
+		return getError(response)
+	}
+
+	return nil
+}
+
+func (c *client) openFile(ctx context.Context, logger lager.Logger, handle string, path string) (io.ReadCloser, error) {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.GetFile, rata.Params{
+		"handle": handle,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.URL.RawQuery = url.Values{"path": []string{path}}.Encode()
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		return nil, getError(response)
+	}
+
+	return response.Body, nil
+}
+
+func (c *client) getTree(ctx context.Context, logger lager.Logger, handle string, path string, depth int) ([]baggageclaim.TreeEntry, error) {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.GetTree, rata.Params{
+		"handle": handle,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.URL.RawQuery = url.Values{
+		"path":  []string{path},
+		"depth": []string{strconv.Itoa(depth)},
+	}.Encode()
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, getError(response)
+	}
+
+	var entries []baggageclaim.TreeEntry
+	err = json.NewDecoder(response.Body).Decode(&entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
 func (c *client) streamP2pOut(ctx context.Context, logger lager.Logger, srcHandle string, encoding baggageclaim.Encoding, path string, streamInURL string) error {
 	request, err := c.requestGenerator.CreateRequest(baggageclaim.StreamP2pOut, rata.Params{
 		"handle": srcHandle,
@@ -376,6 +719,50 @@ func getError(response *http.Response) error {
 		return baggageclaim.ErrFileNotFound
 	}
 
+	if errorResponse.Message == api.ErrStreamInQuotaExceeded.Error() {
+		return baggageclaim.ErrQuotaExceeded
+	}
+
+	if errorResponse.Message == api.ErrCreateVolumeDigestNotFound.Error() {
+		return baggageclaim.ErrDigestNotFound
+	}
+
+	if errorResponse.Message == api.ErrVolumeHasNoParent.Error() {
+		return baggageclaim.ErrVolumeHasNoParent
+	}
+
+	if errorResponse.Message == api.ErrSnapshotNotFound.Error() {
+		return baggageclaim.ErrSnapshotNotFound
+	}
+
+	if errorResponse.Message == api.ErrSnapshotAlreadyExists.Error() {
+		return baggageclaim.ErrSnapshotAlreadyExists
+	}
+
+	if errorResponse.Message == api.ErrVolumeHasChildren.Error() {
+		return baggageclaim.ErrVolumeHasChildren
+	}
+
+	if errorResponse.Message == api.ErrVolumeInUse.Error() {
+		return baggageclaim.ErrVolumeInUse
+	}
+
+	if errorResponse.Message == api.ErrVolumeIsReadOnly.Error() {
+		return baggageclaim.ErrVolumeIsReadOnly
+	}
+
+	if errorResponse.Message == api.ErrVolumeAlreadyExists.Error() {
+		return baggageclaim.ErrVolumeAlreadyExists
+	}
+
+	if errorResponse.Message == api.ErrUnauthorized.Error() {
+		return baggageclaim.ErrUnauthorized
+	}
+
+	if errorResponse.Message == api.ErrForbidden.Error() {
+		return baggageclaim.ErrForbidden
+	}
+
 	if response.StatusCode == 404 {
 		return baggageclaim.ErrVolumeNotFound
 	}
@@ -469,6 +856,123 @@ func (c *client) getPrivileged(logger lager.Logger, handle string) (bool, error)
 	return privileged, nil
 }
 
+// ttlInSeconds rounds ttl up to whole seconds, as the server takes a TTL of
+// zero to mean that the volume never expires.
+func ttlInSeconds(ttl time.Duration) uint {
+	if ttl <= 0 {
+		return 0
+	}
+
+	return uint((ttl + time.Second - 1) / time.Second)
+}
+
+func (c *client) setTTL(logger lager.Logger, handle string, ttl time.Duration) error {
+	buffer := &bytes.Buffer{}
+	json.NewEncoder(buffer).Encode(baggageclaim.TTLRequest{
+		Value: ttlInSeconds(ttl),
+	})
+
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.SetTTL, rata.Params{
+		"handle": handle,
+	}, buffer)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Add("Content-type", "application/json")
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != 204 {
+		return getError(response)
+	}
+
+	return nil
+}
+
+func (c *client) setReadOnly(logger lager.Logger, handle string) error {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.SetReadOnly, rata.Params{
+		"handle": handle,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != 204 {
+		return getError(response)
+	}
+
+	return nil
+}
+
+func (c *client) getUsage(logger lager.Logger, handle string) (baggageclaim.VolumeUsage, error) {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.GetUsage, rata.Params{
+		"handle": handle,
+	}, nil)
+	if err != nil {
+		return baggageclaim.VolumeUsage{}, err
+	}
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return baggageclaim.VolumeUsage{}, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != 200 {
+		return baggageclaim.VolumeUsage{}, getError(response)
+	}
+
+	var usage baggageclaim.VolumeUsage
+	err = json.NewDecoder(response.Body).Decode(&usage)
+	if err != nil {
+		return baggageclaim.VolumeUsage{}, err
+	}
+
+	return usage, nil
+}
+
+func (c *client) getDigest(logger lager.Logger, handle string) (string, error) {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.GetDigest, rata.Params{
+		"handle": handle,
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != 200 {
+		return "", getError(response)
+	}
+
+	var digestResponse baggageclaim.DigestResponse
+	err = json.NewDecoder(response.Body).Decode(&digestResponse)
+	if err != nil {
+		return "", err
+	}
+
+	return digestResponse.Digest, nil
+}
+
 func (c *client) setPrivileged(logger lager.Logger, handle string, privileged bool) error {
 	buffer := &bytes.Buffer{}
 	json.NewEncoder(buffer).Encode(baggageclaim.PrivilegedRequest{
@@ -47,10 +47,10 @@ var _ = Describe("baggageclaim http client", func() {
 					ghttp.CombineHandlers(
 						ghttp.VerifyRequest("GET", "/volumes-async/some-volume"),
 						ghttp.RespondWithJSONEncoded(http.StatusOK, volume.Volume{
-							"some-volume",
-							"/some/path",
-							map[string]string{},
-							false,
+							Handle:     "some-volume",
+							Path:       "/some/path",
+							Properties: map[string]string{},
+							Privileged: false,
 						}),
 					),
 					ghttp.CombineHandlers(
@@ -1,8 +1,15 @@
 package client_test
 
 import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/client"
 
 	"testing"
 )
@@ -11,3 +18,22 @@ func TestClient(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Client Suite")
 }
+
+// lookupVolume has the server hand out the volume and looks it up through a
+// client of the server, for specs about what is then done with the volume.
+func lookupVolume(server *ghttp.Server, response baggageclaim.VolumeResponse) baggageclaim.Volume {
+	server.AppendHandlers(
+		ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", "/volumes/"+response.Handle),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, response),
+		),
+	)
+
+	c := client.New(server.URL(), http.DefaultTransport)
+
+	volume, found, err := c.LookupVolume(lagertest.NewTestLogger("test"), response.Handle)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	ExpectWithOffset(1, found).To(BeTrue())
+
+	return volume
+}
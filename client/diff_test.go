@@ -0,0 +1,87 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/api"
+)
+
+var _ = Describe("diffing a volume against its parent", func() {
+	var (
+		gServer  *ghttp.Server
+		bcVolume baggageclaim.Volume
+	)
+
+	BeforeEach(func() {
+		gServer = ghttp.NewServer()
+		bcVolume = lookupVolume(gServer, baggageclaim.VolumeResponse{Handle: "some-volume"})
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+	})
+
+	It("lists the changes", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes/some-volume/diff"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.Change{
+					{Path: "some-dir", Kind: "modified"},
+					{Path: "some-dir/some-file", Kind: "added"},
+				}),
+			),
+		)
+
+		changes, err := bcVolume.Diff(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(changes).To(Equal([]baggageclaim.Change{
+			{Path: "some-dir", Kind: "modified"},
+			{Path: "some-dir/some-file", Kind: "added"},
+		}))
+	})
+
+	It("streams out the changes", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/volumes/some-volume/stream-out", "diff=true&path=some-dir"),
+				ghttp.VerifyHeaderKV("Accept-Encoding", string(baggageclaim.GzipEncoding)),
+				ghttp.RespondWith(http.StatusOK, "some-layer"),
+			),
+		)
+
+		stream, err := bcVolume.StreamOutDiff(context.Background(), "some-dir", baggageclaim.GzipEncoding)
+		Expect(err).ToNot(HaveOccurred())
+
+		defer stream.Close()
+
+		Expect(ioutil.ReadAll(stream)).To(Equal([]byte("some-layer")))
+	})
+
+	Context("when the volume has no parent", func() {
+		BeforeEach(func() {
+			body, err := json.Marshal(api.ErrorResponse{Message: api.ErrVolumeHasNoParent.Error()})
+			Expect(err).ToNot(HaveOccurred())
+
+			gServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/volumes/some-volume/diff"),
+					ghttp.RespondWith(http.StatusBadRequest, body),
+				),
+			)
+		})
+
+		It("returns ErrVolumeHasNoParent", func() {
+			_, err := bcVolume.Diff(context.Background())
+			Expect(err).To(Equal(baggageclaim.ErrVolumeHasNoParent))
+		})
+	})
+})
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+	"github.com/cenkalti/backoff"
+
+	"github.com/concourse/baggageclaim"
+)
+
+// Events streams volume lifecycle events from the server until ctx is done,
+// at which point the channel is closed. Dropped connections are re-established
+// with backoff; events that happen while disconnected are not replayed, so
+// consumers needing a complete picture should re-list volumes after a gap.
+//
+// A logger may be provided via lagerctx.
+func (c *client) Events(ctx context.Context) <-chan baggageclaim.VolumeEvent {
+	events := make(chan baggageclaim.VolumeEvent)
+
+	go func() {
+		defer close(events)
+
+		logger := lagerctx.WithSession(ctx, "events")
+
+		exponentialBackoff := backoff.NewExponentialBackOff()
+		exponentialBackoff.InitialInterval = 100 * time.Millisecond
+		exponentialBackoff.MaxInterval = 10 * time.Second
+		exponentialBackoff.MaxElapsedTime = 0
+
+		for {
+			received, err := c.streamEvents(ctx, logger, events)
+			if ctx.Err() != nil {
+				return
+			}
+
+			if received {
+				exponentialBackoff.Reset()
+			}
+
+			logger.Info("disconnected", lager.Data{"error": fmt.Sprintf("%v", err)})
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(exponentialBackoff.NextBackOff()):
+			}
+		}
+	}()
+
+	return events
+}
+
+// streamEvents forwards events from a single connection until it drops. It
+// reports whether any events were received so the caller can reset its
+// backoff.
+func (c *client) streamEvents(ctx context.Context, logger lager.Logger, events chan<- baggageclaim.VolumeEvent) (bool, error) {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.Events, nil, nil)
+	if err != nil {
+		return false, err
+	}
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return false, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return false, getError(response)
+	}
+
+	received := false
+
+	decoder := json.NewDecoder(response.Body)
+	for {
+		var event baggageclaim.VolumeEvent
+		err := decoder.Decode(&event)
+		if err != nil {
+			return received, err
+		}
+
+		received = true
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return received, ctx.Err()
+		}
+	}
+}
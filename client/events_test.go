@@ -0,0 +1,60 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("streaming volume events", func() {
+	var (
+		gServer    *ghttp.Server
+		ctx        context.Context
+		cancelFunc context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		gServer = ghttp.NewServer()
+		ctx, cancelFunc = context.WithCancel(context.Background())
+
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/events"),
+				ghttp.RespondWith(http.StatusOK, `{"type":"created","handle":"some-handle"}`+"\n"),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/events"),
+				ghttp.RespondWith(http.StatusOK, `{"type":"destroyed","handle":"some-handle"}`+"\n"),
+			),
+		)
+		gServer.SetAllowUnhandledRequests(true)
+	})
+
+	AfterEach(func() {
+		cancelFunc()
+		gServer.Close()
+	})
+
+	It("delivers events across reconnects and closes when the context is done", func() {
+		c := client.New(gServer.URL(), http.DefaultTransport)
+
+		events := c.Events(ctx)
+
+		var event baggageclaim.VolumeEvent
+		Eventually(events).Should(Receive(&event))
+		Expect(event.Type).To(Equal(baggageclaim.VolumeCreated))
+		Expect(event.Handle).To(Equal("some-handle"))
+
+		Eventually(events).Should(Receive(&event))
+		Expect(event.Type).To(Equal(baggageclaim.VolumeDestroyed))
+
+		cancelFunc()
+
+		Eventually(events).Should(BeClosed())
+	})
+})
@@ -0,0 +1,98 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/api"
+)
+
+var _ = Describe("reading files and listing volume contents", func() {
+	var (
+		gServer  *ghttp.Server
+		bcVolume baggageclaim.Volume
+	)
+
+	BeforeEach(func() {
+		gServer = ghttp.NewServer()
+		bcVolume = lookupVolume(gServer, baggageclaim.VolumeResponse{Handle: "some-volume"})
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+	})
+
+	Context("when the file exists", func() {
+		BeforeEach(func() {
+			gServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/volumes/some-volume/files", "path=some-dir/version.json"),
+					ghttp.RespondWith(http.StatusOK, `{"ref":"some-ref"}`),
+				),
+			)
+		})
+
+		It("opens the file", func() {
+			file, err := bcVolume.OpenFile(context.Background(), "some-dir/version.json")
+			Expect(err).ToNot(HaveOccurred())
+
+			defer file.Close()
+
+			Expect(ioutil.ReadAll(file)).To(Equal([]byte(`{"ref":"some-ref"}`)))
+		})
+
+		It("reads the file", func() {
+			Expect(bcVolume.ReadFile(context.Background(), "some-dir/version.json")).To(Equal([]byte(`{"ref":"some-ref"}`)))
+		})
+	})
+
+	Context("when listing a directory", func() {
+		BeforeEach(func() {
+			gServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/volumes/some-volume/tree", "depth=2&path=some-dir"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.TreeEntry{
+						{Name: ".", Type: "directory", Mode: 0755},
+						{Name: "version.json", Type: "file", Size: 18, Mode: 0644, UID: 1, GID: 2},
+					}),
+				),
+			)
+		})
+
+		It("describes its contents", func() {
+			entries, err := bcVolume.Tree(context.Background(), "some-dir", 2)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(entries).To(Equal([]baggageclaim.TreeEntry{
+				{Name: ".", Type: "directory", Mode: 0755},
+				{Name: "version.json", Type: "file", Size: 18, Mode: 0644, UID: 1, GID: 2},
+			}))
+		})
+	})
+
+	Context("when the file does not exist", func() {
+		BeforeEach(func() {
+			body, err := json.Marshal(api.ErrorResponse{Message: api.ErrStreamOutNotFound.Error()})
+			Expect(err).ToNot(HaveOccurred())
+
+			gServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/volumes/some-volume/files"),
+					ghttp.RespondWith(http.StatusNotFound, body),
+				),
+			)
+		})
+
+		It("returns ErrFileNotFound", func() {
+			_, err := bcVolume.ReadFile(context.Background(), "some-dir/version.json")
+			Expect(err).To(Equal(baggageclaim.ErrFileNotFound))
+		})
+	})
+})
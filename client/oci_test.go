@@ -0,0 +1,46 @@
+package client_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+)
+
+var _ = Describe("OCI images", func() {
+	var (
+		gServer  *ghttp.Server
+		bcVolume baggageclaim.Volume
+	)
+
+	BeforeEach(func() {
+		gServer = ghttp.NewServer()
+		bcVolume = lookupVolume(gServer, baggageclaim.VolumeResponse{Handle: "some-volume"})
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+	})
+
+	It("streams out a volume as an oci image", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/volumes/some-volume/stream-out", "chain=true&format=oci"),
+				ghttp.VerifyHeaderKV("Accept-Encoding", string(baggageclaim.IdentityEncoding)),
+				ghttp.RespondWith(http.StatusOK, "some-image"),
+			),
+		)
+
+		stream, err := bcVolume.StreamOutOCI(context.Background(), true, baggageclaim.IdentityEncoding)
+		Expect(err).ToNot(HaveOccurred())
+
+		defer stream.Close()
+
+		Expect(ioutil.ReadAll(stream)).To(Equal([]byte("some-image")))
+	})
+})
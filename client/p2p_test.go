@@ -0,0 +1,76 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+)
+
+var _ = Describe("getting a volume's p2p stream-in url", func() {
+	var (
+		gServer  *ghttp.Server
+		bcVolume baggageclaim.Volume
+	)
+
+	BeforeEach(func() {
+		gServer = ghttp.NewServer()
+		bcVolume = lookupVolume(gServer, baggageclaim.VolumeResponse{Handle: "some-volume"})
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+	})
+
+	It("returns the url the server minted for the volume and path", func() {
+		signedUrl := "https://10.0.0.1:7788/volumes/some-volume/stream-in?expires=123&path=some%2Fpath&signature=abc"
+
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/p2p-url", "handle=some-volume&path=some%2Fpath"),
+				ghttp.RespondWith(http.StatusOK, signedUrl),
+			),
+		)
+
+		streamInUrl, err := bcVolume.GetStreamInP2pUrl(context.Background(), "some/path")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(streamInUrl).To(Equal(signedUrl))
+	})
+
+	Context("when the server only returns its address", func() {
+		BeforeEach(func() {
+			gServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/p2p-url"),
+					ghttp.RespondWith(http.StatusOK, "http://10.0.0.1:7788"),
+				),
+			)
+		})
+
+		It("builds the stream-in url on that address", func() {
+			streamInUrl, err := bcVolume.GetStreamInP2pUrl(context.Background(), "some/path")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(streamInUrl).To(Equal("http://10.0.0.1:7788/volumes/some-volume/stream-in?path=some%2Fpath"))
+		})
+	})
+
+	Context("when the server fails", func() {
+		BeforeEach(func() {
+			gServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/p2p-url"),
+					ghttp.RespondWith(http.StatusInternalServerError, nil),
+				),
+			)
+		})
+
+		It("returns an error", func() {
+			_, err := bcVolume.GetStreamInP2pUrl(context.Background(), "some/path")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,72 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/api"
+)
+
+var _ = Describe("making a volume read-only", func() {
+	var (
+		gServer  *ghttp.Server
+		bcVolume baggageclaim.Volume
+	)
+
+	BeforeEach(func() {
+		gServer = ghttp.NewServer()
+		bcVolume = lookupVolume(gServer, baggageclaim.VolumeResponse{Handle: "some-volume"})
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+	})
+
+	It("seals the volume", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/volumes/some-volume/readonly"),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			),
+		)
+
+		Expect(bcVolume.SetReadOnly()).To(Succeed())
+		Expect(gServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("reports whether the volume is read-only", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes/some-volume"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, baggageclaim.VolumeResponse{
+					Handle:   "some-volume",
+					ReadOnly: true,
+				}),
+			),
+		)
+
+		Expect(bcVolume.ReadOnly()).To(BeTrue())
+	})
+
+	It("returns ErrVolumeIsReadOnly when streaming into a read-only volume", func() {
+		body, err := json.Marshal(api.ErrorResponse{Message: api.ErrVolumeIsReadOnly.Error()})
+		Expect(err).ToNot(HaveOccurred())
+
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/volumes/some-volume/stream-in"),
+				ghttp.RespondWith(http.StatusConflict, body),
+			),
+		)
+
+		err = bcVolume.StreamIn(context.Background(), ".", baggageclaim.GzipEncoding, strings.NewReader("some-tar"))
+		Expect(err).To(Equal(baggageclaim.ErrVolumeIsReadOnly))
+	})
+})
@@ -0,0 +1,77 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/api"
+)
+
+var _ = Describe("renaming a volume", func() {
+	var (
+		gServer  *ghttp.Server
+		bcVolume baggageclaim.Volume
+	)
+
+	BeforeEach(func() {
+		gServer = ghttp.NewServer()
+		bcVolume = lookupVolume(gServer, baggageclaim.VolumeResponse{
+			Handle: "some-volume",
+			Path:   "/live/some-volume/volume",
+		})
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+	})
+
+	It("refers to the volume by its new handle once renamed", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/volumes/some-volume/rename"),
+				ghttp.VerifyBody([]byte(`{"handle":"new-volume"}`+"\n")),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, baggageclaim.VolumeResponse{
+					Handle: "new-volume",
+					Path:   "/live/new-volume/volume",
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes/new-volume"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, baggageclaim.VolumeResponse{
+					Handle: "new-volume",
+				}),
+			),
+		)
+
+		Expect(bcVolume.Rename(context.Background(), "new-volume")).To(Succeed())
+
+		Expect(bcVolume.Handle()).To(Equal("new-volume"))
+		Expect(bcVolume.Path()).To(Equal("/live/new-volume/volume"))
+
+		_, err := bcVolume.Properties()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("returns ErrVolumeAlreadyExists when the handle is taken", func() {
+		body, err := json.Marshal(api.ErrorResponse{Message: api.ErrVolumeAlreadyExists.Error()})
+		Expect(err).ToNot(HaveOccurred())
+
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/volumes/some-volume/rename"),
+				ghttp.RespondWith(http.StatusConflict, body),
+			),
+		)
+
+		err = bcVolume.Rename(context.Background(), "new-volume")
+		Expect(err).To(Equal(baggageclaim.ErrVolumeAlreadyExists))
+
+		Expect(bcVolume.Handle()).To(Equal("some-volume"))
+	})
+})
@@ -0,0 +1,152 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/api"
+)
+
+var _ = Describe("snapshotting a volume", func() {
+	var (
+		gServer  *ghttp.Server
+		bcVolume baggageclaim.Volume
+
+		createdAt time.Time
+	)
+
+	BeforeEach(func() {
+		createdAt = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		gServer = ghttp.NewServer()
+		bcVolume = lookupVolume(gServer, baggageclaim.VolumeResponse{Handle: "some-volume"})
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+	})
+
+	respondWithError := func(status int, err error) http.HandlerFunc {
+		body, marshalErr := json.Marshal(api.ErrorResponse{Message: err.Error()})
+		Expect(marshalErr).ToNot(HaveOccurred())
+
+		return ghttp.RespondWith(status, body)
+	}
+
+	It("creates a snapshot", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/volumes/some-volume/snapshots"),
+				ghttp.VerifyBody([]byte(`{"handle":"some-snapshot"}`+"\n")),
+				ghttp.RespondWithJSONEncoded(http.StatusCreated, baggageclaim.Snapshot{
+					Handle:    "some-snapshot",
+					CreatedAt: createdAt,
+				}),
+			),
+		)
+
+		snapshot, err := bcVolume.CreateSnapshot(context.Background(), "some-snapshot")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshot).To(Equal(baggageclaim.Snapshot{
+			Handle:    "some-snapshot",
+			CreatedAt: createdAt,
+		}))
+	})
+
+	It("returns ErrSnapshotAlreadyExists for a taken handle", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/volumes/some-volume/snapshots"),
+				respondWithError(http.StatusConflict, api.ErrSnapshotAlreadyExists),
+			),
+		)
+
+		_, err := bcVolume.CreateSnapshot(context.Background(), "some-snapshot")
+		Expect(err).To(Equal(baggageclaim.ErrSnapshotAlreadyExists))
+	})
+
+	It("lists the volume's snapshots", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes/some-volume"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, baggageclaim.VolumeResponse{
+					Handle: "some-volume",
+					Snapshots: []baggageclaim.Snapshot{
+						{Handle: "some-snapshot", CreatedAt: createdAt},
+					},
+				}),
+			),
+		)
+
+		snapshots, err := bcVolume.Snapshots()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshots).To(Equal([]baggageclaim.Snapshot{
+			{Handle: "some-snapshot", CreatedAt: createdAt},
+		}))
+	})
+
+	It("restores a snapshot", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/volumes/some-volume/restore/some-snapshot"),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			),
+		)
+
+		Expect(bcVolume.RestoreSnapshot(context.Background(), "some-snapshot")).To(Succeed())
+	})
+
+	It("returns ErrVolumeHasChildren when restoring a volume with children", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/volumes/some-volume/restore/some-snapshot"),
+				respondWithError(http.StatusConflict, api.ErrVolumeHasChildren),
+			),
+		)
+
+		err := bcVolume.RestoreSnapshot(context.Background(), "some-snapshot")
+		Expect(err).To(Equal(baggageclaim.ErrVolumeHasChildren))
+	})
+
+	It("returns ErrVolumeInUse when restoring a volume that is in use", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/volumes/some-volume/restore/some-snapshot"),
+				respondWithError(http.StatusConflict, api.ErrVolumeInUse),
+			),
+		)
+
+		err := bcVolume.RestoreSnapshot(context.Background(), "some-snapshot")
+		Expect(err).To(Equal(baggageclaim.ErrVolumeInUse))
+	})
+
+	It("destroys a snapshot", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/volumes/some-volume/snapshots/some-snapshot"),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			),
+		)
+
+		Expect(bcVolume.DestroySnapshot(context.Background(), "some-snapshot")).To(Succeed())
+	})
+
+	It("returns ErrSnapshotNotFound for a missing snapshot", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/volumes/some-volume/snapshots/some-snapshot"),
+				respondWithError(http.StatusNotFound, api.ErrSnapshotNotFound),
+			),
+		)
+
+		err := bcVolume.DestroySnapshot(context.Background(), "some-snapshot")
+		Expect(err).To(Equal(baggageclaim.ErrSnapshotNotFound))
+	})
+})
@@ -0,0 +1,338 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/cenkalti/backoff"
+	uuid "github.com/nu7hatch/gouuid"
+	"github.com/tedsuo/rata"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/retryhttp"
+)
+
+// streamInReplayWindow is how much of a stream that cannot be seeked is kept
+// in memory, so that it can be sent again from wherever the server got up to
+// when the connection dropped.
+const streamInReplayWindow = 32 * 1024 * 1024
+
+// streamInResumable sends the stream as a resumable upload. If the connection
+// drops, e.g. because of a network blip or the server restarting, the upload
+// resumes from however much the server received rather than starting over.
+func (c *client) streamInResumable(ctx context.Context, logger lager.Logger, destHandle string, path string, encoding baggageclaim.Encoding, tarContent io.Reader) error {
+	logger = logger.Session("stream-in-resumable", lager.Data{
+		"volume": destHandle,
+		"path":   path,
+	})
+
+	sessionID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	session := sessionID.String()
+	stream := newReplayableStream(tarContent)
+
+	exponentialBackoff := backoff.NewExponentialBackOff()
+	exponentialBackoff.InitialInterval = 100 * time.Millisecond
+	exponentialBackoff.MaxInterval = 10 * time.Second
+	exponentialBackoff.MaxElapsedTime = 10 * time.Minute
+
+	var offset int64
+	for {
+		err := c.streamInFrom(ctx, logger, destHandle, path, encoding, session, stream, offset)
+		if err == nil {
+			return nil
+		}
+
+		// failing to read the stream being sent is not the server's fault
+		if stream.err != nil {
+			return stream.err
+		}
+
+		var offsetErr streamInOffsetError
+		if !errors.As(err, &offsetErr) && !isResumable(err) {
+			return err
+		}
+
+		logger.Info("interrupted", lager.Data{"offset": offset, "error": err.Error()})
+
+		offset, err = c.resumeOffset(ctx, logger, exponentialBackoff, destHandle, session, err)
+		if err != nil {
+			return err
+		}
+
+		err = stream.rewind(offset)
+		if err != nil {
+			return err
+		}
+
+		logger.Info("resuming", lager.Data{"offset": offset})
+	}
+}
+
+// streamInFrom makes a single attempt at sending the rest of the stream,
+// starting offset bytes in.
+func (c *client) streamInFrom(ctx context.Context, logger lager.Logger, destHandle string, path string, encoding baggageclaim.Encoding, session string, stream *replayableStream, offset int64) error {
+	// the transport may go on reading the body after giving up on the
+	// request, so each attempt is cut off before the next one starts
+	body := &attemptBody{stream: stream}
+	defer body.stop()
+
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.StreamIn, rata.Params{
+		"handle": destHandle,
+	}, body)
+	if err != nil {
+		return err
+	}
+
+	request.URL.RawQuery = url.Values{
+		"path":    []string{path},
+		"session": []string{session},
+	}.Encode()
+
+	request.Header.Set("Content-Encoding", string(encoding))
+
+	if offset > 0 {
+		request.Header.Set("Content-Range", fmt.Sprintf("bytes %d-*/*", offset))
+	}
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusNoContent:
+		return nil
+
+	case http.StatusRequestedRangeNotSatisfiable:
+		offset, err := strconv.ParseInt(response.Header.Get(baggageclaim.UploadOffsetHeader), 10, 64)
+		if err != nil {
+			return getError(response)
+		}
+
+		return streamInOffsetError{offset: offset}
+
+	default:
+		return getError(response)
+	}
+}
+
+// resumeOffset waits to try again and then asks the server how much of the
+// upload it has received, for as long as the server remains unreachable.
+func (c *client) resumeOffset(ctx context.Context, logger lager.Logger, exponentialBackoff backoff.BackOff, destHandle string, session string, cause error) (int64, error) {
+	for {
+		delay := exponentialBackoff.NextBackOff()
+		if delay == backoff.Stop {
+			return 0, cause
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		var offsetErr streamInOffsetError
+		if errors.As(cause, &offsetErr) {
+			return offsetErr.offset, nil
+		}
+
+		offset, err := c.streamInOffset(ctx, logger, destHandle, session)
+		if err == nil {
+			return offset, nil
+		}
+
+		if !isResumable(err) {
+			return 0, err
+		}
+
+		cause = err
+	}
+}
+
+func (c *client) streamInOffset(ctx context.Context, logger lager.Logger, destHandle string, session string) (int64, error) {
+	request, err := c.requestGenerator.CreateRequest(baggageclaim.StreamInOffset, rata.Params{
+		"handle": destHandle,
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	request.URL.RawQuery = url.Values{"session": []string{session}}.Encode()
+
+	request = request.WithContext(ctx)
+
+	response, err := c.httpClient(logger).Do(request)
+	if err != nil {
+		return 0, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return 0, getError(response)
+	}
+
+	var offsetResponse baggageclaim.StreamInOffsetResponse
+	err = json.NewDecoder(response.Body).Decode(&offsetResponse)
+	if err != nil {
+		return 0, err
+	}
+
+	return offsetResponse.Offset, nil
+}
+
+// streamInOffsetError is returned when the server expects the upload to
+// resume from a different offset.
+type streamInOffsetError struct {
+	offset int64
+}
+
+func (err streamInOffsetError) Error() string {
+	return fmt.Sprintf("upload must resume from offset %d", err.offset)
+}
+
+// isResumable reports whether err means the connection to the server was
+// lost, rather than the server rejecting the stream.
+func isResumable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if (&retryhttp.DefaultRetryer{}).IsRetryable(err) {
+		return true
+	}
+
+	return errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// replayableStream can be rewound to anywhere the server may have got up to.
+// Streams which can be seeked are seeked; otherwise the most recently read
+// part of the stream is kept in memory.
+type replayableStream struct {
+	source io.Reader
+
+	seeker io.Seeker
+	start  int64
+
+	// the last bytes read from source, ending at read
+	window []byte
+	read   int64
+
+	// how far into the stream the next Read is
+	pos int64
+
+	// the error reading from source, if any
+	err error
+}
+
+func newReplayableStream(source io.Reader) *replayableStream {
+	stream := &replayableStream{source: source}
+
+	if seeker, ok := source.(io.Seeker); ok {
+		// e.g. pipes are files but cannot be seeked
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			stream.seeker = seeker
+			stream.start = start
+		}
+	}
+
+	return stream
+}
+
+func (s *replayableStream) Read(p []byte) (int, error) {
+	if s.pos < s.read {
+		n := copy(p, s.window[len(s.window)-int(s.read-s.pos):])
+		s.pos += int64(n)
+		return n, nil
+	}
+
+	n, err := s.source.Read(p)
+
+	if s.seeker == nil && n > 0 {
+		s.window = append(s.window, p[:n]...)
+
+		// trim in bulk so that the window is not copied on every read
+		if len(s.window) > 2*streamInReplayWindow {
+			s.window = append([]byte(nil), s.window[len(s.window)-streamInReplayWindow:]...)
+		}
+	}
+
+	s.read += int64(n)
+	s.pos += int64(n)
+
+	if err != nil && err != io.EOF {
+		s.err = err
+	}
+
+	return n, err
+}
+
+func (s *replayableStream) rewind(offset int64) error {
+	if s.seeker != nil {
+		_, err := s.seeker.Seek(s.start+offset, io.SeekStart)
+		if err != nil {
+			return err
+		}
+
+		s.read = offset
+		s.pos = offset
+
+		return nil
+	}
+
+	if offset > s.read || offset < s.read-int64(len(s.window)) {
+		return fmt.Errorf("cannot resume stream-in from offset %d: only bytes %d-%d can be sent again", offset, s.read-int64(len(s.window)), s.read)
+	}
+
+	s.pos = offset
+
+	return nil
+}
+
+// attemptBody is the body of a single attempt at sending the stream. Once
+// stopped it no longer reads from the stream, so that a transport which is
+// still reading it cannot interfere with the next attempt.
+type attemptBody struct {
+	stream *replayableStream
+
+	stopped bool
+	mutex   sync.Mutex
+}
+
+func (b *attemptBody) Read(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.stopped {
+		return 0, io.ErrClosedPipe
+	}
+
+	return b.stream.Read(p)
+}
+
+func (b *attemptBody) stop() {
+	b.mutex.Lock()
+	b.stopped = true
+	b.mutex.Unlock()
+}
@@ -0,0 +1,219 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/client"
+)
+
+var _ = Describe("streaming in", func() {
+	var (
+		server *httptest.Server
+
+		stream []byte
+
+		// how much of the stream the server takes before dropping the first
+		// connection, or -1 to accept the whole stream straight away
+		dropAfter int
+
+		// the status the server rejects the first attempt with, if any
+		rejectWith int
+
+		lock          sync.Mutex
+		received      []byte
+		sessions      []string
+		contentRanges []string
+
+		bcVolume baggageclaim.Volume
+	)
+
+	BeforeEach(func() {
+		stream = bytes.Repeat([]byte("some-tar-stream\n"), 256*1024)
+		dropAfter = -1
+		rejectWith = 0
+
+		received = nil
+		sessions = nil
+		contentRanges = nil
+	})
+
+	JustBeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			switch {
+			case r.Method == "GET" && r.URL.Path == "/volumes/some-volume":
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(baggageclaim.VolumeResponse{Handle: "some-volume"})
+
+			case r.Method == "GET" && r.URL.Path == "/volumes/some-volume/stream-in":
+				Expect(r.URL.Query().Get("session")).To(Equal(sessions[0]))
+				json.NewEncoder(w).Encode(baggageclaim.StreamInOffsetResponse{Offset: int64(len(received))})
+
+			case r.Method == "PUT" && r.URL.Path == "/volumes/some-volume/stream-in":
+				Expect(r.URL.Query().Get("path")).To(Equal("some-path"))
+
+				sessions = append(sessions, r.URL.Query().Get("session"))
+				contentRanges = append(contentRanges, r.Header.Get("Content-Range"))
+
+				if len(sessions) == 1 && rejectWith != 0 {
+					ioutil.ReadAll(r.Body)
+					w.Header().Set("Upload-Offset", "0")
+					w.WriteHeader(rejectWith)
+					json.NewEncoder(w).Encode(map[string]string{"error": "some-error"})
+					return
+				}
+
+				if len(sessions) == 1 && dropAfter >= 0 {
+					part := make([]byte, dropAfter)
+					_, err := io.ReadFull(r.Body, part)
+					Expect(err).ToNot(HaveOccurred())
+
+					received = append(received, part...)
+
+					conn, _, err := w.(http.Hijacker).Hijack()
+					Expect(err).ToNot(HaveOccurred())
+					conn.Close()
+					return
+				}
+
+				rest, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					// the client gave up on sending the stream
+					return
+				}
+
+				received = append(received, rest...)
+
+				w.WriteHeader(http.StatusNoContent)
+
+			default:
+				Fail("unexpected request: " + r.Method + " " + r.URL.Path)
+			}
+		}))
+
+		c := client.New(server.URL, http.DefaultTransport)
+
+		var found bool
+		var err error
+		bcVolume, found, err = c.LookupVolume(lagertest.NewTestLogger("test"), "some-volume")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sends the stream without an upload session unless asked to", func() {
+		err := bcVolume.StreamIn(context.Background(), "some-path", baggageclaim.GzipEncoding, bytes.NewReader(stream))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(received).To(Equal(stream))
+		Expect(sessions).To(Equal([]string{""}))
+	})
+
+	It("sends the stream as an upload session", func() {
+		err := bcVolume.StreamInResumable(context.Background(), "some-path", baggageclaim.GzipEncoding, bytes.NewReader(stream))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(received).To(Equal(stream))
+		Expect(sessions).To(HaveLen(1))
+		Expect(sessions[0]).ToNot(BeEmpty())
+		Expect(contentRanges).To(Equal([]string{""}))
+	})
+
+	Context("when the connection drops partway through", func() {
+		BeforeEach(func() {
+			dropAfter = 1024 * 1024
+		})
+
+		It("fails without resuming unless asked to", func() {
+			err := bcVolume.StreamIn(context.Background(), "some-path", baggageclaim.GzipEncoding, bytes.NewReader(stream))
+			Expect(err).To(HaveOccurred())
+			Expect(sessions).To(Equal([]string{""}))
+		})
+
+		It("resumes a stream which can be seeked from where the server got up to", func() {
+			err := bcVolume.StreamInResumable(context.Background(), "some-path", baggageclaim.GzipEncoding, bytes.NewReader(stream))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(received).To(Equal(stream))
+			Expect(sessions).To(HaveLen(2))
+			Expect(sessions[1]).To(Equal(sessions[0]))
+			Expect(contentRanges).To(Equal([]string{"", fmt.Sprintf("bytes %d-*/*", dropAfter)}))
+		})
+
+		It("resumes a stream which cannot be seeked from where the server got up to", func() {
+			err := bcVolume.StreamInResumable(context.Background(), "some-path", baggageclaim.GzipEncoding, struct{ io.Reader }{bytes.NewReader(stream)})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(received).To(Equal(stream))
+			Expect(contentRanges).To(Equal([]string{"", fmt.Sprintf("bytes %d-*/*", dropAfter)}))
+		})
+	})
+
+	Context("when the server asks for the stream from another offset", func() {
+		BeforeEach(func() {
+			rejectWith = http.StatusRequestedRangeNotSatisfiable
+		})
+
+		It("sends it again from that offset", func() {
+			err := bcVolume.StreamInResumable(context.Background(), "some-path", baggageclaim.GzipEncoding, bytes.NewReader(stream))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(received).To(Equal(stream))
+			Expect(contentRanges).To(HaveLen(2))
+		})
+	})
+
+	Context("when the server rejects the stream", func() {
+		BeforeEach(func() {
+			rejectWith = http.StatusBadRequest
+		})
+
+		It("returns the error without trying again", func() {
+			err := bcVolume.StreamInResumable(context.Background(), "some-path", baggageclaim.GzipEncoding, bytes.NewReader(stream))
+			Expect(err).To(MatchError("some-error"))
+
+			Expect(sessions).To(HaveLen(1))
+		})
+	})
+
+	Context("when reading the stream fails", func() {
+		It("returns the error without trying again", func() {
+			disaster := errors.New("disaster")
+
+			err := bcVolume.StreamInResumable(context.Background(), "some-path", baggageclaim.GzipEncoding, io.MultiReader(bytes.NewReader(stream[:1024]), failingReader{disaster}))
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Is(err, disaster)).To(BeTrue())
+
+			Expect(len(sessions)).To(BeNumerically("<=", 1))
+		})
+	})
+})
+
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
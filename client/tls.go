@@ -0,0 +1,59 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// NewTLSConfig loads the configuration for talking to servers that serve the
+// API over TLS. Servers are verified against the CA certificates in
+// caCertPath, or the system's if it is empty. The certificate and key are
+// presented to servers that verify client certificates, and may be left
+// empty otherwise.
+func NewTLSConfig(certPath string, keyPath string, caCertPath string) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if (certPath == "") != (keyPath == "") {
+		return nil, errors.New("a client certificate and its key must be given together")
+	}
+
+	if certPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if caCertPath != "" {
+		caCerts, err := ioutil.ReadFile(caCertPath)
+		if err != nil {
+			return nil, fmt.Errorf("read ca certificates: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCerts) {
+			return nil, fmt.Errorf("no ca certificates found in %s", caCertPath)
+		}
+
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// NewWithTLSConfig is like New, but talks to the server over TLS with the
+// given configuration, e.g. as loaded by NewTLSConfig.
+func NewWithTLSConfig(apiURL string, tlsConfig *tls.Config) Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return New(apiURL, transport)
+}
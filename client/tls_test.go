@@ -0,0 +1,182 @@
+package client_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/client"
+)
+
+var _ = Describe("talking to a server over TLS", func() {
+	var (
+		tempDir string
+		gServer *ghttp.Server
+
+		caCertPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "baggageclaim-tls")
+		Expect(err).ToNot(HaveOccurred())
+
+		gServer = ghttp.NewUnstartedServer()
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes/some-volume"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, baggageclaim.VolumeResponse{
+					Handle: "some-volume",
+				}),
+			),
+		)
+	})
+
+	JustBeforeEach(func() {
+		gServer.HTTPTestServer.StartTLS()
+
+		caCertPath = filepath.Join(tempDir, "ca.crt")
+		writePEM(caCertPath, "CERTIFICATE", gServer.HTTPTestServer.Certificate().Raw)
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	lookup := func(c baggageclaim.Client) error {
+		_, _, err := c.LookupVolume(lagertest.NewTestLogger("test"), "some-volume")
+		return err
+	}
+
+	It("verifies the server against the CA certificates", func() {
+		tlsConfig, err := client.NewTLSConfig("", "", caCertPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(lookup(client.NewWithTLSConfig(gServer.URL(), tlsConfig))).To(Succeed())
+		Expect(gServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("refuses servers that the CA certificates do not vouch for", func() {
+		otherCAPath := filepath.Join(tempDir, "other-ca.crt")
+		otherCA, _ := generateCertificate()
+		writePEM(otherCAPath, "CERTIFICATE", otherCA)
+
+		tlsConfig, err := client.NewTLSConfig("", "", otherCAPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(lookup(client.NewWithTLSConfig(gServer.URL(), tlsConfig))).ToNot(Succeed())
+		Expect(gServer.ReceivedRequests()).To(BeEmpty())
+	})
+
+	Context("when the server verifies client certificates", func() {
+		var certPath, keyPath string
+
+		BeforeEach(func() {
+			cert, key := generateCertificate()
+
+			certPath = filepath.Join(tempDir, "client.crt")
+			writePEM(certPath, "CERTIFICATE", cert)
+
+			keyPath = filepath.Join(tempDir, "client.key")
+			writePEM(keyPath, "EC PRIVATE KEY", key)
+
+			parsed, err := x509.ParseCertificate(cert)
+			Expect(err).ToNot(HaveOccurred())
+
+			clientCAs := x509.NewCertPool()
+			clientCAs.AddCert(parsed)
+
+			gServer.HTTPTestServer.TLS = &tls.Config{
+				ClientCAs:  clientCAs,
+				ClientAuth: tls.RequireAndVerifyClientCert,
+			}
+		})
+
+		It("presents the client certificate", func() {
+			tlsConfig, err := client.NewTLSConfig(certPath, keyPath, caCertPath)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(lookup(client.NewWithTLSConfig(gServer.URL(), tlsConfig))).To(Succeed())
+			Expect(gServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("is refused without one", func() {
+			tlsConfig, err := client.NewTLSConfig("", "", caCertPath)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(lookup(client.NewWithTLSConfig(gServer.URL(), tlsConfig))).ToNot(Succeed())
+			Expect(gServer.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
+	Describe("NewTLSConfig", func() {
+		It("requires a certificate and its key together", func() {
+			_, err := client.NewTLSConfig(filepath.Join(tempDir, "client.crt"), "", "")
+			Expect(err).To(HaveOccurred())
+
+			_, err = client.NewTLSConfig("", filepath.Join(tempDir, "client.key"), "")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails when there are no certificates in the CA file", func() {
+			emptyPath := filepath.Join(tempDir, "empty.crt")
+			Expect(ioutil.WriteFile(emptyPath, []byte("nope"), 0644)).To(Succeed())
+
+			_, err := client.NewTLSConfig("", "", emptyPath)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails when the CA file does not exist", func() {
+			_, err := client.NewTLSConfig("", "", filepath.Join(tempDir, "missing.crt"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+// generateCertificate returns the DER of a self-signed certificate and its
+// key, which is fit both to sign and to present as a client certificate.
+func generateCertificate() ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "baggageclaim-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).ToNot(HaveOccurred())
+
+	return cert, keyDER
+}
+
+func writePEM(path string, blockType string, der []byte) {
+	err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0644)
+	Expect(err).ToNot(HaveOccurred())
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"net/http"
+)
+
+// TokenSource provides the bearer token with which to authenticate to the
+// API. It is asked for a token on every request, so it may rotate them.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// StaticToken is a TokenSource that always provides the same token.
+type StaticToken string
+
+func (token StaticToken) Token() (string, error) {
+	return string(token), nil
+}
+
+// NewWithTokenSource is like New, but authenticates every request with a
+// token from tokens.
+func NewWithTokenSource(apiURL string, nestedRoundTripper http.RoundTripper, tokens TokenSource) Client {
+	c := New(apiURL, nestedRoundTripper).(*client)
+
+	// wrapped after New, which may have to make the transport dial a socket
+	c.nestedRoundTripper = &tokenRoundTripper{
+		tokens: tokens,
+		nested: c.nestedRoundTripper,
+	}
+
+	return c
+}
+
+type tokenRoundTripper struct {
+	tokens TokenSource
+	nested http.RoundTripper
+}
+
+func (rt *tokenRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	token, err := rt.tokens.Token()
+	if err != nil {
+		if request.Body != nil {
+			request.Body.Close()
+		}
+
+		return nil, err
+	}
+
+	// round trippers must not modify the request they are given
+	request = request.Clone(request.Context())
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	return rt.nested.RoundTrip(request)
+}
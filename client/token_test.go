@@ -0,0 +1,124 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/api"
+	"github.com/concourse/baggageclaim/client"
+)
+
+type rotatingTokens struct {
+	tokens []string
+	err    error
+}
+
+func (source *rotatingTokens) Token() (string, error) {
+	if source.err != nil {
+		return "", source.err
+	}
+
+	token := source.tokens[0]
+	source.tokens = source.tokens[1:]
+	return token, nil
+}
+
+var _ = Describe("authenticating with a token", func() {
+	var (
+		gServer *ghttp.Server
+		tokens  *rotatingTokens
+		c       client.Client
+	)
+
+	BeforeEach(func() {
+		gServer = ghttp.NewServer()
+		tokens = &rotatingTokens{tokens: []string{"first-token", "second-token"}}
+		c = client.NewWithTokenSource(gServer.URL(), http.DefaultTransport, tokens)
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+	})
+
+	It("presents a fresh token on every request", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer first-token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.VolumeResponse{}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer second-token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.VolumeResponse{}),
+			),
+		)
+
+		_, err := c.ListVolumes(lagertest.NewTestLogger("test"), nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = c.ListVolumes(lagertest.NewTestLogger("test"), nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(gServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("can use a static token", func() {
+		c = client.NewWithTokenSource(gServer.URL(), http.DefaultTransport, client.StaticToken("some-token"))
+
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer some-token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.VolumeResponse{}),
+			),
+		)
+
+		_, err := c.ListVolumes(lagertest.NewTestLogger("test"), nil)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("when the token source fails", func() {
+		BeforeEach(func() {
+			tokens.err = errors.New("no tokens for you")
+		})
+
+		It("does not make the request", func() {
+			_, err := c.ListVolumes(lagertest.NewTestLogger("test"), nil)
+			Expect(err).To(HaveOccurred())
+			Expect(gServer.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
+	Context("when the token is refused", func() {
+		BeforeEach(func() {
+			gServer.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusUnauthorized, api.ErrorResponse{Message: api.ErrUnauthorized.Error()}),
+			)
+		})
+
+		It("returns ErrUnauthorized", func() {
+			_, err := c.ListVolumes(lagertest.NewTestLogger("test"), nil)
+			Expect(err).To(Equal(baggageclaim.ErrUnauthorized))
+		})
+	})
+
+	Context("when the token does not allow the request", func() {
+		BeforeEach(func() {
+			gServer.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusForbidden, api.ErrorResponse{Message: api.ErrForbidden.Error()}),
+			)
+		})
+
+		It("returns ErrForbidden", func() {
+			_, err := c.ListVolumes(lagertest.NewTestLogger("test"), nil)
+			Expect(err).To(Equal(baggageclaim.ErrForbidden))
+		})
+	})
+})
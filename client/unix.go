@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+const unixURLPrefix = "unix://"
+
+// unixAPIURL is the URL requests are made to over a Unix socket. Only its
+// host is sent, as the Host header.
+const unixAPIURL = "http://baggageclaim"
+
+func unixRoundTripper(socketPath string, nested http.RoundTripper) http.RoundTripper {
+	if nested == nil {
+		nested = http.DefaultTransport
+	}
+
+	transport, ok := nested.(*http.Transport)
+	if !ok {
+		return undialableRoundTripper{
+			socketPath: socketPath,
+			nested:     nested,
+		}
+	}
+
+	transport = transport.Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+
+	return transport
+}
+
+// undialableRoundTripper fails every request, as the round tripper it was
+// given cannot be made to dial the socket and would otherwise send them
+// over TCP to the placeholder host.
+type undialableRoundTripper struct {
+	socketPath string
+	nested     http.RoundTripper
+}
+
+func (rt undialableRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Body != nil {
+		request.Body.Close()
+	}
+
+	return nil, fmt.Errorf("cannot dial unix socket %s with a %T; use an *http.Transport", rt.socketPath, rt.nested)
+}
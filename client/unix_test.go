@@ -0,0 +1,107 @@
+package client_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/client"
+)
+
+var _ = Describe("talking to a server on a Unix socket", func() {
+	var (
+		tempDir    string
+		socketPath string
+		gServer    *ghttp.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "baggageclaim-socket")
+		Expect(err).ToNot(HaveOccurred())
+
+		socketPath = filepath.Join(tempDir, "baggageclaim.sock")
+
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		gServer = ghttp.NewUnstartedServer()
+		gServer.HTTPTestServer.Listener.Close()
+		gServer.HTTPTestServer.Listener = listener
+		gServer.Start()
+
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes/some-volume"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, baggageclaim.VolumeResponse{
+					Handle: "some-volume",
+				}),
+			),
+		)
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("dials the socket", func() {
+		c := client.New("unix://"+socketPath, nil)
+
+		bcVolume, found, err := c.LookupVolume(lagertest.NewTestLogger("test"), "some-volume")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(bcVolume.Handle()).To(Equal("some-volume"))
+	})
+
+	It("keeps the settings of the given transport", func() {
+		c := client.New("unix://"+socketPath, &http.Transport{DisableKeepAlives: true})
+
+		_, found, err := c.LookupVolume(lagertest.NewTestLogger("test"), "some-volume")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+	})
+
+	It("dials the socket when authenticating with a token", func() {
+		gServer.SetHandler(0, ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", "/volumes/some-volume"),
+			ghttp.VerifyHeaderKV("Authorization", "Bearer some-token"),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, baggageclaim.VolumeResponse{
+				Handle: "some-volume",
+			}),
+		))
+
+		c := client.NewWithTokenSource("unix://"+socketPath, nil, client.StaticToken("some-token"))
+
+		_, found, err := c.LookupVolume(lagertest.NewTestLogger("test"), "some-volume")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+	})
+
+	It("fails requests rather than sending them elsewhere when given a round tripper that cannot dial the socket", func() {
+		var roundTripper http.RoundTripper = roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			Fail("request was not sent to the socket")
+			return nil, nil
+		})
+
+		c := client.New("unix://"+socketPath, roundTripper)
+
+		_, _, err := c.LookupVolume(lagertest.NewTestLogger("test"), "some-volume")
+		Expect(err).To(MatchError(ContainSubstring("cannot dial unix socket " + socketPath)))
+		Expect(gServer.ReceivedRequests()).To(BeEmpty())
+	})
+})
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
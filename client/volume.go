@@ -3,6 +3,8 @@ package client
 import (
 	"context"
 	"io"
+	"io/ioutil"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/baggageclaim"
@@ -16,6 +18,9 @@ type clientVolume struct {
 	handle string
 	path   string
 
+	// usage as reported when the volume was listed, if requested
+	usage *baggageclaim.VolumeUsage
+
 	bcClient *client
 }
 
@@ -39,14 +44,85 @@ func (cv *clientVolume) Properties() (baggageclaim.VolumeProperties, error) {
 	return vr.Properties, nil
 }
 
+func (cv *clientVolume) Rename(ctx context.Context, handle string) error {
+	vr, err := cv.bcClient.renameVolume(ctx, cv.logger, cv.handle, handle)
+	if err != nil {
+		return err
+	}
+
+	cv.handle = vr.Handle
+	cv.path = vr.Path
+
+	return nil
+}
+
+func (cv *clientVolume) CreateSnapshot(ctx context.Context, handle string) (baggageclaim.Snapshot, error) {
+	return cv.bcClient.createSnapshot(ctx, cv.logger, cv.handle, handle)
+}
+
+func (cv *clientVolume) Snapshots() ([]baggageclaim.Snapshot, error) {
+	vr, found, err := cv.bcClient.getVolumeResponse(cv.logger, cv.handle)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, volume.ErrVolumeDoesNotExist
+	}
+
+	return vr.Snapshots, nil
+}
+
+func (cv *clientVolume) RestoreSnapshot(ctx context.Context, handle string) error {
+	return cv.bcClient.restoreSnapshot(ctx, cv.logger, cv.handle, handle)
+}
+
+func (cv *clientVolume) DestroySnapshot(ctx context.Context, handle string) error {
+	return cv.bcClient.destroySnapshot(ctx, cv.logger, cv.handle, handle)
+}
+
 func (cv *clientVolume) StreamIn(ctx context.Context, path string, encoding baggageclaim.Encoding, tarStream io.Reader) error {
 	return cv.bcClient.streamIn(ctx, cv.logger, cv.handle, path, encoding, tarStream)
 }
 
+func (cv *clientVolume) StreamInResumable(ctx context.Context, path string, encoding baggageclaim.Encoding, tarStream io.Reader) error {
+	return cv.bcClient.streamInResumable(ctx, cv.logger, cv.handle, path, encoding, tarStream)
+}
+
 func (cv *clientVolume) StreamOut(ctx context.Context, path string, encoding baggageclaim.Encoding) (io.ReadCloser, error) {
 	return cv.bcClient.streamOut(ctx, cv.logger, cv.handle, encoding, path)
 }
 
+func (cv *clientVolume) Diff(ctx context.Context) ([]baggageclaim.Change, error) {
+	return cv.bcClient.getDiff(ctx, cv.logger, cv.handle)
+}
+
+func (cv *clientVolume) StreamOutDiff(ctx context.Context, path string, encoding baggageclaim.Encoding) (io.ReadCloser, error) {
+	return cv.bcClient.streamOutDiff(ctx, cv.logger, cv.handle, encoding, path)
+}
+
+func (cv *clientVolume) StreamOutOCI(ctx context.Context, chain bool, encoding baggageclaim.Encoding) (io.ReadCloser, error) {
+	return cv.bcClient.streamOutOCI(ctx, cv.logger, cv.handle, encoding, chain)
+}
+
+func (cv *clientVolume) OpenFile(ctx context.Context, path string) (io.ReadCloser, error) {
+	return cv.bcClient.openFile(ctx, cv.logger, cv.handle, path)
+}
+
+func (cv *clientVolume) ReadFile(ctx context.Context, path string) ([]byte, error) {
+	file, err := cv.OpenFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ioutil.ReadAll(file)
+}
+
+func (cv *clientVolume) Tree(ctx context.Context, path string, depth int) ([]baggageclaim.TreeEntry, error) {
+	return cv.bcClient.getTree(ctx, cv.logger, cv.handle, path, depth)
+}
+
 func (cv *clientVolume) GetPrivileged() (bool, error) {
 	return cv.bcClient.getPrivileged(cv.logger, cv.handle)
 }
@@ -55,6 +131,38 @@ func (cv *clientVolume) SetPrivileged(privileged bool) error {
 	return cv.bcClient.setPrivileged(cv.logger, cv.handle, privileged)
 }
 
+func (cv *clientVolume) SetTTL(ttl time.Duration) error {
+	return cv.bcClient.setTTL(cv.logger, cv.handle, ttl)
+}
+
+func (cv *clientVolume) SetReadOnly() error {
+	return cv.bcClient.setReadOnly(cv.logger, cv.handle)
+}
+
+func (cv *clientVolume) ReadOnly() (bool, error) {
+	vr, found, err := cv.bcClient.getVolumeResponse(cv.logger, cv.handle)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, volume.ErrVolumeDoesNotExist
+	}
+
+	return vr.ReadOnly, nil
+}
+
+func (cv *clientVolume) Usage() (baggageclaim.VolumeUsage, error) {
+	if cv.usage != nil {
+		return *cv.usage, nil
+	}
+
+	return cv.bcClient.getUsage(cv.logger, cv.handle)
+}
+
+func (cv *clientVolume) Digest() (string, error) {
+	return cv.bcClient.getDigest(cv.logger, cv.handle)
+}
+
 func (cv *clientVolume) Destroy() error {
 	return cv.bcClient.destroy(cv.logger, cv.handle)
 }
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+
+	"github.com/concourse/baggageclaim"
+)
+
+const (
+	SortByHandle    = "handle"
+	SortByCreatedAt = "created"
+	SortBySize      = "size"
+)
+
+// DefaultVolumePageSize is how many volumes ListVolumePages fetches at a time
+// unless told otherwise.
+const DefaultVolumePageSize = 500
+
+type ListVolumesOptions struct {
+	Properties baggageclaim.VolumeProperties
+
+	// SortBy is SortByHandle, SortByCreatedAt, or SortBySize, optionally
+	// prefixed with "-" to sort in descending order. The server sorts by
+	// handle if it is left empty.
+	SortBy string
+
+	// PageSize defaults to DefaultVolumePageSize.
+	PageSize int
+
+	IncludeUsage bool
+}
+
+func (c *client) ListVolumePages(logger lager.Logger, opts ListVolumesOptions) *VolumeIterator {
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultVolumePageSize
+	}
+
+	return &VolumeIterator{
+		client: c,
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+// VolumeIterator steps through a listing of volumes:
+//
+//	volumes := c.ListVolumePages(logger, client.ListVolumesOptions{})
+//	for volumes.Next() {
+//	  volume := volumes.Volume()
+//	  ...
+//	}
+//
+//	if err := volumes.Err(); err != nil {
+//	  ...
+//	}
+//
+// Volumes created or destroyed while the listing is under way may or may not
+// be included.
+type VolumeIterator struct {
+	client *client
+	logger lager.Logger
+	opts   ListVolumesOptions
+
+	page     []baggageclaim.VolumeResponse
+	next     url.Values
+	lastPage bool
+
+	volume baggageclaim.Volume
+	err    error
+}
+
+// Next advances to the next volume, fetching another page if need be. It
+// returns false once there are no volumes left or fetching a page fails.
+func (it *VolumeIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.lastPage || it.err != nil {
+			return false
+		}
+
+		query := it.next
+		if query == nil {
+			query = it.query()
+		}
+
+		it.page, it.next, it.err = it.client.requestVolumes(it.logger, query)
+		if it.err != nil {
+			return false
+		}
+
+		it.lastPage = it.next == nil
+	}
+
+	it.volume = it.client.newVolume(it.logger, it.page[0])
+	it.page = it.page[1:]
+
+	return true
+}
+
+// Volume returns the volume Next advanced to.
+func (it *VolumeIterator) Volume() baggageclaim.Volume {
+	return it.volume
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *VolumeIterator) Err() error {
+	return it.err
+}
+
+// query asks for the first page; the rest are linked to from the page before.
+func (it *VolumeIterator) query() url.Values {
+	queryString := url.Values{}
+	for key, val := range it.opts.Properties {
+		queryString.Add(key, val)
+	}
+
+	queryString.Set(baggageclaim.LimitQueryKey, strconv.Itoa(it.opts.PageSize))
+
+	if it.opts.SortBy != "" {
+		queryString.Set(baggageclaim.SortQueryKey, it.opts.SortBy)
+	}
+
+	// only ask for what the client's volumes hold
+	if it.opts.IncludeUsage {
+		queryString.Set(baggageclaim.FieldsQueryKey, "handle,path,usage")
+	} else {
+		queryString.Set(baggageclaim.FieldsQueryKey, "handle,path")
+	}
+
+	return queryString
+}
@@ -0,0 +1,146 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/api"
+	"github.com/concourse/baggageclaim/client"
+)
+
+var _ = Describe("paging through volumes", func() {
+	var (
+		gServer *ghttp.Server
+		c       client.Client
+	)
+
+	BeforeEach(func() {
+		gServer = ghttp.NewServer()
+		c = client.New(gServer.URL(), http.DefaultTransport)
+	})
+
+	AfterEach(func() {
+		gServer.Close()
+	})
+
+	handlesOf := func(volumes *client.VolumeIterator) []string {
+		handles := []string{}
+		for volumes.Next() {
+			handles = append(handles, volumes.Volume().Handle())
+		}
+
+		return handles
+	}
+
+	nextPage := http.Header{"Link": {`</volumes?next=page>; rel="next"`}}
+
+	It("follows the links to the next pages until the listing ends", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes", url.Values{
+					"some":                      {"property"},
+					baggageclaim.LimitQueryKey:  {"2"},
+					baggageclaim.SortQueryKey:   {"-created"},
+					baggageclaim.FieldsQueryKey: {"handle,path"},
+				}.Encode()),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.VolumeResponse{
+					{Handle: "handle-1"},
+					{Handle: "handle-2"},
+				}, http.Header{"Link": {`</volumes?some=property&next=page>; rel="next"`}}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes", "next=page&some=property"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.VolumeResponse{
+					{Handle: "handle-3"},
+				}),
+			),
+		)
+
+		volumes := c.ListVolumePages(lagertest.NewTestLogger("test"), client.ListVolumesOptions{
+			Properties: baggageclaim.VolumeProperties{"some": "property"},
+			SortBy:     "-" + client.SortByCreatedAt,
+			PageSize:   2,
+		})
+
+		Expect(handlesOf(volumes)).To(Equal([]string{"handle-1", "handle-2", "handle-3"}))
+		Expect(volumes.Err()).ToNot(HaveOccurred())
+		Expect(gServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("asks for the usage if it is to be included", func() {
+		gServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/volumes", url.Values{
+					baggageclaim.LimitQueryKey:  {"500"},
+					baggageclaim.FieldsQueryKey: {"handle,path,usage"},
+				}.Encode()),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.VolumeResponse{
+					{Handle: "handle-1", Usage: &baggageclaim.VolumeUsage{ExclusiveBytes: 42}},
+				}),
+			),
+		)
+
+		volumes := c.ListVolumePages(lagertest.NewTestLogger("test"), client.ListVolumesOptions{
+			IncludeUsage: true,
+		})
+
+		Expect(volumes.Next()).To(BeTrue())
+
+		usage, err := volumes.Volume().Usage()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(usage.ExclusiveBytes).To(Equal(uint64(42)))
+
+		Expect(volumes.Next()).To(BeFalse())
+	})
+
+	It("only follows links to the next page", func() {
+		gServer.AppendHandlers(
+			ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.VolumeResponse{
+				{Handle: "handle-1"},
+			}, http.Header{"Link": {`</volumes?first=page>; rel="first"`}}),
+		)
+
+		volumes := c.ListVolumePages(lagertest.NewTestLogger("test"), client.ListVolumesOptions{})
+		Expect(handlesOf(volumes)).To(Equal([]string{"handle-1"}))
+		Expect(gServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("skips over empty pages", func() {
+		gServer.AppendHandlers(
+			ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.VolumeResponse{}, nextPage),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.VolumeResponse{
+				{Handle: "handle-1"},
+			}),
+		)
+
+		volumes := c.ListVolumePages(lagertest.NewTestLogger("test"), client.ListVolumesOptions{})
+		Expect(handlesOf(volumes)).To(Equal([]string{"handle-1"}))
+	})
+
+	Context("when fetching a page fails", func() {
+		BeforeEach(func() {
+			gServer.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, []baggageclaim.VolumeResponse{
+					{Handle: "handle-1"},
+				}, nextPage),
+				ghttp.RespondWithJSONEncoded(http.StatusBadRequest, api.ErrorResponse{
+					Message: api.ErrListVolumesInvalidContinue.Error(),
+				}),
+			)
+		})
+
+		It("stops with the error", func() {
+			volumes := c.ListVolumePages(lagertest.NewTestLogger("test"), client.ListVolumesOptions{})
+			Expect(handlesOf(volumes)).To(Equal([]string{"handle-1"}))
+			Expect(volumes.Err()).To(Equal(errors.New(api.ErrListVolumesInvalidContinue.Error())))
+			Expect(volumes.Next()).To(BeFalse())
+		})
+	})
+})
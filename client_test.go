@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
@@ -89,6 +91,104 @@ var _ = Describe("Baggage Claim Client", func() {
 			})
 		})
 
+		Describe("Listing volumes with usage", func() {
+			It("requests the usage and returns it from the volumes", func() {
+				bcServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/volumes", url.Values{baggageclaim.IncludeUsageQueryKey: {"true"}}.Encode()),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, []volume.Volume{
+							{
+								Handle:     "some-handle",
+								Path:       "some-path",
+								Properties: volume.Properties{},
+								Usage: &volume.VolumeUsage{
+									ExclusiveBytes: 10,
+									SharedBytes:    20,
+									Inodes:         3,
+								},
+							},
+						}),
+					),
+				)
+
+				volumes, err := bcClient.ListVolumesWithUsage(logger, nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(volumes).To(HaveLen(1))
+
+				usage, err := volumes[0].Usage()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(usage).To(Equal(baggageclaim.VolumeUsage{
+					ExclusiveBytes: 10,
+					SharedBytes:    20,
+					Inodes:         3,
+				}))
+
+				Expect(bcServer.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		Describe("Getting the usage of a volume", func() {
+			It("fetches the usage from the server", func() {
+				bcServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/volumes/some-handle"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, volume.Volume{
+							Handle:     "some-handle",
+							Path:       "some-path",
+							Properties: volume.Properties{},
+						}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/volumes/some-handle/usage"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, volume.VolumeUsage{
+							ExclusiveBytes: 10,
+							Inodes:         1,
+						}),
+					),
+				)
+
+				vol, found, err := bcClient.LookupVolume(logger, "some-handle")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				usage, err := vol.Usage()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(usage).To(Equal(baggageclaim.VolumeUsage{
+					ExclusiveBytes: 10,
+					Inodes:         1,
+				}))
+			})
+		})
+
+		Describe("Getting the digest of a volume", func() {
+			It("fetches the digest from the server", func() {
+				bcServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/volumes/some-handle"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, volume.Volume{
+							Handle:     "some-handle",
+							Path:       "some-path",
+							Properties: volume.Properties{},
+						}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/volumes/some-handle/digest"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, baggageclaim.DigestResponse{
+							Digest: "sha256:some-digest",
+						}),
+					),
+				)
+
+				vol, found, err := bcClient.LookupVolume(logger, "some-handle")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				digest, err := vol.Digest()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(digest).To(Equal("sha256:some-digest"))
+			})
+		})
+
 		Describe("Destroying volumes", func() {
 			Context("when all volumes are destroyed as requested", func() {
 				var handles = []string{"some-handle"}
@@ -189,6 +289,79 @@ var _ = Describe("Baggage Claim Client", func() {
 					Expect(err.Error()).To(Equal("lost baggage"))
 				})
 			})
+
+			Context("when no volume has the requested digest", func() {
+				It("returns ErrDigestNotFound", func() {
+					bcServer.AppendHandlers(
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest("POST", "/volumes-async"),
+							ghttp.RespondWithJSONEncoded(http.StatusCreated, baggageclaim.VolumeFutureResponse{
+								Handle: "some-handle",
+							}),
+						),
+					)
+					mockErrorResponse("GET", "/volumes-async/some-handle", "no volume found with digest", 422)
+					bcServer.AppendHandlers(
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest("DELETE", "/volumes-async/some-handle"),
+							ghttp.RespondWith(http.StatusNoContent, ""),
+						),
+					)
+
+					_, err := bcClient.CreateVolume(logger, "some-handle", baggageclaim.VolumeSpec{
+						Strategy: baggageclaim.DedupeStrategy{Digest: "sha256:some-digest"},
+					})
+					Expect(err).To(Equal(baggageclaim.ErrDigestNotFound))
+				})
+			})
+
+			Context("with a TTL", func() {
+				var requestedTTL uint
+
+				BeforeEach(func() {
+					bcServer.AppendHandlers(
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest("POST", "/volumes-async"),
+							func(w http.ResponseWriter, r *http.Request) {
+								var request baggageclaim.VolumeRequest
+								Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+								requestedTTL = request.TTLInSeconds
+							},
+							ghttp.RespondWithJSONEncoded(http.StatusCreated, baggageclaim.VolumeFutureResponse{
+								Handle: "some-handle",
+							}),
+						),
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest("GET", "/volumes-async/some-handle"),
+							ghttp.RespondWithJSONEncoded(http.StatusOK, volume.Volume{
+								Handle:     "some-handle",
+								Path:       "some-path",
+								Properties: volume.Properties{},
+							}),
+						),
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest("DELETE", "/volumes-async/some-handle"),
+							ghttp.RespondWith(http.StatusNoContent, ""),
+						),
+					)
+				})
+
+				It("sends the ttl in seconds, rounded up", func() {
+					_, err := bcClient.CreateVolume(logger, "some-handle", baggageclaim.VolumeSpec{
+						TTL: 90*time.Second + time.Millisecond,
+					})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(requestedTTL).To(Equal(uint(91)))
+				})
+
+				It("does not round a ttl of under a second down to no expiry", func() {
+					_, err := bcClient.CreateVolume(logger, "some-handle", baggageclaim.VolumeSpec{
+						TTL: 500 * time.Millisecond,
+					})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(requestedTTL).To(Equal(uint(1)))
+				})
+			})
 		})
 
 		Describe("Stream in a volume", func() {
@@ -246,6 +419,14 @@ var _ = Describe("Baggage Claim Client", func() {
 					Expect(err.Error()).To(Equal("lost baggage"))
 				})
 			})
+
+			Context("when the volume's quota is exceeded", func() {
+				It("returns ErrQuotaExceeded", func() {
+					mockErrorResponse("PUT", "/volumes/some-handle/stream-in", api.ErrStreamInQuotaExceeded.Error(), http.StatusRequestEntityTooLarge)
+					err := vol.StreamIn(context.TODO(), ".", baggageclaim.GzipEncoding, strings.NewReader("too much tar"))
+					Expect(err).To(Equal(baggageclaim.ErrQuotaExceeded))
+				})
+			})
 		})
 
 		Describe("Stream out a volume", func() {
@@ -363,6 +544,78 @@ var _ = Describe("Baggage Claim Client", func() {
 			})
 		})
 
+		Describe("Setting the ttl of a volume", func() {
+			It("sends the ttl in seconds", func() {
+				bcServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/volumes/some-handle"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, volume.Volume{
+							Handle:     "some-handle",
+							Path:       "some-path",
+							Properties: volume.Properties{},
+						}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/volumes/some-handle/ttl"),
+						ghttp.VerifyJSONRepresenting(baggageclaim.TTLRequest{Value: 90}),
+						ghttp.RespondWith(http.StatusNoContent, nil),
+					),
+				)
+
+				vol, found, err := bcClient.LookupVolume(logger, "some-handle")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				err = vol.SetTTL(90 * time.Second)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("rounds a ttl of under a second up rather than removing the expiry", func() {
+				bcServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/volumes/some-handle"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, volume.Volume{
+							Handle:     "some-handle",
+							Path:       "some-path",
+							Properties: volume.Properties{},
+						}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/volumes/some-handle/ttl"),
+						ghttp.VerifyJSONRepresenting(baggageclaim.TTLRequest{Value: 1}),
+						ghttp.RespondWith(http.StatusNoContent, nil),
+					),
+				)
+
+				vol, found, err := bcClient.LookupVolume(logger, "some-handle")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				err = vol.SetTTL(500 * time.Millisecond)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns ErrVolumeNotFound when the volume is gone", func() {
+				bcServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/volumes/some-handle"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, volume.Volume{
+							Handle:     "some-handle",
+							Path:       "some-path",
+							Properties: volume.Properties{},
+						}),
+					),
+				)
+				mockErrorResponse("PUT", "/volumes/some-handle/ttl", "failed to set ttl on volume", http.StatusNotFound)
+
+				vol, _, err := bcClient.LookupVolume(logger, "some-handle")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = vol.SetTTL(time.Minute)
+				Expect(err).To(Equal(baggageclaim.ErrVolumeNotFound))
+			})
+		})
+
 		Describe("Get p2p stream-in url", func() {
 			var vol baggageclaim.Volume
 			BeforeEach(func() {
@@ -4,3 +4,14 @@ import "errors"
 
 var ErrVolumeNotFound = errors.New("volume not found")
 var ErrFileNotFound = errors.New("file not found")
+var ErrQuotaExceeded = errors.New("volume quota exceeded")
+var ErrDigestNotFound = errors.New("no volume found with digest")
+var ErrVolumeHasNoParent = errors.New("volume has no parent")
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+var ErrSnapshotAlreadyExists = errors.New("snapshot already exists")
+var ErrVolumeHasChildren = errors.New("volume has children")
+var ErrVolumeInUse = errors.New("volume is in use")
+var ErrVolumeIsReadOnly = errors.New("volume is read-only")
+var ErrVolumeAlreadyExists = errors.New("volume already exists")
+var ErrUnauthorized = errors.New("unauthorized")
+var ErrForbidden = errors.New("forbidden")
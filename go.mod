@@ -8,17 +8,17 @@ require (
 	github.com/concourse/go-archive v0.0.0-20180803203406-784931698f4f
 	github.com/concourse/retryhttp v0.0.0-20170802173037-937335fd9545
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible // indirect
-	github.com/google/go-cmp v0.4.0 // indirect
 	github.com/jessevdk/go-flags v1.4.0
 	github.com/klauspost/compress v1.9.7
 	github.com/nu7hatch/gouuid v0.0.0-20131221200532-179d4d0c4d8d
 	github.com/onsi/ginkgo v1.8.0
 	github.com/onsi/gomega v1.5.0
+	github.com/pierrec/lz4/v4 v4.1.4
+	github.com/prometheus/client_golang v1.7.1
 	github.com/tedsuo/ifrit v0.0.0-20180802180643-bea94bb476cc
 	github.com/tedsuo/rata v1.0.1-0.20170830210128-07d200713958
-	golang.org/x/crypto v0.0.0-20180910181607-0e37d006457b // indirect
-	golang.org/x/net v0.0.0-20180911220305-26e67e76b6c3 // indirect
-	golang.org/x/sys v0.0.0-20180918153733-ee1b12c67af4
+	github.com/ulikunitz/xz v0.5.8
+	golang.org/x/sys v0.0.0-20200615200032-f1bc736245b1
 )
 
 go 1.13
@@ -0,0 +1,47 @@
+package integration_test
+
+import (
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Paging", func() {
+	var (
+		runner   *BaggageClaimRunner
+		bcClient client.Client
+	)
+
+	BeforeEach(func() {
+		runner = NewRunner(baggageClaimPath, "naive")
+		runner.Start()
+
+		bcClient = runner.Client().(client.Client)
+	})
+
+	AfterEach(func() {
+		runner.Stop()
+		runner.Cleanup()
+	})
+
+	It("pages through the volumes in the order they were created", func() {
+		for _, handle := range []string{"handle-c", "handle-a", "handle-b"} {
+			_, err := bcClient.CreateVolume(logger, handle, baggageclaim.VolumeSpec{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		volumes := bcClient.ListVolumePages(logger, client.ListVolumesOptions{
+			SortBy:   client.SortByCreatedAt,
+			PageSize: 2,
+		})
+
+		handles := []string{}
+		for volumes.Next() {
+			handles = append(handles, volumes.Volume().Handle())
+		}
+
+		Expect(volumes.Err()).NotTo(HaveOccurred())
+		Expect(handles).To(Equal([]string{"handle-c", "handle-a", "handle-b"}))
+	})
+})
@@ -1,6 +1,9 @@
 package integration_test
 
 import (
+	"os"
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -35,4 +38,26 @@ var _ = Describe("Restarting", func() {
 
 		Expect(runner.CurrentHandles()).To(ConsistOf(createdVolume.Handle()))
 	})
+
+	It("destroys volumes orphaned in the init and dead directories", func() {
+		createdVolume, err := client.CreateVolume(logger, "some-handle", baggageclaim.VolumeSpec{})
+		Expect(err).NotTo(HaveOccurred())
+
+		runner.Stop()
+
+		initOrphan := filepath.Join(runner.VolumeDir(), "init", "init-orphan")
+		err = os.MkdirAll(filepath.Join(initOrphan, "volume"), 0755)
+		Expect(err).NotTo(HaveOccurred())
+
+		deadOrphan := filepath.Join(runner.VolumeDir(), "dead", "dead-orphan")
+		err = os.MkdirAll(filepath.Join(deadOrphan, "volume"), 0755)
+		Expect(err).NotTo(HaveOccurred())
+
+		runner.Start()
+
+		Eventually(initOrphan).ShouldNot(BeADirectory())
+		Eventually(deadOrphan).ShouldNot(BeADirectory())
+
+		Expect(runner.CurrentHandles()).To(ConsistOf(createdVolume.Handle()))
+	})
 })
@@ -72,9 +72,10 @@ type BaggageClaimRunner struct {
 	port      int
 	volumeDir string
 	driver    string
+	extraArgs []string
 }
 
-func NewRunner(path string, driver string) *BaggageClaimRunner {
+func NewRunner(path string, driver string, extraArgs ...string) *BaggageClaimRunner {
 	port := 7788 + GinkgoParallelNode()
 
 	volumeDir, err := ioutil.TempDir("", fmt.Sprintf("baggageclaim_volume_dir_%d", GinkgoParallelNode()))
@@ -88,6 +89,7 @@ func NewRunner(path string, driver string) *BaggageClaimRunner {
 		port:      port,
 		volumeDir: volumeDir,
 		driver:    driver,
+		extraArgs: extraArgs,
 	}
 }
 
@@ -96,11 +98,13 @@ func (bcr *BaggageClaimRunner) Start() {
 		Name: "baggageclaim",
 		Command: exec.Command(
 			bcr.path,
-			"--bind-port", strconv.Itoa(bcr.port),
-			"--debug-bind-port", strconv.Itoa(8099+GinkgoParallelNode()),
-			"--volumes", bcr.volumeDir,
-			"--driver", bcr.driver,
-			"--overlays-dir", filepath.Join(bcr.volumeDir, "overlays"),
+			append([]string{
+				"--bind-port", strconv.Itoa(bcr.port),
+				"--debug-bind-port", strconv.Itoa(8099 + GinkgoParallelNode()),
+				"--volumes", bcr.volumeDir,
+				"--driver", bcr.driver,
+				"--overlays-dir", filepath.Join(bcr.volumeDir, "overlays"),
+			}, bcr.extraArgs...)...,
 		),
 		StartCheck: "baggageclaim.listening",
 	})
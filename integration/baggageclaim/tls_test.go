@@ -0,0 +1,143 @@
+package integration_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLS", func() {
+	var (
+		runner  *BaggageClaimRunner
+		certDir string
+
+		caCertPath string
+		certPath   string
+		keyPath    string
+	)
+
+	BeforeEach(func() {
+		var err error
+		certDir, err = ioutil.TempDir("", "baggageclaim-tls")
+		Expect(err).NotTo(HaveOccurred())
+
+		caCert, caKey := issueCertificate(nil, nil)
+		cert, key := issueCertificate(caCert, caKey)
+
+		caCertPath = filepath.Join(certDir, "ca.crt")
+		writeCertificate(caCertPath, caCert)
+
+		certPath = filepath.Join(certDir, "baggageclaim.crt")
+		writeCertificate(certPath, cert)
+
+		keyPath = filepath.Join(certDir, "baggageclaim.key")
+		writeKey(keyPath, key)
+
+		runner = NewRunner(baggageClaimPath, "naive",
+			"--tls-cert", certPath,
+			"--tls-key", keyPath,
+			"--tls-ca-cert", caCertPath,
+			"--tls-verify-client-certs",
+		)
+		runner.Start()
+	})
+
+	AfterEach(func() {
+		runner.Stop()
+		runner.Cleanup()
+
+		Expect(os.RemoveAll(certDir)).To(Succeed())
+	})
+
+	tlsClient := func(certPath, keyPath string) baggageclaim.Client {
+		tlsConfig, err := client.NewTLSConfig(certPath, keyPath, caCertPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		return client.NewWithTLSConfig(fmt.Sprintf("https://localhost:%d", runner.Port()), tlsConfig)
+	}
+
+	It("serves clients presenting a certificate signed by the CA", func() {
+		bcClient := tlsClient(certPath, keyPath)
+
+		volume, err := bcClient.CreateVolume(logger, "some-handle", baggageclaim.VolumeSpec{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(volume.Handle()).To(Equal("some-handle"))
+
+		volumes, err := bcClient.ListVolumes(logger, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(volumes).To(HaveLen(1))
+	})
+
+	It("refuses clients without a certificate", func() {
+		_, err := tlsClient("", "").ListVolumes(logger, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("refuses clients that do not speak TLS", func() {
+		_, err := runner.Client().ListVolumes(logger, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// issueCertificate returns a certificate for localhost signed by the given
+// CA, or a self-signed CA certificate if there is none.
+func issueCertificate(ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	if ca == nil {
+		template.Subject = pkix.Name{CommonName: "baggageclaim-ca"}
+		template.KeyUsage |= x509.KeyUsageCertSign
+		template.BasicConstraintsValid = true
+		template.IsCA = true
+
+		ca, caKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+
+	return cert, key
+}
+
+func writeCertificate(path string, cert *x509.Certificate) {
+	err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0644)
+	Expect(err).NotTo(HaveOccurred())
+}
+
+func writeKey(path string, key *ecdsa.PrivateKey) {
+	der, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600)
+	Expect(err).NotTo(HaveOccurred())
+}
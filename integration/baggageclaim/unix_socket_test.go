@@ -0,0 +1,89 @@
+package integration_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Unix socket", func() {
+	var (
+		runner     *BaggageClaimRunner
+		socketDir  string
+		socketPath string
+		extraArgs  []string
+	)
+
+	BeforeEach(func() {
+		var err error
+		socketDir, err = ioutil.TempDir("", "baggageclaim-socket")
+		Expect(err).NotTo(HaveOccurred())
+
+		socketPath = filepath.Join(socketDir, "baggageclaim.sock")
+
+		extraArgs = []string{"--bind-socket", socketPath, "--bind-socket-mode", "0600"}
+	})
+
+	JustBeforeEach(func() {
+		runner = NewRunner(baggageClaimPath, "naive", extraArgs...)
+		runner.Start()
+	})
+
+	AfterEach(func() {
+		runner.Stop()
+		runner.Cleanup()
+
+		Expect(os.RemoveAll(socketDir)).To(Succeed())
+	})
+
+	It("serves the API on the socket as well as over TCP", func() {
+		_, err := client.New("unix://"+socketPath, nil).CreateVolume(logger, "some-handle", baggageclaim.VolumeSpec{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(runner.CurrentHandles()).To(ConsistOf("some-handle"))
+	})
+
+	It("gives the socket its permissions", func() {
+		info, err := os.Stat(socketPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode() & os.ModeSocket).ToNot(BeZero())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	Context("when a stale socket is left behind", func() {
+		BeforeEach(func() {
+			listener, err := net.Listen("unix", socketPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			// leave the socket behind, as a crash would
+			listener.(*net.UnixListener).SetUnlinkOnClose(false)
+			Expect(listener.Close()).To(Succeed())
+		})
+
+		It("replaces it", func() {
+			_, err := client.New("unix://"+socketPath, nil).ListVolumes(logger, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when TCP is disabled", func() {
+		BeforeEach(func() {
+			extraArgs = append(extraArgs, "--disable-tcp")
+		})
+
+		It("only serves the API on the socket", func() {
+			_, err := client.New("unix://"+socketPath, nil).ListVolumes(logger, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", runner.Port()))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
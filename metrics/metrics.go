@@ -0,0 +1,124 @@
+// Package metrics defines the Prometheus metrics exported by baggageclaim.
+//
+// All metrics are registered with the default registry, which is served on
+// the debug server's /metrics endpoint.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "baggageclaim"
+
+var (
+	RequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of API requests handled, by route and status code.",
+		},
+		[]string{"route", "code"},
+	)
+
+	RequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Time taken to handle API requests, by route.",
+			Buckets:   []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300, 900},
+		},
+		[]string{"route"},
+	)
+
+	StreamedBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "volume",
+			Name:      "streamed_bytes_total",
+			Help:      "Bytes streamed into or out of volumes, by direction and encoding.",
+		},
+		[]string{"direction", "encoding"},
+	)
+
+	CreationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "volume",
+			Name:      "creation_duration_seconds",
+			Help:      "Time taken to create volumes, by strategy.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+		},
+		[]string{"strategy"},
+	)
+
+	OrphansReclaimed = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "volume",
+			Name:      "orphans_reclaimed_total",
+			Help:      "Orphaned volumes left in the init and dead directories that have been destroyed.",
+		},
+	)
+
+	LockWaitDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "volume",
+			Name:      "lock_wait_duration_seconds",
+			Help:      "Time spent waiting to acquire per-volume locks.",
+			Buckets:   []float64{0.0001, 0.001, 0.01, 0.1, 1, 10, 60},
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		RequestsTotal,
+		RequestDuration,
+		StreamedBytes,
+		CreationDuration,
+		OrphansReclaimed,
+		LockWaitDuration,
+	)
+}
+
+// VolumeStateCollector reports the number of volumes in each lifecycle state
+// (init, live, dead) at scrape time.
+type VolumeStateCollector struct {
+	countVolumes func() (map[string]int, error)
+
+	desc *prometheus.Desc
+}
+
+// NewVolumeStateCollector returns a collector that calls countVolumes on every
+// scrape. countVolumes should return the number of volumes keyed by state.
+func NewVolumeStateCollector(countVolumes func() (map[string]int, error)) *VolumeStateCollector {
+	return &VolumeStateCollector{
+		countVolumes: countVolumes,
+
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "volume", "count"),
+			"Number of volumes on disk, by state.",
+			[]string{"state"},
+			nil,
+		),
+	}
+}
+
+func (collector *VolumeStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.desc
+}
+
+func (collector *VolumeStateCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := collector.countVolumes()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(collector.desc, err)
+		return
+	}
+
+	for state, count := range counts {
+		ch <- prometheus.MustNewConstMetric(collector.desc, prometheus.GaugeValue, float64(count), state)
+	}
+}
@@ -0,0 +1,68 @@
+package metrics_test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/metrics"
+)
+
+var _ = Describe("VolumeStateCollector", func() {
+	var (
+		counts   map[string]int
+		countErr error
+
+		registry *prometheus.Registry
+	)
+
+	BeforeEach(func() {
+		counts = map[string]int{}
+		countErr = nil
+
+		registry = prometheus.NewPedanticRegistry()
+		registry.MustRegister(metrics.NewVolumeStateCollector(func() (map[string]int, error) {
+			return counts, countErr
+		}))
+	})
+
+	It("reports the number of volumes in each state on every scrape", func() {
+		counts = map[string]int{"init": 1, "live": 3, "dead": 0}
+
+		err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP baggageclaim_volume_count Number of volumes on disk, by state.
+# TYPE baggageclaim_volume_count gauge
+baggageclaim_volume_count{state="dead"} 0
+baggageclaim_volume_count{state="init"} 1
+baggageclaim_volume_count{state="live"} 3
+`))
+		Expect(err).NotTo(HaveOccurred())
+
+		counts = map[string]int{"init": 0, "live": 4, "dead": 0}
+
+		err = testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP baggageclaim_volume_count Number of volumes on disk, by state.
+# TYPE baggageclaim_volume_count gauge
+baggageclaim_volume_count{state="dead"} 0
+baggageclaim_volume_count{state="init"} 0
+baggageclaim_volume_count{state="live"} 4
+`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("when counting the volumes fails", func() {
+		BeforeEach(func() {
+			countErr = errors.New("oh no")
+		})
+
+		It("fails the scrape", func() {
+			_, err := registry.Gather()
+			Expect(err).To(MatchError(ContainSubstring("oh no")))
+		})
+	})
+})
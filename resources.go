@@ -2,6 +2,7 @@ package baggageclaim
 
 import (
 	"encoding/json"
+	"time"
 )
 
 type VolumeRequest struct {
@@ -9,12 +10,19 @@ type VolumeRequest struct {
 	Strategy     *json.RawMessage `json:"strategy"`
 	Properties   VolumeProperties `json:"properties"`
 	Privileged   bool             `json:"privileged,omitempty"`
+	QuotaBytes   uint64           `json:"quota_bytes,omitempty"`
+	TTLInSeconds uint             `json:"ttl,omitempty"`
+	ReadOnly     bool             `json:"read_only,omitempty"`
 }
 
 type VolumeResponse struct {
 	Handle       string           `json:"handle"`
 	Path         string           `json:"path"`
 	Properties   VolumeProperties `json:"properties"`
+	Usage        *VolumeUsage     `json:"usage,omitempty"`
+	TTLInSeconds *uint            `json:"ttl,omitempty"`
+	Snapshots    []Snapshot       `json:"snapshots,omitempty"`
+	ReadOnly     bool             `json:"read_only,omitempty"`
 }
 
 type VolumeFutureResponse struct {
@@ -28,3 +36,70 @@ type PropertyRequest struct {
 type PrivilegedRequest struct {
 	Value bool `json:"value"`
 }
+
+type TTLRequest struct {
+	Value uint `json:"value"`
+}
+
+type RenameRequest struct {
+	Handle string `json:"handle"`
+}
+
+type SnapshotRequest struct {
+	Handle string `json:"handle"`
+}
+
+// Snapshot is a read-only copy of a volume's contents as they were when it
+// was created.
+type Snapshot struct {
+	Handle    string    `json:"handle"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DigestResponse struct {
+	Digest string `json:"digest"`
+}
+
+// UploadOffsetHeader is set on responses about resumable stream-ins to how
+// much of the stream the upload session has received.
+const UploadOffsetHeader = "Upload-Offset"
+
+// ListVolumes reserves the query parameters starting with ReservedQueryPrefix
+// to page through, sort, and trim the listing, and to include the volumes'
+// usage. Every other parameter filters by a property, so property filters
+// cannot start with it.
+//
+// Listings that stop at their limit link to the next page with a Link header
+// whose rel is "next". Listings streamed as NDJSON send it as a trailer.
+const ReservedQueryPrefix = "$"
+
+const (
+	LimitQueryKey        = ReservedQueryPrefix + "limit"
+	ContinueQueryKey     = ReservedQueryPrefix + "continue"
+	SortQueryKey         = ReservedQueryPrefix + "sort"
+	FieldsQueryKey       = ReservedQueryPrefix + "fields"
+	IncludeUsageQueryKey = ReservedQueryPrefix + "include_usage"
+)
+
+type StreamInOffsetResponse struct {
+	Offset int64 `json:"offset"`
+}
+
+type FsckRequest struct {
+	Repair     bool `json:"repair"`
+	Quarantine bool `json:"quarantine"`
+}
+
+type VolumeEvent struct {
+	Type   VolumeEventType `json:"type"`
+	Handle string          `json:"handle"`
+	Time   time.Time       `json:"time"`
+
+	Property   string `json:"property,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Privileged *bool  `json:"privileged,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Snapshot   string `json:"snapshot,omitempty"`
+
+	PreviousHandle string `json:"previous_handle,omitempty"`
+}
@@ -8,19 +8,40 @@ const (
 	CreateVolume   = "CreateVolume"
 	DestroyVolume  = "DestroyVolume"
 	DestroyVolumes = "DestroyVolumes"
+	RenameVolume   = "RenameVolume"
 
 	CreateVolumeAsync       = "CreateVolumeAsync"
 	CreateVolumeAsyncCancel = "CreateVolumeAsyncCancel"
 	CreateVolumeAsyncCheck  = "CreateVolumeAsyncCheck"
 
-	SetProperty   = "SetProperty"
-	GetPrivileged = "GetPrivileged"
-	SetPrivileged = "SetPrivileged"
-	StreamIn      = "StreamIn"
-	StreamOut     = "StreamOut"
-	StreamP2pOut  = "StreamP2pOut"
+	SetProperty    = "SetProperty"
+	GetPrivileged  = "GetPrivileged"
+	SetPrivileged  = "SetPrivileged"
+	SetTTL         = "SetTTL"
+	SetReadOnly    = "SetReadOnly"
+	GetUsage       = "GetUsage"
+	GetDigest      = "GetDigest"
+	StreamIn       = "StreamIn"
+	StreamInOffset = "StreamInOffset"
+	StreamOut      = "StreamOut"
+	StreamP2pOut   = "StreamP2pOut"
+	GetFile        = "GetFile"
+	GetTree        = "GetTree"
+	GetDiff        = "GetDiff"
+
+	CreateSnapshot  = "CreateSnapshot"
+	RestoreSnapshot = "RestoreSnapshot"
+	DestroySnapshot = "DestroySnapshot"
 
 	GetP2pUrl = "GetP2pUrl"
+
+	Events = "Events"
+
+	GetOrphans               = "GetOrphans"
+	ListCorruptedVolumes     = "ListCorruptedVolumes"
+	ListQuarantinedVolumes   = "ListQuarantinedVolumes"
+	DestroyQuarantinedVolume = "DestroyQuarantinedVolume"
+	Fsck                     = "Fsck"
 )
 
 var Routes = rata.Routes{
@@ -31,15 +52,35 @@ var Routes = rata.Routes{
 	{Path: "/volumes-async/:handle", Method: "GET", Name: CreateVolumeAsyncCheck},
 	{Path: "/volumes-async/:handle", Method: "DELETE", Name: CreateVolumeAsyncCancel},
 
+	{Path: "/volumes/corrupted", Method: "GET", Name: ListCorruptedVolumes},
 	{Path: "/volumes/:handle", Method: "GET", Name: GetVolume},
 	{Path: "/volumes/:handle/properties/:property", Method: "PUT", Name: SetProperty},
 	{Path: "/volumes/:handle/privileged", Method: "GET", Name: GetPrivileged},
 	{Path: "/volumes/:handle/privileged", Method: "PUT", Name: SetPrivileged},
+	{Path: "/volumes/:handle/ttl", Method: "PUT", Name: SetTTL},
+	{Path: "/volumes/:handle/readonly", Method: "PUT", Name: SetReadOnly},
+	{Path: "/volumes/:handle/usage", Method: "GET", Name: GetUsage},
+	{Path: "/volumes/:handle/digest", Method: "GET", Name: GetDigest},
 	{Path: "/volumes/:handle/stream-in", Method: "PUT", Name: StreamIn},
+	{Path: "/volumes/:handle/stream-in", Method: "GET", Name: StreamInOffset},
 	{Path: "/volumes/:handle/stream-out", Method: "PUT", Name: StreamOut},
 	{Path: "/volumes/:handle/stream-p2p-out", Method: "PUT", Name: StreamP2pOut},
+	{Path: "/volumes/:handle/files", Method: "GET", Name: GetFile},
+	{Path: "/volumes/:handle/tree", Method: "GET", Name: GetTree},
+	{Path: "/volumes/:handle/diff", Method: "GET", Name: GetDiff},
+	{Path: "/volumes/:handle/snapshots", Method: "POST", Name: CreateSnapshot},
+	{Path: "/volumes/:handle/snapshots/:snapshot", Method: "DELETE", Name: DestroySnapshot},
+	{Path: "/volumes/:handle/restore/:snapshot", Method: "POST", Name: RestoreSnapshot},
+	{Path: "/volumes/:handle/rename", Method: "POST", Name: RenameVolume},
 	{Path: "/volumes/destroy", Method: "DELETE", Name: DestroyVolumes},
 	{Path: "/volumes/:handle", Method: "DELETE", Name: DestroyVolume},
 
 	{Path: "/p2p-url", Method: "GET", Name: GetP2pUrl},
+
+	{Path: "/events", Method: "GET", Name: Events},
+
+	{Path: "/orphans", Method: "GET", Name: GetOrphans},
+	{Path: "/quarantined-volumes", Method: "GET", Name: ListQuarantinedVolumes},
+	{Path: "/quarantined-volumes/:handle", Method: "DELETE", Name: DestroyQuarantinedVolume},
+	{Path: "/fsck", Method: "POST", Name: Fsck},
 }
@@ -64,6 +64,24 @@ func findMapping(idMap []syscall.SysProcIDMap, fromID int) int {
 	return fromID
 }
 
+func findReverseMapping(idMap []syscall.SysProcIDMap, fromID int) int {
+	for _, id := range idMap {
+		if id.Size != 1 {
+			continue
+		}
+
+		if id.HostID == fromID {
+			return id.ContainerID
+		}
+	}
+
+	return fromID
+}
+
 func (m uidGidMapper) Map(fromUid int, fromGid int) (int, int) {
 	return findMapping(m.uids, fromUid), findMapping(m.gids, fromGid)
 }
+
+func (m uidGidMapper) Unmap(fromUid int, fromGid int) (int, int) {
+	return findReverseMapping(m.uids, fromUid), findReverseMapping(m.gids, fromGid)
+}
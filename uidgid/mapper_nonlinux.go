@@ -21,3 +21,7 @@ func (m noopMapper) Apply(cmd *exec.Cmd) {}
 func (m noopMapper) Map(fromUid int, fromGid int) (int, int) {
 	return fromUid, fromGid
 }
+
+func (m noopMapper) Unmap(fromUid int, fromGid int) (int, int) {
+	return fromUid, fromGid
+}
@@ -12,6 +12,11 @@ import (
 type Namespacer interface {
 	NamespacePath(logger lager.Logger, path string) error
 	NamespaceCommand(cmd *exec.Cmd)
+
+	// NamespaceIDs maps ownership as seen from within the namespace to the
+	// host, and UnnamespaceIDs maps it back again.
+	NamespaceIDs(uid, gid int) (int, int)
+	UnnamespaceIDs(uid, gid int) (int, int)
 }
 
 type UidNamespacer struct {
@@ -38,7 +43,17 @@ func (n *UidNamespacer) NamespaceCommand(cmd *exec.Cmd) {
 	n.Translator.TranslateCommand(cmd)
 }
 
+func (n *UidNamespacer) NamespaceIDs(uid, gid int) (int, int) {
+	return n.Translator.TranslateIDs(uid, gid)
+}
+
+func (n *UidNamespacer) UnnamespaceIDs(uid, gid int) (int, int) {
+	return n.Translator.UntranslateIDs(uid, gid)
+}
+
 type NoopNamespacer struct{}
 
 func (NoopNamespacer) NamespacePath(lager.Logger, string) error { return nil }
 func (NoopNamespacer) NamespaceCommand(cmd *exec.Cmd)           {}
+func (NoopNamespacer) NamespaceIDs(uid, gid int) (int, int)     { return uid, gid }
+func (NoopNamespacer) UnnamespaceIDs(uid, gid int) (int, int)   { return uid, gid }
@@ -10,6 +10,8 @@ import (
 type Translator interface {
 	TranslatePath(path string, info os.FileInfo, err error) error
 	TranslateCommand(*exec.Cmd)
+	TranslateIDs(uid, gid int) (int, int)
+	UntranslateIDs(uid, gid int) (int, int)
 }
 
 type translator struct {
@@ -20,6 +22,7 @@ type translator struct {
 
 type Mapper interface {
 	Map(int, int) (int, int)
+	Unmap(int, int) (int, int)
 	Apply(*exec.Cmd)
 }
 
@@ -54,3 +57,11 @@ func (t *translator) TranslatePath(path string, info os.FileInfo, err error) err
 func (t *translator) TranslateCommand(cmd *exec.Cmd) {
 	t.setuidgid(cmd)
 }
+
+func (t *translator) TranslateIDs(uid, gid int) (int, int) {
+	return t.mapper.Map(uid, gid)
+}
+
+func (t *translator) UntranslateIDs(uid, gid int) (int, int) {
+	return t.mapper.Unmap(uid, gid)
+}
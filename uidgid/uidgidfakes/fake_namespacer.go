@@ -15,6 +15,20 @@ type FakeNamespacer struct {
 	namespaceCommandArgsForCall []struct {
 		arg1 *exec.Cmd
 	}
+	NamespaceIDsStub        func(int, int) (int, int)
+	namespaceIDsMutex       sync.RWMutex
+	namespaceIDsArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	namespaceIDsReturns struct {
+		result1 int
+		result2 int
+	}
+	namespaceIDsReturnsOnCall map[int]struct {
+		result1 int
+		result2 int
+	}
 	NamespacePathStub        func(lager.Logger, string) error
 	namespacePathMutex       sync.RWMutex
 	namespacePathArgsForCall []struct {
@@ -27,6 +41,20 @@ type FakeNamespacer struct {
 	namespacePathReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UnnamespaceIDsStub        func(int, int) (int, int)
+	unnamespaceIDsMutex       sync.RWMutex
+	unnamespaceIDsArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	unnamespaceIDsReturns struct {
+		result1 int
+		result2 int
+	}
+	unnamespaceIDsReturnsOnCall map[int]struct {
+		result1 int
+		result2 int
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -36,9 +64,10 @@ func (fake *FakeNamespacer) NamespaceCommand(arg1 *exec.Cmd) {
 	fake.namespaceCommandArgsForCall = append(fake.namespaceCommandArgsForCall, struct {
 		arg1 *exec.Cmd
 	}{arg1})
+	stub := fake.NamespaceCommandStub
 	fake.recordInvocation("NamespaceCommand", []interface{}{arg1})
 	fake.namespaceCommandMutex.Unlock()
-	if fake.NamespaceCommandStub != nil {
+	if stub != nil {
 		fake.NamespaceCommandStub(arg1)
 	}
 }
@@ -62,6 +91,71 @@ func (fake *FakeNamespacer) NamespaceCommandArgsForCall(i int) *exec.Cmd {
 	return argsForCall.arg1
 }
 
+func (fake *FakeNamespacer) NamespaceIDs(arg1 int, arg2 int) (int, int) {
+	fake.namespaceIDsMutex.Lock()
+	ret, specificReturn := fake.namespaceIDsReturnsOnCall[len(fake.namespaceIDsArgsForCall)]
+	fake.namespaceIDsArgsForCall = append(fake.namespaceIDsArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.NamespaceIDsStub
+	fakeReturns := fake.namespaceIDsReturns
+	fake.recordInvocation("NamespaceIDs", []interface{}{arg1, arg2})
+	fake.namespaceIDsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeNamespacer) NamespaceIDsCallCount() int {
+	fake.namespaceIDsMutex.RLock()
+	defer fake.namespaceIDsMutex.RUnlock()
+	return len(fake.namespaceIDsArgsForCall)
+}
+
+func (fake *FakeNamespacer) NamespaceIDsCalls(stub func(int, int) (int, int)) {
+	fake.namespaceIDsMutex.Lock()
+	defer fake.namespaceIDsMutex.Unlock()
+	fake.NamespaceIDsStub = stub
+}
+
+func (fake *FakeNamespacer) NamespaceIDsArgsForCall(i int) (int, int) {
+	fake.namespaceIDsMutex.RLock()
+	defer fake.namespaceIDsMutex.RUnlock()
+	argsForCall := fake.namespaceIDsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeNamespacer) NamespaceIDsReturns(result1 int, result2 int) {
+	fake.namespaceIDsMutex.Lock()
+	defer fake.namespaceIDsMutex.Unlock()
+	fake.NamespaceIDsStub = nil
+	fake.namespaceIDsReturns = struct {
+		result1 int
+		result2 int
+	}{result1, result2}
+}
+
+func (fake *FakeNamespacer) NamespaceIDsReturnsOnCall(i int, result1 int, result2 int) {
+	fake.namespaceIDsMutex.Lock()
+	defer fake.namespaceIDsMutex.Unlock()
+	fake.NamespaceIDsStub = nil
+	if fake.namespaceIDsReturnsOnCall == nil {
+		fake.namespaceIDsReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 int
+		})
+	}
+	fake.namespaceIDsReturnsOnCall[i] = struct {
+		result1 int
+		result2 int
+	}{result1, result2}
+}
+
 func (fake *FakeNamespacer) NamespacePath(arg1 lager.Logger, arg2 string) error {
 	fake.namespacePathMutex.Lock()
 	ret, specificReturn := fake.namespacePathReturnsOnCall[len(fake.namespacePathArgsForCall)]
@@ -69,15 +163,16 @@ func (fake *FakeNamespacer) NamespacePath(arg1 lager.Logger, arg2 string) error
 		arg1 lager.Logger
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.NamespacePathStub
+	fakeReturns := fake.namespacePathReturns
 	fake.recordInvocation("NamespacePath", []interface{}{arg1, arg2})
 	fake.namespacePathMutex.Unlock()
-	if fake.NamespacePathStub != nil {
-		return fake.NamespacePathStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.namespacePathReturns
 	return fakeReturns.result1
 }
 
@@ -123,13 +218,82 @@ func (fake *FakeNamespacer) NamespacePathReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeNamespacer) UnnamespaceIDs(arg1 int, arg2 int) (int, int) {
+	fake.unnamespaceIDsMutex.Lock()
+	ret, specificReturn := fake.unnamespaceIDsReturnsOnCall[len(fake.unnamespaceIDsArgsForCall)]
+	fake.unnamespaceIDsArgsForCall = append(fake.unnamespaceIDsArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.UnnamespaceIDsStub
+	fakeReturns := fake.unnamespaceIDsReturns
+	fake.recordInvocation("UnnamespaceIDs", []interface{}{arg1, arg2})
+	fake.unnamespaceIDsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeNamespacer) UnnamespaceIDsCallCount() int {
+	fake.unnamespaceIDsMutex.RLock()
+	defer fake.unnamespaceIDsMutex.RUnlock()
+	return len(fake.unnamespaceIDsArgsForCall)
+}
+
+func (fake *FakeNamespacer) UnnamespaceIDsCalls(stub func(int, int) (int, int)) {
+	fake.unnamespaceIDsMutex.Lock()
+	defer fake.unnamespaceIDsMutex.Unlock()
+	fake.UnnamespaceIDsStub = stub
+}
+
+func (fake *FakeNamespacer) UnnamespaceIDsArgsForCall(i int) (int, int) {
+	fake.unnamespaceIDsMutex.RLock()
+	defer fake.unnamespaceIDsMutex.RUnlock()
+	argsForCall := fake.unnamespaceIDsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeNamespacer) UnnamespaceIDsReturns(result1 int, result2 int) {
+	fake.unnamespaceIDsMutex.Lock()
+	defer fake.unnamespaceIDsMutex.Unlock()
+	fake.UnnamespaceIDsStub = nil
+	fake.unnamespaceIDsReturns = struct {
+		result1 int
+		result2 int
+	}{result1, result2}
+}
+
+func (fake *FakeNamespacer) UnnamespaceIDsReturnsOnCall(i int, result1 int, result2 int) {
+	fake.unnamespaceIDsMutex.Lock()
+	defer fake.unnamespaceIDsMutex.Unlock()
+	fake.UnnamespaceIDsStub = nil
+	if fake.unnamespaceIDsReturnsOnCall == nil {
+		fake.unnamespaceIDsReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 int
+		})
+	}
+	fake.unnamespaceIDsReturnsOnCall[i] = struct {
+		result1 int
+		result2 int
+	}{result1, result2}
+}
+
 func (fake *FakeNamespacer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.namespaceCommandMutex.RLock()
 	defer fake.namespaceCommandMutex.RUnlock()
+	fake.namespaceIDsMutex.RLock()
+	defer fake.namespaceIDsMutex.RUnlock()
 	fake.namespacePathMutex.RLock()
 	defer fake.namespacePathMutex.RUnlock()
+	fake.unnamespaceIDsMutex.RLock()
+	defer fake.unnamespaceIDsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -15,6 +15,20 @@ type FakeTranslator struct {
 	translateCommandArgsForCall []struct {
 		arg1 *exec.Cmd
 	}
+	TranslateIDsStub        func(int, int) (int, int)
+	translateIDsMutex       sync.RWMutex
+	translateIDsArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	translateIDsReturns struct {
+		result1 int
+		result2 int
+	}
+	translateIDsReturnsOnCall map[int]struct {
+		result1 int
+		result2 int
+	}
 	TranslatePathStub        func(string, os.FileInfo, error) error
 	translatePathMutex       sync.RWMutex
 	translatePathArgsForCall []struct {
@@ -28,6 +42,20 @@ type FakeTranslator struct {
 	translatePathReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UntranslateIDsStub        func(int, int) (int, int)
+	untranslateIDsMutex       sync.RWMutex
+	untranslateIDsArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	untranslateIDsReturns struct {
+		result1 int
+		result2 int
+	}
+	untranslateIDsReturnsOnCall map[int]struct {
+		result1 int
+		result2 int
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -37,9 +65,10 @@ func (fake *FakeTranslator) TranslateCommand(arg1 *exec.Cmd) {
 	fake.translateCommandArgsForCall = append(fake.translateCommandArgsForCall, struct {
 		arg1 *exec.Cmd
 	}{arg1})
+	stub := fake.TranslateCommandStub
 	fake.recordInvocation("TranslateCommand", []interface{}{arg1})
 	fake.translateCommandMutex.Unlock()
-	if fake.TranslateCommandStub != nil {
+	if stub != nil {
 		fake.TranslateCommandStub(arg1)
 	}
 }
@@ -63,6 +92,71 @@ func (fake *FakeTranslator) TranslateCommandArgsForCall(i int) *exec.Cmd {
 	return argsForCall.arg1
 }
 
+func (fake *FakeTranslator) TranslateIDs(arg1 int, arg2 int) (int, int) {
+	fake.translateIDsMutex.Lock()
+	ret, specificReturn := fake.translateIDsReturnsOnCall[len(fake.translateIDsArgsForCall)]
+	fake.translateIDsArgsForCall = append(fake.translateIDsArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.TranslateIDsStub
+	fakeReturns := fake.translateIDsReturns
+	fake.recordInvocation("TranslateIDs", []interface{}{arg1, arg2})
+	fake.translateIDsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTranslator) TranslateIDsCallCount() int {
+	fake.translateIDsMutex.RLock()
+	defer fake.translateIDsMutex.RUnlock()
+	return len(fake.translateIDsArgsForCall)
+}
+
+func (fake *FakeTranslator) TranslateIDsCalls(stub func(int, int) (int, int)) {
+	fake.translateIDsMutex.Lock()
+	defer fake.translateIDsMutex.Unlock()
+	fake.TranslateIDsStub = stub
+}
+
+func (fake *FakeTranslator) TranslateIDsArgsForCall(i int) (int, int) {
+	fake.translateIDsMutex.RLock()
+	defer fake.translateIDsMutex.RUnlock()
+	argsForCall := fake.translateIDsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTranslator) TranslateIDsReturns(result1 int, result2 int) {
+	fake.translateIDsMutex.Lock()
+	defer fake.translateIDsMutex.Unlock()
+	fake.TranslateIDsStub = nil
+	fake.translateIDsReturns = struct {
+		result1 int
+		result2 int
+	}{result1, result2}
+}
+
+func (fake *FakeTranslator) TranslateIDsReturnsOnCall(i int, result1 int, result2 int) {
+	fake.translateIDsMutex.Lock()
+	defer fake.translateIDsMutex.Unlock()
+	fake.TranslateIDsStub = nil
+	if fake.translateIDsReturnsOnCall == nil {
+		fake.translateIDsReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 int
+		})
+	}
+	fake.translateIDsReturnsOnCall[i] = struct {
+		result1 int
+		result2 int
+	}{result1, result2}
+}
+
 func (fake *FakeTranslator) TranslatePath(arg1 string, arg2 os.FileInfo, arg3 error) error {
 	fake.translatePathMutex.Lock()
 	ret, specificReturn := fake.translatePathReturnsOnCall[len(fake.translatePathArgsForCall)]
@@ -71,15 +165,16 @@ func (fake *FakeTranslator) TranslatePath(arg1 string, arg2 os.FileInfo, arg3 er
 		arg2 os.FileInfo
 		arg3 error
 	}{arg1, arg2, arg3})
+	stub := fake.TranslatePathStub
+	fakeReturns := fake.translatePathReturns
 	fake.recordInvocation("TranslatePath", []interface{}{arg1, arg2, arg3})
 	fake.translatePathMutex.Unlock()
-	if fake.TranslatePathStub != nil {
-		return fake.TranslatePathStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.translatePathReturns
 	return fakeReturns.result1
 }
 
@@ -125,13 +220,82 @@ func (fake *FakeTranslator) TranslatePathReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeTranslator) UntranslateIDs(arg1 int, arg2 int) (int, int) {
+	fake.untranslateIDsMutex.Lock()
+	ret, specificReturn := fake.untranslateIDsReturnsOnCall[len(fake.untranslateIDsArgsForCall)]
+	fake.untranslateIDsArgsForCall = append(fake.untranslateIDsArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.UntranslateIDsStub
+	fakeReturns := fake.untranslateIDsReturns
+	fake.recordInvocation("UntranslateIDs", []interface{}{arg1, arg2})
+	fake.untranslateIDsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTranslator) UntranslateIDsCallCount() int {
+	fake.untranslateIDsMutex.RLock()
+	defer fake.untranslateIDsMutex.RUnlock()
+	return len(fake.untranslateIDsArgsForCall)
+}
+
+func (fake *FakeTranslator) UntranslateIDsCalls(stub func(int, int) (int, int)) {
+	fake.untranslateIDsMutex.Lock()
+	defer fake.untranslateIDsMutex.Unlock()
+	fake.UntranslateIDsStub = stub
+}
+
+func (fake *FakeTranslator) UntranslateIDsArgsForCall(i int) (int, int) {
+	fake.untranslateIDsMutex.RLock()
+	defer fake.untranslateIDsMutex.RUnlock()
+	argsForCall := fake.untranslateIDsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTranslator) UntranslateIDsReturns(result1 int, result2 int) {
+	fake.untranslateIDsMutex.Lock()
+	defer fake.untranslateIDsMutex.Unlock()
+	fake.UntranslateIDsStub = nil
+	fake.untranslateIDsReturns = struct {
+		result1 int
+		result2 int
+	}{result1, result2}
+}
+
+func (fake *FakeTranslator) UntranslateIDsReturnsOnCall(i int, result1 int, result2 int) {
+	fake.untranslateIDsMutex.Lock()
+	defer fake.untranslateIDsMutex.Unlock()
+	fake.UntranslateIDsStub = nil
+	if fake.untranslateIDsReturnsOnCall == nil {
+		fake.untranslateIDsReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 int
+		})
+	}
+	fake.untranslateIDsReturnsOnCall[i] = struct {
+		result1 int
+		result2 int
+	}{result1, result2}
+}
+
 func (fake *FakeTranslator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.translateCommandMutex.RLock()
 	defer fake.translateCommandMutex.RUnlock()
+	fake.translateIDsMutex.RLock()
+	defer fake.translateIDsMutex.RUnlock()
 	fake.translatePathMutex.RLock()
 	defer fake.translatePathMutex.RUnlock()
+	fake.untranslateIDsMutex.RLock()
+	defer fake.untranslateIDsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
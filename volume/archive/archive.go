@@ -0,0 +1,698 @@
+// Package archive reads and writes tar streams of volume contents without
+// relying on an external tar binary.
+//
+// Ownership, permissions, modification times, extended attributes,
+// hardlinks, device nodes and fifos are preserved. Runs of zeroes in regular
+// files are left as holes when extracting, so sparse files stay sparse. When
+// creating archives sparse files are written out in full, as archive/tar
+// cannot produce sparse entries.
+package archive
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/concourse/baggageclaim/volume/fsroot"
+)
+
+// IDMapper translates a uid/gid pair from one user namespace to another.
+type IDMapper func(uid, gid int) (int, int)
+
+type ExtractOptions struct {
+	// MapIDs translates the ownership recorded in the archive to the
+	// ownership given to the extracted files. Ownership is left as-is if nil.
+	MapIDs IDMapper
+
+	// SkipDevices prevents character and block devices from being created,
+	// for destinations that must not gain access to the host's devices.
+	SkipDevices bool
+
+	// SkipPrivilegedXattrs prevents extended attributes in the trusted and
+	// security namespaces from being set, for destinations written by
+	// unprivileged containers.
+	SkipPrivilegedXattrs bool
+
+	// Whiteouts applies the archive as a layer over what is already in the
+	// destination. An entry named with WhiteoutPrefix deletes the path named
+	// by the rest of its name, and a WhiteoutOpaqueDir entry deletes
+	// everything in its directory that the archive did not itself extract.
+	// Entries replace non-empty directories rather than failing.
+	Whiteouts bool
+
+	// Skip is how many entries at the start of the archive are passed over
+	// rather than extracted, as when resuming an extraction that got that
+	// far. The modification times of skipped directories are still set.
+	Skip int
+
+	// Checkpoint is called, if set, each time an entry is in place with how
+	// many entries of the archive have been extracted, including skipped
+	// ones.
+	Checkpoint func(entries int)
+}
+
+type CreateOptions struct {
+	// MapIDs translates the ownership of files on disk to the ownership
+	// recorded in the archive. Ownership is left as-is if nil.
+	MapIDs IDMapper
+}
+
+// MalformedArchiveError is returned when the archive is not a valid tar
+// stream, or when it contains an entry that cannot be extracted safely.
+// Failures to read the stream the archive comes from are returned as they
+// are, so that a stream that was cut short is not mistaken for a bad one.
+type MalformedArchiveError struct {
+	Err error
+}
+
+func (err *MalformedArchiveError) Error() string {
+	return "malformed archive: " + err.Err.Error()
+}
+
+func (err *MalformedArchiveError) Unwrap() error {
+	return err.Err
+}
+
+// ExtractError is returned when the archive is valid but an entry could not
+// be written to disk.
+type ExtractError struct {
+	Path string
+	Err  error
+}
+
+func (err *ExtractError) Error() string {
+	return "extract " + err.Path + ": " + err.Err.Error()
+}
+
+func (err *ExtractError) Unwrap() error {
+	return err.Err
+}
+
+const xattrPAXPrefix = "SCHILY.xattr."
+
+// WhiteoutPrefix marks an entry in a layer as the deletion of the path named
+// by the rest of its name, as in overlay filesystems and OCI image layers.
+const WhiteoutPrefix = ".wh."
+
+// WhiteoutOpaqueDir marks the directory it is in as replacing, rather than
+// being merged with, the directory beneath it in a layer.
+const WhiteoutOpaqueDir = WhiteoutPrefix + WhiteoutPrefix + ".opq"
+
+// Extract unpacks the tar stream read from r into dest within root, creating
+// dest if it is missing. Neither dest nor the entries may lead outside of
+// root, either directly or by way of a symlink, and the entries may not lead
+// outside of dest.
+func Extract(r io.Reader, root string, dest string, opts ExtractOptions) error {
+	err := extract(r, root, dest, opts)
+
+	var source *sourceError
+	if errors.As(err, &source) {
+		return source.Err
+	}
+
+	return err
+}
+
+func extract(r io.Reader, root string, dest string, opts ExtractOptions) error {
+	rootDir, err := fsroot.Open(root)
+	if err != nil {
+		return err
+	}
+
+	defer rootDir.Close()
+
+	destDir, err := rootDir.ResolveDir(dest, fsroot.ResolveOptions{Mkdir: true})
+	if err != nil {
+		return err
+	}
+
+	defer destDir.Close()
+
+	x := &extractor{
+		dest:  destDir,
+		opts:  opts,
+		chown: os.Geteuid() == 0,
+	}
+
+	defer x.forgetParent()
+
+	if opts.Whiteouts {
+		x.extracted = map[string]bool{}
+	}
+
+	tarReader := tar.NewReader(sourceReader{r})
+
+	for entries := 0; ; entries++ {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return malformed(err)
+		}
+
+		if entries < x.opts.Skip {
+			x.skip(hdr)
+			continue
+		}
+
+		if x.opts.Whiteouts {
+			isWhiteout, err := x.whiteout(hdr)
+			if err != nil {
+				return err
+			}
+
+			if isWhiteout {
+				x.checkpoint(entries + 1)
+				continue
+			}
+		}
+
+		err = x.extract(hdr, tarReader)
+		if err != nil {
+			return err
+		}
+
+		x.checkpoint(entries + 1)
+	}
+
+	// extracting entries into a directory bumps its modification time, so
+	// they are set once everything is in place, deepest first
+	for i := len(x.dirs) - 1; i >= 0; i-- {
+		dir := x.dirs[i]
+
+		parent, name, err := x.resolve(dir.name)
+		if err != nil {
+			return err
+		}
+
+		err = parent.Lchtimes(name, accessTime(dir.hdr), dir.hdr.ModTime)
+		if err != nil {
+			return &ExtractError{Path: parent.Join(name), Err: err}
+		}
+	}
+
+	return nil
+}
+
+type extractor struct {
+	dest  *fsroot.Dir
+	opts  ExtractOptions
+	chown bool
+
+	// the directory the last entry was extracted into, which the next entry
+	// is likely to be extracted into too
+	parent     *fsroot.Dir
+	parentPath string
+
+	// the paths extracted so far, when applying whiteouts
+	extracted map[string]bool
+
+	dirs []extractedDir
+}
+
+type extractedDir struct {
+	name string
+	hdr  *tar.Header
+}
+
+func (x *extractor) extract(hdr *tar.Header, r io.Reader) error {
+	parent, name, err := x.resolve(hdr.Name)
+	if err != nil {
+		return err
+	}
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		err = x.extractDir(parent, name)
+
+	case tar.TypeReg, tar.TypeGNUSparse:
+		err = x.extractFile(parent, name, hdr, r)
+
+	case tar.TypeSymlink:
+		err = x.replace(parent, name)
+		if err == nil {
+			err = parent.Symlink(hdr.Linkname, name)
+		}
+
+	case tar.TypeLink:
+		// hardlinks share the metadata of their target, so there is nothing
+		// else to apply
+		return x.extractLink(parent, name, hdr)
+
+	case tar.TypeChar, tar.TypeBlock:
+		if x.opts.SkipDevices {
+			return nil
+		}
+
+		fallthrough
+
+	case tar.TypeFifo:
+		err = x.replace(parent, name)
+		if err == nil {
+			err = mknod(parent, name, hdr)
+		}
+
+	case tar.TypeXGlobalHeader:
+		return nil
+
+	default:
+		return malformed(fmt.Errorf("%s: unsupported entry type %q", hdr.Name, hdr.Typeflag))
+	}
+
+	if err != nil {
+		return wrapExtractError(parent.Join(name), err)
+	}
+
+	if x.extracted != nil {
+		x.extracted[entryPath(hdr.Name)] = true
+	}
+
+	return x.applyMetadata(parent, name, hdr)
+}
+
+// skip passes over an entry that is already in place, remembering what
+// extracting it would have for the rest of the extraction.
+func (x *extractor) skip(hdr *tar.Header) {
+	if hdr.Typeflag == tar.TypeDir {
+		x.dirs = append(x.dirs, extractedDir{name: hdr.Name, hdr: hdr})
+	}
+
+	if x.extracted != nil && !strings.HasPrefix(path.Base(hdr.Name), WhiteoutPrefix) {
+		x.extracted[entryPath(hdr.Name)] = true
+	}
+}
+
+func (x *extractor) checkpoint(entries int) {
+	if x.opts.Checkpoint != nil {
+		x.opts.Checkpoint(entries)
+	}
+}
+
+// whiteout applies hdr if it is a whiteout, reporting whether it was one.
+func (x *extractor) whiteout(hdr *tar.Header) (bool, error) {
+	base := path.Base(hdr.Name)
+	if !strings.HasPrefix(base, WhiteoutPrefix) {
+		return false, nil
+	}
+
+	rel, err := x.entryPath(hdr.Name)
+	if err != nil {
+		return true, err
+	}
+
+	dirPath := path.Dir(rel)
+
+	// whatever is removed may be, or be above, the directory kept for the
+	// next entry
+	x.forgetParent()
+
+	dir, err := x.dest.ResolveDir(dirPath, fsroot.ResolveOptions{Mkdir: true})
+	if err != nil {
+		return true, x.resolveError(hdr.Name, err)
+	}
+
+	defer dir.Close()
+
+	if base == WhiteoutOpaqueDir {
+		names, err := dir.Readdirnames()
+		if err != nil {
+			return true, &ExtractError{Path: dir.Join("."), Err: err}
+		}
+
+		for _, name := range names {
+			if x.extracted[path.Join(dirPath, name)] {
+				continue
+			}
+
+			err := dir.RemoveAll(name)
+			if err != nil {
+				return true, &ExtractError{Path: dir.Join(name), Err: err}
+			}
+		}
+
+		return true, nil
+	}
+
+	name := strings.TrimPrefix(base, WhiteoutPrefix)
+	if name == "" || name == "." || name == ".." {
+		return true, malformed(fmt.Errorf("%s: invalid whiteout", hdr.Name))
+	}
+
+	err = dir.RemoveAll(name)
+	if err != nil {
+		return true, &ExtractError{Path: dir.Join(name), Err: err}
+	}
+
+	return true, nil
+}
+
+func (x *extractor) extractDir(parent *fsroot.Dir, name string) error {
+	if name == "." {
+		return nil
+	}
+
+	info, err := parent.Lstat(name)
+	if err == nil && info.IsDir() {
+		return nil
+	}
+
+	err = x.replace(parent, name)
+	if err != nil {
+		return err
+	}
+
+	return parent.Mkdir(name, 0700)
+}
+
+func (x *extractor) extractFile(parent *fsroot.Dir, name string, hdr *tar.Header, r io.Reader) error {
+	err := x.replace(parent, name)
+	if err != nil {
+		return err
+	}
+
+	file, err := parent.Create(name, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = copySparse(file, r, hdr.Size)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func (x *extractor) extractLink(parent *fsroot.Dir, name string, hdr *tar.Header) error {
+	targetParent, targetName, err := x.resolveOther(hdr.Linkname)
+	if err != nil {
+		return err
+	}
+
+	defer targetParent.Close()
+
+	err = x.replace(parent, name)
+	if err != nil {
+		return wrapExtractError(parent.Join(name), err)
+	}
+
+	err = parent.Link(targetParent, targetName, name)
+	if err != nil {
+		return wrapExtractError(parent.Join(name), err)
+	}
+
+	return nil
+}
+
+func (x *extractor) applyMetadata(parent *fsroot.Dir, name string, hdr *tar.Header) error {
+	if x.chown {
+		uid, gid := hdr.Uid, hdr.Gid
+		if x.opts.MapIDs != nil {
+			uid, gid = x.opts.MapIDs(uid, gid)
+		}
+
+		err := parent.Lchown(name, uid, gid)
+		if err != nil {
+			return &ExtractError{Path: parent.Join(name), Err: err}
+		}
+	}
+
+	for key, value := range hdr.PAXRecords {
+		if !strings.HasPrefix(key, xattrPAXPrefix) {
+			continue
+		}
+
+		attr := strings.TrimPrefix(key, xattrPAXPrefix)
+		if x.opts.SkipPrivilegedXattrs && isPrivilegedXattr(attr) {
+			continue
+		}
+
+		err := parent.Lsetxattr(name, attr, []byte(value))
+		if isNotSupported(err) {
+			// the destination filesystem cannot hold them; tar only warns
+			break
+		}
+
+		if err != nil {
+			return &ExtractError{Path: parent.Join(name), Err: err}
+		}
+	}
+
+	if hdr.Typeflag == tar.TypeSymlink {
+		err := parent.Lchtimes(name, accessTime(hdr), hdr.ModTime)
+		if err != nil {
+			return &ExtractError{Path: parent.Join(name), Err: err}
+		}
+
+		return nil
+	}
+
+	// chmod after chown, as chown clears the setuid and setgid bits
+	mode := hdr.FileInfo().Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+
+	err := parent.Chmod(name, mode)
+	if err != nil {
+		return &ExtractError{Path: parent.Join(name), Err: err}
+	}
+
+	if hdr.Typeflag == tar.TypeDir {
+		x.dirs = append(x.dirs, extractedDir{name: hdr.Name, hdr: hdr})
+		return nil
+	}
+
+	err = parent.Lchtimes(name, accessTime(hdr), hdr.ModTime)
+	if err != nil {
+		return &ExtractError{Path: parent.Join(name), Err: err}
+	}
+
+	return nil
+}
+
+// isPrivilegedXattr reports whether an extended attribute is one that only
+// the host should set: trusted attributes are interpreted by overlay
+// filesystems, and security attributes grant file capabilities.
+func isPrivilegedXattr(attr string) bool {
+	return strings.HasPrefix(attr, "trusted.") || strings.HasPrefix(attr, "security.")
+}
+
+// resolve returns the directory an entry named name should be extracted into
+// and its name there, creating any of its parent directories that are
+// missing. The directory is kept for the next entry, and must not be closed.
+func (x *extractor) resolve(name string) (*fsroot.Dir, string, error) {
+	rel, err := x.entryPath(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dirPath, base := path.Split(rel)
+	if x.parent != nil && x.parentPath == dirPath {
+		return x.parent, base, nil
+	}
+
+	x.forgetParent()
+
+	parent, err := x.dest.ResolveDir(dirPath, fsroot.ResolveOptions{Mkdir: true})
+	if err != nil {
+		return nil, "", x.resolveError(name, err)
+	}
+
+	x.parent = parent
+	x.parentPath = dirPath
+
+	return parent, base, nil
+}
+
+// resolveOther is like resolve, but for paths other than that of the entry
+// being extracted, e.g. the targets of hardlinks. The directory must be
+// closed by the caller.
+func (x *extractor) resolveOther(name string) (*fsroot.Dir, string, error) {
+	rel, err := x.entryPath(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	parent, base, err := x.dest.Resolve(rel, fsroot.ResolveOptions{})
+	if err != nil {
+		return nil, "", x.resolveError(name, err)
+	}
+
+	return parent, base, nil
+}
+
+// entryPath validates an entry's name, returning its slash-separated path
+// relative to the destination.
+func (x *extractor) entryPath(name string) (string, error) {
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return "", malformed(fmt.Errorf("%s: path refers to parent directory", name))
+		}
+	}
+
+	return entryPath(name), nil
+}
+
+func (x *extractor) resolveError(name string, err error) error {
+	if errors.Is(err, fsroot.ErrOutsideRoot) {
+		return malformed(fmt.Errorf("%s: symlink leads outside of destination", name))
+	}
+
+	if pathErr, ok := err.(*os.PathError); ok {
+		return &ExtractError{Path: pathErr.Path, Err: pathErr.Err}
+	}
+
+	return wrapExtractError(x.dest.Join(name), err)
+}
+
+func (x *extractor) forgetParent() {
+	if x.parent != nil {
+		x.parent.Close()
+		x.parent = nil
+	}
+}
+
+// replace removes whatever is at name so that an entry can be created there.
+// Non-empty directories are left alone, causing the entry to fail, unless
+// the archive is a layer which replaces them.
+func (x *extractor) replace(parent *fsroot.Dir, name string) error {
+	info, err := parent.Lstat(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err == nil && info.IsDir() {
+		// the removed directory may be, or be above, the one kept for the
+		// next entry
+		if parent != x.parent {
+			x.forgetParent()
+		}
+
+		if x.opts.Whiteouts {
+			return parent.RemoveAll(name)
+		}
+	}
+
+	return parent.Remove(name)
+}
+
+// entryPath cleans an entry's name into a slash-separated path relative to
+// the destination. Absolute paths are taken to be relative to the
+// destination, as tar does.
+func entryPath(name string) string {
+	rel := strings.TrimLeft(path.Clean("/"+name), "/")
+	if rel == "" {
+		return "."
+	}
+
+	return rel
+}
+
+// sparseBlockSize is the granularity at which runs of zeroes are detected
+// and skipped over when writing file contents.
+const sparseBlockSize = 32 * 1024
+
+// copySparse writes size bytes read from r to file, seeking over blocks of
+// zeroes rather than writing them.
+func copySparse(file *os.File, r io.Reader, size int64) error {
+	buf := make([]byte, sparseBlockSize)
+
+	var written int64
+	for written < size {
+		chunk := buf
+		if remaining := size - written; remaining < int64(len(chunk)) {
+			chunk = chunk[:remaining]
+		}
+
+		n, err := io.ReadFull(r, chunk)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return malformed(err)
+		}
+
+		if isZero(chunk[:n]) {
+			_, err = file.Seek(int64(n), io.SeekCurrent)
+		} else {
+			_, err = file.Write(chunk[:n])
+		}
+
+		if err != nil {
+			return err
+		}
+
+		written += int64(n)
+	}
+
+	// the file may end in a hole, which seeking alone does not allocate
+	return file.Truncate(size)
+}
+
+func isZero(buf []byte) bool {
+	for _, b := range buf {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func accessTime(hdr *tar.Header) time.Time {
+	if hdr.AccessTime.IsZero() {
+		return hdr.ModTime
+	}
+
+	return hdr.AccessTime
+}
+
+// sourceReader marks failures to read the archive so that they can be told
+// apart from the archive being malformed and from failures to write its
+// contents.
+type sourceReader struct {
+	io.Reader
+}
+
+func (r sourceReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil && err != io.EOF {
+		err = &sourceError{Err: err}
+	}
+
+	return n, err
+}
+
+// sourceError carries a failure to read the archive out of Extract as it is.
+type sourceError struct {
+	Err error
+}
+
+func (err *sourceError) Error() string {
+	return err.Err.Error()
+}
+
+func malformed(err error) error {
+	switch err.(type) {
+	case *MalformedArchiveError, *sourceError:
+		return err
+	}
+
+	return &MalformedArchiveError{Err: err}
+}
+
+func wrapExtractError(path string, err error) error {
+	switch err.(type) {
+	case *MalformedArchiveError, *ExtractError, *sourceError:
+		return err
+	default:
+		return &ExtractError{Path: path, Err: err}
+	}
+}
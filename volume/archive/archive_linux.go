@@ -0,0 +1,48 @@
+package archive
+
+import (
+	"archive/tar"
+	"errors"
+	"os"
+	"syscall"
+
+	"github.com/concourse/baggageclaim/volume/fsroot"
+	"golang.org/x/sys/unix"
+)
+
+type inode struct {
+	dev uint64
+	ino uint64
+}
+
+// inodeOf identifies files with more than one link, so that they can be
+// archived as hardlinks after their first occurrence.
+func inodeOf(info os.FileInfo) (inode, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || stat.Nlink < 2 {
+		return inode{}, false
+	}
+
+	return inode{dev: uint64(stat.Dev), ino: stat.Ino}, true
+}
+
+func mknod(parent *fsroot.Dir, name string, hdr *tar.Header) error {
+	mode := uint32(hdr.Mode & 07777)
+
+	switch hdr.Typeflag {
+	case tar.TypeChar:
+		mode |= unix.S_IFCHR
+	case tar.TypeBlock:
+		mode |= unix.S_IFBLK
+	case tar.TypeFifo:
+		mode |= unix.S_IFIFO
+	}
+
+	dev := unix.Mkdev(uint32(hdr.Devmajor), uint32(hdr.Devminor))
+
+	return parent.Mknod(name, mode, int(dev))
+}
+
+func isNotSupported(err error) bool {
+	return errors.Is(err, unix.ENOTSUP)
+}
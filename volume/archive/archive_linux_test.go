@@ -0,0 +1,212 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/concourse/baggageclaim/volume/archive"
+	"golang.org/x/sys/unix"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Archive on Linux", func() {
+	var (
+		srcDir  string
+		destDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		srcDir, err = ioutil.TempDir("", "archive-src")
+		Expect(err).ToNot(HaveOccurred())
+
+		destDir, err = ioutil.TempDir("", "archive-dest")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(srcDir)).To(Succeed())
+		Expect(os.RemoveAll(destDir)).To(Succeed())
+	})
+
+	roundTrip := func(createOpts archive.CreateOptions, extractOpts archive.ExtractOptions) {
+		buf := new(bytes.Buffer)
+		ExpectWithOffset(1, archive.Create(buf, srcDir, ".", createOpts)).To(Succeed())
+		ExpectWithOffset(1, archive.Extract(buf, destDir, ".", extractOpts)).To(Succeed())
+	}
+
+	stat := func(path string) *syscall.Stat_t {
+		info, err := os.Lstat(path)
+		ExpectWithOffset(1, err).ToNot(HaveOccurred())
+		return info.Sys().(*syscall.Stat_t)
+	}
+
+	Context("when running as root", func() {
+		BeforeEach(func() {
+			if os.Geteuid() != 0 {
+				Skip("must be run as root to change ownership")
+			}
+		})
+
+		It("preserves ownership, including that of symlinks", func() {
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+			Expect(os.Symlink("some-file", filepath.Join(srcDir, "some-link"))).To(Succeed())
+			Expect(os.Lchown(filepath.Join(srcDir, "some-file"), 1000, 1001)).To(Succeed())
+			Expect(os.Lchown(filepath.Join(srcDir, "some-link"), 1002, 1003)).To(Succeed())
+
+			roundTrip(archive.CreateOptions{}, archive.ExtractOptions{})
+
+			Expect(stat(filepath.Join(destDir, "some-file")).Uid).To(BeEquivalentTo(1000))
+			Expect(stat(filepath.Join(destDir, "some-file")).Gid).To(BeEquivalentTo(1001))
+			Expect(stat(filepath.Join(destDir, "some-link")).Uid).To(BeEquivalentTo(1002))
+			Expect(stat(filepath.Join(destDir, "some-link")).Gid).To(BeEquivalentTo(1003))
+		})
+
+		It("maps ownership with MapIDs", func() {
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+
+			roundTrip(archive.CreateOptions{}, archive.ExtractOptions{
+				MapIDs: func(uid, gid int) (int, int) {
+					return uid + 100000, gid + 200000
+				},
+			})
+
+			Expect(stat(filepath.Join(destDir, "some-file")).Uid).To(BeEquivalentTo(100000))
+			Expect(stat(filepath.Join(destDir, "some-file")).Gid).To(BeEquivalentTo(200000))
+		})
+
+		It("keeps setuid bits despite changing ownership", func() {
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-file"), []byte("some-content"), 0755)).To(Succeed())
+			Expect(os.Lchown(filepath.Join(srcDir, "some-file"), 1000, 1000)).To(Succeed())
+			Expect(os.Chmod(filepath.Join(srcDir, "some-file"), 0755|os.ModeSetuid)).To(Succeed())
+
+			roundTrip(archive.CreateOptions{}, archive.ExtractOptions{})
+
+			info, err := os.Stat(filepath.Join(destDir, "some-file"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode() & os.ModeSetuid).ToNot(BeZero())
+		})
+
+		It("creates device nodes", func() {
+			Expect(unix.Mknod(filepath.Join(srcDir, "null"), unix.S_IFCHR|0666, int(unix.Mkdev(1, 3)))).To(Succeed())
+
+			roundTrip(archive.CreateOptions{}, archive.ExtractOptions{})
+
+			rdev := stat(filepath.Join(destDir, "null")).Rdev
+			Expect(unix.Major(rdev)).To(BeEquivalentTo(1))
+			Expect(unix.Minor(rdev)).To(BeEquivalentTo(3))
+		})
+
+		It("skips trusted and security extended attributes with SkipPrivilegedXattrs", func() {
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+
+			for _, attr := range []string{"user.some-attr", "trusted.some-attr", "security.some-attr"} {
+				err := unix.Lsetxattr(filepath.Join(srcDir, "some-file"), attr, []byte("some-value"), 0)
+				if err == unix.ENOTSUP {
+					Skip("filesystem does not support extended attributes")
+				}
+
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			roundTrip(archive.CreateOptions{}, archive.ExtractOptions{SkipPrivilegedXattrs: true})
+
+			value := make([]byte, 64)
+			_, err := unix.Lgetxattr(filepath.Join(destDir, "some-file"), "user.some-attr", value)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = unix.Lgetxattr(filepath.Join(destDir, "some-file"), "trusted.some-attr", value)
+			Expect(err).To(Equal(unix.ENODATA))
+
+			_, err = unix.Lgetxattr(filepath.Join(destDir, "some-file"), "security.some-attr", value)
+			Expect(err).To(Equal(unix.ENODATA))
+		})
+
+		It("skips device nodes with SkipDevices, but not fifos", func() {
+			Expect(unix.Mknod(filepath.Join(srcDir, "null"), unix.S_IFCHR|0666, int(unix.Mkdev(1, 3)))).To(Succeed())
+			Expect(unix.Mkfifo(filepath.Join(srcDir, "some-fifo"), 0644)).To(Succeed())
+
+			roundTrip(archive.CreateOptions{}, archive.ExtractOptions{SkipDevices: true})
+
+			Expect(filepath.Join(destDir, "null")).ToNot(BeAnExistingFile())
+
+			info, err := os.Lstat(filepath.Join(destDir, "some-fifo"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode() & os.ModeNamedPipe).ToNot(BeZero())
+		})
+	})
+
+	It("preserves hardlinks", func() {
+		Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+		Expect(os.Link(filepath.Join(srcDir, "some-file"), filepath.Join(srcDir, "other-file"))).To(Succeed())
+
+		buf := new(bytes.Buffer)
+		Expect(archive.Create(buf, srcDir, ".", archive.CreateOptions{})).To(Succeed())
+
+		tarReader := tar.NewReader(bytes.NewReader(buf.Bytes()))
+
+		var links int
+		for {
+			hdr, err := tarReader.Next()
+			if err != nil {
+				break
+			}
+
+			if hdr.Typeflag == tar.TypeLink {
+				links++
+			}
+		}
+
+		Expect(links).To(Equal(1))
+
+		Expect(archive.Extract(buf, destDir, ".", archive.ExtractOptions{})).To(Succeed())
+
+		Expect(stat(filepath.Join(destDir, "some-file")).Ino).To(Equal(stat(filepath.Join(destDir, "other-file")).Ino))
+	})
+
+	It("preserves extended attributes", func() {
+		Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+
+		err := unix.Lsetxattr(filepath.Join(srcDir, "some-file"), "user.some-attr", []byte("some-value"), 0)
+		if err == unix.ENOTSUP {
+			Skip("filesystem does not support extended attributes")
+		}
+
+		Expect(err).ToNot(HaveOccurred())
+
+		roundTrip(archive.CreateOptions{}, archive.ExtractOptions{})
+
+		value := make([]byte, 64)
+		n, err := unix.Lgetxattr(filepath.Join(destDir, "some-file"), "user.some-attr", value)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(value[:n])).To(Equal("some-value"))
+	})
+
+	It("leaves runs of zeroes in files as holes", func() {
+		const size = 16 * 1024 * 1024
+
+		sparse, err := os.Create(filepath.Join(srcDir, "sparse-file"))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = sparse.WriteAt([]byte("some-content"), size/2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sparse.Truncate(size)).To(Succeed())
+		Expect(sparse.Close()).To(Succeed())
+
+		roundTrip(archive.CreateOptions{}, archive.ExtractOptions{})
+
+		info, err := os.Stat(filepath.Join(destDir, "sparse-file"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Size()).To(BeEquivalentTo(size))
+		Expect(stat(filepath.Join(destDir, "sparse-file")).Blocks * 512).To(BeNumerically("<", size/4))
+
+		content, err := ioutil.ReadFile(filepath.Join(destDir, "sparse-file"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(bytes.Trim(content, "\x00"))).To(Equal("some-content"))
+	})
+})
@@ -0,0 +1,26 @@
+// +build !linux
+
+package archive
+
+import (
+	"archive/tar"
+	"os"
+
+	"github.com/concourse/baggageclaim/volume/fsroot"
+)
+
+type inode struct{}
+
+// hardlinks are not detected, so every link is archived as a separate file
+func inodeOf(info os.FileInfo) (inode, bool) {
+	return inode{}, false
+}
+
+func mknod(parent *fsroot.Dir, name string, hdr *tar.Header) error {
+	return parent.Mknod(name, uint32(hdr.Mode), 0)
+}
+
+// extended attributes are not supported, so are never set
+func isNotSupported(err error) bool {
+	return false
+}
@@ -0,0 +1,474 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/concourse/baggageclaim/volume/archive"
+	"github.com/concourse/baggageclaim/volume/fsroot"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Archive", func() {
+	var (
+		srcDir  string
+		destDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		srcDir, err = ioutil.TempDir("", "archive-src")
+		Expect(err).ToNot(HaveOccurred())
+
+		destDir, err = ioutil.TempDir("", "archive-dest")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(srcDir)).To(Succeed())
+		Expect(os.RemoveAll(destDir)).To(Succeed())
+	})
+
+	writeArchive := func(entries ...*tar.Header) *bytes.Buffer {
+		buf := new(bytes.Buffer)
+		tarWriter := tar.NewWriter(buf)
+
+		for _, hdr := range entries {
+			content := hdr.Linkname
+			if hdr.Typeflag == tar.TypeReg {
+				hdr.Linkname = ""
+				hdr.Size = int64(len(content))
+			}
+
+			Expect(tarWriter.WriteHeader(hdr)).To(Succeed())
+
+			if hdr.Typeflag == tar.TypeReg {
+				_, err := tarWriter.Write([]byte(content))
+				Expect(err).ToNot(HaveOccurred())
+			}
+		}
+
+		Expect(tarWriter.Close()).To(Succeed())
+
+		return buf
+	}
+
+	// regular file contents are passed in Linkname for brevity
+	file := func(name string, content string) *tar.Header {
+		return &tar.Header{Typeflag: tar.TypeReg, Name: name, Linkname: content, Mode: 0644}
+	}
+
+	Describe("round-tripping a directory", func() {
+		var mtime time.Time
+
+		BeforeEach(func() {
+			mtime = time.Now().Add(-time.Hour).Truncate(time.Second)
+
+			Expect(os.MkdirAll(filepath.Join(srcDir, "some-dir", "nested-dir"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-file"), []byte("some-content"), 0640)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-dir", "nested-file"), []byte("nested-content"), 0755)).To(Succeed())
+			Expect(os.Symlink("../some-file", filepath.Join(srcDir, "some-dir", "some-link"))).To(Succeed())
+			Expect(os.Chmod(filepath.Join(srcDir, "some-dir"), 0700)).To(Succeed())
+
+			Expect(os.Chtimes(filepath.Join(srcDir, "some-file"), mtime, mtime)).To(Succeed())
+			Expect(os.Chtimes(filepath.Join(srcDir, "some-dir"), mtime, mtime)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			buf := new(bytes.Buffer)
+			Expect(archive.Create(buf, srcDir, ".", archive.CreateOptions{})).To(Succeed())
+			Expect(archive.Extract(buf, destDir, ".", archive.ExtractOptions{})).To(Succeed())
+		})
+
+		It("preserves contents", func() {
+			content, err := ioutil.ReadFile(filepath.Join(destDir, "some-file"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+
+			content, err = ioutil.ReadFile(filepath.Join(destDir, "some-dir", "nested-file"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("nested-content"))
+
+			Expect(filepath.Join(destDir, "some-dir", "nested-dir")).To(BeADirectory())
+		})
+
+		It("preserves symlinks", func() {
+			target, err := os.Readlink(filepath.Join(destDir, "some-dir", "some-link"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target).To(Equal("../some-file"))
+		})
+
+		It("preserves permissions", func() {
+			info, err := os.Stat(filepath.Join(destDir, "some-file"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0640)))
+
+			info, err = os.Stat(filepath.Join(destDir, "some-dir"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0700)))
+		})
+
+		It("preserves modification times, including those of directories", func() {
+			info, err := os.Stat(filepath.Join(destDir, "some-file"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.ModTime()).To(BeTemporally("==", mtime))
+
+			info, err = os.Stat(filepath.Join(destDir, "some-dir"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.ModTime()).To(BeTemporally("==", mtime))
+		})
+	})
+
+	Describe("Create", func() {
+		It("archives a single file by its base name", func() {
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+
+			buf := new(bytes.Buffer)
+			Expect(archive.Create(buf, srcDir, "some-file", archive.CreateOptions{})).To(Succeed())
+
+			tarReader := tar.NewReader(buf)
+
+			hdr, err := tarReader.Next()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hdr.Name).To(Equal("some-file"))
+
+			_, err = tarReader.Next()
+			Expect(err).To(Equal(io.EOF))
+		})
+
+		It("maps ownership with MapIDs", func() {
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+
+			buf := new(bytes.Buffer)
+			Expect(archive.Create(buf, srcDir, "some-file", archive.CreateOptions{
+				MapIDs: func(uid, gid int) (int, int) {
+					return 1234, 5678
+				},
+			})).To(Succeed())
+
+			hdr, err := tar.NewReader(buf).Next()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hdr.Uid).To(Equal(1234))
+			Expect(hdr.Gid).To(Equal(5678))
+			Expect(hdr.Uname).To(BeEmpty())
+			Expect(hdr.Gname).To(BeEmpty())
+		})
+
+		It("returns an error when the path does not exist", func() {
+			err := archive.Create(ioutil.Discard, srcDir, "bogus", archive.CreateOptions{})
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("resolves symlinks in the path as though the root were the root of the filesystem", func() {
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(destDir, "shadow"), []byte("host-content"), 0644)).To(Succeed())
+			Expect(os.Symlink("/", filepath.Join(srcDir, "root-link"))).To(Succeed())
+			Expect(os.Symlink(destDir, filepath.Join(srcDir, "host-link"))).To(Succeed())
+
+			buf := new(bytes.Buffer)
+			Expect(archive.Create(buf, srcDir, "root-link/some-file", archive.CreateOptions{})).To(Succeed())
+
+			tarReader := tar.NewReader(buf)
+
+			hdr, err := tarReader.Next()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hdr.Name).To(Equal("some-file"))
+
+			content, err := ioutil.ReadAll(tarReader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+
+			err = archive.Create(ioutil.Discard, srcDir, "host-link/shadow", archive.CreateOptions{})
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	})
+
+	Describe("CreateLayer", func() {
+		It("archives the entries without their contents and whiteouts for deletions", func() {
+			Expect(os.MkdirAll(filepath.Join(srcDir, "some-dir", "nested-dir"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-dir", "some-file"), []byte("some-content"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(srcDir, "some-dir", "nested-dir", "nested-file"), []byte("nested-content"), 0644)).To(Succeed())
+
+			buf := new(bytes.Buffer)
+			Expect(archive.CreateLayer(buf, srcDir, ".", []archive.LayerEntry{
+				{Path: "deleted-file", Deleted: true},
+				{Path: "some-dir"},
+				{Path: "some-dir/deleted-dir", Deleted: true},
+				{Path: "some-dir/some-file"},
+			}, archive.CreateOptions{})).To(Succeed())
+
+			tarReader := tar.NewReader(buf)
+
+			names := []string{}
+			for {
+				hdr, err := tarReader.Next()
+				if err == io.EOF {
+					break
+				}
+
+				Expect(err).ToNot(HaveOccurred())
+				names = append(names, hdr.Name)
+
+				if strings.Contains(hdr.Name, archive.WhiteoutPrefix) {
+					Expect(hdr.Typeflag).To(Equal(byte(tar.TypeReg)))
+					Expect(hdr.Size).To(BeZero())
+				}
+			}
+
+			Expect(names).To(Equal([]string{
+				".wh.deleted-file",
+				"some-dir/",
+				"some-dir/.wh.deleted-dir",
+				"some-dir/some-file",
+			}))
+		})
+
+		It("returns an error when an entry does not exist", func() {
+			err := archive.CreateLayer(ioutil.Discard, srcDir, ".", []archive.LayerEntry{
+				{Path: "bogus"},
+			}, archive.CreateOptions{})
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	})
+
+	Describe("Extract", func() {
+		var extractErr error
+
+		extract := func(r io.Reader) {
+			extractErr = archive.Extract(r, destDir, ".", archive.ExtractOptions{})
+		}
+
+		expectMalformed := func() {
+			var malformed *archive.MalformedArchiveError
+			ExpectWithOffset(1, errors.As(extractErr, &malformed)).To(BeTrue(), "expected a malformed archive error, got %v", extractErr)
+		}
+
+		It("extracts into the given path within the root, creating it", func() {
+			extractErr = archive.Extract(writeArchive(file("some-file", "some-content")), destDir, "some/path", archive.ExtractOptions{})
+			Expect(extractErr).ToNot(HaveOccurred())
+
+			content, err := ioutil.ReadFile(filepath.Join(destDir, "some", "path", "some-file"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+		})
+
+		It("refuses a path that leads outside of the root", func() {
+			Expect(os.Symlink(srcDir, filepath.Join(destDir, "host-link"))).To(Succeed())
+
+			extractErr = archive.Extract(writeArchive(file("some-file", "some-content")), destDir, "host-link", archive.ExtractOptions{})
+			Expect(errors.Is(extractErr, fsroot.ErrOutsideRoot)).To(BeTrue())
+
+			Expect(filepath.Join(srcDir, "some-file")).ToNot(BeAnExistingFile())
+		})
+
+		It("creates missing parent directories", func() {
+			extract(writeArchive(file("a/b/c", "some-content")))
+			Expect(extractErr).ToNot(HaveOccurred())
+
+			content, err := ioutil.ReadFile(filepath.Join(destDir, "a", "b", "c"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+		})
+
+		It("replaces existing files", func() {
+			Expect(ioutil.WriteFile(filepath.Join(destDir, "some-file"), []byte("old-content"), 0644)).To(Succeed())
+
+			extract(writeArchive(file("some-file", "new-content")))
+			Expect(extractErr).ToNot(HaveOccurred())
+
+			content, err := ioutil.ReadFile(filepath.Join(destDir, "some-file"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("new-content"))
+		})
+
+		It("passes over the entries it is told to skip", func() {
+			modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+			Expect(os.Mkdir(filepath.Join(destDir, "some-dir"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(destDir, "some-dir", "first-file"), []byte("already-there"), 0644)).To(Succeed())
+
+			checkpoints := []int{}
+			extractErr = archive.Extract(writeArchive(
+				&tar.Header{Typeflag: tar.TypeDir, Name: "some-dir/", Mode: 0755, ModTime: modTime},
+				file("some-dir/first-file", "first-content"),
+				file("some-dir/second-file", "second-content"),
+			), destDir, ".", archive.ExtractOptions{
+				Skip: 2,
+				Checkpoint: func(entries int) {
+					checkpoints = append(checkpoints, entries)
+				},
+			})
+			Expect(extractErr).ToNot(HaveOccurred())
+
+			Expect(ioutil.ReadFile(filepath.Join(destDir, "some-dir", "first-file"))).To(Equal([]byte("already-there")))
+			Expect(ioutil.ReadFile(filepath.Join(destDir, "some-dir", "second-file"))).To(Equal([]byte("second-content")))
+			Expect(checkpoints).To(Equal([]int{3}))
+
+			// the skipped directory still gets its modification time
+			info, err := os.Stat(filepath.Join(destDir, "some-dir"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.ModTime()).To(BeTemporally("==", modTime))
+		})
+
+		It("extracts absolute paths relative to the destination", func() {
+			extract(writeArchive(file("/some-file", "some-content")))
+			Expect(extractErr).ToNot(HaveOccurred())
+			Expect(filepath.Join(destDir, "some-file")).To(BeARegularFile())
+		})
+
+		It("returns a malformed archive error for garbage", func() {
+			extract(strings.NewReader(strings.Repeat("not a tar file", 100)))
+			expectMalformed()
+		})
+
+		It("returns a malformed archive error for a truncated archive", func() {
+			buf := writeArchive(file("some-file", strings.Repeat("x", 4096)))
+
+			extract(bytes.NewReader(buf.Bytes()[:1024]))
+			expectMalformed()
+		})
+
+		It("returns the error as it is when reading the stream fails between entries", func() {
+			disconnected := errors.New("connection reset")
+
+			extract(io.MultiReader(
+				bytes.NewReader(writeArchive(file("some-file", "")).Bytes()[:512]),
+				failingReader{disconnected},
+			))
+			Expect(extractErr).To(Equal(disconnected))
+		})
+
+		It("returns the error as it is when reading the stream fails within an entry", func() {
+			disconnected := errors.New("connection reset")
+
+			extract(io.MultiReader(
+				bytes.NewReader(writeArchive(file("some-file", strings.Repeat("x", 4096))).Bytes()[:1024]),
+				failingReader{disconnected},
+			))
+			Expect(extractErr).To(Equal(disconnected))
+		})
+
+		It("rejects entries that refer to a parent directory", func() {
+			extract(writeArchive(file("../escaped", "some-content")))
+			expectMalformed()
+
+			Expect(filepath.Join(filepath.Dir(destDir), "escaped")).ToNot(BeAnExistingFile())
+		})
+
+		It("rejects hardlinks that refer to a parent directory", func() {
+			extract(writeArchive(&tar.Header{
+				Typeflag: tar.TypeLink,
+				Name:     "some-link",
+				Linkname: "../../etc/passwd",
+			}))
+			expectMalformed()
+		})
+
+		It("rejects entries beneath symlinks which lead out of the destination", func() {
+			extract(writeArchive(
+				&tar.Header{Typeflag: tar.TypeSymlink, Name: "some-link", Linkname: srcDir},
+				file("some-link/escaped", "some-content"),
+			))
+			expectMalformed()
+
+			Expect(filepath.Join(srcDir, "escaped")).ToNot(BeAnExistingFile())
+		})
+
+		It("allows entries beneath symlinks within the destination", func() {
+			extract(writeArchive(
+				&tar.Header{Typeflag: tar.TypeDir, Name: "some-dir/", Mode: 0755},
+				&tar.Header{Typeflag: tar.TypeSymlink, Name: "some-link", Linkname: "some-dir"},
+				file("some-link/some-file", "some-content"),
+			))
+			Expect(extractErr).ToNot(HaveOccurred())
+			Expect(filepath.Join(destDir, "some-dir", "some-file")).To(BeARegularFile())
+		})
+
+		It("returns an extract error when an entry cannot be written", func() {
+			Expect(os.MkdirAll(filepath.Join(destDir, "some-dir", "nested"), 0755)).To(Succeed())
+
+			extract(writeArchive(file("some-dir", "some-content")))
+
+			var extractError *archive.ExtractError
+			Expect(errors.As(extractErr, &extractError)).To(BeTrue())
+			Expect(extractError.Path).To(Equal(filepath.Join(destDir, "some-dir")))
+
+			var malformed *archive.MalformedArchiveError
+			Expect(errors.As(extractErr, &malformed)).To(BeFalse())
+		})
+
+		Context("when applying whiteouts", func() {
+			BeforeEach(func() {
+				Expect(os.MkdirAll(filepath.Join(destDir, "doomed-dir", "nested-dir"), 0755)).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(destDir, "opaque-dir"), 0755)).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(destDir, "replaced-dir", "nested-dir"), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(destDir, "doomed-file"), []byte("doomed"), 0644)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(destDir, "opaque-dir", "hidden-file"), []byte("hidden"), 0644)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(destDir, "kept-file"), []byte("kept"), 0644)).To(Succeed())
+			})
+
+			applyLayer := func(r io.Reader) {
+				extractErr = archive.Extract(r, destDir, ".", archive.ExtractOptions{Whiteouts: true})
+			}
+
+			It("deletes whited-out paths", func() {
+				applyLayer(writeArchive(
+					file(".wh.doomed-dir", ""),
+					file(".wh.doomed-file", ""),
+				))
+				Expect(extractErr).ToNot(HaveOccurred())
+
+				Expect(filepath.Join(destDir, "doomed-dir")).ToNot(BeAnExistingFile())
+				Expect(filepath.Join(destDir, "doomed-file")).ToNot(BeAnExistingFile())
+				Expect(filepath.Join(destDir, ".wh.doomed-file")).ToNot(BeAnExistingFile())
+				Expect(filepath.Join(destDir, "kept-file")).To(BeARegularFile())
+			})
+
+			It("empties opaque directories of everything but what the archive extracted", func() {
+				applyLayer(writeArchive(
+					&tar.Header{Typeflag: tar.TypeDir, Name: "opaque-dir/", Mode: 0755},
+					file("opaque-dir/new-file", "new"),
+					file("opaque-dir/"+archive.WhiteoutOpaqueDir, ""),
+				))
+				Expect(extractErr).ToNot(HaveOccurred())
+
+				Expect(filepath.Join(destDir, "opaque-dir", "hidden-file")).ToNot(BeAnExistingFile())
+				Expect(filepath.Join(destDir, "opaque-dir", "new-file")).To(BeARegularFile())
+				Expect(filepath.Join(destDir, "opaque-dir", archive.WhiteoutOpaqueDir)).ToNot(BeAnExistingFile())
+			})
+
+			It("replaces non-empty directories", func() {
+				applyLayer(writeArchive(file("replaced-dir", "some-content")))
+				Expect(extractErr).ToNot(HaveOccurred())
+
+				content, err := ioutil.ReadFile(filepath.Join(destDir, "replaced-dir"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("some-content"))
+			})
+
+			It("rejects whiteouts of parent directories", func() {
+				applyLayer(writeArchive(file("opaque-dir/.wh...", "")))
+				expectMalformed()
+
+				Expect(filepath.Join(destDir, "kept-file")).To(BeARegularFile())
+			})
+		})
+	})
+})
+
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,286 @@
+package archive
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/concourse/baggageclaim/volume/fsroot"
+)
+
+// errChanged is returned when a file is replaced while it is being archived.
+var errChanged = errors.New("file changed as it was archived")
+
+// LayerEntry is a path to include in a layer written by CreateLayer.
+type LayerEntry struct {
+	// Path is slash-separated and relative to the directory the layer is
+	// created from.
+	Path string
+
+	// Deleted records the path as removed rather than including it.
+	Deleted bool
+}
+
+// Create writes a tar stream of src within root to w. Symlinks in src are
+// resolved as though root were the root of the filesystem, so that they can
+// never lead outside of it. If path is a directory its contents are included
+// recursively, with names relative to it; otherwise the file is included
+// under its own name.
+func Create(w io.Writer, root string, src string, opts CreateOptions) error {
+	rootDir, err := fsroot.Open(root)
+	if err != nil {
+		return err
+	}
+
+	defer rootDir.Close()
+
+	parent, name, err := rootDir.Resolve(src, fsroot.ResolveOptions{Chroot: true, Follow: true})
+	if err != nil {
+		return err
+	}
+
+	defer parent.Close()
+
+	c := &creator{
+		tarWriter: tar.NewWriter(w),
+		opts:      opts,
+		links:     map[inode]string{},
+		recurse:   true,
+	}
+
+	info, err := parent.Lstat(name)
+	if err != nil {
+		return err
+	}
+
+	entryName := name
+	if info.IsDir() {
+		entryName = "."
+	}
+
+	err = c.add(parent, name, entryName)
+	if err != nil {
+		return err
+	}
+
+	return c.tarWriter.Close()
+}
+
+// CreateLayer writes a tar stream of the given entries within src, which is
+// resolved within root as Create does, to w, in order. Directories are
+// included without their contents, and deleted paths are recorded as empty
+// whiteout files in the directory they were removed from.
+func CreateLayer(w io.Writer, root string, src string, entries []LayerEntry, opts CreateOptions) error {
+	rootDir, err := fsroot.Open(root)
+	if err != nil {
+		return err
+	}
+
+	defer rootDir.Close()
+
+	dir, err := rootDir.ResolveDir(src, fsroot.ResolveOptions{Chroot: true})
+	if err != nil {
+		return err
+	}
+
+	defer dir.Close()
+
+	c := &creator{
+		tarWriter: tar.NewWriter(w),
+		opts:      opts,
+		links:     map[inode]string{},
+	}
+
+	for _, entry := range entries {
+		name := path.Clean(entry.Path)
+
+		if entry.Deleted {
+			err := c.tarWriter.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     path.Join(path.Dir(name), WhiteoutPrefix+path.Base(name)),
+				Mode:     0644,
+			})
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		err := c.addEntry(dir, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.tarWriter.Close()
+}
+
+type creator struct {
+	tarWriter *tar.Writer
+	opts      CreateOptions
+
+	// whether the contents of directories are included
+	recurse bool
+
+	// the names that files with more than one link were first archived as
+	links map[inode]string
+}
+
+// addEntry adds the file at name within dir, which must not lead outside of
+// it.
+func (c *creator) addEntry(dir *fsroot.Dir, name string) error {
+	parent, base, err := dir.Resolve(name, fsroot.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer parent.Close()
+
+	return c.add(parent, base, name)
+}
+
+// add archives the file called name within parent as entryName. Regular
+// files and directories are opened before they are described, so that the
+// header always matches what is archived.
+func (c *creator) add(parent *fsroot.Dir, name string, entryName string) error {
+	info, err := parent.Lstat(name)
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSocket != 0 {
+		// sockets cannot be archived; tar skips them too
+		return nil
+	}
+
+	var file *os.File
+	var dir *fsroot.Dir
+	var target string
+
+	switch {
+	case info.Mode().IsRegular():
+		file, err = parent.Open(name)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		info, err = file.Stat()
+
+	case info.IsDir():
+		dir, err = parent.OpenDir(name)
+		if err != nil {
+			return err
+		}
+
+		defer dir.Close()
+
+		info, err = dir.Stat()
+
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err = parent.Readlink(name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if (file != nil && !info.Mode().IsRegular()) || (dir != nil && !info.IsDir()) {
+		return &os.PathError{Op: "archive", Path: parent.Join(name), Err: errChanged}
+	}
+
+	hdr, err := tar.FileInfoHeader(info, target)
+	if err != nil {
+		return err
+	}
+
+	hdr.Name = entryName
+	if info.IsDir() {
+		hdr.Name += "/"
+	}
+
+	// ownership is recorded numerically, as names may not mean the same
+	// thing wherever the archive is extracted
+	hdr.Uname = ""
+	hdr.Gname = ""
+
+	if c.opts.MapIDs != nil {
+		hdr.Uid, hdr.Gid = c.opts.MapIDs(hdr.Uid, hdr.Gid)
+	}
+
+	hdr.AccessTime = time.Time{}
+	hdr.ChangeTime = time.Time{}
+
+	if info.Mode().IsRegular() {
+		if key, ok := inodeOf(info); ok {
+			if first, found := c.links[key]; found {
+				hdr.Typeflag = tar.TypeLink
+				hdr.Linkname = first
+				hdr.Size = 0
+			} else {
+				c.links[key] = entryName
+			}
+		}
+	}
+
+	xattrs, err := parent.Lxattrs(name)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range xattrs {
+		if hdr.PAXRecords == nil {
+			hdr.PAXRecords = map[string]string{}
+		}
+
+		hdr.PAXRecords[xattrPAXPrefix+name] = value
+	}
+
+	err = c.tarWriter.WriteHeader(hdr)
+	if err != nil {
+		return err
+	}
+
+	if dir != nil && c.recurse {
+		return c.addContents(dir, entryName)
+	}
+
+	if hdr.Typeflag != tar.TypeReg {
+		return nil
+	}
+
+	// the file may have grown since it was stat'd; only what was promised in
+	// the header fits in the archive
+	_, err = io.CopyN(c.tarWriter, file, hdr.Size)
+	return err
+}
+
+// addContents archives everything within dir, in lexical order as tar does.
+func (c *creator) addContents(dir *fsroot.Dir, dirName string) error {
+	names, err := dir.Readdirnames()
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		entryName := name
+		if dirName != "." {
+			entryName = path.Join(dirName, name)
+		}
+
+		err := c.add(dir, name, entryName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package volume
+
+import (
+	"errors"
+	"sort"
+
+	"code.cloudfoundry.org/lager"
+)
+
+var ErrNoDigestProvided = errors.New("no digest provided")
+var ErrDigestNotFound = errors.New("no volume found with digest")
+
+// DedupeStrategy creates a copy-on-write child of an existing volume whose
+// recorded digest matches, rather than materializing the content again.
+// Digests are recorded when volumes are imported, streamed in or made
+// read-only, and forgotten while they are being streamed in to. Only volumes
+// as privileged as the one being created are used, as the ownership of the
+// others' contents is namespaced differently.
+type DedupeStrategy struct {
+	Digest     string
+	Privileged bool
+}
+
+func (strategy DedupeStrategy) Materialize(logger lager.Logger, handle string, fs Filesystem, streamer Streamer) (FilesystemInitVolume, error) {
+	if strategy.Digest == "" {
+		logger.Info("digest-not-specified")
+		return nil, ErrNoDigestProvided
+	}
+
+	candidates, err := fs.VolumesWithDigest(strategy.Digest)
+	if err != nil {
+		logger.Error("failed-to-find-volumes-with-digest", err)
+		return nil, err
+	}
+
+	ranked := []rankedVolume{}
+	for _, candidate := range candidates {
+		volume, ok := rankForDedupe(candidate, strategy.Digest, strategy.Privileged)
+		if ok {
+			ranked = append(ranked, volume)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].rank() < ranked[j].rank()
+	})
+
+	for _, candidate := range ranked {
+		// the contents of writable volumes may have been changed without going
+		// through the repository, e.g. by a container
+		if !candidate.readOnly {
+			digest, err := TreeDigest(candidate.volume.DataPath())
+			if err != nil || digest != strategy.Digest {
+				continue
+			}
+		}
+
+		logger.Debug("deduplicating", lager.Data{"parent": candidate.volume.Handle()})
+
+		return candidate.volume.NewSubvolume(handle)
+	}
+
+	logger.Info("digest-not-found", lager.Data{"digest": strategy.Digest})
+	return nil, ErrDigestNotFound
+}
+
+type rankedVolume struct {
+	volume    FilesystemLiveVolume
+	readOnly  bool
+	hasParent bool
+}
+
+// rank orders the volumes to deduplicate against. Read-only volumes come
+// first, as their contents cannot drift from their digest, and volumes
+// without a parent before those with one, to keep copy-on-write chains
+// short.
+func (ranked rankedVolume) rank() int {
+	rank := 0
+	if !ranked.readOnly {
+		rank += 2
+	}
+
+	if ranked.hasParent {
+		rank++
+	}
+
+	return rank
+}
+
+func rankForDedupe(volume FilesystemLiveVolume, digest string, privileged bool) (rankedVolume, bool) {
+	recorded, err := volume.LoadDigest()
+	if err != nil || recorded != digest {
+		// destroyed, corrupted or since changed; either way it's no use to us
+		return rankedVolume{}, false
+	}
+
+	isPrivileged, err := volume.LoadPrivileged()
+	if err != nil || isPrivileged != privileged {
+		return rankedVolume{}, false
+	}
+
+	_, hasParent, err := volume.Parent()
+	if err != nil {
+		return rankedVolume{}, false
+	}
+
+	readOnly, err := volume.LoadReadOnly()
+	if err != nil {
+		return rankedVolume{}, false
+	}
+
+	return rankedVolume{
+		volume:    volume,
+		readOnly:  readOnly,
+		hasParent: hasParent,
+	}, true
+}
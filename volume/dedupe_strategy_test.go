@@ -0,0 +1,219 @@
+package volume_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DedupeStrategy", func() {
+	var (
+		strategy Strategy
+	)
+
+	BeforeEach(func() {
+		strategy = DedupeStrategy{Digest: "sha256:some-digest"}
+	})
+
+	Describe("Materialize", func() {
+		var (
+			fakeFilesystem *volumefakes.FakeFilesystem
+
+			materializedVolume FilesystemInitVolume
+			materializeErr     error
+		)
+
+		BeforeEach(func() {
+			fakeFilesystem = new(volumefakes.FakeFilesystem)
+		})
+
+		JustBeforeEach(func() {
+			materializedVolume, materializeErr = strategy.Materialize(
+				lagertest.NewTestLogger("test"),
+				"some-volume",
+				fakeFilesystem,
+				new(volumefakes.FakeStreamer),
+			)
+		})
+
+		liveVolumeWithDigest := func(digest string, hasParent bool) *volumefakes.FakeFilesystemLiveVolume {
+			vol := new(volumefakes.FakeFilesystemLiveVolume)
+			vol.LoadDigestReturns(digest, nil)
+			vol.LoadReadOnlyReturns(true, nil)
+			vol.ParentReturns(nil, hasParent, nil)
+			return vol
+		}
+
+		Context("when a volume with the digest exists", func() {
+			var (
+				otherVolume    *volumefakes.FakeFilesystemLiveVolume
+				childVolume    *volumefakes.FakeFilesystemLiveVolume
+				rootVolume     *volumefakes.FakeFilesystemLiveVolume
+				fakeInitVolume *volumefakes.FakeFilesystemInitVolume
+			)
+
+			BeforeEach(func() {
+				// the index may be behind a digest that has since changed
+				otherVolume = liveVolumeWithDigest("sha256:other-digest", false)
+				childVolume = liveVolumeWithDigest("sha256:some-digest", true)
+				rootVolume = liveVolumeWithDigest("sha256:some-digest", false)
+
+				fakeFilesystem.VolumesWithDigestReturns([]FilesystemLiveVolume{
+					otherVolume,
+					childVolume,
+					rootVolume,
+				}, nil)
+
+				fakeInitVolume = new(volumefakes.FakeFilesystemInitVolume)
+				rootVolume.NewSubvolumeReturns(fakeInitVolume, nil)
+			})
+
+			It("creates a copy-on-write child of it, preferring volumes without a parent", func() {
+				Expect(materializeErr).ToNot(HaveOccurred())
+				Expect(materializedVolume).To(Equal(fakeInitVolume))
+
+				Expect(fakeFilesystem.VolumesWithDigestArgsForCall(0)).To(Equal("sha256:some-digest"))
+
+				Expect(rootVolume.NewSubvolumeCallCount()).To(Equal(1))
+				Expect(rootVolume.NewSubvolumeArgsForCall(0)).To(Equal("some-volume"))
+
+				Expect(childVolume.NewSubvolumeCallCount()).To(Equal(0))
+				Expect(otherVolume.NewSubvolumeCallCount()).To(Equal(0))
+			})
+
+			Context("when the volume without a parent is privileged", func() {
+				BeforeEach(func() {
+					rootVolume.LoadPrivilegedReturns(true, nil)
+					childVolume.NewSubvolumeReturns(fakeInitVolume, nil)
+				})
+
+				It("only uses volumes as privileged as the one being created", func() {
+					Expect(materializeErr).ToNot(HaveOccurred())
+					Expect(childVolume.NewSubvolumeCallCount()).To(Equal(1))
+					Expect(rootVolume.NewSubvolumeCallCount()).To(Equal(0))
+				})
+
+				Context("when the volume being created is privileged", func() {
+					BeforeEach(func() {
+						strategy = DedupeStrategy{Digest: "sha256:some-digest", Privileged: true}
+					})
+
+					It("uses it", func() {
+						Expect(materializeErr).ToNot(HaveOccurred())
+						Expect(rootVolume.NewSubvolumeCallCount()).To(Equal(1))
+						Expect(childVolume.NewSubvolumeCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when the volume without a parent is writable", func() {
+				var dataDir string
+
+				BeforeEach(func() {
+					var err error
+					dataDir, err = ioutil.TempDir("", "dedupe")
+					Expect(err).ToNot(HaveOccurred())
+
+					rootVolume.LoadReadOnlyReturns(false, nil)
+					rootVolume.DataPathReturns(dataDir)
+
+					childVolume.NewSubvolumeReturns(fakeInitVolume, nil)
+				})
+
+				AfterEach(func() {
+					os.RemoveAll(dataDir)
+				})
+
+				It("prefers read-only volumes, whose contents cannot change", func() {
+					Expect(materializeErr).ToNot(HaveOccurred())
+					Expect(childVolume.NewSubvolumeCallCount()).To(Equal(1))
+					Expect(rootVolume.NewSubvolumeCallCount()).To(Equal(0))
+				})
+
+				Context("when it is the only volume with the digest", func() {
+					BeforeEach(func() {
+						fakeFilesystem.VolumesWithDigestReturns([]FilesystemLiveVolume{rootVolume}, nil)
+						rootVolume.NewSubvolumeReturns(fakeInitVolume, nil)
+					})
+
+					Context("when its contents still have the digest", func() {
+						BeforeEach(func() {
+							digest, err := TreeDigest(dataDir)
+							Expect(err).ToNot(HaveOccurred())
+
+							strategy = DedupeStrategy{Digest: digest}
+							rootVolume.LoadDigestReturns(digest, nil)
+						})
+
+						It("creates a copy-on-write child of it", func() {
+							Expect(materializeErr).ToNot(HaveOccurred())
+							Expect(rootVolume.NewSubvolumeCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when its contents have changed since the digest was recorded", func() {
+						It("returns ErrDigestNotFound", func() {
+							Expect(materializeErr).To(Equal(ErrDigestNotFound))
+							Expect(rootVolume.NewSubvolumeCallCount()).To(Equal(0))
+						})
+					})
+				})
+			})
+
+			Context("when creating the sub volume fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					rootVolume.NewSubvolumeReturns(nil, disaster)
+				})
+
+				It("returns the error", func() {
+					Expect(materializeErr).To(Equal(disaster))
+				})
+			})
+		})
+
+		Context("when no volume has the digest", func() {
+			BeforeEach(func() {
+				fakeFilesystem.VolumesWithDigestReturns([]FilesystemLiveVolume{}, nil)
+			})
+
+			It("returns ErrDigestNotFound", func() {
+				Expect(materializeErr).To(Equal(ErrDigestNotFound))
+			})
+		})
+
+		Context("when no digest is given", func() {
+			BeforeEach(func() {
+				strategy = DedupeStrategy{Digest: ""}
+			})
+
+			It("returns ErrNoDigestProvided", func() {
+				Expect(materializeErr).To(Equal(ErrNoDigestProvided))
+			})
+
+			It("does not look for volumes", func() {
+				Expect(fakeFilesystem.VolumesWithDigestCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when looking for volumes with the digest fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeFilesystem.VolumesWithDigestReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(materializeErr).To(Equal(disaster))
+			})
+		})
+	})
+})
@@ -0,0 +1,151 @@
+package volume
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+
+	"github.com/concourse/baggageclaim/metrics"
+	"github.com/concourse/baggageclaim/volume/fsroot"
+)
+
+var ErrVolumeHasNoParent = errors.New("volume has no parent")
+
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeModified ChangeKind = "modified"
+	ChangeDeleted  ChangeKind = "deleted"
+)
+
+// Change describes a path that differs between a copy-on-write volume and
+// its parent.
+//
+// Everything beneath an added directory is listed as added too, but nothing
+// beneath a deleted directory is listed.
+type Change struct {
+	// Path is relative to the root of the volume and slash-separated.
+	Path string     `json:"path"`
+	Kind ChangeKind `json:"kind"`
+}
+
+// Diff lists the paths that have been added, modified or deleted in a
+// copy-on-write volume since it was created from its parent, ordered by
+// path.
+func (repo *repository) Diff(ctx context.Context, handle string) ([]Change, error) {
+	logger := lagerctx.FromContext(ctx).Session("diff", lager.Data{
+		"volume": handle,
+	})
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return nil, err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return nil, ErrVolumeDoesNotExist
+	}
+
+	changes, err := volume.Diff()
+	if err != nil {
+		if err == ErrVolumeHasNoParent {
+			logger.Info("volume-has-no-parent")
+		} else {
+			logger.Error("failed-to-diff-volume", err)
+		}
+
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// StreamOutDiff streams out the changes beneath path as a layer: a tar
+// stream of the added and modified paths, with deleted paths recorded as
+// whiteouts.
+func (repo *repository) StreamOutDiff(ctx context.Context, handle string, path string, encoding string, dest io.Writer) error {
+	logger := lagerctx.FromContext(ctx).Session("stream-out-diff", lager.Data{
+		"volume":   handle,
+		"sub-path": path,
+	})
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return ErrVolumeDoesNotExist
+	}
+
+	info, err := fsroot.Stat(volume.DataPath(), path)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return &os.PathError{Op: "diff", Path: path, Err: syscall.ENOTDIR}
+	}
+
+	isPrivileged, err := volume.LoadPrivileged()
+	if err != nil {
+		logger.Error("failed-to-check-if-volume-is-privileged", err)
+		return err
+	}
+
+	streamer, found := repo.streamer(encoding)
+	if !found {
+		return ErrUnsupportedStreamEncoding
+	}
+
+	changes, err := volume.Diff()
+	if err != nil {
+		if err != ErrVolumeHasNoParent {
+			logger.Error("failed-to-diff-volume", err)
+		}
+
+		return err
+	}
+
+	return streamer.OutDiff(meteredWriter{
+		Writer: dest,
+		bytes:  metrics.StreamedBytes.WithLabelValues("out", encoding),
+	}, volume.DataPath(), path, changesBeneath(changes, path), isPrivileged)
+}
+
+// changesBeneath returns the changes at or beneath path, with their paths
+// made relative to it.
+func changesBeneath(changes []Change, path string) []Change {
+	prefix := strings.Trim(filepath.ToSlash(filepath.Clean("/"+path)), "/")
+	if prefix == "" {
+		return changes
+	}
+
+	beneath := []Change{}
+	for _, change := range changes {
+		switch {
+		case change.Path == prefix:
+			change.Path = "."
+		case strings.HasPrefix(change.Path, prefix+"/"):
+			change.Path = strings.TrimPrefix(change.Path, prefix+"/")
+		default:
+			continue
+		}
+
+		beneath = append(beneath, change)
+	}
+
+	return beneath
+}
@@ -0,0 +1,181 @@
+package volume_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/uidgid/uidgidfakes"
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+)
+
+var _ = Describe("Diff", func() {
+	var (
+		fakeFilesystem *volumefakes.FakeFilesystem
+		fakeVolume     *volumefakes.FakeFilesystemLiveVolume
+
+		repository volume.Repository
+
+		dataDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dataDir, err = ioutil.TempDir("", "diff")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(dataDir, "some-dir", "new-dir"), 0755)).To(Succeed())
+
+		err = ioutil.WriteFile(filepath.Join(dataDir, "some-dir", "modified-file"), []byte("modified"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(dataDir, "some-dir", "new-dir", "new-file"), []byte("new"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+		fakeVolume.DataPathReturns(dataDir)
+		fakeVolume.LoadPrivilegedReturns(true, nil)
+		fakeVolume.DiffReturns([]volume.Change{
+			{Path: "deleted-file", Kind: volume.ChangeDeleted},
+			{Path: "some-dir", Kind: volume.ChangeModified},
+			{Path: "some-dir/deleted-dir", Kind: volume.ChangeDeleted},
+			{Path: "some-dir/modified-file", Kind: volume.ChangeModified},
+			{Path: "some-dir/new-dir", Kind: volume.ChangeAdded},
+			{Path: "some-dir/new-dir/new-file", Kind: volume.ChangeAdded},
+		}, nil)
+
+		fakeFilesystem = new(volumefakes.FakeFilesystem)
+		fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+
+		repository = volume.NewRepository(
+			fakeFilesystem,
+			new(volumefakes.FakeLockManager),
+			new(uidgidfakes.FakeNamespacer),
+			new(uidgidfakes.FakeNamespacer),
+			nil,
+			0,
+		)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dataDir)
+	})
+
+	tarNames := func(r io.Reader) []string {
+		names := []string{}
+
+		tarReader := tar.NewReader(r)
+		for {
+			hdr, err := tarReader.Next()
+			if err == io.EOF {
+				return names
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+			names = append(names, hdr.Name)
+		}
+	}
+
+	Describe("Diff", func() {
+		It("returns the volume's changes", func() {
+			changes, err := repository.Diff(context.Background(), "some-handle")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(HaveLen(6))
+
+			Expect(fakeFilesystem.LookupVolumeArgsForCall(0)).To(Equal("some-handle"))
+		})
+
+		Context("when the volume has no parent", func() {
+			BeforeEach(func() {
+				fakeVolume.DiffReturns(nil, volume.ErrVolumeHasNoParent)
+			})
+
+			It("returns ErrVolumeHasNoParent", func() {
+				_, err := repository.Diff(context.Background(), "some-handle")
+				Expect(err).To(Equal(volume.ErrVolumeHasNoParent))
+			})
+		})
+
+		Context("when diffing the volume fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeVolume.DiffReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := repository.Diff(context.Background(), "some-handle")
+				Expect(err).To(Equal(disaster))
+			})
+		})
+
+		Context("when the volume does not exist", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+			})
+
+			It("returns ErrVolumeDoesNotExist", func() {
+				_, err := repository.Diff(context.Background(), "some-handle")
+				Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+			})
+		})
+	})
+
+	Describe("StreamOutDiff", func() {
+		It("streams out the changes as a layer", func() {
+			buf := new(bytes.Buffer)
+			err := repository.StreamOutDiff(context.Background(), "some-handle", "", volume.IdentityEncoding, buf)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tarNames(buf)).To(Equal([]string{
+				".wh.deleted-file",
+				"some-dir/",
+				"some-dir/.wh.deleted-dir",
+				"some-dir/modified-file",
+				"some-dir/new-dir/",
+				"some-dir/new-dir/new-file",
+			}))
+		})
+
+		It("streams out only the changes beneath the path, relative to it", func() {
+			buf := new(bytes.Buffer)
+			err := repository.StreamOutDiff(context.Background(), "some-handle", "some-dir/new-dir", volume.IdentityEncoding, buf)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tarNames(buf)).To(Equal([]string{
+				"./",
+				"new-file",
+			}))
+		})
+
+		It("returns a not-exist error for a missing path", func() {
+			err := repository.StreamOutDiff(context.Background(), "some-handle", "bogus", volume.IdentityEncoding, ioutil.Discard)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("returns ErrUnsupportedStreamEncoding for an unknown encoding", func() {
+			err := repository.StreamOutDiff(context.Background(), "some-handle", "", "bogus", ioutil.Discard)
+			Expect(err).To(Equal(volume.ErrUnsupportedStreamEncoding))
+		})
+
+		Context("when the volume has no parent", func() {
+			BeforeEach(func() {
+				fakeVolume.DiffReturns(nil, volume.ErrVolumeHasNoParent)
+			})
+
+			It("returns ErrVolumeHasNoParent", func() {
+				err := repository.StreamOutDiff(context.Background(), "some-handle", "", volume.IdentityEncoding, ioutil.Discard)
+				Expect(err).To(Equal(volume.ErrVolumeHasNoParent))
+			})
+		})
+	})
+})
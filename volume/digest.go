@@ -0,0 +1,144 @@
+package volume
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/concourse/baggageclaim/volume/fsroot"
+)
+
+const digestAlgorithm = "sha256"
+
+// TreeDigest computes a deterministic digest of the directory tree at root.
+//
+// Entries are visited in lexical order and contribute their relative path,
+// type, mode including the setuid, setgid and sticky bits, ownership, device
+// numbers, extended attributes, and either their contents (regular files) or
+// their target (symlinks). Ownership is taken as it is stored on disk, so
+// unprivileged volumes' digests differ from those of privileged volumes with
+// the same content. Timestamps are ignored so that the same content has the
+// same digest regardless of when it was written.
+func TreeDigest(root string) (string, error) {
+	hash := sha256.New()
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		uid, gid := fileOwner(info)
+
+		fmt.Fprintf(hash, "%s\x00%c\x00%o\x00%d\x00%d\x00%d\x00",
+			filepath.ToSlash(relPath), entryType(info.Mode()), unixMode(info.Mode()),
+			uid, gid, fileDevice(info))
+
+		err = hashXattrs(hash, path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.Mode().IsRegular():
+			err = hashFile(hash, path)
+			if err != nil {
+				return err
+			}
+
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			io.WriteString(hash, target)
+		}
+
+		hash.Write([]byte{'\n'})
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return digestAlgorithm + ":" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	// hash the contents separately so that they cannot be confused with the
+	// entry framing
+	contents := sha256.New()
+
+	_, err = io.Copy(contents, file)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(contents.Sum(nil))
+	return err
+}
+
+func hashXattrs(w io.Writer, path string) error {
+	dir, err := fsroot.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+
+	defer dir.Close()
+
+	xattrs, err := dir.Lxattrs(filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(xattrs))
+	for name := range xattrs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	// values may hold anything, so they are hex-encoded to keep them from
+	// being confused with the entry framing
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\x00%x\x00", name, xattrs[name])
+	}
+
+	return nil
+}
+
+func entryType(mode os.FileMode) rune {
+	switch {
+	case mode.IsDir():
+		return 'd'
+	case mode&os.ModeSymlink != 0:
+		return 'l'
+	case mode&os.ModeCharDevice != 0:
+		return 'c'
+	case mode&os.ModeDevice != 0:
+		return 'b'
+	case mode&os.ModeNamedPipe != 0:
+		return 'p'
+	case mode&os.ModeSocket != 0:
+		return 's'
+	default:
+		return 'f'
+	}
+}
@@ -0,0 +1,89 @@
+package volume
+
+import "sync"
+
+// digestIndex maps the digests recorded for live volumes to their handles,
+// so that finding a volume by its digest does not read every volume's
+// metadata. It is loaded from the volumes' metadata when first used, and
+// kept up to date as digests are recorded and volumes move or go away.
+type digestIndex struct {
+	mutex   sync.Mutex
+	loaded  bool
+	digests map[string]string          // handle -> digest
+	handles map[string]map[string]bool // digest -> handles
+}
+
+func newDigestIndex() *digestIndex {
+	return &digestIndex{
+		digests: map[string]string{},
+		handles: map[string]map[string]bool{},
+	}
+}
+
+// lookup returns the handles of the volumes recorded with the digest,
+// loading the index with load first if need be.
+func (index *digestIndex) lookup(digest string, load func() (map[string]string, error)) ([]string, error) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if !index.loaded {
+		digests, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		for handle, digest := range digests {
+			index.set(handle, digest)
+		}
+
+		index.loaded = true
+	}
+
+	handles := make([]string, 0, len(index.handles[digest]))
+	for handle := range index.handles[digest] {
+		handles = append(handles, handle)
+	}
+
+	return handles, nil
+}
+
+// record notes the volume's digest, or forgets it if digest is "".
+func (index *digestIndex) record(handle string, digest string) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	index.set(handle, digest)
+}
+
+// move notes that the volume has been renamed.
+func (index *digestIndex) move(handle string, newHandle string) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	digest := index.digests[handle]
+	index.set(handle, "")
+	index.set(newHandle, digest)
+}
+
+func (index *digestIndex) set(handle string, digest string) {
+	if old, found := index.digests[handle]; found {
+		delete(index.handles[old], handle)
+		if len(index.handles[old]) == 0 {
+			delete(index.handles, old)
+		}
+
+		delete(index.digests, handle)
+	}
+
+	if digest == "" {
+		return
+	}
+
+	index.digests[handle] = digest
+
+	if index.handles[digest] == nil {
+		index.handles[digest] = map[string]bool{}
+	}
+
+	index.handles[digest][handle] = true
+}
@@ -0,0 +1,76 @@
+package volume_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/concourse/baggageclaim/volume"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TreeDigest on linux", func() {
+	var tempDir, a, b string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "tree-digest")
+		Expect(err).ToNot(HaveOccurred())
+
+		a = filepath.Join(tempDir, "a")
+		b = filepath.Join(tempDir, "b")
+
+		for _, root := range []string{a, b} {
+			Expect(os.MkdirAll(root, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(root, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	digestOf := func(root string) string {
+		digest, err := volume.TreeDigest(root)
+		Expect(err).ToNot(HaveOccurred())
+		return digest
+	}
+
+	It("changes when an extended attribute changes", func() {
+		for root, value := range map[string]string{a: "some-value", b: "other-value"} {
+			err := unix.Lsetxattr(filepath.Join(root, "some-file"), "user.some-attr", []byte(value), 0)
+			if err == unix.ENOTSUP {
+				Skip("filesystem does not support extended attributes")
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(digestOf(a)).ToNot(Equal(digestOf(b)))
+	})
+
+	Context("when running as root", func() {
+		BeforeEach(func() {
+			if os.Geteuid() != 0 {
+				Skip("must be run as root to change ownership and make devices")
+			}
+		})
+
+		It("changes when ownership changes", func() {
+			Expect(os.Lchown(filepath.Join(b, "some-file"), 1000, 1001)).To(Succeed())
+
+			Expect(digestOf(a)).ToNot(Equal(digestOf(b)))
+		})
+
+		It("changes when a device's numbers change", func() {
+			Expect(unix.Mknod(filepath.Join(a, "some-device"), unix.S_IFCHR|0666, int(unix.Mkdev(1, 3)))).To(Succeed())
+			Expect(unix.Mknod(filepath.Join(b, "some-device"), unix.S_IFCHR|0666, int(unix.Mkdev(1, 5)))).To(Succeed())
+
+			Expect(digestOf(a)).ToNot(Equal(digestOf(b)))
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package volume_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/concourse/baggageclaim/volume"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TreeDigest", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "tree-digest")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	makeTree := func(name string) string {
+		root := filepath.Join(tempDir, name)
+
+		Expect(os.MkdirAll(filepath.Join(root, "some-dir"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(root, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(root, "some-dir", "nested-file"), []byte("nested-content"), 0600)).To(Succeed())
+		Expect(os.Symlink("some-file", filepath.Join(root, "some-link"))).To(Succeed())
+
+		return root
+	}
+
+	digestOf := func(root string) string {
+		digest, err := volume.TreeDigest(root)
+		Expect(err).ToNot(HaveOccurred())
+		return digest
+	}
+
+	It("is the same for identical trees", func() {
+		Expect(digestOf(makeTree("a"))).To(Equal(digestOf(makeTree("b"))))
+	})
+
+	It("is prefixed with the algorithm", func() {
+		Expect(digestOf(makeTree("a"))).To(HavePrefix("sha256:"))
+	})
+
+	It("ignores modification times", func() {
+		a := makeTree("a")
+		b := makeTree("b")
+
+		old := time.Now().Add(-time.Hour)
+		Expect(os.Chtimes(filepath.Join(b, "some-file"), old, old)).To(Succeed())
+
+		Expect(digestOf(a)).To(Equal(digestOf(b)))
+	})
+
+	It("changes when file contents change", func() {
+		a := makeTree("a")
+		b := makeTree("b")
+
+		Expect(ioutil.WriteFile(filepath.Join(b, "some-file"), []byte("other-content"), 0644)).To(Succeed())
+
+		Expect(digestOf(a)).ToNot(Equal(digestOf(b)))
+	})
+
+	It("changes when a file is renamed", func() {
+		a := makeTree("a")
+		b := makeTree("b")
+
+		Expect(os.Rename(filepath.Join(b, "some-dir", "nested-file"), filepath.Join(b, "some-dir", "renamed-file"))).To(Succeed())
+
+		Expect(digestOf(a)).ToNot(Equal(digestOf(b)))
+	})
+
+	It("changes when permissions change", func() {
+		a := makeTree("a")
+		b := makeTree("b")
+
+		Expect(os.Chmod(filepath.Join(b, "some-file"), 0755)).To(Succeed())
+
+		Expect(digestOf(a)).ToNot(Equal(digestOf(b)))
+	})
+
+	It("changes when the setuid bit is set", func() {
+		a := makeTree("a")
+		b := makeTree("b")
+
+		Expect(os.Chmod(filepath.Join(b, "some-file"), 0644|os.ModeSetuid)).To(Succeed())
+
+		Expect(digestOf(a)).ToNot(Equal(digestOf(b)))
+	})
+
+	It("changes when a symlink target changes", func() {
+		a := makeTree("a")
+		b := makeTree("b")
+
+		Expect(os.Remove(filepath.Join(b, "some-link"))).To(Succeed())
+		Expect(os.Symlink("some-dir", filepath.Join(b, "some-link"))).To(Succeed())
+
+		Expect(digestOf(a)).ToNot(Equal(digestOf(b)))
+	})
+})
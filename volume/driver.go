@@ -1,5 +1,10 @@
 package volume
 
+import "errors"
+
+var ErrQuotaNotSupported = errors.New("volume quotas are not supported by this driver")
+var ErrRepairNotSupported = errors.New("volume repair is not supported by this driver")
+
 //go:generate counterfeiter . Driver
 
 type Driver interface {
@@ -8,5 +13,59 @@ type Driver interface {
 
 	CreateCopyOnWriteLayer(FilesystemInitVolume, FilesystemLiveVolume) error
 
+	// SharesLayers reports whether a copy-on-write layer shares the data of
+	// every volume it descends from, rather than copying some or all of it,
+	// so that layers can be stacked many deep without the cost growing.
+	SharesLayers() bool
+
+	// Usage reports how much disk space and how many inodes the volume
+	// consumes, split into space exclusive to the volume and space shared
+	// with its parent(s).
+	Usage(FilesystemVolume) (VolumeUsage, error)
+
+	// SetQuota limits the number of bytes exclusive to the volume. Drivers
+	// that cannot enforce a quota return ErrQuotaNotSupported.
+	SetQuota(FilesystemVolume, uint64) error
+
+	// Check returns an error describing what is wrong with the driver's
+	// state for the volume, e.g. a missing mount, or nil if it is healthy.
+	Check(FilesystemVolume) error
+
+	// SetReadOnly protects the volume's data from writes, where the driver
+	// can. Drivers that mount volumes must protect read-only volumes again
+	// when remounting them.
+	SetReadOnly(FilesystemVolume) error
+
+	// RenameVolume moves any state the driver keeps under the volume's handle
+	// to where it would be kept for the given handle. It is called before the
+	// volume's own directory is moved, and must leave the volume usable while
+	// it stays mounted.
+	RenameVolume(FilesystemVolume, string) error
+
+	// Repair attempts to restore the driver's state for the volume. Drivers
+	// that have nothing to restore return ErrRepairNotSupported.
+	Repair(FilesystemVolume) error
+
+	// Diff lists what has been added, modified or deleted in a copy-on-write
+	// volume since it was created from its parent, ordered by path. Drivers
+	// that keep nothing of the parent as it was when the volume was created
+	// compare the volume with the parent as it is now.
+	Diff(child FilesystemVolume, parent FilesystemLiveVolume) ([]Change, error)
+
+	// CreateSnapshot copies the volume's data to the snapshot's data path,
+	// protected from writes where the driver can enforce it. Drivers which
+	// cannot freeze the data while it is copied return ErrVolumeInUse if it
+	// changed in the meantime.
+	CreateSnapshot(FilesystemLiveVolume, FilesystemSnapshot) error
+
+	// RestoreSnapshot replaces the volume's data with a writable copy of the
+	// snapshot's, leaving the snapshot as it was. Drivers which cannot protect
+	// snapshots from writes return ErrSnapshotCorrupted if it has changed.
+	RestoreSnapshot(FilesystemLiveVolume, FilesystemSnapshot) error
+
+	// DestroySnapshot removes the snapshot's data, which may be missing if
+	// taking the snapshot failed part way.
+	DestroySnapshot(FilesystemSnapshot) error
+
 	Recover(Filesystem) error
 }
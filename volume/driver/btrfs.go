@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/baggageclaim/volume"
@@ -14,15 +16,22 @@ import (
 type BtrFSDriver struct {
 	logger   lager.Logger
 	btrfsBin string
+	quotas   bool
 }
 
+// NewBtrFSDriver returns a driver for a btrfs filesystem. Quotas must have
+// been enabled on the filesystem for it to measure usage with qgroups and
+// limit volumes' sizes; without them, usage is measured by walking the
+// volume, and quotas are not supported.
 func NewBtrFSDriver(
 	logger lager.Logger,
 	btrfsBin string,
+	quotas bool,
 ) *BtrFSDriver {
 	return &BtrFSDriver{
 		logger:   logger,
 		btrfsBin: btrfsBin,
+		quotas:   quotas,
 	}
 }
 
@@ -36,6 +45,38 @@ func (driver *BtrFSDriver) CreateVolume(vol volume.FilesystemInitVolume) error {
 }
 
 func (driver *BtrFSDriver) DestroyVolume(vol volume.FilesystemVolume) error {
+	if _, err := os.Lstat(diffBasePath(vol)); err == nil {
+		_, _, err := driver.run(driver.btrfsBin, "subvolume", "delete", diffBasePath(vol))
+		if err != nil {
+			return err
+		}
+	}
+
+	// the subvolume may never have been created if the volume was orphaned
+	// part way through creation
+	if _, err := os.Lstat(vol.DataPath()); os.IsNotExist(err) {
+		return nil
+	}
+
+	readOnly, err := vol.LoadReadOnly()
+	if err != nil {
+		return err
+	}
+
+	// subvolumes nested in a read-only subvolume cannot be deleted
+	if readOnly {
+		_, _, err := driver.run(driver.btrfsBin, "property", "set", "-ts", vol.DataPath(), "ro", "false")
+		if err != nil {
+			return err
+		}
+	}
+
+	return driver.deleteSubvolumes(vol.DataPath())
+}
+
+// deleteSubvolumes deletes the subvolume at path along with any nested within
+// it.
+func (driver *BtrFSDriver) deleteSubvolumes(path string) error {
 	volumePathsToDelete := []string{}
 
 	findSubvolumes := func(p string, f os.FileInfo, err error) error {
@@ -59,8 +100,8 @@ func (driver *BtrFSDriver) DestroyVolume(vol volume.FilesystemVolume) error {
 		return nil
 	}
 
-	if err := filepath.Walk(vol.DataPath(), findSubvolumes); err != nil {
-		return fmt.Errorf("recursively walking subvolumes for %s failed: %v", vol.DataPath(), err)
+	if err := filepath.Walk(path, findSubvolumes); err != nil {
+		return fmt.Errorf("recursively walking subvolumes for %s failed: %v", path, err)
 	}
 
 	for i := len(volumePathsToDelete) - 1; i >= 0; i-- {
@@ -73,14 +114,361 @@ func (driver *BtrFSDriver) DestroyVolume(vol volume.FilesystemVolume) error {
 	return nil
 }
 
+// CreateCopyOnWriteLayer snapshots the parent twice: once read-only, as the
+// base the child is diffed against, and then again from that base as the
+// child itself, so that the two start out the same whatever happens to the
+// parent. The base shares its extents with the parent until the parent
+// changes.
 func (driver *BtrFSDriver) CreateCopyOnWriteLayer(
 	childVol volume.FilesystemInitVolume,
 	parentVol volume.FilesystemLiveVolume,
 ) error {
-	_, _, err := driver.run(driver.btrfsBin, "subvolume", "snapshot", parentVol.DataPath(), childVol.DataPath())
+	basePath := diffBasePath(childVol)
+
+	_, _, err := driver.run(driver.btrfsBin, "subvolume", "snapshot", "-r", parentVol.DataPath(), basePath)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = driver.run(driver.btrfsBin, "subvolume", "snapshot", basePath, childVol.DataPath())
+	if err != nil {
+		_, _, _ = driver.run(driver.btrfsBin, "subvolume", "delete", basePath)
+		return err
+	}
+
+	return nil
+}
+
+// diffBasePath is where a copy-on-write volume keeps a read-only snapshot of
+// its parent as it was when the volume was created.
+func diffBasePath(vol volume.FilesystemVolume) string {
+	return filepath.Join(filepath.Dir(vol.DataPath()), "diff-base")
+}
+
+// SharesLayers is true as a snapshot of a snapshot shares its extents with
+// every subvolume it descends from.
+func (driver *BtrFSDriver) SharesLayers() bool {
+	return true
+}
+
+func (driver *BtrFSDriver) Usage(vol volume.FilesystemVolume) (volume.VolumeUsage, error) {
+	if !driver.quotas {
+		// data shared with a parent snapshot cannot be told apart, so it is
+		// all counted as the volume's own
+		bytes, inodes, err := diskUsage(vol.DataPath())
+		if err != nil {
+			return volume.VolumeUsage{}, err
+		}
+
+		return volume.VolumeUsage{
+			ExclusiveBytes: bytes,
+			Inodes:         inodes,
+		}, nil
+	}
+
+	referenced, exclusive, err := driver.qgroupUsage(vol.DataPath())
+	if err != nil {
+		return volume.VolumeUsage{}, err
+	}
+
+	// qgroups only account for bytes, so count inodes by hand
+	_, inodes, err := diskUsage(vol.DataPath())
+	if err != nil {
+		return volume.VolumeUsage{}, err
+	}
+
+	return volume.VolumeUsage{
+		ExclusiveBytes: exclusive,
+		SharedBytes:    referenced - exclusive,
+		Inodes:         inodes,
+	}, nil
+}
+
+func (driver *BtrFSDriver) SetQuota(vol volume.FilesystemVolume, bytes uint64) error {
+	if !driver.quotas {
+		return volume.ErrQuotaNotSupported
+	}
+
+	// limit exclusive usage so that data shared with a parent does not count
+	// against the child
+	_, _, err := driver.run(driver.btrfsBin, "qgroup", "limit", "-e", strconv.FormatUint(bytes, 10), vol.DataPath())
 	return err
 }
 
+// SetReadOnly marks the volume's subvolume read-only. Snapshots taken of it
+// to create copy-on-write children are still writable.
+func (driver *BtrFSDriver) SetReadOnly(vol volume.FilesystemVolume) error {
+	_, _, err := driver.run(driver.btrfsBin, "property", "set", "-ts", vol.DataPath(), "ro", "true")
+	return err
+}
+
+func (driver *BtrFSDriver) RenameVolume(volume.FilesystemVolume, string) error {
+	// the subvolume lives within the volume's directory, and snapshots of it
+	// do not refer back to it, so there is nothing to move
+	return nil
+}
+
+func (driver *BtrFSDriver) Check(vol volume.FilesystemVolume) error {
+	isSub, err := isSubvolume(vol.DataPath())
+	if err != nil {
+		return err
+	}
+
+	if !isSub {
+		return fmt.Errorf("%s is not a btrfs subvolume", vol.DataPath())
+	}
+
+	return nil
+}
+
+func (driver *BtrFSDriver) Repair(volume.FilesystemVolume) error {
+	// a lost subvolume cannot be brought back
+	return volume.ErrRepairNotSupported
+}
+
+// Diff compares the volume with the snapshot of its parent taken when it was
+// created, so that changes made to the parent since are not mistaken for
+// changes to the volume, and asks btrfs which files have been written to in
+// the volume since it was created to catch those rewritten without their
+// size or modification time changing.
+//
+// Volumes created before their parent's snapshot was kept have only the
+// parent's current contents to be compared with.
+func (driver *BtrFSDriver) Diff(child volume.FilesystemVolume, parent volume.FilesystemLiveVolume) ([]volume.Change, error) {
+	baseDir := diffBasePath(child)
+	if _, err := os.Lstat(baseDir); os.IsNotExist(err) {
+		baseDir = parent.DataPath()
+	}
+
+	changes, err := compareTrees(baseDir, child.DataPath())
+	if err != nil {
+		return nil, err
+	}
+
+	generation, err := driver.creationGeneration(child.DataPath())
+	if err != nil {
+		return nil, err
+	}
+
+	// the snapshot is taken as its generation is committed, so anything
+	// written to it afterwards belongs to a later one
+	written, err := driver.writtenSince(child.DataPath(), generation+1)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := map[string]bool{}
+	for _, change := range changes {
+		changed[change.Path] = true
+	}
+
+	for _, path := range written {
+		if changed[path] {
+			continue
+		}
+
+		changed[path] = true
+
+		changes = append(changes, volume.Change{
+			Path: path,
+			Kind: volume.ChangeModified,
+		})
+	}
+
+	sortChanges(changes)
+
+	return changes, nil
+}
+
+// CreateSnapshot takes a read-only snapshot of the volume's subvolume.
+func (driver *BtrFSDriver) CreateSnapshot(vol volume.FilesystemLiveVolume, snapshot volume.FilesystemSnapshot) error {
+	_, _, err := driver.run(driver.btrfsBin, "subvolume", "snapshot", "-r", vol.DataPath(), snapshot.DataPath())
+	return err
+}
+
+// RestoreSnapshot replaces the volume's subvolume with a writable snapshot of
+// the read-only one, carrying over any limit on its exclusive usage. The
+// replaced subvolume is moved aside before the snapshot is moved into place,
+// and only deleted once it has been.
+func (driver *BtrFSDriver) RestoreSnapshot(vol volume.FilesystemLiveVolume, snapshot volume.FilesystemSnapshot) error {
+	restorePath := restorePath(vol)
+	replacedPath := replacedPath(vol)
+
+	// left behind by a restore that failed part way
+	for _, path := range []string{restorePath, replacedPath} {
+		if _, err := os.Lstat(path); err == nil {
+			err := driver.deleteSubvolumes(path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, _, err := driver.run(driver.btrfsBin, "subvolume", "snapshot", snapshot.DataPath(), restorePath)
+	if err != nil {
+		return err
+	}
+
+	limit, limited, err := driver.exclusiveLimit(vol.DataPath())
+	if err != nil {
+		// quotas may not be enabled, in which case there is nothing to carry
+		// over
+		driver.logger.Debug("failed-to-get-exclusive-limit", lager.Data{
+			"error": err.Error(),
+		})
+	}
+
+	err = swapIn(restorePath, vol.DataPath(), replacedPath)
+	if err != nil {
+		return err
+	}
+
+	err = driver.deleteSubvolumes(replacedPath)
+	if err != nil {
+		return err
+	}
+
+	if !limited {
+		return nil
+	}
+
+	return driver.SetQuota(vol, limit)
+}
+
+func (driver *BtrFSDriver) DestroySnapshot(snapshot volume.FilesystemSnapshot) error {
+	if _, err := os.Lstat(snapshot.DataPath()); os.IsNotExist(err) {
+		return nil
+	}
+
+	_, _, err := driver.run(driver.btrfsBin, "subvolume", "delete", snapshot.DataPath())
+	return err
+}
+
+// creationGeneration returns the generation in which the subvolume at the
+// given path was created.
+func (driver *BtrFSDriver) creationGeneration(path string) (uint64, error) {
+	stdout, _, err := driver.run(driver.btrfsBin, "subvolume", "show", path)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(fields) != 2 || fields[0] != "Gen at creation" {
+			continue
+		}
+
+		generation, err := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("malformed subvolume output %q: %s", line, err)
+		}
+
+		return generation, nil
+	}
+
+	return 0, fmt.Errorf("no creation generation found for %s", path)
+}
+
+// writtenSince returns the paths of the files in the subvolume at the given
+// path with data written in or after the given generation.
+func (driver *BtrFSDriver) writtenSince(path string, generation uint64) ([]string, error) {
+	stdout, _, err := driver.run(driver.btrfsBin, "subvolume", "find-new", path, strconv.FormatUint(generation, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	// output is a line per extent written, ending with the file's path:
+	//
+	//   inode 257 file offset 0 len 4096 disk start 0 offset 0 gen 10 flags NONE some/file
+	//
+	// followed by a "transid marker" line
+	const pathField = 16
+
+	seen := map[string]bool{}
+	paths := []string{}
+	for _, line := range strings.Split(stdout, "\n") {
+		if !strings.HasPrefix(line, "inode ") {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", pathField+1)
+		if len(fields) != pathField+1 {
+			return nil, fmt.Errorf("malformed find-new output %q", line)
+		}
+
+		path := fields[pathField]
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	return paths, nil
+}
+
+// qgroupUsage returns the referenced and exclusive byte counts of the level-0
+// qgroup belonging to the subvolume at the given path. Quotas must be enabled
+// on the filesystem.
+func (driver *BtrFSDriver) qgroupUsage(path string) (uint64, uint64, error) {
+	stdout, _, err := driver.run(driver.btrfsBin, "qgroup", "show", "-f", "--raw", path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// output is a header followed by lines of "<qgroupid> <rfer> <excl>"
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !strings.HasPrefix(fields[0], "0/") {
+			continue
+		}
+
+		referenced, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("malformed qgroup output %q: %s", line, err)
+		}
+
+		exclusive, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("malformed qgroup output %q: %s", line, err)
+		}
+
+		return referenced, exclusive, nil
+	}
+
+	return 0, 0, fmt.Errorf("no qgroup found for %s", path)
+}
+
+// exclusiveLimit returns the limit on exclusive usage of the level-0 qgroup
+// belonging to the subvolume at the given path, if it has one.
+func (driver *BtrFSDriver) exclusiveLimit(path string) (uint64, bool, error) {
+	stdout, _, err := driver.run(driver.btrfsBin, "qgroup", "show", "-fe", "--raw", path)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// output is a header followed by lines of
+	// "<qgroupid> <rfer> <excl> <max_excl>", where max_excl may be "none"
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "0/") {
+			continue
+		}
+
+		if fields[3] == "none" {
+			return 0, false, nil
+		}
+
+		limit, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("malformed qgroup output %q: %s", line, err)
+		}
+
+		return limit, true, nil
+	}
+
+	return 0, false, fmt.Errorf("no qgroup found for %s", path)
+}
+
 func (driver *BtrFSDriver) run(command string, args ...string) (string, string, error) {
 	cmd := exec.Command(command, args...)
 
@@ -47,7 +47,7 @@ var _ = Describe("BtrFS", func() {
 		err = filesystem.Create(1 * 1024 * 1024 * 1024)
 		Expect(err).NotTo(HaveOccurred())
 
-		fsDriver = driver.NewBtrFSDriver(logger, "btrfs")
+		fsDriver = driver.NewBtrFSDriver(logger, "btrfs", false)
 
 		volumeFs, err = volume.NewFilesystem(fsDriver, volumesDir)
 		Expect(err).ToNot(HaveOccurred())
@@ -110,4 +110,40 @@ var _ = Describe("BtrFS", func() {
 			Expect(siblingVol.DataPath()).To(BeADirectory())
 		})
 	})
+
+	Describe("Diff", func() {
+		It("does not list changes made to the parent since the child was created", func() {
+			parentInit, err := volumeFs.NewVolume("parent-volume")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(parentInit.DataPath(), "some-file"), []byte("some-contents"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			parentVol, err := parentInit.Initialize()
+			Expect(err).NotTo(HaveOccurred())
+
+			childInit, err := parentVol.NewSubvolume("child-volume")
+			Expect(err).NotTo(HaveOccurred())
+
+			childVol, err := childInit.Initialize()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(parentVol.DataPath(), "parent-file"), []byte("parent-contents"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = os.Remove(filepath.Join(parentVol.DataPath(), "some-file"))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(childVol.DataPath(), "child-file"), []byte("child-contents"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(childVol.Diff()).To(Equal([]volume.Change{
+				{Path: "child-file", Kind: volume.ChangeAdded},
+			}))
+
+			err = childVol.Destroy()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Dir(childVol.DataPath())).NotTo(BeADirectory())
+		})
+	})
 })
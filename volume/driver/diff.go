@@ -0,0 +1,153 @@
+package driver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/concourse/baggageclaim/volume"
+)
+
+// compareTrees lists the differences between a parent's data and its
+// child's by walking both. Files are compared by their metadata rather than
+// their contents, so a file rewritten without its size or modification time
+// changing is not noticed.
+func compareTrees(parentDir string, childDir string) ([]volume.Change, error) {
+	differ := &treeDiffer{
+		parentDir: parentDir,
+		childDir:  childDir,
+	}
+
+	err := differ.compareDir(".")
+	if err != nil {
+		return nil, err
+	}
+
+	sortChanges(differ.changes)
+
+	return differ.changes, nil
+}
+
+type treeDiffer struct {
+	parentDir string
+	childDir  string
+
+	changes []volume.Change
+}
+
+func (differ *treeDiffer) compareDir(rel string) error {
+	parentInfos, err := ioutil.ReadDir(filepath.Join(differ.parentDir, rel))
+	if err != nil {
+		return err
+	}
+
+	childInfos, err := ioutil.ReadDir(filepath.Join(differ.childDir, rel))
+	if err != nil {
+		return err
+	}
+
+	inParent := map[string]os.FileInfo{}
+	for _, info := range parentInfos {
+		inParent[info.Name()] = info
+	}
+
+	for _, childInfo := range childInfos {
+		name := filepath.Join(rel, childInfo.Name())
+
+		parentInfo, found := inParent[childInfo.Name()]
+		delete(inParent, childInfo.Name())
+
+		switch {
+		case !found:
+			err = differ.addAll(name, childInfo)
+
+		case parentInfo.IsDir() && childInfo.IsDir():
+			if !sameMetadata(parentInfo, childInfo) {
+				differ.record(name, volume.ChangeModified)
+			}
+
+			err = differ.compareDir(name)
+
+		default:
+			if sameMetadata(parentInfo, childInfo) {
+				continue
+			}
+
+			differ.record(name, volume.ChangeModified)
+
+			// a directory replacing something else has all new contents
+			if childInfo.IsDir() {
+				err = differ.addBeneath(name)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, parentInfo := range parentInfos {
+		if _, deleted := inParent[parentInfo.Name()]; deleted {
+			differ.record(filepath.Join(rel, parentInfo.Name()), volume.ChangeDeleted)
+		}
+	}
+
+	return nil
+}
+
+func (differ *treeDiffer) addAll(rel string, info os.FileInfo) error {
+	differ.record(rel, volume.ChangeAdded)
+
+	if !info.IsDir() {
+		return nil
+	}
+
+	return differ.addBeneath(rel)
+}
+
+func (differ *treeDiffer) addBeneath(rel string) error {
+	infos, err := ioutil.ReadDir(filepath.Join(differ.childDir, rel))
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		err := differ.addAll(filepath.Join(rel, info.Name()), info)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (differ *treeDiffer) record(rel string, kind volume.ChangeKind) {
+	differ.changes = append(differ.changes, volume.Change{
+		Path: filepath.ToSlash(rel),
+		Kind: kind,
+	})
+}
+
+// sameMetadata reports whether two files look the same without reading
+// their contents. The modification time of a directory changes whenever
+// entries are added to or removed from it.
+func sameMetadata(a os.FileInfo, b os.FileInfo) bool {
+	if a.Mode() != b.Mode() || !a.ModTime().Equal(b.ModTime()) {
+		return false
+	}
+
+	if !a.IsDir() && a.Size() != b.Size() {
+		return false
+	}
+
+	return sameOwner(a, b)
+}
+
+// sortChanges orders changes by path, which puts a directory before its
+// contents.
+func sortChanges(changes []volume.Change) {
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+}
@@ -1,6 +1,7 @@
 package driver
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/concourse/baggageclaim/volume"
@@ -24,6 +25,103 @@ func (driver *NaiveDriver) CreateCopyOnWriteLayer(
 	return copy.Cp(false, parentVol.DataPath(), childVol.DataPath())
 }
 
+func (driver *NaiveDriver) SharesLayers() bool {
+	return false
+}
+
+func (driver *NaiveDriver) Usage(vol volume.FilesystemVolume) (volume.VolumeUsage, error) {
+	// copy-on-write layers are full copies, so nothing is ever shared
+	bytes, inodes, err := diskUsage(vol.DataPath())
+	if err != nil {
+		return volume.VolumeUsage{}, err
+	}
+
+	return volume.VolumeUsage{
+		ExclusiveBytes: bytes,
+		Inodes:         inodes,
+	}, nil
+}
+
+func (driver *NaiveDriver) SetQuota(volume.FilesystemVolume, uint64) error {
+	return volume.ErrQuotaNotSupported
+}
+
+func (driver *NaiveDriver) SetReadOnly(volume.FilesystemVolume) error {
+	// plain directories cannot be protected without changing the permissions
+	// of their contents, so only the API refuses writes
+	return nil
+}
+
+func (driver *NaiveDriver) RenameVolume(volume.FilesystemVolume, string) error {
+	// the data is kept within the volume's directory, which is moved with it
+	return nil
+}
+
+func (driver *NaiveDriver) Check(vol volume.FilesystemVolume) error {
+	info, err := os.Stat(vol.DataPath())
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", vol.DataPath())
+	}
+
+	return nil
+}
+
+func (driver *NaiveDriver) Repair(volume.FilesystemVolume) error {
+	return volume.ErrRepairNotSupported
+}
+
+// Diff compares the volume with its parent, as it is a full copy of it. Only
+// the parent's current contents are kept, so anything changed in the parent
+// since the volume was created is listed as though it were changed in the
+// volume; parents are expected to be left alone once they have children.
+func (driver *NaiveDriver) Diff(child volume.FilesystemVolume, parent volume.FilesystemLiveVolume) ([]volume.Change, error) {
+	return compareTrees(parent.DataPath(), child.DataPath())
+}
+
+// CreateSnapshot copies the volume's data, which must not be written to in
+// the meantime; see copySnapshot.
+func (driver *NaiveDriver) CreateSnapshot(vol volume.FilesystemLiveVolume, snapshot volume.FilesystemSnapshot) error {
+	return copySnapshot(vol.DataPath(), snapshot)
+}
+
+// RestoreSnapshot copies the snapshot alongside the volume's data before
+// swapping it in, so that a failed copy or swap, or a snapshot that has been
+// changed, leaves the volume as it was.
+func (driver *NaiveDriver) RestoreSnapshot(vol volume.FilesystemLiveVolume, snapshot volume.FilesystemSnapshot) error {
+	restorePath := restorePath(vol)
+	replacedPath := replacedPath(vol)
+
+	// left behind by a restore that failed part way
+	for _, path := range []string{restorePath, replacedPath} {
+		err := os.RemoveAll(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := copyFromSnapshot(snapshot, restorePath)
+	if err != nil {
+		os.RemoveAll(restorePath)
+		return err
+	}
+
+	err = swapIn(restorePath, vol.DataPath(), replacedPath)
+	if err != nil {
+		os.RemoveAll(restorePath)
+		return err
+	}
+
+	return os.RemoveAll(replacedPath)
+}
+
+func (driver *NaiveDriver) DestroySnapshot(snapshot volume.FilesystemSnapshot) error {
+	return os.RemoveAll(snapshot.DataPath())
+}
+
 func (driver *NaiveDriver) Recover(volume.Filesystem) error {
 	// nothing to do
 	return nil
@@ -0,0 +1,198 @@
+package driver_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/driver"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Naive", func() {
+	Describe("Diff", func() {
+		var tmpdir string
+		var fs volume.Filesystem
+
+		var parentVol volume.FilesystemLiveVolume
+		var childVol volume.FilesystemLiveVolume
+
+		BeforeEach(func() {
+			var err error
+			tmpdir, err = ioutil.TempDir("", "naive-test")
+			Expect(err).ToNot(HaveOccurred())
+
+			fs, err = volume.NewFilesystem(&driver.NaiveDriver{}, tmpdir)
+			Expect(err).ToNot(HaveOccurred())
+
+			parentInit, err := fs.NewVolume("parent-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			parentData := parentInit.DataPath()
+			Expect(os.MkdirAll(filepath.Join(parentData, "kept-dir"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(parentData, "doomed-dir", "nested-dir"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(parentData, "kept-dir", "kept-file"), []byte("kept"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(parentData, "kept-dir", "modified-file"), []byte("old"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(parentData, "kept-dir", "doomed-file"), []byte("doomed"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(parentData, "replaced-file"), []byte("replaced"), 0644)).To(Succeed())
+
+			// make sure that changes made to the child are seen as modifications
+			past := time.Now().Add(-time.Hour)
+			for _, path := range []string{"kept-dir/kept-file", "kept-dir/modified-file", "kept-dir"} {
+				Expect(os.Chtimes(filepath.Join(parentData, path), past, past)).To(Succeed())
+			}
+
+			parentVol, err = parentInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			childInit, err := parentVol.NewSubvolume("child-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			childVol, err = childInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(tmpdir)).To(Succeed())
+		})
+
+		It("lists nothing for an untouched child", func() {
+			Expect(childVol.Diff()).To(BeEmpty())
+		})
+
+		It("lists what was added, modified and deleted in the child", func() {
+			childData := childVol.DataPath()
+			Expect(ioutil.WriteFile(filepath.Join(childData, "kept-dir", "modified-file"), []byte("new"), 0644)).To(Succeed())
+			Expect(os.Remove(filepath.Join(childData, "kept-dir", "doomed-file"))).To(Succeed())
+			Expect(os.RemoveAll(filepath.Join(childData, "doomed-dir"))).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(childData, "new-dir", "nested-dir"), 0755)).To(Succeed())
+			Expect(os.Remove(filepath.Join(childData, "replaced-file"))).To(Succeed())
+			Expect(os.Mkdir(filepath.Join(childData, "replaced-file"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(childData, "replaced-file", "new-file"), []byte("new"), 0644)).To(Succeed())
+
+			Expect(childVol.Diff()).To(Equal([]volume.Change{
+				{Path: "doomed-dir", Kind: volume.ChangeDeleted},
+				{Path: "kept-dir", Kind: volume.ChangeModified},
+				{Path: "kept-dir/doomed-file", Kind: volume.ChangeDeleted},
+				{Path: "kept-dir/modified-file", Kind: volume.ChangeModified},
+				{Path: "new-dir", Kind: volume.ChangeAdded},
+				{Path: "new-dir/nested-dir", Kind: volume.ChangeAdded},
+				{Path: "replaced-file", Kind: volume.ChangeModified},
+				{Path: "replaced-file/new-file", Kind: volume.ChangeAdded},
+			}))
+		})
+
+		It("returns ErrVolumeHasNoParent for a volume without a parent", func() {
+			_, err := parentVol.Diff()
+			Expect(err).To(Equal(volume.ErrVolumeHasNoParent))
+		})
+	})
+
+	Describe("Snapshots", func() {
+		var tmpdir string
+		var vol volume.FilesystemLiveVolume
+
+		BeforeEach(func() {
+			var err error
+			tmpdir, err = ioutil.TempDir("", "naive-test")
+			Expect(err).ToNot(HaveOccurred())
+
+			fs, err := volume.NewFilesystem(&driver.NaiveDriver{}, tmpdir)
+			Expect(err).ToNot(HaveOccurred())
+
+			initVol, err := fs.NewVolume("some-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(initVol.DataPath(), "some-file"), []byte("before"), 0644)).To(Succeed())
+
+			vol, err = initVol.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(tmpdir)).To(Succeed())
+		})
+
+		It("restores the volume to how it was when the snapshot was taken", func() {
+			snapshot, err := vol.NewSnapshot("some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(vol.DataPath(), "some-file"), []byte("after"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(vol.DataPath(), "new-file"), []byte("new"), 0644)).To(Succeed())
+
+			Expect(vol.Restore(snapshot)).To(Succeed())
+
+			Expect(ioutil.ReadFile(filepath.Join(vol.DataPath(), "some-file"))).To(Equal([]byte("before")))
+			Expect(filepath.Join(vol.DataPath(), "new-file")).ToNot(BeAnExistingFile())
+
+			// the snapshot is left as it was, so it can be restored again
+			Expect(ioutil.ReadFile(filepath.Join(snapshot.DataPath(), "some-file"))).To(Equal([]byte("before")))
+
+			// neither the copy nor the replaced data is left alongside it
+			entries, err := ioutil.ReadDir(filepath.Dir(vol.DataPath()))
+			Expect(err).ToNot(HaveOccurred())
+			for _, entry := range entries {
+				Expect(entry.Name()).ToNot(Or(Equal("restoring"), Equal("replaced")))
+			}
+		})
+
+		It("refuses to restore a snapshot that has changed since it was taken", func() {
+			snapshot, err := vol.NewSnapshot("some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(vol.DataPath(), "some-file"), []byte("after"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(snapshot.DataPath(), "some-file"), []byte("tampered"), 0644)).To(Succeed())
+
+			Expect(vol.Restore(snapshot)).To(Equal(volume.ErrSnapshotCorrupted))
+
+			// the volume is left as it was
+			Expect(ioutil.ReadFile(filepath.Join(vol.DataPath(), "some-file"))).To(Equal([]byte("after")))
+			Expect(filepath.Join(filepath.Dir(vol.DataPath()), "restoring")).ToNot(BeADirectory())
+		})
+
+		It("lists the volume's snapshots", func() {
+			_, err := vol.NewSnapshot("some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			snapshots, err := vol.ListSnapshots()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshots).To(HaveLen(1))
+			Expect(snapshots[0].Handle()).To(Equal("some-snapshot"))
+
+			createdAt, err := snapshots[0].LoadCreatedAt()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(createdAt).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+
+		It("fails to take a snapshot with a handle that is already taken", func() {
+			_, err := vol.NewSnapshot("some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = vol.NewSnapshot("some-snapshot")
+			Expect(os.IsExist(err)).To(BeTrue())
+		})
+
+		It("destroys the snapshots along with the volume", func() {
+			snapshot, err := vol.NewSnapshot("some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(vol.Destroy()).To(Succeed())
+			Expect(snapshot.DataPath()).ToNot(BeADirectory())
+		})
+
+		It("can destroy a snapshot", func() {
+			snapshot, err := vol.NewSnapshot("some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(snapshot.Destroy()).To(Succeed())
+
+			_, found, err := vol.LookupSnapshot("some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+})
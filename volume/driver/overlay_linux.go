@@ -1,12 +1,17 @@
 package driver
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
 
+	"golang.org/x/sys/unix"
+
 	"github.com/concourse/baggageclaim/volume"
 	"github.com/concourse/baggageclaim/volume/copy"
 )
@@ -59,7 +64,10 @@ func (driver *OverlayDriver) DestroyVolume(vol volume.FilesystemVolume) error {
 	// when a path is already unmounted, and unmount is called
 	// on it, syscall.EINVAL is returned as an error
 	// ignore this error and continue to clean up
-	if err != nil && err != os.ErrInvalid {
+	//
+	// the path may also be missing entirely if the volume was orphaned
+	// before it was fully created
+	if err != nil && err != syscall.EINVAL && err != syscall.ENOENT {
 		return err
 	}
 
@@ -68,6 +76,13 @@ func (driver *OverlayDriver) DestroyVolume(vol volume.FilesystemVolume) error {
 		return err
 	}
 
+	// the layer's project id is reused once it is released, so its limit must
+	// not outlive it
+	err = clearProjectQuota(driver.layerDir(vol), driver.projectIDsDir())
+	if err != nil {
+		return err
+	}
+
 	err = os.RemoveAll(driver.layerDir(vol))
 	if err != nil {
 		return err
@@ -94,6 +109,254 @@ func (driver *OverlayDriver) CreateCopyOnWriteLayer(
 	return driver.overlayMount(child, rootParent)
 }
 
+// SharesLayers is false as only the root parent's layer is used as a lower
+// dir; any intermediate layers are copied into the child's upper dir.
+func (driver *OverlayDriver) SharesLayers() bool {
+	return false
+}
+
+func (driver *OverlayDriver) Usage(vol volume.FilesystemVolume) (volume.VolumeUsage, error) {
+	exclusiveBytes, exclusiveInodes, err := diskUsage(driver.layerDir(vol))
+	if err != nil {
+		return volume.VolumeUsage{}, err
+	}
+
+	usage := volume.VolumeUsage{
+		ExclusiveBytes: exclusiveBytes,
+		Inodes:         exclusiveInodes,
+	}
+
+	parent, hasParent, err := vol.Parent()
+	if err != nil {
+		return volume.VolumeUsage{}, err
+	}
+
+	if !hasParent {
+		return usage, nil
+	}
+
+	// intermediate layers are copied into the child's upper dir when it is
+	// created, so only the root parent's layer is actually shared
+	rootParent, err := driver.resolveRootParent(parent)
+	if err != nil {
+		return volume.VolumeUsage{}, err
+	}
+
+	sharedBytes, sharedInodes, err := diskUsage(driver.layerDir(rootParent))
+	if err != nil {
+		return volume.VolumeUsage{}, err
+	}
+
+	usage.SharedBytes = sharedBytes
+	usage.Inodes += sharedInodes
+
+	return usage, nil
+}
+
+// SetQuota applies a project quota to the volume's upper dir. Files already
+// present in the upper dir (e.g. copied from intermediate parents) are not
+// counted against it.
+func (driver *OverlayDriver) SetQuota(vol volume.FilesystemVolume, bytes uint64) error {
+	return setProjectQuota(driver.layerDir(vol), driver.projectIDsDir(), bytes)
+}
+
+// SetReadOnly remounts the volume read-only. Its layer can still be used as
+// the lower dir of copy-on-write children.
+func (driver *OverlayDriver) SetReadOnly(vol volume.FilesystemVolume) error {
+	return syscall.Mount("", vol.DataPath(), "", syscall.MS_REMOUNT|syscall.MS_BIND|syscall.MS_RDONLY, "")
+}
+
+// RenameVolume moves the volume's layer and work dirs, which are named after
+// its handle. Mounts follow the dirs they were made from, so the volume and
+// any children layered on it do not need to be remounted.
+func (driver *OverlayDriver) RenameVolume(vol volume.FilesystemVolume, handle string) error {
+	layerDir := filepath.Join(driver.OverlaysDir, handle)
+
+	err := os.Rename(driver.layerDir(vol), layerDir)
+	if err != nil {
+		return err
+	}
+
+	// volumes without a parent are bind mounted, so have no work dir
+	err = os.Rename(driver.workDir(vol), filepath.Join(driver.OverlaysDir, "work", handle))
+	if err != nil && !os.IsNotExist(err) {
+		os.Rename(layerDir, driver.layerDir(vol))
+		return err
+	}
+
+	return nil
+}
+
+func (driver *OverlayDriver) Check(vol volume.FilesystemVolume) error {
+	_, err := os.Stat(driver.layerDir(vol))
+	if err != nil {
+		return fmt.Errorf("missing layer: %w", err)
+	}
+
+	mounted, err := isMountPoint(vol.DataPath())
+	if err != nil {
+		return err
+	}
+
+	if !mounted {
+		return fmt.Errorf("%s is not mounted", vol.DataPath())
+	}
+
+	return nil
+}
+
+// Repair mounts the volume again if it is no longer mounted.
+func (driver *OverlayDriver) Repair(vol volume.FilesystemVolume) error {
+	mounted, err := isMountPoint(vol.DataPath())
+	if err != nil {
+		return err
+	}
+
+	if mounted {
+		return nil
+	}
+
+	err = driver.mount(vol)
+	if err != nil {
+		return err
+	}
+
+	return driver.protect(vol)
+}
+
+// mount mounts a volume whose layer has already been set up.
+func (driver *OverlayDriver) mount(vol volume.FilesystemVolume) error {
+	parent, hasParent, err := vol.Parent()
+	if err != nil {
+		return fmt.Errorf("get parent: %w", err)
+	}
+
+	if !hasParent {
+		return driver.bindMount(vol)
+	}
+
+	// intermediate parents' layers were already copied into the volume's
+	// layer when it was created, so don't copy them again
+	rootParent, err := driver.resolveRootParent(parent)
+	if err != nil {
+		return err
+	}
+
+	return driver.overlayMount(vol, rootParent)
+}
+
+// Diff lists what is in the volume's upper dir, as anything changed in an
+// overlay is copied up into it and deletions are recorded there as whiteouts.
+// The parent's current contents only tell additions from modifications,
+// unless the volume is layered on an intermediate parent, in which case it is
+// compared with the parent as a whole. Either way, changes made to the parent
+// since the volume was created are not told apart from the volume's own;
+// parents are expected to be left alone once they have children.
+func (driver *OverlayDriver) Diff(child volume.FilesystemVolume, parent volume.FilesystemLiveVolume) ([]volume.Change, error) {
+	_, hasGrandparent, err := parent.Parent()
+	if err != nil {
+		return nil, err
+	}
+
+	if hasGrandparent {
+		// the parent's layer was copied into the child's when it was created,
+		// so the child's layer alone does not tell them apart
+		return compareTrees(parent.DataPath(), child.DataPath())
+	}
+
+	differ := &layerDiffer{
+		upperDir: driver.layerDir(child),
+	}
+
+	err = differ.diffDir(".", parent.DataPath())
+	if err != nil {
+		return nil, err
+	}
+
+	sortChanges(differ.changes)
+
+	return differ.changes, nil
+}
+
+// CreateSnapshot copies the volume's upper dir, which holds everything that
+// is not shared with its root parent. The upper dir is copied while the
+// volume is mounted, so it must not be written to in the meantime; see
+// copySnapshot.
+func (driver *OverlayDriver) CreateSnapshot(vol volume.FilesystemLiveVolume, snapshot volume.FilesystemSnapshot) error {
+	return copySnapshot(driver.layerDir(vol), snapshot)
+}
+
+// RestoreSnapshot replaces the volume's upper dir with a copy of the frozen
+// one and mounts the volume again. The volume is unmounted before its upper
+// dir is touched, so ErrVolumeInUse is returned if anything has it open.
+func (driver *OverlayDriver) RestoreSnapshot(vol volume.FilesystemLiveVolume, snapshot volume.FilesystemSnapshot) error {
+	// copy the snapshot next to the layer so that it can be renamed into
+	// place, as the overlays dir may be on a different filesystem
+	restorePath, err := ioutil.TempDir(driver.OverlaysDir, ".restore-")
+	if err != nil {
+		return err
+	}
+
+	// carry over the layer's project id, and with it its quota, before
+	// anything is copied in so that everything inherits it
+	projectID, err := getProjectID(driver.layerDir(vol))
+	if err != nil {
+		os.RemoveAll(restorePath)
+		return err
+	}
+
+	if projectID != 0 {
+		err = setProjectID(restorePath, projectID)
+		if err != nil {
+			os.RemoveAll(restorePath)
+			return err
+		}
+	}
+
+	err = copyFromSnapshot(snapshot, restorePath)
+	if err != nil {
+		os.RemoveAll(restorePath)
+		return err
+	}
+
+	err = syscall.Unmount(vol.DataPath(), 0)
+	if err == syscall.EBUSY {
+		os.RemoveAll(restorePath)
+		return volume.ErrVolumeInUse
+	}
+
+	if err != nil && err != syscall.EINVAL {
+		os.RemoveAll(restorePath)
+		return err
+	}
+
+	replacedPath := restorePath + "-replaced"
+
+	err = swapIn(restorePath, driver.layerDir(vol), replacedPath)
+	if err != nil {
+		os.RemoveAll(restorePath)
+		driver.Repair(vol)
+		return err
+	}
+
+	// the work dir must be empty when mounting over a different upper dir
+	err = os.RemoveAll(driver.workDir(vol))
+	if err == nil {
+		err = driver.Repair(vol)
+	}
+
+	removeErr := os.RemoveAll(replacedPath)
+	if err == nil {
+		err = removeErr
+	}
+
+	return err
+}
+
+func (driver *OverlayDriver) DestroySnapshot(snapshot volume.FilesystemSnapshot) error {
+	return os.RemoveAll(snapshot.DataPath())
+}
+
 func (driver *OverlayDriver) Recover(fs volume.Filesystem) error {
 	vols, err := fs.ListVolumes()
 	if err != nil {
@@ -124,6 +387,11 @@ func (driver *OverlayDriver) Recover(fs volume.Filesystem) error {
 		if err != nil {
 			return fmt.Errorf("recover bind mount: %w", err)
 		}
+
+		err = driver.protect(vol)
+		if err != nil {
+			return fmt.Errorf("recover read-only mount: %w", err)
+		}
 	}
 
 	for _, cow := range cows {
@@ -136,11 +404,31 @@ func (driver *OverlayDriver) Recover(fs volume.Filesystem) error {
 		if err != nil {
 			return fmt.Errorf("recover overlay mount: %w", err)
 		}
+
+		err = driver.protect(cow.child)
+		if err != nil {
+			return fmt.Errorf("recover read-only mount: %w", err)
+		}
 	}
 
 	return nil
 }
 
+// protect remounts the volume read-only if it has been made read-only, as
+// mounts do not remember it.
+func (driver *OverlayDriver) protect(vol volume.FilesystemVolume) error {
+	readOnly, err := vol.LoadReadOnly()
+	if err != nil {
+		return err
+	}
+
+	if !readOnly {
+		return nil
+	}
+
+	return driver.SetReadOnly(vol)
+}
+
 func (driver *OverlayDriver) findRootParent(child volume.FilesystemVolume,
 	parent volume.FilesystemLiveVolume) (volume.FilesystemLiveVolume, error) {
 	rootParent := parent
@@ -157,21 +445,26 @@ func (driver *OverlayDriver) findRootParent(child volume.FilesystemVolume,
 			return nil, fmt.Errorf("copy parent data to child: %w", err)
 		}
 
-		rootParent = grandparent
+		return driver.resolveRootParent(grandparent)
+	}
 
-		// resolve to root volume
-		for {
-			grandparent, hasGrandparent, err := rootParent.Parent()
-			if err != nil {
-				return nil, err
-			}
+	return rootParent, nil
+}
 
-			if !hasGrandparent {
-				break
-			}
+func (driver *OverlayDriver) resolveRootParent(vol volume.FilesystemLiveVolume) (volume.FilesystemLiveVolume, error) {
+	rootParent := vol
 
-			rootParent = grandparent
+	for {
+		grandparent, hasGrandparent, err := rootParent.Parent()
+		if err != nil {
+			return nil, err
+		}
+
+		if !hasGrandparent {
+			break
 		}
+
+		rootParent = grandparent
 	}
 
 	return rootParent, nil
@@ -220,10 +513,173 @@ func (driver *OverlayDriver) overlayMount(child volume.FilesystemVolume, parent
 	return nil
 }
 
+// isMountPoint checks whether something is mounted at the given path by
+// scanning /proc/self/mountinfo.
+func isMountPoint(path string) (bool, error) {
+	mountinfo, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return false, err
+	}
+
+	defer mountinfo.Close()
+
+	// mount points have whitespace and backslashes escaped as octal
+	escaped := strings.NewReplacer(
+		`\`, `\134`,
+		" ", `\040`,
+		"\t", `\011`,
+		"\n", `\012`,
+	).Replace(path)
+
+	scanner := bufio.NewScanner(mountinfo)
+	for scanner.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 5 && fields[4] == escaped {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+type layerDiffer struct {
+	upperDir string
+
+	changes []volume.Change
+}
+
+// diffDir lists the changes recorded in a directory of the upper dir.
+// lowerDir is the directory it lies over, or empty if there is none.
+func (differ *layerDiffer) diffDir(rel string, lowerDir string) error {
+	upperDir := filepath.Join(differ.upperDir, rel)
+
+	infos, err := ioutil.ReadDir(upperDir)
+	if err != nil {
+		return err
+	}
+
+	inUpper := map[string]bool{}
+
+	for _, info := range infos {
+		name := filepath.Join(rel, info.Name())
+		inUpper[info.Name()] = true
+
+		if isWhiteout(info) {
+			differ.record(name, volume.ChangeDeleted)
+			continue
+		}
+
+		var lowerInfo os.FileInfo
+		var lower string
+		if lowerDir != "" {
+			lower = filepath.Join(lowerDir, info.Name())
+
+			lowerInfo, err = os.Lstat(lower)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		switch {
+		case lowerInfo == nil:
+			differ.record(name, volume.ChangeAdded)
+			lower = ""
+
+		case lowerInfo.IsDir() && info.IsDir():
+			// directories are copied up as-is when something beneath them
+			// changes, so they have only changed if their metadata has
+			if !sameMetadata(lowerInfo, info) {
+				differ.record(name, volume.ChangeModified)
+			}
+
+		default:
+			differ.record(name, volume.ChangeModified)
+			lower = ""
+		}
+
+		if !info.IsDir() {
+			continue
+		}
+
+		err := differ.diffDir(name, lower)
+		if err != nil {
+			return err
+		}
+	}
+
+	if lowerDir == "" {
+		return nil
+	}
+
+	opaque, err := isOpaque(upperDir)
+	if err != nil {
+		return err
+	}
+
+	if !opaque {
+		return nil
+	}
+
+	// an opaque directory hides everything in the directory beneath it
+	lowerInfos, err := ioutil.ReadDir(lowerDir)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range lowerInfos {
+		if !inUpper[info.Name()] {
+			differ.record(filepath.Join(rel, info.Name()), volume.ChangeDeleted)
+		}
+	}
+
+	return nil
+}
+
+func (differ *layerDiffer) record(rel string, kind volume.ChangeKind) {
+	differ.changes = append(differ.changes, volume.Change{
+		Path: filepath.ToSlash(rel),
+		Kind: kind,
+	})
+}
+
+// isWhiteout reports whether a file in an upper dir marks the deletion of
+// the file with the same name beneath it, which overlayfs records as a 0/0
+// character device.
+func isWhiteout(info os.FileInfo) bool {
+	if info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && stat.Rdev == 0
+}
+
+// isOpaque reports whether a directory in an upper dir hides the contents of
+// the directory beneath it, e.g. because it replaced one that was removed.
+func isOpaque(dir string) (bool, error) {
+	buf := make([]byte, 1)
+
+	n, err := unix.Lgetxattr(dir, "trusted.overlay.opaque", buf)
+	if err == unix.ENODATA || err == unix.ENOTSUP {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1 && buf[0] == 'y', nil
+}
+
 func (driver *OverlayDriver) layerDir(vol volume.FilesystemVolume) string {
 	return filepath.Join(driver.OverlaysDir, vol.Handle())
 }
 
+func (driver *OverlayDriver) projectIDsDir() string {
+	return filepath.Join(driver.OverlaysDir, ".project-ids")
+}
+
 func (driver *OverlayDriver) workDir(vol volume.FilesystemVolume) string {
 	return filepath.Join(driver.OverlaysDir, "work", vol.Handle())
 }
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"syscall"
 
 	"github.com/concourse/baggageclaim/volume"
 	"github.com/concourse/baggageclaim/volume/driver"
@@ -100,5 +101,307 @@ var _ = Describe("Overlay", func() {
 				nest = childLive
 			}
 		})
+
+		It("remounts volumes that have been unmounted on repair", func() {
+			parentInit, err := fs.NewVolume("parent-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(parentInit.DataPath(), "some-file"), []byte("some-content"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(parentInit.StorePrivileged(false)).To(Succeed())
+
+			parentLive, err := parentInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := parentLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			childInit, err := parentLive.NewSubvolume("child-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(childInit.StorePrivileged(false)).To(Succeed())
+
+			childLive, err := childInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := childLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			Expect(childLive.Check()).To(BeEmpty())
+
+			err = syscall.Unmount(childLive.DataPath(), 0)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(childLive.Check()).To(ConsistOf(ContainSubstring("is not mounted")))
+
+			err = childLive.Repair()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(childLive.Check()).To(BeEmpty())
+
+			content, err := ioutil.ReadFile(filepath.Join(childLive.DataPath(), "some-file"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+		})
+
+		It("diffs a child against its parent using its layer", func() {
+			parentInit, err := fs.NewVolume("parent-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(parentInit.DataPath(), "some-dir", "nested-dir"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(parentInit.DataPath(), "some-dir", "doomed-file"), []byte("doomed"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(parentInit.DataPath(), "modified-file"), []byte("old"), 0644)).To(Succeed())
+
+			parentLive, err := parentInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := parentLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			childInit, err := parentLive.NewSubvolume("child-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			childLive, err := childInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := childLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			Expect(childLive.Diff()).To(BeEmpty())
+
+			childData := childLive.DataPath()
+			Expect(os.Remove(filepath.Join(childData, "some-dir", "doomed-file"))).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(childData, "modified-file"), []byte("new"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(childData, "some-dir", "nested-dir", "new-file"), []byte("new"), 0644)).To(Succeed())
+
+			Expect(childLive.Diff()).To(Equal([]volume.Change{
+				{Path: "modified-file", Kind: volume.ChangeModified},
+				{Path: "some-dir", Kind: volume.ChangeModified},
+				{Path: "some-dir/doomed-file", Kind: volume.ChangeDeleted},
+				{Path: "some-dir/nested-dir", Kind: volume.ChangeModified},
+				{Path: "some-dir/nested-dir/new-file", Kind: volume.ChangeAdded},
+			}))
+		})
+
+		It("mounts read-only volumes read-only, even once remounted", func() {
+			parentInit, err := fs.NewVolume("parent-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(parentInit.DataPath(), "some-file"), []byte("sealed"), 0644)).To(Succeed())
+
+			parentLive, err := parentInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := parentLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			Expect(parentLive.SetReadOnly()).To(Succeed())
+
+			err = ioutil.WriteFile(filepath.Join(parentLive.DataPath(), "some-file"), []byte("poisoned"), 0644)
+			Expect(err).To(HaveOccurred())
+
+			Expect(syscall.Unmount(parentLive.DataPath(), 0)).To(Succeed())
+			Expect(parentLive.Repair()).To(Succeed())
+
+			err = ioutil.WriteFile(filepath.Join(parentLive.DataPath(), "new-file"), []byte("poisoned"), 0644)
+			Expect(err).To(HaveOccurred())
+
+			childInit, err := parentLive.NewSubvolume("child-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			childLive, err := childInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := childLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			// children of a read-only volume are still writable
+			Expect(ioutil.WriteFile(filepath.Join(childLive.DataPath(), "some-file"), []byte("changed"), 0644)).To(Succeed())
+			Expect(ioutil.ReadFile(filepath.Join(parentLive.DataPath(), "some-file"))).To(Equal([]byte("sealed")))
+		})
+
+		It("renames a volume without disturbing its children", func() {
+			parentInit, err := fs.NewVolume("parent-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(parentInit.DataPath(), "parent-file"), []byte("parent"), 0644)).To(Succeed())
+			Expect(parentInit.StorePrivileged(false)).To(Succeed())
+
+			parentLive, err := parentInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			childInit, err := parentLive.NewSubvolume("child-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(childInit.StorePrivileged(false)).To(Succeed())
+
+			childLive, err := childInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := childLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			Expect(ioutil.WriteFile(filepath.Join(childLive.DataPath(), "child-file"), []byte("child"), 0644)).To(Succeed())
+
+			renamed, err := parentLive.Rename("renamed-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := renamed.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			Expect(renamed.Handle()).To(Equal("renamed-vol"))
+			Expect(renamed.Check()).To(BeEmpty())
+			Expect(ioutil.ReadFile(filepath.Join(renamed.DataPath(), "parent-file"))).To(Equal([]byte("parent")))
+
+			_, found, err := fs.LookupVolume("parent-vol")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			Expect(childLive.Reparent(renamed)).To(Succeed())
+
+			parent, found, err := childLive.Parent()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(parent.Handle()).To(Equal("renamed-vol"))
+
+			Expect(childLive.Check()).To(BeEmpty())
+			Expect(childLive.Diff()).To(Equal([]volume.Change{
+				{Path: "child-file", Kind: volume.ChangeAdded},
+			}))
+
+			// the child can still be remounted on top of its renamed parent
+			Expect(syscall.Unmount(childLive.DataPath(), 0)).To(Succeed())
+			Expect(childLive.Repair()).To(Succeed())
+
+			Expect(ioutil.ReadFile(filepath.Join(childLive.DataPath(), "parent-file"))).To(Equal([]byte("parent")))
+			Expect(ioutil.ReadFile(filepath.Join(childLive.DataPath(), "child-file"))).To(Equal([]byte("child")))
+		})
+
+		It("refuses to rename a volume to a handle that is taken", func() {
+			someInit, err := fs.NewVolume("some-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(someInit.StorePrivileged(false)).To(Succeed())
+
+			someLive, err := someInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := someLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			otherInit, err := fs.NewVolume("other-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(otherInit.DataPath(), "other-file"), []byte("other"), 0644)).To(Succeed())
+
+			otherLive, err := otherInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := otherLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			_, err = someLive.Rename("other-vol")
+			Expect(os.IsExist(err)).To(BeTrue())
+
+			Expect(someLive.Check()).To(BeEmpty())
+			Expect(ioutil.ReadFile(filepath.Join(otherLive.DataPath(), "other-file"))).To(Equal([]byte("other")))
+		})
+
+		It("restores a child to a snapshot of its layer", func() {
+			parentInit, err := fs.NewVolume("parent-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(parentInit.DataPath(), "parent-file"), []byte("parent"), 0644)).To(Succeed())
+
+			parentLive, err := parentInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := parentLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			childInit, err := parentLive.NewSubvolume("child-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			childLive, err := childInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := childLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			childData := childLive.DataPath()
+			Expect(ioutil.WriteFile(filepath.Join(childData, "child-file"), []byte("before"), 0644)).To(Succeed())
+
+			snapshot, err := childLive.NewSnapshot("some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(childData, "child-file"), []byte("after"), 0644)).To(Succeed())
+			Expect(os.Remove(filepath.Join(childData, "parent-file"))).To(Succeed())
+
+			Expect(childLive.Restore(snapshot)).To(Succeed())
+
+			Expect(ioutil.ReadFile(filepath.Join(childData, "child-file"))).To(Equal([]byte("before")))
+			Expect(ioutil.ReadFile(filepath.Join(childData, "parent-file"))).To(Equal([]byte("parent")))
+			Expect(childLive.Diff()).To(Equal([]volume.Change{
+				{Path: "child-file", Kind: volume.ChangeAdded},
+			}))
+		})
+
+		It("refuses to restore a volume that is in use", func() {
+			someInit, err := fs.NewVolume("some-vol")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(someInit.StoreProperties(volume.Properties{})).To(Succeed())
+			Expect(someInit.StorePrivileged(false)).To(Succeed())
+
+			someLive, err := someInit.Initialize()
+			Expect(err).ToNot(HaveOccurred())
+
+			defer func() {
+				err := someLive.Destroy()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			someFile := filepath.Join(someLive.DataPath(), "some-file")
+			Expect(ioutil.WriteFile(someFile, []byte("before"), 0644)).To(Succeed())
+
+			snapshot, err := someLive.NewSnapshot("some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ioutil.WriteFile(someFile, []byte("after"), 0644)).To(Succeed())
+
+			file, err := os.Open(someFile)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(someLive.Restore(snapshot)).To(Equal(volume.ErrVolumeInUse))
+			Expect(file.Close()).To(Succeed())
+
+			Expect(ioutil.ReadFile(someFile)).To(Equal([]byte("after")))
+			Expect(someLive.Check()).To(BeEmpty())
+		})
 	})
 })
@@ -0,0 +1,16 @@
+package driver
+
+import (
+	"os"
+	"syscall"
+)
+
+func sameOwner(a os.FileInfo, b os.FileInfo) bool {
+	aStat, aOk := a.Sys().(*syscall.Stat_t)
+	bStat, bOk := b.Sys().(*syscall.Stat_t)
+	if !aOk || !bOk {
+		return true
+	}
+
+	return aStat.Uid == bStat.Uid && aStat.Gid == bStat.Gid
+}
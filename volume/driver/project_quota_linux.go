@@ -0,0 +1,301 @@
+package driver
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// These are not exposed by the syscall package, so they are taken from
+// linux/fs.h and linux/quota.h.
+const (
+	fsIocFsGetXattr = 0x801c581f
+	fsIocFsSetXattr = 0x401c5820
+
+	fsXflagProjInherit = 0x00000200
+
+	qSetQuota = 0x800008
+	prjQuota  = 2
+
+	qifBLimits   = 1
+	qifDQBlkSize = 1024
+)
+
+// fsxattr mirrors struct fsxattr from linux/fs.h.
+type fsxattr struct {
+	xflags     uint32
+	extsize    uint32
+	nextents   uint32
+	projid     uint32
+	cowextsize uint32
+	pad        [8]byte
+}
+
+// ifDqblk mirrors struct if_dqblk from linux/quota.h.
+type ifDqblk struct {
+	bhardlimit uint64
+	bsoftlimit uint64
+	curspace   uint64
+	ihardlimit uint64
+	isoftlimit uint64
+	curinodes  uint64
+	btime      uint64
+	itime      uint64
+	valid      uint32
+}
+
+// firstProjectID leaves the low project IDs to anything else managing
+// project quotas on the same filesystem.
+const firstProjectID = 1 << 16
+
+// setProjectQuota limits the space used by everything created beneath dir to
+// the given number of bytes using a filesystem project quota. The filesystem
+// containing dir must support project quotas (xfs, or ext4 with the 'project'
+// feature) and be mounted with them enabled.
+//
+// Project IDs are allocated by claiming a file named after them in idsDir, so
+// that no two directories share one. A directory keeps its project ID if its
+// quota is changed.
+func setProjectQuota(dir string, idsDir string, bytes uint64) error {
+	var stat syscall.Stat_t
+	err := syscall.Stat(dir, &stat)
+	if err != nil {
+		return err
+	}
+
+	projectID, err := getProjectID(dir)
+	if err != nil {
+		return fmt.Errorf("get project id: %w", err)
+	}
+
+	if projectID == 0 {
+		projectID, err = allocateProjectID(idsDir)
+		if err != nil {
+			return fmt.Errorf("allocate project id: %w", err)
+		}
+
+		err = setProjectID(dir, projectID)
+		if err != nil {
+			releaseProjectID(idsDir, projectID)
+			return fmt.Errorf("set project id: %w", err)
+		}
+	}
+
+	device, err := blockDevice(stat.Dev)
+	if err != nil {
+		return fmt.Errorf("find block device: %w", err)
+	}
+
+	err = setQuotaLimit(device, projectID, bytes)
+	if err != nil {
+		return fmt.Errorf("set project quota on %s: %w", device, err)
+	}
+
+	return nil
+}
+
+// clearProjectQuota lifts the limit set on dir by setProjectQuota and releases
+// its project ID, so that the ID can be given to another directory without
+// bringing the limit with it.
+func clearProjectQuota(dir string, idsDir string) error {
+	var stat syscall.Stat_t
+	err := syscall.Stat(dir, &stat)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	projectID, err := getProjectID(dir)
+	if err != nil {
+		return fmt.Errorf("get project id: %w", err)
+	}
+
+	if projectID == 0 {
+		return nil
+	}
+
+	device, err := blockDevice(stat.Dev)
+	if err != nil {
+		return fmt.Errorf("find block device: %w", err)
+	}
+
+	// a limit of 0 is no limit at all; quotas having since been turned off
+	// leaves nothing to clear
+	err = setQuotaLimit(device, projectID, 0)
+	if err != nil && err != syscall.ESRCH {
+		return fmt.Errorf("clear project quota on %s: %w", device, err)
+	}
+
+	return releaseProjectID(idsDir, projectID)
+}
+
+// allocateProjectID claims the project ID after the highest one claimed so
+// far, moving on if another allocation beats it to it.
+func allocateProjectID(idsDir string) (uint32, error) {
+	err := os.MkdirAll(idsDir, 0755)
+	if err != nil {
+		return 0, err
+	}
+
+	claimed, err := ioutil.ReadDir(idsDir)
+	if err != nil {
+		return 0, err
+	}
+
+	next := uint64(firstProjectID)
+	for _, claim := range claimed {
+		id, err := strconv.ParseUint(claim.Name(), 10, 32)
+		if err == nil && id >= next {
+			next = id + 1
+		}
+	}
+
+	for ; next <= math.MaxUint32; next++ {
+		claim, err := os.OpenFile(filepath.Join(idsDir, strconv.FormatUint(next, 10)), os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		claim.Close()
+
+		return uint32(next), nil
+	}
+
+	return 0, errors.New("project ids exhausted")
+}
+
+func releaseProjectID(idsDir string, projectID uint32) error {
+	err := os.Remove(filepath.Join(idsDir, strconv.FormatUint(uint64(projectID), 10)))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// getProjectID returns 0 for directories on filesystems without project ids.
+func getProjectID(dir string) (uint32, error) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	defer d.Close()
+
+	var attr fsxattr
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, d.Fd(), fsIocFsGetXattr, uintptr(unsafe.Pointer(&attr)))
+	if errno == syscall.ENOTTY || errno == syscall.EOPNOTSUPP || errno == syscall.EINVAL {
+		return 0, nil
+	}
+
+	if errno != 0 {
+		return 0, errno
+	}
+
+	return attr.projid, nil
+}
+
+func setProjectID(dir string, projectID uint32) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	defer d.Close()
+
+	var attr fsxattr
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, d.Fd(), fsIocFsGetXattr, uintptr(unsafe.Pointer(&attr)))
+	if errno != 0 {
+		return errno
+	}
+
+	attr.projid = projectID
+	attr.xflags |= fsXflagProjInherit
+
+	_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, d.Fd(), fsIocFsSetXattr, uintptr(unsafe.Pointer(&attr)))
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func setQuotaLimit(device string, projectID uint32, bytes uint64) error {
+	devicePtr, err := syscall.BytePtrFromString(device)
+	if err != nil {
+		return err
+	}
+
+	limit := ifDqblk{
+		bhardlimit: (bytes + qifDQBlkSize - 1) / qifDQBlkSize,
+		valid:      qifBLimits,
+	}
+
+	// soft limits are pointless for a volume; only enforce the hard limit
+	limit.bsoftlimit = limit.bhardlimit
+
+	cmd := qSetQuota<<8 | prjQuota
+
+	_, _, errno := syscall.Syscall6(
+		syscall.SYS_QUOTACTL,
+		uintptr(cmd),
+		uintptr(unsafe.Pointer(devicePtr)),
+		uintptr(projectID),
+		uintptr(unsafe.Pointer(&limit)),
+		0, 0,
+	)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// blockDevice finds the mount source for the given device number by scanning
+// /proc/self/mountinfo.
+func blockDevice(dev uint64) (string, error) {
+	mountinfo, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return "", err
+	}
+
+	defer mountinfo.Close()
+
+	majorMinor := fmt.Sprintf("%d:%d", (dev>>8)&0xfff|(dev>>32)&^0xfff, dev&0xff|(dev>>12)&^0xff)
+
+	scanner := bufio.NewScanner(mountinfo)
+	for scanner.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[2] != majorMinor {
+			continue
+		}
+
+		for i, field := range fields {
+			if field == "-" && i+2 < len(fields) {
+				return fields[i+2], nil
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("no mount found for device %s", majorMinor)
+}
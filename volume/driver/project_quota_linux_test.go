@@ -0,0 +1,64 @@
+package driver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Project IDs", func() {
+	var idsDir string
+
+	BeforeEach(func() {
+		tempDir, err := ioutil.TempDir("", "project-ids")
+		Expect(err).ToNot(HaveOccurred())
+
+		idsDir = filepath.Join(tempDir, "ids")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(filepath.Dir(idsDir))).To(Succeed())
+	})
+
+	It("allocates a distinct id every time", func() {
+		first, err := allocateProjectID(idsDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first).To(Equal(uint32(firstProjectID)))
+
+		second, err := allocateProjectID(idsDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).To(Equal(uint32(firstProjectID + 1)))
+	})
+
+	It("remembers the ids it allocated", func() {
+		Expect(os.MkdirAll(idsDir, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(idsDir, "70000"), nil, 0644)).To(Succeed())
+
+		id, err := allocateProjectID(idsDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(Equal(uint32(70001)))
+	})
+
+	It("ignores anything that is not an id", func() {
+		Expect(os.MkdirAll(idsDir, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(idsDir, "bogus"), nil, 0644)).To(Succeed())
+
+		id, err := allocateProjectID(idsDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(Equal(uint32(firstProjectID)))
+	})
+
+	It("releases ids", func() {
+		id, err := allocateProjectID(idsDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(releaseProjectID(idsDir, id)).To(Succeed())
+		Expect(ioutil.ReadDir(idsDir)).To(BeEmpty())
+
+		// releasing twice is harmless
+		Expect(releaseProjectID(idsDir, id)).To(Succeed())
+	})
+})
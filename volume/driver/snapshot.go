@@ -0,0 +1,97 @@
+package driver
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/copy"
+)
+
+// copySnapshot copies the data at path to the snapshot, for drivers whose
+// snapshots are plain copies. These cannot be made read-only without changing
+// the modes that they hold, so the copy's digest is recorded, and checked by
+// copyFromSnapshot before the snapshot is restored.
+//
+// Nothing may write to path while the copy is taken, or it would not be of a
+// single point in time. The copy is compared with path once it is taken, and
+// ErrVolumeInUse is returned if they differ.
+func copySnapshot(path string, snapshot volume.FilesystemSnapshot) error {
+	err := copy.Cp(false, path, snapshot.DataPath())
+	if err != nil {
+		return err
+	}
+
+	digest, err := volume.TreeDigest(snapshot.DataPath())
+	if err != nil {
+		return err
+	}
+
+	current, err := volume.TreeDigest(path)
+	if err != nil {
+		return err
+	}
+
+	if current != digest {
+		return volume.ErrVolumeInUse
+	}
+
+	return snapshot.StoreDigest(digest)
+}
+
+// copyFromSnapshot copies a snapshot taken by copySnapshot to path, returning
+// ErrSnapshotCorrupted if what was copied is not what was snapshotted.
+func copyFromSnapshot(snapshot volume.FilesystemSnapshot, path string) error {
+	recorded, err := snapshot.LoadDigest()
+	if err != nil {
+		return err
+	}
+
+	err = copy.Cp(false, snapshot.DataPath(), path)
+	if err != nil {
+		return err
+	}
+
+	// the copy is checked rather than the snapshot, so that nothing can change
+	// in between
+	digest, err := volume.TreeDigest(path)
+	if err != nil {
+		return err
+	}
+
+	if digest != recorded {
+		return volume.ErrSnapshotCorrupted
+	}
+
+	return nil
+}
+
+// restorePath is where a snapshot is copied to before it replaces a volume's
+// data. It is alongside the data so that the copy can be renamed into place.
+func restorePath(vol volume.FilesystemVolume) string {
+	return filepath.Join(filepath.Dir(vol.DataPath()), "restoring")
+}
+
+// replacedPath is where a volume's data is moved to while a restored copy is
+// moved into its place.
+func replacedPath(vol volume.FilesystemVolume) string {
+	return filepath.Join(filepath.Dir(vol.DataPath()), "replaced")
+}
+
+// swapIn moves the restored data at from to path, first moving whatever is at
+// path to replaced, so that it can be put back if the move fails. What was
+// replaced is left for the caller to remove.
+func swapIn(from string, path string, replaced string) error {
+	err := os.Rename(path, replaced)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(from, path)
+	if err != nil {
+		_ = os.Rename(replaced, path)
+		return err
+	}
+
+	return nil
+}
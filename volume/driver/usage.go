@@ -0,0 +1,44 @@
+package driver
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// diskUsage walks the given directory tree and totals the space allocated to
+// it and the number of entries within it, including the directory itself.
+// Hard-linked files are only counted once, and entries that disappear during
+// the walk, as they do in volumes that are in use, are skipped.
+func diskUsage(path string) (uint64, uint64, error) {
+	var bytes, inodes uint64
+
+	seen := map[fileID]bool{}
+
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p != path {
+				return nil
+			}
+
+			return err
+		}
+
+		if id, linked := hardLinkID(info); linked {
+			if seen[id] {
+				return nil
+			}
+
+			seen[id] = true
+		}
+
+		inodes++
+		bytes += allocatedBytes(info)
+
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return bytes, inodes, nil
+}
@@ -0,0 +1,31 @@
+package driver
+
+import (
+	"os"
+	"syscall"
+)
+
+type fileID struct {
+	dev uint64
+	ino uint64
+}
+
+func allocatedBytes(info os.FileInfo) uint64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return uint64(info.Size())
+	}
+
+	return uint64(stat.Blocks) * 512
+}
+
+// hardLinkID identifies files with more than one link, so that they are only
+// counted once.
+func hardLinkID(info os.FileInfo) (fileID, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || info.IsDir() || stat.Nlink <= 1 {
+		return fileID{}, false
+	}
+
+	return fileID{dev: uint64(stat.Dev), ino: stat.Ino}, true
+}
@@ -0,0 +1,62 @@
+package driver_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/driver"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Usage", func() {
+	var tmpdir string
+	var vol volume.FilesystemLiveVolume
+
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = ioutil.TempDir("", "usage-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		fs, err := volume.NewFilesystem(&driver.NaiveDriver{}, tmpdir)
+		Expect(err).ToNot(HaveOccurred())
+
+		initVol, err := fs.NewVolume("some-vol")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(ioutil.WriteFile(filepath.Join(initVol.DataPath(), "some-file"), []byte("some-content"), 0644)).To(Succeed())
+
+		vol, err = initVol.Initialize()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpdir)).To(Succeed())
+	})
+
+	It("counts the blocks allocated to files rather than their apparent size", func() {
+		sparse, err := os.Create(filepath.Join(vol.DataPath(), "sparse-file"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sparse.Truncate(100 * 1024 * 1024)).To(Succeed())
+		Expect(sparse.Close()).To(Succeed())
+
+		usage, err := vol.Usage()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(usage.ExclusiveBytes).To(BeNumerically("<", 1024*1024))
+		Expect(usage.Inodes).To(Equal(uint64(3)))
+	})
+
+	It("counts hard-linked files once", func() {
+		before, err := vol.Usage()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.Link(filepath.Join(vol.DataPath(), "some-file"), filepath.Join(vol.DataPath(), "linked-file"))).To(Succeed())
+
+		after, err := vol.Usage()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(after).To(Equal(before))
+	})
+})
@@ -0,0 +1,19 @@
+// +build !linux
+
+package driver
+
+import "os"
+
+type fileID struct{}
+
+func allocatedBytes(info os.FileInfo) uint64 {
+	if info.Mode().IsRegular() {
+		return uint64(info.Size())
+	}
+
+	return 0
+}
+
+func hardLinkID(info os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
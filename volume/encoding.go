@@ -0,0 +1,173 @@
+package volume
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	IdentityEncoding string = "identity"
+	GzipEncoding     string = "gzip"
+	ZstdEncoding     string = "zstd"
+	Lz4Encoding      string = "lz4"
+	XzEncoding       string = "xz"
+)
+
+// Encoding compresses and decompresses the tar streams of volume contents.
+// Encodings are registered under the name used for them in the
+// Content-Encoding and Accept-Encoding headers.
+type Encoding interface {
+	NewReader(io.Reader) (io.ReadCloser, error)
+	NewWriter(io.Writer) (io.WriteCloser, error)
+}
+
+var (
+	encodings = map[string]Encoding{
+		IdentityEncoding: identityEncoding{},
+		GzipEncoding:     gzipEncoding{},
+		ZstdEncoding:     zstdEncoding{},
+		Lz4Encoding:      lz4Encoding{},
+		XzEncoding:       xzEncoding{},
+	}
+
+	encodingsL sync.RWMutex
+)
+
+// RegisterEncoding makes an encoding available for streaming volumes in and
+// out, replacing any encoding already registered under the same name.
+func RegisterEncoding(name string, encoding Encoding) {
+	encodingsL.Lock()
+	encodings[name] = encoding
+	encodingsL.Unlock()
+}
+
+func LookupEncoding(name string) (Encoding, bool) {
+	encodingsL.RLock()
+	defer encodingsL.RUnlock()
+
+	encoding, found := encodings[name]
+	return encoding, found
+}
+
+// Encodings returns the names of every registered encoding, sorted.
+func Encodings() []string {
+	encodingsL.RLock()
+	defer encodingsL.RUnlock()
+
+	names := make([]string, 0, len(encodings))
+	for name := range encodings {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+type identityEncoding struct{}
+
+func (identityEncoding) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return ioutil.NopCloser(r), nil
+}
+
+func (identityEncoding) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+type gzipEncoding struct{}
+
+func (gzipEncoding) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+func (gzipEncoding) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+type zstdEncoding struct{}
+
+func (zstdEncoding) NewReader(r io.Reader) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return zstdReadCloser{decoder}, nil
+}
+
+func (zstdEncoding) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w)
+}
+
+// zstdReadCloser adapts zstd.Decoder, whose Close releases its resources
+// but does not return an error.
+type zstdReadCloser struct {
+	*zstd.Decoder
+}
+
+func (r zstdReadCloser) Close() error {
+	r.Decoder.Close()
+	return nil
+}
+
+type lz4Encoding struct{}
+
+func (lz4Encoding) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return ioutil.NopCloser(&stickyEOFReader{Reader: lz4.NewReader(r)}), nil
+}
+
+func (lz4Encoding) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return lz4.NewWriter(w), nil
+}
+
+type xzEncoding struct{}
+
+func (xzEncoding) NewReader(r io.Reader) (io.ReadCloser, error) {
+	reader, err := xz.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(reader), nil
+}
+
+func (xzEncoding) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return xz.NewWriter(w)
+}
+
+// stickyEOFReader keeps returning io.EOF once it has been reached. The lz4
+// reader fails with an error if it is read from again after the end of the
+// stream, which is exactly what happens when draining it after extraction.
+type stickyEOFReader struct {
+	io.Reader
+
+	eof bool
+}
+
+func (r *stickyEOFReader) Read(p []byte) (int, error) {
+	if r.eof {
+		return 0, io.EOF
+	}
+
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		r.eof = true
+	}
+
+	return n, err
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,83 @@
+package volume_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"github.com/concourse/baggageclaim/volume"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Encodings", func() {
+	It("includes the built-in encodings", func() {
+		for _, name := range []string{
+			volume.IdentityEncoding,
+			volume.GzipEncoding,
+			volume.ZstdEncoding,
+			volume.Lz4Encoding,
+			volume.XzEncoding,
+		} {
+			Expect(volume.Encodings()).To(ContainElement(name))
+		}
+	})
+
+	for _, name := range []string{
+		volume.IdentityEncoding,
+		volume.GzipEncoding,
+		volume.ZstdEncoding,
+		volume.Lz4Encoding,
+		volume.XzEncoding,
+	} {
+		name := name
+
+		It("round-trips data with "+name, func() {
+			encoding, found := volume.LookupEncoding(name)
+			Expect(found).To(BeTrue())
+
+			buf := new(bytes.Buffer)
+
+			writer, err := encoding.NewWriter(buf)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = writer.Write(bytes.Repeat([]byte("some-data"), 1000))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer.Close()).To(Succeed())
+
+			reader, err := encoding.NewReader(buf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ioutil.ReadAll(reader)).To(Equal(bytes.Repeat([]byte("some-data"), 1000)))
+
+			// reading on past the end is how streams are drained after extraction
+			n, err := reader.Read(make([]byte, 1))
+			Expect(n).To(BeZero())
+			Expect(err).To(Equal(io.EOF))
+
+			Expect(reader.Close()).To(Succeed())
+		})
+	}
+
+	It("can have more encodings registered", func() {
+		volume.RegisterEncoding("some-encoding", fakeEncoding{})
+
+		encoding, found := volume.LookupEncoding("some-encoding")
+		Expect(found).To(BeTrue())
+		Expect(encoding).To(Equal(fakeEncoding{}))
+	})
+
+	It("does not find unregistered encodings", func() {
+		_, found := volume.LookupEncoding("bogus")
+		Expect(found).To(BeFalse())
+	})
+})
+
+type fakeEncoding struct{}
+
+func (fakeEncoding) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return ioutil.NopCloser(r), nil
+}
+
+func (fakeEncoding) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return nil, nil
+}
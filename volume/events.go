@@ -0,0 +1,108 @@
+package volume
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventCreated           EventType = "created"
+	EventInitialized       EventType = "initialized"
+	EventPropertySet       EventType = "property-set"
+	EventPrivilegedChanged EventType = "privileged-changed"
+	EventDestroyed         EventType = "destroyed"
+	EventStreamedIn        EventType = "streamed-in"
+	EventQuarantined       EventType = "quarantined"
+	EventRestored          EventType = "restored"
+	EventMadeReadOnly      EventType = "made-read-only"
+	EventRenamed           EventType = "renamed"
+)
+
+// Event describes a change to a volume's lifecycle or state.
+type Event struct {
+	Type   EventType `json:"type"`
+	Handle string    `json:"handle"`
+	Time   time.Time `json:"time"`
+
+	// set for property-set events
+	Property string `json:"property,omitempty"`
+	Value    string `json:"value,omitempty"`
+
+	// set for privileged-changed events
+	Privileged *bool `json:"privileged,omitempty"`
+
+	// set for streamed-in events
+	Path string `json:"path,omitempty"`
+
+	// set for restored events
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// set for renamed events, whose handle is the volume's new handle
+	PreviousHandle string `json:"previous_handle,omitempty"`
+}
+
+// subscriberBufferSize is how many events may be pending for a subscriber
+// before it is considered too slow and dropped.
+const subscriberBufferSize = 128
+
+type eventHub struct {
+	subscribers map[chan Event]struct{}
+
+	sync.Mutex
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		subscribers: map[chan Event]struct{}{},
+	}
+}
+
+// Subscribe returns a channel of events published from now on. The channel is
+// closed once ctx is done, or early if the subscriber falls too far behind, so
+// that a closed channel always means events may have been missed.
+func (hub *eventHub) Subscribe(ctx context.Context) <-chan Event {
+	events := make(chan Event, subscriberBufferSize)
+
+	hub.Lock()
+	hub.subscribers[events] = struct{}{}
+	hub.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		hub.unsubscribe(events)
+	}()
+
+	return events
+}
+
+func (hub *eventHub) Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	hub.Lock()
+	defer hub.Unlock()
+
+	for subscriber := range hub.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			// never block volume operations on a slow reader
+			delete(hub.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+func (hub *eventHub) unsubscribe(events chan Event) {
+	hub.Lock()
+	defer hub.Unlock()
+
+	if _, found := hub.subscribers[events]; found {
+		delete(hub.subscribers, events)
+		close(events)
+	}
+}
@@ -0,0 +1,98 @@
+package volume
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+	"github.com/concourse/baggageclaim/volume/fsroot"
+)
+
+var ErrNotRegularFile = errors.New("not a regular file")
+
+// OpenFile opens a regular file within a volume for reading. Whatever the
+// volume, the file is served to API clients rather than to the container that
+// wrote it, so symlinks can never lead to files on the host.
+//
+// Unprivileged containers see their volumes as part of their own root
+// filesystem, so symlinks in unprivileged volumes are resolved as though the
+// volume were the root of the filesystem. Privileged containers have the run
+// of the host, so an absolute symlink or a ".." above the volume in a
+// privileged volume may well mean the host's path, which is not served; such
+// paths are refused with ErrPathOutsideVolume rather than resolved within the
+// volume to a file they did not mean.
+func (repo *repository) OpenFile(ctx context.Context, handle string, path string) (*os.File, error) {
+	logger := lagerctx.FromContext(ctx).Session("open-file", lager.Data{
+		"volume":   handle,
+		"sub-path": path,
+	})
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return nil, err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return nil, ErrVolumeDoesNotExist
+	}
+
+	privileged, err := volume.LoadPrivileged()
+	if err != nil {
+		logger.Error("failed-to-check-if-volume-is-privileged", err)
+		return nil, err
+	}
+
+	rootDir, err := fsroot.Open(volume.DataPath())
+	if err != nil {
+		logger.Error("failed-to-open-volume", err)
+		return nil, err
+	}
+
+	defer rootDir.Close()
+
+	parent, name, err := rootDir.Resolve(path, fsroot.ResolveOptions{
+		Chroot: !privileged,
+		Follow: true,
+	})
+	if err != nil {
+		if errors.Is(err, fsroot.ErrOutsideRoot) {
+			logger.Info("path-outside-volume")
+			return nil, ErrPathOutsideVolume
+		}
+
+		if !os.IsNotExist(err) {
+			logger.Error("failed-to-resolve-path", err)
+		}
+
+		return nil, err
+	}
+
+	defer parent.Close()
+
+	file, err := parent.Open(name)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Error("failed-to-open-file", err)
+		}
+
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		logger.Error("failed-to-stat-file", err)
+		return nil, err
+	}
+
+	if !info.Mode().IsRegular() {
+		_ = file.Close()
+		return nil, ErrNotRegularFile
+	}
+
+	return file, nil
+}
@@ -0,0 +1,17 @@
+// +build !linux
+
+package volume
+
+import (
+	"os"
+)
+
+// ownership and device numbers are not reported, as with archives created
+// on these platforms
+func fileOwner(info os.FileInfo) (int, int) {
+	return 0, 0
+}
+
+func fileDevice(info os.FileInfo) uint64 {
+	return 0
+}
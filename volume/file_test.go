@@ -0,0 +1,188 @@
+package volume_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/uidgid/uidgidfakes"
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+)
+
+var _ = Describe("OpenFile", func() {
+	var (
+		fakeFilesystem *volumefakes.FakeFilesystem
+		fakeVolume     *volumefakes.FakeFilesystemLiveVolume
+
+		repository volume.Repository
+
+		tmpdir   string
+		hostFile string
+		dataDir  string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = ioutil.TempDir("", "open-file")
+		Expect(err).ToNot(HaveOccurred())
+
+		hostFile = filepath.Join(tmpdir, "host-secret")
+		err = ioutil.WriteFile(hostFile, []byte("host-contents"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		dataDir = filepath.Join(tmpdir, "volume")
+		Expect(os.MkdirAll(filepath.Join(dataDir, "some-dir"), 0755)).To(Succeed())
+
+		err = ioutil.WriteFile(filepath.Join(dataDir, "some-dir", "some-file"), []byte("some-contents"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(dataDir, "host-secret"), []byte("volume-contents"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.Symlink("/some-dir/some-file", filepath.Join(dataDir, "absolute-link"))).To(Succeed())
+		Expect(os.Symlink("some-dir", filepath.Join(dataDir, "dir-link"))).To(Succeed())
+		Expect(os.Symlink(hostFile, filepath.Join(dataDir, "host-link"))).To(Succeed())
+		Expect(os.Symlink("../host-secret", filepath.Join(dataDir, "escaping-link"))).To(Succeed())
+		Expect(os.Symlink("loop", filepath.Join(dataDir, "loop"))).To(Succeed())
+
+		fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+		fakeVolume.DataPathReturns(dataDir)
+
+		fakeFilesystem = new(volumefakes.FakeFilesystem)
+		fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+
+		repository = volume.NewRepository(
+			fakeFilesystem,
+			new(volumefakes.FakeLockManager),
+			new(uidgidfakes.FakeNamespacer),
+			new(uidgidfakes.FakeNamespacer),
+			nil,
+			0,
+		)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpdir)
+	})
+
+	read := func(path string) (string, error) {
+		file, err := repository.OpenFile(context.Background(), "some-handle", path)
+		if err != nil {
+			return "", err
+		}
+
+		defer file.Close()
+
+		contents, err := ioutil.ReadAll(file)
+		return string(contents), err
+	}
+
+	Context("when the volume is unprivileged", func() {
+		BeforeEach(func() {
+			fakeVolume.LoadPrivilegedReturns(false, nil)
+		})
+
+		It("opens files in the volume", func() {
+			Expect(read("some-dir/some-file")).To(Equal("some-contents"))
+			Expect(read("/some-dir/./some-file")).To(Equal("some-contents"))
+		})
+
+		It("resolves symlinks within the volume", func() {
+			Expect(read("absolute-link")).To(Equal("some-contents"))
+			Expect(read("dir-link/some-file")).To(Equal("some-contents"))
+		})
+
+		It("never leads outside of the volume", func() {
+			Expect(read("escaping-link")).To(Equal("volume-contents"))
+			Expect(read("../host-secret")).To(Equal("volume-contents"))
+
+			// the host's path does not exist within the volume
+			_, err := read("host-link")
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("gives up on symlink loops", func() {
+			_, err := read("loop")
+			Expect(errors.Is(err, syscall.ELOOP)).To(BeTrue())
+		})
+
+		It("returns a not-exist error for missing files", func() {
+			_, err := read("some-dir/bogus")
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("rejects directories", func() {
+			_, err := read("some-dir")
+			Expect(err).To(Equal(volume.ErrNotRegularFile))
+
+			_, err = read("dir-link")
+			Expect(err).To(Equal(volume.ErrNotRegularFile))
+
+			_, err = read("")
+			Expect(err).To(Equal(volume.ErrNotRegularFile))
+		})
+
+		It("rejects fifos without blocking on them", func() {
+			if runtime.GOOS != "linux" {
+				Skip("fifos are only opened without blocking on linux")
+			}
+
+			Expect(syscall.Mkfifo(filepath.Join(dataDir, "some-fifo"), 0644)).To(Succeed())
+
+			_, err := read("some-fifo")
+			Expect(err).To(Equal(volume.ErrNotRegularFile))
+		})
+	})
+
+	Context("when the volume is privileged", func() {
+		BeforeEach(func() {
+			fakeVolume.LoadPrivilegedReturns(true, nil)
+		})
+
+		It("opens files in the volume", func() {
+			Expect(read("some-dir/some-file")).To(Equal("some-contents"))
+			Expect(read("/some-dir/./some-file")).To(Equal("some-contents"))
+		})
+
+		It("resolves relative symlinks within the volume", func() {
+			Expect(read("dir-link/some-file")).To(Equal("some-contents"))
+		})
+
+		It("refuses paths that may mean the host's", func() {
+			for _, path := range []string{"../host-secret", "escaping-link", "host-link", "absolute-link"} {
+				_, err := read(path)
+				Expect(err).To(Equal(volume.ErrPathOutsideVolume), path)
+			}
+		})
+
+		It("rejects fifos without blocking on them", func() {
+			if runtime.GOOS != "linux" {
+				Skip("fifos are only opened without blocking on linux")
+			}
+
+			Expect(syscall.Mkfifo(filepath.Join(dataDir, "some-fifo"), 0644)).To(Succeed())
+
+			_, err := read("some-fifo")
+			Expect(err).To(Equal(volume.ErrNotRegularFile))
+		})
+	})
+
+	Context("when the volume does not exist", func() {
+		BeforeEach(func() {
+			fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+		})
+
+		It("returns ErrVolumeDoesNotExist", func() {
+			_, err := read("some-dir/some-file")
+			Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+		})
+	})
+})
@@ -1,9 +1,12 @@
 package volume
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 )
 
 //go:generate counterfeiter . Filesystem
@@ -12,6 +15,30 @@ type Filesystem interface {
 	NewVolume(string) (FilesystemInitVolume, error)
 	LookupVolume(string) (FilesystemLiveVolume, bool, error)
 	ListVolumes() ([]FilesystemLiveVolume, error)
+
+	// ListOrphans returns the volumes left behind in the init and dead
+	// directories that no in-flight operation is responsible for, e.g.
+	// because the process crashed while creating or destroying them.
+	ListOrphans() ([]FilesystemVolume, error)
+
+	// LookupQuarantinedVolume and ListQuarantinedVolumes find the volumes set
+	// aside by Quarantine, which may only be inspected or destroyed.
+	LookupQuarantinedVolume(string) (FilesystemVolume, bool, error)
+	ListQuarantinedVolumes() ([]FilesystemVolume, error)
+
+	// SharesLayers reports whether the driver's copy-on-write layers share
+	// the data of every volume they descend from; see Driver.
+	SharesLayers() bool
+
+	// TempDir is where scratch files, e.g. spooled image layers, are kept
+	// alongside the volumes. Anything left there is removed when the
+	// filesystem is next set up.
+	TempDir() string
+
+	// VolumesWithDigest returns the live volumes whose recorded digest is the
+	// given one. It looks them up in an index rather than reading every
+	// volume's metadata.
+	VolumesWithDigest(string) ([]FilesystemLiveVolume, error)
 }
 
 //go:generate counterfeiter . FilesystemVolume
@@ -31,8 +58,34 @@ type FilesystemVolume interface {
 	LoadPrivileged() (bool, error)
 	StorePrivileged(bool) error
 
+	LoadExpiresAt() (time.Time, error)
+	StoreExpiresAt(time.Time) error
+
+	// LoadCreatedAt returns the zero time for volumes created before their
+	// creation time was recorded.
+	LoadCreatedAt() (time.Time, error)
+
+	LoadReadOnly() (bool, error)
+
+	// LoadDigest returns the digest recorded by StoreDigest, or "" if there
+	// is none.
+	LoadDigest() (string, error)
+
 	Parent() (FilesystemLiveVolume, bool, error)
 
+	// LoadLayer returns the digest of the image layer the volume was created
+	// to hold by OCIStrategy, or "" if it was not created as one.
+	LoadLayer() (string, error)
+
+	Usage() (VolumeUsage, error)
+
+	// SetQuota asks the driver to limit the volume's size, and records the
+	// limit for LoadQuota.
+	SetQuota(uint64) error
+
+	// LoadQuota returns the volume's quota, or 0 if it has none.
+	LoadQuota() (uint64, error)
+
 	Destroy() error
 }
 
@@ -41,6 +94,10 @@ type FilesystemVolume interface {
 type FilesystemInitVolume interface {
 	FilesystemVolume
 
+	// StoreLayer records that the volume holds the image layer with the
+	// given digest, and exists only for the volumes layered on it.
+	StoreLayer(string) error
+
 	Initialize() (FilesystemLiveVolume, error)
 }
 
@@ -50,26 +107,110 @@ type FilesystemLiveVolume interface {
 	FilesystemVolume
 
 	NewSubvolume(handle string) (FilesystemInitVolume, error)
+
+	// UploadsPath is where the partial state of resumable stream-ins is kept,
+	// alongside the volume's other metadata.
+	UploadsPath() string
+
+	// Diff lists the changes made to the volume since it was created as a
+	// copy-on-write layer of its parent. ErrVolumeHasNoParent is returned if
+	// it has no parent.
+	Diff() ([]Change, error)
+
+	// Check returns a description of every problem found with the volume's
+	// metadata, parent link, and driver state.
+	Check() []string
+
+	// Repair asks the driver to restore its state for the volume, e.g. by
+	// remounting it.
+	Repair() error
+
+	// Quarantine moves the volume out of the live directory, leaving it on
+	// disk for inspection but no longer accessible via the API. It keeps its
+	// handle, and with it its driver state, so the handle cannot be reused
+	// until the quarantined volume is destroyed.
+	Quarantine() error
+
+	// NewSnapshot takes a read-only, point-in-time copy of the volume's data,
+	// kept alongside the volume until it is destroyed.
+	NewSnapshot(handle string) (FilesystemSnapshot, error)
+	LookupSnapshot(handle string) (FilesystemSnapshot, bool, error)
+	ListSnapshots() ([]FilesystemSnapshot, error)
+
+	// Restore replaces the volume's data with a writable copy of the
+	// snapshot's.
+	Restore(FilesystemSnapshot) error
+
+	// SetReadOnly records that the volume's data may no longer change and
+	// asks the driver to enforce it. A read-only volume cannot be made
+	// writable again.
+	SetReadOnly() error
+
+	// StoreDigest records the digest of the volume's data, or forgets it if
+	// it is "". It is kept apart from the properties so that it cannot be set
+	// by clients, and should be forgotten before the data is changed.
+	StoreDigest(string) error
+
+	// Rename moves the volume to the given handle, returning it under its new
+	// handle. An error satisfying os.IsExist is returned if the handle is
+	// taken. Children are left linked to the old handle; see Reparent.
+	Rename(handle string) (FilesystemLiveVolume, error)
+
+	// Reparent replaces the volume's link to its parent with one to the given
+	// volume, e.g. once the parent has been renamed.
+	Reparent(parent FilesystemLiveVolume) error
+}
+
+//go:generate counterfeiter . FilesystemSnapshot
+
+// FilesystemSnapshot represents a snapshot of a live volume's data.
+type FilesystemSnapshot interface {
+	Handle() string
+
+	DataPath() string
+
+	LoadCreatedAt() (time.Time, error)
+
+	// LoadDigest returns the digest recorded for the snapshot's data by
+	// drivers that cannot protect it from writes, or "" if none was.
+	LoadDigest() (string, error)
+	StoreDigest(string) error
+
+	Destroy() error
 }
 
 const (
-	initDirname = "init" // volumes being initialized
-	liveDirname = "live" // volumes accessible via API
-	deadDirname = "dead" // volumes being torn down
+	initDirname       = "init"       // volumes being initialized
+	liveDirname       = "live"       // volumes accessible via API
+	deadDirname       = "dead"       // volumes being torn down
+	quarantineDirname = "quarantine" // corrupted volumes set aside
+	tmpDirname        = "tmp"        // scratch files
+
+	snapshotsDirname = "snapshots" // within a volume's dir
 )
 
 type filesystem struct {
 	driver Driver
 
-	initDir string
-	liveDir string
-	deadDir string
+	initDir       string
+	liveDir       string
+	deadDir       string
+	quarantineDir string
+	tmpDir        string
+
+	// init and dead volume dirs that an operation is currently working on
+	inFlight  map[string]bool
+	inFlightL sync.Mutex
+
+	digests *digestIndex
 }
 
 func NewFilesystem(driver Driver, parentDir string) (Filesystem, error) {
 	initDir := filepath.Join(parentDir, initDirname)
 	liveDir := filepath.Join(parentDir, liveDirname)
 	deadDir := filepath.Join(parentDir, deadDirname)
+	quarantineDir := filepath.Join(parentDir, quarantineDirname)
+	tmpDir := filepath.Join(parentDir, tmpDirname)
 
 	err := os.MkdirAll(initDir, 0755)
 	if err != nil {
@@ -86,15 +227,106 @@ func NewFilesystem(driver Driver, parentDir string) (Filesystem, error) {
 		return nil, err
 	}
 
+	err = os.MkdirAll(quarantineDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	// scratch files are only ever in use by the process that made them
+	err = os.RemoveAll(tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(tmpDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
 	return &filesystem{
 		driver: driver,
 
-		initDir: initDir,
-		liveDir: liveDir,
-		deadDir: deadDir,
+		initDir:       initDir,
+		liveDir:       liveDir,
+		deadDir:       deadDir,
+		quarantineDir: quarantineDir,
+		tmpDir:        tmpDir,
+
+		inFlight: map[string]bool{},
+
+		digests: newDigestIndex(),
 	}, nil
 }
 
+// CountVolumes returns the number of volumes in each lifecycle state under
+// the given volumes directory, keyed by state name.
+func CountVolumes(parentDir string) (map[string]int, error) {
+	counts := map[string]int{}
+
+	for _, state := range []string{initDirname, liveDirname, deadDirname, quarantineDirname} {
+		entries, err := ioutil.ReadDir(filepath.Join(parentDir, state))
+		if err != nil {
+			return nil, err
+		}
+
+		counts[state] = len(entries)
+	}
+
+	return counts, nil
+}
+
+func (fs *filesystem) SharesLayers() bool {
+	return fs.driver.SharesLayers()
+}
+
+func (fs *filesystem) TempDir() string {
+	return fs.tmpDir
+}
+
+func (fs *filesystem) VolumesWithDigest(digest string) ([]FilesystemLiveVolume, error) {
+	handles, err := fs.digests.lookup(digest, fs.loadDigests)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := []FilesystemLiveVolume{}
+	for _, handle := range handles {
+		volume, found, err := fs.LookupVolume(handle)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			volumes = append(volumes, volume)
+		}
+	}
+
+	return volumes, nil
+}
+
+// loadDigests reads the digests recorded for the live volumes, skipping
+// those that go away or are corrupted.
+func (fs *filesystem) loadDigests() (map[string]string, error) {
+	volumes, err := fs.ListVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	digests := map[string]string{}
+	for _, volume := range volumes {
+		digest, err := volume.LoadDigest()
+		if err != nil {
+			continue
+		}
+
+		if digest != "" {
+			digests[volume.Handle()] = digest
+		}
+	}
+
+	return digests, nil
+}
+
 func (fs *filesystem) NewVolume(handle string) (FilesystemInitVolume, error) {
 	volume, err := fs.initRawVolume(handle)
 	if err != nil {
@@ -160,11 +392,167 @@ func (fs *filesystem) ListVolumes() ([]FilesystemLiveVolume, error) {
 	return response, nil
 }
 
+func (fs *filesystem) LookupQuarantinedVolume(handle string) (FilesystemVolume, bool, error) {
+	volumePath := fs.quarantineVolumePath(handle)
+
+	info, err := os.Stat(volumePath)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !info.IsDir() {
+		return nil, false, nil
+	}
+
+	return &quarantinedVolume{
+		baseVolume: baseVolume{
+			fs: fs,
+
+			handle: handle,
+			dir:    volumePath,
+		},
+	}, true, nil
+}
+
+func (fs *filesystem) ListQuarantinedVolumes() ([]FilesystemVolume, error) {
+	quarantineDirs, err := ioutil.ReadDir(fs.quarantineDir)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]FilesystemVolume, 0, len(quarantineDirs))
+
+	for _, quarantineDir := range quarantineDirs {
+		handle := quarantineDir.Name()
+
+		response = append(response, &quarantinedVolume{
+			baseVolume: baseVolume{
+				fs: fs,
+
+				handle: handle,
+				dir:    fs.quarantineVolumePath(handle),
+			},
+		})
+	}
+
+	return response, nil
+}
+
+func (fs *filesystem) ListOrphans() ([]FilesystemVolume, error) {
+	orphans := []FilesystemVolume{}
+
+	initDirs, err := ioutil.ReadDir(fs.initDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, initDir := range initDirs {
+		handle := initDir.Name()
+
+		volumePath := fs.initVolumePath(handle)
+		if !fs.isOrphan(volumePath) {
+			continue
+		}
+
+		orphans = append(orphans, &initVolume{
+			baseVolume: baseVolume{
+				fs: fs,
+
+				handle: handle,
+				dir:    volumePath,
+			},
+		})
+	}
+
+	deadDirs, err := ioutil.ReadDir(fs.deadDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, deadDir := range deadDirs {
+		handle := deadDir.Name()
+
+		volumePath := fs.deadVolumePath(handle)
+		if !fs.isOrphan(volumePath) {
+			continue
+		}
+
+		orphans = append(orphans, &deadVolume{
+			baseVolume: baseVolume{
+				fs: fs,
+
+				handle: handle,
+				dir:    volumePath,
+			},
+		})
+	}
+
+	return orphans, nil
+}
+
+// isOrphan checks that nothing is working on the volume dir and that it has
+// not been cleaned up since it was listed.
+func (fs *filesystem) isOrphan(dir string) bool {
+	fs.inFlightL.Lock()
+	inFlight := fs.inFlight[dir]
+	fs.inFlightL.Unlock()
+
+	if inFlight {
+		return false
+	}
+
+	_, err := os.Lstat(dir)
+	return err == nil
+}
+
+// claim marks the volume dir as being worked on so that it is not mistaken
+// for an orphan. It returns false if the dir has already been claimed.
+func (fs *filesystem) claim(dir string) bool {
+	fs.inFlightL.Lock()
+	defer fs.inFlightL.Unlock()
+
+	if fs.inFlight[dir] {
+		return false
+	}
+
+	fs.inFlight[dir] = true
+
+	return true
+}
+
+func (fs *filesystem) release(dir string) {
+	fs.inFlightL.Lock()
+	delete(fs.inFlight, dir)
+	fs.inFlightL.Unlock()
+}
+
 func (fs *filesystem) initRawVolume(handle string) (*initVolume, error) {
 	volumePath := fs.initVolumePath(handle)
 
-	err := os.Mkdir(volumePath, 0755)
+	if !fs.claim(volumePath) {
+		return nil, &os.PathError{Op: "mkdir", Path: volumePath, Err: os.ErrExist}
+	}
+
+	// the driver's state for a quarantined volume is still kept under its
+	// handle
+	quarantined, err := fs.isQuarantined(handle)
 	if err != nil {
+		fs.release(volumePath)
+		return nil, err
+	}
+
+	if quarantined {
+		fs.release(volumePath)
+		return nil, &os.PathError{Op: "mkdir", Path: volumePath, Err: os.ErrExist}
+	}
+
+	err = os.Mkdir(volumePath, 0755)
+	if err != nil {
+		fs.release(volumePath)
 		return nil, err
 	}
 
@@ -179,6 +567,13 @@ func (fs *filesystem) initRawVolume(handle string) (*initVolume, error) {
 
 	err = volume.StoreProperties(Properties{})
 	if err != nil {
+		fs.release(volumePath)
+		return nil, err
+	}
+
+	err = (&Metadata{volumePath}).StoreCreatedAt(time.Now())
+	if err != nil {
+		fs.release(volumePath)
 		return nil, err
 	}
 
@@ -197,6 +592,19 @@ func (fs *filesystem) deadVolumePath(handle string) string {
 	return filepath.Join(fs.deadDir, handle)
 }
 
+func (fs *filesystem) quarantineVolumePath(handle string) string {
+	return filepath.Join(fs.quarantineDir, handle)
+}
+
+func (fs *filesystem) isQuarantined(handle string) (bool, error) {
+	_, err := os.Lstat(fs.quarantineVolumePath(handle))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
 type baseVolume struct {
 	fs *filesystem
 
@@ -228,6 +636,30 @@ func (base *baseVolume) StorePrivileged(isPrivileged bool) error {
 	return (&Metadata{base.dir}).StorePrivileged(isPrivileged)
 }
 
+func (base *baseVolume) LoadExpiresAt() (time.Time, error) {
+	return (&Metadata{base.dir}).ExpiresAt()
+}
+
+func (base *baseVolume) StoreExpiresAt(expiresAt time.Time) error {
+	return (&Metadata{base.dir}).StoreExpiresAt(expiresAt)
+}
+
+func (base *baseVolume) LoadCreatedAt() (time.Time, error) {
+	return (&Metadata{base.dir}).CreatedAt()
+}
+
+func (base *baseVolume) LoadReadOnly() (bool, error) {
+	return (&Metadata{base.dir}).IsReadOnly()
+}
+
+func (base *baseVolume) LoadDigest() (string, error) {
+	return (&Metadata{base.dir}).Digest()
+}
+
+func (base *baseVolume) LoadLayer() (string, error) {
+	return (&Metadata{base.dir}).Layer()
+}
+
 func (base *baseVolume) Parent() (FilesystemLiveVolume, bool, error) {
 	parentDir, err := filepath.EvalSymlinks(base.parentLink())
 	if os.IsNotExist(err) {
@@ -248,14 +680,44 @@ func (base *baseVolume) Parent() (FilesystemLiveVolume, bool, error) {
 	}, true, nil
 }
 
+func (base *baseVolume) Usage() (VolumeUsage, error) {
+	return base.fs.driver.Usage(base)
+}
+
+func (base *baseVolume) SetQuota(bytes uint64) error {
+	err := base.fs.driver.SetQuota(base, bytes)
+	if err != nil {
+		return err
+	}
+
+	return (&Metadata{base.dir}).StoreQuota(bytes)
+}
+
+func (base *baseVolume) LoadQuota() (uint64, error) {
+	return (&Metadata{base.dir}).Quota()
+}
+
 func (base *baseVolume) Destroy() error {
 	deadDir := base.fs.deadVolumePath(base.handle)
 
+	if !base.fs.claim(deadDir) {
+		return &os.LinkError{Op: "rename", Old: base.dir, New: deadDir, Err: os.ErrExist}
+	}
+
+	defer base.fs.release(deadDir)
+
 	err := os.Rename(base.dir, deadDir)
+
+	// if the rename failed, an init volume is left to be collected as an
+	// orphan
+	base.fs.release(base.dir)
+
 	if err != nil {
 		return err
 	}
 
+	base.fs.digests.record(base.handle, "")
+
 	deadVol := &deadVolume{
 		baseVolume: baseVolume{
 			fs: base.fs,
@@ -268,7 +730,61 @@ func (base *baseVolume) Destroy() error {
 	return deadVol.Destroy()
 }
 
+func (base *baseVolume) LookupSnapshot(handle string) (FilesystemSnapshot, bool, error) {
+	snapshotDir := filepath.Join(base.snapshotsDir(), handle)
+
+	info, err := os.Stat(snapshotDir)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !info.IsDir() {
+		return nil, false, nil
+	}
+
+	return &snapshot{
+		fs: base.fs,
+
+		handle: handle,
+		dir:    snapshotDir,
+	}, true, nil
+}
+
+func (base *baseVolume) ListSnapshots() ([]FilesystemSnapshot, error) {
+	snapshotDirs, err := ioutil.ReadDir(base.snapshotsDir())
+	if os.IsNotExist(err) {
+		return []FilesystemSnapshot{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]FilesystemSnapshot, 0, len(snapshotDirs))
+	for _, snapshotDir := range snapshotDirs {
+		handle := snapshotDir.Name()
+
+		snapshots = append(snapshots, &snapshot{
+			fs: base.fs,
+
+			handle: handle,
+			dir:    filepath.Join(base.snapshotsDir(), handle),
+		})
+	}
+
+	return snapshots, nil
+}
+
+func (base *baseVolume) snapshotsDir() string {
+	return filepath.Join(base.dir, snapshotsDirname)
+}
+
 func (base *baseVolume) cleanup() error {
+	defer base.fs.release(base.dir)
 	return os.RemoveAll(base.dir)
 }
 
@@ -280,6 +796,10 @@ type initVolume struct {
 	baseVolume
 }
 
+func (vol *initVolume) StoreLayer(digest string) error {
+	return (&Metadata{vol.dir}).StoreLayer(digest)
+}
+
 func (vol *initVolume) Initialize() (FilesystemLiveVolume, error) {
 	liveDir := vol.fs.liveVolumePath(vol.handle)
 
@@ -288,6 +808,8 @@ func (vol *initVolume) Initialize() (FilesystemLiveVolume, error) {
 		return nil, err
 	}
 
+	vol.fs.release(vol.dir)
+
 	return &liveVolume{
 		baseVolume: baseVolume{
 			fs: vol.fs,
@@ -323,15 +845,284 @@ func (vol *liveVolume) NewSubvolume(handle string) (FilesystemInitVolume, error)
 	return child, nil
 }
 
+func (vol *liveVolume) UploadsPath() string {
+	return filepath.Join(vol.dir, uploadsDirname)
+}
+
+func (vol *liveVolume) Diff() ([]Change, error) {
+	parent, found, err := vol.Parent()
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, ErrVolumeHasNoParent
+	}
+
+	return vol.fs.driver.Diff(vol, parent)
+}
+
+func (vol *liveVolume) Check() []string {
+	problems := (&Metadata{vol.dir}).Verify()
+
+	err := vol.checkParent()
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("broken parent link: %s", err))
+	}
+
+	err = vol.fs.driver.Check(vol)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	return problems
+}
+
+func (vol *liveVolume) checkParent() error {
+	_, err := os.Lstat(vol.parentLink())
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	parentDir, err := filepath.EvalSymlinks(vol.parentLink())
+	if err != nil {
+		return err
+	}
+
+	liveDir, err := filepath.EvalSymlinks(vol.fs.liveDir)
+	if err != nil {
+		return err
+	}
+
+	if filepath.Dir(parentDir) != liveDir {
+		return fmt.Errorf("%s is not a live volume", parentDir)
+	}
+
+	return nil
+}
+
+func (vol *liveVolume) NewSnapshot(handle string) (FilesystemSnapshot, error) {
+	err := os.MkdirAll(vol.snapshotsDir(), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotDir := filepath.Join(vol.snapshotsDir(), handle)
+
+	err = os.Mkdir(snapshotDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &snapshot{
+		fs: vol.fs,
+
+		handle: handle,
+		dir:    snapshotDir,
+	}
+
+	err = (&Metadata{snapshotDir}).StoreCreatedAt(time.Now())
+	if err != nil {
+		os.RemoveAll(snapshotDir)
+		return nil, err
+	}
+
+	err = vol.fs.driver.CreateSnapshot(vol, snapshot)
+	if err != nil {
+		snapshot.Destroy()
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func (vol *liveVolume) Restore(snapshot FilesystemSnapshot) error {
+	return vol.fs.driver.RestoreSnapshot(vol, snapshot)
+}
+
+func (vol *liveVolume) StoreDigest(digest string) error {
+	err := (&Metadata{vol.dir}).StoreDigest(digest)
+	if err != nil {
+		return err
+	}
+
+	vol.fs.digests.record(vol.handle, digest)
+
+	return nil
+}
+
+func (vol *liveVolume) SetReadOnly() error {
+	err := (&Metadata{vol.dir}).StoreReadOnly(true)
+	if err != nil {
+		return err
+	}
+
+	return vol.fs.driver.SetReadOnly(vol)
+}
+
+func (vol *liveVolume) Rename(handle string) (FilesystemLiveVolume, error) {
+	liveDir := vol.fs.liveVolumePath(handle)
+
+	// keep a volume from being created with the handle in the meantime
+	initDir := vol.fs.initVolumePath(handle)
+	if !vol.fs.claim(initDir) {
+		return nil, &os.LinkError{Op: "rename", Old: vol.dir, New: liveDir, Err: os.ErrExist}
+	}
+
+	defer vol.fs.release(initDir)
+
+	_, err := os.Lstat(liveDir)
+	if err == nil {
+		return nil, &os.LinkError{Op: "rename", Old: vol.dir, New: liveDir, Err: os.ErrExist}
+	}
+
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	quarantined, err := vol.fs.isQuarantined(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if quarantined {
+		return nil, &os.LinkError{Op: "rename", Old: vol.dir, New: liveDir, Err: os.ErrExist}
+	}
+
+	err = vol.fs.driver.RenameVolume(vol, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Rename(vol.dir, liveDir)
+	if err != nil {
+		// the volume is still where it was, so move the driver's state back
+		moved := &liveVolume{
+			baseVolume: baseVolume{
+				fs: vol.fs,
+
+				handle: handle,
+				dir:    vol.dir,
+			},
+		}
+
+		vol.fs.driver.RenameVolume(moved, vol.handle)
+
+		return nil, err
+	}
+
+	vol.fs.digests.move(vol.handle, handle)
+
+	return &liveVolume{
+		baseVolume: baseVolume{
+			fs: vol.fs,
+
+			handle: handle,
+			dir:    liveDir,
+		},
+	}, nil
+}
+
+func (vol *liveVolume) Reparent(parent FilesystemLiveVolume) error {
+	// link alongside the old link and swap it in, so that the volume is never
+	// left without a parent
+	newLink := vol.parentLink() + ".new"
+
+	err := os.Remove(newLink)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	err = os.Symlink(vol.fs.liveVolumePath(parent.Handle()), newLink)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(newLink, vol.parentLink())
+}
+
+func (vol *liveVolume) Repair() error {
+	return vol.fs.driver.Repair(vol)
+}
+
+func (vol *liveVolume) Quarantine() error {
+	err := os.Rename(vol.dir, vol.fs.quarantineVolumePath(vol.handle))
+	if err != nil {
+		return err
+	}
+
+	vol.fs.digests.record(vol.handle, "")
+
+	return nil
+}
+
+// quarantinedVolume is destroyed like any other, taking its driver state
+// with it.
+type quarantinedVolume struct {
+	baseVolume
+}
+
 type deadVolume struct {
 	baseVolume
 }
 
 func (vol *deadVolume) Destroy() error {
-	err := vol.fs.driver.DestroyVolume(vol)
+	snapshots, err := vol.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range snapshots {
+		err := snapshot.Destroy()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = vol.fs.driver.DestroyVolume(vol)
 	if err != nil {
 		return err
 	}
 
 	return vol.cleanup()
 }
+
+type snapshot struct {
+	fs *filesystem
+
+	handle string
+	dir    string
+}
+
+func (snap *snapshot) Handle() string {
+	return snap.handle
+}
+
+func (snap *snapshot) DataPath() string {
+	return filepath.Join(snap.dir, "volume")
+}
+
+func (snap *snapshot) LoadCreatedAt() (time.Time, error) {
+	return (&Metadata{snap.dir}).CreatedAt()
+}
+
+func (snap *snapshot) LoadDigest() (string, error) {
+	return (&Metadata{snap.dir}).Digest()
+}
+
+func (snap *snapshot) StoreDigest(digest string) error {
+	return (&Metadata{snap.dir}).StoreDigest(digest)
+}
+
+func (snap *snapshot) Destroy() error {
+	err := snap.fs.driver.DestroySnapshot(snap)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(snap.dir)
+}
@@ -0,0 +1,323 @@
+package fsroot
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Dir is an open directory. Operations on the names within it never follow a
+// symlink in the name's place.
+type Dir struct {
+	fd   int
+	path string
+}
+
+// Open opens the directory at path, which is trusted, as a root.
+func Open(path string) (*Dir, error) {
+	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+
+	return &Dir{fd: fd, path: path}, nil
+}
+
+func (dir *Dir) Close() error {
+	return unix.Close(dir.fd)
+}
+
+// Join returns the path of name within the directory, for use in errors.
+func (dir *Dir) Join(name string) string {
+	return filepath.Join(dir.path, name)
+}
+
+// OpenDir opens the directory called name.
+func (dir *Dir) OpenDir(name string) (*Dir, error) {
+	fd, err := unix.Openat(dir.fd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, dir.pathError("open", name, err)
+	}
+
+	return &Dir{fd: fd, path: dir.Join(name)}, nil
+}
+
+// Stat describes the directory itself.
+func (dir *Dir) Stat() (os.FileInfo, error) {
+	return dir.Lstat(".")
+}
+
+func (dir *Dir) Lstat(name string) (os.FileInfo, error) {
+	info, err := os.Lstat(dir.procPath(name))
+	if err != nil {
+		return nil, dir.pathError("lstat", name, underlying(err))
+	}
+
+	return info, nil
+}
+
+// Open opens the file called name for reading. Fifos are opened without
+// blocking.
+func (dir *Dir) Open(name string) (*os.File, error) {
+	fd, err := unix.Openat(dir.fd, name, unix.O_RDONLY|unix.O_NOFOLLOW|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, dir.pathError("open", name, err)
+	}
+
+	return os.NewFile(uintptr(fd), dir.Join(name)), nil
+}
+
+// Create creates a file called name for writing, failing if anything is
+// already there.
+func (dir *Dir) Create(name string, perm os.FileMode) (*os.File, error) {
+	fd, err := unix.Openat(dir.fd, name, unix.O_WRONLY|unix.O_CREAT|unix.O_EXCL|unix.O_NOFOLLOW|unix.O_CLOEXEC, uint32(perm.Perm()))
+	if err != nil {
+		return nil, dir.pathError("open", name, err)
+	}
+
+	return os.NewFile(uintptr(fd), dir.Join(name)), nil
+}
+
+func (dir *Dir) Mkdir(name string, perm os.FileMode) error {
+	return dir.pathError("mkdir", name, unix.Mkdirat(dir.fd, name, uint32(perm.Perm())))
+}
+
+func (dir *Dir) Symlink(target string, name string) error {
+	return dir.pathError("symlink", name, unix.Symlinkat(target, dir.fd, name))
+}
+
+// Link creates a hardlink called name to oldName within oldDir. A symlink
+// called oldName is linked to rather than followed.
+func (dir *Dir) Link(oldDir *Dir, oldName string, name string) error {
+	return dir.pathError("link", name, unix.Linkat(oldDir.fd, oldName, dir.fd, name, 0))
+}
+
+// Mknod creates a device node or fifo called name.
+func (dir *Dir) Mknod(name string, mode uint32, dev int) error {
+	return dir.pathError("mknod", name, unix.Mknodat(dir.fd, name, mode, dev))
+}
+
+func (dir *Dir) Readlink(name string) (string, error) {
+	for size := 256; ; size *= 2 {
+		buf := make([]byte, size)
+
+		n, err := unix.Readlinkat(dir.fd, name, buf)
+		if err != nil {
+			return "", dir.pathError("readlink", name, err)
+		}
+
+		if n < size {
+			return string(buf[:n]), nil
+		}
+	}
+}
+
+// Readdirnames lists the names in the directory.
+func (dir *Dir) Readdirnames() ([]string, error) {
+	// a fresh open file description, so that its offset is not shared
+	fd, err := unix.Openat(dir.fd, ".", unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, dir.pathError("open", ".", err)
+	}
+
+	file := os.NewFile(uintptr(fd), dir.path)
+	defer file.Close()
+
+	return file.Readdirnames(-1)
+}
+
+// Remove removes the file or empty directory called name.
+func (dir *Dir) Remove(name string) error {
+	err := unix.Unlinkat(dir.fd, name, 0)
+	if err == unix.EISDIR {
+		err = unix.Unlinkat(dir.fd, name, unix.AT_REMOVEDIR)
+	}
+
+	return dir.pathError("remove", name, err)
+}
+
+// RemoveAll removes name and anything beneath it. It is not an error if name
+// does not exist.
+func (dir *Dir) RemoveAll(name string) error {
+	err := dir.Remove(name)
+	if err == nil || os.IsNotExist(err) {
+		return nil
+	}
+
+	sub, openErr := dir.OpenDir(name)
+	if openErr != nil {
+		// not a directory after all, so the removal failed for some other
+		// reason
+		return err
+	}
+
+	names, err := sub.Readdirnames()
+	if err != nil {
+		sub.Close()
+		return err
+	}
+
+	for _, child := range names {
+		err := sub.RemoveAll(child)
+		if err != nil {
+			sub.Close()
+			return err
+		}
+	}
+
+	sub.Close()
+
+	err = dir.Remove(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (dir *Dir) Lchown(name string, uid int, gid int) error {
+	return dir.pathError("lchown", name, unix.Fchownat(dir.fd, name, uid, gid, unix.AT_SYMLINK_NOFOLLOW))
+}
+
+// Chmod changes the mode of name, which must not be a symlink; as symlinks
+// have no mode of their own, changing one would change whatever it points
+// to.
+func (dir *Dir) Chmod(name string, mode os.FileMode) error {
+	fd, err := unix.Openat(dir.fd, name, unix.O_PATH|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return dir.pathError("chmod", name, err)
+	}
+
+	defer unix.Close(fd)
+
+	var stat unix.Stat_t
+	err = unix.Fstat(fd, &stat)
+	if err != nil {
+		return dir.pathError("chmod", name, err)
+	}
+
+	if stat.Mode&unix.S_IFMT == unix.S_IFLNK {
+		return dir.pathError("chmod", name, unix.ELOOP)
+	}
+
+	// there is no fchmod for fds opened with O_PATH, but the fd's entry in
+	// procfs leads straight to the file it was opened on
+	err = unix.Fchmodat(unix.AT_FDCWD, "/proc/self/fd/"+strconv.Itoa(fd), fileMode(mode), 0)
+
+	return dir.pathError("chmod", name, err)
+}
+
+// Lchtimes changes the access and modification times of name, or of the
+// symlink itself if it is one.
+func (dir *Dir) Lchtimes(name string, atime time.Time, mtime time.Time) error {
+	times := []unix.Timespec{
+		unix.NsecToTimespec(atime.UnixNano()),
+		unix.NsecToTimespec(mtime.UnixNano()),
+	}
+
+	return dir.pathError("chtimes", name, unix.UtimesNanoAt(dir.fd, name, times, unix.AT_SYMLINK_NOFOLLOW))
+}
+
+// Lsetxattr sets an extended attribute on name, or on the symlink itself if
+// it is one.
+func (dir *Dir) Lsetxattr(name string, attr string, value []byte) error {
+	err := unix.Lsetxattr(dir.procPath(name), attr, value, 0)
+	return dir.pathError("setxattr "+attr, name, err)
+}
+
+// Lxattrs returns the extended attributes of name, or of the symlink itself
+// if it is one. Nothing is returned if the filesystem does not support them.
+func (dir *Dir) Lxattrs(name string) (map[string]string, error) {
+	path := dir.procPath(name)
+
+	size, err := unix.Llistxattr(path, nil)
+	if err == unix.ENOTSUP || size == 0 {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, dir.pathError("listxattr", name, err)
+	}
+
+	names := make([]byte, size)
+	size, err = unix.Llistxattr(path, names)
+	if err != nil {
+		return nil, dir.pathError("listxattr", name, err)
+	}
+
+	xattrs := map[string]string{}
+	for _, attr := range splitNull(names[:size]) {
+		size, err := unix.Lgetxattr(path, attr, nil)
+		if err != nil {
+			return nil, dir.pathError("getxattr "+attr, name, err)
+		}
+
+		value := make([]byte, size)
+		size, err = unix.Lgetxattr(path, attr, value)
+		if err != nil {
+			return nil, dir.pathError("getxattr "+attr, name, err)
+		}
+
+		xattrs[attr] = string(value[:size])
+	}
+
+	return xattrs, nil
+}
+
+// procPath names name within the directory by way of the directory's fd, so
+// that the directory cannot be swapped out, while leaving it to the caller
+// whether a symlink called name is followed.
+func (dir *Dir) procPath(name string) string {
+	return "/proc/self/fd/" + strconv.Itoa(dir.fd) + "/" + name
+}
+
+func (dir *Dir) pathError(op string, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &os.PathError{Op: op, Path: dir.Join(name), Err: err}
+}
+
+func fileMode(mode os.FileMode) uint32 {
+	perm := uint32(mode.Perm())
+
+	if mode&os.ModeSetuid != 0 {
+		perm |= syscall.S_ISUID
+	}
+
+	if mode&os.ModeSetgid != 0 {
+		perm |= syscall.S_ISGID
+	}
+
+	if mode&os.ModeSticky != 0 {
+		perm |= syscall.S_ISVTX
+	}
+
+	return perm
+}
+
+func splitNull(buf []byte) []string {
+	names := []string{}
+
+	start := 0
+	for i, b := range buf {
+		if b == 0 {
+			if i > start {
+				names = append(names, string(buf[start:i]))
+			}
+
+			start = i + 1
+		}
+	}
+
+	if start < len(buf) {
+		names = append(names, string(buf[start:]))
+	}
+
+	return names
+}
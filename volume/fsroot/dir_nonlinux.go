@@ -0,0 +1,176 @@
+// +build !linux
+
+package fsroot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// Dir is a directory. Operations on the names within it do not follow a
+// symlink in the name's place, but as the directory is only known by its
+// path it may itself be swapped out from under them.
+type Dir struct {
+	path string
+}
+
+// Open opens the directory at path, which is trusted, as a root.
+func Open(path string) (*Dir, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, &os.PathError{Op: "open", Path: path, Err: syscall.ENOTDIR}
+	}
+
+	return &Dir{path: path}, nil
+}
+
+func (dir *Dir) Close() error {
+	return nil
+}
+
+// Join returns the path of name within the directory, for use in errors.
+func (dir *Dir) Join(name string) string {
+	return filepath.Join(dir.path, name)
+}
+
+// OpenDir opens the directory called name.
+func (dir *Dir) OpenDir(name string) (*Dir, error) {
+	info, err := os.Lstat(dir.Join(name))
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, &os.PathError{Op: "open", Path: dir.Join(name), Err: syscall.ENOTDIR}
+	}
+
+	return &Dir{path: dir.Join(name)}, nil
+}
+
+// Stat describes the directory itself.
+func (dir *Dir) Stat() (os.FileInfo, error) {
+	return os.Lstat(dir.path)
+}
+
+func (dir *Dir) Lstat(name string) (os.FileInfo, error) {
+	return os.Lstat(dir.Join(name))
+}
+
+// Open opens the file called name for reading.
+func (dir *Dir) Open(name string) (*os.File, error) {
+	info, err := os.Lstat(dir.Join(name))
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		return nil, &os.PathError{Op: "open", Path: dir.Join(name), Err: syscall.ELOOP}
+	}
+
+	return os.Open(dir.Join(name))
+}
+
+// Create creates a file called name for writing, failing if anything is
+// already there.
+func (dir *Dir) Create(name string, perm os.FileMode) (*os.File, error) {
+	return os.OpenFile(dir.Join(name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+}
+
+func (dir *Dir) Mkdir(name string, perm os.FileMode) error {
+	return os.Mkdir(dir.Join(name), perm)
+}
+
+func (dir *Dir) Symlink(target string, name string) error {
+	return os.Symlink(target, dir.Join(name))
+}
+
+// Link creates a hardlink called name to oldName within oldDir.
+func (dir *Dir) Link(oldDir *Dir, oldName string, name string) error {
+	return os.Link(oldDir.Join(oldName), dir.Join(name))
+}
+
+// Mknod creates a device node or fifo called name.
+func (dir *Dir) Mknod(name string, mode uint32, dev int) error {
+	return &os.PathError{
+		Op:   "mknod",
+		Path: dir.Join(name),
+		Err:  fmt.Errorf("creating special files is not supported on %s", runtime.GOOS),
+	}
+}
+
+func (dir *Dir) Readlink(name string) (string, error) {
+	return os.Readlink(dir.Join(name))
+}
+
+// Readdirnames lists the names in the directory.
+func (dir *Dir) Readdirnames() ([]string, error) {
+	file, err := os.Open(dir.path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return file.Readdirnames(-1)
+}
+
+// Remove removes the file or empty directory called name.
+func (dir *Dir) Remove(name string) error {
+	return os.Remove(dir.Join(name))
+}
+
+// RemoveAll removes name and anything beneath it. It is not an error if name
+// does not exist.
+func (dir *Dir) RemoveAll(name string) error {
+	return os.RemoveAll(dir.Join(name))
+}
+
+func (dir *Dir) Lchown(name string, uid int, gid int) error {
+	return os.Lchown(dir.Join(name), uid, gid)
+}
+
+// Chmod changes the mode of name, which must not be a symlink.
+func (dir *Dir) Chmod(name string, mode os.FileMode) error {
+	info, err := os.Lstat(dir.Join(name))
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		return &os.PathError{Op: "chmod", Path: dir.Join(name), Err: syscall.ELOOP}
+	}
+
+	return os.Chmod(dir.Join(name), mode)
+}
+
+// Lchtimes changes the access and modification times of name. Those of
+// symlinks are left alone.
+func (dir *Dir) Lchtimes(name string, atime time.Time, mtime time.Time) error {
+	info, err := os.Lstat(dir.Join(name))
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		return nil
+	}
+
+	return os.Chtimes(dir.Join(name), atime, mtime)
+}
+
+// extended attributes are not supported on these platforms
+func (dir *Dir) Lsetxattr(name string, attr string, value []byte) error {
+	return nil
+}
+
+func (dir *Dir) Lxattrs(name string) (map[string]string, error) {
+	return nil, nil
+}
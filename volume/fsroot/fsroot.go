@@ -0,0 +1,238 @@
+// Package fsroot operates on the files beneath a root directory without
+// letting symlinks lead outside of it.
+//
+// Volume contents are written by containers, which may replace any part of a
+// path with a symlink at any moment. Paths are therefore resolved one
+// component at a time, each relative to the directory before it, and every
+// operation acts on a name within an already-opened Dir without following a
+// symlink in its place. On Linux a Dir holds its directory open, so nothing
+// can be swapped out from under an operation once the Dir is resolved.
+package fsroot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// ErrOutsideRoot is returned when resolving a path would lead outside of the
+// root, whether through a symlink or a ".." component.
+var ErrOutsideRoot = errors.New("path leads outside of root")
+
+// maxSymlinks is how many symlinks may be followed while resolving a path, as
+// with the kernel's own limit.
+const maxSymlinks = 40
+
+type ResolveOptions struct {
+	// Chroot resolves symlinks and ".." components as though the root were
+	// the root of the filesystem, so that they can never climb above it,
+	// rather than refusing them with ErrOutsideRoot.
+	Chroot bool
+
+	// Follow resolves a symlink in the final component of the path too.
+	Follow bool
+
+	// Mkdir creates any missing directories leading up to the final
+	// component.
+	Mkdir bool
+}
+
+// Resolve walks path within dir, returning the directory that holds its final
+// component and that component's name, which is "." if the path resolves to
+// the directory itself. The final component need not exist. The returned Dir
+// must be closed by the caller.
+func (dir *Dir) Resolve(path string, opts ResolveOptions) (*Dir, string, error) {
+	pathErr := func(err error) error {
+		return &os.PathError{Op: "resolve", Path: dir.Join(path), Err: underlying(err)}
+	}
+
+	root, err := dir.OpenDir(".")
+	if err != nil {
+		return nil, "", pathErr(err)
+	}
+
+	// the directories resolved so far, starting at the root
+	dirs := []*Dir{root}
+	closeFrom := func(i int) {
+		for _, d := range dirs[i:] {
+			d.Close()
+		}
+
+		dirs = dirs[:i]
+	}
+
+	// hands the last directory to the caller, closing the rest
+	last := func() *Dir {
+		d := dirs[len(dirs)-1]
+		dirs = dirs[:len(dirs)-1]
+		closeFrom(0)
+		return d
+	}
+
+	remaining := splitPath(path)
+	links := 0
+
+	for len(remaining) > 0 {
+		name := remaining[0]
+		remaining = remaining[1:]
+
+		switch name {
+		case "", ".":
+			continue
+
+		case "..":
+			if len(dirs) > 1 {
+				closeFrom(len(dirs) - 1)
+			} else if !opts.Chroot {
+				closeFrom(0)
+				return nil, "", pathErr(ErrOutsideRoot)
+			}
+
+			continue
+		}
+
+		current := dirs[len(dirs)-1]
+		final := len(remaining) == 0
+
+		info, err := current.Lstat(name)
+		if os.IsNotExist(err) && !final && opts.Mkdir {
+			err = current.Mkdir(name, 0755)
+			if err == nil || os.IsExist(err) {
+				info, err = current.Lstat(name)
+			}
+		}
+
+		if os.IsNotExist(err) && final {
+			return last(), name, nil
+		}
+
+		if err != nil {
+			closeFrom(0)
+			return nil, "", pathErr(err)
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 && (!final || opts.Follow) {
+			links++
+			if links > maxSymlinks {
+				closeFrom(0)
+				return nil, "", pathErr(syscall.ELOOP)
+			}
+
+			target, err := current.Readlink(name)
+			if err != nil {
+				closeFrom(0)
+				return nil, "", pathErr(err)
+			}
+
+			if filepath.IsAbs(target) {
+				if !opts.Chroot {
+					closeFrom(0)
+					return nil, "", pathErr(ErrOutsideRoot)
+				}
+
+				closeFrom(1)
+			}
+
+			remaining = append(splitPath(target), remaining...)
+
+			continue
+		}
+
+		if final {
+			return last(), name, nil
+		}
+
+		if !info.IsDir() {
+			closeFrom(0)
+			return nil, "", pathErr(syscall.ENOTDIR)
+		}
+
+		next, err := current.OpenDir(name)
+		if err != nil {
+			closeFrom(0)
+			return nil, "", pathErr(err)
+		}
+
+		dirs = append(dirs, next)
+	}
+
+	return last(), ".", nil
+}
+
+// ResolveDir is like Resolve, but returns the directory the path leads to,
+// following a symlink in its final component. With Mkdir, the directory is
+// created if it is missing.
+func (dir *Dir) ResolveDir(path string, opts ResolveOptions) (*Dir, error) {
+	opts.Follow = true
+
+	parent, name, err := dir.Resolve(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer parent.Close()
+
+	if opts.Mkdir {
+		err := parent.Mkdir(name, 0755)
+		if err != nil && !os.IsExist(err) {
+			return nil, err
+		}
+	}
+
+	return parent.OpenDir(name)
+}
+
+// OpenFile opens path for reading as though root were the root of the
+// filesystem.
+func OpenFile(root string, path string) (*os.File, error) {
+	rootDir, err := Open(root)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rootDir.Close()
+
+	parent, name, err := rootDir.Resolve(path, ResolveOptions{Chroot: true, Follow: true})
+	if err != nil {
+		return nil, err
+	}
+
+	defer parent.Close()
+
+	return parent.Open(name)
+}
+
+// Stat describes path as though root were the root of the filesystem.
+func Stat(root string, path string) (os.FileInfo, error) {
+	rootDir, err := Open(root)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rootDir.Close()
+
+	parent, name, err := rootDir.Resolve(path, ResolveOptions{Chroot: true, Follow: true})
+	if err != nil {
+		return nil, err
+	}
+
+	defer parent.Close()
+
+	return parent.Lstat(name)
+}
+
+func splitPath(path string) []string {
+	return strings.Split(filepath.ToSlash(path), "/")
+}
+
+// underlying unwraps the error from an os function so that it can be
+// reported against another path, e.g. the name rather than its procfs path.
+func underlying(err error) error {
+	if pathErr, ok := err.(*os.PathError); ok {
+		return pathErr.Err
+	}
+
+	return err
+}
@@ -0,0 +1,13 @@
+package fsroot_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFsroot(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fsroot Suite")
+}
@@ -0,0 +1,136 @@
+package fsroot_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/concourse/baggageclaim/volume/fsroot"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fsroot", func() {
+	var (
+		rootPath string
+		hostPath string
+
+		root *fsroot.Dir
+	)
+
+	BeforeEach(func() {
+		var err error
+		rootPath, err = ioutil.TempDir("", "fsroot-root")
+		Expect(err).ToNot(HaveOccurred())
+
+		hostPath, err = ioutil.TempDir("", "fsroot-host")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(rootPath, "some-dir"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(rootPath, "some-dir", "some-file"), []byte("some-contents"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(hostPath, "some-file"), []byte("host-contents"), 0644)).To(Succeed())
+
+		Expect(os.Symlink("some-dir", filepath.Join(rootPath, "relative-link"))).To(Succeed())
+		Expect(os.Symlink("/some-dir", filepath.Join(rootPath, "absolute-link"))).To(Succeed())
+		Expect(os.Symlink(hostPath, filepath.Join(rootPath, "host-link"))).To(Succeed())
+		Expect(os.Symlink("../..", filepath.Join(rootPath, "some-dir", "climbing-link"))).To(Succeed())
+
+		root, err = fsroot.Open(rootPath)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(root.Close()).To(Succeed())
+		Expect(os.RemoveAll(rootPath)).To(Succeed())
+		Expect(os.RemoveAll(hostPath)).To(Succeed())
+	})
+
+	Describe("Resolve", func() {
+		It("returns the directory holding the final component", func() {
+			parent, name, err := root.Resolve("relative-link/some-file", fsroot.ResolveOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			defer parent.Close()
+
+			Expect(name).To(Equal("some-file"))
+
+			info, err := parent.Lstat(name)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().IsRegular()).To(BeTrue())
+		})
+
+		It("does not follow a symlink in the final component unless asked to", func() {
+			parent, name, err := root.Resolve("relative-link", fsroot.ResolveOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(name).To(Equal("relative-link"))
+			Expect(parent.Close()).To(Succeed())
+
+			parent, name, err = root.Resolve("relative-link", fsroot.ResolveOptions{Follow: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(name).To(Equal("some-dir"))
+			Expect(parent.Close()).To(Succeed())
+		})
+
+		It("refuses paths that lead outside of the root", func() {
+			for _, path := range []string{"..", "some-dir/../..", "host-link/some-file", "absolute-link/some-file", "some-dir/climbing-link/some-file"} {
+				_, _, err := root.Resolve(path, fsroot.ResolveOptions{})
+				Expect(errors.Is(err, fsroot.ErrOutsideRoot)).To(BeTrue(), "expected %s to be refused, got %v", path, err)
+			}
+		})
+
+		It("keeps paths within the root with Chroot", func() {
+			file, err := fsroot.OpenFile(rootPath, "some-dir/climbing-link/absolute-link/some-file")
+			Expect(err).ToNot(HaveOccurred())
+			defer file.Close()
+
+			contents, err := ioutil.ReadAll(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(contents)).To(Equal("some-contents"))
+
+			_, err = fsroot.OpenFile(rootPath, "host-link/some-file")
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("creates missing directories with Mkdir", func() {
+			dir, err := root.ResolveDir("relative-link/a/b", fsroot.ResolveOptions{Mkdir: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dir.Close()).To(Succeed())
+
+			Expect(filepath.Join(rootPath, "some-dir", "a", "b")).To(BeADirectory())
+		})
+
+		It("gives up on symlink loops", func() {
+			Expect(os.Symlink("loop", filepath.Join(rootPath, "loop"))).To(Succeed())
+
+			_, _, err := root.Resolve("loop", fsroot.ResolveOptions{Follow: true})
+			Expect(errors.Is(err, syscall.ELOOP)).To(BeTrue())
+		})
+	})
+
+	Describe("Chmod", func() {
+		It("refuses to change the mode of a symlink's target", func() {
+			err := root.Chmod("host-link", 0777)
+			Expect(errors.Is(err, syscall.ELOOP)).To(BeTrue())
+
+			info, err := os.Stat(hostPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0700)))
+		})
+	})
+
+	Describe("RemoveAll", func() {
+		It("removes symlinks rather than what they point to", func() {
+			Expect(root.RemoveAll("host-link")).To(Succeed())
+
+			Expect(filepath.Join(rootPath, "host-link")).ToNot(BeAnExistingFile())
+			Expect(filepath.Join(hostPath, "some-file")).To(BeAnExistingFile())
+		})
+
+		It("removes directories and their contents", func() {
+			Expect(root.RemoveAll("some-dir")).To(Succeed())
+			Expect(filepath.Join(rootPath, "some-dir")).ToNot(BeAnExistingFile())
+		})
+	})
+})
@@ -39,7 +39,7 @@ func (strategy ImportStrategy) Materialize(logger lager.Logger, handle string, f
 
 		defer tgzFile.Close()
 
-		invalid, err := streamer.In(tgzFile, destination, true)
+		invalid, err := streamer.In(tgzFile, destination, ".", true)
 		if err != nil {
 			if invalid {
 				logger.Info("malformed-archive", lager.Data{
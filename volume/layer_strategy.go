@@ -0,0 +1,56 @@
+package volume
+
+import (
+	"errors"
+	"os"
+
+	"code.cloudfoundry.org/lager"
+)
+
+var ErrNoLayerProvided = errors.New("no layer provided")
+
+// LayerStrategy creates a copy-on-write child of a volume and applies a
+// layer to it, e.g. one streamed out of another volume with only its
+// changes. The layer is a gzipped tarball on the host in which deletions are
+// recorded as overlay-style whiteouts.
+type LayerStrategy struct {
+	ParentHandle string
+	Path         string
+}
+
+func (strategy LayerStrategy) Materialize(logger lager.Logger, handle string, fs Filesystem, streamer Streamer) (FilesystemInitVolume, error) {
+	if strategy.Path == "" {
+		logger.Info("layer-not-specified")
+		return nil, ErrNoLayerProvided
+	}
+
+	initVolume, err := COWStrategy{strategy.ParentHandle}.Materialize(logger, handle, fs, streamer)
+	if err != nil {
+		return nil, err
+	}
+
+	layerFile, err := os.Open(strategy.Path)
+	if err != nil {
+		logger.Error("failed-to-open-layer", err)
+		initVolume.Destroy()
+		return nil, err
+	}
+
+	defer layerFile.Close()
+
+	invalid, err := streamer.InLayer(layerFile, initVolume.DataPath(), ".", true)
+	if err != nil {
+		if invalid {
+			logger.Info("malformed-layer", lager.Data{
+				"error": err.Error(),
+			})
+		} else {
+			logger.Error("failed-to-apply-layer", err)
+		}
+
+		initVolume.Destroy()
+		return nil, err
+	}
+
+	return initVolume, nil
+}
@@ -0,0 +1,155 @@
+package volume_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LayerStrategy", func() {
+	var (
+		strategy Strategy
+
+		layerPath string
+	)
+
+	BeforeEach(func() {
+		layerFile, err := ioutil.TempFile("", "layer")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = layerFile.Write([]byte("some-layer"))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(layerFile.Close()).To(Succeed())
+
+		layerPath = layerFile.Name()
+
+		strategy = LayerStrategy{
+			ParentHandle: "parent-volume",
+			Path:         layerPath,
+		}
+	})
+
+	AfterEach(func() {
+		os.Remove(layerPath)
+	})
+
+	Describe("Materialize", func() {
+		var (
+			fakeFilesystem *volumefakes.FakeFilesystem
+			fakeStreamer   *volumefakes.FakeStreamer
+			parentVolume   *volumefakes.FakeFilesystemLiveVolume
+			fakeVolume     *volumefakes.FakeFilesystemInitVolume
+
+			appliedLayer []byte
+
+			materializedVolume FilesystemInitVolume
+			materializeErr     error
+		)
+
+		BeforeEach(func() {
+			fakeVolume = new(volumefakes.FakeFilesystemInitVolume)
+			fakeVolume.DataPathReturns("/some/data/path")
+
+			parentVolume = new(volumefakes.FakeFilesystemLiveVolume)
+			parentVolume.NewSubvolumeReturns(fakeVolume, nil)
+
+			fakeFilesystem = new(volumefakes.FakeFilesystem)
+			fakeFilesystem.LookupVolumeReturns(parentVolume, true, nil)
+
+			fakeStreamer = new(volumefakes.FakeStreamer)
+			fakeStreamer.InLayerStub = func(stream io.Reader, root string, dest string, privileged bool) (bool, error) {
+				var err error
+				appliedLayer, err = ioutil.ReadAll(stream)
+				return false, err
+			}
+		})
+
+		JustBeforeEach(func() {
+			materializedVolume, materializeErr = strategy.Materialize(
+				lagertest.NewTestLogger("test"),
+				"some-volume",
+				fakeFilesystem,
+				fakeStreamer,
+			)
+		})
+
+		It("creates a child of the parent volume", func() {
+			Expect(materializeErr).ToNot(HaveOccurred())
+			Expect(materializedVolume).To(Equal(fakeVolume))
+
+			Expect(fakeFilesystem.LookupVolumeArgsForCall(0)).To(Equal("parent-volume"))
+			Expect(parentVolume.NewSubvolumeArgsForCall(0)).To(Equal("some-volume"))
+		})
+
+		It("applies the layer to the child", func() {
+			Expect(fakeStreamer.InLayerCallCount()).To(Equal(1))
+
+			_, root, dest, privileged := fakeStreamer.InLayerArgsForCall(0)
+			Expect(appliedLayer).To(Equal([]byte("some-layer")))
+			Expect(root).To(Equal("/some/data/path"))
+			Expect(dest).To(Equal("."))
+			Expect(privileged).To(BeTrue())
+		})
+
+		Context("when applying the layer fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeStreamer.InLayerStub = nil
+				fakeStreamer.InLayerReturns(true, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(materializeErr).To(Equal(disaster))
+			})
+
+			It("destroys the child", func() {
+				Expect(fakeVolume.DestroyCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the layer does not exist", func() {
+			BeforeEach(func() {
+				strategy = LayerStrategy{
+					ParentHandle: "parent-volume",
+					Path:         filepath.Join(layerPath, "bogus"),
+				}
+			})
+
+			It("returns the error and destroys the child", func() {
+				Expect(materializeErr).To(HaveOccurred())
+				Expect(fakeVolume.DestroyCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when no layer is given", func() {
+			BeforeEach(func() {
+				strategy = LayerStrategy{ParentHandle: "parent-volume"}
+			})
+
+			It("returns ErrNoLayerProvided", func() {
+				Expect(materializeErr).To(Equal(ErrNoLayerProvided))
+			})
+		})
+
+		Context("when the parent volume does not exist", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+			})
+
+			It("returns ErrParentVolumeNotFound", func() {
+				Expect(materializeErr).To(Equal(ErrParentVolumeNotFound))
+			})
+		})
+	})
+})
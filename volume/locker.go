@@ -3,6 +3,9 @@ package volume
 import (
 	"fmt"
 	"sync"
+	"time"
+
+	"github.com/concourse/baggageclaim/metrics"
 )
 
 //go:generate counterfeiter . LockManager
@@ -30,6 +33,8 @@ func NewLockManager() LockManager {
 }
 
 func (m *lockManager) Lock(key string) {
+	start := time.Now()
+
 	m.mutex.Lock()
 	entry, ok := m.locks[key]
 	if !ok {
@@ -42,6 +47,8 @@ func (m *lockManager) Lock(key string) {
 	entry.count++
 	m.mutex.Unlock()
 	entry.ch <- struct{}{}
+
+	metrics.LockWaitDuration.Observe(time.Since(start).Seconds())
 }
 
 func (m *lockManager) Unlock(key string) {
@@ -2,8 +2,10 @@ package volume
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 type VolumeState string
@@ -11,6 +13,12 @@ type VolumeState string
 const (
 	propertiesFileName   = "properties.json"
 	isPrivilegedFileName = "privileged.json"
+	expiresAtFileName    = "expires_at.json"
+	createdAtFileName    = "created_at.json"
+	readOnlyFileName     = "read_only.json"
+	digestFileName       = "digest.json"
+	quotaFileName        = "quota.json"
+	layerFileName        = "layer.json"
 )
 
 type Metadata struct {
@@ -80,6 +88,333 @@ func (ipf *isPrivilegedFile) IsPrivileged() (bool, error) {
 	return isPrivileged, nil
 }
 
+func (md *Metadata) expiresAtFile() *expiresAtFile {
+	return &expiresAtFile{path: filepath.Join(md.path, expiresAtFileName)}
+}
+
+// ExpiresAt returns when the volume's TTL runs out. The zero time is returned
+// if the volume has no TTL.
+func (md *Metadata) ExpiresAt() (time.Time, error) {
+	return md.expiresAtFile().ExpiresAt()
+}
+
+func (md *Metadata) StoreExpiresAt(expiresAt time.Time) error {
+	return md.expiresAtFile().WriteExpiresAt(expiresAt)
+}
+
+type expiresAtFile struct {
+	path string
+}
+
+func (eaf *expiresAtFile) WriteExpiresAt(expiresAt time.Time) error {
+	return writeMetadataFile(eaf.path, expiresAt)
+}
+
+func (eaf *expiresAtFile) ExpiresAt() (time.Time, error) {
+	// volumes created without a TTL have no file
+	_, err := os.Stat(eaf.path)
+	if os.IsNotExist(err) {
+		_, err = os.Stat(filepath.Dir(eaf.path))
+		if err == nil {
+			return time.Time{}, nil
+		}
+	}
+
+	var expiresAt time.Time
+
+	err = readMetadataFile(eaf.path, &expiresAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return expiresAt, nil
+}
+
+func (md *Metadata) createdAtFile() *createdAtFile {
+	return &createdAtFile{path: filepath.Join(md.path, createdAtFileName)}
+}
+
+// CreatedAt returns when a volume was created or a snapshot was taken.
+// Volumes created before their creation time was recorded have none, and
+// report the zero time.
+func (md *Metadata) CreatedAt() (time.Time, error) {
+	return md.createdAtFile().CreatedAt()
+}
+
+func (md *Metadata) StoreCreatedAt(createdAt time.Time) error {
+	return md.createdAtFile().WriteCreatedAt(createdAt)
+}
+
+type createdAtFile struct {
+	path string
+}
+
+func (caf *createdAtFile) WriteCreatedAt(createdAt time.Time) error {
+	return writeMetadataFile(caf.path, createdAt)
+}
+
+func (caf *createdAtFile) CreatedAt() (time.Time, error) {
+	_, err := os.Stat(caf.path)
+	if os.IsNotExist(err) {
+		_, err = os.Stat(filepath.Dir(caf.path))
+		if err == nil {
+			return time.Time{}, nil
+		}
+	}
+
+	var createdAt time.Time
+
+	err = readMetadataFile(caf.path, &createdAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return createdAt, nil
+}
+
+func (md *Metadata) readOnlyFile() *readOnlyFile {
+	return &readOnlyFile{path: filepath.Join(md.path, readOnlyFileName)}
+}
+
+// IsReadOnly returns whether the volume's contents have been sealed against
+// changes.
+func (md *Metadata) IsReadOnly() (bool, error) {
+	return md.readOnlyFile().IsReadOnly()
+}
+
+func (md *Metadata) StoreReadOnly(isReadOnly bool) error {
+	return md.readOnlyFile().WriteReadOnly(isReadOnly)
+}
+
+type readOnlyFile struct {
+	path string
+}
+
+func (rof *readOnlyFile) WriteReadOnly(isReadOnly bool) error {
+	return writeMetadataFile(rof.path, isReadOnly)
+}
+
+func (rof *readOnlyFile) IsReadOnly() (bool, error) {
+	// volumes that have never been made read-only have no file
+	_, err := os.Stat(rof.path)
+	if os.IsNotExist(err) {
+		_, err = os.Stat(filepath.Dir(rof.path))
+		if err == nil {
+			return false, nil
+		}
+	}
+
+	var isReadOnly bool
+
+	err = readMetadataFile(rof.path, &isReadOnly)
+	if err != nil {
+		return false, err
+	}
+
+	return isReadOnly, nil
+}
+
+func (md *Metadata) digestFile() *digestFile {
+	return &digestFile{path: filepath.Join(md.path, digestFileName)}
+}
+
+// Digest returns the recorded digest of the volume's contents, or "" if none
+// has been recorded.
+func (md *Metadata) Digest() (string, error) {
+	return md.digestFile().Digest()
+}
+
+func (md *Metadata) StoreDigest(digest string) error {
+	return md.digestFile().WriteDigest(digest)
+}
+
+type digestFile struct {
+	path string
+}
+
+func (df *digestFile) WriteDigest(digest string) error {
+	return writeMetadataFile(df.path, digest)
+}
+
+func (df *digestFile) Digest() (string, error) {
+	// volumes whose digest has not been recorded have no file
+	_, err := os.Stat(df.path)
+	if os.IsNotExist(err) {
+		_, err = os.Stat(filepath.Dir(df.path))
+		if err == nil {
+			return "", nil
+		}
+	}
+
+	var digest string
+
+	err = readMetadataFile(df.path, &digest)
+	if err != nil {
+		return "", err
+	}
+
+	return digest, nil
+}
+
+func (md *Metadata) quotaFile() *quotaFile {
+	return &quotaFile{path: filepath.Join(md.path, quotaFileName)}
+}
+
+// Quota returns the number of bytes the volume is limited to, or 0 if it has
+// no quota.
+func (md *Metadata) Quota() (uint64, error) {
+	return md.quotaFile().Quota()
+}
+
+func (md *Metadata) StoreQuota(quota uint64) error {
+	return md.quotaFile().WriteQuota(quota)
+}
+
+type quotaFile struct {
+	path string
+}
+
+func (qf *quotaFile) WriteQuota(quota uint64) error {
+	return writeMetadataFile(qf.path, quota)
+}
+
+func (qf *quotaFile) Quota() (uint64, error) {
+	// volumes created without a quota have no file
+	_, err := os.Stat(qf.path)
+	if os.IsNotExist(err) {
+		_, err = os.Stat(filepath.Dir(qf.path))
+		if err == nil {
+			return 0, nil
+		}
+	}
+
+	var quota uint64
+
+	err = readMetadataFile(qf.path, &quota)
+	if err != nil {
+		return 0, err
+	}
+
+	return quota, nil
+}
+
+func (md *Metadata) layerFile() *layerFile {
+	return &layerFile{path: filepath.Join(md.path, layerFileName)}
+}
+
+// Layer returns the digest of the image layer the volume holds, or "" if it
+// does not hold one.
+func (md *Metadata) Layer() (string, error) {
+	return md.layerFile().Layer()
+}
+
+func (md *Metadata) StoreLayer(digest string) error {
+	return md.layerFile().WriteLayer(digest)
+}
+
+type layerFile struct {
+	path string
+}
+
+func (lf *layerFile) WriteLayer(digest string) error {
+	return writeMetadataFile(lf.path, digest)
+}
+
+func (lf *layerFile) Layer() (string, error) {
+	// only volumes created to hold an image layer have a file
+	_, err := os.Stat(lf.path)
+	if os.IsNotExist(err) {
+		_, err = os.Stat(filepath.Dir(lf.path))
+		if err == nil {
+			return "", nil
+		}
+	}
+
+	var digest string
+
+	err = readMetadataFile(lf.path, &digest)
+	if err != nil {
+		return "", err
+	}
+
+	return digest, nil
+}
+
+// Verify checks that each metadata file is present and parseable, returning
+// a description of every problem found.
+func (md *Metadata) Verify() []string {
+	problems := []string{}
+
+	var properties Properties
+	if err := verifyMetadataFile(md.propertiesFile().path, &properties); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	var isPrivileged bool
+	if err := verifyMetadataFile(md.isPrivilegedFile().path, &isPrivileged); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	// volumes created without a TTL have no file
+	expiresAtPath := md.expiresAtFile().path
+	if _, err := os.Stat(expiresAtPath); !os.IsNotExist(err) {
+		var expiresAt time.Time
+		if err := verifyMetadataFile(expiresAtPath, &expiresAt); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	readOnlyPath := md.readOnlyFile().path
+	if _, err := os.Stat(readOnlyPath); !os.IsNotExist(err) {
+		var isReadOnly bool
+		if err := verifyMetadataFile(readOnlyPath, &isReadOnly); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	digestPath := md.digestFile().path
+	if _, err := os.Stat(digestPath); !os.IsNotExist(err) {
+		var digest string
+		if err := verifyMetadataFile(digestPath, &digest); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	quotaPath := md.quotaFile().path
+	if _, err := os.Stat(quotaPath); !os.IsNotExist(err) {
+		var quota uint64
+		if err := verifyMetadataFile(quotaPath, &quota); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	layerPath := md.layerFile().path
+	if _, err := os.Stat(layerPath); !os.IsNotExist(err) {
+		var layer string
+		if err := verifyMetadataFile(layerPath, &layer); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	return problems
+}
+
+func verifyMetadataFile(path string, value interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	err = json.NewDecoder(file).Decode(value)
+	if err != nil {
+		return fmt.Errorf("malformed %s: %s", filepath.Base(path), err)
+	}
+
+	return nil
+}
+
 func readMetadataFile(path string, properties interface{}) error {
 	file, err := os.Open(path)
 	if err != nil {
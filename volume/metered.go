@@ -0,0 +1,45 @@
+package volume
+
+import (
+	"io"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type meteredReader struct {
+	io.Reader
+
+	bytes prometheus.Counter
+}
+
+func (reader meteredReader) Read(p []byte) (int, error) {
+	n, err := reader.Reader.Read(p)
+	reader.bytes.Add(float64(n))
+	return n, err
+}
+
+type meteredWriter struct {
+	io.Writer
+
+	bytes prometheus.Counter
+}
+
+func (writer meteredWriter) Write(p []byte) (int, error) {
+	n, err := writer.Writer.Write(p)
+	writer.bytes.Add(float64(n))
+	return n, err
+}
+
+// countingWriter counts the bytes written through it. It is not safe for
+// concurrent use; read n only once writing is over.
+type countingWriter struct {
+	io.Writer
+
+	n int64
+}
+
+func (writer *countingWriter) Write(p []byte) (int, error) {
+	n, err := writer.Writer.Write(p)
+	writer.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,222 @@
+package volume
+
+import (
+	"context"
+	"io"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+
+	"github.com/concourse/baggageclaim/metrics"
+	"github.com/concourse/baggageclaim/volume/oci"
+)
+
+// StreamOutOCI streams out an OCI image layout holding the volume's contents
+// as a tar stream, naming the image after the volume and labelling it with
+// its properties.
+//
+// If chain is set the image has a layer for each volume in the volume's
+// copy-on-write chain, holding the changes each made to its parent, so that
+// images of volumes sharing a parent share its layers too. Otherwise the
+// image has a single layer.
+func (repo *repository) StreamOutOCI(ctx context.Context, handle string, chain bool, encoding string, dest io.Writer) error {
+	logger := lagerctx.FromContext(ctx).Session("stream-out-oci", lager.Data{
+		"volume": handle,
+		"chain":  chain,
+	})
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return ErrVolumeDoesNotExist
+	}
+
+	enc, found := LookupEncoding(encoding)
+	if !found {
+		return ErrUnsupportedStreamEncoding
+	}
+
+	volumes := []FilesystemLiveVolume{volume}
+	for chain {
+		parent, found, err := volumes[0].Parent()
+		if err != nil {
+			logger.Error("failed-to-get-parent", err)
+			return err
+		}
+
+		if !found {
+			break
+		}
+
+		volumes = append([]FilesystemLiveVolume{parent}, volumes...)
+	}
+
+	properties, err := volume.LoadProperties()
+	if err != nil {
+		logger.Error("failed-to-load-properties", err)
+		return err
+	}
+
+	layers := []*oci.Layer{}
+	defer func() {
+		for _, layer := range layers {
+			_ = layer.Close()
+		}
+	}()
+
+	for i, layerVolume := range volumes {
+		layer, err := repo.ociLayer(layerVolume, i == 0)
+		if err != nil {
+			logger.Error("failed-to-create-layer", err, lager.Data{
+				"layer-volume": layerVolume.Handle(),
+			})
+			return err
+		}
+
+		layers = append(layers, layer)
+	}
+
+	writer, err := enc.NewWriter(meteredWriter{
+		Writer: dest,
+		bytes:  metrics.StreamedBytes.WithLabelValues("out", encoding),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = oci.WriteArchive(writer, layers, properties, handle)
+	if err != nil {
+		_ = writer.Close()
+		return err
+	}
+
+	return writer.Close()
+}
+
+// ociLayer spools a layer of a volume's contents. The base layer holds all
+// of them, and any other holds the changes made to the volume's parent.
+func (repo *repository) ociLayer(volume FilesystemLiveVolume, base bool) (*oci.Layer, error) {
+	privileged, err := volume.LoadPrivileged()
+	if err != nil {
+		return nil, err
+	}
+
+	namespacer := repo.namespacer(false)
+
+	if base {
+		return oci.NewLayer(repo.filesystem.TempDir(), func(w io.Writer) error {
+			return createTar(w, volume.DataPath(), ".", privileged, namespacer)
+		})
+	}
+
+	changes, err := volume.Diff()
+	if err != nil {
+		return nil, err
+	}
+
+	return oci.NewLayer(repo.filesystem.TempDir(), func(w io.Writer) error {
+		return createLayer(w, volume.DataPath(), ".", changes, privileged, namespacer)
+	})
+}
+
+// announceLayers tells subscribers about the image layer volumes a strategy
+// created beneath a volume, base first.
+func (repo *repository) announceLayers(logger lager.Logger, volume FilesystemLiveVolume) {
+	layerVolumes := []FilesystemLiveVolume{}
+
+	for {
+		digest, err := volume.LoadLayer()
+		if err != nil {
+			logger.Error("failed-to-load-layer", err)
+			break
+		}
+
+		if digest == "" {
+			break
+		}
+
+		layerVolumes = append([]FilesystemLiveVolume{volume}, layerVolumes...)
+
+		parent, found, err := volume.Parent()
+		if err != nil {
+			logger.Error("failed-to-get-parent", err)
+			break
+		}
+
+		if !found {
+			break
+		}
+
+		volume = parent
+	}
+
+	for _, layerVolume := range layerVolumes {
+		repo.events.Publish(Event{Type: EventCreated, Handle: layerVolume.Handle()})
+		repo.events.Publish(Event{Type: EventInitialized, Handle: layerVolume.Handle()})
+	}
+}
+
+// releaseLayers destroys the image layer volumes beneath a volume that has
+// been destroyed, from the top down, for as long as nothing else is layered
+// on them.
+func (repo *repository) releaseLayers(logger lager.Logger, volume FilesystemLiveVolume) {
+	for volume != nil {
+		volume = repo.releaseLayer(logger, volume)
+	}
+}
+
+// releaseLayer destroys the volume if it is an image layer volume with
+// nothing layered on it, returning its parent if so.
+func (repo *repository) releaseLayer(logger lager.Logger, volume FilesystemLiveVolume) FilesystemLiveVolume {
+	handle := volume.Handle()
+
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	logger = logger.Session("release-layer", lager.Data{
+		"layer-volume": handle,
+	})
+
+	digest, err := volume.LoadLayer()
+	if err != nil {
+		logger.Error("failed-to-load-layer", err)
+		return nil
+	}
+
+	if digest == "" {
+		return nil
+	}
+
+	hasChildren, err := repo.hasChildren(handle)
+	if err != nil {
+		logger.Error("failed-to-list-children", err)
+		return nil
+	}
+
+	if hasChildren {
+		return nil
+	}
+
+	parent, _, err := volume.Parent()
+	if err != nil {
+		logger.Error("failed-to-get-parent", err)
+		return nil
+	}
+
+	err = volume.Destroy()
+	if err != nil {
+		logger.Error("failed-to-destroy", err)
+		return nil
+	}
+
+	logger.Info("destroyed")
+
+	repo.events.Publish(Event{Type: EventDestroyed, Handle: handle})
+
+	return parent
+}
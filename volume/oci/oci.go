@@ -0,0 +1,453 @@
+// Package oci reads and writes OCI image layouts holding volume contents, so
+// that volumes can be pushed to and pulled from container registries.
+//
+// Only what is needed to describe a volume is supported: an image layout
+// with a single manifest per image, whose layers are gzipped tarballs.
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+)
+
+const (
+	MediaTypeImageIndex     = "application/vnd.oci.image.index.v1+json"
+	MediaTypeImageManifest  = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeImageConfig    = "application/vnd.oci.image.config.v1+json"
+	MediaTypeImageLayerGzip = "application/vnd.oci.image.layer.v1.tar+gzip"
+
+	// MediaTypeDockerLayerGzip is what Docker calls a gzipped layer, which
+	// is found in layouts converted from Docker images.
+	MediaTypeDockerLayerGzip = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+
+	// AnnotationRefName names an image within a layout.
+	AnnotationRefName = "org.opencontainers.image.ref.name"
+)
+
+const (
+	layoutFile    = "oci-layout"
+	indexFile     = "index.json"
+	layoutVersion = "1.0.0"
+)
+
+var ErrImageNotFound = errors.New("image not found in layout")
+var ErrAmbiguousImage = errors.New("layout holds more than one image")
+var ErrInvalidDigest = errors.New("invalid digest")
+
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// DigestMismatchError is returned when a blob's contents do not match the
+// digest or size it is referred to by.
+type DigestMismatchError struct {
+	Digest string
+}
+
+func (err DigestMismatchError) Error() string {
+	return fmt.Sprintf("blob does not match %s", err.Digest)
+}
+
+// UnsupportedMediaTypeError is returned for manifests and layers which are
+// not of a supported type.
+type UnsupportedMediaTypeError struct {
+	MediaType string
+}
+
+func (err UnsupportedMediaTypeError) Error() string {
+	return fmt.Sprintf("unsupported media type %q", err.MediaType)
+}
+
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type Index struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Manifests     []Descriptor `json:"manifests"`
+}
+
+type Manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Config        Descriptor   `json:"config"`
+	Layers        []Descriptor `json:"layers"`
+}
+
+// Image is the image configuration, which for a volume records little more
+// than its layers.
+type Image struct {
+	Architecture string      `json:"architecture"`
+	OS           string      `json:"os"`
+	Config       ImageConfig `json:"config"`
+	RootFS       RootFS      `json:"rootfs"`
+}
+
+type ImageConfig struct {
+	Labels map[string]string `json:"Labels,omitempty"`
+}
+
+type RootFS struct {
+	Type    string   `json:"type"`
+	DiffIDs []string `json:"diff_ids"`
+}
+
+// Layer is a gzipped layer blob spooled to a temporary file so that its
+// digest and size are known before it is written to a layout.
+type Layer struct {
+	Descriptor Descriptor
+
+	// DiffID is the digest of the uncompressed tar stream.
+	DiffID string
+
+	file *os.File
+}
+
+// NewLayer spools the tar stream written by write as a gzipped layer within
+// dir. The layer must be closed once it is no longer needed.
+func NewLayer(dir string, write func(io.Writer) error) (*Layer, error) {
+	file, err := ioutil.TempFile(dir, "oci-layer")
+	if err != nil {
+		return nil, err
+	}
+
+	layer := &Layer{file: file}
+
+	blobDigest := sha256.New()
+	blob := &countingWriter{Writer: io.MultiWriter(file, blobDigest)}
+
+	diffID := sha256.New()
+	gzipWriter := gzip.NewWriter(blob)
+
+	err = write(io.MultiWriter(gzipWriter, diffID))
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+
+	if err != nil {
+		_ = layer.Close()
+		return nil, err
+	}
+
+	layer.Descriptor = Descriptor{
+		MediaType: MediaTypeImageLayerGzip,
+		Digest:    digestOf(blobDigest),
+		Size:      blob.n,
+	}
+
+	layer.DiffID = digestOf(diffID)
+
+	return layer, nil
+}
+
+// Close removes the spooled layer.
+func (layer *Layer) Close() error {
+	err := layer.file.Close()
+
+	removeErr := os.Remove(layer.file.Name())
+	if err == nil {
+		err = removeErr
+	}
+
+	return err
+}
+
+// WriteArchive writes a tar stream to w of an image layout holding a single
+// image made of the given layers, base first, named ref.
+func WriteArchive(w io.Writer, layers []*Layer, labels map[string]string, ref string) error {
+	image := Image{
+		Architecture: runtime.GOARCH,
+		OS:           "linux",
+		Config: ImageConfig{
+			Labels: labels,
+		},
+		RootFS: RootFS{
+			Type:    "layers",
+			DiffIDs: []string{},
+		},
+	}
+
+	manifest := Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Layers:        []Descriptor{},
+	}
+
+	for _, layer := range layers {
+		image.RootFS.DiffIDs = append(image.RootFS.DiffIDs, layer.DiffID)
+		manifest.Layers = append(manifest.Layers, layer.Descriptor)
+	}
+
+	configBlob, err := json.Marshal(image)
+	if err != nil {
+		return err
+	}
+
+	manifest.Config = descriptorOf(MediaTypeImageConfig, configBlob)
+
+	manifestBlob, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	manifestDescriptor := descriptorOf(MediaTypeImageManifest, manifestBlob)
+	if ref != "" {
+		manifestDescriptor.Annotations = map[string]string{
+			AnnotationRefName: ref,
+		}
+	}
+
+	indexBlob, err := json.Marshal(Index{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageIndex,
+		Manifests:     []Descriptor{manifestDescriptor},
+	})
+	if err != nil {
+		return err
+	}
+
+	layoutBlob, err := json.Marshal(struct {
+		ImageLayoutVersion string `json:"imageLayoutVersion"`
+	}{layoutVersion})
+	if err != nil {
+		return err
+	}
+
+	tarWriter := tar.NewWriter(w)
+
+	err = writeFile(tarWriter, layoutFile, layoutBlob)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{"blobs/", "blobs/sha256/"} {
+		err = tarWriter.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     dir,
+			Mode:     0755,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	written := map[string]bool{}
+	for _, layer := range layers {
+		// the same changes may have been made in more than one layer
+		if written[layer.Descriptor.Digest] {
+			continue
+		}
+
+		written[layer.Descriptor.Digest] = true
+
+		err = writeLayer(tarWriter, layer)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = writeFile(tarWriter, blobName(manifest.Config.Digest), configBlob)
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(tarWriter, blobName(manifestDescriptor.Digest), manifestBlob)
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(tarWriter, indexFile, indexBlob)
+	if err != nil {
+		return err
+	}
+
+	return tarWriter.Close()
+}
+
+func writeLayer(tarWriter *tar.Writer, layer *Layer) error {
+	_, err := layer.file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     blobName(layer.Descriptor.Digest),
+		Mode:     0644,
+		Size:     layer.Descriptor.Size,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.CopyN(tarWriter, layer.file, layer.Descriptor.Size)
+	return err
+}
+
+func writeFile(tarWriter *tar.Writer, name string, content []byte) error {
+	err := tarWriter.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(content)),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tarWriter.Write(content)
+	return err
+}
+
+// ReadManifest reads the manifest of the image named ref in the layout at
+// dir, or of its only image if ref is empty.
+func ReadManifest(dir string, ref string) (Manifest, error) {
+	var index Index
+	err := readJSON(filepath.Join(dir, indexFile), &index)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var found []Descriptor
+	for _, descriptor := range index.Manifests {
+		if ref == "" || descriptor.Annotations[AnnotationRefName] == ref {
+			found = append(found, descriptor)
+		}
+	}
+
+	if len(found) == 0 {
+		return Manifest{}, ErrImageNotFound
+	}
+
+	if len(found) > 1 {
+		return Manifest{}, ErrAmbiguousImage
+	}
+
+	if found[0].MediaType != MediaTypeImageManifest {
+		return Manifest{}, UnsupportedMediaTypeError{found[0].MediaType}
+	}
+
+	blob, err := OpenBlob(dir, found[0])
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	defer blob.Close()
+
+	var manifest Manifest
+	err = json.NewDecoder(blob).Decode(&manifest)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	// make sure the whole manifest matched its digest
+	_, err = io.Copy(ioutil.Discard, blob)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return manifest, nil
+}
+
+// OpenBlob opens the blob described by descriptor in the layout at dir. A
+// DigestMismatchError is returned once the blob has been read to the end if
+// it does not match the descriptor.
+func OpenBlob(dir string, descriptor Descriptor) (io.ReadCloser, error) {
+	if !digestPattern.MatchString(descriptor.Digest) {
+		return nil, ErrInvalidDigest
+	}
+
+	file, err := os.Open(filepath.Join(dir, filepath.FromSlash(blobName(descriptor.Digest))))
+	if err != nil {
+		return nil, err
+	}
+
+	return &verifyingReader{
+		file:       file,
+		descriptor: descriptor,
+		hash:       sha256.New(),
+	}, nil
+}
+
+type verifyingReader struct {
+	file       *os.File
+	descriptor Descriptor
+	hash       hash.Hash
+
+	read int64
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.file.Read(p)
+	r.hash.Write(p[:n])
+	r.read += int64(n)
+
+	if r.read > r.descriptor.Size {
+		return n, DigestMismatchError{r.descriptor.Digest}
+	}
+
+	if err == io.EOF && (r.read != r.descriptor.Size || digestOf(r.hash) != r.descriptor.Digest) {
+		return n, DigestMismatchError{r.descriptor.Digest}
+	}
+
+	return n, err
+}
+
+func (r *verifyingReader) Close() error {
+	return r.file.Close()
+}
+
+func readJSON(path string, dest interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	return json.NewDecoder(file).Decode(dest)
+}
+
+func blobName(digest string) string {
+	return "blobs/sha256/" + digest[len("sha256:"):]
+}
+
+func descriptorOf(mediaType string, blob []byte) Descriptor {
+	sum := sha256.Sum256(blob)
+
+	return Descriptor{
+		MediaType: mediaType,
+		Digest:    "sha256:" + hex.EncodeToString(sum[:]),
+		Size:      int64(len(blob)),
+	}
+}
+
+func digestOf(h hash.Hash) string {
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+type countingWriter struct {
+	io.Writer
+
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.n += int64(n)
+	return n, err
+}
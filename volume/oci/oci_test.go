@@ -0,0 +1,236 @@
+package oci_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/volume/oci"
+)
+
+var _ = Describe("OCI image layouts", func() {
+	var (
+		layoutDir string
+		spoolDir  string
+		layers    []*oci.Layer
+	)
+
+	newLayer := func(name string, content string) *oci.Layer {
+		layer, err := oci.NewLayer(spoolDir, func(w io.Writer) error {
+			tarWriter := tar.NewWriter(w)
+
+			err := tarWriter.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     name,
+				Mode:     0644,
+				Size:     int64(len(content)),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = tarWriter.Write([]byte(content))
+			if err != nil {
+				return err
+			}
+
+			return tarWriter.Close()
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		return layer
+	}
+
+	// unpack writes out the layout in the archive the way a client would
+	unpack := func(archive io.Reader) {
+		tarReader := tar.NewReader(archive)
+		for {
+			hdr, err := tarReader.Next()
+			if err == io.EOF {
+				return
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+
+			path := filepath.Join(layoutDir, hdr.Name)
+			if hdr.Typeflag == tar.TypeDir {
+				Expect(os.MkdirAll(path, 0755)).To(Succeed())
+				continue
+			}
+
+			content, err := ioutil.ReadAll(tarReader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ioutil.WriteFile(path, content, 0644)).To(Succeed())
+		}
+	}
+
+	readBlob := func(descriptor oci.Descriptor) ([]byte, error) {
+		blob, err := oci.OpenBlob(layoutDir, descriptor)
+		Expect(err).ToNot(HaveOccurred())
+
+		defer blob.Close()
+
+		return ioutil.ReadAll(blob)
+	}
+
+	BeforeEach(func() {
+		var err error
+		layoutDir, err = ioutil.TempDir("", "oci-layout")
+		Expect(err).ToNot(HaveOccurred())
+
+		spoolDir, err = ioutil.TempDir("", "oci-spool")
+		Expect(err).ToNot(HaveOccurred())
+
+		layers = []*oci.Layer{
+			newLayer("base-file", "base"),
+			newLayer("top-file", "top"),
+		}
+	})
+
+	AfterEach(func() {
+		for _, layer := range layers {
+			Expect(layer.Close()).To(Succeed())
+		}
+
+		Expect(os.RemoveAll(layoutDir)).To(Succeed())
+
+		entries, err := ioutil.ReadDir(spoolDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+
+		Expect(os.RemoveAll(spoolDir)).To(Succeed())
+	})
+
+	writeLayout := func(ref string) {
+		buf := new(bytes.Buffer)
+		err := oci.WriteArchive(buf, layers, map[string]string{"some": "label"}, ref)
+		Expect(err).ToNot(HaveOccurred())
+
+		unpack(buf)
+	}
+
+	It("spools layers within the given directory", func() {
+		entries, err := ioutil.ReadDir(spoolDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(len(layers)))
+	})
+
+	It("writes a layout that can be read back", func() {
+		writeLayout("some-ref")
+
+		Expect(ioutil.ReadFile(filepath.Join(layoutDir, "oci-layout"))).To(MatchJSON(`{"imageLayoutVersion":"1.0.0"}`))
+
+		manifest, err := oci.ReadManifest(layoutDir, "some-ref")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manifest.Layers).To(Equal([]oci.Descriptor{
+			layers[0].Descriptor,
+			layers[1].Descriptor,
+		}))
+
+		configBlob, err := readBlob(manifest.Config)
+		Expect(err).ToNot(HaveOccurred())
+
+		var image oci.Image
+		Expect(json.Unmarshal(configBlob, &image)).To(Succeed())
+		Expect(image.OS).To(Equal("linux"))
+		Expect(image.Config.Labels).To(Equal(map[string]string{"some": "label"}))
+		Expect(image.RootFS).To(Equal(oci.RootFS{
+			Type:    "layers",
+			DiffIDs: []string{layers[0].DiffID, layers[1].DiffID},
+		}))
+
+		layerBlob, err := readBlob(manifest.Layers[1])
+		Expect(err).ToNot(HaveOccurred())
+
+		gzipReader, err := gzip.NewReader(bytes.NewReader(layerBlob))
+		Expect(err).ToNot(HaveOccurred())
+
+		tarReader := tar.NewReader(gzipReader)
+		hdr, err := tarReader.Next()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hdr.Name).To(Equal("top-file"))
+		Expect(ioutil.ReadAll(tarReader)).To(Equal([]byte("top")))
+	})
+
+	It("reads the only image in a layout without a ref", func() {
+		writeLayout("some-ref")
+
+		manifest, err := oci.ReadManifest(layoutDir, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Layers).To(HaveLen(2))
+	})
+
+	It("returns ErrImageNotFound for an unknown ref", func() {
+		writeLayout("some-ref")
+
+		_, err := oci.ReadManifest(layoutDir, "bogus-ref")
+		Expect(err).To(Equal(oci.ErrImageNotFound))
+	})
+
+	Context("when the layout holds more than one image", func() {
+		BeforeEach(func() {
+			writeLayout("some-ref")
+
+			var index oci.Index
+			indexBlob, err := ioutil.ReadFile(filepath.Join(layoutDir, "index.json"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Unmarshal(indexBlob, &index)).To(Succeed())
+
+			other := index.Manifests[0]
+			other.Annotations = map[string]string{oci.AnnotationRefName: "other-ref"}
+			index.Manifests = append(index.Manifests, other)
+
+			indexBlob, err = json.Marshal(index)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(layoutDir, "index.json"), indexBlob, 0644)).To(Succeed())
+		})
+
+		It("reads the image named by the ref", func() {
+			_, err := oci.ReadManifest(layoutDir, "other-ref")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns ErrAmbiguousImage without a ref", func() {
+			_, err := oci.ReadManifest(layoutDir, "")
+			Expect(err).To(Equal(oci.ErrAmbiguousImage))
+		})
+	})
+
+	Describe("OpenBlob", func() {
+		BeforeEach(func() {
+			writeLayout("")
+		})
+
+		It("returns a DigestMismatchError for a corrupted blob", func() {
+			descriptor := layers[0].Descriptor
+
+			blobPath := filepath.Join(layoutDir, "blobs", "sha256", descriptor.Digest[len("sha256:"):])
+			Expect(ioutil.WriteFile(blobPath, bytes.Repeat([]byte("x"), int(descriptor.Size)), 0644)).To(Succeed())
+
+			_, err := readBlob(descriptor)
+			Expect(err).To(Equal(oci.DigestMismatchError{Digest: descriptor.Digest}))
+		})
+
+		It("returns a DigestMismatchError for a blob larger than described", func() {
+			descriptor := layers[0].Descriptor
+			descriptor.Size--
+
+			_, err := readBlob(descriptor)
+			Expect(err).To(Equal(oci.DigestMismatchError{Digest: descriptor.Digest}))
+		})
+
+		It("returns ErrInvalidDigest for a digest that is not a sha256", func() {
+			_, err := oci.OpenBlob(layoutDir, oci.Descriptor{Digest: "sha256:../../etc/passwd"})
+			Expect(err).To(Equal(oci.ErrInvalidDigest))
+		})
+	})
+})
@@ -0,0 +1,202 @@
+package volume
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"code.cloudfoundry.org/lager"
+
+	"github.com/concourse/baggageclaim/volume/oci"
+)
+
+var ErrNoImageLayoutProvided = errors.New("no image layout provided")
+
+// OCIStrategy creates a volume from an image in an OCI image layout on the
+// host, e.g. one pulled from a registry, verifying each blob against its
+// digest.
+//
+// Where the driver's copy-on-write layers share their ancestors' data, each
+// of the image's layers but the top one is applied to a read-only volume of
+// its own, with each volume a copy-on-write child of the one below and the
+// created volume a child of the last. The layer volumes are recorded as such
+// so that they are destroyed along with the last volume layered on them.
+// Otherwise the layers are applied in turn to the created volume.
+type OCIStrategy struct {
+	Path string
+
+	// Ref names the image within the layout. It may be left empty if the
+	// layout holds a single image.
+	Ref string
+}
+
+func (strategy OCIStrategy) Materialize(logger lager.Logger, handle string, fs Filesystem, streamer Streamer) (FilesystemInitVolume, error) {
+	if strategy.Path == "" {
+		logger.Info("image-layout-not-specified")
+		return nil, ErrNoImageLayoutProvided
+	}
+
+	manifest, err := oci.ReadManifest(strategy.Path, strategy.Ref)
+	if err != nil {
+		logger.Error("failed-to-read-image-manifest", err)
+		return nil, err
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != oci.MediaTypeImageLayerGzip && layer.MediaType != oci.MediaTypeDockerLayerGzip {
+			logger.Info("unsupported-layer-media-type", lager.Data{
+				"media-type": layer.MediaType,
+			})
+
+			return nil, oci.UnsupportedMediaTypeError{MediaType: layer.MediaType}
+		}
+	}
+
+	layers := manifest.Layers
+
+	// the volumes holding the lower layers, base first
+	layerVolumes := []FilesystemLiveVolume{}
+	destroyLayerVolumes := func() {
+		for i := len(layerVolumes) - 1; i >= 0; i-- {
+			err := layerVolumes[i].Destroy()
+			if err != nil {
+				logger.Error("failed-to-destroy-layer-volume", err, lager.Data{
+					"layer-volume": layerVolumes[i].Handle(),
+				})
+			}
+		}
+	}
+
+	if fs.SharesLayers() && len(layers) > 1 {
+		for i, layer := range layers[:len(layers)-1] {
+			layerVolume, err := strategy.newLayerVolume(logger.WithData(lager.Data{
+				"digest": layer.Digest,
+			}), fmt.Sprintf("%s-layer-%d", handle, i), layer, fs, layerVolumes, streamer)
+			if err != nil {
+				destroyLayerVolumes()
+				return nil, err
+			}
+
+			layerVolumes = append(layerVolumes, layerVolume)
+		}
+
+		layers = layers[len(layers)-1:]
+	}
+
+	initVolume, err := newVolumeOn(handle, fs, layerVolumes)
+	if err != nil {
+		destroyLayerVolumes()
+		return nil, err
+	}
+
+	for _, layer := range layers {
+		err := strategy.applyLayer(logger.WithData(lager.Data{
+			"digest": layer.Digest,
+		}), layer, initVolume.DataPath(), streamer)
+		if err != nil {
+			initVolume.Destroy()
+			destroyLayerVolumes()
+			return nil, err
+		}
+	}
+
+	return initVolume, nil
+}
+
+// newLayerVolume creates a read-only volume holding the layer, layered on the
+// last of the given layer volumes.
+func (strategy OCIStrategy) newLayerVolume(logger lager.Logger, handle string, layer oci.Descriptor, fs Filesystem, below []FilesystemLiveVolume, streamer Streamer) (FilesystemLiveVolume, error) {
+	initVolume, err := newVolumeOn(handle, fs, below)
+	if err != nil {
+		logger.Error("failed-to-create-layer-volume", err)
+		return nil, err
+	}
+
+	err = strategy.applyLayer(logger, layer, initVolume.DataPath(), streamer)
+	if err != nil {
+		initVolume.Destroy()
+		return nil, err
+	}
+
+	// layers are applied as they are, so their contents are only fit for
+	// privileged use
+	err = initVolume.StoreProperties(Properties{})
+	if err == nil {
+		err = initVolume.StorePrivileged(true)
+	}
+
+	if err == nil {
+		err = initVolume.StoreLayer(layer.Digest)
+	}
+
+	if err != nil {
+		logger.Error("failed-to-store-layer-volume-metadata", err)
+		initVolume.Destroy()
+		return nil, err
+	}
+
+	liveVolume, err := initVolume.Initialize()
+	if err != nil {
+		logger.Error("failed-to-initialize-layer-volume", err)
+		initVolume.Destroy()
+		return nil, err
+	}
+
+	err = liveVolume.SetReadOnly()
+	if err != nil {
+		logger.Error("failed-to-set-layer-volume-read-only", err)
+		liveVolume.Destroy()
+		return nil, err
+	}
+
+	return liveVolume, nil
+}
+
+// newVolumeOn creates a volume as a copy-on-write child of the last of the
+// given volumes, or an empty one if there are none.
+func newVolumeOn(handle string, fs Filesystem, below []FilesystemLiveVolume) (FilesystemInitVolume, error) {
+	if len(below) == 0 {
+		return fs.NewVolume(handle)
+	}
+
+	return below[len(below)-1].NewSubvolume(handle)
+}
+
+func (strategy OCIStrategy) applyLayer(logger lager.Logger, layer oci.Descriptor, dest string, streamer Streamer) error {
+	blob, err := oci.OpenBlob(strategy.Path, layer)
+	if err != nil {
+		logger.Error("failed-to-open-layer", err)
+		return err
+	}
+
+	defer blob.Close()
+
+	invalid, err := streamer.InLayer(blob, dest, ".", true)
+	if err != nil {
+		var mismatch oci.DigestMismatchError
+		if errors.As(err, &mismatch) {
+			logger.Info("layer-does-not-match-digest")
+			return mismatch
+		}
+
+		if invalid {
+			logger.Info("malformed-layer", lager.Data{
+				"error": err.Error(),
+			})
+		} else {
+			logger.Error("failed-to-apply-layer", err)
+		}
+
+		return err
+	}
+
+	// the layer may be read only as far as the end of its archive
+	_, err = io.Copy(ioutil.Discard, blob)
+	if err != nil {
+		logger.Info("layer-does-not-match-digest")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package volume_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/oci"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OCIStrategy", func() {
+	var (
+		strategy Strategy
+
+		layoutDir string
+		layers    []oci.Descriptor
+	)
+
+	BeforeEach(func() {
+		var err error
+		layoutDir, err = ioutil.TempDir("", "oci-layout")
+		Expect(err).ToNot(HaveOccurred())
+
+		var spooled []*oci.Layer
+		for _, content := range []string{"base-layer", "top-layer"} {
+			content := content
+
+			layer, err := oci.NewLayer(layoutDir, func(w io.Writer) error {
+				_, err := w.Write([]byte(content))
+				return err
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			defer layer.Close()
+
+			spooled = append(spooled, layer)
+		}
+
+		buf := new(bytes.Buffer)
+		Expect(oci.WriteArchive(buf, spooled, nil, "some-ref")).To(Succeed())
+
+		layers = []oci.Descriptor{spooled[0].Descriptor, spooled[1].Descriptor}
+
+		tarReader := tar.NewReader(buf)
+		for {
+			hdr, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+
+			path := filepath.Join(layoutDir, hdr.Name)
+			if hdr.Typeflag == tar.TypeDir {
+				Expect(os.MkdirAll(path, 0755)).To(Succeed())
+				continue
+			}
+
+			content, err := ioutil.ReadAll(tarReader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ioutil.WriteFile(path, content, 0644)).To(Succeed())
+		}
+
+		strategy = OCIStrategy{
+			Path: layoutDir,
+			Ref:  "some-ref",
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(layoutDir)
+	})
+
+	Describe("Materialize", func() {
+		var (
+			fakeFilesystem *volumefakes.FakeFilesystem
+			fakeStreamer   *volumefakes.FakeStreamer
+			fakeVolume     *volumefakes.FakeFilesystemInitVolume
+
+			appliedLayers [][]byte
+
+			materializedVolume FilesystemInitVolume
+			materializeErr     error
+		)
+
+		BeforeEach(func() {
+			fakeVolume = new(volumefakes.FakeFilesystemInitVolume)
+			fakeVolume.DataPathReturns("/some/data/path")
+
+			fakeFilesystem = new(volumefakes.FakeFilesystem)
+			fakeFilesystem.NewVolumeReturns(fakeVolume, nil)
+
+			appliedLayers = nil
+
+			fakeStreamer = new(volumefakes.FakeStreamer)
+			fakeStreamer.InLayerStub = func(stream io.Reader, root string, dest string, privileged bool) (bool, error) {
+				layer, err := ioutil.ReadAll(stream)
+				appliedLayers = append(appliedLayers, layer)
+				return false, err
+			}
+		})
+
+		JustBeforeEach(func() {
+			materializedVolume, materializeErr = strategy.Materialize(
+				lagertest.NewTestLogger("test"),
+				"some-volume",
+				fakeFilesystem,
+				fakeStreamer,
+			)
+		})
+
+		It("creates a new volume", func() {
+			Expect(materializeErr).ToNot(HaveOccurred())
+			Expect(materializedVolume).To(Equal(fakeVolume))
+
+			Expect(fakeFilesystem.NewVolumeArgsForCall(0)).To(Equal("some-volume"))
+		})
+
+		It("applies each layer in turn, base first", func() {
+			Expect(fakeStreamer.InLayerCallCount()).To(Equal(2))
+			Expect(appliedLayers).To(HaveLen(2))
+
+			for i := range layers {
+				_, root, dest, privileged := fakeStreamer.InLayerArgsForCall(i)
+				Expect(root).To(Equal("/some/data/path"))
+				Expect(dest).To(Equal("."))
+				Expect(privileged).To(BeTrue())
+			}
+
+			Expect(appliedLayers[0]).ToNot(Equal(appliedLayers[1]))
+		})
+
+		Context("when a layer does not match its digest", func() {
+			BeforeEach(func() {
+				blobPath := filepath.Join(layoutDir, "blobs", "sha256", strings.TrimPrefix(layers[1].Digest, "sha256:"))
+				Expect(ioutil.WriteFile(blobPath, bytes.Repeat([]byte("x"), int(layers[1].Size)), 0644)).To(Succeed())
+			})
+
+			It("returns a DigestMismatchError", func() {
+				Expect(materializeErr).To(Equal(oci.DigestMismatchError{Digest: layers[1].Digest}))
+			})
+
+			It("destroys the volume", func() {
+				Expect(fakeVolume.DestroyCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when applying a layer fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeStreamer.InLayerStub = nil
+				fakeStreamer.InLayerReturns(true, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(materializeErr).To(Equal(disaster))
+			})
+
+			It("destroys the volume", func() {
+				Expect(fakeVolume.DestroyCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the driver shares layers", func() {
+			var (
+				fakeLayerInitVolume *volumefakes.FakeFilesystemInitVolume
+				fakeLayerVolume     *volumefakes.FakeFilesystemLiveVolume
+			)
+
+			BeforeEach(func() {
+				fakeFilesystem.SharesLayersReturns(true)
+
+				fakeLayerVolume = new(volumefakes.FakeFilesystemLiveVolume)
+				fakeLayerVolume.HandleReturns("some-volume-layer-0")
+				fakeLayerVolume.NewSubvolumeReturns(fakeVolume, nil)
+
+				fakeLayerInitVolume = new(volumefakes.FakeFilesystemInitVolume)
+				fakeLayerInitVolume.DataPathReturns("/some/layer/data/path")
+				fakeLayerInitVolume.InitializeReturns(fakeLayerVolume, nil)
+
+				fakeFilesystem.NewVolumeReturns(fakeLayerInitVolume, nil)
+			})
+
+			It("applies the base layer to a read-only layer volume", func() {
+				Expect(materializeErr).ToNot(HaveOccurred())
+
+				Expect(fakeFilesystem.NewVolumeCallCount()).To(Equal(1))
+				Expect(fakeFilesystem.NewVolumeArgsForCall(0)).To(Equal("some-volume-layer-0"))
+
+				_, root, _, _ := fakeStreamer.InLayerArgsForCall(0)
+				Expect(root).To(Equal("/some/layer/data/path"))
+
+				Expect(fakeLayerInitVolume.StorePrivilegedArgsForCall(0)).To(BeTrue())
+				Expect(fakeLayerInitVolume.StoreLayerArgsForCall(0)).To(Equal(layers[0].Digest))
+				Expect(fakeLayerInitVolume.InitializeCallCount()).To(Equal(1))
+				Expect(fakeLayerVolume.SetReadOnlyCallCount()).To(Equal(1))
+			})
+
+			It("creates the volume as a child of the layer volume, applying the top layer to it", func() {
+				Expect(materializedVolume).To(Equal(fakeVolume))
+
+				Expect(fakeLayerVolume.NewSubvolumeCallCount()).To(Equal(1))
+				Expect(fakeLayerVolume.NewSubvolumeArgsForCall(0)).To(Equal("some-volume"))
+
+				Expect(fakeStreamer.InLayerCallCount()).To(Equal(2))
+				_, root, _, _ := fakeStreamer.InLayerArgsForCall(1)
+				Expect(root).To(Equal("/some/data/path"))
+
+				Expect(appliedLayers[0]).ToNot(Equal(appliedLayers[1]))
+			})
+
+			Context("when applying the top layer fails", func() {
+				BeforeEach(func() {
+					blobPath := filepath.Join(layoutDir, "blobs", "sha256", strings.TrimPrefix(layers[1].Digest, "sha256:"))
+					Expect(ioutil.WriteFile(blobPath, bytes.Repeat([]byte("x"), int(layers[1].Size)), 0644)).To(Succeed())
+				})
+
+				It("destroys the volume and the layer volume", func() {
+					Expect(materializeErr).To(Equal(oci.DigestMismatchError{Digest: layers[1].Digest}))
+
+					Expect(fakeVolume.DestroyCallCount()).To(Equal(1))
+					Expect(fakeLayerVolume.DestroyCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when applying the base layer fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					fakeStreamer.InLayerStub = nil
+					fakeStreamer.InLayerReturns(true, disaster)
+				})
+
+				It("destroys the layer volume without creating the volume", func() {
+					Expect(materializeErr).To(Equal(disaster))
+
+					Expect(fakeLayerInitVolume.DestroyCallCount()).To(Equal(1))
+					Expect(fakeLayerVolume.NewSubvolumeCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("when the image is not in the layout", func() {
+			BeforeEach(func() {
+				strategy = OCIStrategy{
+					Path: layoutDir,
+					Ref:  "bogus-ref",
+				}
+			})
+
+			It("returns ErrImageNotFound without creating a volume", func() {
+				Expect(materializeErr).To(Equal(oci.ErrImageNotFound))
+				Expect(fakeFilesystem.NewVolumeCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when no layout is given", func() {
+			BeforeEach(func() {
+				strategy = OCIStrategy{}
+			})
+
+			It("returns ErrNoImageLayoutProvided", func() {
+				Expect(materializeErr).To(Equal(ErrNoImageLayoutProvided))
+			})
+		})
+	})
+})
@@ -0,0 +1,191 @@
+package volume_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/uidgid/uidgidfakes"
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/oci"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+)
+
+var _ = Describe("StreamOutOCI", func() {
+	var (
+		fakeFilesystem *volumefakes.FakeFilesystem
+		parentVolume   *volumefakes.FakeFilesystemLiveVolume
+		childVolume    *volumefakes.FakeFilesystemLiveVolume
+
+		repository volume.Repository
+
+		parentDir string
+		childDir  string
+	)
+
+	BeforeEach(func() {
+		var err error
+		parentDir, err = ioutil.TempDir("", "oci-parent")
+		Expect(err).ToNot(HaveOccurred())
+
+		childDir, err = ioutil.TempDir("", "oci-child")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(ioutil.WriteFile(filepath.Join(parentDir, "parent-file"), []byte("parent"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(childDir, "parent-file"), []byte("parent"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(childDir, "child-file"), []byte("child"), 0644)).To(Succeed())
+
+		parentVolume = new(volumefakes.FakeFilesystemLiveVolume)
+		parentVolume.HandleReturns("parent-handle")
+		parentVolume.DataPathReturns(parentDir)
+		parentVolume.LoadPrivilegedReturns(true, nil)
+
+		childVolume = new(volumefakes.FakeFilesystemLiveVolume)
+		childVolume.HandleReturns("child-handle")
+		childVolume.DataPathReturns(childDir)
+		childVolume.LoadPrivilegedReturns(true, nil)
+		childVolume.LoadPropertiesReturns(volume.Properties{"some": "property"}, nil)
+		childVolume.ParentReturns(parentVolume, true, nil)
+		childVolume.DiffReturns([]volume.Change{
+			{Path: "child-file", Kind: volume.ChangeAdded},
+			{Path: "deleted-file", Kind: volume.ChangeDeleted},
+		}, nil)
+
+		fakeFilesystem = new(volumefakes.FakeFilesystem)
+		fakeFilesystem.LookupVolumeReturns(childVolume, true, nil)
+
+		repository = volume.NewRepository(
+			fakeFilesystem,
+			new(volumefakes.FakeLockManager),
+			new(uidgidfakes.FakeNamespacer),
+			new(uidgidfakes.FakeNamespacer),
+			nil,
+			0,
+		)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(parentDir)
+		os.RemoveAll(childDir)
+	})
+
+	// readLayout returns the files in the layout archive by name
+	readLayout := func(r io.Reader) map[string][]byte {
+		files := map[string][]byte{}
+
+		tarReader := tar.NewReader(r)
+		for {
+			hdr, err := tarReader.Next()
+			if err == io.EOF {
+				return files
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+
+			content, err := ioutil.ReadAll(tarReader)
+			Expect(err).ToNot(HaveOccurred())
+
+			files[hdr.Name] = content
+		}
+	}
+
+	blob := func(files map[string][]byte, digest string) []byte {
+		content, found := files["blobs/sha256/"+strings.TrimPrefix(digest, "sha256:")]
+		Expect(found).To(BeTrue())
+		return content
+	}
+
+	layerNames := func(layer []byte) []string {
+		gzipReader, err := gzip.NewReader(bytes.NewReader(layer))
+		Expect(err).ToNot(HaveOccurred())
+
+		names := []string{}
+
+		tarReader := tar.NewReader(gzipReader)
+		for {
+			hdr, err := tarReader.Next()
+			if err == io.EOF {
+				return names
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+			names = append(names, hdr.Name)
+		}
+	}
+
+	streamOut := func(chain bool) (map[string][]byte, oci.Manifest) {
+		buf := new(bytes.Buffer)
+		err := repository.StreamOutOCI(context.Background(), "child-handle", chain, volume.IdentityEncoding, buf)
+		Expect(err).ToNot(HaveOccurred())
+
+		files := readLayout(buf)
+
+		var index oci.Index
+		Expect(json.Unmarshal(files["index.json"], &index)).To(Succeed())
+		Expect(index.Manifests).To(HaveLen(1))
+		Expect(index.Manifests[0].Annotations).To(HaveKeyWithValue(oci.AnnotationRefName, "child-handle"))
+
+		var manifest oci.Manifest
+		Expect(json.Unmarshal(blob(files, index.Manifests[0].Digest), &manifest)).To(Succeed())
+
+		var image oci.Image
+		Expect(json.Unmarshal(blob(files, manifest.Config.Digest), &image)).To(Succeed())
+		Expect(image.Config.Labels).To(Equal(map[string]string{"some": "property"}))
+		Expect(image.RootFS.DiffIDs).To(HaveLen(len(manifest.Layers)))
+
+		return files, manifest
+	}
+
+	It("streams out an image with a single layer holding the volume's contents", func() {
+		files, manifest := streamOut(false)
+
+		Expect(manifest.Layers).To(HaveLen(1))
+		Expect(layerNames(blob(files, manifest.Layers[0].Digest))).To(Equal([]string{
+			"./",
+			"child-file",
+			"parent-file",
+		}))
+
+		Expect(childVolume.DiffCallCount()).To(BeZero())
+	})
+
+	It("streams out an image with a layer for each volume in the chain", func() {
+		files, manifest := streamOut(true)
+
+		Expect(manifest.Layers).To(HaveLen(2))
+		Expect(layerNames(blob(files, manifest.Layers[0].Digest))).To(Equal([]string{
+			"./",
+			"parent-file",
+		}))
+		Expect(layerNames(blob(files, manifest.Layers[1].Digest))).To(Equal([]string{
+			"child-file",
+			".wh.deleted-file",
+		}))
+	})
+
+	It("returns ErrUnsupportedStreamEncoding for an unknown encoding", func() {
+		err := repository.StreamOutOCI(context.Background(), "child-handle", false, "bogus", ioutil.Discard)
+		Expect(err).To(Equal(volume.ErrUnsupportedStreamEncoding))
+	})
+
+	Context("when the volume does not exist", func() {
+		BeforeEach(func() {
+			fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+		})
+
+		It("returns ErrVolumeDoesNotExist", func() {
+			err := repository.StreamOutOCI(context.Background(), "child-handle", false, volume.IdentityEncoding, ioutil.Discard)
+			Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+		})
+	})
+})
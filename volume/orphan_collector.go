@@ -0,0 +1,62 @@
+package volume
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+)
+
+// OrphanCollector destroys volumes orphaned in the init and dead directories,
+// once at startup and then periodically.
+type OrphanCollector struct {
+	logger   lager.Logger
+	repo     Repository
+	interval time.Duration
+}
+
+func NewOrphanCollector(logger lager.Logger, repo Repository, interval time.Duration) *OrphanCollector {
+	return &OrphanCollector{
+		logger:   logger,
+		repo:     repo,
+		interval: interval,
+	}
+}
+
+func (collector *OrphanCollector) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	collector.Collect()
+
+	ticker := time.NewTicker(collector.interval)
+	defer ticker.Stop()
+
+	close(ready)
+
+	for {
+		select {
+		case <-ticker.C:
+			collector.Collect()
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+// Collect destroys every orphaned volume.
+func (collector *OrphanCollector) Collect() {
+	logger := collector.logger.Session("collect")
+
+	ctx := lagerctx.NewContext(context.Background(), logger)
+
+	reclaimed, err := collector.repo.CollectOrphans(ctx)
+	if err != nil {
+		return
+	}
+
+	if reclaimed > 0 {
+		logger.Info("reclaimed-orphans", lager.Data{
+			"count": reclaimed,
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package volume_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("OrphanCollector", func() {
+	var (
+		fakeRepository *volumefakes.FakeRepository
+
+		collector *volume.OrphanCollector
+	)
+
+	BeforeEach(func() {
+		fakeRepository = new(volumefakes.FakeRepository)
+
+		collector = volume.NewOrphanCollector(lagertest.NewTestLogger("test"), fakeRepository, time.Hour)
+	})
+
+	Describe("Run", func() {
+		var process ifrit.Process
+
+		JustBeforeEach(func() {
+			process = ifrit.Invoke(collector)
+		})
+
+		AfterEach(func() {
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive())
+		})
+
+		It("collects orphans before becoming ready", func() {
+			Expect(fakeRepository.CollectOrphansCallCount()).To(Equal(1))
+		})
+
+		Context("when collecting fails", func() {
+			BeforeEach(func() {
+				fakeRepository.CollectOrphansReturns(0, errors.New("nope"))
+			})
+
+			It("still becomes ready", func() {
+				Consistently(process.Wait()).ShouldNot(Receive())
+			})
+		})
+	})
+})
@@ -0,0 +1,72 @@
+package volume
+
+// parentLockingFilesystem is the Filesystem that strategies materialize
+// volumes on. Creating a subvolume of a volume found through it takes the
+// volume's lock, which is held until unlock is called once the subvolume is
+// initialized or destroyed. Until then the subvolume is not listed among the
+// volume's children, so the lock keeps the volume from being restored to a
+// snapshot or released as a layer from under it.
+type parentLockingFilesystem struct {
+	Filesystem
+
+	locker LockManager
+	locked []string
+}
+
+func (fs *parentLockingFilesystem) LookupVolume(handle string) (FilesystemLiveVolume, bool, error) {
+	volume, found, err := fs.Filesystem.LookupVolume(handle)
+	if err != nil || !found {
+		return volume, found, err
+	}
+
+	return parentLockingVolume{FilesystemLiveVolume: volume, fs: fs}, true, nil
+}
+
+func (fs *parentLockingFilesystem) ListVolumes() ([]FilesystemLiveVolume, error) {
+	volumes, err := fs.Filesystem.ListVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.wrap(volumes), nil
+}
+
+func (fs *parentLockingFilesystem) VolumesWithDigest(digest string) ([]FilesystemLiveVolume, error) {
+	volumes, err := fs.Filesystem.VolumesWithDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.wrap(volumes), nil
+}
+
+// unlock releases the locks taken on the parents of any subvolumes created.
+func (fs *parentLockingFilesystem) unlock() {
+	for _, handle := range fs.locked {
+		fs.locker.Unlock(handle)
+	}
+
+	fs.locked = nil
+}
+
+func (fs *parentLockingFilesystem) wrap(volumes []FilesystemLiveVolume) []FilesystemLiveVolume {
+	wrapped := make([]FilesystemLiveVolume, len(volumes))
+	for i, volume := range volumes {
+		wrapped[i] = parentLockingVolume{FilesystemLiveVolume: volume, fs: fs}
+	}
+
+	return wrapped
+}
+
+type parentLockingVolume struct {
+	FilesystemLiveVolume
+
+	fs *parentLockingFilesystem
+}
+
+func (volume parentLockingVolume) NewSubvolume(handle string) (FilesystemInitVolume, error) {
+	volume.fs.locker.Lock(volume.Handle())
+	volume.fs.locked = append(volume.fs.locked, volume.Handle())
+
+	return volume.FilesystemLiveVolume.NewSubvolume(handle)
+}
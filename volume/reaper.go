@@ -0,0 +1,61 @@
+package volume
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+)
+
+// Reaper periodically destroys volumes whose TTL has run out, along with any
+// of their descendants, and discards upload sessions that have been idle for
+// longer than the upload TTL.
+type Reaper struct {
+	logger    lager.Logger
+	repo      Repository
+	interval  time.Duration
+	uploadTTL time.Duration
+}
+
+// NewReaper returns a Reaper. Upload sessions are kept until their volume is
+// destroyed if uploadTTL is 0.
+func NewReaper(logger lager.Logger, repo Repository, interval time.Duration, uploadTTL time.Duration) *Reaper {
+	return &Reaper{
+		logger:    logger,
+		repo:      repo,
+		interval:  interval,
+		uploadTTL: uploadTTL,
+	}
+}
+
+func (reaper *Reaper) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	ticker := time.NewTicker(reaper.interval)
+	defer ticker.Stop()
+
+	close(ready)
+
+	for {
+		select {
+		case <-ticker.C:
+			reaper.Reap()
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+// Reap destroys every volume and upload session that has expired.
+func (reaper *Reaper) Reap() {
+	logger := reaper.logger.Session("reap")
+
+	ctx := lagerctx.NewContext(context.Background(), logger)
+
+	// failures are logged by the repository
+	_, _ = reaper.repo.ReapVolumes(ctx)
+
+	if reaper.uploadTTL > 0 {
+		_, _ = reaper.repo.ReapUploads(ctx, reaper.uploadTTL)
+	}
+}
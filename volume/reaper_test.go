@@ -0,0 +1,63 @@
+package volume_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reaper", func() {
+	var (
+		fakeRepository *volumefakes.FakeRepository
+
+		reaper *volume.Reaper
+	)
+
+	BeforeEach(func() {
+		fakeRepository = new(volumefakes.FakeRepository)
+
+		reaper = volume.NewReaper(lagertest.NewTestLogger("test"), fakeRepository, 0, time.Hour)
+	})
+
+	Describe("Reap", func() {
+		JustBeforeEach(func() {
+			reaper.Reap()
+		})
+
+		It("reaps the expired volumes", func() {
+			Expect(fakeRepository.ReapVolumesCallCount()).To(Equal(1))
+		})
+
+		Context("when reaping volumes fails", func() {
+			BeforeEach(func() {
+				fakeRepository.ReapVolumesReturns(0, errors.New("nope"))
+			})
+
+			It("still reaps upload sessions", func() {
+				Expect(fakeRepository.ReapUploadsCallCount()).To(Equal(1))
+			})
+		})
+
+		It("reaps upload sessions idle for longer than the upload TTL", func() {
+			Expect(fakeRepository.ReapUploadsCallCount()).To(Equal(1))
+
+			_, ttl := fakeRepository.ReapUploadsArgsForCall(0)
+			Expect(ttl).To(Equal(time.Hour))
+		})
+
+		Context("when there is no upload TTL", func() {
+			BeforeEach(func() {
+				reaper = volume.NewReaper(lagertest.NewTestLogger("test"), fakeRepository, 0, 0)
+			})
+
+			It("keeps upload sessions", func() {
+				Expect(fakeRepository.ReapUploadsCallCount()).To(BeZero())
+			})
+		})
+	})
+})
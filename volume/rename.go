@@ -0,0 +1,197 @@
+package volume
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+)
+
+var ErrVolumeAlreadyExists = errors.New("volume already exists")
+var ErrInvalidVolumeHandle = errors.New("invalid volume handle")
+
+// RenameVolume moves a volume to a new handle, so that a volume which has
+// been populated can be promoted to a well-known handle without copying it.
+// Its copy-on-write children are linked to it under its new handle; if one
+// cannot be, the rename is undone, unless the volume cannot be moved back.
+func (repo *repository) RenameVolume(ctx context.Context, handle string, newHandle string) (Volume, error) {
+	logger := lagerctx.FromContext(ctx).Session("rename-volume", lager.Data{
+		"volume":     handle,
+		"new-handle": newHandle,
+	})
+
+	if !validHandle(newHandle) {
+		logger.Info("invalid-volume-handle")
+		return Volume{}, ErrInvalidVolumeHandle
+	}
+
+	children, unlock, err := repo.lockWithChildren(handle, newHandle)
+	if err != nil {
+		logger.Error("failed-to-find-children", err)
+		return Volume{}, err
+	}
+
+	defer unlock()
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return Volume{}, err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return Volume{}, ErrVolumeDoesNotExist
+	}
+
+	// stream-ins are counted under the old handle, so they must finish before
+	// the volume is moved; new ones wait for the lock held here
+	repo.writers.wait(handle)
+
+	renamed, err := volume.Rename(newHandle)
+	if err != nil {
+		if os.IsExist(err) {
+			logger.Info("volume-already-exists")
+			return Volume{}, ErrVolumeAlreadyExists
+		}
+
+		logger.Error("failed-to-rename-volume", err)
+		return Volume{}, err
+	}
+
+	for i, child := range children {
+		err := child.Reparent(renamed)
+		if err == nil {
+			continue
+		}
+
+		logger.Error("failed-to-reparent-child", err, lager.Data{
+			"child": child.Handle(),
+		})
+
+		undoErr := repo.undoRename(logger, renamed, handle, children[:i])
+		if undoErr == nil {
+			return Volume{}, err
+		}
+
+		// the rename stands, so carry on linking the children to it; those
+		// that cannot be are left for fsck to report
+		logger.Error("failed-to-undo-rename", undoErr)
+
+		for _, child := range children[i+1:] {
+			err := child.Reparent(renamed)
+			if err != nil {
+				logger.Error("failed-to-reparent-child", err, lager.Data{
+					"child": child.Handle(),
+				})
+			}
+		}
+
+		break
+	}
+
+	logger.Info("renamed")
+
+	repo.events.Publish(Event{
+		Type:           EventRenamed,
+		Handle:         newHandle,
+		PreviousHandle: handle,
+	})
+
+	return repo.volumeFrom(renamed)
+}
+
+// undoRename moves a renamed volume back to its handle and links the children
+// that had been moved along with it back to it. It only fails if the volume
+// could not be moved back, in which case the rename stands.
+func (repo *repository) undoRename(logger lager.Logger, renamed FilesystemLiveVolume, handle string, moved []FilesystemLiveVolume) error {
+	restored, err := renamed.Rename(handle)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range moved {
+		err := child.Reparent(restored)
+		if err != nil {
+			logger.Error("failed-to-reparent-child-back", err, lager.Data{
+				"child": child.Handle(),
+			})
+		}
+	}
+
+	logger.Info("undid-rename")
+
+	return nil
+}
+
+// lockWithChildren locks the volume, the given other handles and the
+// volume's copy-on-write children, returning the children and a function
+// which unlocks them all. The children are locked so that nothing, e.g. fsck,
+// finds their parent link dangling while the volume is moved. As children may
+// come and go until they are locked, they are listed again once they are,
+// and locked again if any were missed.
+func (repo *repository) lockWithChildren(handle string, others ...string) ([]FilesystemLiveVolume, func(), error) {
+	children, err := repo.childrenOf(handle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		handles := append([]string{handle}, others...)
+		locked := map[string]bool{}
+		for _, child := range children {
+			handles = append(handles, child.Handle())
+			locked[child.Handle()] = true
+		}
+
+		unlock := repo.lockAll(handles)
+
+		children, err = repo.childrenOf(handle)
+		if err != nil {
+			unlock()
+			return nil, nil, err
+		}
+
+		missed := false
+		for _, child := range children {
+			if !locked[child.Handle()] {
+				missed = true
+			}
+		}
+
+		if !missed {
+			return children, unlock, nil
+		}
+
+		unlock()
+	}
+}
+
+// lockAll locks each of the handles once, in order, so that operations
+// locking several volumes cannot deadlock one another. The returned function
+// unlocks them.
+func (repo *repository) lockAll(handles []string) func() {
+	sorted := []string{}
+	seen := map[string]bool{}
+	for _, handle := range handles {
+		if !seen[handle] {
+			seen[handle] = true
+			sorted = append(sorted, handle)
+		}
+	}
+
+	sort.Strings(sorted)
+
+	for _, handle := range sorted {
+		repo.locker.Lock(handle)
+	}
+
+	return func() {
+		for _, handle := range sorted {
+			repo.locker.Unlock(handle)
+		}
+	}
+}
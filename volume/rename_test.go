@@ -0,0 +1,300 @@
+package volume_test
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/uidgid/uidgidfakes"
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+)
+
+var _ = Describe("Renaming a volume", func() {
+	var (
+		fakeFilesystem *volumefakes.FakeFilesystem
+		fakeLocker     *volumefakes.FakeLockManager
+		fakeVolume     *volumefakes.FakeFilesystemLiveVolume
+		fakeRenamed    *volumefakes.FakeFilesystemLiveVolume
+		fakeChild      *volumefakes.FakeFilesystemLiveVolume
+		fakeStranger   *volumefakes.FakeFilesystemLiveVolume
+
+		repository volume.Repository
+	)
+
+	BeforeEach(func() {
+		fakeRenamed = new(volumefakes.FakeFilesystemLiveVolume)
+		fakeRenamed.HandleReturns("new-handle")
+		fakeRenamed.DataPathReturns("/live/new-handle/volume")
+		fakeRenamed.LoadPropertiesReturns(volume.Properties{"some": "property"}, nil)
+
+		fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+		fakeVolume.HandleReturns("some-handle")
+		fakeVolume.RenameReturns(fakeRenamed, nil)
+
+		fakeChild = new(volumefakes.FakeFilesystemLiveVolume)
+		fakeChild.HandleReturns("child-handle")
+		fakeChild.ParentReturns(fakeVolume, true, nil)
+
+		fakeStranger = new(volumefakes.FakeFilesystemLiveVolume)
+		fakeStranger.HandleReturns("stranger-handle")
+
+		fakeFilesystem = new(volumefakes.FakeFilesystem)
+		fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+		fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeVolume, fakeChild, fakeStranger}, nil)
+
+		fakeLocker = new(volumefakes.FakeLockManager)
+
+		repository = volume.NewRepository(
+			fakeFilesystem,
+			fakeLocker,
+			new(uidgidfakes.FakeNamespacer),
+			new(uidgidfakes.FakeNamespacer),
+			nil,
+			0,
+		)
+	})
+
+	It("moves the volume to the new handle", func() {
+		renamed, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fakeFilesystem.LookupVolumeArgsForCall(0)).To(Equal("some-handle"))
+		Expect(fakeVolume.RenameArgsForCall(0)).To(Equal("new-handle"))
+
+		Expect(renamed).To(Equal(volume.Volume{
+			Handle:     "new-handle",
+			Path:       "/live/new-handle/volume",
+			Properties: volume.Properties{"some": "property"},
+		}))
+	})
+
+	It("links the volume's children to it under its new handle", func() {
+		_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fakeChild.ReparentCallCount()).To(Equal(1))
+		Expect(fakeChild.ReparentArgsForCall(0)).To(Equal(fakeRenamed))
+
+		Expect(fakeStranger.ReparentCallCount()).To(BeZero())
+	})
+
+	It("holds the locks of the volume, its new handle and its children, in order", func() {
+		_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fakeLocker.LockCallCount()).To(Equal(3))
+		Expect(fakeLocker.LockArgsForCall(0)).To(Equal("child-handle"))
+		Expect(fakeLocker.LockArgsForCall(1)).To(Equal("new-handle"))
+		Expect(fakeLocker.LockArgsForCall(2)).To(Equal("some-handle"))
+		Expect(fakeLocker.UnlockCallCount()).To(Equal(3))
+	})
+
+	It("publishes a renamed event", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := repository.Subscribe(ctx)
+
+		_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+		Expect(err).ToNot(HaveOccurred())
+
+		var event volume.Event
+		Eventually(events).Should(Receive(&event))
+		Expect(event.Type).To(Equal(volume.EventRenamed))
+		Expect(event.Handle).To(Equal("new-handle"))
+		Expect(event.PreviousHandle).To(Equal("some-handle"))
+	})
+
+	Context("when a child is created before the locks are taken", func() {
+		var fakeNewChild *volumefakes.FakeFilesystemLiveVolume
+
+		BeforeEach(func() {
+			fakeNewChild = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeNewChild.HandleReturns("new-child-handle")
+			fakeNewChild.ParentReturns(fakeVolume, true, nil)
+
+			fakeFilesystem.ListVolumesReturnsOnCall(0, []volume.FilesystemLiveVolume{fakeVolume, fakeChild}, nil)
+			fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeVolume, fakeChild, fakeNewChild}, nil)
+		})
+
+		It("locks the new child too before linking it to the renamed volume", func() {
+			_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+			Expect(err).ToNot(HaveOccurred())
+
+			// the first locks are released and all of them taken again
+			Expect(fakeLocker.LockCallCount()).To(Equal(7))
+			Expect(fakeLocker.LockArgsForCall(3)).To(Equal("child-handle"))
+			Expect(fakeLocker.LockArgsForCall(4)).To(Equal("new-child-handle"))
+			Expect(fakeLocker.UnlockCallCount()).To(Equal(7))
+
+			Expect(fakeNewChild.ReparentCallCount()).To(Equal(1))
+			Expect(fakeChild.ReparentCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when a child is destroyed before the locks are taken", func() {
+		BeforeEach(func() {
+			fakeFilesystem.ListVolumesReturnsOnCall(0, []volume.FilesystemLiveVolume{fakeVolume, fakeChild}, nil)
+			fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeVolume}, nil)
+		})
+
+		It("does not link it to the renamed volume", func() {
+			_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeChild.ReparentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when renaming to the volume's own handle", func() {
+		BeforeEach(func() {
+			fakeVolume.RenameReturns(nil, &os.LinkError{Op: "rename", Err: os.ErrExist})
+		})
+
+		It("locks the handle once", func() {
+			_, err := repository.RenameVolume(context.Background(), "some-handle", "some-handle")
+			Expect(err).To(Equal(volume.ErrVolumeAlreadyExists))
+
+			Expect(fakeLocker.LockCallCount()).To(Equal(2))
+			Expect(fakeLocker.LockArgsForCall(0)).To(Equal("child-handle"))
+			Expect(fakeLocker.LockArgsForCall(1)).To(Equal("some-handle"))
+		})
+	})
+
+	Context("when the new handle is taken", func() {
+		BeforeEach(func() {
+			fakeVolume.RenameReturns(nil, &os.LinkError{Op: "rename", Err: os.ErrExist})
+		})
+
+		It("returns ErrVolumeAlreadyExists without touching the children", func() {
+			_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+			Expect(err).To(Equal(volume.ErrVolumeAlreadyExists))
+
+			Expect(fakeChild.ReparentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the new handle is not a plain name", func() {
+		It("returns ErrInvalidVolumeHandle", func() {
+			for _, handle := range []string{"", ".", "..", "../escape", "some/nested"} {
+				_, err := repository.RenameVolume(context.Background(), "some-handle", handle)
+				Expect(err).To(Equal(volume.ErrInvalidVolumeHandle))
+			}
+
+			Expect(fakeVolume.RenameCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when renaming fails", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			fakeVolume.RenameReturns(nil, disaster)
+		})
+
+		It("returns the error", func() {
+			_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+			Expect(err).To(Equal(disaster))
+		})
+	})
+
+	Context("when a child cannot be linked to the renamed volume", func() {
+		disaster := errors.New("nope")
+
+		var (
+			movedChild   *volumefakes.FakeFilesystemLiveVolume
+			fakeRestored *volumefakes.FakeFilesystemLiveVolume
+		)
+
+		BeforeEach(func() {
+			fakeChild.ReparentReturns(disaster)
+
+			movedChild = new(volumefakes.FakeFilesystemLiveVolume)
+			movedChild.HandleReturns("moved-child-handle")
+			movedChild.ParentReturns(fakeVolume, true, nil)
+
+			fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeVolume, movedChild, fakeChild}, nil)
+
+			fakeRestored = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeRestored.HandleReturns("some-handle")
+			fakeRenamed.RenameReturns(fakeRestored, nil)
+		})
+
+		It("moves the volume back, links the children that were moved back to it, and returns the error", func() {
+			_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+			Expect(err).To(Equal(disaster))
+
+			Expect(fakeRenamed.RenameCallCount()).To(Equal(1))
+			Expect(fakeRenamed.RenameArgsForCall(0)).To(Equal("some-handle"))
+
+			Expect(movedChild.ReparentCallCount()).To(Equal(2))
+			Expect(movedChild.ReparentArgsForCall(0)).To(Equal(fakeRenamed))
+			Expect(movedChild.ReparentArgsForCall(1)).To(Equal(fakeRestored))
+		})
+
+		It("does not publish a renamed event", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := repository.Subscribe(ctx)
+
+			_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+			Expect(err).To(HaveOccurred())
+
+			Consistently(events).ShouldNot(Receive())
+		})
+
+		Context("when the volume cannot be moved back", func() {
+			var otherChild *volumefakes.FakeFilesystemLiveVolume
+
+			BeforeEach(func() {
+				fakeRenamed.RenameReturns(nil, errors.New("still nope"))
+
+				otherChild = new(volumefakes.FakeFilesystemLiveVolume)
+				otherChild.HandleReturns("other-child-handle")
+				otherChild.ParentReturns(fakeVolume, true, nil)
+
+				fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeVolume, movedChild, fakeChild, otherChild}, nil)
+			})
+
+			It("lets the rename stand, still linking the other children", func() {
+				renamed, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(renamed.Handle).To(Equal("new-handle"))
+
+				Expect(movedChild.ReparentCallCount()).To(Equal(1))
+				Expect(otherChild.ReparentCallCount()).To(Equal(1))
+				Expect(otherChild.ReparentArgsForCall(0)).To(Equal(fakeRenamed))
+			})
+
+			It("publishes a renamed event", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				events := repository.Subscribe(ctx)
+
+				_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+				Expect(err).ToNot(HaveOccurred())
+
+				var event volume.Event
+				Eventually(events).Should(Receive(&event))
+				Expect(event.Type).To(Equal(volume.EventRenamed))
+			})
+		})
+	})
+
+	Context("when the volume does not exist", func() {
+		BeforeEach(func() {
+			fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+		})
+
+		It("returns ErrVolumeDoesNotExist", func() {
+			_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+			Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+		})
+	})
+})
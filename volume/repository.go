@@ -1,46 +1,127 @@
 package volume
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
-	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagerctx"
+	"github.com/concourse/baggageclaim/metrics"
 	"github.com/concourse/baggageclaim/uidgid"
+	"github.com/concourse/baggageclaim/volume/fsroot"
 )
 
 var ErrVolumeDoesNotExist = errors.New("volume does not exist")
 var ErrVolumeIsCorrupted = errors.New("volume is corrupted")
 var ErrUnsupportedStreamEncoding = errors.New("unsupported stream encoding")
+var ErrQuotaExceeded = errors.New("volume quota exceeded")
+var ErrVolumeIsReadOnly = errors.New("volume is read-only")
 
-const GzipEncoding string = "gzip"
-const ZstdEncoding string = "zstd"
+// maxP2pErrorBodySize limits how much of a peer's error response is kept.
+const maxP2pErrorBodySize = 4096
+
+// P2pStreamError is returned when a peer rejects a volume streamed to it.
+type P2pStreamError struct {
+	StatusCode int
+	Body       string
+	BytesSent  int64
+}
+
+func (err P2pStreamError) Error() string {
+	msg := fmt.Sprintf("p2p streaming error %d after %d bytes", err.StatusCode, err.BytesSent)
+	if err.Body != "" {
+		msg += ": " + err.Body
+	}
+
+	return msg
+}
 
 //go:generate counterfeiter . Repository
 
 type Repository interface {
 	ListVolumes(ctx context.Context, queryProperties Properties) (Volumes, []string, error)
+
+	// VisitVolumes calls visit with each volume matching the query, in the
+	// query's order, stopping at the first error visit returns.
+	VisitVolumes(ctx context.Context, query VolumeQuery, visit func(Volume) error) error
+
 	GetVolume(ctx context.Context, handle string) (Volume, bool, error)
-	CreateVolume(ctx context.Context, handle string, strategy Strategy, properties Properties, isPrivileged bool) (Volume, error)
+	CreateVolume(ctx context.Context, handle string, spec VolumeSpec) (Volume, error)
 	DestroyVolume(ctx context.Context, handle string) error
 	DestroyVolumeAndDescendants(ctx context.Context, handle string) error
 
+	// ReapVolumes destroys the volumes whose TTL has run out, along with
+	// their descendants, returning how many were destroyed.
+	ReapVolumes(ctx context.Context) (int, error)
+
+	// RenameVolume moves a volume to a new handle. ErrVolumeAlreadyExists is
+	// returned if the handle is taken.
+	RenameVolume(ctx context.Context, handle string, newHandle string) (Volume, error)
+
 	SetProperty(ctx context.Context, handle string, propertyName string, propertyValue string) error
 	GetPrivileged(ctx context.Context, handle string) (bool, error)
 	SetPrivileged(ctx context.Context, handle string, privileged bool) error
+	SetTTL(ctx context.Context, handle string, ttl time.Duration) error
+
+	// SetReadOnly seals the volume's contents, after which streaming in,
+	// changing its privileges and restoring snapshots fail with
+	// ErrVolumeIsReadOnly. Its properties may still be set. Stream-ins
+	// already in progress are waited for.
+	SetReadOnly(ctx context.Context, handle string) error
+
+	GetUsage(ctx context.Context, handle string) (VolumeUsage, error)
+	GetDigest(ctx context.Context, handle string) (string, error)
+
+	CollectOrphans(ctx context.Context) (int, error)
+	GetOrphanStats(ctx context.Context) (OrphanStats, error)
+
+	Fsck(ctx context.Context, opts FsckOptions) (FsckReport, error)
+
+	// ListQuarantinedVolumes returns the handles of the volumes Fsck set
+	// aside, which are kept until DestroyQuarantinedVolume is called.
+	ListQuarantinedVolumes(ctx context.Context) ([]string, error)
+	DestroyQuarantinedVolume(ctx context.Context, handle string) error
 
 	StreamIn(ctx context.Context, handle string, path string, encoding string, stream io.Reader) (bool, error)
+
+	// StreamInResumable streams in as part of an upload session, with stream
+	// continuing from offset bytes in. If the stream is interrupted the upload
+	// can be resumed from the offset returned by UploadOffset.
+	StreamInResumable(ctx context.Context, handle string, path string, encoding string, session string, offset int64, stream io.Reader) (bool, error)
+	UploadOffset(ctx context.Context, handle string, session string) (int64, error)
+
+	// ReapUploads discards the upload sessions that have received nothing
+	// for longer than ttl, returning how many were discarded.
+	ReapUploads(ctx context.Context, ttl time.Duration) (int, error)
+
 	StreamOut(ctx context.Context, handle string, path string, encoding string, dest io.Writer) error
 
 	StreamP2pOut(ctx context.Context, handle string, path string, encoding string, streamInURL string) error
 
+	Diff(ctx context.Context, handle string) ([]Change, error)
+	StreamOutDiff(ctx context.Context, handle string, path string, encoding string, dest io.Writer) error
+
+	StreamOutOCI(ctx context.Context, handle string, chain bool, encoding string, dest io.Writer) error
+
+	CreateSnapshot(ctx context.Context, handle string, snapshotHandle string) (Snapshot, error)
+	RestoreSnapshot(ctx context.Context, handle string, snapshotHandle string) error
+	DestroySnapshot(ctx context.Context, handle string, snapshotHandle string) error
+
+	OpenFile(ctx context.Context, handle string, path string) (*os.File, error)
+	Tree(ctx context.Context, handle string, path string, depth int) ([]TreeEntry, error)
+
 	VolumeParent(ctx context.Context, handle string) (Volume, bool, error)
+
+	Subscribe(ctx context.Context) <-chan Event
 }
 
 type repository struct {
@@ -48,29 +129,44 @@ type repository struct {
 
 	locker LockManager
 
-	gzipStreamer Streamer
-	zstdStreamer Streamer
-	namespacer   func(bool) uidgid.Namespacer
+	namespacer func(bool) uidgid.Namespacer
+
+	p2pClient *http.Client
+
+	// maxUploadSize limits how much an upload session may spool, if non-zero
+	maxUploadSize int64
+
+	// stream-ins in progress, which sealing or restoring a volume waits for
+	writers *writerCount
+
+	// usage measured to sort volumes by size, reused across pages
+	usages *usageCache
+
+	events *eventHub
+
+	orphansReclaimed uint64
 }
 
+// NewRepository returns a Repository of the volumes in the filesystem.
+// Volumes are streamed to peers with p2pClient, or http.DefaultClient if it is
+// nil. Upload sessions may spool up to maxUploadSize bytes, or any amount if
+// it is 0, and no more than the volume's quota.
 func NewRepository(
 	filesystem Filesystem,
 	locker LockManager,
 	privilegedNamespacer uidgid.Namespacer,
 	unprivilegedNamespacer uidgid.Namespacer,
+	p2pClient *http.Client,
+	maxUploadSize int64,
 ) Repository {
+	if p2pClient == nil {
+		p2pClient = http.DefaultClient
+	}
+
 	return &repository{
 		filesystem: filesystem,
 		locker:     locker,
 
-		gzipStreamer: &tarGzipStreamer{
-			namespacer: unprivilegedNamespacer,
-		},
-
-		zstdStreamer: &tarZstdStreamer{
-			namespacer: unprivilegedNamespacer,
-		},
-
 		namespacer: func(privileged bool) uidgid.Namespacer {
 			if privileged {
 				return privilegedNamespacer
@@ -78,37 +174,81 @@ func NewRepository(
 				return unprivilegedNamespacer
 			}
 		},
+
+		p2pClient: p2pClient,
+
+		maxUploadSize: maxUploadSize,
+
+		writers: newWriterCount(),
+
+		usages: newUsageCache(),
+
+		events: newEventHub(),
 	}
 }
 
-func (repo *repository) DestroyVolume(ctx context.Context, handle string) error {
-	repo.locker.Lock(handle)
-	defer repo.locker.Unlock(handle)
+func (repo *repository) Subscribe(ctx context.Context) <-chan Event {
+	return repo.events.Subscribe(ctx)
+}
 
+func (repo *repository) DestroyVolume(ctx context.Context, handle string) error {
 	logger := lagerctx.FromContext(ctx).Session("destroy-volume", lager.Data{
 		"volume": handle,
 	})
 
+	parent, err := repo.destroyVolume(logger, handle)
+	if err != nil {
+		return err
+	}
+
+	// the volume's lock is released first, as the layers beneath it are
+	// locked in turn
+	if parent != nil {
+		repo.releaseLayers(logger, parent)
+	}
+
+	return nil
+}
+
+// destroyVolume destroys the volume, returning its parent, if it had one.
+func (repo *repository) destroyVolume(logger lager.Logger, handle string) (FilesystemLiveVolume, error) {
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	return repo.destroyLockedVolume(logger, handle)
+}
+
+// destroyLockedVolume is destroyVolume for callers already holding the
+// volume's lock.
+func (repo *repository) destroyLockedVolume(logger lager.Logger, handle string) (FilesystemLiveVolume, error) {
 	volume, found, err := repo.filesystem.LookupVolume(handle)
 	if err != nil {
 		logger.Error("failed-to-lookup-volume", err)
-		return err
+		return nil, err
 	}
 
 	if !found {
 		logger.Info("volume-not-found")
-		return ErrVolumeDoesNotExist
+		return nil, ErrVolumeDoesNotExist
+	}
+
+	// only needed to release any image layers beneath the volume
+	parent, _, err := volume.Parent()
+	if err != nil {
+		logger.Error("failed-to-get-parent", err)
 	}
 
 	err = volume.Destroy()
 	if err != nil {
 		logger.Error("failed-to-destroy", err)
-		return err
+		return nil, err
 	}
 
 	logger.Info("destroyed")
 
-	return nil
+	repo.events.Publish(Event{Type: EventDestroyed, Handle: handle})
+
+	return parent, nil
 }
 
 func (repo *repository) DestroyVolumeAndDescendants(ctx context.Context, handle string) error {
@@ -147,42 +287,237 @@ func (repo *repository) DestroyVolumeAndDescendants(ctx context.Context, handle
 	return repo.DestroyVolume(ctx, handle)
 }
 
-func (repo *repository) CreateVolume(ctx context.Context, handle string, strategy Strategy, properties Properties, isPrivileged bool) (Volume, error) {
+func (repo *repository) ReapVolumes(ctx context.Context) (int, error) {
+	logger := lagerctx.FromContext(ctx).Session("reap-volumes")
+
+	volumes, err := repo.filesystem.ListVolumes()
+	if err != nil {
+		logger.Error("failed-to-list-volumes", err)
+		return 0, err
+	}
+
+	reaped := 0
+	for _, volume := range volumes {
+		// only the expiry is loaded, as most volumes will not have expired
+		expiresAt, err := volume.LoadExpiresAt()
+		if err != nil {
+			logger.Error("failed-to-load-expiry", err, lager.Data{
+				"volume": volume.Handle(),
+			})
+
+			continue
+		}
+
+		if !hasExpired(expiresAt) {
+			continue
+		}
+
+		expired, err := repo.reapVolume(logger, volume.Handle())
+		if err != nil {
+			// an expired ancestor may have taken it out already
+			if err != ErrVolumeDoesNotExist {
+				logger.Error("failed-to-destroy-expired-volume", err, lager.Data{
+					"volume": volume.Handle(),
+				})
+			}
+
+			continue
+		}
+
+		if expired {
+			logger.Info("reaped-expired-volume", lager.Data{
+				"volume": volume.Handle(),
+			})
+
+			reaped++
+		}
+	}
+
+	return reaped, nil
+}
+
+// reapVolume destroys the volume and its descendants if it has expired,
+// reporting whether it did. The expiry is checked again under the volume's
+// lock, as its TTL may have been set since it was listed.
+func (repo *repository) reapVolume(logger lager.Logger, handle string) (bool, error) {
+	parent, expired, err := repo.destroyExpiredVolume(logger, handle)
+	if err != nil || !expired {
+		return false, err
+	}
+
+	if parent != nil {
+		repo.releaseLayers(logger, parent)
+	}
+
+	return true, nil
+}
+
+func (repo *repository) destroyExpiredVolume(logger lager.Logger, handle string) (FilesystemLiveVolume, bool, error) {
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !found {
+		return nil, false, ErrVolumeDoesNotExist
+	}
+
+	expiresAt, err := volume.LoadExpiresAt()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !hasExpired(expiresAt) {
+		return nil, false, nil
+	}
+
+	err = repo.destroyDescendants(logger, handle)
+	if err != nil {
+		return nil, false, err
+	}
+
+	parent, err := repo.destroyLockedVolume(logger, handle)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return parent, true, nil
+}
+
+// destroyDescendants destroys the volumes beneath a volume, deepest first.
+// The layers beneath them are not released, as they are all being destroyed
+// along with the volume.
+func (repo *repository) destroyDescendants(logger lager.Logger, handle string) error {
+	allVolumes, err := repo.filesystem.ListVolumes()
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range allVolumes {
+		candidateParent, found, err := candidate.Parent()
+		if err != nil || !found || candidateParent.Handle() != handle {
+			continue
+		}
+
+		err = repo.destroyDescendants(logger, candidate.Handle())
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.destroyVolume(logger, candidate.Handle())
+		if err != nil && err != ErrVolumeDoesNotExist {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasExpired reports whether a volume expiring at expiresAt has expired. The
+// zero time never expires.
+func hasExpired(expiresAt time.Time) bool {
+	return !expiresAt.IsZero() && !time.Now().Before(expiresAt)
+}
+
+func (repo *repository) CreateVolume(ctx context.Context, handle string, spec VolumeSpec) (Volume, error) {
 	logger := lagerctx.FromContext(ctx).Session("create-volume", lager.Data{"handle": handle})
 
+	start := time.Now()
+
 	// only the import strategy uses the gzip streamer as,
 	// base resource type rootfs' are available locally as .tgz
-	initVolume, err := strategy.Materialize(logger, handle, repo.filesystem, repo.gzipStreamer)
+	gzipStreamer, _ := repo.streamer(GzipEncoding)
+
+	// a copy-on-write child holds its parent's lock until it is initialized
+	fs := &parentLockingFilesystem{
+		Filesystem: repo.filesystem,
+		locker:     repo.locker,
+	}
+
+	initVolume, err := spec.Strategy.Materialize(logger, handle, fs, gzipStreamer)
 	if err != nil {
+		fs.unlock()
 		logger.Error("failed-to-materialize-strategy", err)
 		return Volume{}, err
 	}
 
+	parent, _, err := initVolume.Parent()
+	if err != nil {
+		logger.Error("failed-to-get-parent", err)
+		initVolume.Destroy()
+		fs.unlock()
+		return Volume{}, err
+	}
+
+	// the strategy may have layered the volume on volumes of its own
+	if parent != nil {
+		repo.announceLayers(logger, parent)
+	}
+
+	repo.events.Publish(Event{Type: EventCreated, Handle: handle})
+
+	// subscribers are told the volume is gone whether or not destroying it
+	// succeeds; an init volume is never seen by anything else, and is left
+	// for the orphan collector if it cannot be destroyed now
 	var initialized bool
 	defer func() {
 		if !initialized {
-			initVolume.Destroy()
+			err := initVolume.Destroy()
+			if err != nil {
+				logger.Error("failed-to-destroy-init-volume", err)
+			}
+
+			// releasing the layers takes the parent's lock
+			fs.unlock()
+
+			repo.events.Publish(Event{Type: EventDestroyed, Handle: handle})
+
+			if err == nil && parent != nil {
+				repo.releaseLayers(logger, parent)
+			}
 		}
 	}()
 
-	err = initVolume.StoreProperties(properties)
+	err = initVolume.StoreProperties(spec.Properties)
 	if err != nil {
 		logger.Error("failed-to-set-properties", err)
 		return Volume{}, err
 	}
 
-	err = initVolume.StorePrivileged(isPrivileged)
+	err = initVolume.StorePrivileged(spec.Privileged)
 	if err != nil {
 		logger.Error("failed-to-set-privileged", err)
 		return Volume{}, err
 	}
 
-	err = repo.namespacer(isPrivileged).NamespacePath(logger, initVolume.DataPath())
+	err = repo.namespacer(spec.Privileged).NamespacePath(logger, initVolume.DataPath())
 	if err != nil {
 		logger.Error("failed-to-namespace-data", err)
 		return Volume{}, err
 	}
 
+	if spec.QuotaBytes > 0 {
+		err = initVolume.SetQuota(spec.QuotaBytes)
+		if err != nil {
+			logger.Error("failed-to-set-quota", err)
+			return Volume{}, err
+		}
+	}
+
+	var expiresAt time.Time
+	if spec.TTL > 0 {
+		expiresAt = time.Now().Add(spec.TTL)
+
+		err = initVolume.StoreExpiresAt(expiresAt)
+		if err != nil {
+			logger.Error("failed-to-set-ttl", err)
+			return Volume{}, err
+		}
+	}
+
 	liveVolume, err := initVolume.Initialize()
 	if err != nil {
 		logger.Error("failed-to-initialize-volume", err)
@@ -190,11 +525,44 @@ func (repo *repository) CreateVolume(ctx context.Context, handle string, strateg
 	}
 
 	initialized = true
+	fs.unlock()
+
+	if spec.ReadOnly {
+		// drivers may only be able to protect a mounted volume
+		err = liveVolume.SetReadOnly()
+		if err != nil {
+			logger.Error("failed-to-set-read-only", err)
+
+			// the volume is live, so it is destroyed as any other would be
+			parent, destroyErr := repo.destroyVolume(logger, handle)
+			if destroyErr == nil {
+				if parent != nil {
+					repo.releaseLayers(logger, parent)
+				}
+			} else if destroyErr != ErrVolumeDoesNotExist {
+				// the volume is still live, so it is announced as it is
+				repo.events.Publish(Event{Type: EventInitialized, Handle: liveVolume.Handle()})
+			}
+
+			return Volume{}, err
+		}
+	}
+
+	// imported and sealed contents are what is most worth deduplicating
+	if _, imported := spec.Strategy.(ImportStrategy); imported || spec.ReadOnly {
+		repo.recordDigest(logger, liveVolume)
+	}
+
+	repo.events.Publish(Event{Type: EventInitialized, Handle: liveVolume.Handle()})
+
+	metrics.CreationDuration.WithLabelValues(strategyName(spec.Strategy)).Observe(time.Since(start).Seconds())
 
 	return Volume{
 		Handle:     liveVolume.Handle(),
 		Path:       liveVolume.DataPath(),
-		Properties: properties,
+		Properties: spec.Properties,
+		TTL:        remainingTTL(expiresAt),
+		ReadOnly:   spec.ReadOnly,
 	}, nil
 }
 
@@ -230,6 +598,117 @@ func (repo *repository) ListVolumes(ctx context.Context, queryProperties Propert
 	return healthyVolumes, corruptedVolumeHandles, nil
 }
 
+// VisitVolumes only loads the volumes' sort keys up front. Volumes are then
+// hydrated one at a time as they are visited, so a limited listing reads the
+// metadata of only as many volumes as it needs to.
+func (repo *repository) VisitVolumes(ctx context.Context, query VolumeQuery, visit func(Volume) error) error {
+	logger := lagerctx.FromContext(ctx).Session("visit-volumes")
+
+	liveVolumes, err := repo.filesystem.ListVolumes()
+	if err != nil {
+		logger.Error("failed-to-list-volumes", err)
+		return err
+	}
+
+	if query.SortBy == SortBySize {
+		repo.usages.expire()
+	}
+
+	candidates := make([]sortableVolume, 0, len(liveVolumes))
+
+	for _, liveVolume := range liveVolumes {
+		candidate, err := repo.sortableVolumeFrom(liveVolume, query.SortBy)
+		if err == ErrVolumeDoesNotExist {
+			continue
+		}
+
+		if err != nil {
+			logger.Error("failed-to-load-sort-key", err, lager.Data{
+				"volume": liveVolume.Handle(),
+			})
+			continue
+		}
+
+		if query.After != nil && !query.less(*query.After, candidate.cursor) {
+			continue
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return query.less(candidates[i].cursor, candidates[j].cursor)
+	})
+
+	visited := 0
+
+	for _, candidate := range candidates {
+		if query.Limit > 0 && visited == query.Limit {
+			break
+		}
+
+		volume, err := repo.volumeFrom(candidate.volume)
+		if err == ErrVolumeDoesNotExist {
+			continue
+		}
+
+		if err != nil {
+			logger.Error("failed-hydrating-volume", err)
+			continue
+		}
+
+		if !volume.Properties.HasProperties(query.Properties) {
+			continue
+		}
+
+		// already measured, so there is no reason not to report it
+		volume.Usage = candidate.usage
+
+		err = visit(volume)
+		if err != nil {
+			return err
+		}
+
+		visited++
+	}
+
+	return nil
+}
+
+type sortableVolume struct {
+	volume FilesystemLiveVolume
+	cursor VolumeCursor
+	usage  *VolumeUsage
+}
+
+func (repo *repository) sortableVolumeFrom(liveVolume FilesystemLiveVolume, sortBy VolumeSortKey) (sortableVolume, error) {
+	candidate := sortableVolume{
+		volume: liveVolume,
+		cursor: VolumeCursor{Handle: liveVolume.Handle()},
+	}
+
+	switch sortBy {
+	case SortByCreatedAt:
+		createdAt, err := liveVolume.LoadCreatedAt()
+		if err != nil {
+			return sortableVolume{}, err
+		}
+
+		candidate.cursor.CreatedAt = createdAt
+
+	case SortBySize:
+		usage, err := repo.usages.measure(liveVolume)
+		if err != nil {
+			return sortableVolume{}, err
+		}
+
+		candidate.cursor.Size = usage.ExclusiveBytes
+		candidate.usage = &usage
+	}
+
+	return candidate, nil
+}
+
 func (repo *repository) GetVolume(ctx context.Context, handle string) (Volume, bool, error) {
 	logger := lagerctx.FromContext(ctx).Session("get-volume", lager.Data{
 		"volume": handle,
@@ -295,6 +774,13 @@ func (repo *repository) SetProperty(ctx context.Context, handle string, property
 		return err
 	}
 
+	repo.events.Publish(Event{
+		Type:     EventPropertySet,
+		Handle:   handle,
+		Property: propertyName,
+		Value:    propertyValue,
+	})
+
 	return nil
 }
 
@@ -345,6 +831,12 @@ func (repo *repository) SetPrivileged(ctx context.Context, handle string, privil
 		return ErrVolumeDoesNotExist
 	}
 
+	err = checkWritable(volume)
+	if err != nil {
+		logger.Info("volume-is-read-only")
+		return err
+	}
+
 	err = repo.namespacer(privileged).NamespacePath(logger, volume.DataPath())
 	if err != nil {
 		logger.Error("failed-to-namespace-volume", err)
@@ -357,59 +849,850 @@ func (repo *repository) SetPrivileged(ctx context.Context, handle string, privil
 		return err
 	}
 
+	repo.events.Publish(Event{
+		Type:       EventPrivilegedChanged,
+		Handle:     handle,
+		Privileged: &privileged,
+	})
+
 	return nil
 }
 
-func (repo *repository) StreamIn(ctx context.Context, handle string, path string, encoding string, stream io.Reader) (bool, error) {
-	logger := lagerctx.FromContext(ctx).Session("stream-in", lager.Data{
-		"volume":   handle,
-		"sub-path": path,
-		"encoding": encoding,
+func (repo *repository) SetTTL(ctx context.Context, handle string, ttl time.Duration) error {
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	logger := lagerctx.FromContext(ctx).Session("set-ttl", lager.Data{
+		"volume": handle,
+		"ttl":    ttl.String(),
 	})
 
 	volume, found, err := repo.filesystem.LookupVolume(handle)
 	if err != nil {
 		logger.Error("failed-to-lookup-volume", err)
-		return false, err
+		return err
 	}
 
 	if !found {
 		logger.Info("volume-not-found")
-		return false, ErrVolumeDoesNotExist
+		return ErrVolumeDoesNotExist
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	err = volume.StoreExpiresAt(expiresAt)
+	if err != nil {
+		logger.Error("failed-to-store-ttl", err)
+		return err
+	}
+
+	return nil
+}
+
+func (repo *repository) SetReadOnly(ctx context.Context, handle string) error {
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	logger := lagerctx.FromContext(ctx).Session("set-read-only", lager.Data{
+		"volume": handle,
+	})
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return err
 	}
 
-	destinationPath := filepath.Join(volume.DataPath(), path)
+	if !found {
+		logger.Info("volume-not-found")
+		return ErrVolumeDoesNotExist
+	}
+
+	// new stream-ins wait for the lock held here, and fail once they have it
+	repo.writers.wait(handle)
+
+	err = volume.SetReadOnly()
+	if err != nil {
+		logger.Error("failed-to-set-read-only", err)
+		return err
+	}
+
+	repo.recordDigest(logger, volume)
+
+	repo.events.Publish(Event{
+		Type:   EventMadeReadOnly,
+		Handle: handle,
+	})
+
+	return nil
+}
+
+func (repo *repository) GetUsage(ctx context.Context, handle string) (VolumeUsage, error) {
+	logger := lagerctx.FromContext(ctx).Session("get-usage", lager.Data{
+		"volume": handle,
+	})
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return VolumeUsage{}, err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return VolumeUsage{}, ErrVolumeDoesNotExist
+	}
+
+	usage, err := volume.Usage()
+	if err != nil {
+		logger.Error("failed-to-get-usage", err)
+		return VolumeUsage{}, err
+	}
+
+	return usage, nil
+}
+
+// GetDigest computes the digest of the volume's contents. The recorded digest
+// of a read-only volume is returned as-is; that of a writable volume may have
+// gone stale, e.g. if a container wrote to it, so it is computed afresh.
+func (repo *repository) GetDigest(ctx context.Context, handle string) (string, error) {
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	logger := lagerctx.FromContext(ctx).Session("get-digest", lager.Data{
+		"volume": handle,
+	})
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return "", err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return "", ErrVolumeDoesNotExist
+	}
+
+	readOnly, err := volume.LoadReadOnly()
+	if err != nil {
+		logger.Error("failed-to-load-read-only", err)
+		return "", err
+	}
+
+	if readOnly {
+		digest, err := volume.LoadDigest()
+		if err != nil {
+			logger.Error("failed-to-load-digest", err)
+			return "", err
+		}
+
+		if digest != "" {
+			return digest, nil
+		}
+	}
+
+	digest, err := TreeDigest(volume.DataPath())
+	if err != nil {
+		logger.Error("failed-to-compute-digest", err)
+		return "", err
+	}
+
+	if readOnly {
+		err = volume.StoreDigest(digest)
+		if err != nil {
+			logger.Error("failed-to-store-digest", err)
+			return "", err
+		}
+	}
+
+	return digest, nil
+}
+
+// recordDigest computes and records the digest of the volume's contents, so
+// that DedupeStrategy can find it. Failing to is only logged, as the volume
+// is no less usable without one.
+func (repo *repository) recordDigest(logger lager.Logger, volume FilesystemLiveVolume) {
+	digest, err := TreeDigest(volume.DataPath())
+	if err != nil {
+		logger.Error("failed-to-compute-digest", err)
+		return
+	}
+
+	err = volume.StoreDigest(digest)
+	if err != nil {
+		logger.Error("failed-to-store-digest", err)
+	}
+}
+
+// streamer returns a Streamer for the named encoding, if it is registered.
+// Ownership is mapped with the unprivileged namespacer; privileged volumes
+// are streamed as-is.
+func (repo *repository) streamer(encoding string) (Streamer, bool) {
+	enc, found := LookupEncoding(encoding)
+	if !found {
+		return nil, false
+	}
+
+	return &tarStreamer{
+		namespacer: repo.namespacer(false),
+		encoding:   enc,
+	}, true
+}
+
+// checkWritable returns ErrVolumeIsReadOnly if the volume's contents may no
+// longer change.
+func checkWritable(volume FilesystemVolume) error {
+	readOnly, err := volume.LoadReadOnly()
+	if err != nil {
+		return err
+	}
+
+	if readOnly {
+		return ErrVolumeIsReadOnly
+	}
+
+	return nil
+}
+
+// CollectOrphans destroys any volumes orphaned in the init and dead
+// directories and returns how many were reclaimed. Failing to destroy an
+// orphan is logged and left for the next collection.
+func (repo *repository) CollectOrphans(ctx context.Context) (int, error) {
+	logger := lagerctx.FromContext(ctx).Session("collect-orphans")
+
+	orphans, err := repo.filesystem.ListOrphans()
+	if err != nil {
+		logger.Error("failed-to-list-orphans", err)
+		return 0, err
+	}
 
-	logger = logger.WithData(lager.Data{
-		"full-path": destinationPath,
+	reclaimed := 0
+	for _, orphan := range orphans {
+		err := orphan.Destroy()
+		if os.IsNotExist(err) {
+			// its owner finished with it after all
+			continue
+		}
+
+		if err != nil {
+			logger.Error("failed-to-destroy-orphan", err, lager.Data{
+				"volume": orphan.Handle(),
+			})
+
+			continue
+		}
+
+		logger.Info("reclaimed-orphan", lager.Data{
+			"volume": orphan.Handle(),
+		})
+
+		reclaimed++
+	}
+
+	atomic.AddUint64(&repo.orphansReclaimed, uint64(reclaimed))
+	metrics.OrphansReclaimed.Add(float64(reclaimed))
+
+	return reclaimed, nil
+}
+
+func (repo *repository) GetOrphanStats(ctx context.Context) (OrphanStats, error) {
+	logger := lagerctx.FromContext(ctx).Session("get-orphan-stats")
+
+	orphans, err := repo.filesystem.ListOrphans()
+	if err != nil {
+		logger.Error("failed-to-list-orphans", err)
+		return OrphanStats{}, err
+	}
+
+	return OrphanStats{
+		Pending:   len(orphans),
+		Reclaimed: atomic.LoadUint64(&repo.orphansReclaimed),
+	}, nil
+}
+
+// Fsck checks the integrity of every live volume, optionally repairing or
+// quarantining the corrupted ones.
+func (repo *repository) Fsck(ctx context.Context, opts FsckOptions) (FsckReport, error) {
+	logger := lagerctx.FromContext(ctx).Session("fsck", lager.Data{
+		"repair":     opts.Repair,
+		"quarantine": opts.Quarantine,
+	})
+
+	liveVolumes, err := repo.filesystem.ListVolumes()
+	if err != nil {
+		logger.Error("failed-to-list-volumes", err)
+		return FsckReport{}, err
+	}
+
+	report := FsckReport{
+		Corrupted: []CorruptedVolume{},
+	}
+
+	for _, liveVolume := range liveVolumes {
+		corrupted, found := repo.fsckVolume(logger, liveVolume.Handle(), opts)
+		if !found {
+			// destroyed while we were busy
+			continue
+		}
+
+		report.Checked++
+
+		if corrupted != nil {
+			report.Corrupted = append(report.Corrupted, *corrupted)
+		}
+	}
+
+	return report, nil
+}
+
+func (repo *repository) fsckVolume(logger lager.Logger, handle string, opts FsckOptions) (*CorruptedVolume, bool) {
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	logger = logger.WithData(lager.Data{"volume": handle})
+
+	liveVolume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return nil, false
+	}
+
+	if !found {
+		return nil, false
+	}
+
+	problems := liveVolume.Check()
+	if len(problems) == 0 {
+		return nil, true
+	}
+
+	logger.Info("found-corrupted-volume", lager.Data{"problems": problems})
+
+	corrupted := &CorruptedVolume{
+		Handle:   handle,
+		Problems: problems,
+	}
+
+	if opts.Repair {
+		err := liveVolume.Repair()
+		if err != nil {
+			logger.Error("failed-to-repair-volume", err)
+			corrupted.Error = err.Error()
+		} else {
+			corrupted.Repaired = len(liveVolume.Check()) == 0
+		}
+	}
+
+	if !corrupted.Repaired && opts.Quarantine {
+		err := repo.quarantine(liveVolume)
+		if err != nil {
+			logger.Error("failed-to-quarantine-volume", err)
+			corrupted.Error = err.Error()
+		} else {
+			corrupted.Quarantined = true
+			corrupted.Error = ""
+
+			logger.Info("quarantined-volume")
+
+			repo.events.Publish(Event{Type: EventQuarantined, Handle: handle})
+		}
+	}
+
+	return corrupted, true
+}
+
+// quarantine refuses to set aside volumes with children, whose layers would
+// be left depending on it.
+func (repo *repository) quarantine(liveVolume FilesystemLiveVolume) error {
+	hasChildren, err := repo.hasChildren(liveVolume.Handle())
+	if err != nil {
+		return err
+	}
+
+	if hasChildren {
+		return ErrVolumeHasChildren
+	}
+
+	return liveVolume.Quarantine()
+}
+
+func (repo *repository) ListQuarantinedVolumes(ctx context.Context) ([]string, error) {
+	logger := lagerctx.FromContext(ctx).Session("list-quarantined-volumes")
+
+	quarantinedVolumes, err := repo.filesystem.ListQuarantinedVolumes()
+	if err != nil {
+		logger.Error("failed-to-list-quarantined-volumes", err)
+		return nil, err
+	}
+
+	handles := make([]string, 0, len(quarantinedVolumes))
+	for _, quarantinedVolume := range quarantinedVolumes {
+		handles = append(handles, quarantinedVolume.Handle())
+	}
+
+	return handles, nil
+}
+
+func (repo *repository) DestroyQuarantinedVolume(ctx context.Context, handle string) error {
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	logger := lagerctx.FromContext(ctx).Session("destroy-quarantined-volume", lager.Data{
+		"volume": handle,
+	})
+
+	quarantinedVolume, found, err := repo.filesystem.LookupQuarantinedVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return ErrVolumeDoesNotExist
+	}
+
+	err = quarantinedVolume.Destroy()
+	if err != nil {
+		logger.Error("failed-to-destroy", err)
+		return err
+	}
+
+	logger.Info("destroyed")
+
+	repo.events.Publish(Event{Type: EventDestroyed, Handle: handle})
+
+	return nil
+}
+
+func (repo *repository) StreamIn(ctx context.Context, handle string, path string, encoding string, stream io.Reader) (bool, error) {
+	return repo.streamIn(ctx, handle, path, encoding, stream, Streamer.In)
+}
+
+// streamIn streams in to the volume with the given method of its encoding's
+// streamer.
+func (repo *repository) streamIn(ctx context.Context, handle string, path string, encoding string, stream io.Reader, in func(Streamer, io.Reader, string, string, bool) (bool, error)) (bool, error) {
+	logger := lagerctx.FromContext(ctx).Session("stream-in", lager.Data{
+		"volume":   handle,
+		"sub-path": path,
+		"encoding": encoding,
 	})
 
-	err = os.MkdirAll(destinationPath, 0755)
+	streamer, found := repo.streamer(encoding)
+	if !found {
+		return false, ErrUnsupportedStreamEncoding
+	}
+
+	volume, privileged, err := repo.startWriting(logger, handle)
 	if err != nil {
-		logger.Error("failed-to-create-destination-path", err)
 		return false, err
 	}
 
+	badStream, err := in(streamer, meteredReader{
+		Reader: stream,
+		bytes:  metrics.StreamedBytes.WithLabelValues("in", encoding),
+	}, volume.DataPath(), path, privileged)
+
+	repo.finishWriting(logger, handle, err == nil)
+
+	if err != nil {
+		return badStream, err
+	}
+
+	repo.events.Publish(Event{
+		Type:   EventStreamedIn,
+		Handle: handle,
+		Path:   path,
+	})
+
+	return false, nil
+}
+
+// startWriting looks up the volume, checks that it may be streamed in to,
+// forgets its digest and counts a writer to it, all under the volume's lock
+// so that the volume cannot be sealed or renamed in between. The caller must
+// call finishWriting once it has finished writing. It returns whether the
+// volume is privileged.
+func (repo *repository) startWriting(logger lager.Logger, handle string) (FilesystemLiveVolume, bool, error) {
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return nil, false, err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return nil, false, ErrVolumeDoesNotExist
+	}
+
+	err = checkWritable(volume)
+	if err != nil {
+		logger.Info("volume-is-read-only")
+		return nil, false, err
+	}
+
 	privileged, err := volume.LoadPrivileged()
 	if err != nil {
 		logger.Error("failed-to-check-if-volume-is-privileged", err)
-		return false, err
+		return nil, false, err
 	}
 
 	err = repo.namespacer(privileged).NamespacePath(logger, volume.DataPath())
 	if err != nil {
 		logger.Error("failed-to-namespace-path", err)
+		return nil, false, err
+	}
+
+	err = volume.StoreDigest("")
+	if err != nil {
+		logger.Error("failed-to-forget-digest", err)
+		return nil, false, err
+	}
+
+	repo.writers.add(handle)
+
+	return volume, privileged, nil
+}
+
+// finishWriting stops counting a writer to the volume. Once the last writer
+// has finished, the digest of what was streamed in is recorded, under the
+// volume's lock so that no new writer starts while it is computed. A writer
+// that failed leaves it forgotten, as the contents may be incomplete.
+func (repo *repository) finishWriting(logger lager.Logger, handle string, succeeded bool) {
+	// sealing or renaming the volume waits for writers while holding its
+	// lock, so the writer must be done before taking it
+	repo.writers.done(handle)
+
+	if !succeeded {
+		return
+	}
+
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	if repo.writers.writing(handle) {
+		// the last of them records it
+		return
+	}
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil || !found {
+		// destroyed in the meantime
+		return
+	}
+
+	readOnly, err := volume.LoadReadOnly()
+	if err != nil || readOnly {
+		// sealing the volume recorded it
+		return
+	}
+
+	repo.recordDigest(logger, volume)
+}
+
+func (repo *repository) StreamInResumable(ctx context.Context, handle string, path string, encoding string, session string, offset int64, stream io.Reader) (bool, error) {
+	logger := lagerctx.FromContext(ctx).Session("stream-in-resumable", lager.Data{
+		"volume":  handle,
+		"session": session,
+		"offset":  offset,
+	})
+
+	if !uploadSessionPattern.MatchString(session) {
+		return false, ErrInvalidUploadSession
+	}
+
+	// the offset must not be checked while an earlier attempt is still
+	// spooling what it received
+	lockKey := uploadLockKey(handle, session)
+	repo.locker.Lock(lockKey)
+	defer repo.locker.Unlock(lockKey)
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
 		return false, err
 	}
 
-	switch encoding {
-	case ZstdEncoding:
-		return repo.zstdStreamer.In(stream, destinationPath, privileged)
-	case GzipEncoding:
-		return repo.gzipStreamer.In(stream, destinationPath, privileged)
+	if !found {
+		logger.Info("volume-not-found")
+		return false, ErrVolumeDoesNotExist
+	}
+
+	// don't spool what cannot be streamed in; this is checked again, under
+	// the volume's lock, before anything is extracted
+	err = checkWritable(volume)
+	if err != nil {
+		logger.Info("volume-is-read-only")
+		return false, err
+	}
+
+	upload := newUpload(volume.UploadsPath(), session)
+
+	state := uploadState{Path: path, Encoding: encoding}
+
+	if offset == 0 {
+		err = upload.start(state)
+		if err != nil {
+			logger.Error("failed-to-start-upload", err)
+			return false, err
+		}
+	} else {
+		var found bool
+		state, found, err = upload.load()
+		if err != nil {
+			logger.Error("failed-to-load-upload", err)
+			return false, err
+		}
+
+		if !found {
+			logger.Info("upload-not-found")
+			return false, UploadOffsetError{Offset: 0}
+		}
+
+		if state.Path != path || state.Encoding != encoding {
+			logger.Info("upload-conflict")
+			return false, ErrUploadConflict
+		}
+
+		received, err := upload.offset()
+		if err != nil {
+			logger.Error("failed-to-get-upload-offset", err)
+			return false, err
+		}
+
+		if received != offset {
+			logger.Info("upload-offset-mismatch", lager.Data{"received": received})
+			return false, UploadOffsetError{Offset: received}
+		}
+	}
+
+	received, err := os.Open(upload.spoolPath)
+	if err != nil {
+		logger.Error("failed-to-open-upload-spool", err)
+		return false, err
+	}
+
+	defer received.Close()
+
+	spool, err := os.OpenFile(upload.spoolPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		logger.Error("failed-to-open-upload-spool", err)
+		return false, err
+	}
+
+	defer spool.Close()
+
+	// a session may spool no more than the volume's quota, as the volume
+	// could never hold more anyway, and no more than the upload limit
+	quota, err := volume.LoadQuota()
+	if err != nil {
+		logger.Error("failed-to-load-quota", err)
+		return false, err
+	}
+
+	body := &spoolingReader{
+		body:  stream,
+		spool: spool,
+
+		spooled:  offset,
+		limit:    repo.maxUploadSize,
+		limitErr: ErrUploadTooLarge,
+	}
+
+	if quota > 0 && (body.limit == 0 || int64(quota) < body.limit) {
+		body.limit = int64(quota)
+		body.limitErr = ErrQuotaExceeded
+	}
+
+	// the stream is decoded from the start, as it cannot be entered midway,
+	// but the entries an earlier attempt put in place are passed over rather
+	// than extracted again
+	extracted := state.Entries
+
+	badStream, err := repo.streamIn(
+		ctx,
+		handle,
+		path,
+		encoding,
+		io.MultiReader(io.LimitReader(received, offset), body),
+		func(streamer Streamer, stream io.Reader, root string, dest string, privileged bool) (bool, error) {
+			return streamer.InResuming(stream, root, dest, privileged, state.Entries, func(entries int) {
+				extracted = entries
+			})
+		},
+	)
+
+	if body.bodyErr != nil {
+		logger.Info("upload-interrupted", lager.Data{
+			"error":   body.bodyErr.Error(),
+			"entries": extracted,
+		})
+
+		err = spool.Sync()
+		if err != nil {
+			logger.Error("failed-to-sync-upload-spool", err)
+			return false, err
+		}
+
+		state.Entries = extracted
+
+		err = upload.checkpoint(state)
+		if err != nil {
+			logger.Error("failed-to-checkpoint-upload", err)
+			return false, err
+		}
+
+		return false, ErrUploadIncomplete
+	}
+
+	if body.spoolErr == body.limitErr {
+		logger.Info("upload-too-large", lager.Data{"limit": body.limit})
+		badStream, err = false, body.spoolErr
+	} else if body.spoolErr != nil {
+		logger.Error("failed-to-spool-upload", body.spoolErr)
+		badStream, err = false, body.spoolErr
 	}
 
-	return false, ErrUnsupportedStreamEncoding
+	// the upload is over one way or another; a failed stream is not resumed
+	// but started again
+	removeErr := upload.remove()
+	if removeErr != nil {
+		logger.Error("failed-to-remove-upload", removeErr)
+	}
+
+	return badStream, err
+}
+
+// UploadOffset returns how much of the stream an upload session has
+// received. Sessions which have never been started, or which have finished,
+// have received nothing.
+func (repo *repository) UploadOffset(ctx context.Context, handle string, session string) (int64, error) {
+	logger := lagerctx.FromContext(ctx).Session("upload-offset", lager.Data{
+		"volume":  handle,
+		"session": session,
+	})
+
+	if !uploadSessionPattern.MatchString(session) {
+		return 0, ErrInvalidUploadSession
+	}
+
+	lockKey := uploadLockKey(handle, session)
+	repo.locker.Lock(lockKey)
+	defer repo.locker.Unlock(lockKey)
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return 0, err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return 0, ErrVolumeDoesNotExist
+	}
+
+	upload := newUpload(volume.UploadsPath(), session)
+
+	_, found, err = upload.load()
+	if err != nil {
+		logger.Error("failed-to-load-upload", err)
+		return 0, err
+	}
+
+	if !found {
+		return 0, nil
+	}
+
+	offset, err := upload.offset()
+	if err != nil {
+		logger.Error("failed-to-get-upload-offset", err)
+		return 0, err
+	}
+
+	return offset, nil
+}
+
+func (repo *repository) ReapUploads(ctx context.Context, ttl time.Duration) (int, error) {
+	logger := lagerctx.FromContext(ctx).Session("reap-uploads")
+
+	volumes, err := repo.filesystem.ListVolumes()
+	if err != nil {
+		logger.Error("failed-to-list-volumes", err)
+		return 0, err
+	}
+
+	reaped := 0
+	for _, volume := range volumes {
+		sessions, err := listUploadSessions(volume.UploadsPath())
+		if err != nil {
+			logger.Error("failed-to-list-upload-sessions", err, lager.Data{
+				"volume": volume.Handle(),
+			})
+
+			continue
+		}
+
+		for _, session := range sessions {
+			expired, err := repo.reapUpload(volume, session, ttl)
+			if err != nil {
+				logger.Error("failed-to-reap-upload", err, lager.Data{
+					"volume":  volume.Handle(),
+					"session": session,
+				})
+
+				continue
+			}
+
+			if expired {
+				logger.Info("reaped-expired-upload", lager.Data{
+					"volume":  volume.Handle(),
+					"session": session,
+				})
+
+				reaped++
+			}
+		}
+	}
+
+	return reaped, nil
+}
+
+// reapUpload discards an upload session if it has expired, reporting whether
+// it did.
+func (repo *repository) reapUpload(volume FilesystemLiveVolume, session string, ttl time.Duration) (bool, error) {
+	// a session that is still receiving is not idle, however long ago it
+	// was started
+	lockKey := uploadLockKey(volume.Handle(), session)
+	repo.locker.Lock(lockKey)
+	defer repo.locker.Unlock(lockKey)
+
+	upload := newUpload(volume.UploadsPath(), session)
+
+	lastActive, err := upload.lastActive()
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if time.Since(lastActive) < ttl {
+		return false, nil
+	}
+
+	return true, upload.remove()
+}
+
+func uploadLockKey(handle string, session string) string {
+	return handle + "/uploads/" + session
 }
 
 func (repo *repository) StreamOut(ctx context.Context, handle string, path string, encoding string, dest io.Writer) error {
@@ -429,26 +1712,21 @@ func (repo *repository) StreamOut(ctx context.Context, handle string, path strin
 		return ErrVolumeDoesNotExist
 	}
 
-	srcPath := filepath.Join(volume.DataPath(), path)
-
-	logger = logger.WithData(lager.Data{
-		"full-path": srcPath,
-	})
-
 	isPrivileged, err := volume.LoadPrivileged()
 	if err != nil {
 		logger.Error("failed-to-check-if-volume-is-privileged", err)
 		return err
 	}
 
-	switch encoding {
-	case ZstdEncoding:
-		return repo.zstdStreamer.Out(dest, srcPath, isPrivileged)
-	case GzipEncoding:
-		return repo.gzipStreamer.Out(dest, srcPath, isPrivileged)
+	streamer, found := repo.streamer(encoding)
+	if !found {
+		return ErrUnsupportedStreamEncoding
 	}
 
-	return ErrUnsupportedStreamEncoding
+	return streamer.Out(meteredWriter{
+		Writer: dest,
+		bytes:  metrics.StreamedBytes.WithLabelValues("out", encoding),
+	}, volume.DataPath(), path, isPrivileged)
 }
 
 func (repo *repository) StreamP2pOut(ctx context.Context, handle string, path string, encoding string, streamInURL string) error {
@@ -472,55 +1750,98 @@ func (repo *repository) StreamP2pOut(ctx context.Context, handle string, path st
 		return ErrVolumeDoesNotExist
 	}
 
-	srcPath := filepath.Join(volume.DataPath(), path)
-
-	logger = logger.WithData(lager.Data{
-		"full-path": srcPath,
-	})
-
 	isPrivileged, err := volume.LoadPrivileged()
 	if err != nil {
 		logger.Error("failed-to-check-if-volume-is-privileged", err)
 		return err
 	}
 
-	buffer := new(bytes.Buffer)
-	switch encoding {
-	case ZstdEncoding:
-		err = repo.zstdStreamer.Out(buffer, srcPath, isPrivileged)
-	case GzipEncoding:
-		err = repo.gzipStreamer.Out(buffer, srcPath, isPrivileged)
-	default:
+	streamer, found := repo.streamer(encoding)
+	if !found {
 		return ErrUnsupportedStreamEncoding
 	}
+
+	// fail before contacting the peer if there is nothing to send
+	_, err = fsroot.Stat(volume.DataPath(), path)
 	if err != nil {
-		logger.Error("failed-to-compress-volume", err)
+		logger.Info("source-path-not-found")
 		return err
 	}
 
 	logger.Debug("p2p-streaming-start", lager.Data{"streamInURL": streamInURL})
 
-	client := &http.Client{}
-	req, err := http.NewRequest(http.MethodPut, streamInURL, buffer)
+	// the archive is piped straight into the request body, so it is only
+	// produced as fast as the peer consumes it
+	pipeReader, pipeWriter := io.Pipe()
+
+	sent := &countingWriter{
+		Writer: meteredWriter{
+			Writer: pipeWriter,
+			bytes:  metrics.StreamedBytes.WithLabelValues("out", encoding),
+		},
+	}
+
+	streamed := make(chan error, 1)
+	go func() {
+		err := streamer.Out(sent, volume.DataPath(), path, isPrivileged)
+		pipeWriter.CloseWithError(err)
+		streamed <- err
+	}()
+
+	req, err := http.NewRequest(http.MethodPut, streamInURL, pipeReader)
 	if err != nil {
 		logger.Error("failed-to-create-p2p-request", err)
+		pipeReader.Close()
+		<-streamed
 		return err
 	}
 
+	req = req.WithContext(ctx)
 	req.Header.Set("Content-Encoding", encoding)
-	resp, err := client.Do(req)
+
+	resp, err := repo.p2pClient.Do(req)
+
+	// unblock the streamer if the request gave up before reading everything;
+	// it then fails writing to the closed pipe, which is not its fault
+	pipeReader.Close()
+	streamErr := <-streamed
+
+	if streamErr != nil && !errors.Is(streamErr, io.ErrClosedPipe) {
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		logger.Error("failed-to-stream-volume", streamErr, lager.Data{"bytes-sent": sent.n})
+		return streamErr
+	}
+
 	if err != nil {
-		logger.Error("failed-to-streaming-to-peer", err)
-		return err
+		logger.Error("failed-to-stream-to-peer", err, lager.Data{"bytes-sent": sent.n})
+		return fmt.Errorf("p2p streaming failed after %d bytes: %w", sent.n, err)
 	}
 
-	logger.Debug("p2p-streaming-end", lager.Data{"code": resp.StatusCode})
+	defer resp.Body.Close()
+
+	logger.Debug("p2p-streaming-end", lager.Data{
+		"code":       resp.StatusCode,
+		"bytes-sent": sent.n,
+	})
 
 	if resp.StatusCode == http.StatusNoContent {
+		if streamErr != nil {
+			return fmt.Errorf("p2p peer finished reading after only %d bytes", sent.n)
+		}
+
 		return nil
 	}
 
-	return fmt.Errorf("p2p streaming error %d", resp.StatusCode)
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxP2pErrorBodySize))
+
+	return P2pStreamError{
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(body)),
+		BytesSent:  sent.n,
+	}
 }
 
 func (repo *repository) VolumeParent(ctx context.Context, handle string) (Volume, bool, error) {
@@ -567,10 +1888,56 @@ func (repo *repository) volumeFrom(liveVolume FilesystemLiveVolume) (Volume, err
 		return Volume{}, err
 	}
 
-	return Volume{
+	expiresAt, err := liveVolume.LoadExpiresAt()
+	if err != nil {
+		return Volume{}, err
+	}
+
+	snapshots, err := snapshotsOf(liveVolume)
+	if err != nil {
+		return Volume{}, err
+	}
+
+	readOnly, err := liveVolume.LoadReadOnly()
+	if err != nil {
+		return Volume{}, err
+	}
+
+	createdAt, err := liveVolume.LoadCreatedAt()
+	if err != nil {
+		return Volume{}, err
+	}
+
+	volume := Volume{
 		Handle:     liveVolume.Handle(),
 		Path:       liveVolume.DataPath(),
 		Properties: properties,
 		Privileged: isPrivileged,
-	}, nil
+		TTL:        remainingTTL(expiresAt),
+		Snapshots:  snapshots,
+		ReadOnly:   readOnly,
+	}
+
+	if !createdAt.IsZero() {
+		volume.CreatedAt = &createdAt
+	}
+
+	return volume, nil
+}
+
+// remainingTTL converts an expiry time into the number of whole seconds left,
+// rounding up so that a volume only reports 0 once it has actually expired.
+func remainingTTL(expiresAt time.Time) *uint {
+	if expiresAt.IsZero() {
+		return nil
+	}
+
+	var seconds uint
+
+	remaining := time.Until(expiresAt)
+	if remaining > 0 {
+		seconds = uint((remaining + time.Second - 1) / time.Second)
+	}
+
+	return &seconds
 }
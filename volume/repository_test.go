@@ -1,17 +1,22 @@
 package volume_test
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
-	"github.com/concourse/go-archive/tgzfs"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"code.cloudfoundry.org/lager"
 	"github.com/concourse/baggageclaim/uidgid/uidgidfakes"
 	"github.com/concourse/baggageclaim/volume"
 	"github.com/concourse/baggageclaim/volume/volumefakes"
@@ -40,6 +45,8 @@ var _ = Describe("Repository", func() {
 			fakeLocker,
 			fakePrivilegedNamespacer,
 			fakeUnprivilegedNamespacer,
+			nil,
+			0,
 		)
 	})
 
@@ -48,25 +55,55 @@ var _ = Describe("Repository", func() {
 			fakeStrategy *volumefakes.FakeStrategy
 			properties   volume.Properties
 			privileged   bool
+			quotaBytes   uint64
+			ttl          time.Duration
+			readOnly     bool
 
 			createdVolume volume.Volume
 			createErr     error
+
+			events       <-chan volume.Event
+			cancelEvents context.CancelFunc
 		)
 
+		publishedEvents := func() []volume.EventType {
+			types := []volume.EventType{}
+			for {
+				select {
+				case event := <-events:
+					types = append(types, event.Type)
+				default:
+					return types
+				}
+			}
+		}
+
 		BeforeEach(func() {
+			var ctx context.Context
+			ctx, cancelEvents = context.WithCancel(context.Background())
+			events = repository.Subscribe(ctx)
+
 			fakeStrategy = new(volumefakes.FakeStrategy)
 			properties = volume.Properties{"some": "properties"}
 			privileged = false
+			quotaBytes = 0
+			ttl = 0
+			readOnly = false
+		})
+
+		AfterEach(func() {
+			cancelEvents()
 		})
 
 		JustBeforeEach(func() {
-			createdVolume, createErr = repository.CreateVolume(
-				context.Background(),
-				"some-handle",
-				fakeStrategy,
-				properties,
-				privileged,
-			)
+			createdVolume, createErr = repository.CreateVolume(context.Background(), "some-handle", volume.VolumeSpec{
+				Strategy:   fakeStrategy,
+				Properties: properties,
+				Privileged: privileged,
+				QuotaBytes: quotaBytes,
+				TTL:        ttl,
+				ReadOnly:   readOnly,
+			})
 		})
 
 		Context("when a new volume can be materialized with the strategy", func() {
@@ -99,6 +136,10 @@ var _ = Describe("Repository", func() {
 						Expect(createErr).To(BeNil())
 					})
 
+					It("publishes that the volume was created and initialized", func() {
+						Expect(publishedEvents()).To(Equal([]volume.EventType{volume.EventCreated, volume.EventInitialized}))
+					})
+
 					It("returns the created volume", func() {
 						Expect(createdVolume).To(Equal(volume.Volume{
 							Handle:     "live-handle",
@@ -110,7 +151,9 @@ var _ = Describe("Repository", func() {
 					It("materialized with the correct volume, fs, and driver", func() {
 						_, handle, fs, _ := fakeStrategy.MaterializeArgsForCall(0)
 						Expect(handle).ToNot(BeEmpty())
-						Expect(fs).To(Equal(fakeFilesystem))
+
+						fakeFilesystem.TempDirReturns("some-temp-dir")
+						Expect(fs.TempDir()).To(Equal("some-temp-dir"))
 					})
 
 					It("does not destroy the volume (due to busted cleanup logic)", func() {
@@ -186,6 +229,180 @@ var _ = Describe("Repository", func() {
 					})
 				})
 
+				Context("when a quota is requested", func() {
+					BeforeEach(func() {
+						quotaBytes = 1024
+						fakeInitVolume.InitializeReturns(new(volumefakes.FakeFilesystemLiveVolume), nil)
+					})
+
+					It("sets the quota on the initializing volume", func() {
+						Expect(fakeInitVolume.SetQuotaCallCount()).To(Equal(1))
+						Expect(fakeInitVolume.SetQuotaArgsForCall(0)).To(Equal(uint64(1024)))
+					})
+
+					Context("when setting the quota fails", func() {
+						BeforeEach(func() {
+							fakeInitVolume.SetQuotaReturns(volume.ErrQuotaNotSupported)
+						})
+
+						It("returns the error", func() {
+							Expect(createErr).To(Equal(volume.ErrQuotaNotSupported))
+						})
+
+						It("destroys the initializing volume", func() {
+							Expect(fakeInitVolume.DestroyCallCount()).To(Equal(1))
+						})
+					})
+				})
+
+				Context("when no quota is requested", func() {
+					BeforeEach(func() {
+						fakeInitVolume.InitializeReturns(new(volumefakes.FakeFilesystemLiveVolume), nil)
+					})
+
+					It("does not set a quota", func() {
+						Expect(fakeInitVolume.SetQuotaCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when a ttl is requested", func() {
+					BeforeEach(func() {
+						ttl = time.Minute
+						fakeInitVolume.InitializeReturns(new(volumefakes.FakeFilesystemLiveVolume), nil)
+					})
+
+					It("stores when the volume expires", func() {
+						Expect(fakeInitVolume.StoreExpiresAtCallCount()).To(Equal(1))
+						Expect(fakeInitVolume.StoreExpiresAtArgsForCall(0)).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+					})
+
+					It("returns the remaining ttl", func() {
+						Expect(createdVolume.TTL).ToNot(BeNil())
+						Expect(*createdVolume.TTL).To(BeNumerically("~", 60, 1))
+					})
+				})
+
+				Context("when no ttl is requested", func() {
+					BeforeEach(func() {
+						fakeInitVolume.InitializeReturns(new(volumefakes.FakeFilesystemLiveVolume), nil)
+					})
+
+					It("does not store an expiry", func() {
+						Expect(fakeInitVolume.StoreExpiresAtCallCount()).To(Equal(0))
+						Expect(createdVolume.TTL).To(BeNil())
+					})
+				})
+
+				Context("when the volume is to be read-only", func() {
+					var (
+						fakeLiveVolume *volumefakes.FakeFilesystemLiveVolume
+						dataDir        string
+					)
+
+					BeforeEach(func() {
+						readOnly = true
+
+						var err error
+						dataDir, err = ioutil.TempDir("", "create-read-only")
+						Expect(err).ToNot(HaveOccurred())
+
+						fakeLiveVolume = new(volumefakes.FakeFilesystemLiveVolume)
+						fakeLiveVolume.DataPathReturns(dataDir)
+						fakeInitVolume.InitializeReturns(fakeLiveVolume, nil)
+					})
+
+					AfterEach(func() {
+						os.RemoveAll(dataDir)
+					})
+
+					It("makes the initialized volume read-only", func() {
+						Expect(createErr).ToNot(HaveOccurred())
+						Expect(fakeLiveVolume.SetReadOnlyCallCount()).To(Equal(1))
+						Expect(createdVolume.ReadOnly).To(BeTrue())
+					})
+
+					It("records the digest of its contents", func() {
+						expected, err := volume.TreeDigest(dataDir)
+						Expect(err).ToNot(HaveOccurred())
+
+						Expect(fakeLiveVolume.StoreDigestCallCount()).To(Equal(1))
+						Expect(fakeLiveVolume.StoreDigestArgsForCall(0)).To(Equal(expected))
+					})
+
+					Context("when making it read-only fails", func() {
+						disaster := errors.New("nope")
+
+						BeforeEach(func() {
+							fakeLiveVolume.SetReadOnlyReturns(disaster)
+							fakeFilesystem.LookupVolumeReturns(fakeLiveVolume, true, nil)
+						})
+
+						It("returns the error", func() {
+							Expect(createErr).To(Equal(disaster))
+						})
+
+						It("destroys the volume", func() {
+							Expect(fakeLiveVolume.DestroyCallCount()).To(Equal(1))
+						})
+
+						Context("when destroying it", func() {
+							BeforeEach(func() {
+								fakeLiveVolume.DestroyStub = func() error {
+									Expect(fakeLocker.LockCallCount()).To(BeNumerically(">", fakeLocker.UnlockCallCount()))
+									Expect(fakeLocker.LockArgsForCall(fakeLocker.LockCallCount() - 1)).To(Equal("some-handle"))
+									return nil
+								}
+							})
+
+							It("holds the volume's lock", func() {
+								Expect(fakeLiveVolume.DestroyCallCount()).To(Equal(1))
+							})
+						})
+
+						Context("when it is layered on an image layer", func() {
+							var fakeLayerVolume *volumefakes.FakeFilesystemLiveVolume
+
+							BeforeEach(func() {
+								fakeLayerVolume = new(volumefakes.FakeFilesystemLiveVolume)
+								fakeLayerVolume.HandleReturns("layer-handle")
+								fakeLayerVolume.LoadLayerReturns("sha256:some-layer", nil)
+								fakeLiveVolume.ParentReturns(fakeLayerVolume, true, nil)
+							})
+
+							It("releases the layer", func() {
+								Expect(fakeLayerVolume.DestroyCallCount()).To(Equal(1))
+							})
+						})
+
+						It("publishes that the volume was destroyed", func() {
+							Expect(publishedEvents()).To(Equal([]volume.EventType{volume.EventCreated, volume.EventDestroyed}))
+						})
+
+						Context("when destroying the volume fails", func() {
+							BeforeEach(func() {
+								fakeLiveVolume.DestroyReturns(errors.New("nope"))
+							})
+
+							It("publishes that the volume was initialized, as it is still there", func() {
+								Expect(publishedEvents()).To(Equal([]volume.EventType{volume.EventCreated, volume.EventInitialized}))
+							})
+						})
+					})
+				})
+
+				Context("when the volume is not to be read-only", func() {
+					var fakeLiveVolume *volumefakes.FakeFilesystemLiveVolume
+
+					BeforeEach(func() {
+						fakeLiveVolume = new(volumefakes.FakeFilesystemLiveVolume)
+						fakeInitVolume.InitializeReturns(fakeLiveVolume, nil)
+					})
+
+					It("leaves it writable", func() {
+						Expect(fakeLiveVolume.SetReadOnlyCallCount()).To(Equal(0))
+					})
+				})
+
 				Context("when the volume cannot be initialized", func() {
 					disaster := errors.New("nope")
 
@@ -221,6 +438,20 @@ var _ = Describe("Repository", func() {
 				It("returns the error", func() {
 					Expect(createErr).To(Equal(disaster))
 				})
+
+				It("publishes that the volume was destroyed", func() {
+					Expect(publishedEvents()).To(Equal([]volume.EventType{volume.EventCreated, volume.EventDestroyed}))
+				})
+
+				Context("when cleaning up the volume fails", func() {
+					BeforeEach(func() {
+						fakeInitVolume.DestroyReturns(errors.New("nope"))
+					})
+
+					It("still publishes that the volume was destroyed", func() {
+						Expect(publishedEvents()).To(Equal([]volume.EventType{volume.EventCreated, volume.EventDestroyed}))
+					})
+				})
 			})
 
 			Context("when storing the privileged fails", func() {
@@ -244,6 +475,73 @@ var _ = Describe("Repository", func() {
 			})
 		})
 
+		Context("when the strategy creates a copy-on-write child", func() {
+			var (
+				fakeParentVolume *volumefakes.FakeFilesystemLiveVolume
+				fakeInitVolume   *volumefakes.FakeFilesystemInitVolume
+
+				unlockedWhenInitialized int
+			)
+
+			BeforeEach(func() {
+				fakeInitVolume = new(volumefakes.FakeFilesystemInitVolume)
+				fakeInitVolume.InitializeStub = func() (volume.FilesystemLiveVolume, error) {
+					unlockedWhenInitialized = fakeLocker.UnlockCallCount()
+					return new(volumefakes.FakeFilesystemLiveVolume), nil
+				}
+
+				fakeParentVolume = new(volumefakes.FakeFilesystemLiveVolume)
+				fakeParentVolume.HandleReturns("parent-handle")
+				fakeParentVolume.NewSubvolumeReturns(fakeInitVolume, nil)
+				fakeFilesystem.LookupVolumeReturns(fakeParentVolume, true, nil)
+
+				fakeStrategy.MaterializeStub = func(logger lager.Logger, handle string, fs volume.Filesystem, streamer volume.Streamer) (volume.FilesystemInitVolume, error) {
+					parent, _, err := fs.LookupVolume("parent-handle")
+					Expect(err).ToNot(HaveOccurred())
+
+					return parent.NewSubvolume(handle)
+				}
+			})
+
+			It("holds the parent's lock until the child is initialized", func() {
+				Expect(createErr).ToNot(HaveOccurred())
+
+				Expect(fakeLocker.LockCallCount()).To(Equal(1))
+				Expect(fakeLocker.LockArgsForCall(0)).To(Equal("parent-handle"))
+				Expect(unlockedWhenInitialized).To(Equal(0))
+				Expect(fakeLocker.UnlockCallCount()).To(Equal(1))
+				Expect(fakeLocker.UnlockArgsForCall(0)).To(Equal("parent-handle"))
+			})
+
+			Context("when the child cannot be initialized", func() {
+				BeforeEach(func() {
+					fakeInitVolume.InitializeStub = nil
+					fakeInitVolume.InitializeReturns(nil, errors.New("nope"))
+				})
+
+				It("releases the parent's lock once the child is destroyed", func() {
+					Expect(createErr).To(HaveOccurred())
+
+					Expect(fakeInitVolume.DestroyCallCount()).To(Equal(1))
+					Expect(fakeLocker.UnlockCallCount()).To(Equal(1))
+					Expect(fakeLocker.UnlockArgsForCall(0)).To(Equal("parent-handle"))
+				})
+			})
+
+			Context("when the child cannot be created", func() {
+				BeforeEach(func() {
+					fakeParentVolume.NewSubvolumeReturns(nil, errors.New("nope"))
+				})
+
+				It("releases the parent's lock", func() {
+					Expect(createErr).To(HaveOccurred())
+
+					Expect(fakeLocker.UnlockCallCount()).To(Equal(1))
+					Expect(fakeLocker.UnlockArgsForCall(0)).To(Equal("parent-handle"))
+				})
+			})
+		})
+
 		Context("when creating the volume fails", func() {
 			disaster := errors.New("nope")
 
@@ -254,6 +552,10 @@ var _ = Describe("Repository", func() {
 			It("returns the error", func() {
 				Expect(createErr).To(Equal(disaster))
 			})
+
+			It("publishes nothing", func() {
+				Expect(publishedEvents()).To(BeEmpty())
+			})
 		})
 	})
 
@@ -287,6 +589,74 @@ var _ = Describe("Repository", func() {
 				})
 			})
 
+			Context("when the volume is layered on image layer volumes", func() {
+				var (
+					fakeLayerVolume     *volumefakes.FakeFilesystemLiveVolume
+					fakeBaseLayerVolume *volumefakes.FakeFilesystemLiveVolume
+				)
+
+				BeforeEach(func() {
+					fakeBaseLayerVolume = new(volumefakes.FakeFilesystemLiveVolume)
+					fakeBaseLayerVolume.HandleReturns("some-volume-layer-0")
+					fakeBaseLayerVolume.LoadLayerReturns("sha256:base", nil)
+
+					fakeLayerVolume = new(volumefakes.FakeFilesystemLiveVolume)
+					fakeLayerVolume.HandleReturns("some-volume-layer-1")
+					fakeLayerVolume.LoadLayerReturns("sha256:middle", nil)
+					fakeLayerVolume.ParentReturns(fakeBaseLayerVolume, true, nil)
+
+					fakeVolume.ParentReturns(fakeLayerVolume, true, nil)
+				})
+
+				Context("when nothing else is layered on them", func() {
+					BeforeEach(func() {
+						fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeLayerVolume, fakeBaseLayerVolume}, nil)
+
+						fakeLayerVolume.DestroyStub = func() error {
+							fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeBaseLayerVolume}, nil)
+							return nil
+						}
+					})
+
+					It("destroys them too", func() {
+						Expect(destroyErr).ToNot(HaveOccurred())
+
+						Expect(fakeLayerVolume.DestroyCallCount()).To(Equal(1))
+						Expect(fakeBaseLayerVolume.DestroyCallCount()).To(Equal(1))
+					})
+				})
+
+				Context("when another volume is layered on one of them", func() {
+					BeforeEach(func() {
+						otherVolume := new(volumefakes.FakeFilesystemLiveVolume)
+						otherVolume.ParentReturns(fakeBaseLayerVolume, true, nil)
+
+						fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeLayerVolume, fakeBaseLayerVolume, otherVolume}, nil)
+					})
+
+					It("destroys only those above it", func() {
+						Expect(destroyErr).ToNot(HaveOccurred())
+
+						Expect(fakeLayerVolume.DestroyCallCount()).To(Equal(1))
+						Expect(fakeBaseLayerVolume.DestroyCallCount()).To(BeZero())
+					})
+				})
+			})
+
+			Context("when the volume's parent is not an image layer volume", func() {
+				var fakeParentVolume *volumefakes.FakeFilesystemLiveVolume
+
+				BeforeEach(func() {
+					fakeParentVolume = new(volumefakes.FakeFilesystemLiveVolume)
+					fakeVolume.ParentReturns(fakeParentVolume, true, nil)
+				})
+
+				It("leaves it alone", func() {
+					Expect(destroyErr).ToNot(HaveOccurred())
+					Expect(fakeParentVolume.DestroyCallCount()).To(BeZero())
+				})
+			})
+
 			Context("when destroying the volume fails", func() {
 				disaster := errors.New("nope")
 
@@ -416,6 +786,109 @@ var _ = Describe("Repository", func() {
 		})
 	})
 
+	Describe("ReapVolumes", func() {
+		var (
+			fakeExpired *volumefakes.FakeFilesystemLiveVolume
+			fakeChild   *volumefakes.FakeFilesystemLiveVolume
+			fakeAlive   *volumefakes.FakeFilesystemLiveVolume
+			fakeNoTTL   *volumefakes.FakeFilesystemLiveVolume
+
+			reaped  int
+			reapErr error
+		)
+
+		BeforeEach(func() {
+			fakeExpired = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeChild = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeAlive = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeNoTTL = new(volumefakes.FakeFilesystemLiveVolume)
+
+			fakeExpired.HandleReturns("expired")
+			fakeChild.HandleReturns("child")
+			fakeAlive.HandleReturns("alive")
+			fakeNoTTL.HandleReturns("no-ttl")
+
+			fakeExpired.LoadExpiresAtReturns(time.Now().Add(-time.Minute), nil)
+			fakeAlive.LoadExpiresAtReturns(time.Now().Add(time.Minute), nil)
+
+			fakeChild.ParentReturns(fakeExpired, true, nil)
+
+			fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{
+				fakeExpired,
+				fakeChild,
+				fakeAlive,
+				fakeNoTTL,
+			}, nil)
+			fakeFilesystem.LookupVolumeStub = func(handle string) (volume.FilesystemLiveVolume, bool, error) {
+				switch handle {
+				case "expired":
+					return fakeExpired, true, nil
+				case "child":
+					return fakeChild, true, nil
+				}
+
+				return nil, false, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			reaped, reapErr = repository.ReapVolumes(context.Background())
+		})
+
+		It("destroys the expired volumes and their descendants", func() {
+			Expect(reapErr).ToNot(HaveOccurred())
+			Expect(reaped).To(Equal(1))
+
+			Expect(fakeExpired.DestroyCallCount()).To(Equal(1))
+			Expect(fakeChild.DestroyCallCount()).To(Equal(1))
+			Expect(fakeAlive.DestroyCallCount()).To(BeZero())
+			Expect(fakeNoTTL.DestroyCallCount()).To(BeZero())
+		})
+
+		It("only loads the volumes' expiry", func() {
+			Expect(fakeAlive.LoadPropertiesCallCount()).To(BeZero())
+			Expect(fakeAlive.LoadPrivilegedCallCount()).To(BeZero())
+		})
+
+		Context("when the volume's TTL is set after it is listed", func() {
+			BeforeEach(func() {
+				fakeExpired.LoadExpiresAtReturnsOnCall(1, time.Now().Add(time.Minute), nil)
+			})
+
+			It("checks again under its lock and leaves it", func() {
+				Expect(reaped).To(BeZero())
+				Expect(fakeExpired.DestroyCallCount()).To(BeZero())
+				Expect(fakeChild.DestroyCallCount()).To(BeZero())
+
+				Expect(fakeLocker.LockCallCount()).To(Equal(1))
+				Expect(fakeLocker.LockArgsForCall(0)).To(Equal("expired"))
+			})
+		})
+
+		Context("when destroying an expired volume fails", func() {
+			BeforeEach(func() {
+				fakeExpired.DestroyReturns(errors.New("nope"))
+			})
+
+			It("carries on with the rest", func() {
+				Expect(reapErr).ToNot(HaveOccurred())
+				Expect(reaped).To(BeZero())
+			})
+		})
+
+		Context("when listing the volumes fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeFilesystem.ListVolumesReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(reapErr).To(Equal(disaster))
+			})
+		})
+	})
+
 	Describe("ListVolumes", func() {
 		var (
 			queryProperties volume.Properties
@@ -648,42 +1121,284 @@ var _ = Describe("Repository", func() {
 		})
 	})
 
-	Describe("GetVolume", func() {
+	Describe("VisitVolumes", func() {
 		var (
-			foundVolume volume.Volume
-			found       bool
-			getErr      error
+			query volume.VolumeQuery
+
+			visitErr error
+			visited  volume.Volumes
+			walkErr  error
 		)
 
+		var fakeVolume1 *volumefakes.FakeFilesystemLiveVolume
+		var fakeVolume2 *volumefakes.FakeFilesystemLiveVolume
+		var fakeVolume3 *volumefakes.FakeFilesystemLiveVolume
+
+		createdAt := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		BeforeEach(func() {
+			query = volume.VolumeQuery{}
+			visitErr = nil
+			visited = nil
+
+			fakeVolume1 = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeVolume1.HandleReturns("handle-1")
+			fakeVolume1.LoadPropertiesReturns(volume.Properties{"a": "a"}, nil)
+			fakeVolume1.LoadCreatedAtReturns(createdAt.Add(time.Hour), nil)
+			fakeVolume1.UsageReturns(volume.VolumeUsage{ExclusiveBytes: 10}, nil)
+
+			fakeVolume2 = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeVolume2.HandleReturns("handle-2")
+			fakeVolume2.LoadPropertiesReturns(volume.Properties{}, nil)
+			fakeVolume2.LoadCreatedAtReturns(createdAt.Add(2*time.Hour), nil)
+			fakeVolume2.UsageReturns(volume.VolumeUsage{ExclusiveBytes: 30}, nil)
+
+			fakeVolume3 = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeVolume3.HandleReturns("handle-3")
+			fakeVolume3.LoadPropertiesReturns(volume.Properties{"a": "a"}, nil)
+			fakeVolume3.LoadCreatedAtReturns(createdAt, nil)
+			fakeVolume3.UsageReturns(volume.VolumeUsage{ExclusiveBytes: 20}, nil)
+
+			fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{
+				fakeVolume3,
+				fakeVolume1,
+				fakeVolume2,
+			}, nil)
+		})
+
 		JustBeforeEach(func() {
-			foundVolume, found, getErr = repository.GetVolume(context.Background(), "some-volume")
+			walkErr = repository.VisitVolumes(context.Background(), query, func(vol volume.Volume) error {
+				visited = append(visited, vol)
+				return visitErr
+			})
 		})
 
-		Context("when the volume is found in the filesystem", func() {
-			var fakeVolume *volumefakes.FakeFilesystemLiveVolume
+		handles := func() []string {
+			handles := []string{}
+			for _, vol := range visited {
+				handles = append(handles, vol.Handle)
+			}
 
+			return handles
+		}
+
+		It("visits every volume in order of their handles", func() {
+			Expect(walkErr).ToNot(HaveOccurred())
+			Expect(handles()).To(Equal([]string{"handle-1", "handle-2", "handle-3"}))
+		})
+
+		It("includes when the volumes were created", func() {
+			Expect(*visited[0].CreatedAt).To(Equal(createdAt.Add(time.Hour)))
+		})
+
+		It("does not measure their usage", func() {
+			Expect(fakeVolume1.UsageCallCount()).To(BeZero())
+			Expect(visited[0].Usage).To(BeNil())
+		})
+
+		Context("when properties are given", func() {
 			BeforeEach(func() {
-				fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
-				fakeVolume.HandleReturns("some-volume")
-				fakeVolume.DataPathReturns("some-data-path")
-				fakeVolume.LoadPropertiesReturns(volume.Properties{"a": "a", "b": "b"}, nil)
-				fakeVolume.LoadPrivilegedReturns(true, nil)
+				query.Properties = volume.Properties{"a": "a"}
+			})
 
-				fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+			It("only visits volumes whose properties match", func() {
+				Expect(handles()).To(Equal([]string{"handle-1", "handle-3"}))
 			})
+		})
 
-			It("succeeds", func() {
-				Expect(getErr).ToNot(HaveOccurred())
+		Context("when a limit is given", func() {
+			BeforeEach(func() {
+				query.Limit = 2
 			})
 
-			It("found it by looking for the right handle", func() {
-				handle := fakeFilesystem.LookupVolumeArgsForCall(0)
-				Expect(handle).To(Equal("some-volume"))
+			It("stops after that many volumes", func() {
+				Expect(handles()).To(Equal([]string{"handle-1", "handle-2"}))
 			})
 
-			It("returns the volume and true", func() {
-				Expect(found).To(BeTrue())
-				Expect(foundVolume).To(Equal(volume.Volume{
+			It("does not load the metadata of the volumes it does not visit", func() {
+				Expect(fakeVolume3.LoadPropertiesCallCount()).To(BeZero())
+			})
+
+			Context("when volumes are filtered out by their properties", func() {
+				BeforeEach(func() {
+					query.Properties = volume.Properties{"a": "a"}
+				})
+
+				It("does not count them", func() {
+					Expect(handles()).To(Equal([]string{"handle-1", "handle-3"}))
+				})
+			})
+		})
+
+		Context("when continuing after a volume", func() {
+			BeforeEach(func() {
+				query.After = &volume.VolumeCursor{Handle: "handle-1"}
+			})
+
+			It("only visits the volumes that sort after it", func() {
+				Expect(handles()).To(Equal([]string{"handle-2", "handle-3"}))
+			})
+		})
+
+		Context("when sorting by creation time", func() {
+			BeforeEach(func() {
+				query.SortBy = volume.SortByCreatedAt
+			})
+
+			It("visits the oldest volumes first", func() {
+				Expect(handles()).To(Equal([]string{"handle-3", "handle-1", "handle-2"}))
+			})
+
+			Context("when descending", func() {
+				BeforeEach(func() {
+					query.Descending = true
+				})
+
+				It("visits the newest volumes first", func() {
+					Expect(handles()).To(Equal([]string{"handle-2", "handle-1", "handle-3"}))
+				})
+			})
+
+			Context("when volumes were created at the same time", func() {
+				BeforeEach(func() {
+					fakeVolume2.LoadCreatedAtReturns(createdAt, nil)
+				})
+
+				It("orders them by handle", func() {
+					Expect(handles()).To(Equal([]string{"handle-2", "handle-3", "handle-1"}))
+				})
+			})
+
+			Context("when continuing after a volume", func() {
+				BeforeEach(func() {
+					query.After = &volume.VolumeCursor{Handle: "handle-1", CreatedAt: createdAt.Add(time.Hour)}
+				})
+
+				It("only visits the volumes created after it", func() {
+					Expect(handles()).To(Equal([]string{"handle-2"}))
+				})
+			})
+
+			Context("when a volume disappears", func() {
+				BeforeEach(func() {
+					fakeVolume1.LoadCreatedAtReturns(time.Time{}, volume.ErrVolumeDoesNotExist)
+				})
+
+				It("is not visited", func() {
+					Expect(walkErr).ToNot(HaveOccurred())
+					Expect(handles()).To(Equal([]string{"handle-3", "handle-2"}))
+				})
+			})
+		})
+
+		Context("when sorting by size", func() {
+			BeforeEach(func() {
+				query.SortBy = volume.SortBySize
+				query.Descending = true
+			})
+
+			It("visits the volumes in order of their exclusive bytes", func() {
+				Expect(handles()).To(Equal([]string{"handle-2", "handle-3", "handle-1"}))
+			})
+
+			It("includes their usage", func() {
+				Expect(visited[0].Usage).To(Equal(&volume.VolumeUsage{ExclusiveBytes: 30}))
+			})
+
+			It("does not measure the volumes again for the next page", func() {
+				Expect(fakeVolume2.UsageCallCount()).To(Equal(1))
+
+				query.Limit = 1
+				query.After = &volume.VolumeCursor{Handle: "handle-2", Size: 30}
+
+				visited = nil
+				err := repository.VisitVolumes(context.Background(), query, func(vol volume.Volume) error {
+					visited = append(visited, vol)
+					return nil
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(handles()).To(Equal([]string{"handle-3"}))
+				Expect(visited[0].Usage).To(Equal(&volume.VolumeUsage{ExclusiveBytes: 20}))
+
+				Expect(fakeVolume1.UsageCallCount()).To(Equal(1))
+				Expect(fakeVolume2.UsageCallCount()).To(Equal(1))
+				Expect(fakeVolume3.UsageCallCount()).To(Equal(1))
+			})
+
+			Context("when measuring a volume fails", func() {
+				BeforeEach(func() {
+					fakeVolume3.UsageReturns(volume.VolumeUsage{}, errors.New("nope"))
+				})
+
+				It("leaves it out", func() {
+					Expect(walkErr).ToNot(HaveOccurred())
+					Expect(handles()).To(Equal([]string{"handle-2", "handle-1"}))
+				})
+			})
+		})
+
+		Context("when the visit fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				visitErr = disaster
+			})
+
+			It("stops and returns the error", func() {
+				Expect(walkErr).To(Equal(disaster))
+				Expect(visited).To(HaveLen(1))
+			})
+		})
+
+		Context("when listing the volumes on the filesystem fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeFilesystem.ListVolumesReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(walkErr).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("GetVolume", func() {
+		var (
+			foundVolume volume.Volume
+			found       bool
+			getErr      error
+		)
+
+		JustBeforeEach(func() {
+			foundVolume, found, getErr = repository.GetVolume(context.Background(), "some-volume")
+		})
+
+		Context("when the volume is found in the filesystem", func() {
+			var fakeVolume *volumefakes.FakeFilesystemLiveVolume
+
+			BeforeEach(func() {
+				fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+				fakeVolume.HandleReturns("some-volume")
+				fakeVolume.DataPathReturns("some-data-path")
+				fakeVolume.LoadPropertiesReturns(volume.Properties{"a": "a", "b": "b"}, nil)
+				fakeVolume.LoadPrivilegedReturns(true, nil)
+
+				fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+			})
+
+			It("succeeds", func() {
+				Expect(getErr).ToNot(HaveOccurred())
+			})
+
+			It("found it by looking for the right handle", func() {
+				handle := fakeFilesystem.LookupVolumeArgsForCall(0)
+				Expect(handle).To(Equal("some-volume"))
+			})
+
+			It("returns the volume and true", func() {
+				Expect(found).To(BeTrue())
+				Expect(foundVolume).To(Equal(volume.Volume{
 					Handle:     "some-volume",
 					Path:       "some-data-path",
 					Properties: volume.Properties{"a": "a", "b": "b"},
@@ -949,6 +1664,18 @@ var _ = Describe("Repository", func() {
 					Expect(setErr).To(Equal(disaster))
 				})
 			})
+
+			Context("when the volume is read-only", func() {
+				BeforeEach(func() {
+					fakeVolume.LoadReadOnlyReturns(true, nil)
+				})
+
+				It("returns ErrVolumeIsReadOnly without changing it", func() {
+					Expect(setErr).To(Equal(volume.ErrVolumeIsReadOnly))
+					Expect(fakePrivilegedNamespacer.NamespacePathCallCount()).To(BeZero())
+					Expect(fakeVolume.StorePrivilegedCallCount()).To(BeZero())
+				})
+			})
 		})
 
 		Context("when the volume is not found on the filesystem", func() {
@@ -974,99 +1701,138 @@ var _ = Describe("Repository", func() {
 		})
 	})
 
-	Describe("VolumeParent", func() {
-		var (
-			parent    volume.Volume
-			found     bool
-			parentErr error
-		)
+	Describe("SetReadOnly", func() {
+		var setErr error
 
 		JustBeforeEach(func() {
-			parent, found, parentErr = repository.VolumeParent(context.Background(), "some-volume")
+			setErr = repository.SetReadOnly(context.Background(), "some-volume")
 		})
 
 		Context("when the volume is found in the filesystem", func() {
-			var fakeVolume *volumefakes.FakeFilesystemLiveVolume
+			var (
+				fakeVolume *volumefakes.FakeFilesystemLiveVolume
+				dataDir    string
+			)
 
 			BeforeEach(func() {
+				var err error
+				dataDir, err = ioutil.TempDir("", "set-read-only")
+				Expect(err).ToNot(HaveOccurred())
+
 				fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
 				fakeVolume.HandleReturns("some-volume")
-				fakeVolume.DataPathReturns("some-data-path")
-				fakeVolume.LoadPropertiesReturns(volume.Properties{"a": "a", "b": "b"}, nil)
-				fakeVolume.LoadPrivilegedReturns(false, nil)
+				fakeVolume.DataPathReturns(dataDir)
 
 				fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
 			})
 
-			Context("when the volume has a parent", func() {
-				var parentVolume *volumefakes.FakeFilesystemLiveVolume
+			AfterEach(func() {
+				os.RemoveAll(dataDir)
+			})
 
-				BeforeEach(func() {
-					parentVolume = new(volumefakes.FakeFilesystemLiveVolume)
-					parentVolume.HandleReturns("parent-volume")
-					parentVolume.DataPathReturns("parent-data-path")
-					parentVolume.LoadPropertiesReturns(volume.Properties{"parent": "property"}, nil)
-					parentVolume.LoadPrivilegedReturns(true, nil)
+			It("makes the volume read-only", func() {
+				Expect(setErr).ToNot(HaveOccurred())
+				Expect(fakeVolume.SetReadOnlyCallCount()).To(Equal(1))
+			})
 
-					fakeVolume.ParentReturns(parentVolume, true, nil)
-				})
+			It("records the digest of its contents", func() {
+				expected, err := volume.TreeDigest(dataDir)
+				Expect(err).ToNot(HaveOccurred())
 
-				It("succeeds", func() {
-					Expect(parentErr).ToNot(HaveOccurred())
+				Expect(fakeVolume.StoreDigestCallCount()).To(Equal(1))
+				Expect(fakeVolume.StoreDigestArgsForCall(0)).To(Equal(expected))
+			})
+
+			It("locks the volume", func() {
+				Expect(fakeLocker.LockCallCount()).To(Equal(1))
+				Expect(fakeLocker.LockArgsForCall(0)).To(Equal("some-volume"))
+				Expect(fakeLocker.UnlockCallCount()).To(Equal(1))
+			})
+
+			Context("when making the volume read-only fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					fakeVolume.SetReadOnlyReturns(disaster)
 				})
 
-				It("found the child volume by looking for the right handle", func() {
-					handle := fakeFilesystem.LookupVolumeArgsForCall(0)
-					Expect(handle).To(Equal("some-volume"))
+				It("returns the error", func() {
+					Expect(setErr).To(Equal(disaster))
 				})
 
-				It("returns the parent volume and true", func() {
-					Expect(found).To(BeTrue())
-					Expect(parent).To(Equal(volume.Volume{
-						Handle:     "parent-volume",
-						Path:       "parent-data-path",
-						Properties: volume.Properties{"parent": "property"},
-						Privileged: true,
-					}))
+				It("does not record a digest", func() {
+					Expect(fakeVolume.StoreDigestCallCount()).To(BeZero())
 				})
+			})
+		})
 
-				Context("when hydrating the parent volume fails", func() {
-					disaster := errors.New("nope")
+		Context("when the volume is not found on the filesystem", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+			})
 
-					BeforeEach(func() {
-						parentVolume.LoadPropertiesReturns(nil, disaster)
-					})
+			It("returns ErrVolumeDoesNotExist", func() {
+				Expect(setErr).To(Equal(volume.ErrVolumeDoesNotExist))
+			})
+		})
+	})
 
-					It("returns the special error", func() {
-						Expect(parentErr).To(Equal(volume.ErrVolumeIsCorrupted))
-					})
-				})
+	Describe("SetTTL", func() {
+		var (
+			ttl    time.Duration
+			setErr error
+		)
+
+		BeforeEach(func() {
+			ttl = time.Minute
+		})
+
+		JustBeforeEach(func() {
+			setErr = repository.SetTTL(context.Background(), "some-volume", ttl)
+		})
+
+		Context("when the volume is found in the filesystem", func() {
+			var fakeVolume *volumefakes.FakeFilesystemLiveVolume
+
+			BeforeEach(func() {
+				fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+				fakeVolume.HandleReturns("some-volume")
+
+				fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
 			})
 
-			Context("when the volume does not have a parent", func() {
-				BeforeEach(func() {
-					fakeVolume.ParentReturns(nil, false, nil)
-				})
+			It("stores when the volume expires", func() {
+				Expect(setErr).ToNot(HaveOccurred())
+				Expect(fakeVolume.StoreExpiresAtCallCount()).To(Equal(1))
+				Expect(fakeVolume.StoreExpiresAtArgsForCall(0)).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+			})
 
-				It("succeeds", func() {
-					Expect(parentErr).ToNot(HaveOccurred())
+			It("locks the volume", func() {
+				Expect(fakeLocker.LockCallCount()).To(Equal(1))
+				Expect(fakeLocker.LockArgsForCall(0)).To(Equal("some-volume"))
+				Expect(fakeLocker.UnlockCallCount()).To(Equal(1))
+			})
+
+			Context("when the ttl is zero", func() {
+				BeforeEach(func() {
+					ttl = 0
 				})
 
-				It("returns no volume and false", func() {
-					Expect(found).To(BeFalse())
-					Expect(parent).To(BeZero())
+				It("clears the expiry", func() {
+					Expect(fakeVolume.StoreExpiresAtCallCount()).To(Equal(1))
+					Expect(fakeVolume.StoreExpiresAtArgsForCall(0)).To(BeZero())
 				})
 			})
 
-			Context("when getting the parent volume fails", func() {
+			Context("when storing the expiry fails", func() {
 				disaster := errors.New("nope")
 
 				BeforeEach(func() {
-					fakeVolume.ParentReturns(nil, false, disaster)
+					fakeVolume.StoreExpiresAtReturns(disaster)
 				})
 
 				It("returns the error", func() {
-					Expect(parentErr).To(Equal(disaster))
+					Expect(setErr).To(Equal(disaster))
 				})
 			})
 		})
@@ -1077,22 +1843,1211 @@ var _ = Describe("Repository", func() {
 			})
 
 			It("returns ErrVolumeDoesNotExist", func() {
-				Expect(parentErr).To(Equal(volume.ErrVolumeDoesNotExist))
+				Expect(setErr).To(Equal(volume.ErrVolumeDoesNotExist))
 			})
 		})
+	})
 
-		Context("when looking up the volume on the filesystem fails", func() {
-			disaster := errors.New("nope")
+	Describe("GetDigest", func() {
+		var (
+			dataDir    string
+			fakeVolume *volumefakes.FakeFilesystemLiveVolume
 
-			BeforeEach(func() {
-				fakeFilesystem.LookupVolumeReturns(nil, false, disaster)
-			})
+			digest string
+			getErr error
+		)
 
-			It("returns the error", func() {
-				Expect(parentErr).To(Equal(disaster))
-			})
+		BeforeEach(func() {
+			var err error
+			dataDir, err = ioutil.TempDir("", "get-digest")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(dataDir, "some-file"), []byte("some-content"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeVolume.HandleReturns("some-volume")
+			fakeVolume.DataPathReturns(dataDir)
+			fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
 		})
-	})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dataDir)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			digest, getErr = repository.GetDigest(context.Background(), "some-volume")
+		})
+
+		It("computes the digest under the volume's lock", func() {
+			Expect(getErr).ToNot(HaveOccurred())
+
+			expected, err := volume.TreeDigest(dataDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(digest).To(Equal(expected))
+
+			Expect(fakeLocker.LockCallCount()).To(Equal(1))
+			Expect(fakeLocker.LockArgsForCall(0)).To(Equal("some-volume"))
+			Expect(fakeLocker.UnlockCallCount()).To(Equal(1))
+		})
+
+		It("does not record the digest of a writable volume", func() {
+			Expect(fakeVolume.StoreDigestCallCount()).To(Equal(0))
+			Expect(fakeVolume.StorePropertiesCallCount()).To(Equal(0))
+		})
+
+		Context("when the volume is read-only", func() {
+			BeforeEach(func() {
+				fakeVolume.LoadReadOnlyReturns(true, nil)
+			})
+
+			It("records the digest", func() {
+				Expect(fakeVolume.StoreDigestCallCount()).To(Equal(1))
+				Expect(fakeVolume.StoreDigestArgsForCall(0)).To(Equal(digest))
+			})
+
+			Context("when its digest has already been recorded", func() {
+				BeforeEach(func() {
+					fakeVolume.LoadDigestReturns("sha256:recorded", nil)
+				})
+
+				It("returns it without recomputing it", func() {
+					Expect(digest).To(Equal("sha256:recorded"))
+					Expect(fakeVolume.StoreDigestCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when the volume does not exist", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+			})
+
+			It("returns ErrVolumeDoesNotExist", func() {
+				Expect(getErr).To(Equal(volume.ErrVolumeDoesNotExist))
+			})
+		})
+	})
+
+	Describe("GetUsage", func() {
+		var (
+			usage  volume.VolumeUsage
+			getErr error
+		)
+
+		JustBeforeEach(func() {
+			usage, getErr = repository.GetUsage(context.Background(), "some-volume")
+		})
+
+		Context("when the volume is found in the filesystem", func() {
+			var fakeVolume *volumefakes.FakeFilesystemLiveVolume
+
+			BeforeEach(func() {
+				fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+				fakeVolume.HandleReturns("some-volume")
+
+				fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+			})
+
+			Context("when getting the usage succeeds", func() {
+				BeforeEach(func() {
+					fakeVolume.UsageReturns(volume.VolumeUsage{
+						ExclusiveBytes: 1,
+						SharedBytes:    2,
+						Inodes:         3,
+					}, nil)
+				})
+
+				It("returns the usage", func() {
+					Expect(getErr).ToNot(HaveOccurred())
+					Expect(usage).To(Equal(volume.VolumeUsage{
+						ExclusiveBytes: 1,
+						SharedBytes:    2,
+						Inodes:         3,
+					}))
+				})
+			})
+
+			Context("when getting the usage fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					fakeVolume.UsageReturns(volume.VolumeUsage{}, disaster)
+				})
+
+				It("returns the error", func() {
+					Expect(getErr).To(Equal(disaster))
+				})
+			})
+		})
+
+		Context("when the volume is not found on the filesystem", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+			})
+
+			It("returns ErrVolumeDoesNotExist", func() {
+				Expect(getErr).To(Equal(volume.ErrVolumeDoesNotExist))
+			})
+		})
+	})
+
+	Describe("CollectOrphans", func() {
+		var (
+			reclaimed  int
+			collectErr error
+		)
+
+		JustBeforeEach(func() {
+			reclaimed, collectErr = repository.CollectOrphans(context.Background())
+		})
+
+		Context("when there are orphans", func() {
+			var (
+				fakeInitOrphan *volumefakes.FakeFilesystemVolume
+				fakeDeadOrphan *volumefakes.FakeFilesystemVolume
+			)
+
+			BeforeEach(func() {
+				fakeInitOrphan = new(volumefakes.FakeFilesystemVolume)
+				fakeInitOrphan.HandleReturns("init-orphan")
+
+				fakeDeadOrphan = new(volumefakes.FakeFilesystemVolume)
+				fakeDeadOrphan.HandleReturns("dead-orphan")
+
+				fakeFilesystem.ListOrphansReturns([]volume.FilesystemVolume{
+					fakeInitOrphan,
+					fakeDeadOrphan,
+				}, nil)
+			})
+
+			It("destroys all of them", func() {
+				Expect(collectErr).ToNot(HaveOccurred())
+				Expect(reclaimed).To(Equal(2))
+
+				Expect(fakeInitOrphan.DestroyCallCount()).To(Equal(1))
+				Expect(fakeDeadOrphan.DestroyCallCount()).To(Equal(1))
+			})
+
+			It("counts them in the orphan stats", func() {
+				fakeFilesystem.ListOrphansReturns([]volume.FilesystemVolume{}, nil)
+
+				stats, err := repository.GetOrphanStats(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stats).To(Equal(volume.OrphanStats{
+					Pending:   0,
+					Reclaimed: 2,
+				}))
+			})
+
+			Context("when destroying an orphan fails", func() {
+				BeforeEach(func() {
+					fakeInitOrphan.DestroyReturns(errors.New("nope"))
+				})
+
+				It("carries on with the rest", func() {
+					Expect(collectErr).ToNot(HaveOccurred())
+					Expect(reclaimed).To(Equal(1))
+
+					Expect(fakeDeadOrphan.DestroyCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when an orphan has disappeared by the time it is destroyed", func() {
+				BeforeEach(func() {
+					fakeInitOrphan.DestroyReturns(os.ErrNotExist)
+				})
+
+				It("does not count it", func() {
+					Expect(collectErr).ToNot(HaveOccurred())
+					Expect(reclaimed).To(Equal(1))
+				})
+			})
+		})
+
+		Context("when listing orphans fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeFilesystem.ListOrphansReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(collectErr).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("GetOrphanStats", func() {
+		BeforeEach(func() {
+			fakeFilesystem.ListOrphansReturns([]volume.FilesystemVolume{
+				new(volumefakes.FakeFilesystemVolume),
+				new(volumefakes.FakeFilesystemVolume),
+				new(volumefakes.FakeFilesystemVolume),
+			}, nil)
+		})
+
+		It("reports the orphans awaiting collection", func() {
+			stats, err := repository.GetOrphanStats(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stats).To(Equal(volume.OrphanStats{
+				Pending:   3,
+				Reclaimed: 0,
+			}))
+		})
+	})
+
+	Describe("Fsck", func() {
+		var (
+			opts volume.FsckOptions
+
+			fakeHealthyVolume   *volumefakes.FakeFilesystemLiveVolume
+			fakeCorruptedVolume *volumefakes.FakeFilesystemLiveVolume
+
+			report  volume.FsckReport
+			fsckErr error
+		)
+
+		BeforeEach(func() {
+			opts = volume.FsckOptions{}
+
+			fakeHealthyVolume = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeHealthyVolume.HandleReturns("healthy-volume")
+
+			fakeCorruptedVolume = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeCorruptedVolume.HandleReturns("corrupted-volume")
+			fakeCorruptedVolume.CheckReturns([]string{"not mounted"})
+
+			fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{
+				fakeHealthyVolume,
+				fakeCorruptedVolume,
+			}, nil)
+
+			fakeFilesystem.LookupVolumeStub = func(handle string) (volume.FilesystemLiveVolume, bool, error) {
+				switch handle {
+				case "healthy-volume":
+					return fakeHealthyVolume, true, nil
+				case "corrupted-volume":
+					return fakeCorruptedVolume, true, nil
+				default:
+					return nil, false, nil
+				}
+			}
+		})
+
+		JustBeforeEach(func() {
+			report, fsckErr = repository.Fsck(context.Background(), opts)
+		})
+
+		It("reports the corrupted volumes without touching them", func() {
+			Expect(fsckErr).ToNot(HaveOccurred())
+			Expect(report).To(Equal(volume.FsckReport{
+				Checked: 2,
+				Corrupted: []volume.CorruptedVolume{
+					{
+						Handle:   "corrupted-volume",
+						Problems: []string{"not mounted"},
+					},
+				},
+			}))
+
+			Expect(fakeCorruptedVolume.RepairCallCount()).To(Equal(0))
+			Expect(fakeCorruptedVolume.QuarantineCallCount()).To(Equal(0))
+		})
+
+		It("locks each volume while checking it", func() {
+			Expect(fakeLocker.LockCallCount()).To(Equal(2))
+			Expect(fakeLocker.LockArgsForCall(0)).To(Equal("healthy-volume"))
+			Expect(fakeLocker.LockArgsForCall(1)).To(Equal("corrupted-volume"))
+			Expect(fakeLocker.UnlockCallCount()).To(Equal(2))
+		})
+
+		Context("when a volume disappears before it is checked", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeStub = func(handle string) (volume.FilesystemLiveVolume, bool, error) {
+					if handle == "healthy-volume" {
+						return fakeHealthyVolume, true, nil
+					}
+
+					return nil, false, nil
+				}
+			})
+
+			It("skips it", func() {
+				Expect(report.Checked).To(Equal(1))
+				Expect(report.Corrupted).To(BeEmpty())
+			})
+		})
+
+		Context("when repairing", func() {
+			BeforeEach(func() {
+				opts.Repair = true
+			})
+
+			Context("when the repair fixes the volume", func() {
+				BeforeEach(func() {
+					fakeCorruptedVolume.RepairStub = func() error {
+						fakeCorruptedVolume.CheckReturns([]string{})
+						return nil
+					}
+				})
+
+				It("reports it as repaired", func() {
+					Expect(report.Corrupted).To(Equal([]volume.CorruptedVolume{
+						{
+							Handle:   "corrupted-volume",
+							Problems: []string{"not mounted"},
+							Repaired: true,
+						},
+					}))
+				})
+
+				It("does not repair healthy volumes", func() {
+					Expect(fakeHealthyVolume.RepairCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the repair fails", func() {
+				BeforeEach(func() {
+					fakeCorruptedVolume.RepairReturns(errors.New("nope"))
+				})
+
+				It("reports the error", func() {
+					Expect(report.Corrupted).To(Equal([]volume.CorruptedVolume{
+						{
+							Handle:   "corrupted-volume",
+							Problems: []string{"not mounted"},
+							Error:    "nope",
+						},
+					}))
+				})
+
+				Context("when also quarantining", func() {
+					BeforeEach(func() {
+						opts.Quarantine = true
+					})
+
+					It("quarantines the volume", func() {
+						Expect(fakeCorruptedVolume.QuarantineCallCount()).To(Equal(1))
+
+						Expect(report.Corrupted).To(Equal([]volume.CorruptedVolume{
+							{
+								Handle:      "corrupted-volume",
+								Problems:    []string{"not mounted"},
+								Quarantined: true,
+							},
+						}))
+					})
+				})
+			})
+		})
+
+		Context("when quarantining", func() {
+			BeforeEach(func() {
+				opts.Quarantine = true
+			})
+
+			It("quarantines only the corrupted volumes", func() {
+				Expect(fakeHealthyVolume.QuarantineCallCount()).To(Equal(0))
+				Expect(fakeCorruptedVolume.QuarantineCallCount()).To(Equal(1))
+
+				Expect(report.Corrupted[0].Quarantined).To(BeTrue())
+			})
+
+			Context("when quarantining fails", func() {
+				BeforeEach(func() {
+					fakeCorruptedVolume.QuarantineReturns(errors.New("nope"))
+				})
+
+				It("reports the error", func() {
+					Expect(report.Corrupted[0].Quarantined).To(BeFalse())
+					Expect(report.Corrupted[0].Error).To(Equal("nope"))
+				})
+			})
+
+			Context("when the volume has children", func() {
+				BeforeEach(func() {
+					fakeHealthyVolume.ParentReturns(fakeCorruptedVolume, true, nil)
+				})
+
+				It("leaves it in place", func() {
+					Expect(fakeCorruptedVolume.QuarantineCallCount()).To(Equal(0))
+
+					Expect(report.Corrupted[0].Quarantined).To(BeFalse())
+					Expect(report.Corrupted[0].Error).To(Equal(volume.ErrVolumeHasChildren.Error()))
+				})
+			})
+		})
+
+		Context("when listing the volumes fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeFilesystem.ListVolumesReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(fsckErr).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("DestroyQuarantinedVolume", func() {
+		var fakeQuarantinedVolume *volumefakes.FakeFilesystemVolume
+
+		BeforeEach(func() {
+			fakeQuarantinedVolume = new(volumefakes.FakeFilesystemVolume)
+			fakeQuarantinedVolume.HandleReturns("quarantined-volume")
+
+			fakeFilesystem.LookupQuarantinedVolumeReturns(fakeQuarantinedVolume, true, nil)
+		})
+
+		It("destroys the volume under its lock", func() {
+			err := repository.DestroyQuarantinedVolume(context.Background(), "quarantined-volume")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeFilesystem.LookupQuarantinedVolumeArgsForCall(0)).To(Equal("quarantined-volume"))
+			Expect(fakeQuarantinedVolume.DestroyCallCount()).To(Equal(1))
+
+			Expect(fakeLocker.LockCallCount()).To(Equal(1))
+			Expect(fakeLocker.LockArgsForCall(0)).To(Equal("quarantined-volume"))
+			Expect(fakeLocker.UnlockCallCount()).To(Equal(1))
+		})
+
+		Context("when the volume is not quarantined", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupQuarantinedVolumeReturns(nil, false, nil)
+			})
+
+			It("returns ErrVolumeDoesNotExist", func() {
+				err := repository.DestroyQuarantinedVolume(context.Background(), "quarantined-volume")
+				Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+			})
+		})
+
+		Context("when destroying the volume fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeQuarantinedVolume.DestroyReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				err := repository.DestroyQuarantinedVolume(context.Background(), "quarantined-volume")
+				Expect(err).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("VolumeParent", func() {
+		var (
+			parent    volume.Volume
+			found     bool
+			parentErr error
+		)
+
+		JustBeforeEach(func() {
+			parent, found, parentErr = repository.VolumeParent(context.Background(), "some-volume")
+		})
+
+		Context("when the volume is found in the filesystem", func() {
+			var fakeVolume *volumefakes.FakeFilesystemLiveVolume
+
+			BeforeEach(func() {
+				fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+				fakeVolume.HandleReturns("some-volume")
+				fakeVolume.DataPathReturns("some-data-path")
+				fakeVolume.LoadPropertiesReturns(volume.Properties{"a": "a", "b": "b"}, nil)
+				fakeVolume.LoadPrivilegedReturns(false, nil)
+
+				fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+			})
+
+			Context("when the volume has a parent", func() {
+				var parentVolume *volumefakes.FakeFilesystemLiveVolume
+
+				BeforeEach(func() {
+					parentVolume = new(volumefakes.FakeFilesystemLiveVolume)
+					parentVolume.HandleReturns("parent-volume")
+					parentVolume.DataPathReturns("parent-data-path")
+					parentVolume.LoadPropertiesReturns(volume.Properties{"parent": "property"}, nil)
+					parentVolume.LoadPrivilegedReturns(true, nil)
+
+					fakeVolume.ParentReturns(parentVolume, true, nil)
+				})
+
+				It("succeeds", func() {
+					Expect(parentErr).ToNot(HaveOccurred())
+				})
+
+				It("found the child volume by looking for the right handle", func() {
+					handle := fakeFilesystem.LookupVolumeArgsForCall(0)
+					Expect(handle).To(Equal("some-volume"))
+				})
+
+				It("returns the parent volume and true", func() {
+					Expect(found).To(BeTrue())
+					Expect(parent).To(Equal(volume.Volume{
+						Handle:     "parent-volume",
+						Path:       "parent-data-path",
+						Properties: volume.Properties{"parent": "property"},
+						Privileged: true,
+					}))
+				})
+
+				Context("when hydrating the parent volume fails", func() {
+					disaster := errors.New("nope")
+
+					BeforeEach(func() {
+						parentVolume.LoadPropertiesReturns(nil, disaster)
+					})
+
+					It("returns the special error", func() {
+						Expect(parentErr).To(Equal(volume.ErrVolumeIsCorrupted))
+					})
+				})
+			})
+
+			Context("when the volume does not have a parent", func() {
+				BeforeEach(func() {
+					fakeVolume.ParentReturns(nil, false, nil)
+				})
+
+				It("succeeds", func() {
+					Expect(parentErr).ToNot(HaveOccurred())
+				})
+
+				It("returns no volume and false", func() {
+					Expect(found).To(BeFalse())
+					Expect(parent).To(BeZero())
+				})
+			})
+
+			Context("when getting the parent volume fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					fakeVolume.ParentReturns(nil, false, disaster)
+				})
+
+				It("returns the error", func() {
+					Expect(parentErr).To(Equal(disaster))
+				})
+			})
+		})
+
+		Context("when the volume is not found on the filesystem", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+			})
+
+			It("returns ErrVolumeDoesNotExist", func() {
+				Expect(parentErr).To(Equal(volume.ErrVolumeDoesNotExist))
+			})
+		})
+
+		Context("when looking up the volume on the filesystem fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(parentErr).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("StreamIn", func() {
+		var (
+			dataDir    string
+			fakeVolume *volumefakes.FakeFilesystemLiveVolume
+
+			stream []byte
+		)
+
+		BeforeEach(func() {
+			var err error
+			dataDir, err = ioutil.TempDir("", "stream-in")
+			Expect(err).ToNot(HaveOccurred())
+
+			fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeVolume.DataPathReturns(dataDir)
+			fakeVolume.LoadPrivilegedReturns(true, nil)
+			fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+
+			buf := new(bytes.Buffer)
+			tarWriter := tar.NewWriter(buf)
+			contents := bytes.Repeat([]byte("some-contents\n"), 1024)
+			err = tarWriter.WriteHeader(&tar.Header{
+				Name:     "some-file",
+				Mode:     0644,
+				Size:     int64(len(contents)),
+				Typeflag: tar.TypeReg,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = tarWriter.Write(contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tarWriter.Close()).To(Succeed())
+
+			stream = buf.Bytes()
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dataDir)
+		})
+
+		It("records the digest of what was streamed in, having forgotten the old one", func() {
+			_, err := repository.StreamIn(context.Background(), "some-handle", ".", volume.IdentityEncoding, bytes.NewReader(stream))
+			Expect(err).ToNot(HaveOccurred())
+
+			expected, err := volume.TreeDigest(dataDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeVolume.StoreDigestCallCount()).To(Equal(2))
+			Expect(fakeVolume.StoreDigestArgsForCall(0)).To(Equal(""))
+			Expect(fakeVolume.StoreDigestArgsForCall(1)).To(Equal(expected))
+		})
+
+		Context("when the stream is malformed", func() {
+			BeforeEach(func() {
+				stream = stream[:600]
+			})
+
+			It("leaves the digest forgotten", func() {
+				_, err := repository.StreamIn(context.Background(), "some-handle", ".", volume.IdentityEncoding, bytes.NewReader(stream))
+				Expect(err).To(HaveOccurred())
+
+				Expect(fakeVolume.StoreDigestCallCount()).To(Equal(1))
+				Expect(fakeVolume.StoreDigestArgsForCall(0)).To(Equal(""))
+			})
+		})
+
+		It("reports a truncated stream as bad", func() {
+			badStream, err := repository.StreamIn(context.Background(), "some-handle", ".", volume.IdentityEncoding, bytes.NewReader(stream[:600]))
+			Expect(err).To(HaveOccurred())
+			Expect(badStream).To(BeTrue())
+		})
+
+		It("reports a stream that fails to decompress as bad", func() {
+			badStream, err := repository.StreamIn(context.Background(), "some-handle", ".", volume.GzipEncoding, bytes.NewReader(stream))
+			Expect(err).To(HaveOccurred())
+			Expect(badStream).To(BeTrue())
+		})
+
+		Context("when reading the stream fails", func() {
+			var disconnected error
+
+			BeforeEach(func() {
+				disconnected = errors.New("connection reset")
+			})
+
+			It("returns the error without reporting the stream as bad", func() {
+				badStream, err := repository.StreamIn(context.Background(), "some-handle", ".", volume.IdentityEncoding, io.MultiReader(
+					bytes.NewReader(stream[:600]),
+					failingReader{disconnected},
+				))
+				Expect(err).To(Equal(disconnected))
+				Expect(badStream).To(BeFalse())
+			})
+
+			It("returns the error without reporting the stream as bad while decompressing it", func() {
+				buf := new(bytes.Buffer)
+				gzipWriter := gzip.NewWriter(buf)
+				_, err := gzipWriter.Write(stream)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(gzipWriter.Close()).To(Succeed())
+
+				badStream, err := repository.StreamIn(context.Background(), "some-handle", ".", volume.GzipEncoding, io.MultiReader(
+					bytes.NewReader(buf.Bytes()[:buf.Len()/2]),
+					failingReader{disconnected},
+				))
+				Expect(err).To(Equal(disconnected))
+				Expect(badStream).To(BeFalse())
+			})
+		})
+
+		It("checks that the volume is writable while holding its lock", func() {
+			fakeVolume.LoadReadOnlyStub = func() (bool, error) {
+				if fakeVolume.LoadReadOnlyCallCount() == 1 {
+					Expect(fakeLocker.LockCallCount()).To(Equal(1))
+					Expect(fakeLocker.UnlockCallCount()).To(BeZero())
+				}
+
+				return false, nil
+			}
+
+			_, err := repository.StreamIn(context.Background(), "some-handle", ".", volume.IdentityEncoding, bytes.NewReader(stream))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeLocker.LockArgsForCall(0)).To(Equal("some-handle"))
+			Expect(fakeVolume.LoadReadOnlyCallCount()).To(BeNumerically(">=", 1))
+			Expect(filepath.Join(dataDir, "some-file")).To(BeAnExistingFile())
+		})
+
+		Context("when the volume is made read-only while streaming in", func() {
+			It("waits for the stream-in to finish before sealing the volume", func() {
+				reader, writer := io.Pipe()
+
+				streamedIn := make(chan error, 1)
+				go func() {
+					defer GinkgoRecover()
+
+					_, err := repository.StreamIn(context.Background(), "some-handle", ".", volume.IdentityEncoding, reader)
+					streamedIn <- err
+				}()
+
+				// returns once the stream-in has started reading
+				_, err := writer.Write(stream[:512])
+				Expect(err).ToNot(HaveOccurred())
+
+				sealed := make(chan error, 1)
+				go func() {
+					defer GinkgoRecover()
+
+					sealed <- repository.SetReadOnly(context.Background(), "some-handle")
+				}()
+
+				Consistently(sealed).ShouldNot(Receive())
+				Expect(fakeVolume.SetReadOnlyCallCount()).To(BeZero())
+
+				_, err = writer.Write(stream[512:])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(writer.Close()).To(Succeed())
+
+				Eventually(streamedIn).Should(Receive(BeNil()))
+				Eventually(sealed).Should(Receive(BeNil()))
+				Expect(fakeVolume.SetReadOnlyCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the volume is renamed while streaming in", func() {
+			BeforeEach(func() {
+				fakeRenamed := new(volumefakes.FakeFilesystemLiveVolume)
+				fakeRenamed.HandleReturns("new-handle")
+				fakeVolume.RenameReturns(fakeRenamed, nil)
+			})
+
+			It("waits for the stream-in to finish before moving the volume", func() {
+				reader, writer := io.Pipe()
+
+				streamedIn := make(chan error, 1)
+				go func() {
+					defer GinkgoRecover()
+
+					_, err := repository.StreamIn(context.Background(), "some-handle", ".", volume.IdentityEncoding, reader)
+					streamedIn <- err
+				}()
+
+				// returns once the stream-in has started reading
+				_, err := writer.Write(stream[:512])
+				Expect(err).ToNot(HaveOccurred())
+
+				renamed := make(chan error, 1)
+				go func() {
+					defer GinkgoRecover()
+
+					_, err := repository.RenameVolume(context.Background(), "some-handle", "new-handle")
+					renamed <- err
+				}()
+
+				Consistently(renamed).ShouldNot(Receive())
+				Expect(fakeVolume.RenameCallCount()).To(BeZero())
+
+				_, err = writer.Write(stream[512:])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(writer.Close()).To(Succeed())
+
+				Eventually(streamedIn).Should(Receive(BeNil()))
+				Eventually(renamed).Should(Receive(BeNil()))
+				Expect(fakeVolume.RenameCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when a snapshot is taken while streaming in", func() {
+			BeforeEach(func() {
+				fakeSnapshot := new(volumefakes.FakeFilesystemSnapshot)
+				fakeSnapshot.HandleReturns("some-snapshot")
+				fakeVolume.NewSnapshotReturns(fakeSnapshot, nil)
+			})
+
+			It("waits for the stream-in to finish before taking the snapshot", func() {
+				reader, writer := io.Pipe()
+
+				streamedIn := make(chan error, 1)
+				go func() {
+					defer GinkgoRecover()
+
+					_, err := repository.StreamIn(context.Background(), "some-handle", ".", volume.IdentityEncoding, reader)
+					streamedIn <- err
+				}()
+
+				// returns once the stream-in has started reading
+				_, err := writer.Write(stream[:512])
+				Expect(err).ToNot(HaveOccurred())
+
+				snapshotted := make(chan error, 1)
+				go func() {
+					defer GinkgoRecover()
+
+					_, err := repository.CreateSnapshot(context.Background(), "some-handle", "some-snapshot")
+					snapshotted <- err
+				}()
+
+				Consistently(snapshotted).ShouldNot(Receive())
+				Expect(fakeVolume.NewSnapshotCallCount()).To(BeZero())
+
+				_, err = writer.Write(stream[512:])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(writer.Close()).To(Succeed())
+
+				Eventually(streamedIn).Should(Receive(BeNil()))
+				Eventually(snapshotted).Should(Receive(BeNil()))
+				Expect(fakeVolume.NewSnapshotCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("StreamInResumable", func() {
+		var (
+			tmpdir     string
+			dataDir    string
+			uploadsDir string
+			fakeVolume *volumefakes.FakeFilesystemLiveVolume
+
+			stream []byte
+		)
+
+		BeforeEach(func() {
+			var err error
+			tmpdir, err = ioutil.TempDir("", "stream-in-resumable")
+			Expect(err).ToNot(HaveOccurred())
+
+			dataDir = filepath.Join(tmpdir, "volume")
+			uploadsDir = filepath.Join(tmpdir, "uploads")
+
+			err = os.Mkdir(dataDir, 0755)
+			Expect(err).ToNot(HaveOccurred())
+
+			fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+			fakeVolume.DataPathReturns(dataDir)
+			fakeVolume.UploadsPathReturns(uploadsDir)
+			fakeVolume.LoadPrivilegedReturns(true, nil)
+			fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+
+			buf := new(bytes.Buffer)
+			tarWriter := tar.NewWriter(buf)
+			contents := bytes.Repeat([]byte("some-contents\n"), 1024)
+			err = tarWriter.WriteHeader(&tar.Header{
+				Name:     "some-file",
+				Mode:     0644,
+				Size:     int64(len(contents)),
+				Typeflag: tar.TypeReg,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = tarWriter.Write(contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tarWriter.Close()).To(Succeed())
+
+			stream = buf.Bytes()
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpdir)
+		})
+
+		uploadFiles := func() []string {
+			infos, err := ioutil.ReadDir(uploadsDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			names := []string{}
+			for _, info := range infos {
+				names = append(names, info.Name())
+			}
+
+			return names
+		}
+
+		It("extracts a complete stream and forgets the upload", func() {
+			badStream, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 0, bytes.NewReader(stream))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(badStream).To(BeFalse())
+
+			contents, err := ioutil.ReadFile(filepath.Join(dataDir, "some-path", "some-file"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(HaveLen(14 * 1024))
+
+			Expect(uploadFiles()).To(BeEmpty())
+		})
+
+		It("refuses to stream into a read-only volume", func() {
+			fakeVolume.LoadReadOnlyReturns(true, nil)
+
+			_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 0, bytes.NewReader(stream))
+			Expect(err).To(Equal(volume.ErrVolumeIsReadOnly))
+
+			Expect(filepath.Join(dataDir, "some-path")).ToNot(BeADirectory())
+			Expect(uploadsDir).ToNot(BeADirectory())
+		})
+
+		It("locks the upload session", func() {
+			_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 0, bytes.NewReader(stream))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeLocker.LockCallCount()).ToNot(BeZero())
+			Expect(fakeLocker.LockArgsForCall(0)).To(Equal("some-handle/uploads/some-session"))
+			Expect(fakeLocker.UnlockCallCount()).To(Equal(fakeLocker.LockCallCount()))
+		})
+
+		Context("when the stream is interrupted", func() {
+			var (
+				sent      int64
+				streamErr error
+			)
+
+			BeforeEach(func() {
+				sent = int64(len(stream) / 2)
+
+				_, streamErr = repository.StreamInResumable(
+					context.Background(),
+					"some-handle",
+					"some-path",
+					volume.IdentityEncoding,
+					"some-session",
+					0,
+					io.MultiReader(bytes.NewReader(stream[:sent]), failingReader{errors.New("connection reset")}),
+				)
+			})
+
+			It("returns ErrUploadIncomplete", func() {
+				Expect(streamErr).To(Equal(volume.ErrUploadIncomplete))
+			})
+
+			It("remembers how much was received", func() {
+				offset, err := repository.UploadOffset(context.Background(), "some-handle", "some-session")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(offset).To(Equal(sent))
+			})
+
+			It("can be resumed from where it left off", func() {
+				badStream, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", sent, bytes.NewReader(stream[sent:]))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(badStream).To(BeFalse())
+
+				contents, err := ioutil.ReadFile(filepath.Join(dataDir, "some-path", "some-file"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(contents)).To(Equal(strings.Repeat("some-contents\n", 1024)))
+
+				Expect(uploadFiles()).To(BeEmpty())
+
+				offset, err := repository.UploadOffset(context.Background(), "some-handle", "some-session")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(offset).To(BeZero())
+			})
+
+			It("can be restarted from the beginning", func() {
+				_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 0, bytes.NewReader(stream))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(uploadFiles()).To(BeEmpty())
+			})
+
+			It("rejects resuming from any other offset", func() {
+				_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", sent-1, bytes.NewReader(stream[sent-1:]))
+				Expect(err).To(Equal(volume.UploadOffsetError{Offset: sent}))
+
+				offset, err := repository.UploadOffset(context.Background(), "some-handle", "some-session")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(offset).To(Equal(sent))
+			})
+
+			It("rejects resuming to a different path", func() {
+				_, err := repository.StreamInResumable(context.Background(), "some-handle", "other-path", volume.IdentityEncoding, "some-session", sent, bytes.NewReader(stream[sent:]))
+				Expect(err).To(Equal(volume.ErrUploadConflict))
+			})
+
+			It("rejects resuming with a different encoding", func() {
+				_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.GzipEncoding, "some-session", sent, bytes.NewReader(stream[sent:]))
+				Expect(err).To(Equal(volume.ErrUploadConflict))
+			})
+		})
+
+		Context("when the stream is interrupted after some of its entries are in place", func() {
+			var sent int64
+
+			BeforeEach(func() {
+				buf := new(bytes.Buffer)
+				tarWriter := tar.NewWriter(buf)
+				for _, name := range []string{"first-file", "second-file"} {
+					contents := bytes.Repeat([]byte(name+"\n"), 1024)
+					err := tarWriter.WriteHeader(&tar.Header{
+						Name:     name,
+						Mode:     0644,
+						Size:     int64(len(contents)),
+						Typeflag: tar.TypeReg,
+					})
+					Expect(err).ToNot(HaveOccurred())
+					_, err = tarWriter.Write(contents)
+					Expect(err).ToNot(HaveOccurred())
+
+					if name == "first-file" {
+						Expect(tarWriter.Flush()).To(Succeed())
+						// send the next header too, so that the first entry
+						// is known to be in place
+						sent = int64(buf.Len()) + 512
+					}
+				}
+				Expect(tarWriter.Close()).To(Succeed())
+
+				stream = buf.Bytes()
+
+				_, err := repository.StreamInResumable(
+					context.Background(),
+					"some-handle",
+					"some-path",
+					volume.IdentityEncoding,
+					"some-session",
+					0,
+					io.MultiReader(bytes.NewReader(stream[:sent]), failingReader{errors.New("connection reset")}),
+				)
+				Expect(err).To(Equal(volume.ErrUploadIncomplete))
+			})
+
+			It("does not extract them again when resumed", func() {
+				firstFile := filepath.Join(dataDir, "some-path", "first-file")
+				Expect(ioutil.ReadFile(firstFile)).To(Equal(bytes.Repeat([]byte("first-file\n"), 1024)))
+				Expect(ioutil.WriteFile(firstFile, []byte("left alone"), 0644)).To(Succeed())
+
+				_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", sent, bytes.NewReader(stream[sent:]))
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ioutil.ReadFile(firstFile)).To(Equal([]byte("left alone")))
+				Expect(ioutil.ReadFile(filepath.Join(dataDir, "some-path", "second-file"))).To(Equal(bytes.Repeat([]byte("second-file\n"), 1024)))
+
+				Expect(uploadFiles()).To(BeEmpty())
+			})
+		})
+
+		Context("when the stream is bad", func() {
+			It("forgets the upload", func() {
+				badStream, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 0, strings.NewReader("not a tar stream, but long enough to fill a header block"+strings.Repeat(" ", 512)))
+				Expect(err).To(HaveOccurred())
+				Expect(badStream).To(BeTrue())
+
+				Expect(uploadFiles()).To(BeEmpty())
+			})
+		})
+
+		Context("when the stream is larger than the volume's quota", func() {
+			BeforeEach(func() {
+				fakeVolume.LoadQuotaReturns(uint64(len(stream)/2), nil)
+			})
+
+			It("returns ErrQuotaExceeded and forgets the upload", func() {
+				badStream, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 0, bytes.NewReader(stream))
+				Expect(err).To(Equal(volume.ErrQuotaExceeded))
+				Expect(badStream).To(BeFalse())
+
+				Expect(uploadFiles()).To(BeEmpty())
+			})
+		})
+
+		Context("when the stream is larger than the maximum upload size", func() {
+			BeforeEach(func() {
+				repository = volume.NewRepository(
+					fakeFilesystem,
+					fakeLocker,
+					fakePrivilegedNamespacer,
+					fakeUnprivilegedNamespacer,
+					nil,
+					int64(len(stream)/2),
+				)
+			})
+
+			It("returns ErrUploadTooLarge and forgets the upload", func() {
+				badStream, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 0, bytes.NewReader(stream))
+				Expect(err).To(Equal(volume.ErrUploadTooLarge))
+				Expect(badStream).To(BeFalse())
+
+				Expect(uploadFiles()).To(BeEmpty())
+			})
+
+			It("counts what was received before the stream was resumed", func() {
+				sent := int64(len(stream) / 4)
+
+				_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 0, io.MultiReader(bytes.NewReader(stream[:sent]), failingReader{errors.New("connection reset")}))
+				Expect(err).To(Equal(volume.ErrUploadIncomplete))
+
+				_, err = repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", sent, bytes.NewReader(stream[sent:]))
+				Expect(err).To(Equal(volume.ErrUploadTooLarge))
+			})
+		})
+
+		Describe("ReapUploads", func() {
+			BeforeEach(func() {
+				fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeVolume}, nil)
+
+				_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 0, io.MultiReader(bytes.NewReader(stream[:100]), failingReader{errors.New("connection reset")}))
+				Expect(err).To(Equal(volume.ErrUploadIncomplete))
+			})
+
+			It("keeps sessions that have received something recently", func() {
+				reaped, err := repository.ReapUploads(context.Background(), time.Hour)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reaped).To(BeZero())
+
+				Expect(uploadFiles()).ToNot(BeEmpty())
+			})
+
+			It("discards sessions that have been idle for longer than the TTL", func() {
+				idle := time.Now().Add(-2 * time.Hour)
+				for _, name := range uploadFiles() {
+					Expect(os.Chtimes(filepath.Join(uploadsDir, name), idle, idle)).To(Succeed())
+				}
+
+				reaped, err := repository.ReapUploads(context.Background(), time.Hour)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reaped).To(Equal(1))
+
+				Expect(uploadFiles()).To(BeEmpty())
+
+				offset, err := repository.UploadOffset(context.Background(), "some-handle", "some-session")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(offset).To(BeZero())
+			})
+		})
+
+		Context("when resuming a session that was never started", func() {
+			It("asks for the stream from the beginning", func() {
+				_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 42, bytes.NewReader(stream))
+				Expect(err).To(Equal(volume.UploadOffsetError{Offset: 0}))
+			})
+		})
+
+		Context("when the session is invalid", func() {
+			It("returns ErrInvalidUploadSession", func() {
+				_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "../some-session", 0, bytes.NewReader(stream))
+				Expect(err).To(Equal(volume.ErrInvalidUploadSession))
+
+				_, err = repository.UploadOffset(context.Background(), "some-handle", "../some-session")
+				Expect(err).To(Equal(volume.ErrInvalidUploadSession))
+			})
+		})
+
+		Context("when the volume does not exist", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+			})
+
+			It("returns ErrVolumeDoesNotExist", func() {
+				_, err := repository.StreamInResumable(context.Background(), "some-handle", "some-path", volume.IdentityEncoding, "some-session", 0, bytes.NewReader(stream))
+				Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+
+				_, err = repository.UploadOffset(context.Background(), "some-handle", "some-session")
+				Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+			})
+		})
+	})
 
 	Describe("StreamP2pOut", func() {
 		var (
@@ -1101,12 +3056,24 @@ var _ = Describe("Repository", func() {
 			serverCalled       bool
 			serverResponseCode int
 			serverReadBytes    []byte
+			serverResponseBody string
+			serverContentLen   int64
 			tempFile           *os.File
+			streamPath         string
+			ctx                context.Context
+			peerTLS            bool
+			trustPeer          bool
 		)
 		BeforeEach(func() {
 			var err error
 			tempFile, err = ioutil.TempFile("", "StreamP2pOutTest")
 			Expect(err).ToNot(HaveOccurred())
+
+			streamPath = filepath.Base(tempFile.Name())
+			serverResponseBody = ""
+			ctx = context.Background()
+			peerTLS = false
+			trustPeer = false
 		})
 		AfterEach(func() {
 			if server != nil {
@@ -1120,15 +3087,36 @@ var _ = Describe("Repository", func() {
 		JustBeforeEach(func() {
 			serverCalled = false
 			serverReadBytes = nil
-			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				w.WriteHeader(serverResponseCode)
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				serverCalled = true
+				serverContentLen = r.ContentLength
 
 				var err error
 				serverReadBytes, err = ioutil.ReadAll(r.Body)
 				Expect(err).ToNot(HaveOccurred())
-			}))
-			streamErr = repository.StreamP2pOut(context.Background(), "some-handle", filepath.Base(tempFile.Name()), volume.GzipEncoding, server.URL)
+
+				w.WriteHeader(serverResponseCode)
+				w.Write([]byte(serverResponseBody))
+			})
+
+			if peerTLS {
+				server = httptest.NewTLSServer(handler)
+			} else {
+				server = httptest.NewServer(handler)
+			}
+
+			if trustPeer {
+				repository = volume.NewRepository(
+					fakeFilesystem,
+					fakeLocker,
+					fakePrivilegedNamespacer,
+					fakeUnprivilegedNamespacer,
+					server.Client(),
+					0,
+				)
+			}
+
+			streamErr = repository.StreamP2pOut(ctx, "some-handle", streamPath, volume.GzipEncoding, server.URL)
 		})
 
 		Context("when lookup volume fails", func() {
@@ -1191,10 +3179,17 @@ var _ = Describe("Repository", func() {
 				Context("remote returns error", func() {
 					BeforeEach(func() {
 						serverResponseCode = http.StatusInternalServerError
+						serverResponseBody = "disk full\n"
 					})
 					It("should fail", func() {
 						Expect(streamErr).To(HaveOccurred())
-						Expect(streamErr).To(Equal(fmt.Errorf("p2p streaming error %d", http.StatusInternalServerError)))
+
+						var p2pErr volume.P2pStreamError
+						Expect(errors.As(streamErr, &p2pErr)).To(BeTrue())
+						Expect(p2pErr.StatusCode).To(Equal(http.StatusInternalServerError))
+						Expect(p2pErr.Body).To(Equal("disk full"))
+						Expect(p2pErr.BytesSent).To(BeEquivalentTo(len(serverReadBytes)))
+						Expect(streamErr.Error()).To(Equal(fmt.Sprintf("p2p streaming error 500 after %d bytes: disk full", len(serverReadBytes))))
 					})
 					It("should http request", func() {
 						Expect(serverCalled).To(BeTrue())
@@ -1211,15 +3206,88 @@ var _ = Describe("Repository", func() {
 					It("should http request", func() {
 						Expect(serverCalled).To(BeTrue())
 					})
+					It("streams the archive rather than sending it all at once", func() {
+						Expect(serverContentLen).To(BeEquivalentTo(-1))
+					})
 					It("remote should receive bytes", func() {
-						b := new(bytes.Buffer)
-						tgzfs.Compress(b, filepath.Dir(tempFile.Name()), filepath.Base(tempFile.Name()))
-						Expect(len(serverReadBytes)).To(Equal(len(b.Bytes())))
-						n := len(serverReadBytes)
-						Expect(serverReadBytes[:n]).To(Equal(b.Bytes()[:n]))
+						gzReader, err := gzip.NewReader(bytes.NewReader(serverReadBytes))
+						Expect(err).ToNot(HaveOccurred())
+
+						tarReader := tar.NewReader(gzReader)
+
+						hdr, err := tarReader.Next()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(hdr.Name).To(Equal(filepath.Base(tempFile.Name())))
+
+						content, err := ioutil.ReadAll(tarReader)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(string(content)).To(Equal("hello-world"))
+
+						_, err = tarReader.Next()
+						Expect(err).To(Equal(io.EOF))
+					})
+				})
+
+				Context("when the peer serves TLS", func() {
+					BeforeEach(func() {
+						serverResponseCode = http.StatusNoContent
+						peerTLS = true
+					})
+
+					Context("and the p2p client trusts it", func() {
+						BeforeEach(func() {
+							trustPeer = true
+						})
+						It("streams to it", func() {
+							Expect(streamErr).ToNot(HaveOccurred())
+							Expect(serverCalled).To(BeTrue())
+							Expect(serverReadBytes).ToNot(BeEmpty())
+						})
+					})
+
+					Context("and the p2p client does not trust it", func() {
+						It("should fail", func() {
+							Expect(streamErr).To(HaveOccurred())
+						})
+						It("should not reach the remote", func() {
+							Expect(serverCalled).To(BeFalse())
+						})
+					})
+				})
+
+				Context("when the source path does not exist", func() {
+					BeforeEach(func() {
+						streamPath = "bogus"
+					})
+					It("should fail", func() {
+						Expect(os.IsNotExist(streamErr)).To(BeTrue())
+					})
+					It("should not http request", func() {
+						Expect(serverCalled).To(BeFalse())
+					})
+				})
+
+				Context("when the context is canceled", func() {
+					BeforeEach(func() {
+						serverResponseCode = http.StatusNoContent
+
+						var cancel context.CancelFunc
+						ctx, cancel = context.WithCancel(context.Background())
+						cancel()
+					})
+					It("should fail", func() {
+						Expect(errors.Is(streamErr, context.Canceled)).To(BeTrue())
 					})
 				})
 			})
 		})
 	})
 })
+
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,317 @@
+package volume
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+)
+
+var ErrSnapshotDoesNotExist = errors.New("snapshot does not exist")
+var ErrSnapshotAlreadyExists = errors.New("snapshot already exists")
+var ErrInvalidSnapshotHandle = errors.New("invalid snapshot handle")
+var ErrVolumeHasChildren = errors.New("volume has children")
+var ErrVolumeInUse = errors.New("volume is in use")
+var ErrSnapshotCorrupted = errors.New("snapshot has changed since it was taken")
+
+// Snapshot is a read-only, point-in-time copy of a volume's contents which the
+// volume can be restored to. Snapshots are destroyed along with their volume.
+type Snapshot struct {
+	Handle    string    `json:"handle"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateSnapshot takes a snapshot of the volume's contents once anything
+// streaming in to it has finished. Drivers that cannot freeze the contents
+// while they are copied need nothing else to write to the volume until the
+// snapshot is taken, and return ErrVolumeInUse if anything did.
+func (repo *repository) CreateSnapshot(ctx context.Context, handle string, snapshotHandle string) (Snapshot, error) {
+	logger := lagerctx.FromContext(ctx).Session("create-snapshot", lager.Data{
+		"volume":   handle,
+		"snapshot": snapshotHandle,
+	})
+
+	if !validHandle(snapshotHandle) {
+		logger.Info("invalid-snapshot-handle")
+		return Snapshot{}, ErrInvalidSnapshotHandle
+	}
+
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return Snapshot{}, err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return Snapshot{}, ErrVolumeDoesNotExist
+	}
+
+	// new stream-ins wait for the lock held here, so the snapshot cannot
+	// capture a partial extraction
+	repo.writers.wait(handle)
+
+	fsSnapshot, err := volume.NewSnapshot(snapshotHandle)
+	if err != nil {
+		if os.IsExist(err) {
+			logger.Info("snapshot-already-exists")
+			return Snapshot{}, ErrSnapshotAlreadyExists
+		}
+
+		if err == ErrVolumeInUse {
+			logger.Info("volume-in-use")
+			return Snapshot{}, err
+		}
+
+		logger.Error("failed-to-create-snapshot", err)
+		return Snapshot{}, err
+	}
+
+	snapshot, err := snapshotFrom(fsSnapshot)
+	if err != nil {
+		logger.Error("failed-to-hydrate-snapshot", err)
+		return Snapshot{}, err
+	}
+
+	logger.Info("created")
+
+	return snapshot, nil
+}
+
+// RestoreSnapshot replaces the volume's contents with the snapshot's. Volumes
+// with copy-on-write children cannot be restored, as the children may be
+// layered on top of the contents being replaced.
+//
+// The volume must not be in use, e.g. bind mounted into a container, while it
+// is restored: drivers swap in new data rather than changing the old in
+// place, and the old data is removed once it has been swapped out. Drivers
+// return ErrVolumeInUse where they can tell that it is.
+func (repo *repository) RestoreSnapshot(ctx context.Context, handle string, snapshotHandle string) error {
+	logger := lagerctx.FromContext(ctx).Session("restore-snapshot", lager.Data{
+		"volume":   handle,
+		"snapshot": snapshotHandle,
+	})
+
+	if !validHandle(snapshotHandle) {
+		logger.Info("snapshot-not-found")
+		return ErrSnapshotDoesNotExist
+	}
+
+	// held throughout, so that the volume cannot be made read-only or
+	// renamed once it has been checked
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return ErrVolumeDoesNotExist
+	}
+
+	err = checkWritable(volume)
+	if err != nil {
+		logger.Info("volume-is-read-only")
+		return err
+	}
+
+	// children are created under the lock held here, so none can appear
+	// between checking and restoring
+	hasChildren, err := repo.hasChildren(handle)
+	if err != nil {
+		logger.Error("failed-to-find-children", err)
+		return err
+	}
+
+	if hasChildren {
+		logger.Info("volume-has-children")
+		return ErrVolumeHasChildren
+	}
+
+	// new stream-ins wait for the lock held here
+	repo.writers.wait(handle)
+
+	snapshot, found, err := volume.LookupSnapshot(snapshotHandle)
+	if err != nil {
+		logger.Error("failed-to-lookup-snapshot", err)
+		return err
+	}
+
+	if !found {
+		logger.Info("snapshot-not-found")
+		return ErrSnapshotDoesNotExist
+	}
+
+	// the restored contents are not the ones the digest was recorded for
+	err = volume.StoreDigest("")
+	if err != nil {
+		logger.Error("failed-to-forget-digest", err)
+		return err
+	}
+
+	err = volume.Restore(snapshot)
+	if err == ErrVolumeInUse {
+		logger.Info("volume-in-use")
+		return err
+	}
+
+	if err == ErrSnapshotCorrupted {
+		logger.Info("snapshot-corrupted")
+		return err
+	}
+
+	if err != nil {
+		logger.Error("failed-to-restore-snapshot", err)
+		return err
+	}
+
+	logger.Info("restored")
+
+	repo.events.Publish(Event{
+		Type:     EventRestored,
+		Handle:   handle,
+		Snapshot: snapshotHandle,
+	})
+
+	return nil
+}
+
+func (repo *repository) DestroySnapshot(ctx context.Context, handle string, snapshotHandle string) error {
+	logger := lagerctx.FromContext(ctx).Session("destroy-snapshot", lager.Data{
+		"volume":   handle,
+		"snapshot": snapshotHandle,
+	})
+
+	if !validHandle(snapshotHandle) {
+		logger.Info("snapshot-not-found")
+		return ErrSnapshotDoesNotExist
+	}
+
+	repo.locker.Lock(handle)
+	defer repo.locker.Unlock(handle)
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return ErrVolumeDoesNotExist
+	}
+
+	snapshot, found, err := volume.LookupSnapshot(snapshotHandle)
+	if err != nil {
+		logger.Error("failed-to-lookup-snapshot", err)
+		return err
+	}
+
+	if !found {
+		logger.Info("snapshot-not-found")
+		return ErrSnapshotDoesNotExist
+	}
+
+	err = snapshot.Destroy()
+	if err != nil {
+		logger.Error("failed-to-destroy-snapshot", err)
+		return err
+	}
+
+	logger.Info("destroyed")
+
+	return nil
+}
+
+func (repo *repository) hasChildren(handle string) (bool, error) {
+	children, err := repo.childrenOf(handle)
+	if err != nil {
+		return false, err
+	}
+
+	return len(children) > 0, nil
+}
+
+// childrenOf lists the volumes created as copy-on-write children of the
+// volume.
+func (repo *repository) childrenOf(handle string) ([]FilesystemLiveVolume, error) {
+	volumes, err := repo.filesystem.ListVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []FilesystemLiveVolume{}
+	for _, candidate := range volumes {
+		parent, found, err := candidate.Parent()
+		if err != nil {
+			// the candidate may have been destroyed since it was listed
+			continue
+		}
+
+		if found && parent.Handle() == handle {
+			children = append(children, candidate)
+		}
+	}
+
+	return children, nil
+}
+
+// snapshotsOf describes the volume's snapshots, oldest first, or returns nil
+// if it has none.
+func snapshotsOf(volume FilesystemLiveVolume) ([]Snapshot, error) {
+	fsSnapshots, err := volume.ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, fsSnapshot := range fsSnapshots {
+		snapshot, err := snapshotFrom(fsSnapshot)
+		if err == ErrVolumeDoesNotExist {
+			// being taken or destroyed
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+	})
+
+	return snapshots, nil
+}
+
+func snapshotFrom(snapshot FilesystemSnapshot) (Snapshot, error) {
+	createdAt, err := snapshot.LoadCreatedAt()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return Snapshot{
+		Handle:    snapshot.Handle(),
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// validHandle checks that the handle names a single directory, as volumes
+// and snapshots are kept in directories named after their handles.
+func validHandle(handle string) bool {
+	return handle != "" && handle != "." && handle != ".." && filepath.Base(handle) == handle
+}
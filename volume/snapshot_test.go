@@ -0,0 +1,302 @@
+package volume_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/uidgid/uidgidfakes"
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+)
+
+var _ = Describe("Snapshots", func() {
+	var (
+		fakeFilesystem *volumefakes.FakeFilesystem
+		fakeLocker     *volumefakes.FakeLockManager
+		fakeVolume     *volumefakes.FakeFilesystemLiveVolume
+		fakeSnapshot   *volumefakes.FakeFilesystemSnapshot
+
+		createdAt time.Time
+
+		repository volume.Repository
+	)
+
+	BeforeEach(func() {
+		createdAt = time.Now().Round(time.Second)
+
+		fakeSnapshot = new(volumefakes.FakeFilesystemSnapshot)
+		fakeSnapshot.HandleReturns("some-snapshot")
+		fakeSnapshot.LoadCreatedAtReturns(createdAt, nil)
+
+		fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+		fakeVolume.HandleReturns("some-handle")
+		fakeVolume.NewSnapshotReturns(fakeSnapshot, nil)
+		fakeVolume.LookupSnapshotReturns(fakeSnapshot, true, nil)
+		fakeVolume.LoadPropertiesReturns(volume.Properties{}, nil)
+
+		fakeFilesystem = new(volumefakes.FakeFilesystem)
+		fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+		fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeVolume}, nil)
+
+		fakeLocker = new(volumefakes.FakeLockManager)
+
+		repository = volume.NewRepository(
+			fakeFilesystem,
+			fakeLocker,
+			new(uidgidfakes.FakeNamespacer),
+			new(uidgidfakes.FakeNamespacer),
+			nil,
+			0,
+		)
+	})
+
+	Describe("CreateSnapshot", func() {
+		It("takes a snapshot of the volume", func() {
+			snapshot, err := repository.CreateSnapshot(context.Background(), "some-handle", "some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(snapshot).To(Equal(volume.Snapshot{
+				Handle:    "some-snapshot",
+				CreatedAt: createdAt,
+			}))
+
+			Expect(fakeVolume.NewSnapshotArgsForCall(0)).To(Equal("some-snapshot"))
+		})
+
+		It("holds the volume's lock while taking the snapshot", func() {
+			_, err := repository.CreateSnapshot(context.Background(), "some-handle", "some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeLocker.LockCallCount()).To(Equal(1))
+			Expect(fakeLocker.LockArgsForCall(0)).To(Equal("some-handle"))
+			Expect(fakeLocker.UnlockCallCount()).To(Equal(1))
+		})
+
+		Context("when the snapshot already exists", func() {
+			BeforeEach(func() {
+				fakeVolume.NewSnapshotReturns(nil, &os.PathError{Op: "mkdir", Path: "some-path", Err: os.ErrExist})
+			})
+
+			It("returns ErrSnapshotAlreadyExists", func() {
+				_, err := repository.CreateSnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(volume.ErrSnapshotAlreadyExists))
+			})
+		})
+
+		Context("when the snapshot handle is not a plain name", func() {
+			It("returns ErrInvalidSnapshotHandle", func() {
+				for _, handle := range []string{"", ".", "..", "../escape", "some/nested"} {
+					_, err := repository.CreateSnapshot(context.Background(), "some-handle", handle)
+					Expect(err).To(Equal(volume.ErrInvalidSnapshotHandle))
+				}
+
+				Expect(fakeVolume.NewSnapshotCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when taking the snapshot fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeVolume.NewSnapshotReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := repository.CreateSnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(disaster))
+			})
+		})
+
+		Context("when the volume does not exist", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+			})
+
+			It("returns ErrVolumeDoesNotExist", func() {
+				_, err := repository.CreateSnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+			})
+		})
+	})
+
+	Describe("RestoreSnapshot", func() {
+		It("restores the volume to the snapshot", func() {
+			err := repository.RestoreSnapshot(context.Background(), "some-handle", "some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeVolume.LookupSnapshotArgsForCall(0)).To(Equal("some-snapshot"))
+			Expect(fakeVolume.RestoreCallCount()).To(Equal(1))
+			Expect(fakeVolume.RestoreArgsForCall(0)).To(Equal(fakeSnapshot))
+		})
+
+		It("publishes a restored event", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := repository.Subscribe(ctx)
+
+			err := repository.RestoreSnapshot(context.Background(), "some-handle", "some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			var event volume.Event
+			Eventually(events).Should(Receive(&event))
+			Expect(event.Type).To(Equal(volume.EventRestored))
+			Expect(event.Handle).To(Equal("some-handle"))
+			Expect(event.Snapshot).To(Equal("some-snapshot"))
+		})
+
+		It("checks the volume while holding its lock", func() {
+			fakeVolume.LoadReadOnlyStub = func() (bool, error) {
+				Expect(fakeLocker.LockCallCount()).To(Equal(1))
+				Expect(fakeLocker.UnlockCallCount()).To(BeZero())
+				return false, nil
+			}
+
+			err := repository.RestoreSnapshot(context.Background(), "some-handle", "some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeLocker.LockArgsForCall(0)).To(Equal("some-handle"))
+			Expect(fakeLocker.UnlockCallCount()).To(Equal(1))
+		})
+
+		Context("when the volume is in use", func() {
+			BeforeEach(func() {
+				fakeVolume.RestoreReturns(volume.ErrVolumeInUse)
+			})
+
+			It("returns ErrVolumeInUse", func() {
+				err := repository.RestoreSnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(volume.ErrVolumeInUse))
+			})
+		})
+
+		Context("when the volume has children", func() {
+			BeforeEach(func() {
+				fakeChild := new(volumefakes.FakeFilesystemLiveVolume)
+				fakeChild.ParentReturns(fakeVolume, true, nil)
+
+				fakeFilesystem.ListVolumesReturns([]volume.FilesystemLiveVolume{fakeVolume, fakeChild}, nil)
+			})
+
+			It("returns ErrVolumeHasChildren without restoring it", func() {
+				err := repository.RestoreSnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(volume.ErrVolumeHasChildren))
+
+				Expect(fakeVolume.RestoreCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the volume is read-only", func() {
+			BeforeEach(func() {
+				fakeVolume.LoadReadOnlyReturns(true, nil)
+			})
+
+			It("returns ErrVolumeIsReadOnly without restoring it", func() {
+				err := repository.RestoreSnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(volume.ErrVolumeIsReadOnly))
+
+				Expect(fakeVolume.RestoreCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the snapshot does not exist", func() {
+			BeforeEach(func() {
+				fakeVolume.LookupSnapshotReturns(nil, false, nil)
+			})
+
+			It("returns ErrSnapshotDoesNotExist", func() {
+				err := repository.RestoreSnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(volume.ErrSnapshotDoesNotExist))
+			})
+		})
+
+		Context("when the snapshot handle is not a plain name", func() {
+			It("returns ErrSnapshotDoesNotExist", func() {
+				err := repository.RestoreSnapshot(context.Background(), "some-handle", "../some-snapshot")
+				Expect(err).To(Equal(volume.ErrSnapshotDoesNotExist))
+
+				Expect(fakeVolume.LookupSnapshotCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when restoring fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeVolume.RestoreReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				err := repository.RestoreSnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(disaster))
+			})
+		})
+
+		Context("when the volume does not exist", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+			})
+
+			It("returns ErrVolumeDoesNotExist", func() {
+				err := repository.RestoreSnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+			})
+		})
+	})
+
+	Describe("DestroySnapshot", func() {
+		It("destroys the snapshot", func() {
+			err := repository.DestroySnapshot(context.Background(), "some-handle", "some-snapshot")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeVolume.LookupSnapshotArgsForCall(0)).To(Equal("some-snapshot"))
+			Expect(fakeSnapshot.DestroyCallCount()).To(Equal(1))
+		})
+
+		Context("when the snapshot does not exist", func() {
+			BeforeEach(func() {
+				fakeVolume.LookupSnapshotReturns(nil, false, nil)
+			})
+
+			It("returns ErrSnapshotDoesNotExist", func() {
+				err := repository.DestroySnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(volume.ErrSnapshotDoesNotExist))
+			})
+		})
+
+		Context("when the volume does not exist", func() {
+			BeforeEach(func() {
+				fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+			})
+
+			It("returns ErrVolumeDoesNotExist", func() {
+				err := repository.DestroySnapshot(context.Background(), "some-handle", "some-snapshot")
+				Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+			})
+		})
+	})
+
+	Describe("looking up a volume", func() {
+		It("lists its snapshots, oldest first", func() {
+			olderSnapshot := new(volumefakes.FakeFilesystemSnapshot)
+			olderSnapshot.HandleReturns("older-snapshot")
+			olderSnapshot.LoadCreatedAtReturns(createdAt.Add(-time.Hour), nil)
+
+			fakeVolume.ListSnapshotsReturns([]volume.FilesystemSnapshot{fakeSnapshot, olderSnapshot}, nil)
+
+			vol, found, err := repository.GetVolume(context.Background(), "some-handle")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			Expect(vol.Snapshots).To(Equal([]volume.Snapshot{
+				{Handle: "older-snapshot", CreatedAt: createdAt.Add(-time.Hour)},
+				{Handle: "some-snapshot", CreatedAt: createdAt},
+			}))
+		})
+	})
+})
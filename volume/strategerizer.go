@@ -16,6 +16,9 @@ const (
 	StrategyEmpty       = "empty"
 	StrategyCopyOnWrite = "cow"
 	StrategyImport      = "import"
+	StrategyDedupe      = "dedupe"
+	StrategyLayer       = "layer"
+	StrategyOCI         = "oci"
 )
 
 var ErrNoStrategy = errors.New("no strategy given")
@@ -57,9 +60,49 @@ func (s *strategerizer) StrategyFor(request baggageclaim.VolumeRequest) (Strateg
 			Path:           path,
 			FollowSymlinks: followSymlinks,
 		}
+	case StrategyDedupe:
+		digest, _ := strategyInfo["digest"].(string)
+		strategy = DedupeStrategy{
+			Digest:     digest,
+			Privileged: request.Privileged,
+		}
+	case StrategyLayer:
+		volume, _ := strategyInfo["volume"].(string)
+		path, _ := strategyInfo["path"].(string)
+		strategy = LayerStrategy{
+			ParentHandle: volume,
+			Path:         path,
+		}
+	case StrategyOCI:
+		path, _ := strategyInfo["path"].(string)
+		ref, _ := strategyInfo["ref"].(string)
+		strategy = OCIStrategy{
+			Path: path,
+			Ref:  ref,
+		}
 	default:
 		return nil, ErrUnknownStrategy
 	}
 
 	return strategy, nil
 }
+
+// strategyName returns the request type corresponding to the given strategy.
+func strategyName(strategy Strategy) string {
+	switch strategy.(type) {
+	case EmptyStrategy:
+		return StrategyEmpty
+	case COWStrategy:
+		return StrategyCopyOnWrite
+	case ImportStrategy:
+		return StrategyImport
+	case DedupeStrategy:
+		return StrategyDedupe
+	case LayerStrategy:
+		return StrategyLayer
+	case OCIStrategy:
+		return StrategyOCI
+	default:
+		return "unknown"
+	}
+}
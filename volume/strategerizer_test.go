@@ -102,5 +102,71 @@ var _ = Describe("Strategerizer", func() {
 				Expect(strategy).To(Equal(volume.COWStrategy{ParentHandle: "parent-handle"}))
 			})
 		})
+
+		Context("with a dedupe strategy", func() {
+			BeforeEach(func() {
+				request.Strategy = baggageclaim.DedupeStrategy{Digest: "sha256:some-digest"}.Encode()
+			})
+
+			It("succeeds", func() {
+				Expect(strategyForErr).ToNot(HaveOccurred())
+			})
+
+			It("constructs a dedupe strategy", func() {
+				Expect(strategy).To(Equal(volume.DedupeStrategy{Digest: "sha256:some-digest"}))
+			})
+
+			Context("when the volume is to be privileged", func() {
+				BeforeEach(func() {
+					request.Privileged = true
+				})
+
+				It("only dedupes onto privileged volumes", func() {
+					Expect(strategy).To(Equal(volume.DedupeStrategy{Digest: "sha256:some-digest", Privileged: true}))
+				})
+			})
+		})
+
+		Context("with a layer strategy", func() {
+			BeforeEach(func() {
+				volume := new(baggageclaimfakes.FakeVolume)
+				volume.HandleReturns("parent-handle")
+				request.Strategy = baggageclaim.LayerStrategy{
+					Parent: volume,
+					Path:   "/some/host/layer.tgz",
+				}.Encode()
+			})
+
+			It("succeeds", func() {
+				Expect(strategyForErr).ToNot(HaveOccurred())
+			})
+
+			It("constructs a layer strategy", func() {
+				Expect(strategy).To(Equal(volume.LayerStrategy{
+					ParentHandle: "parent-handle",
+					Path:         "/some/host/layer.tgz",
+				}))
+			})
+		})
+
+		Context("with an oci strategy", func() {
+			BeforeEach(func() {
+				request.Strategy = baggageclaim.OCIStrategy{
+					Path: "/some/host/image",
+					Ref:  "some-ref",
+				}.Encode()
+			})
+
+			It("succeeds", func() {
+				Expect(strategyForErr).ToNot(HaveOccurred())
+			})
+
+			It("constructs an oci strategy", func() {
+				Expect(strategy).To(Equal(volume.OCIStrategy{
+					Path: "/some/host/image",
+					Ref:  "some-ref",
+				}))
+			})
+		})
 	})
 })
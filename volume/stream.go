@@ -1,22 +1,120 @@
 package volume
 
 import (
+	"errors"
 	"io"
+	"io/ioutil"
+	"syscall"
 
 	"github.com/concourse/baggageclaim/uidgid"
+	"github.com/concourse/baggageclaim/volume/archive"
+	"github.com/concourse/baggageclaim/volume/fsroot"
 )
 
 //go:generate counterfeiter . Streamer
 
+// Streamer streams volume contents in and out. Each path is given along with
+// the root it lies within, typically a volume's data path, which neither the
+// path nor the stream's contents may lead outside of.
 type Streamer interface {
-	In(io.Reader, string, bool) (bool, error)
-	Out(io.Writer, string, bool) error
+	In(io.Reader, string, string, bool) (bool, error)
+	Out(io.Writer, string, string, bool) error
+
+	// InLayer is like In, but applies the whiteouts in the stream as
+	// deletions from what is already at the destination.
+	InLayer(io.Reader, string, string, bool) (bool, error)
+
+	// InResuming is like In, but passes over the given number of entries at
+	// the start of the stream, as they are already in place, and calls the
+	// given func with how many entries are in place as each one is extracted.
+	InResuming(io.Reader, string, string, bool, int, func(int)) (bool, error)
+
+	// OutDiff streams out the given changes beneath a directory as a layer.
+	OutDiff(io.Writer, string, string, []Change, bool) error
 }
 
-type tarZstdStreamer struct {
+// tarStreamer streams tar archives of volume contents, compressed with the
+// given encoding.
+type tarStreamer struct {
 	namespacer uidgid.Namespacer
+	encoding   Encoding
 }
 
-type tarGzipStreamer struct {
-	namespacer uidgid.Namespacer
+// extractTar unpacks a tar stream into dest within root as opts say, mapping
+// ownership into the namespacer's user namespace and leaving out devices and
+// privileged extended attributes unless the volume is privileged. Only archives
+// that cannot be read are reported as bad streams; failing to write their
+// contents is the volume's fault, not the client's.
+//
+// The rest of the stream is read once the archive ends so that a checksum
+// trailing a compressed stream is still verified.
+func extractTar(tarStream io.Reader, root string, dest string, privileged bool, namespacer uidgid.Namespacer, opts archive.ExtractOptions) (bool, error) {
+	if !privileged {
+		opts.MapIDs = namespacer.NamespaceIDs
+		opts.SkipDevices = true
+		opts.SkipPrivilegedXattrs = true
+	}
+
+	err := archive.Extract(tarStream, root, dest, opts)
+	if err != nil {
+		if isQuotaExceeded(err) {
+			return false, ErrQuotaExceeded
+		}
+
+		// the destination itself leads outside of the volume
+		if errors.Is(err, fsroot.ErrOutsideRoot) {
+			return true, err
+		}
+
+		var malformed *archive.MalformedArchiveError
+		if errors.As(err, &malformed) {
+			return true, err
+		}
+
+		return false, err
+	}
+
+	_, err = io.Copy(ioutil.Discard, tarStream)
+	if err != nil {
+		var malformed *archive.MalformedArchiveError
+		return errors.As(err, &malformed), err
+	}
+
+	return false, nil
+}
+
+// createTar writes a tar stream of src within root, mapping ownership out of
+// the namespacer's user namespace unless the volume is privileged.
+func createTar(w io.Writer, root string, src string, privileged bool, namespacer uidgid.Namespacer) error {
+	opts := archive.CreateOptions{}
+	if !privileged {
+		opts.MapIDs = namespacer.UnnamespaceIDs
+	}
+
+	return archive.Create(w, root, src, opts)
+}
+
+// createLayer writes a tar stream of the changes within src, mapping
+// ownership as createTar does.
+func createLayer(w io.Writer, root string, src string, changes []Change, privileged bool, namespacer uidgid.Namespacer) error {
+	opts := archive.CreateOptions{}
+	if !privileged {
+		opts.MapIDs = namespacer.UnnamespaceIDs
+	}
+
+	entries := make([]archive.LayerEntry, len(changes))
+	for i, change := range changes {
+		entries[i] = archive.LayerEntry{
+			Path:    change.Path,
+			Deleted: change.Kind == ChangeDeleted,
+		}
+	}
+
+	return archive.CreateLayer(w, root, src, entries, opts)
+}
+
+// isQuotaExceeded reports whether an extraction failed because the volume ran
+// out of quota.
+func isQuotaExceeded(err error) bool {
+	return errors.Is(err, syscall.EDQUOT)
 }
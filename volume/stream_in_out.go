@@ -0,0 +1,108 @@
+package volume
+
+import (
+	"io"
+
+	"github.com/concourse/baggageclaim/volume/archive"
+	"github.com/concourse/baggageclaim/volume/fsroot"
+)
+
+func (streamer *tarStreamer) In(stream io.Reader, root string, dest string, privileged bool) (bool, error) {
+	return streamer.in(stream, root, dest, privileged, archive.ExtractOptions{})
+}
+
+func (streamer *tarStreamer) InLayer(stream io.Reader, root string, dest string, privileged bool) (bool, error) {
+	return streamer.in(stream, root, dest, privileged, archive.ExtractOptions{Whiteouts: true})
+}
+
+func (streamer *tarStreamer) InResuming(stream io.Reader, root string, dest string, privileged bool, skip int, checkpoint func(int)) (bool, error) {
+	return streamer.in(stream, root, dest, privileged, archive.ExtractOptions{
+		Skip:       skip,
+		Checkpoint: checkpoint,
+	})
+}
+
+func (streamer *tarStreamer) in(stream io.Reader, root string, dest string, privileged bool, opts archive.ExtractOptions) (bool, error) {
+	source := &sourceReader{Reader: stream}
+
+	decoder, err := streamer.encoding.NewReader(source)
+	if err != nil {
+		if source.failed {
+			return false, err
+		}
+
+		return true, &archive.MalformedArchiveError{Err: err}
+	}
+
+	defer decoder.Close()
+
+	return extractTar(decodedReader{decoder: decoder, source: source}, root, dest, privileged, streamer.namespacer, opts)
+}
+
+// sourceReader notes whether reading the stream itself failed, e.g. because
+// the client went away.
+type sourceReader struct {
+	io.Reader
+	failed bool
+}
+
+func (r *sourceReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.failed = true
+	}
+
+	return n, err
+}
+
+// decodedReader reports failures to decompress the stream as a malformed
+// archive, unless they were caused by failing to read the stream.
+type decodedReader struct {
+	decoder io.Reader
+	source  *sourceReader
+}
+
+func (r decodedReader) Read(p []byte) (int, error) {
+	n, err := r.decoder.Read(p)
+	if err != nil && err != io.EOF && !r.source.failed {
+		err = &archive.MalformedArchiveError{Err: err}
+	}
+
+	return n, err
+}
+
+func (streamer *tarStreamer) Out(w io.Writer, root string, src string, privileged bool) error {
+	// fail before anything is written if there is nothing to stream
+	_, err := fsroot.Stat(root, src)
+	if err != nil {
+		return err
+	}
+
+	encoder, err := streamer.encoding.NewWriter(w)
+	if err != nil {
+		return err
+	}
+
+	err = createTar(encoder, root, src, privileged, streamer.namespacer)
+	if err != nil {
+		_ = encoder.Close()
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func (streamer *tarStreamer) OutDiff(w io.Writer, root string, src string, changes []Change, privileged bool) error {
+	encoder, err := streamer.encoding.NewWriter(w)
+	if err != nil {
+		return err
+	}
+
+	err = createLayer(encoder, root, src, changes, privileged, streamer.namespacer)
+	if err != nil {
+		_ = encoder.Close()
+		return err
+	}
+
+	return encoder.Close()
+}
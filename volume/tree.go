@@ -0,0 +1,270 @@
+package volume
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagerctx"
+
+	"github.com/concourse/baggageclaim/uidgid"
+	"github.com/concourse/baggageclaim/volume/fsroot"
+)
+
+var ErrPathOutsideVolume = errors.New("path leads outside of the volume")
+var ErrTreeTooLarge = errors.New("too many entries to describe")
+var ErrTreeTooDeep = errors.New("too many levels to describe")
+
+const (
+	// MaxTreeEntries is how many entries Tree may describe at once.
+	MaxTreeEntries = 10000
+
+	// MaxTreeDepth is how many levels down Tree may describe.
+	MaxTreeDepth = 64
+)
+
+type TreeEntryType string
+
+const (
+	TreeEntryFile        TreeEntryType = "file"
+	TreeEntryDirectory   TreeEntryType = "directory"
+	TreeEntrySymlink     TreeEntryType = "symlink"
+	TreeEntryFifo        TreeEntryType = "fifo"
+	TreeEntrySocket      TreeEntryType = "socket"
+	TreeEntryCharDevice  TreeEntryType = "char-device"
+	TreeEntryBlockDevice TreeEntryType = "block-device"
+)
+
+// TreeEntry describes a file within a volume.
+type TreeEntry struct {
+	// Name is the path of the entry relative to the path that was listed,
+	// which is itself named ".".
+	Name string        `json:"name"`
+	Type TreeEntryType `json:"type"`
+	Size int64         `json:"size"`
+
+	// Mode holds the permission bits, along with the setuid, setgid and
+	// sticky bits as they are stored on disk.
+	Mode uint32 `json:"mode"`
+
+	// UID and GID are the ownership as seen from within the volume, i.e.
+	// translated out of the user namespace for unprivileged volumes.
+	UID int `json:"uid"`
+	GID int `json:"gid"`
+
+	ModTime time.Time `json:"mtime"`
+
+	// LinkTarget is set for symlinks.
+	LinkTarget string `json:"link_target,omitempty"`
+}
+
+// Tree describes path within a volume and, if it is a directory, the entries
+// beneath it up to depth levels down, in lexical order. Symlinks are
+// described rather than followed, and a path which leads outside of the
+// volume is rejected. The description is built in memory, so it may hold no
+// more than MaxTreeEntries entries and descend no more than MaxTreeDepth
+// levels.
+func (repo *repository) Tree(ctx context.Context, handle string, path string, depth int) ([]TreeEntry, error) {
+	logger := lagerctx.FromContext(ctx).Session("tree", lager.Data{
+		"volume":   handle,
+		"sub-path": path,
+		"depth":    depth,
+	})
+
+	if depth > MaxTreeDepth {
+		return nil, ErrTreeTooDeep
+	}
+
+	volume, found, err := repo.filesystem.LookupVolume(handle)
+	if err != nil {
+		logger.Error("failed-to-lookup-volume", err)
+		return nil, err
+	}
+
+	if !found {
+		logger.Info("volume-not-found")
+		return nil, ErrVolumeDoesNotExist
+	}
+
+	privileged, err := volume.LoadPrivileged()
+	if err != nil {
+		logger.Error("failed-to-check-if-volume-is-privileged", err)
+		return nil, err
+	}
+
+	rootDir, err := fsroot.Open(volume.DataPath())
+	if err != nil {
+		logger.Error("failed-to-open-volume", err)
+		return nil, err
+	}
+
+	defer rootDir.Close()
+
+	// the path itself is not followed, as it may be a symlink to describe
+	parent, name, err := rootDir.Resolve(path, fsroot.ResolveOptions{})
+	if err != nil {
+		if errors.Is(err, fsroot.ErrOutsideRoot) {
+			logger.Info("path-outside-volume")
+			return nil, ErrPathOutsideVolume
+		}
+
+		if !os.IsNotExist(err) {
+			logger.Error("failed-to-resolve-path", err)
+		}
+
+		return nil, err
+	}
+
+	defer parent.Close()
+
+	walker := &treeWalker{
+		privileged: privileged,
+		namespacer: repo.namespacer(false),
+	}
+
+	err = walker.walk(parent, name, ".", depth)
+	if err != nil {
+		if err == ErrTreeTooLarge {
+			logger.Info("tree-too-large")
+		} else if !os.IsNotExist(err) {
+			logger.Error("failed-to-walk-tree", err)
+		}
+
+		return nil, err
+	}
+
+	return walker.entries, nil
+}
+
+type treeWalker struct {
+	privileged bool
+	namespacer uidgid.Namespacer
+
+	entries []TreeEntry
+}
+
+// walk describes the file called name within parent as entryName, and its
+// entries if it is a directory and depth allows.
+func (w *treeWalker) walk(parent *fsroot.Dir, name string, entryName string, depth int) error {
+	if len(w.entries) >= MaxTreeEntries {
+		return ErrTreeTooLarge
+	}
+
+	info, err := parent.Lstat(name)
+	if err != nil {
+		return err
+	}
+
+	var dir *fsroot.Dir
+	if info.IsDir() && depth > 0 {
+		dir, err = parent.OpenDir(name)
+		if err != nil {
+			return err
+		}
+
+		defer dir.Close()
+
+		// describe the directory that is listed, whatever may have been
+		// there before it was opened
+		info, err = dir.Stat()
+		if err != nil {
+			return err
+		}
+	}
+
+	entry := TreeEntry{
+		Name:    entryName,
+		Type:    treeEntryType(info.Mode()),
+		Size:    info.Size(),
+		Mode:    unixMode(info.Mode()),
+		ModTime: info.ModTime(),
+	}
+
+	entry.UID, entry.GID = fileOwner(info)
+	if !w.privileged {
+		entry.UID, entry.GID = w.namespacer.UnnamespaceIDs(entry.UID, entry.GID)
+	}
+
+	if entry.Type == TreeEntrySymlink {
+		target, err := parent.Readlink(name)
+		if err != nil {
+			return err
+		}
+
+		entry.LinkTarget = target
+	}
+
+	w.entries = append(w.entries, entry)
+
+	if dir == nil {
+		return nil
+	}
+
+	children, err := dir.Readdirnames()
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(children)
+
+	for _, child := range children {
+		childName := child
+		if entryName != "." {
+			childName = entryName + "/" + child
+		}
+
+		err := w.walk(dir, child, childName, depth-1)
+		if os.IsNotExist(err) {
+			// removed since the directory was listed
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func treeEntryType(mode os.FileMode) TreeEntryType {
+	switch {
+	case mode.IsDir():
+		return TreeEntryDirectory
+	case mode&os.ModeSymlink != 0:
+		return TreeEntrySymlink
+	case mode&os.ModeNamedPipe != 0:
+		return TreeEntryFifo
+	case mode&os.ModeSocket != 0:
+		return TreeEntrySocket
+	case mode&os.ModeCharDevice != 0:
+		return TreeEntryCharDevice
+	case mode&os.ModeDevice != 0:
+		return TreeEntryBlockDevice
+	default:
+		return TreeEntryFile
+	}
+}
+
+// unixMode converts the permission and special bits of mode to how they are
+// represented on disk, e.g. 04755 for a setuid executable.
+func unixMode(mode os.FileMode) uint32 {
+	bits := uint32(mode.Perm())
+
+	if mode&os.ModeSetuid != 0 {
+		bits |= 04000
+	}
+
+	if mode&os.ModeSetgid != 0 {
+		bits |= 02000
+	}
+
+	if mode&os.ModeSticky != 0 {
+		bits |= 01000
+	}
+
+	return bits
+}
@@ -0,0 +1,207 @@
+package volume_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/uidgid/uidgidfakes"
+	"github.com/concourse/baggageclaim/volume"
+	"github.com/concourse/baggageclaim/volume/volumefakes"
+)
+
+var _ = Describe("Tree", func() {
+	var (
+		fakeFilesystem             *volumefakes.FakeFilesystem
+		fakeVolume                 *volumefakes.FakeFilesystemLiveVolume
+		fakeUnprivilegedNamespacer *uidgidfakes.FakeNamespacer
+
+		repository volume.Repository
+
+		tmpdir  string
+		dataDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = ioutil.TempDir("", "tree")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.Mkdir(filepath.Join(tmpdir, "outside"), 0755)).To(Succeed())
+
+		dataDir = filepath.Join(tmpdir, "volume")
+		Expect(os.MkdirAll(filepath.Join(dataDir, "some-dir", "nested-dir"), 0755)).To(Succeed())
+
+		err = ioutil.WriteFile(filepath.Join(dataDir, "some-dir", "some-file"), []byte("some-contents"), 0640)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(dataDir, "some-dir", "nested-dir", "nested-file"), []byte("nested"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.Symlink("some-file", filepath.Join(dataDir, "some-dir", "some-link"))).To(Succeed())
+		Expect(os.Symlink(filepath.Join(tmpdir, "outside"), filepath.Join(dataDir, "outside-link"))).To(Succeed())
+
+		fakeVolume = new(volumefakes.FakeFilesystemLiveVolume)
+		fakeVolume.DataPathReturns(dataDir)
+
+		fakeFilesystem = new(volumefakes.FakeFilesystem)
+		fakeFilesystem.LookupVolumeReturns(fakeVolume, true, nil)
+
+		fakeUnprivilegedNamespacer = new(uidgidfakes.FakeNamespacer)
+		fakeUnprivilegedNamespacer.UnnamespaceIDsStub = func(uid, gid int) (int, int) {
+			return uid + 1000, gid + 2000
+		}
+
+		repository = volume.NewRepository(
+			fakeFilesystem,
+			new(volumefakes.FakeLockManager),
+			new(uidgidfakes.FakeNamespacer),
+			fakeUnprivilegedNamespacer,
+			nil,
+			0,
+		)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpdir)
+	})
+
+	names := func(entries []volume.TreeEntry) []string {
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Name)
+		}
+
+		return names
+	}
+
+	Context("when the volume is privileged", func() {
+		BeforeEach(func() {
+			fakeVolume.LoadPrivilegedReturns(true, nil)
+		})
+
+		It("describes the path and the entries directly within it", func() {
+			entries, err := repository.Tree(context.Background(), "some-handle", "some-dir", 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(names(entries)).To(Equal([]string{".", "nested-dir", "some-file", "some-link"}))
+
+			Expect(entries[0].Type).To(Equal(volume.TreeEntryDirectory))
+			Expect(entries[0].Mode).To(Equal(uint32(0755)))
+
+			file := entries[2]
+			Expect(file.Type).To(Equal(volume.TreeEntryFile))
+			Expect(file.Size).To(Equal(int64(len("some-contents"))))
+			Expect(file.Mode).To(Equal(uint32(0640)))
+			Expect(file.UID).To(Equal(os.Getuid()))
+			Expect(file.GID).To(Equal(os.Getgid()))
+			Expect(file.ModTime).ToNot(BeZero())
+
+			link := entries[3]
+			Expect(link.Type).To(Equal(volume.TreeEntrySymlink))
+			Expect(link.LinkTarget).To(Equal("some-file"))
+		})
+
+		It("descends as deep as asked", func() {
+			entries, err := repository.Tree(context.Background(), "some-handle", "", 3)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(names(entries)).To(Equal([]string{
+				".",
+				"outside-link",
+				"some-dir",
+				"some-dir/nested-dir",
+				"some-dir/nested-dir/nested-file",
+				"some-dir/some-file",
+				"some-dir/some-link",
+			}))
+		})
+
+		It("describes only the path itself at a depth of zero", func() {
+			entries, err := repository.Tree(context.Background(), "some-handle", "/some-dir/some-file", 0)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(names(entries)).To(Equal([]string{"."}))
+			Expect(entries[0].Type).To(Equal(volume.TreeEntryFile))
+		})
+
+		It("describes symlinks rather than following them", func() {
+			entries, err := repository.Tree(context.Background(), "some-handle", "outside-link", 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Type).To(Equal(volume.TreeEntrySymlink))
+			Expect(entries[0].LinkTarget).To(Equal(filepath.Join(tmpdir, "outside")))
+		})
+
+		It("rejects paths which lead outside of the volume", func() {
+			_, err := repository.Tree(context.Background(), "some-handle", "../outside", 1)
+			Expect(err).To(Equal(volume.ErrPathOutsideVolume))
+
+			_, err = repository.Tree(context.Background(), "some-handle", "some-dir/../../outside", 1)
+			Expect(err).To(Equal(volume.ErrPathOutsideVolume))
+
+			_, err = repository.Tree(context.Background(), "some-handle", "outside-link/some-file", 1)
+			Expect(err).To(Equal(volume.ErrPathOutsideVolume))
+		})
+
+		It("refuses to descend too deep", func() {
+			_, err := repository.Tree(context.Background(), "some-handle", "", volume.MaxTreeDepth+1)
+			Expect(err).To(Equal(volume.ErrTreeTooDeep))
+		})
+
+		It("refuses to describe too many entries", func() {
+			manyDir := filepath.Join(dataDir, "many")
+			Expect(os.Mkdir(manyDir, 0755)).To(Succeed())
+
+			for i := 0; i < volume.MaxTreeEntries; i++ {
+				Expect(ioutil.WriteFile(filepath.Join(manyDir, strconv.Itoa(i)), nil, 0644)).To(Succeed())
+			}
+
+			_, err := repository.Tree(context.Background(), "some-handle", "many", 1)
+			Expect(err).To(Equal(volume.ErrTreeTooLarge))
+
+			entries, err := repository.Tree(context.Background(), "some-handle", "many", 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+
+		It("returns a not-exist error for missing paths", func() {
+			_, err := repository.Tree(context.Background(), "some-handle", "some-dir/bogus", 1)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+
+			_, err = repository.Tree(context.Background(), "some-handle", "bogus/bogus", 1)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	})
+
+	Context("when the volume is unprivileged", func() {
+		BeforeEach(func() {
+			fakeVolume.LoadPrivilegedReturns(false, nil)
+		})
+
+		It("translates ownership out of the namespace", func() {
+			entries, err := repository.Tree(context.Background(), "some-handle", "some-dir/some-file", 0)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(entries[0].UID).To(Equal(os.Getuid() + 1000))
+			Expect(entries[0].GID).To(Equal(os.Getgid() + 2000))
+		})
+	})
+
+	Context("when the volume does not exist", func() {
+		BeforeEach(func() {
+			fakeFilesystem.LookupVolumeReturns(nil, false, nil)
+		})
+
+		It("returns ErrVolumeDoesNotExist", func() {
+			_, err := repository.Tree(context.Background(), "some-handle", "", 1)
+			Expect(err).To(Equal(volume.ErrVolumeDoesNotExist))
+		})
+	})
+})
@@ -0,0 +1,216 @@
+package volume
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var ErrInvalidUploadSession = errors.New("invalid upload session")
+var ErrUploadConflict = errors.New("upload session was started for a different path or encoding")
+var ErrUploadIncomplete = errors.New("upload was interrupted before the end of the stream")
+var ErrUploadTooLarge = errors.New("upload exceeds the maximum upload size")
+
+// UploadOffsetError is returned when a resumable stream-in does not continue
+// from where the upload session left off. Offset is where it must resume.
+type UploadOffsetError struct {
+	Offset int64
+}
+
+func (err UploadOffsetError) Error() string {
+	return fmt.Sprintf("upload must resume from offset %d", err.Offset)
+}
+
+const uploadsDirname = "uploads"
+
+var uploadSessionPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// upload is the state of a resumable stream-in. Everything received so far
+// is spooled to disk, so that once the client resumes the stream can be
+// decoded again without it having to be sent again, passing over the entries
+// that were already extracted.
+type upload struct {
+	statePath string
+	spoolPath string
+}
+
+type uploadState struct {
+	Path     string `json:"path"`
+	Encoding string `json:"encoding"`
+
+	// Entries is how many entries at the start of the stream were in place
+	// when the upload was last interrupted.
+	Entries int `json:"entries,omitempty"`
+}
+
+func newUpload(dir string, session string) upload {
+	return upload{
+		statePath: filepath.Join(dir, session+".json"),
+		spoolPath: filepath.Join(dir, session+".partial"),
+	}
+}
+
+// load returns the state of the upload, or false if it was never started or
+// has since finished.
+func (u upload) load() (uploadState, bool, error) {
+	_, err := os.Stat(u.statePath)
+	if os.IsNotExist(err) {
+		return uploadState{}, false, nil
+	}
+
+	var state uploadState
+
+	err = readMetadataFile(u.statePath, &state)
+	if err != nil {
+		return uploadState{}, false, err
+	}
+
+	return state, true, nil
+}
+
+// lastActive is when the upload last received anything, or was started.
+func (u upload) lastActive() (time.Time, error) {
+	info, err := os.Stat(u.spoolPath)
+	if os.IsNotExist(err) {
+		info, err = os.Stat(u.statePath)
+	}
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
+}
+
+// offset is how much of the stream has been received.
+func (u upload) offset() (int64, error) {
+	info, err := os.Stat(u.spoolPath)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+// start begins the upload afresh, discarding anything already received.
+func (u upload) start(state uploadState) error {
+	err := u.remove()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(u.statePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	spool, err := os.Create(u.spoolPath)
+	if err != nil {
+		return err
+	}
+
+	err = spool.Close()
+	if err != nil {
+		return err
+	}
+
+	return writeMetadataFile(u.statePath, state)
+}
+
+// checkpoint records how far the upload got, once it has been interrupted.
+func (u upload) checkpoint(state uploadState) error {
+	return writeMetadataFile(u.statePath, state)
+}
+
+func (u upload) remove() error {
+	for _, path := range []string{u.statePath, u.spoolPath} {
+		err := os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listUploadSessions returns the sessions with state in dir.
+func listUploadSessions(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	sessions := []string{}
+
+	for _, info := range infos {
+		ext := filepath.Ext(info.Name())
+		if ext != ".json" && ext != ".partial" {
+			continue
+		}
+
+		session := strings.TrimSuffix(info.Name(), ext)
+		if seen[session] || !uploadSessionPattern.MatchString(session) {
+			continue
+		}
+
+		seen[session] = true
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// spoolingReader copies everything read from the client into the upload's
+// spool, recording why reading stopped so that an interrupted upload can be
+// told apart from a bad stream. Once the spool holds limit bytes, if limit is
+// non-zero, reading fails with limitErr.
+type spoolingReader struct {
+	body  io.Reader
+	spool io.Writer
+
+	spooled  int64
+	limit    int64
+	limitErr error
+
+	bodyErr  error
+	spoolErr error
+}
+
+func (r *spoolingReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if n > 0 {
+		if r.limit > 0 && r.spooled+int64(n) > r.limit {
+			r.spoolErr = r.limitErr
+			return 0, r.limitErr
+		}
+
+		_, werr := r.spool.Write(p[:n])
+		if werr != nil {
+			r.spoolErr = werr
+			return n, werr
+		}
+
+		r.spooled += int64(n)
+	}
+
+	if err != nil && err != io.EOF {
+		r.bodyErr = err
+	}
+
+	return n, err
+}
@@ -0,0 +1,66 @@
+package volume
+
+import (
+	"sync"
+	"time"
+)
+
+// usageTTL is how long a volume's usage is reused for when sorting by size.
+// It covers paging through a listing, which would otherwise measure every
+// volume again for every page.
+const usageTTL = time.Minute
+
+// usageCache remembers the volumes' usage measured for sorting them by size.
+type usageCache struct {
+	mutex  sync.Mutex
+	usages map[string]measuredUsage
+}
+
+type measuredUsage struct {
+	usage      VolumeUsage
+	measuredAt time.Time
+}
+
+func newUsageCache() *usageCache {
+	return &usageCache{
+		usages: map[string]measuredUsage{},
+	}
+}
+
+// measure returns the volume's usage, measuring it only if it has not been
+// measured within usageTTL.
+func (cache *usageCache) measure(liveVolume FilesystemLiveVolume) (VolumeUsage, error) {
+	handle := liveVolume.Handle()
+
+	cache.mutex.Lock()
+	measured, found := cache.usages[handle]
+	cache.mutex.Unlock()
+
+	if found && time.Since(measured.measuredAt) < usageTTL {
+		return measured.usage, nil
+	}
+
+	usage, err := liveVolume.Usage()
+	if err != nil {
+		return VolumeUsage{}, err
+	}
+
+	cache.mutex.Lock()
+	cache.usages[handle] = measuredUsage{usage: usage, measuredAt: time.Now()}
+	cache.mutex.Unlock()
+
+	return usage, nil
+}
+
+// expire forgets the usage measured longer than usageTTL ago, including that
+// of volumes which have since been destroyed.
+func (cache *usageCache) expire() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for handle, measured := range cache.usages {
+		if time.Since(measured.measuredAt) >= usageTTL {
+			delete(cache.usages, handle)
+		}
+	}
+}
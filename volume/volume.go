@@ -1,10 +1,159 @@
 package volume
 
+import "time"
+
 type Volume struct {
-	Handle     string     `json:"handle"`
-	Path       string     `json:"path"`
-	Properties Properties `json:"properties"`
-	Privileged bool       `json:"privileged"`
+	Handle     string       `json:"handle"`
+	Path       string       `json:"path"`
+	Properties Properties   `json:"properties"`
+	Privileged bool         `json:"privileged"`
+	Usage      *VolumeUsage `json:"usage,omitempty"`
+
+	// TTL is the number of seconds remaining before the volume is reaped, or
+	// nil if the volume lives until it is destroyed.
+	TTL *uint `json:"ttl,omitempty"`
+
+	Snapshots []Snapshot `json:"snapshots,omitempty"`
+
+	// ReadOnly is set once the volume's contents have been sealed.
+	ReadOnly bool `json:"read_only,omitempty"`
+
+	// CreatedAt is nil for volumes created before their creation time was
+	// recorded.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
 }
 
 type Volumes []Volume
+
+// VolumeSpec describes a volume to create.
+type VolumeSpec struct {
+	// Strategy materializes the volume's initial contents.
+	Strategy Strategy
+
+	Properties Properties
+
+	// Privileged volumes' contents are not namespaced.
+	Privileged bool
+
+	// QuotaBytes limits the volume's size, or 0 for no limit.
+	QuotaBytes uint64
+
+	// TTL is how long the volume lives before it is reaped, or 0 for it to
+	// live until it is destroyed.
+	TTL time.Duration
+
+	// ReadOnly seals the volume once its strategy has populated it.
+	ReadOnly bool
+}
+
+// Cursor returns the volume's position in a sorted listing. Its size is only
+// known if the volume's usage has been loaded.
+func (volume Volume) Cursor() VolumeCursor {
+	cursor := VolumeCursor{Handle: volume.Handle}
+
+	if volume.CreatedAt != nil {
+		cursor.CreatedAt = *volume.CreatedAt
+	}
+
+	if volume.Usage != nil {
+		cursor.Size = volume.Usage.ExclusiveBytes
+	}
+
+	return cursor
+}
+
+type VolumeSortKey string
+
+const (
+	SortByHandle    VolumeSortKey = "handle"
+	SortByCreatedAt VolumeSortKey = "created"
+	SortBySize      VolumeSortKey = "size"
+)
+
+// VolumeQuery selects the volumes to visit with VisitVolumes, and the order
+// in which to visit them.
+type VolumeQuery struct {
+	Properties Properties
+
+	// SortBy defaults to SortByHandle. Volumes that sort equally are ordered
+	// by handle. Sorting by size orders volumes by their exclusive bytes, and
+	// has to measure every volume's usage; measurements are reused for a
+	// minute, so that the pages of a listing do not measure them all again.
+	SortBy     VolumeSortKey
+	Descending bool
+
+	// After continues a listing from where a previous one left off; only the
+	// volumes that sort after it are visited.
+	After *VolumeCursor
+
+	// Limit is the most volumes to visit, or 0 to visit them all.
+	Limit int
+}
+
+// VolumeCursor is a position in a sorted listing of volumes.
+type VolumeCursor struct {
+	Handle    string
+	CreatedAt time.Time
+	Size      uint64
+}
+
+// less reports whether a sorts before b in the query's order.
+func (query VolumeQuery) less(a VolumeCursor, b VolumeCursor) bool {
+	if query.Descending {
+		a, b = b, a
+	}
+
+	switch query.SortBy {
+	case SortByCreatedAt:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	case SortBySize:
+		if a.Size != b.Size {
+			return a.Size < b.Size
+		}
+	}
+
+	return a.Handle < b.Handle
+}
+
+type VolumeUsage struct {
+	ExclusiveBytes uint64 `json:"exclusive_bytes"`
+	SharedBytes    uint64 `json:"shared_bytes"`
+	Inodes         uint64 `json:"inodes"`
+}
+
+// OrphanStats describes volumes left behind in the init and dead directories.
+type OrphanStats struct {
+	// Pending is the number of orphans awaiting collection.
+	Pending int `json:"pending"`
+
+	// Reclaimed is the number of orphans destroyed since startup.
+	Reclaimed uint64 `json:"reclaimed"`
+}
+
+type FsckOptions struct {
+	// Repair asks the driver to fix corrupted volumes where it can, e.g. by
+	// remounting them.
+	Repair bool
+
+	// Quarantine moves volumes that are still corrupted out of the live
+	// directory.
+	Quarantine bool
+}
+
+type FsckReport struct {
+	Checked   int               `json:"checked"`
+	Corrupted []CorruptedVolume `json:"corrupted"`
+}
+
+type CorruptedVolume struct {
+	Handle   string   `json:"handle"`
+	Problems []string `json:"problems"`
+
+	Repaired    bool `json:"repaired"`
+	Quarantined bool `json:"quarantined"`
+
+	// Error is set if repairing or quarantining the volume failed.
+	Error string `json:"error,omitempty"`
+}
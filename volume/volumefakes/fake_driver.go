@@ -8,6 +8,17 @@ import (
 )
 
 type FakeDriver struct {
+	CheckStub        func(volume.FilesystemVolume) error
+	checkMutex       sync.RWMutex
+	checkArgsForCall []struct {
+		arg1 volume.FilesystemVolume
+	}
+	checkReturns struct {
+		result1 error
+	}
+	checkReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CreateCopyOnWriteLayerStub        func(volume.FilesystemInitVolume, volume.FilesystemLiveVolume) error
 	createCopyOnWriteLayerMutex       sync.RWMutex
 	createCopyOnWriteLayerArgsForCall []struct {
@@ -20,6 +31,18 @@ type FakeDriver struct {
 	createCopyOnWriteLayerReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CreateSnapshotStub        func(volume.FilesystemLiveVolume, volume.FilesystemSnapshot) error
+	createSnapshotMutex       sync.RWMutex
+	createSnapshotArgsForCall []struct {
+		arg1 volume.FilesystemLiveVolume
+		arg2 volume.FilesystemSnapshot
+	}
+	createSnapshotReturns struct {
+		result1 error
+	}
+	createSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CreateVolumeStub        func(volume.FilesystemInitVolume) error
 	createVolumeMutex       sync.RWMutex
 	createVolumeArgsForCall []struct {
@@ -31,6 +54,17 @@ type FakeDriver struct {
 	createVolumeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DestroySnapshotStub        func(volume.FilesystemSnapshot) error
+	destroySnapshotMutex       sync.RWMutex
+	destroySnapshotArgsForCall []struct {
+		arg1 volume.FilesystemSnapshot
+	}
+	destroySnapshotReturns struct {
+		result1 error
+	}
+	destroySnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DestroyVolumeStub        func(volume.FilesystemVolume) error
 	destroyVolumeMutex       sync.RWMutex
 	destroyVolumeArgsForCall []struct {
@@ -42,6 +76,20 @@ type FakeDriver struct {
 	destroyVolumeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DiffStub        func(volume.FilesystemVolume, volume.FilesystemLiveVolume) ([]volume.Change, error)
+	diffMutex       sync.RWMutex
+	diffArgsForCall []struct {
+		arg1 volume.FilesystemVolume
+		arg2 volume.FilesystemLiveVolume
+	}
+	diffReturns struct {
+		result1 []volume.Change
+		result2 error
+	}
+	diffReturnsOnCall map[int]struct {
+		result1 []volume.Change
+		result2 error
+	}
 	RecoverStub        func(volume.Filesystem) error
 	recoverMutex       sync.RWMutex
 	recoverArgsForCall []struct {
@@ -53,10 +101,152 @@ type FakeDriver struct {
 	recoverReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RenameVolumeStub        func(volume.FilesystemVolume, string) error
+	renameVolumeMutex       sync.RWMutex
+	renameVolumeArgsForCall []struct {
+		arg1 volume.FilesystemVolume
+		arg2 string
+	}
+	renameVolumeReturns struct {
+		result1 error
+	}
+	renameVolumeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RepairStub        func(volume.FilesystemVolume) error
+	repairMutex       sync.RWMutex
+	repairArgsForCall []struct {
+		arg1 volume.FilesystemVolume
+	}
+	repairReturns struct {
+		result1 error
+	}
+	repairReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RestoreSnapshotStub        func(volume.FilesystemLiveVolume, volume.FilesystemSnapshot) error
+	restoreSnapshotMutex       sync.RWMutex
+	restoreSnapshotArgsForCall []struct {
+		arg1 volume.FilesystemLiveVolume
+		arg2 volume.FilesystemSnapshot
+	}
+	restoreSnapshotReturns struct {
+		result1 error
+	}
+	restoreSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetQuotaStub        func(volume.FilesystemVolume, uint64) error
+	setQuotaMutex       sync.RWMutex
+	setQuotaArgsForCall []struct {
+		arg1 volume.FilesystemVolume
+		arg2 uint64
+	}
+	setQuotaReturns struct {
+		result1 error
+	}
+	setQuotaReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetReadOnlyStub        func(volume.FilesystemVolume) error
+	setReadOnlyMutex       sync.RWMutex
+	setReadOnlyArgsForCall []struct {
+		arg1 volume.FilesystemVolume
+	}
+	setReadOnlyReturns struct {
+		result1 error
+	}
+	setReadOnlyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SharesLayersStub        func() bool
+	sharesLayersMutex       sync.RWMutex
+	sharesLayersArgsForCall []struct {
+	}
+	sharesLayersReturns struct {
+		result1 bool
+	}
+	sharesLayersReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	UsageStub        func(volume.FilesystemVolume) (volume.VolumeUsage, error)
+	usageMutex       sync.RWMutex
+	usageArgsForCall []struct {
+		arg1 volume.FilesystemVolume
+	}
+	usageReturns struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}
+	usageReturnsOnCall map[int]struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeDriver) Check(arg1 volume.FilesystemVolume) error {
+	fake.checkMutex.Lock()
+	ret, specificReturn := fake.checkReturnsOnCall[len(fake.checkArgsForCall)]
+	fake.checkArgsForCall = append(fake.checkArgsForCall, struct {
+		arg1 volume.FilesystemVolume
+	}{arg1})
+	stub := fake.CheckStub
+	fakeReturns := fake.checkReturns
+	fake.recordInvocation("Check", []interface{}{arg1})
+	fake.checkMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriver) CheckCallCount() int {
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
+	return len(fake.checkArgsForCall)
+}
+
+func (fake *FakeDriver) CheckCalls(stub func(volume.FilesystemVolume) error) {
+	fake.checkMutex.Lock()
+	defer fake.checkMutex.Unlock()
+	fake.CheckStub = stub
+}
+
+func (fake *FakeDriver) CheckArgsForCall(i int) volume.FilesystemVolume {
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
+	argsForCall := fake.checkArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDriver) CheckReturns(result1 error) {
+	fake.checkMutex.Lock()
+	defer fake.checkMutex.Unlock()
+	fake.CheckStub = nil
+	fake.checkReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) CheckReturnsOnCall(i int, result1 error) {
+	fake.checkMutex.Lock()
+	defer fake.checkMutex.Unlock()
+	fake.CheckStub = nil
+	if fake.checkReturnsOnCall == nil {
+		fake.checkReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeDriver) CreateCopyOnWriteLayer(arg1 volume.FilesystemInitVolume, arg2 volume.FilesystemLiveVolume) error {
 	fake.createCopyOnWriteLayerMutex.Lock()
 	ret, specificReturn := fake.createCopyOnWriteLayerReturnsOnCall[len(fake.createCopyOnWriteLayerArgsForCall)]
@@ -64,15 +254,16 @@ func (fake *FakeDriver) CreateCopyOnWriteLayer(arg1 volume.FilesystemInitVolume,
 		arg1 volume.FilesystemInitVolume
 		arg2 volume.FilesystemLiveVolume
 	}{arg1, arg2})
+	stub := fake.CreateCopyOnWriteLayerStub
+	fakeReturns := fake.createCopyOnWriteLayerReturns
 	fake.recordInvocation("CreateCopyOnWriteLayer", []interface{}{arg1, arg2})
 	fake.createCopyOnWriteLayerMutex.Unlock()
-	if fake.CreateCopyOnWriteLayerStub != nil {
-		return fake.CreateCopyOnWriteLayerStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.createCopyOnWriteLayerReturns
 	return fakeReturns.result1
 }
 
@@ -118,21 +309,84 @@ func (fake *FakeDriver) CreateCopyOnWriteLayerReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
+func (fake *FakeDriver) CreateSnapshot(arg1 volume.FilesystemLiveVolume, arg2 volume.FilesystemSnapshot) error {
+	fake.createSnapshotMutex.Lock()
+	ret, specificReturn := fake.createSnapshotReturnsOnCall[len(fake.createSnapshotArgsForCall)]
+	fake.createSnapshotArgsForCall = append(fake.createSnapshotArgsForCall, struct {
+		arg1 volume.FilesystemLiveVolume
+		arg2 volume.FilesystemSnapshot
+	}{arg1, arg2})
+	stub := fake.CreateSnapshotStub
+	fakeReturns := fake.createSnapshotReturns
+	fake.recordInvocation("CreateSnapshot", []interface{}{arg1, arg2})
+	fake.createSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriver) CreateSnapshotCallCount() int {
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	return len(fake.createSnapshotArgsForCall)
+}
+
+func (fake *FakeDriver) CreateSnapshotCalls(stub func(volume.FilesystemLiveVolume, volume.FilesystemSnapshot) error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = stub
+}
+
+func (fake *FakeDriver) CreateSnapshotArgsForCall(i int) (volume.FilesystemLiveVolume, volume.FilesystemSnapshot) {
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	argsForCall := fake.createSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDriver) CreateSnapshotReturns(result1 error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = nil
+	fake.createSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) CreateSnapshotReturnsOnCall(i int, result1 error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = nil
+	if fake.createSnapshotReturnsOnCall == nil {
+		fake.createSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeDriver) CreateVolume(arg1 volume.FilesystemInitVolume) error {
 	fake.createVolumeMutex.Lock()
 	ret, specificReturn := fake.createVolumeReturnsOnCall[len(fake.createVolumeArgsForCall)]
 	fake.createVolumeArgsForCall = append(fake.createVolumeArgsForCall, struct {
 		arg1 volume.FilesystemInitVolume
 	}{arg1})
+	stub := fake.CreateVolumeStub
+	fakeReturns := fake.createVolumeReturns
 	fake.recordInvocation("CreateVolume", []interface{}{arg1})
 	fake.createVolumeMutex.Unlock()
-	if fake.CreateVolumeStub != nil {
-		return fake.CreateVolumeStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.createVolumeReturns
 	return fakeReturns.result1
 }
 
@@ -178,21 +432,83 @@ func (fake *FakeDriver) CreateVolumeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeDriver) DestroySnapshot(arg1 volume.FilesystemSnapshot) error {
+	fake.destroySnapshotMutex.Lock()
+	ret, specificReturn := fake.destroySnapshotReturnsOnCall[len(fake.destroySnapshotArgsForCall)]
+	fake.destroySnapshotArgsForCall = append(fake.destroySnapshotArgsForCall, struct {
+		arg1 volume.FilesystemSnapshot
+	}{arg1})
+	stub := fake.DestroySnapshotStub
+	fakeReturns := fake.destroySnapshotReturns
+	fake.recordInvocation("DestroySnapshot", []interface{}{arg1})
+	fake.destroySnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriver) DestroySnapshotCallCount() int {
+	fake.destroySnapshotMutex.RLock()
+	defer fake.destroySnapshotMutex.RUnlock()
+	return len(fake.destroySnapshotArgsForCall)
+}
+
+func (fake *FakeDriver) DestroySnapshotCalls(stub func(volume.FilesystemSnapshot) error) {
+	fake.destroySnapshotMutex.Lock()
+	defer fake.destroySnapshotMutex.Unlock()
+	fake.DestroySnapshotStub = stub
+}
+
+func (fake *FakeDriver) DestroySnapshotArgsForCall(i int) volume.FilesystemSnapshot {
+	fake.destroySnapshotMutex.RLock()
+	defer fake.destroySnapshotMutex.RUnlock()
+	argsForCall := fake.destroySnapshotArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDriver) DestroySnapshotReturns(result1 error) {
+	fake.destroySnapshotMutex.Lock()
+	defer fake.destroySnapshotMutex.Unlock()
+	fake.DestroySnapshotStub = nil
+	fake.destroySnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) DestroySnapshotReturnsOnCall(i int, result1 error) {
+	fake.destroySnapshotMutex.Lock()
+	defer fake.destroySnapshotMutex.Unlock()
+	fake.DestroySnapshotStub = nil
+	if fake.destroySnapshotReturnsOnCall == nil {
+		fake.destroySnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.destroySnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeDriver) DestroyVolume(arg1 volume.FilesystemVolume) error {
 	fake.destroyVolumeMutex.Lock()
 	ret, specificReturn := fake.destroyVolumeReturnsOnCall[len(fake.destroyVolumeArgsForCall)]
 	fake.destroyVolumeArgsForCall = append(fake.destroyVolumeArgsForCall, struct {
 		arg1 volume.FilesystemVolume
 	}{arg1})
+	stub := fake.DestroyVolumeStub
+	fakeReturns := fake.destroyVolumeReturns
 	fake.recordInvocation("DestroyVolume", []interface{}{arg1})
 	fake.destroyVolumeMutex.Unlock()
-	if fake.DestroyVolumeStub != nil {
-		return fake.DestroyVolumeStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.destroyVolumeReturns
 	return fakeReturns.result1
 }
 
@@ -238,21 +554,87 @@ func (fake *FakeDriver) DestroyVolumeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeDriver) Diff(arg1 volume.FilesystemVolume, arg2 volume.FilesystemLiveVolume) ([]volume.Change, error) {
+	fake.diffMutex.Lock()
+	ret, specificReturn := fake.diffReturnsOnCall[len(fake.diffArgsForCall)]
+	fake.diffArgsForCall = append(fake.diffArgsForCall, struct {
+		arg1 volume.FilesystemVolume
+		arg2 volume.FilesystemLiveVolume
+	}{arg1, arg2})
+	stub := fake.DiffStub
+	fakeReturns := fake.diffReturns
+	fake.recordInvocation("Diff", []interface{}{arg1, arg2})
+	fake.diffMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDriver) DiffCallCount() int {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	return len(fake.diffArgsForCall)
+}
+
+func (fake *FakeDriver) DiffCalls(stub func(volume.FilesystemVolume, volume.FilesystemLiveVolume) ([]volume.Change, error)) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = stub
+}
+
+func (fake *FakeDriver) DiffArgsForCall(i int) (volume.FilesystemVolume, volume.FilesystemLiveVolume) {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	argsForCall := fake.diffArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDriver) DiffReturns(result1 []volume.Change, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	fake.diffReturns = struct {
+		result1 []volume.Change
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDriver) DiffReturnsOnCall(i int, result1 []volume.Change, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	if fake.diffReturnsOnCall == nil {
+		fake.diffReturnsOnCall = make(map[int]struct {
+			result1 []volume.Change
+			result2 error
+		})
+	}
+	fake.diffReturnsOnCall[i] = struct {
+		result1 []volume.Change
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeDriver) Recover(arg1 volume.Filesystem) error {
 	fake.recoverMutex.Lock()
 	ret, specificReturn := fake.recoverReturnsOnCall[len(fake.recoverArgsForCall)]
 	fake.recoverArgsForCall = append(fake.recoverArgsForCall, struct {
 		arg1 volume.Filesystem
 	}{arg1})
+	stub := fake.RecoverStub
+	fakeReturns := fake.recoverReturns
 	fake.recordInvocation("Recover", []interface{}{arg1})
 	fake.recoverMutex.Unlock()
-	if fake.RecoverStub != nil {
-		return fake.RecoverStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.recoverReturns
 	return fakeReturns.result1
 }
 
@@ -298,17 +680,464 @@ func (fake *FakeDriver) RecoverReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeDriver) RenameVolume(arg1 volume.FilesystemVolume, arg2 string) error {
+	fake.renameVolumeMutex.Lock()
+	ret, specificReturn := fake.renameVolumeReturnsOnCall[len(fake.renameVolumeArgsForCall)]
+	fake.renameVolumeArgsForCall = append(fake.renameVolumeArgsForCall, struct {
+		arg1 volume.FilesystemVolume
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RenameVolumeStub
+	fakeReturns := fake.renameVolumeReturns
+	fake.recordInvocation("RenameVolume", []interface{}{arg1, arg2})
+	fake.renameVolumeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriver) RenameVolumeCallCount() int {
+	fake.renameVolumeMutex.RLock()
+	defer fake.renameVolumeMutex.RUnlock()
+	return len(fake.renameVolumeArgsForCall)
+}
+
+func (fake *FakeDriver) RenameVolumeCalls(stub func(volume.FilesystemVolume, string) error) {
+	fake.renameVolumeMutex.Lock()
+	defer fake.renameVolumeMutex.Unlock()
+	fake.RenameVolumeStub = stub
+}
+
+func (fake *FakeDriver) RenameVolumeArgsForCall(i int) (volume.FilesystemVolume, string) {
+	fake.renameVolumeMutex.RLock()
+	defer fake.renameVolumeMutex.RUnlock()
+	argsForCall := fake.renameVolumeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDriver) RenameVolumeReturns(result1 error) {
+	fake.renameVolumeMutex.Lock()
+	defer fake.renameVolumeMutex.Unlock()
+	fake.RenameVolumeStub = nil
+	fake.renameVolumeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) RenameVolumeReturnsOnCall(i int, result1 error) {
+	fake.renameVolumeMutex.Lock()
+	defer fake.renameVolumeMutex.Unlock()
+	fake.RenameVolumeStub = nil
+	if fake.renameVolumeReturnsOnCall == nil {
+		fake.renameVolumeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renameVolumeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) Repair(arg1 volume.FilesystemVolume) error {
+	fake.repairMutex.Lock()
+	ret, specificReturn := fake.repairReturnsOnCall[len(fake.repairArgsForCall)]
+	fake.repairArgsForCall = append(fake.repairArgsForCall, struct {
+		arg1 volume.FilesystemVolume
+	}{arg1})
+	stub := fake.RepairStub
+	fakeReturns := fake.repairReturns
+	fake.recordInvocation("Repair", []interface{}{arg1})
+	fake.repairMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriver) RepairCallCount() int {
+	fake.repairMutex.RLock()
+	defer fake.repairMutex.RUnlock()
+	return len(fake.repairArgsForCall)
+}
+
+func (fake *FakeDriver) RepairCalls(stub func(volume.FilesystemVolume) error) {
+	fake.repairMutex.Lock()
+	defer fake.repairMutex.Unlock()
+	fake.RepairStub = stub
+}
+
+func (fake *FakeDriver) RepairArgsForCall(i int) volume.FilesystemVolume {
+	fake.repairMutex.RLock()
+	defer fake.repairMutex.RUnlock()
+	argsForCall := fake.repairArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDriver) RepairReturns(result1 error) {
+	fake.repairMutex.Lock()
+	defer fake.repairMutex.Unlock()
+	fake.RepairStub = nil
+	fake.repairReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) RepairReturnsOnCall(i int, result1 error) {
+	fake.repairMutex.Lock()
+	defer fake.repairMutex.Unlock()
+	fake.RepairStub = nil
+	if fake.repairReturnsOnCall == nil {
+		fake.repairReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.repairReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) RestoreSnapshot(arg1 volume.FilesystemLiveVolume, arg2 volume.FilesystemSnapshot) error {
+	fake.restoreSnapshotMutex.Lock()
+	ret, specificReturn := fake.restoreSnapshotReturnsOnCall[len(fake.restoreSnapshotArgsForCall)]
+	fake.restoreSnapshotArgsForCall = append(fake.restoreSnapshotArgsForCall, struct {
+		arg1 volume.FilesystemLiveVolume
+		arg2 volume.FilesystemSnapshot
+	}{arg1, arg2})
+	stub := fake.RestoreSnapshotStub
+	fakeReturns := fake.restoreSnapshotReturns
+	fake.recordInvocation("RestoreSnapshot", []interface{}{arg1, arg2})
+	fake.restoreSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriver) RestoreSnapshotCallCount() int {
+	fake.restoreSnapshotMutex.RLock()
+	defer fake.restoreSnapshotMutex.RUnlock()
+	return len(fake.restoreSnapshotArgsForCall)
+}
+
+func (fake *FakeDriver) RestoreSnapshotCalls(stub func(volume.FilesystemLiveVolume, volume.FilesystemSnapshot) error) {
+	fake.restoreSnapshotMutex.Lock()
+	defer fake.restoreSnapshotMutex.Unlock()
+	fake.RestoreSnapshotStub = stub
+}
+
+func (fake *FakeDriver) RestoreSnapshotArgsForCall(i int) (volume.FilesystemLiveVolume, volume.FilesystemSnapshot) {
+	fake.restoreSnapshotMutex.RLock()
+	defer fake.restoreSnapshotMutex.RUnlock()
+	argsForCall := fake.restoreSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDriver) RestoreSnapshotReturns(result1 error) {
+	fake.restoreSnapshotMutex.Lock()
+	defer fake.restoreSnapshotMutex.Unlock()
+	fake.RestoreSnapshotStub = nil
+	fake.restoreSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) RestoreSnapshotReturnsOnCall(i int, result1 error) {
+	fake.restoreSnapshotMutex.Lock()
+	defer fake.restoreSnapshotMutex.Unlock()
+	fake.RestoreSnapshotStub = nil
+	if fake.restoreSnapshotReturnsOnCall == nil {
+		fake.restoreSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) SetQuota(arg1 volume.FilesystemVolume, arg2 uint64) error {
+	fake.setQuotaMutex.Lock()
+	ret, specificReturn := fake.setQuotaReturnsOnCall[len(fake.setQuotaArgsForCall)]
+	fake.setQuotaArgsForCall = append(fake.setQuotaArgsForCall, struct {
+		arg1 volume.FilesystemVolume
+		arg2 uint64
+	}{arg1, arg2})
+	stub := fake.SetQuotaStub
+	fakeReturns := fake.setQuotaReturns
+	fake.recordInvocation("SetQuota", []interface{}{arg1, arg2})
+	fake.setQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriver) SetQuotaCallCount() int {
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	return len(fake.setQuotaArgsForCall)
+}
+
+func (fake *FakeDriver) SetQuotaCalls(stub func(volume.FilesystemVolume, uint64) error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = stub
+}
+
+func (fake *FakeDriver) SetQuotaArgsForCall(i int) (volume.FilesystemVolume, uint64) {
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	argsForCall := fake.setQuotaArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDriver) SetQuotaReturns(result1 error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = nil
+	fake.setQuotaReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) SetQuotaReturnsOnCall(i int, result1 error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = nil
+	if fake.setQuotaReturnsOnCall == nil {
+		fake.setQuotaReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setQuotaReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) SetReadOnly(arg1 volume.FilesystemVolume) error {
+	fake.setReadOnlyMutex.Lock()
+	ret, specificReturn := fake.setReadOnlyReturnsOnCall[len(fake.setReadOnlyArgsForCall)]
+	fake.setReadOnlyArgsForCall = append(fake.setReadOnlyArgsForCall, struct {
+		arg1 volume.FilesystemVolume
+	}{arg1})
+	stub := fake.SetReadOnlyStub
+	fakeReturns := fake.setReadOnlyReturns
+	fake.recordInvocation("SetReadOnly", []interface{}{arg1})
+	fake.setReadOnlyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriver) SetReadOnlyCallCount() int {
+	fake.setReadOnlyMutex.RLock()
+	defer fake.setReadOnlyMutex.RUnlock()
+	return len(fake.setReadOnlyArgsForCall)
+}
+
+func (fake *FakeDriver) SetReadOnlyCalls(stub func(volume.FilesystemVolume) error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = stub
+}
+
+func (fake *FakeDriver) SetReadOnlyArgsForCall(i int) volume.FilesystemVolume {
+	fake.setReadOnlyMutex.RLock()
+	defer fake.setReadOnlyMutex.RUnlock()
+	argsForCall := fake.setReadOnlyArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDriver) SetReadOnlyReturns(result1 error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = nil
+	fake.setReadOnlyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) SetReadOnlyReturnsOnCall(i int, result1 error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = nil
+	if fake.setReadOnlyReturnsOnCall == nil {
+		fake.setReadOnlyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setReadOnlyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDriver) SharesLayers() bool {
+	fake.sharesLayersMutex.Lock()
+	ret, specificReturn := fake.sharesLayersReturnsOnCall[len(fake.sharesLayersArgsForCall)]
+	fake.sharesLayersArgsForCall = append(fake.sharesLayersArgsForCall, struct {
+	}{})
+	stub := fake.SharesLayersStub
+	fakeReturns := fake.sharesLayersReturns
+	fake.recordInvocation("SharesLayers", []interface{}{})
+	fake.sharesLayersMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDriver) SharesLayersCallCount() int {
+	fake.sharesLayersMutex.RLock()
+	defer fake.sharesLayersMutex.RUnlock()
+	return len(fake.sharesLayersArgsForCall)
+}
+
+func (fake *FakeDriver) SharesLayersCalls(stub func() bool) {
+	fake.sharesLayersMutex.Lock()
+	defer fake.sharesLayersMutex.Unlock()
+	fake.SharesLayersStub = stub
+}
+
+func (fake *FakeDriver) SharesLayersReturns(result1 bool) {
+	fake.sharesLayersMutex.Lock()
+	defer fake.sharesLayersMutex.Unlock()
+	fake.SharesLayersStub = nil
+	fake.sharesLayersReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeDriver) SharesLayersReturnsOnCall(i int, result1 bool) {
+	fake.sharesLayersMutex.Lock()
+	defer fake.sharesLayersMutex.Unlock()
+	fake.SharesLayersStub = nil
+	if fake.sharesLayersReturnsOnCall == nil {
+		fake.sharesLayersReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.sharesLayersReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeDriver) Usage(arg1 volume.FilesystemVolume) (volume.VolumeUsage, error) {
+	fake.usageMutex.Lock()
+	ret, specificReturn := fake.usageReturnsOnCall[len(fake.usageArgsForCall)]
+	fake.usageArgsForCall = append(fake.usageArgsForCall, struct {
+		arg1 volume.FilesystemVolume
+	}{arg1})
+	stub := fake.UsageStub
+	fakeReturns := fake.usageReturns
+	fake.recordInvocation("Usage", []interface{}{arg1})
+	fake.usageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDriver) UsageCallCount() int {
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
+	return len(fake.usageArgsForCall)
+}
+
+func (fake *FakeDriver) UsageCalls(stub func(volume.FilesystemVolume) (volume.VolumeUsage, error)) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = stub
+}
+
+func (fake *FakeDriver) UsageArgsForCall(i int) volume.FilesystemVolume {
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
+	argsForCall := fake.usageArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDriver) UsageReturns(result1 volume.VolumeUsage, result2 error) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = nil
+	fake.usageReturns = struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDriver) UsageReturnsOnCall(i int, result1 volume.VolumeUsage, result2 error) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = nil
+	if fake.usageReturnsOnCall == nil {
+		fake.usageReturnsOnCall = make(map[int]struct {
+			result1 volume.VolumeUsage
+			result2 error
+		})
+	}
+	fake.usageReturnsOnCall[i] = struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeDriver) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
 	fake.createCopyOnWriteLayerMutex.RLock()
 	defer fake.createCopyOnWriteLayerMutex.RUnlock()
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
 	fake.createVolumeMutex.RLock()
 	defer fake.createVolumeMutex.RUnlock()
+	fake.destroySnapshotMutex.RLock()
+	defer fake.destroySnapshotMutex.RUnlock()
 	fake.destroyVolumeMutex.RLock()
 	defer fake.destroyVolumeMutex.RUnlock()
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
 	fake.recoverMutex.RLock()
 	defer fake.recoverMutex.RUnlock()
+	fake.renameVolumeMutex.RLock()
+	defer fake.renameVolumeMutex.RUnlock()
+	fake.repairMutex.RLock()
+	defer fake.repairMutex.RUnlock()
+	fake.restoreSnapshotMutex.RLock()
+	defer fake.restoreSnapshotMutex.RUnlock()
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	fake.setReadOnlyMutex.RLock()
+	defer fake.setReadOnlyMutex.RUnlock()
+	fake.sharesLayersMutex.RLock()
+	defer fake.sharesLayersMutex.RUnlock()
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
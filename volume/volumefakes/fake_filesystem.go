@@ -8,6 +8,30 @@ import (
 )
 
 type FakeFilesystem struct {
+	ListOrphansStub        func() ([]volume.FilesystemVolume, error)
+	listOrphansMutex       sync.RWMutex
+	listOrphansArgsForCall []struct {
+	}
+	listOrphansReturns struct {
+		result1 []volume.FilesystemVolume
+		result2 error
+	}
+	listOrphansReturnsOnCall map[int]struct {
+		result1 []volume.FilesystemVolume
+		result2 error
+	}
+	ListQuarantinedVolumesStub        func() ([]volume.FilesystemVolume, error)
+	listQuarantinedVolumesMutex       sync.RWMutex
+	listQuarantinedVolumesArgsForCall []struct {
+	}
+	listQuarantinedVolumesReturns struct {
+		result1 []volume.FilesystemVolume
+		result2 error
+	}
+	listQuarantinedVolumesReturnsOnCall map[int]struct {
+		result1 []volume.FilesystemVolume
+		result2 error
+	}
 	ListVolumesStub        func() ([]volume.FilesystemLiveVolume, error)
 	listVolumesMutex       sync.RWMutex
 	listVolumesArgsForCall []struct {
@@ -20,6 +44,21 @@ type FakeFilesystem struct {
 		result1 []volume.FilesystemLiveVolume
 		result2 error
 	}
+	LookupQuarantinedVolumeStub        func(string) (volume.FilesystemVolume, bool, error)
+	lookupQuarantinedVolumeMutex       sync.RWMutex
+	lookupQuarantinedVolumeArgsForCall []struct {
+		arg1 string
+	}
+	lookupQuarantinedVolumeReturns struct {
+		result1 volume.FilesystemVolume
+		result2 bool
+		result3 error
+	}
+	lookupQuarantinedVolumeReturnsOnCall map[int]struct {
+		result1 volume.FilesystemVolume
+		result2 bool
+		result3 error
+	}
 	LookupVolumeStub        func(string) (volume.FilesystemLiveVolume, bool, error)
 	lookupVolumeMutex       sync.RWMutex
 	lookupVolumeArgsForCall []struct {
@@ -48,24 +87,170 @@ type FakeFilesystem struct {
 		result1 volume.FilesystemInitVolume
 		result2 error
 	}
+	SharesLayersStub        func() bool
+	sharesLayersMutex       sync.RWMutex
+	sharesLayersArgsForCall []struct {
+	}
+	sharesLayersReturns struct {
+		result1 bool
+	}
+	sharesLayersReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	TempDirStub        func() string
+	tempDirMutex       sync.RWMutex
+	tempDirArgsForCall []struct {
+	}
+	tempDirReturns struct {
+		result1 string
+	}
+	tempDirReturnsOnCall map[int]struct {
+		result1 string
+	}
+	VolumesWithDigestStub        func(string) ([]volume.FilesystemLiveVolume, error)
+	volumesWithDigestMutex       sync.RWMutex
+	volumesWithDigestArgsForCall []struct {
+		arg1 string
+	}
+	volumesWithDigestReturns struct {
+		result1 []volume.FilesystemLiveVolume
+		result2 error
+	}
+	volumesWithDigestReturnsOnCall map[int]struct {
+		result1 []volume.FilesystemLiveVolume
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeFilesystem) ListOrphans() ([]volume.FilesystemVolume, error) {
+	fake.listOrphansMutex.Lock()
+	ret, specificReturn := fake.listOrphansReturnsOnCall[len(fake.listOrphansArgsForCall)]
+	fake.listOrphansArgsForCall = append(fake.listOrphansArgsForCall, struct {
+	}{})
+	stub := fake.ListOrphansStub
+	fakeReturns := fake.listOrphansReturns
+	fake.recordInvocation("ListOrphans", []interface{}{})
+	fake.listOrphansMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystem) ListOrphansCallCount() int {
+	fake.listOrphansMutex.RLock()
+	defer fake.listOrphansMutex.RUnlock()
+	return len(fake.listOrphansArgsForCall)
+}
+
+func (fake *FakeFilesystem) ListOrphansCalls(stub func() ([]volume.FilesystemVolume, error)) {
+	fake.listOrphansMutex.Lock()
+	defer fake.listOrphansMutex.Unlock()
+	fake.ListOrphansStub = stub
+}
+
+func (fake *FakeFilesystem) ListOrphansReturns(result1 []volume.FilesystemVolume, result2 error) {
+	fake.listOrphansMutex.Lock()
+	defer fake.listOrphansMutex.Unlock()
+	fake.ListOrphansStub = nil
+	fake.listOrphansReturns = struct {
+		result1 []volume.FilesystemVolume
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystem) ListOrphansReturnsOnCall(i int, result1 []volume.FilesystemVolume, result2 error) {
+	fake.listOrphansMutex.Lock()
+	defer fake.listOrphansMutex.Unlock()
+	fake.ListOrphansStub = nil
+	if fake.listOrphansReturnsOnCall == nil {
+		fake.listOrphansReturnsOnCall = make(map[int]struct {
+			result1 []volume.FilesystemVolume
+			result2 error
+		})
+	}
+	fake.listOrphansReturnsOnCall[i] = struct {
+		result1 []volume.FilesystemVolume
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystem) ListQuarantinedVolumes() ([]volume.FilesystemVolume, error) {
+	fake.listQuarantinedVolumesMutex.Lock()
+	ret, specificReturn := fake.listQuarantinedVolumesReturnsOnCall[len(fake.listQuarantinedVolumesArgsForCall)]
+	fake.listQuarantinedVolumesArgsForCall = append(fake.listQuarantinedVolumesArgsForCall, struct {
+	}{})
+	stub := fake.ListQuarantinedVolumesStub
+	fakeReturns := fake.listQuarantinedVolumesReturns
+	fake.recordInvocation("ListQuarantinedVolumes", []interface{}{})
+	fake.listQuarantinedVolumesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystem) ListQuarantinedVolumesCallCount() int {
+	fake.listQuarantinedVolumesMutex.RLock()
+	defer fake.listQuarantinedVolumesMutex.RUnlock()
+	return len(fake.listQuarantinedVolumesArgsForCall)
+}
+
+func (fake *FakeFilesystem) ListQuarantinedVolumesCalls(stub func() ([]volume.FilesystemVolume, error)) {
+	fake.listQuarantinedVolumesMutex.Lock()
+	defer fake.listQuarantinedVolumesMutex.Unlock()
+	fake.ListQuarantinedVolumesStub = stub
+}
+
+func (fake *FakeFilesystem) ListQuarantinedVolumesReturns(result1 []volume.FilesystemVolume, result2 error) {
+	fake.listQuarantinedVolumesMutex.Lock()
+	defer fake.listQuarantinedVolumesMutex.Unlock()
+	fake.ListQuarantinedVolumesStub = nil
+	fake.listQuarantinedVolumesReturns = struct {
+		result1 []volume.FilesystemVolume
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystem) ListQuarantinedVolumesReturnsOnCall(i int, result1 []volume.FilesystemVolume, result2 error) {
+	fake.listQuarantinedVolumesMutex.Lock()
+	defer fake.listQuarantinedVolumesMutex.Unlock()
+	fake.ListQuarantinedVolumesStub = nil
+	if fake.listQuarantinedVolumesReturnsOnCall == nil {
+		fake.listQuarantinedVolumesReturnsOnCall = make(map[int]struct {
+			result1 []volume.FilesystemVolume
+			result2 error
+		})
+	}
+	fake.listQuarantinedVolumesReturnsOnCall[i] = struct {
+		result1 []volume.FilesystemVolume
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilesystem) ListVolumes() ([]volume.FilesystemLiveVolume, error) {
 	fake.listVolumesMutex.Lock()
 	ret, specificReturn := fake.listVolumesReturnsOnCall[len(fake.listVolumesArgsForCall)]
 	fake.listVolumesArgsForCall = append(fake.listVolumesArgsForCall, struct {
 	}{})
+	stub := fake.ListVolumesStub
+	fakeReturns := fake.listVolumesReturns
 	fake.recordInvocation("ListVolumes", []interface{}{})
 	fake.listVolumesMutex.Unlock()
-	if fake.ListVolumesStub != nil {
-		return fake.ListVolumesStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.listVolumesReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -107,21 +292,89 @@ func (fake *FakeFilesystem) ListVolumesReturnsOnCall(i int, result1 []volume.Fil
 	}{result1, result2}
 }
 
+func (fake *FakeFilesystem) LookupQuarantinedVolume(arg1 string) (volume.FilesystemVolume, bool, error) {
+	fake.lookupQuarantinedVolumeMutex.Lock()
+	ret, specificReturn := fake.lookupQuarantinedVolumeReturnsOnCall[len(fake.lookupQuarantinedVolumeArgsForCall)]
+	fake.lookupQuarantinedVolumeArgsForCall = append(fake.lookupQuarantinedVolumeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.LookupQuarantinedVolumeStub
+	fakeReturns := fake.lookupQuarantinedVolumeReturns
+	fake.recordInvocation("LookupQuarantinedVolume", []interface{}{arg1})
+	fake.lookupQuarantinedVolumeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeFilesystem) LookupQuarantinedVolumeCallCount() int {
+	fake.lookupQuarantinedVolumeMutex.RLock()
+	defer fake.lookupQuarantinedVolumeMutex.RUnlock()
+	return len(fake.lookupQuarantinedVolumeArgsForCall)
+}
+
+func (fake *FakeFilesystem) LookupQuarantinedVolumeCalls(stub func(string) (volume.FilesystemVolume, bool, error)) {
+	fake.lookupQuarantinedVolumeMutex.Lock()
+	defer fake.lookupQuarantinedVolumeMutex.Unlock()
+	fake.LookupQuarantinedVolumeStub = stub
+}
+
+func (fake *FakeFilesystem) LookupQuarantinedVolumeArgsForCall(i int) string {
+	fake.lookupQuarantinedVolumeMutex.RLock()
+	defer fake.lookupQuarantinedVolumeMutex.RUnlock()
+	argsForCall := fake.lookupQuarantinedVolumeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystem) LookupQuarantinedVolumeReturns(result1 volume.FilesystemVolume, result2 bool, result3 error) {
+	fake.lookupQuarantinedVolumeMutex.Lock()
+	defer fake.lookupQuarantinedVolumeMutex.Unlock()
+	fake.LookupQuarantinedVolumeStub = nil
+	fake.lookupQuarantinedVolumeReturns = struct {
+		result1 volume.FilesystemVolume
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeFilesystem) LookupQuarantinedVolumeReturnsOnCall(i int, result1 volume.FilesystemVolume, result2 bool, result3 error) {
+	fake.lookupQuarantinedVolumeMutex.Lock()
+	defer fake.lookupQuarantinedVolumeMutex.Unlock()
+	fake.LookupQuarantinedVolumeStub = nil
+	if fake.lookupQuarantinedVolumeReturnsOnCall == nil {
+		fake.lookupQuarantinedVolumeReturnsOnCall = make(map[int]struct {
+			result1 volume.FilesystemVolume
+			result2 bool
+			result3 error
+		})
+	}
+	fake.lookupQuarantinedVolumeReturnsOnCall[i] = struct {
+		result1 volume.FilesystemVolume
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeFilesystem) LookupVolume(arg1 string) (volume.FilesystemLiveVolume, bool, error) {
 	fake.lookupVolumeMutex.Lock()
 	ret, specificReturn := fake.lookupVolumeReturnsOnCall[len(fake.lookupVolumeArgsForCall)]
 	fake.lookupVolumeArgsForCall = append(fake.lookupVolumeArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.LookupVolumeStub
+	fakeReturns := fake.lookupVolumeReturns
 	fake.recordInvocation("LookupVolume", []interface{}{arg1})
 	fake.lookupVolumeMutex.Unlock()
-	if fake.LookupVolumeStub != nil {
-		return fake.LookupVolumeStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.lookupVolumeReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -179,15 +432,16 @@ func (fake *FakeFilesystem) NewVolume(arg1 string) (volume.FilesystemInitVolume,
 	fake.newVolumeArgsForCall = append(fake.newVolumeArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.NewVolumeStub
+	fakeReturns := fake.newVolumeReturns
 	fake.recordInvocation("NewVolume", []interface{}{arg1})
 	fake.newVolumeMutex.Unlock()
-	if fake.NewVolumeStub != nil {
-		return fake.NewVolumeStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.newVolumeReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -236,15 +490,197 @@ func (fake *FakeFilesystem) NewVolumeReturnsOnCall(i int, result1 volume.Filesys
 	}{result1, result2}
 }
 
+func (fake *FakeFilesystem) SharesLayers() bool {
+	fake.sharesLayersMutex.Lock()
+	ret, specificReturn := fake.sharesLayersReturnsOnCall[len(fake.sharesLayersArgsForCall)]
+	fake.sharesLayersArgsForCall = append(fake.sharesLayersArgsForCall, struct {
+	}{})
+	stub := fake.SharesLayersStub
+	fakeReturns := fake.sharesLayersReturns
+	fake.recordInvocation("SharesLayers", []interface{}{})
+	fake.sharesLayersMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystem) SharesLayersCallCount() int {
+	fake.sharesLayersMutex.RLock()
+	defer fake.sharesLayersMutex.RUnlock()
+	return len(fake.sharesLayersArgsForCall)
+}
+
+func (fake *FakeFilesystem) SharesLayersCalls(stub func() bool) {
+	fake.sharesLayersMutex.Lock()
+	defer fake.sharesLayersMutex.Unlock()
+	fake.SharesLayersStub = stub
+}
+
+func (fake *FakeFilesystem) SharesLayersReturns(result1 bool) {
+	fake.sharesLayersMutex.Lock()
+	defer fake.sharesLayersMutex.Unlock()
+	fake.SharesLayersStub = nil
+	fake.sharesLayersReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeFilesystem) SharesLayersReturnsOnCall(i int, result1 bool) {
+	fake.sharesLayersMutex.Lock()
+	defer fake.sharesLayersMutex.Unlock()
+	fake.SharesLayersStub = nil
+	if fake.sharesLayersReturnsOnCall == nil {
+		fake.sharesLayersReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.sharesLayersReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeFilesystem) TempDir() string {
+	fake.tempDirMutex.Lock()
+	ret, specificReturn := fake.tempDirReturnsOnCall[len(fake.tempDirArgsForCall)]
+	fake.tempDirArgsForCall = append(fake.tempDirArgsForCall, struct {
+	}{})
+	stub := fake.TempDirStub
+	fakeReturns := fake.tempDirReturns
+	fake.recordInvocation("TempDir", []interface{}{})
+	fake.tempDirMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystem) TempDirCallCount() int {
+	fake.tempDirMutex.RLock()
+	defer fake.tempDirMutex.RUnlock()
+	return len(fake.tempDirArgsForCall)
+}
+
+func (fake *FakeFilesystem) TempDirCalls(stub func() string) {
+	fake.tempDirMutex.Lock()
+	defer fake.tempDirMutex.Unlock()
+	fake.TempDirStub = stub
+}
+
+func (fake *FakeFilesystem) TempDirReturns(result1 string) {
+	fake.tempDirMutex.Lock()
+	defer fake.tempDirMutex.Unlock()
+	fake.TempDirStub = nil
+	fake.tempDirReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeFilesystem) TempDirReturnsOnCall(i int, result1 string) {
+	fake.tempDirMutex.Lock()
+	defer fake.tempDirMutex.Unlock()
+	fake.TempDirStub = nil
+	if fake.tempDirReturnsOnCall == nil {
+		fake.tempDirReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.tempDirReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeFilesystem) VolumesWithDigest(arg1 string) ([]volume.FilesystemLiveVolume, error) {
+	fake.volumesWithDigestMutex.Lock()
+	ret, specificReturn := fake.volumesWithDigestReturnsOnCall[len(fake.volumesWithDigestArgsForCall)]
+	fake.volumesWithDigestArgsForCall = append(fake.volumesWithDigestArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.VolumesWithDigestStub
+	fakeReturns := fake.volumesWithDigestReturns
+	fake.recordInvocation("VolumesWithDigest", []interface{}{arg1})
+	fake.volumesWithDigestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystem) VolumesWithDigestCallCount() int {
+	fake.volumesWithDigestMutex.RLock()
+	defer fake.volumesWithDigestMutex.RUnlock()
+	return len(fake.volumesWithDigestArgsForCall)
+}
+
+func (fake *FakeFilesystem) VolumesWithDigestCalls(stub func(string) ([]volume.FilesystemLiveVolume, error)) {
+	fake.volumesWithDigestMutex.Lock()
+	defer fake.volumesWithDigestMutex.Unlock()
+	fake.VolumesWithDigestStub = stub
+}
+
+func (fake *FakeFilesystem) VolumesWithDigestArgsForCall(i int) string {
+	fake.volumesWithDigestMutex.RLock()
+	defer fake.volumesWithDigestMutex.RUnlock()
+	argsForCall := fake.volumesWithDigestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystem) VolumesWithDigestReturns(result1 []volume.FilesystemLiveVolume, result2 error) {
+	fake.volumesWithDigestMutex.Lock()
+	defer fake.volumesWithDigestMutex.Unlock()
+	fake.VolumesWithDigestStub = nil
+	fake.volumesWithDigestReturns = struct {
+		result1 []volume.FilesystemLiveVolume
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystem) VolumesWithDigestReturnsOnCall(i int, result1 []volume.FilesystemLiveVolume, result2 error) {
+	fake.volumesWithDigestMutex.Lock()
+	defer fake.volumesWithDigestMutex.Unlock()
+	fake.VolumesWithDigestStub = nil
+	if fake.volumesWithDigestReturnsOnCall == nil {
+		fake.volumesWithDigestReturnsOnCall = make(map[int]struct {
+			result1 []volume.FilesystemLiveVolume
+			result2 error
+		})
+	}
+	fake.volumesWithDigestReturnsOnCall[i] = struct {
+		result1 []volume.FilesystemLiveVolume
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilesystem) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.listOrphansMutex.RLock()
+	defer fake.listOrphansMutex.RUnlock()
+	fake.listQuarantinedVolumesMutex.RLock()
+	defer fake.listQuarantinedVolumesMutex.RUnlock()
 	fake.listVolumesMutex.RLock()
 	defer fake.listVolumesMutex.RUnlock()
+	fake.lookupQuarantinedVolumeMutex.RLock()
+	defer fake.lookupQuarantinedVolumeMutex.RUnlock()
 	fake.lookupVolumeMutex.RLock()
 	defer fake.lookupVolumeMutex.RUnlock()
 	fake.newVolumeMutex.RLock()
 	defer fake.newVolumeMutex.RUnlock()
+	fake.sharesLayersMutex.RLock()
+	defer fake.sharesLayersMutex.RUnlock()
+	fake.tempDirMutex.RLock()
+	defer fake.tempDirMutex.RUnlock()
+	fake.volumesWithDigestMutex.RLock()
+	defer fake.volumesWithDigestMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
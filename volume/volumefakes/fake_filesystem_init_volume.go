@@ -3,6 +3,7 @@ package volumefakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/concourse/baggageclaim/volume"
 )
@@ -50,6 +51,54 @@ type FakeFilesystemInitVolume struct {
 		result1 volume.FilesystemLiveVolume
 		result2 error
 	}
+	LoadCreatedAtStub        func() (time.Time, error)
+	loadCreatedAtMutex       sync.RWMutex
+	loadCreatedAtArgsForCall []struct {
+	}
+	loadCreatedAtReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	loadCreatedAtReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
+	LoadDigestStub        func() (string, error)
+	loadDigestMutex       sync.RWMutex
+	loadDigestArgsForCall []struct {
+	}
+	loadDigestReturns struct {
+		result1 string
+		result2 error
+	}
+	loadDigestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	LoadExpiresAtStub        func() (time.Time, error)
+	loadExpiresAtMutex       sync.RWMutex
+	loadExpiresAtArgsForCall []struct {
+	}
+	loadExpiresAtReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	loadExpiresAtReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
+	LoadLayerStub        func() (string, error)
+	loadLayerMutex       sync.RWMutex
+	loadLayerArgsForCall []struct {
+	}
+	loadLayerReturns struct {
+		result1 string
+		result2 error
+	}
+	loadLayerReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	LoadPrivilegedStub        func() (bool, error)
 	loadPrivilegedMutex       sync.RWMutex
 	loadPrivilegedArgsForCall []struct {
@@ -74,6 +123,30 @@ type FakeFilesystemInitVolume struct {
 		result1 volume.Properties
 		result2 error
 	}
+	LoadQuotaStub        func() (uint64, error)
+	loadQuotaMutex       sync.RWMutex
+	loadQuotaArgsForCall []struct {
+	}
+	loadQuotaReturns struct {
+		result1 uint64
+		result2 error
+	}
+	loadQuotaReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
+	LoadReadOnlyStub        func() (bool, error)
+	loadReadOnlyMutex       sync.RWMutex
+	loadReadOnlyArgsForCall []struct {
+	}
+	loadReadOnlyReturns struct {
+		result1 bool
+		result2 error
+	}
+	loadReadOnlyReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	ParentStub        func() (volume.FilesystemLiveVolume, bool, error)
 	parentMutex       sync.RWMutex
 	parentArgsForCall []struct {
@@ -88,6 +161,39 @@ type FakeFilesystemInitVolume struct {
 		result2 bool
 		result3 error
 	}
+	SetQuotaStub        func(uint64) error
+	setQuotaMutex       sync.RWMutex
+	setQuotaArgsForCall []struct {
+		arg1 uint64
+	}
+	setQuotaReturns struct {
+		result1 error
+	}
+	setQuotaReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreExpiresAtStub        func(time.Time) error
+	storeExpiresAtMutex       sync.RWMutex
+	storeExpiresAtArgsForCall []struct {
+		arg1 time.Time
+	}
+	storeExpiresAtReturns struct {
+		result1 error
+	}
+	storeExpiresAtReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreLayerStub        func(string) error
+	storeLayerMutex       sync.RWMutex
+	storeLayerArgsForCall []struct {
+		arg1 string
+	}
+	storeLayerReturns struct {
+		result1 error
+	}
+	storeLayerReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StorePrivilegedStub        func(bool) error
 	storePrivilegedMutex       sync.RWMutex
 	storePrivilegedArgsForCall []struct {
@@ -110,6 +216,18 @@ type FakeFilesystemInitVolume struct {
 	storePropertiesReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UsageStub        func() (volume.VolumeUsage, error)
+	usageMutex       sync.RWMutex
+	usageArgsForCall []struct {
+	}
+	usageReturns struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}
+	usageReturnsOnCall map[int]struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -119,15 +237,16 @@ func (fake *FakeFilesystemInitVolume) DataPath() string {
 	ret, specificReturn := fake.dataPathReturnsOnCall[len(fake.dataPathArgsForCall)]
 	fake.dataPathArgsForCall = append(fake.dataPathArgsForCall, struct {
 	}{})
+	stub := fake.DataPathStub
+	fakeReturns := fake.dataPathReturns
 	fake.recordInvocation("DataPath", []interface{}{})
 	fake.dataPathMutex.Unlock()
-	if fake.DataPathStub != nil {
-		return fake.DataPathStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.dataPathReturns
 	return fakeReturns.result1
 }
 
@@ -171,15 +290,16 @@ func (fake *FakeFilesystemInitVolume) Destroy() error {
 	ret, specificReturn := fake.destroyReturnsOnCall[len(fake.destroyArgsForCall)]
 	fake.destroyArgsForCall = append(fake.destroyArgsForCall, struct {
 	}{})
+	stub := fake.DestroyStub
+	fakeReturns := fake.destroyReturns
 	fake.recordInvocation("Destroy", []interface{}{})
 	fake.destroyMutex.Unlock()
-	if fake.DestroyStub != nil {
-		return fake.DestroyStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.destroyReturns
 	return fakeReturns.result1
 }
 
@@ -223,15 +343,16 @@ func (fake *FakeFilesystemInitVolume) Handle() string {
 	ret, specificReturn := fake.handleReturnsOnCall[len(fake.handleArgsForCall)]
 	fake.handleArgsForCall = append(fake.handleArgsForCall, struct {
 	}{})
+	stub := fake.HandleStub
+	fakeReturns := fake.handleReturns
 	fake.recordInvocation("Handle", []interface{}{})
 	fake.handleMutex.Unlock()
-	if fake.HandleStub != nil {
-		return fake.HandleStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.handleReturns
 	return fakeReturns.result1
 }
 
@@ -275,15 +396,16 @@ func (fake *FakeFilesystemInitVolume) Initialize() (volume.FilesystemLiveVolume,
 	ret, specificReturn := fake.initializeReturnsOnCall[len(fake.initializeArgsForCall)]
 	fake.initializeArgsForCall = append(fake.initializeArgsForCall, struct {
 	}{})
+	stub := fake.InitializeStub
+	fakeReturns := fake.initializeReturns
 	fake.recordInvocation("Initialize", []interface{}{})
 	fake.initializeMutex.Unlock()
-	if fake.InitializeStub != nil {
-		return fake.InitializeStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.initializeReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -325,20 +447,245 @@ func (fake *FakeFilesystemInitVolume) InitializeReturnsOnCall(i int, result1 vol
 	}{result1, result2}
 }
 
+func (fake *FakeFilesystemInitVolume) LoadCreatedAt() (time.Time, error) {
+	fake.loadCreatedAtMutex.Lock()
+	ret, specificReturn := fake.loadCreatedAtReturnsOnCall[len(fake.loadCreatedAtArgsForCall)]
+	fake.loadCreatedAtArgsForCall = append(fake.loadCreatedAtArgsForCall, struct {
+	}{})
+	stub := fake.LoadCreatedAtStub
+	fakeReturns := fake.loadCreatedAtReturns
+	fake.recordInvocation("LoadCreatedAt", []interface{}{})
+	fake.loadCreatedAtMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemInitVolume) LoadCreatedAtCallCount() int {
+	fake.loadCreatedAtMutex.RLock()
+	defer fake.loadCreatedAtMutex.RUnlock()
+	return len(fake.loadCreatedAtArgsForCall)
+}
+
+func (fake *FakeFilesystemInitVolume) LoadCreatedAtCalls(stub func() (time.Time, error)) {
+	fake.loadCreatedAtMutex.Lock()
+	defer fake.loadCreatedAtMutex.Unlock()
+	fake.LoadCreatedAtStub = stub
+}
+
+func (fake *FakeFilesystemInitVolume) LoadCreatedAtReturns(result1 time.Time, result2 error) {
+	fake.loadCreatedAtMutex.Lock()
+	defer fake.loadCreatedAtMutex.Unlock()
+	fake.LoadCreatedAtStub = nil
+	fake.loadCreatedAtReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) LoadCreatedAtReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.loadCreatedAtMutex.Lock()
+	defer fake.loadCreatedAtMutex.Unlock()
+	fake.LoadCreatedAtStub = nil
+	if fake.loadCreatedAtReturnsOnCall == nil {
+		fake.loadCreatedAtReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+			result2 error
+		})
+	}
+	fake.loadCreatedAtReturnsOnCall[i] = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) LoadDigest() (string, error) {
+	fake.loadDigestMutex.Lock()
+	ret, specificReturn := fake.loadDigestReturnsOnCall[len(fake.loadDigestArgsForCall)]
+	fake.loadDigestArgsForCall = append(fake.loadDigestArgsForCall, struct {
+	}{})
+	stub := fake.LoadDigestStub
+	fakeReturns := fake.loadDigestReturns
+	fake.recordInvocation("LoadDigest", []interface{}{})
+	fake.loadDigestMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemInitVolume) LoadDigestCallCount() int {
+	fake.loadDigestMutex.RLock()
+	defer fake.loadDigestMutex.RUnlock()
+	return len(fake.loadDigestArgsForCall)
+}
+
+func (fake *FakeFilesystemInitVolume) LoadDigestCalls(stub func() (string, error)) {
+	fake.loadDigestMutex.Lock()
+	defer fake.loadDigestMutex.Unlock()
+	fake.LoadDigestStub = stub
+}
+
+func (fake *FakeFilesystemInitVolume) LoadDigestReturns(result1 string, result2 error) {
+	fake.loadDigestMutex.Lock()
+	defer fake.loadDigestMutex.Unlock()
+	fake.LoadDigestStub = nil
+	fake.loadDigestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) LoadDigestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.loadDigestMutex.Lock()
+	defer fake.loadDigestMutex.Unlock()
+	fake.LoadDigestStub = nil
+	if fake.loadDigestReturnsOnCall == nil {
+		fake.loadDigestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.loadDigestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) LoadExpiresAt() (time.Time, error) {
+	fake.loadExpiresAtMutex.Lock()
+	ret, specificReturn := fake.loadExpiresAtReturnsOnCall[len(fake.loadExpiresAtArgsForCall)]
+	fake.loadExpiresAtArgsForCall = append(fake.loadExpiresAtArgsForCall, struct {
+	}{})
+	stub := fake.LoadExpiresAtStub
+	fakeReturns := fake.loadExpiresAtReturns
+	fake.recordInvocation("LoadExpiresAt", []interface{}{})
+	fake.loadExpiresAtMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemInitVolume) LoadExpiresAtCallCount() int {
+	fake.loadExpiresAtMutex.RLock()
+	defer fake.loadExpiresAtMutex.RUnlock()
+	return len(fake.loadExpiresAtArgsForCall)
+}
+
+func (fake *FakeFilesystemInitVolume) LoadExpiresAtCalls(stub func() (time.Time, error)) {
+	fake.loadExpiresAtMutex.Lock()
+	defer fake.loadExpiresAtMutex.Unlock()
+	fake.LoadExpiresAtStub = stub
+}
+
+func (fake *FakeFilesystemInitVolume) LoadExpiresAtReturns(result1 time.Time, result2 error) {
+	fake.loadExpiresAtMutex.Lock()
+	defer fake.loadExpiresAtMutex.Unlock()
+	fake.LoadExpiresAtStub = nil
+	fake.loadExpiresAtReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) LoadExpiresAtReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.loadExpiresAtMutex.Lock()
+	defer fake.loadExpiresAtMutex.Unlock()
+	fake.LoadExpiresAtStub = nil
+	if fake.loadExpiresAtReturnsOnCall == nil {
+		fake.loadExpiresAtReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+			result2 error
+		})
+	}
+	fake.loadExpiresAtReturnsOnCall[i] = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) LoadLayer() (string, error) {
+	fake.loadLayerMutex.Lock()
+	ret, specificReturn := fake.loadLayerReturnsOnCall[len(fake.loadLayerArgsForCall)]
+	fake.loadLayerArgsForCall = append(fake.loadLayerArgsForCall, struct {
+	}{})
+	stub := fake.LoadLayerStub
+	fakeReturns := fake.loadLayerReturns
+	fake.recordInvocation("LoadLayer", []interface{}{})
+	fake.loadLayerMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemInitVolume) LoadLayerCallCount() int {
+	fake.loadLayerMutex.RLock()
+	defer fake.loadLayerMutex.RUnlock()
+	return len(fake.loadLayerArgsForCall)
+}
+
+func (fake *FakeFilesystemInitVolume) LoadLayerCalls(stub func() (string, error)) {
+	fake.loadLayerMutex.Lock()
+	defer fake.loadLayerMutex.Unlock()
+	fake.LoadLayerStub = stub
+}
+
+func (fake *FakeFilesystemInitVolume) LoadLayerReturns(result1 string, result2 error) {
+	fake.loadLayerMutex.Lock()
+	defer fake.loadLayerMutex.Unlock()
+	fake.LoadLayerStub = nil
+	fake.loadLayerReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) LoadLayerReturnsOnCall(i int, result1 string, result2 error) {
+	fake.loadLayerMutex.Lock()
+	defer fake.loadLayerMutex.Unlock()
+	fake.LoadLayerStub = nil
+	if fake.loadLayerReturnsOnCall == nil {
+		fake.loadLayerReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.loadLayerReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilesystemInitVolume) LoadPrivileged() (bool, error) {
 	fake.loadPrivilegedMutex.Lock()
 	ret, specificReturn := fake.loadPrivilegedReturnsOnCall[len(fake.loadPrivilegedArgsForCall)]
 	fake.loadPrivilegedArgsForCall = append(fake.loadPrivilegedArgsForCall, struct {
 	}{})
+	stub := fake.LoadPrivilegedStub
+	fakeReturns := fake.loadPrivilegedReturns
 	fake.recordInvocation("LoadPrivileged", []interface{}{})
 	fake.loadPrivilegedMutex.Unlock()
-	if fake.LoadPrivilegedStub != nil {
-		return fake.LoadPrivilegedStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.loadPrivilegedReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -385,15 +732,16 @@ func (fake *FakeFilesystemInitVolume) LoadProperties() (volume.Properties, error
 	ret, specificReturn := fake.loadPropertiesReturnsOnCall[len(fake.loadPropertiesArgsForCall)]
 	fake.loadPropertiesArgsForCall = append(fake.loadPropertiesArgsForCall, struct {
 	}{})
+	stub := fake.LoadPropertiesStub
+	fakeReturns := fake.loadPropertiesReturns
 	fake.recordInvocation("LoadProperties", []interface{}{})
 	fake.loadPropertiesMutex.Unlock()
-	if fake.LoadPropertiesStub != nil {
-		return fake.LoadPropertiesStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.loadPropertiesReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -435,20 +783,133 @@ func (fake *FakeFilesystemInitVolume) LoadPropertiesReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *FakeFilesystemInitVolume) LoadQuota() (uint64, error) {
+	fake.loadQuotaMutex.Lock()
+	ret, specificReturn := fake.loadQuotaReturnsOnCall[len(fake.loadQuotaArgsForCall)]
+	fake.loadQuotaArgsForCall = append(fake.loadQuotaArgsForCall, struct {
+	}{})
+	stub := fake.LoadQuotaStub
+	fakeReturns := fake.loadQuotaReturns
+	fake.recordInvocation("LoadQuota", []interface{}{})
+	fake.loadQuotaMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemInitVolume) LoadQuotaCallCount() int {
+	fake.loadQuotaMutex.RLock()
+	defer fake.loadQuotaMutex.RUnlock()
+	return len(fake.loadQuotaArgsForCall)
+}
+
+func (fake *FakeFilesystemInitVolume) LoadQuotaCalls(stub func() (uint64, error)) {
+	fake.loadQuotaMutex.Lock()
+	defer fake.loadQuotaMutex.Unlock()
+	fake.LoadQuotaStub = stub
+}
+
+func (fake *FakeFilesystemInitVolume) LoadQuotaReturns(result1 uint64, result2 error) {
+	fake.loadQuotaMutex.Lock()
+	defer fake.loadQuotaMutex.Unlock()
+	fake.LoadQuotaStub = nil
+	fake.loadQuotaReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) LoadQuotaReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.loadQuotaMutex.Lock()
+	defer fake.loadQuotaMutex.Unlock()
+	fake.LoadQuotaStub = nil
+	if fake.loadQuotaReturnsOnCall == nil {
+		fake.loadQuotaReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.loadQuotaReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) LoadReadOnly() (bool, error) {
+	fake.loadReadOnlyMutex.Lock()
+	ret, specificReturn := fake.loadReadOnlyReturnsOnCall[len(fake.loadReadOnlyArgsForCall)]
+	fake.loadReadOnlyArgsForCall = append(fake.loadReadOnlyArgsForCall, struct {
+	}{})
+	stub := fake.LoadReadOnlyStub
+	fakeReturns := fake.loadReadOnlyReturns
+	fake.recordInvocation("LoadReadOnly", []interface{}{})
+	fake.loadReadOnlyMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemInitVolume) LoadReadOnlyCallCount() int {
+	fake.loadReadOnlyMutex.RLock()
+	defer fake.loadReadOnlyMutex.RUnlock()
+	return len(fake.loadReadOnlyArgsForCall)
+}
+
+func (fake *FakeFilesystemInitVolume) LoadReadOnlyCalls(stub func() (bool, error)) {
+	fake.loadReadOnlyMutex.Lock()
+	defer fake.loadReadOnlyMutex.Unlock()
+	fake.LoadReadOnlyStub = stub
+}
+
+func (fake *FakeFilesystemInitVolume) LoadReadOnlyReturns(result1 bool, result2 error) {
+	fake.loadReadOnlyMutex.Lock()
+	defer fake.loadReadOnlyMutex.Unlock()
+	fake.LoadReadOnlyStub = nil
+	fake.loadReadOnlyReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) LoadReadOnlyReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.loadReadOnlyMutex.Lock()
+	defer fake.loadReadOnlyMutex.Unlock()
+	fake.LoadReadOnlyStub = nil
+	if fake.loadReadOnlyReturnsOnCall == nil {
+		fake.loadReadOnlyReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.loadReadOnlyReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilesystemInitVolume) Parent() (volume.FilesystemLiveVolume, bool, error) {
 	fake.parentMutex.Lock()
 	ret, specificReturn := fake.parentReturnsOnCall[len(fake.parentArgsForCall)]
 	fake.parentArgsForCall = append(fake.parentArgsForCall, struct {
 	}{})
+	stub := fake.ParentStub
+	fakeReturns := fake.parentReturns
 	fake.recordInvocation("Parent", []interface{}{})
 	fake.parentMutex.Unlock()
-	if fake.ParentStub != nil {
-		return fake.ParentStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.parentReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -493,21 +954,205 @@ func (fake *FakeFilesystemInitVolume) ParentReturnsOnCall(i int, result1 volume.
 	}{result1, result2, result3}
 }
 
+func (fake *FakeFilesystemInitVolume) SetQuota(arg1 uint64) error {
+	fake.setQuotaMutex.Lock()
+	ret, specificReturn := fake.setQuotaReturnsOnCall[len(fake.setQuotaArgsForCall)]
+	fake.setQuotaArgsForCall = append(fake.setQuotaArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	stub := fake.SetQuotaStub
+	fakeReturns := fake.setQuotaReturns
+	fake.recordInvocation("SetQuota", []interface{}{arg1})
+	fake.setQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemInitVolume) SetQuotaCallCount() int {
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	return len(fake.setQuotaArgsForCall)
+}
+
+func (fake *FakeFilesystemInitVolume) SetQuotaCalls(stub func(uint64) error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = stub
+}
+
+func (fake *FakeFilesystemInitVolume) SetQuotaArgsForCall(i int) uint64 {
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	argsForCall := fake.setQuotaArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemInitVolume) SetQuotaReturns(result1 error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = nil
+	fake.setQuotaReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemInitVolume) SetQuotaReturnsOnCall(i int, result1 error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = nil
+	if fake.setQuotaReturnsOnCall == nil {
+		fake.setQuotaReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setQuotaReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemInitVolume) StoreExpiresAt(arg1 time.Time) error {
+	fake.storeExpiresAtMutex.Lock()
+	ret, specificReturn := fake.storeExpiresAtReturnsOnCall[len(fake.storeExpiresAtArgsForCall)]
+	fake.storeExpiresAtArgsForCall = append(fake.storeExpiresAtArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	stub := fake.StoreExpiresAtStub
+	fakeReturns := fake.storeExpiresAtReturns
+	fake.recordInvocation("StoreExpiresAt", []interface{}{arg1})
+	fake.storeExpiresAtMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemInitVolume) StoreExpiresAtCallCount() int {
+	fake.storeExpiresAtMutex.RLock()
+	defer fake.storeExpiresAtMutex.RUnlock()
+	return len(fake.storeExpiresAtArgsForCall)
+}
+
+func (fake *FakeFilesystemInitVolume) StoreExpiresAtCalls(stub func(time.Time) error) {
+	fake.storeExpiresAtMutex.Lock()
+	defer fake.storeExpiresAtMutex.Unlock()
+	fake.StoreExpiresAtStub = stub
+}
+
+func (fake *FakeFilesystemInitVolume) StoreExpiresAtArgsForCall(i int) time.Time {
+	fake.storeExpiresAtMutex.RLock()
+	defer fake.storeExpiresAtMutex.RUnlock()
+	argsForCall := fake.storeExpiresAtArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemInitVolume) StoreExpiresAtReturns(result1 error) {
+	fake.storeExpiresAtMutex.Lock()
+	defer fake.storeExpiresAtMutex.Unlock()
+	fake.StoreExpiresAtStub = nil
+	fake.storeExpiresAtReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemInitVolume) StoreExpiresAtReturnsOnCall(i int, result1 error) {
+	fake.storeExpiresAtMutex.Lock()
+	defer fake.storeExpiresAtMutex.Unlock()
+	fake.StoreExpiresAtStub = nil
+	if fake.storeExpiresAtReturnsOnCall == nil {
+		fake.storeExpiresAtReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeExpiresAtReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemInitVolume) StoreLayer(arg1 string) error {
+	fake.storeLayerMutex.Lock()
+	ret, specificReturn := fake.storeLayerReturnsOnCall[len(fake.storeLayerArgsForCall)]
+	fake.storeLayerArgsForCall = append(fake.storeLayerArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.StoreLayerStub
+	fakeReturns := fake.storeLayerReturns
+	fake.recordInvocation("StoreLayer", []interface{}{arg1})
+	fake.storeLayerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemInitVolume) StoreLayerCallCount() int {
+	fake.storeLayerMutex.RLock()
+	defer fake.storeLayerMutex.RUnlock()
+	return len(fake.storeLayerArgsForCall)
+}
+
+func (fake *FakeFilesystemInitVolume) StoreLayerCalls(stub func(string) error) {
+	fake.storeLayerMutex.Lock()
+	defer fake.storeLayerMutex.Unlock()
+	fake.StoreLayerStub = stub
+}
+
+func (fake *FakeFilesystemInitVolume) StoreLayerArgsForCall(i int) string {
+	fake.storeLayerMutex.RLock()
+	defer fake.storeLayerMutex.RUnlock()
+	argsForCall := fake.storeLayerArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemInitVolume) StoreLayerReturns(result1 error) {
+	fake.storeLayerMutex.Lock()
+	defer fake.storeLayerMutex.Unlock()
+	fake.StoreLayerStub = nil
+	fake.storeLayerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemInitVolume) StoreLayerReturnsOnCall(i int, result1 error) {
+	fake.storeLayerMutex.Lock()
+	defer fake.storeLayerMutex.Unlock()
+	fake.StoreLayerStub = nil
+	if fake.storeLayerReturnsOnCall == nil {
+		fake.storeLayerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeLayerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeFilesystemInitVolume) StorePrivileged(arg1 bool) error {
 	fake.storePrivilegedMutex.Lock()
 	ret, specificReturn := fake.storePrivilegedReturnsOnCall[len(fake.storePrivilegedArgsForCall)]
 	fake.storePrivilegedArgsForCall = append(fake.storePrivilegedArgsForCall, struct {
 		arg1 bool
 	}{arg1})
+	stub := fake.StorePrivilegedStub
+	fakeReturns := fake.storePrivilegedReturns
 	fake.recordInvocation("StorePrivileged", []interface{}{arg1})
 	fake.storePrivilegedMutex.Unlock()
-	if fake.StorePrivilegedStub != nil {
-		return fake.StorePrivilegedStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.storePrivilegedReturns
 	return fakeReturns.result1
 }
 
@@ -559,15 +1204,16 @@ func (fake *FakeFilesystemInitVolume) StoreProperties(arg1 volume.Properties) er
 	fake.storePropertiesArgsForCall = append(fake.storePropertiesArgsForCall, struct {
 		arg1 volume.Properties
 	}{arg1})
+	stub := fake.StorePropertiesStub
+	fakeReturns := fake.storePropertiesReturns
 	fake.recordInvocation("StoreProperties", []interface{}{arg1})
 	fake.storePropertiesMutex.Unlock()
-	if fake.StorePropertiesStub != nil {
-		return fake.StorePropertiesStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.storePropertiesReturns
 	return fakeReturns.result1
 }
 
@@ -613,6 +1259,62 @@ func (fake *FakeFilesystemInitVolume) StorePropertiesReturnsOnCall(i int, result
 	}{result1}
 }
 
+func (fake *FakeFilesystemInitVolume) Usage() (volume.VolumeUsage, error) {
+	fake.usageMutex.Lock()
+	ret, specificReturn := fake.usageReturnsOnCall[len(fake.usageArgsForCall)]
+	fake.usageArgsForCall = append(fake.usageArgsForCall, struct {
+	}{})
+	stub := fake.UsageStub
+	fakeReturns := fake.usageReturns
+	fake.recordInvocation("Usage", []interface{}{})
+	fake.usageMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemInitVolume) UsageCallCount() int {
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
+	return len(fake.usageArgsForCall)
+}
+
+func (fake *FakeFilesystemInitVolume) UsageCalls(stub func() (volume.VolumeUsage, error)) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = stub
+}
+
+func (fake *FakeFilesystemInitVolume) UsageReturns(result1 volume.VolumeUsage, result2 error) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = nil
+	fake.usageReturns = struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemInitVolume) UsageReturnsOnCall(i int, result1 volume.VolumeUsage, result2 error) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = nil
+	if fake.usageReturnsOnCall == nil {
+		fake.usageReturnsOnCall = make(map[int]struct {
+			result1 volume.VolumeUsage
+			result2 error
+		})
+	}
+	fake.usageReturnsOnCall[i] = struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilesystemInitVolume) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -624,16 +1326,36 @@ func (fake *FakeFilesystemInitVolume) Invocations() map[string][][]interface{} {
 	defer fake.handleMutex.RUnlock()
 	fake.initializeMutex.RLock()
 	defer fake.initializeMutex.RUnlock()
+	fake.loadCreatedAtMutex.RLock()
+	defer fake.loadCreatedAtMutex.RUnlock()
+	fake.loadDigestMutex.RLock()
+	defer fake.loadDigestMutex.RUnlock()
+	fake.loadExpiresAtMutex.RLock()
+	defer fake.loadExpiresAtMutex.RUnlock()
+	fake.loadLayerMutex.RLock()
+	defer fake.loadLayerMutex.RUnlock()
 	fake.loadPrivilegedMutex.RLock()
 	defer fake.loadPrivilegedMutex.RUnlock()
 	fake.loadPropertiesMutex.RLock()
 	defer fake.loadPropertiesMutex.RUnlock()
+	fake.loadQuotaMutex.RLock()
+	defer fake.loadQuotaMutex.RUnlock()
+	fake.loadReadOnlyMutex.RLock()
+	defer fake.loadReadOnlyMutex.RUnlock()
 	fake.parentMutex.RLock()
 	defer fake.parentMutex.RUnlock()
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	fake.storeExpiresAtMutex.RLock()
+	defer fake.storeExpiresAtMutex.RUnlock()
+	fake.storeLayerMutex.RLock()
+	defer fake.storeLayerMutex.RUnlock()
 	fake.storePrivilegedMutex.RLock()
 	defer fake.storePrivilegedMutex.RUnlock()
 	fake.storePropertiesMutex.RLock()
 	defer fake.storePropertiesMutex.RUnlock()
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -3,11 +3,22 @@ package volumefakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/concourse/baggageclaim/volume"
 )
 
 type FakeFilesystemLiveVolume struct {
+	CheckStub        func() []string
+	checkMutex       sync.RWMutex
+	checkArgsForCall []struct {
+	}
+	checkReturns struct {
+		result1 []string
+	}
+	checkReturnsOnCall map[int]struct {
+		result1 []string
+	}
 	DataPathStub        func() string
 	dataPathMutex       sync.RWMutex
 	dataPathArgsForCall []struct {
@@ -28,6 +39,18 @@ type FakeFilesystemLiveVolume struct {
 	destroyReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DiffStub        func() ([]volume.Change, error)
+	diffMutex       sync.RWMutex
+	diffArgsForCall []struct {
+	}
+	diffReturns struct {
+		result1 []volume.Change
+		result2 error
+	}
+	diffReturnsOnCall map[int]struct {
+		result1 []volume.Change
+		result2 error
+	}
 	HandleStub        func() string
 	handleMutex       sync.RWMutex
 	handleArgsForCall []struct {
@@ -38,6 +61,66 @@ type FakeFilesystemLiveVolume struct {
 	handleReturnsOnCall map[int]struct {
 		result1 string
 	}
+	ListSnapshotsStub        func() ([]volume.FilesystemSnapshot, error)
+	listSnapshotsMutex       sync.RWMutex
+	listSnapshotsArgsForCall []struct {
+	}
+	listSnapshotsReturns struct {
+		result1 []volume.FilesystemSnapshot
+		result2 error
+	}
+	listSnapshotsReturnsOnCall map[int]struct {
+		result1 []volume.FilesystemSnapshot
+		result2 error
+	}
+	LoadCreatedAtStub        func() (time.Time, error)
+	loadCreatedAtMutex       sync.RWMutex
+	loadCreatedAtArgsForCall []struct {
+	}
+	loadCreatedAtReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	loadCreatedAtReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
+	LoadDigestStub        func() (string, error)
+	loadDigestMutex       sync.RWMutex
+	loadDigestArgsForCall []struct {
+	}
+	loadDigestReturns struct {
+		result1 string
+		result2 error
+	}
+	loadDigestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	LoadExpiresAtStub        func() (time.Time, error)
+	loadExpiresAtMutex       sync.RWMutex
+	loadExpiresAtArgsForCall []struct {
+	}
+	loadExpiresAtReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	loadExpiresAtReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
+	LoadLayerStub        func() (string, error)
+	loadLayerMutex       sync.RWMutex
+	loadLayerArgsForCall []struct {
+	}
+	loadLayerReturns struct {
+		result1 string
+		result2 error
+	}
+	loadLayerReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	LoadPrivilegedStub        func() (bool, error)
 	loadPrivilegedMutex       sync.RWMutex
 	loadPrivilegedArgsForCall []struct {
@@ -62,6 +145,58 @@ type FakeFilesystemLiveVolume struct {
 		result1 volume.Properties
 		result2 error
 	}
+	LoadQuotaStub        func() (uint64, error)
+	loadQuotaMutex       sync.RWMutex
+	loadQuotaArgsForCall []struct {
+	}
+	loadQuotaReturns struct {
+		result1 uint64
+		result2 error
+	}
+	loadQuotaReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
+	LoadReadOnlyStub        func() (bool, error)
+	loadReadOnlyMutex       sync.RWMutex
+	loadReadOnlyArgsForCall []struct {
+	}
+	loadReadOnlyReturns struct {
+		result1 bool
+		result2 error
+	}
+	loadReadOnlyReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	LookupSnapshotStub        func(string) (volume.FilesystemSnapshot, bool, error)
+	lookupSnapshotMutex       sync.RWMutex
+	lookupSnapshotArgsForCall []struct {
+		arg1 string
+	}
+	lookupSnapshotReturns struct {
+		result1 volume.FilesystemSnapshot
+		result2 bool
+		result3 error
+	}
+	lookupSnapshotReturnsOnCall map[int]struct {
+		result1 volume.FilesystemSnapshot
+		result2 bool
+		result3 error
+	}
+	NewSnapshotStub        func(string) (volume.FilesystemSnapshot, error)
+	newSnapshotMutex       sync.RWMutex
+	newSnapshotArgsForCall []struct {
+		arg1 string
+	}
+	newSnapshotReturns struct {
+		result1 volume.FilesystemSnapshot
+		result2 error
+	}
+	newSnapshotReturnsOnCall map[int]struct {
+		result1 volume.FilesystemSnapshot
+		result2 error
+	}
 	NewSubvolumeStub        func(string) (volume.FilesystemInitVolume, error)
 	newSubvolumeMutex       sync.RWMutex
 	newSubvolumeArgsForCall []struct {
@@ -89,6 +224,104 @@ type FakeFilesystemLiveVolume struct {
 		result2 bool
 		result3 error
 	}
+	QuarantineStub        func() error
+	quarantineMutex       sync.RWMutex
+	quarantineArgsForCall []struct {
+	}
+	quarantineReturns struct {
+		result1 error
+	}
+	quarantineReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RenameStub        func(string) (volume.FilesystemLiveVolume, error)
+	renameMutex       sync.RWMutex
+	renameArgsForCall []struct {
+		arg1 string
+	}
+	renameReturns struct {
+		result1 volume.FilesystemLiveVolume
+		result2 error
+	}
+	renameReturnsOnCall map[int]struct {
+		result1 volume.FilesystemLiveVolume
+		result2 error
+	}
+	RepairStub        func() error
+	repairMutex       sync.RWMutex
+	repairArgsForCall []struct {
+	}
+	repairReturns struct {
+		result1 error
+	}
+	repairReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReparentStub        func(volume.FilesystemLiveVolume) error
+	reparentMutex       sync.RWMutex
+	reparentArgsForCall []struct {
+		arg1 volume.FilesystemLiveVolume
+	}
+	reparentReturns struct {
+		result1 error
+	}
+	reparentReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RestoreStub        func(volume.FilesystemSnapshot) error
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		arg1 volume.FilesystemSnapshot
+	}
+	restoreReturns struct {
+		result1 error
+	}
+	restoreReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetQuotaStub        func(uint64) error
+	setQuotaMutex       sync.RWMutex
+	setQuotaArgsForCall []struct {
+		arg1 uint64
+	}
+	setQuotaReturns struct {
+		result1 error
+	}
+	setQuotaReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetReadOnlyStub        func() error
+	setReadOnlyMutex       sync.RWMutex
+	setReadOnlyArgsForCall []struct {
+	}
+	setReadOnlyReturns struct {
+		result1 error
+	}
+	setReadOnlyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreDigestStub        func(string) error
+	storeDigestMutex       sync.RWMutex
+	storeDigestArgsForCall []struct {
+		arg1 string
+	}
+	storeDigestReturns struct {
+		result1 error
+	}
+	storeDigestReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreExpiresAtStub        func(time.Time) error
+	storeExpiresAtMutex       sync.RWMutex
+	storeExpiresAtArgsForCall []struct {
+		arg1 time.Time
+	}
+	storeExpiresAtReturns struct {
+		result1 error
+	}
+	storeExpiresAtReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StorePrivilegedStub        func(bool) error
 	storePrivilegedMutex       sync.RWMutex
 	storePrivilegedArgsForCall []struct {
@@ -111,24 +344,100 @@ type FakeFilesystemLiveVolume struct {
 	storePropertiesReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UploadsPathStub        func() string
+	uploadsPathMutex       sync.RWMutex
+	uploadsPathArgsForCall []struct {
+	}
+	uploadsPathReturns struct {
+		result1 string
+	}
+	uploadsPathReturnsOnCall map[int]struct {
+		result1 string
+	}
+	UsageStub        func() (volume.VolumeUsage, error)
+	usageMutex       sync.RWMutex
+	usageArgsForCall []struct {
+	}
+	usageReturns struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}
+	usageReturnsOnCall map[int]struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeFilesystemLiveVolume) Check() []string {
+	fake.checkMutex.Lock()
+	ret, specificReturn := fake.checkReturnsOnCall[len(fake.checkArgsForCall)]
+	fake.checkArgsForCall = append(fake.checkArgsForCall, struct {
+	}{})
+	stub := fake.CheckStub
+	fakeReturns := fake.checkReturns
+	fake.recordInvocation("Check", []interface{}{})
+	fake.checkMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemLiveVolume) CheckCallCount() int {
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
+	return len(fake.checkArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) CheckCalls(stub func() []string) {
+	fake.checkMutex.Lock()
+	defer fake.checkMutex.Unlock()
+	fake.CheckStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) CheckReturns(result1 []string) {
+	fake.checkMutex.Lock()
+	defer fake.checkMutex.Unlock()
+	fake.CheckStub = nil
+	fake.checkReturns = struct {
+		result1 []string
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) CheckReturnsOnCall(i int, result1 []string) {
+	fake.checkMutex.Lock()
+	defer fake.checkMutex.Unlock()
+	fake.CheckStub = nil
+	if fake.checkReturnsOnCall == nil {
+		fake.checkReturnsOnCall = make(map[int]struct {
+			result1 []string
+		})
+	}
+	fake.checkReturnsOnCall[i] = struct {
+		result1 []string
+	}{result1}
+}
+
 func (fake *FakeFilesystemLiveVolume) DataPath() string {
 	fake.dataPathMutex.Lock()
 	ret, specificReturn := fake.dataPathReturnsOnCall[len(fake.dataPathArgsForCall)]
 	fake.dataPathArgsForCall = append(fake.dataPathArgsForCall, struct {
 	}{})
+	stub := fake.DataPathStub
+	fakeReturns := fake.dataPathReturns
 	fake.recordInvocation("DataPath", []interface{}{})
 	fake.dataPathMutex.Unlock()
-	if fake.DataPathStub != nil {
-		return fake.DataPathStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.dataPathReturns
 	return fakeReturns.result1
 }
 
@@ -172,15 +481,16 @@ func (fake *FakeFilesystemLiveVolume) Destroy() error {
 	ret, specificReturn := fake.destroyReturnsOnCall[len(fake.destroyArgsForCall)]
 	fake.destroyArgsForCall = append(fake.destroyArgsForCall, struct {
 	}{})
+	stub := fake.DestroyStub
+	fakeReturns := fake.destroyReturns
 	fake.recordInvocation("Destroy", []interface{}{})
 	fake.destroyMutex.Unlock()
-	if fake.DestroyStub != nil {
-		return fake.DestroyStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.destroyReturns
 	return fakeReturns.result1
 }
 
@@ -219,20 +529,77 @@ func (fake *FakeFilesystemLiveVolume) DestroyReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
+func (fake *FakeFilesystemLiveVolume) Diff() ([]volume.Change, error) {
+	fake.diffMutex.Lock()
+	ret, specificReturn := fake.diffReturnsOnCall[len(fake.diffArgsForCall)]
+	fake.diffArgsForCall = append(fake.diffArgsForCall, struct {
+	}{})
+	stub := fake.DiffStub
+	fakeReturns := fake.diffReturns
+	fake.recordInvocation("Diff", []interface{}{})
+	fake.diffMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) DiffCallCount() int {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	return len(fake.diffArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) DiffCalls(stub func() ([]volume.Change, error)) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) DiffReturns(result1 []volume.Change, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	fake.diffReturns = struct {
+		result1 []volume.Change
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) DiffReturnsOnCall(i int, result1 []volume.Change, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	if fake.diffReturnsOnCall == nil {
+		fake.diffReturnsOnCall = make(map[int]struct {
+			result1 []volume.Change
+			result2 error
+		})
+	}
+	fake.diffReturnsOnCall[i] = struct {
+		result1 []volume.Change
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilesystemLiveVolume) Handle() string {
 	fake.handleMutex.Lock()
 	ret, specificReturn := fake.handleReturnsOnCall[len(fake.handleArgsForCall)]
 	fake.handleArgsForCall = append(fake.handleArgsForCall, struct {
 	}{})
+	stub := fake.HandleStub
+	fakeReturns := fake.handleReturns
 	fake.recordInvocation("Handle", []interface{}{})
 	fake.handleMutex.Unlock()
-	if fake.HandleStub != nil {
-		return fake.HandleStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.handleReturns
 	return fakeReturns.result1
 }
 
@@ -271,151 +638,677 @@ func (fake *FakeFilesystemLiveVolume) HandleReturnsOnCall(i int, result1 string)
 	}{result1}
 }
 
-func (fake *FakeFilesystemLiveVolume) LoadPrivileged() (bool, error) {
-	fake.loadPrivilegedMutex.Lock()
-	ret, specificReturn := fake.loadPrivilegedReturnsOnCall[len(fake.loadPrivilegedArgsForCall)]
-	fake.loadPrivilegedArgsForCall = append(fake.loadPrivilegedArgsForCall, struct {
+func (fake *FakeFilesystemLiveVolume) ListSnapshots() ([]volume.FilesystemSnapshot, error) {
+	fake.listSnapshotsMutex.Lock()
+	ret, specificReturn := fake.listSnapshotsReturnsOnCall[len(fake.listSnapshotsArgsForCall)]
+	fake.listSnapshotsArgsForCall = append(fake.listSnapshotsArgsForCall, struct {
 	}{})
-	fake.recordInvocation("LoadPrivileged", []interface{}{})
-	fake.loadPrivilegedMutex.Unlock()
-	if fake.LoadPrivilegedStub != nil {
-		return fake.LoadPrivilegedStub()
+	stub := fake.ListSnapshotsStub
+	fakeReturns := fake.listSnapshotsReturns
+	fake.recordInvocation("ListSnapshots", []interface{}{})
+	fake.listSnapshotsMutex.Unlock()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.loadPrivilegedReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeFilesystemLiveVolume) LoadPrivilegedCallCount() int {
-	fake.loadPrivilegedMutex.RLock()
-	defer fake.loadPrivilegedMutex.RUnlock()
-	return len(fake.loadPrivilegedArgsForCall)
+func (fake *FakeFilesystemLiveVolume) ListSnapshotsCallCount() int {
+	fake.listSnapshotsMutex.RLock()
+	defer fake.listSnapshotsMutex.RUnlock()
+	return len(fake.listSnapshotsArgsForCall)
 }
 
-func (fake *FakeFilesystemLiveVolume) LoadPrivilegedCalls(stub func() (bool, error)) {
-	fake.loadPrivilegedMutex.Lock()
-	defer fake.loadPrivilegedMutex.Unlock()
-	fake.LoadPrivilegedStub = stub
+func (fake *FakeFilesystemLiveVolume) ListSnapshotsCalls(stub func() ([]volume.FilesystemSnapshot, error)) {
+	fake.listSnapshotsMutex.Lock()
+	defer fake.listSnapshotsMutex.Unlock()
+	fake.ListSnapshotsStub = stub
 }
 
-func (fake *FakeFilesystemLiveVolume) LoadPrivilegedReturns(result1 bool, result2 error) {
-	fake.loadPrivilegedMutex.Lock()
-	defer fake.loadPrivilegedMutex.Unlock()
-	fake.LoadPrivilegedStub = nil
-	fake.loadPrivilegedReturns = struct {
-		result1 bool
+func (fake *FakeFilesystemLiveVolume) ListSnapshotsReturns(result1 []volume.FilesystemSnapshot, result2 error) {
+	fake.listSnapshotsMutex.Lock()
+	defer fake.listSnapshotsMutex.Unlock()
+	fake.ListSnapshotsStub = nil
+	fake.listSnapshotsReturns = struct {
+		result1 []volume.FilesystemSnapshot
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeFilesystemLiveVolume) LoadPrivilegedReturnsOnCall(i int, result1 bool, result2 error) {
-	fake.loadPrivilegedMutex.Lock()
-	defer fake.loadPrivilegedMutex.Unlock()
-	fake.LoadPrivilegedStub = nil
-	if fake.loadPrivilegedReturnsOnCall == nil {
-		fake.loadPrivilegedReturnsOnCall = make(map[int]struct {
-			result1 bool
+func (fake *FakeFilesystemLiveVolume) ListSnapshotsReturnsOnCall(i int, result1 []volume.FilesystemSnapshot, result2 error) {
+	fake.listSnapshotsMutex.Lock()
+	defer fake.listSnapshotsMutex.Unlock()
+	fake.ListSnapshotsStub = nil
+	if fake.listSnapshotsReturnsOnCall == nil {
+		fake.listSnapshotsReturnsOnCall = make(map[int]struct {
+			result1 []volume.FilesystemSnapshot
 			result2 error
 		})
 	}
-	fake.loadPrivilegedReturnsOnCall[i] = struct {
-		result1 bool
+	fake.listSnapshotsReturnsOnCall[i] = struct {
+		result1 []volume.FilesystemSnapshot
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeFilesystemLiveVolume) LoadProperties() (volume.Properties, error) {
-	fake.loadPropertiesMutex.Lock()
-	ret, specificReturn := fake.loadPropertiesReturnsOnCall[len(fake.loadPropertiesArgsForCall)]
-	fake.loadPropertiesArgsForCall = append(fake.loadPropertiesArgsForCall, struct {
+func (fake *FakeFilesystemLiveVolume) LoadCreatedAt() (time.Time, error) {
+	fake.loadCreatedAtMutex.Lock()
+	ret, specificReturn := fake.loadCreatedAtReturnsOnCall[len(fake.loadCreatedAtArgsForCall)]
+	fake.loadCreatedAtArgsForCall = append(fake.loadCreatedAtArgsForCall, struct {
 	}{})
-	fake.recordInvocation("LoadProperties", []interface{}{})
-	fake.loadPropertiesMutex.Unlock()
-	if fake.LoadPropertiesStub != nil {
-		return fake.LoadPropertiesStub()
+	stub := fake.LoadCreatedAtStub
+	fakeReturns := fake.loadCreatedAtReturns
+	fake.recordInvocation("LoadCreatedAt", []interface{}{})
+	fake.loadCreatedAtMutex.Unlock()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.loadPropertiesReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeFilesystemLiveVolume) LoadPropertiesCallCount() int {
-	fake.loadPropertiesMutex.RLock()
-	defer fake.loadPropertiesMutex.RUnlock()
-	return len(fake.loadPropertiesArgsForCall)
+func (fake *FakeFilesystemLiveVolume) LoadCreatedAtCallCount() int {
+	fake.loadCreatedAtMutex.RLock()
+	defer fake.loadCreatedAtMutex.RUnlock()
+	return len(fake.loadCreatedAtArgsForCall)
 }
 
-func (fake *FakeFilesystemLiveVolume) LoadPropertiesCalls(stub func() (volume.Properties, error)) {
-	fake.loadPropertiesMutex.Lock()
-	defer fake.loadPropertiesMutex.Unlock()
-	fake.LoadPropertiesStub = stub
+func (fake *FakeFilesystemLiveVolume) LoadCreatedAtCalls(stub func() (time.Time, error)) {
+	fake.loadCreatedAtMutex.Lock()
+	defer fake.loadCreatedAtMutex.Unlock()
+	fake.LoadCreatedAtStub = stub
 }
 
-func (fake *FakeFilesystemLiveVolume) LoadPropertiesReturns(result1 volume.Properties, result2 error) {
-	fake.loadPropertiesMutex.Lock()
-	defer fake.loadPropertiesMutex.Unlock()
-	fake.LoadPropertiesStub = nil
-	fake.loadPropertiesReturns = struct {
-		result1 volume.Properties
+func (fake *FakeFilesystemLiveVolume) LoadCreatedAtReturns(result1 time.Time, result2 error) {
+	fake.loadCreatedAtMutex.Lock()
+	defer fake.loadCreatedAtMutex.Unlock()
+	fake.LoadCreatedAtStub = nil
+	fake.loadCreatedAtReturns = struct {
+		result1 time.Time
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeFilesystemLiveVolume) LoadPropertiesReturnsOnCall(i int, result1 volume.Properties, result2 error) {
-	fake.loadPropertiesMutex.Lock()
-	defer fake.loadPropertiesMutex.Unlock()
-	fake.LoadPropertiesStub = nil
-	if fake.loadPropertiesReturnsOnCall == nil {
-		fake.loadPropertiesReturnsOnCall = make(map[int]struct {
-			result1 volume.Properties
+func (fake *FakeFilesystemLiveVolume) LoadCreatedAtReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.loadCreatedAtMutex.Lock()
+	defer fake.loadCreatedAtMutex.Unlock()
+	fake.LoadCreatedAtStub = nil
+	if fake.loadCreatedAtReturnsOnCall == nil {
+		fake.loadCreatedAtReturnsOnCall = make(map[int]struct {
+			result1 time.Time
 			result2 error
 		})
 	}
-	fake.loadPropertiesReturnsOnCall[i] = struct {
-		result1 volume.Properties
+	fake.loadCreatedAtReturnsOnCall[i] = struct {
+		result1 time.Time
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeFilesystemLiveVolume) NewSubvolume(arg1 string) (volume.FilesystemInitVolume, error) {
-	fake.newSubvolumeMutex.Lock()
-	ret, specificReturn := fake.newSubvolumeReturnsOnCall[len(fake.newSubvolumeArgsForCall)]
-	fake.newSubvolumeArgsForCall = append(fake.newSubvolumeArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("NewSubvolume", []interface{}{arg1})
-	fake.newSubvolumeMutex.Unlock()
-	if fake.NewSubvolumeStub != nil {
-		return fake.NewSubvolumeStub(arg1)
+func (fake *FakeFilesystemLiveVolume) LoadDigest() (string, error) {
+	fake.loadDigestMutex.Lock()
+	ret, specificReturn := fake.loadDigestReturnsOnCall[len(fake.loadDigestArgsForCall)]
+	fake.loadDigestArgsForCall = append(fake.loadDigestArgsForCall, struct {
+	}{})
+	stub := fake.LoadDigestStub
+	fakeReturns := fake.loadDigestReturns
+	fake.recordInvocation("LoadDigest", []interface{}{})
+	fake.loadDigestMutex.Unlock()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.newSubvolumeReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeFilesystemLiveVolume) NewSubvolumeCallCount() int {
-	fake.newSubvolumeMutex.RLock()
-	defer fake.newSubvolumeMutex.RUnlock()
-	return len(fake.newSubvolumeArgsForCall)
-}
-
-func (fake *FakeFilesystemLiveVolume) NewSubvolumeCalls(stub func(string) (volume.FilesystemInitVolume, error)) {
-	fake.newSubvolumeMutex.Lock()
-	defer fake.newSubvolumeMutex.Unlock()
-	fake.NewSubvolumeStub = stub
+func (fake *FakeFilesystemLiveVolume) LoadDigestCallCount() int {
+	fake.loadDigestMutex.RLock()
+	defer fake.loadDigestMutex.RUnlock()
+	return len(fake.loadDigestArgsForCall)
 }
 
-func (fake *FakeFilesystemLiveVolume) NewSubvolumeArgsForCall(i int) string {
-	fake.newSubvolumeMutex.RLock()
-	defer fake.newSubvolumeMutex.RUnlock()
-	argsForCall := fake.newSubvolumeArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeFilesystemLiveVolume) LoadDigestCalls(stub func() (string, error)) {
+	fake.loadDigestMutex.Lock()
+	defer fake.loadDigestMutex.Unlock()
+	fake.LoadDigestStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadDigestReturns(result1 string, result2 error) {
+	fake.loadDigestMutex.Lock()
+	defer fake.loadDigestMutex.Unlock()
+	fake.LoadDigestStub = nil
+	fake.loadDigestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadDigestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.loadDigestMutex.Lock()
+	defer fake.loadDigestMutex.Unlock()
+	fake.LoadDigestStub = nil
+	if fake.loadDigestReturnsOnCall == nil {
+		fake.loadDigestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.loadDigestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadExpiresAt() (time.Time, error) {
+	fake.loadExpiresAtMutex.Lock()
+	ret, specificReturn := fake.loadExpiresAtReturnsOnCall[len(fake.loadExpiresAtArgsForCall)]
+	fake.loadExpiresAtArgsForCall = append(fake.loadExpiresAtArgsForCall, struct {
+	}{})
+	stub := fake.LoadExpiresAtStub
+	fakeReturns := fake.loadExpiresAtReturns
+	fake.recordInvocation("LoadExpiresAt", []interface{}{})
+	fake.loadExpiresAtMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadExpiresAtCallCount() int {
+	fake.loadExpiresAtMutex.RLock()
+	defer fake.loadExpiresAtMutex.RUnlock()
+	return len(fake.loadExpiresAtArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadExpiresAtCalls(stub func() (time.Time, error)) {
+	fake.loadExpiresAtMutex.Lock()
+	defer fake.loadExpiresAtMutex.Unlock()
+	fake.LoadExpiresAtStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadExpiresAtReturns(result1 time.Time, result2 error) {
+	fake.loadExpiresAtMutex.Lock()
+	defer fake.loadExpiresAtMutex.Unlock()
+	fake.LoadExpiresAtStub = nil
+	fake.loadExpiresAtReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadExpiresAtReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.loadExpiresAtMutex.Lock()
+	defer fake.loadExpiresAtMutex.Unlock()
+	fake.LoadExpiresAtStub = nil
+	if fake.loadExpiresAtReturnsOnCall == nil {
+		fake.loadExpiresAtReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+			result2 error
+		})
+	}
+	fake.loadExpiresAtReturnsOnCall[i] = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadLayer() (string, error) {
+	fake.loadLayerMutex.Lock()
+	ret, specificReturn := fake.loadLayerReturnsOnCall[len(fake.loadLayerArgsForCall)]
+	fake.loadLayerArgsForCall = append(fake.loadLayerArgsForCall, struct {
+	}{})
+	stub := fake.LoadLayerStub
+	fakeReturns := fake.loadLayerReturns
+	fake.recordInvocation("LoadLayer", []interface{}{})
+	fake.loadLayerMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadLayerCallCount() int {
+	fake.loadLayerMutex.RLock()
+	defer fake.loadLayerMutex.RUnlock()
+	return len(fake.loadLayerArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadLayerCalls(stub func() (string, error)) {
+	fake.loadLayerMutex.Lock()
+	defer fake.loadLayerMutex.Unlock()
+	fake.LoadLayerStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadLayerReturns(result1 string, result2 error) {
+	fake.loadLayerMutex.Lock()
+	defer fake.loadLayerMutex.Unlock()
+	fake.LoadLayerStub = nil
+	fake.loadLayerReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadLayerReturnsOnCall(i int, result1 string, result2 error) {
+	fake.loadLayerMutex.Lock()
+	defer fake.loadLayerMutex.Unlock()
+	fake.LoadLayerStub = nil
+	if fake.loadLayerReturnsOnCall == nil {
+		fake.loadLayerReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.loadLayerReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadPrivileged() (bool, error) {
+	fake.loadPrivilegedMutex.Lock()
+	ret, specificReturn := fake.loadPrivilegedReturnsOnCall[len(fake.loadPrivilegedArgsForCall)]
+	fake.loadPrivilegedArgsForCall = append(fake.loadPrivilegedArgsForCall, struct {
+	}{})
+	stub := fake.LoadPrivilegedStub
+	fakeReturns := fake.loadPrivilegedReturns
+	fake.recordInvocation("LoadPrivileged", []interface{}{})
+	fake.loadPrivilegedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadPrivilegedCallCount() int {
+	fake.loadPrivilegedMutex.RLock()
+	defer fake.loadPrivilegedMutex.RUnlock()
+	return len(fake.loadPrivilegedArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadPrivilegedCalls(stub func() (bool, error)) {
+	fake.loadPrivilegedMutex.Lock()
+	defer fake.loadPrivilegedMutex.Unlock()
+	fake.LoadPrivilegedStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadPrivilegedReturns(result1 bool, result2 error) {
+	fake.loadPrivilegedMutex.Lock()
+	defer fake.loadPrivilegedMutex.Unlock()
+	fake.LoadPrivilegedStub = nil
+	fake.loadPrivilegedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadPrivilegedReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.loadPrivilegedMutex.Lock()
+	defer fake.loadPrivilegedMutex.Unlock()
+	fake.LoadPrivilegedStub = nil
+	if fake.loadPrivilegedReturnsOnCall == nil {
+		fake.loadPrivilegedReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.loadPrivilegedReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadProperties() (volume.Properties, error) {
+	fake.loadPropertiesMutex.Lock()
+	ret, specificReturn := fake.loadPropertiesReturnsOnCall[len(fake.loadPropertiesArgsForCall)]
+	fake.loadPropertiesArgsForCall = append(fake.loadPropertiesArgsForCall, struct {
+	}{})
+	stub := fake.LoadPropertiesStub
+	fakeReturns := fake.loadPropertiesReturns
+	fake.recordInvocation("LoadProperties", []interface{}{})
+	fake.loadPropertiesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadPropertiesCallCount() int {
+	fake.loadPropertiesMutex.RLock()
+	defer fake.loadPropertiesMutex.RUnlock()
+	return len(fake.loadPropertiesArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadPropertiesCalls(stub func() (volume.Properties, error)) {
+	fake.loadPropertiesMutex.Lock()
+	defer fake.loadPropertiesMutex.Unlock()
+	fake.LoadPropertiesStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadPropertiesReturns(result1 volume.Properties, result2 error) {
+	fake.loadPropertiesMutex.Lock()
+	defer fake.loadPropertiesMutex.Unlock()
+	fake.LoadPropertiesStub = nil
+	fake.loadPropertiesReturns = struct {
+		result1 volume.Properties
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadPropertiesReturnsOnCall(i int, result1 volume.Properties, result2 error) {
+	fake.loadPropertiesMutex.Lock()
+	defer fake.loadPropertiesMutex.Unlock()
+	fake.LoadPropertiesStub = nil
+	if fake.loadPropertiesReturnsOnCall == nil {
+		fake.loadPropertiesReturnsOnCall = make(map[int]struct {
+			result1 volume.Properties
+			result2 error
+		})
+	}
+	fake.loadPropertiesReturnsOnCall[i] = struct {
+		result1 volume.Properties
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadQuota() (uint64, error) {
+	fake.loadQuotaMutex.Lock()
+	ret, specificReturn := fake.loadQuotaReturnsOnCall[len(fake.loadQuotaArgsForCall)]
+	fake.loadQuotaArgsForCall = append(fake.loadQuotaArgsForCall, struct {
+	}{})
+	stub := fake.LoadQuotaStub
+	fakeReturns := fake.loadQuotaReturns
+	fake.recordInvocation("LoadQuota", []interface{}{})
+	fake.loadQuotaMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadQuotaCallCount() int {
+	fake.loadQuotaMutex.RLock()
+	defer fake.loadQuotaMutex.RUnlock()
+	return len(fake.loadQuotaArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadQuotaCalls(stub func() (uint64, error)) {
+	fake.loadQuotaMutex.Lock()
+	defer fake.loadQuotaMutex.Unlock()
+	fake.LoadQuotaStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadQuotaReturns(result1 uint64, result2 error) {
+	fake.loadQuotaMutex.Lock()
+	defer fake.loadQuotaMutex.Unlock()
+	fake.LoadQuotaStub = nil
+	fake.loadQuotaReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadQuotaReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.loadQuotaMutex.Lock()
+	defer fake.loadQuotaMutex.Unlock()
+	fake.LoadQuotaStub = nil
+	if fake.loadQuotaReturnsOnCall == nil {
+		fake.loadQuotaReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.loadQuotaReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadReadOnly() (bool, error) {
+	fake.loadReadOnlyMutex.Lock()
+	ret, specificReturn := fake.loadReadOnlyReturnsOnCall[len(fake.loadReadOnlyArgsForCall)]
+	fake.loadReadOnlyArgsForCall = append(fake.loadReadOnlyArgsForCall, struct {
+	}{})
+	stub := fake.LoadReadOnlyStub
+	fakeReturns := fake.loadReadOnlyReturns
+	fake.recordInvocation("LoadReadOnly", []interface{}{})
+	fake.loadReadOnlyMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadReadOnlyCallCount() int {
+	fake.loadReadOnlyMutex.RLock()
+	defer fake.loadReadOnlyMutex.RUnlock()
+	return len(fake.loadReadOnlyArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadReadOnlyCalls(stub func() (bool, error)) {
+	fake.loadReadOnlyMutex.Lock()
+	defer fake.loadReadOnlyMutex.Unlock()
+	fake.LoadReadOnlyStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadReadOnlyReturns(result1 bool, result2 error) {
+	fake.loadReadOnlyMutex.Lock()
+	defer fake.loadReadOnlyMutex.Unlock()
+	fake.LoadReadOnlyStub = nil
+	fake.loadReadOnlyReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LoadReadOnlyReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.loadReadOnlyMutex.Lock()
+	defer fake.loadReadOnlyMutex.Unlock()
+	fake.LoadReadOnlyStub = nil
+	if fake.loadReadOnlyReturnsOnCall == nil {
+		fake.loadReadOnlyReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.loadReadOnlyReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) LookupSnapshot(arg1 string) (volume.FilesystemSnapshot, bool, error) {
+	fake.lookupSnapshotMutex.Lock()
+	ret, specificReturn := fake.lookupSnapshotReturnsOnCall[len(fake.lookupSnapshotArgsForCall)]
+	fake.lookupSnapshotArgsForCall = append(fake.lookupSnapshotArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.LookupSnapshotStub
+	fakeReturns := fake.lookupSnapshotReturns
+	fake.recordInvocation("LookupSnapshot", []interface{}{arg1})
+	fake.lookupSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeFilesystemLiveVolume) LookupSnapshotCallCount() int {
+	fake.lookupSnapshotMutex.RLock()
+	defer fake.lookupSnapshotMutex.RUnlock()
+	return len(fake.lookupSnapshotArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) LookupSnapshotCalls(stub func(string) (volume.FilesystemSnapshot, bool, error)) {
+	fake.lookupSnapshotMutex.Lock()
+	defer fake.lookupSnapshotMutex.Unlock()
+	fake.LookupSnapshotStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) LookupSnapshotArgsForCall(i int) string {
+	fake.lookupSnapshotMutex.RLock()
+	defer fake.lookupSnapshotMutex.RUnlock()
+	argsForCall := fake.lookupSnapshotArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemLiveVolume) LookupSnapshotReturns(result1 volume.FilesystemSnapshot, result2 bool, result3 error) {
+	fake.lookupSnapshotMutex.Lock()
+	defer fake.lookupSnapshotMutex.Unlock()
+	fake.LookupSnapshotStub = nil
+	fake.lookupSnapshotReturns = struct {
+		result1 volume.FilesystemSnapshot
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeFilesystemLiveVolume) LookupSnapshotReturnsOnCall(i int, result1 volume.FilesystemSnapshot, result2 bool, result3 error) {
+	fake.lookupSnapshotMutex.Lock()
+	defer fake.lookupSnapshotMutex.Unlock()
+	fake.LookupSnapshotStub = nil
+	if fake.lookupSnapshotReturnsOnCall == nil {
+		fake.lookupSnapshotReturnsOnCall = make(map[int]struct {
+			result1 volume.FilesystemSnapshot
+			result2 bool
+			result3 error
+		})
+	}
+	fake.lookupSnapshotReturnsOnCall[i] = struct {
+		result1 volume.FilesystemSnapshot
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeFilesystemLiveVolume) NewSnapshot(arg1 string) (volume.FilesystemSnapshot, error) {
+	fake.newSnapshotMutex.Lock()
+	ret, specificReturn := fake.newSnapshotReturnsOnCall[len(fake.newSnapshotArgsForCall)]
+	fake.newSnapshotArgsForCall = append(fake.newSnapshotArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.NewSnapshotStub
+	fakeReturns := fake.newSnapshotReturns
+	fake.recordInvocation("NewSnapshot", []interface{}{arg1})
+	fake.newSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) NewSnapshotCallCount() int {
+	fake.newSnapshotMutex.RLock()
+	defer fake.newSnapshotMutex.RUnlock()
+	return len(fake.newSnapshotArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) NewSnapshotCalls(stub func(string) (volume.FilesystemSnapshot, error)) {
+	fake.newSnapshotMutex.Lock()
+	defer fake.newSnapshotMutex.Unlock()
+	fake.NewSnapshotStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) NewSnapshotArgsForCall(i int) string {
+	fake.newSnapshotMutex.RLock()
+	defer fake.newSnapshotMutex.RUnlock()
+	argsForCall := fake.newSnapshotArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemLiveVolume) NewSnapshotReturns(result1 volume.FilesystemSnapshot, result2 error) {
+	fake.newSnapshotMutex.Lock()
+	defer fake.newSnapshotMutex.Unlock()
+	fake.NewSnapshotStub = nil
+	fake.newSnapshotReturns = struct {
+		result1 volume.FilesystemSnapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) NewSnapshotReturnsOnCall(i int, result1 volume.FilesystemSnapshot, result2 error) {
+	fake.newSnapshotMutex.Lock()
+	defer fake.newSnapshotMutex.Unlock()
+	fake.NewSnapshotStub = nil
+	if fake.newSnapshotReturnsOnCall == nil {
+		fake.newSnapshotReturnsOnCall = make(map[int]struct {
+			result1 volume.FilesystemSnapshot
+			result2 error
+		})
+	}
+	fake.newSnapshotReturnsOnCall[i] = struct {
+		result1 volume.FilesystemSnapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) NewSubvolume(arg1 string) (volume.FilesystemInitVolume, error) {
+	fake.newSubvolumeMutex.Lock()
+	ret, specificReturn := fake.newSubvolumeReturnsOnCall[len(fake.newSubvolumeArgsForCall)]
+	fake.newSubvolumeArgsForCall = append(fake.newSubvolumeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.NewSubvolumeStub
+	fakeReturns := fake.newSubvolumeReturns
+	fake.recordInvocation("NewSubvolume", []interface{}{arg1})
+	fake.newSubvolumeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) NewSubvolumeCallCount() int {
+	fake.newSubvolumeMutex.RLock()
+	defer fake.newSubvolumeMutex.RUnlock()
+	return len(fake.newSubvolumeArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) NewSubvolumeCalls(stub func(string) (volume.FilesystemInitVolume, error)) {
+	fake.newSubvolumeMutex.Lock()
+	defer fake.newSubvolumeMutex.Unlock()
+	fake.NewSubvolumeStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) NewSubvolumeArgsForCall(i int) string {
+	fake.newSubvolumeMutex.RLock()
+	defer fake.newSubvolumeMutex.RUnlock()
+	argsForCall := fake.newSubvolumeArgsForCall[i]
+	return argsForCall.arg1
 }
 
 func (fake *FakeFilesystemLiveVolume) NewSubvolumeReturns(result1 volume.FilesystemInitVolume, result2 error) {
@@ -438,68 +1331,597 @@ func (fake *FakeFilesystemLiveVolume) NewSubvolumeReturnsOnCall(i int, result1 v
 			result2 error
 		})
 	}
-	fake.newSubvolumeReturnsOnCall[i] = struct {
-		result1 volume.FilesystemInitVolume
+	fake.newSubvolumeReturnsOnCall[i] = struct {
+		result1 volume.FilesystemInitVolume
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) Parent() (volume.FilesystemLiveVolume, bool, error) {
+	fake.parentMutex.Lock()
+	ret, specificReturn := fake.parentReturnsOnCall[len(fake.parentArgsForCall)]
+	fake.parentArgsForCall = append(fake.parentArgsForCall, struct {
+	}{})
+	stub := fake.ParentStub
+	fakeReturns := fake.parentReturns
+	fake.recordInvocation("Parent", []interface{}{})
+	fake.parentMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeFilesystemLiveVolume) ParentCallCount() int {
+	fake.parentMutex.RLock()
+	defer fake.parentMutex.RUnlock()
+	return len(fake.parentArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) ParentCalls(stub func() (volume.FilesystemLiveVolume, bool, error)) {
+	fake.parentMutex.Lock()
+	defer fake.parentMutex.Unlock()
+	fake.ParentStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) ParentReturns(result1 volume.FilesystemLiveVolume, result2 bool, result3 error) {
+	fake.parentMutex.Lock()
+	defer fake.parentMutex.Unlock()
+	fake.ParentStub = nil
+	fake.parentReturns = struct {
+		result1 volume.FilesystemLiveVolume
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeFilesystemLiveVolume) ParentReturnsOnCall(i int, result1 volume.FilesystemLiveVolume, result2 bool, result3 error) {
+	fake.parentMutex.Lock()
+	defer fake.parentMutex.Unlock()
+	fake.ParentStub = nil
+	if fake.parentReturnsOnCall == nil {
+		fake.parentReturnsOnCall = make(map[int]struct {
+			result1 volume.FilesystemLiveVolume
+			result2 bool
+			result3 error
+		})
+	}
+	fake.parentReturnsOnCall[i] = struct {
+		result1 volume.FilesystemLiveVolume
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeFilesystemLiveVolume) Quarantine() error {
+	fake.quarantineMutex.Lock()
+	ret, specificReturn := fake.quarantineReturnsOnCall[len(fake.quarantineArgsForCall)]
+	fake.quarantineArgsForCall = append(fake.quarantineArgsForCall, struct {
+	}{})
+	stub := fake.QuarantineStub
+	fakeReturns := fake.quarantineReturns
+	fake.recordInvocation("Quarantine", []interface{}{})
+	fake.quarantineMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemLiveVolume) QuarantineCallCount() int {
+	fake.quarantineMutex.RLock()
+	defer fake.quarantineMutex.RUnlock()
+	return len(fake.quarantineArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) QuarantineCalls(stub func() error) {
+	fake.quarantineMutex.Lock()
+	defer fake.quarantineMutex.Unlock()
+	fake.QuarantineStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) QuarantineReturns(result1 error) {
+	fake.quarantineMutex.Lock()
+	defer fake.quarantineMutex.Unlock()
+	fake.QuarantineStub = nil
+	fake.quarantineReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) QuarantineReturnsOnCall(i int, result1 error) {
+	fake.quarantineMutex.Lock()
+	defer fake.quarantineMutex.Unlock()
+	fake.QuarantineStub = nil
+	if fake.quarantineReturnsOnCall == nil {
+		fake.quarantineReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.quarantineReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) Rename(arg1 string) (volume.FilesystemLiveVolume, error) {
+	fake.renameMutex.Lock()
+	ret, specificReturn := fake.renameReturnsOnCall[len(fake.renameArgsForCall)]
+	fake.renameArgsForCall = append(fake.renameArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RenameStub
+	fakeReturns := fake.renameReturns
+	fake.recordInvocation("Rename", []interface{}{arg1})
+	fake.renameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) RenameCallCount() int {
+	fake.renameMutex.RLock()
+	defer fake.renameMutex.RUnlock()
+	return len(fake.renameArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) RenameCalls(stub func(string) (volume.FilesystemLiveVolume, error)) {
+	fake.renameMutex.Lock()
+	defer fake.renameMutex.Unlock()
+	fake.RenameStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) RenameArgsForCall(i int) string {
+	fake.renameMutex.RLock()
+	defer fake.renameMutex.RUnlock()
+	argsForCall := fake.renameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemLiveVolume) RenameReturns(result1 volume.FilesystemLiveVolume, result2 error) {
+	fake.renameMutex.Lock()
+	defer fake.renameMutex.Unlock()
+	fake.RenameStub = nil
+	fake.renameReturns = struct {
+		result1 volume.FilesystemLiveVolume
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) RenameReturnsOnCall(i int, result1 volume.FilesystemLiveVolume, result2 error) {
+	fake.renameMutex.Lock()
+	defer fake.renameMutex.Unlock()
+	fake.RenameStub = nil
+	if fake.renameReturnsOnCall == nil {
+		fake.renameReturnsOnCall = make(map[int]struct {
+			result1 volume.FilesystemLiveVolume
+			result2 error
+		})
+	}
+	fake.renameReturnsOnCall[i] = struct {
+		result1 volume.FilesystemLiveVolume
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeFilesystemLiveVolume) Parent() (volume.FilesystemLiveVolume, bool, error) {
-	fake.parentMutex.Lock()
-	ret, specificReturn := fake.parentReturnsOnCall[len(fake.parentArgsForCall)]
-	fake.parentArgsForCall = append(fake.parentArgsForCall, struct {
+func (fake *FakeFilesystemLiveVolume) Repair() error {
+	fake.repairMutex.Lock()
+	ret, specificReturn := fake.repairReturnsOnCall[len(fake.repairArgsForCall)]
+	fake.repairArgsForCall = append(fake.repairArgsForCall, struct {
 	}{})
-	fake.recordInvocation("Parent", []interface{}{})
-	fake.parentMutex.Unlock()
-	if fake.ParentStub != nil {
-		return fake.ParentStub()
+	stub := fake.RepairStub
+	fakeReturns := fake.repairReturns
+	fake.recordInvocation("Repair", []interface{}{})
+	fake.repairMutex.Unlock()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
-		return ret.result1, ret.result2, ret.result3
+		return ret.result1
 	}
-	fakeReturns := fake.parentReturns
-	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+	return fakeReturns.result1
 }
 
-func (fake *FakeFilesystemLiveVolume) ParentCallCount() int {
-	fake.parentMutex.RLock()
-	defer fake.parentMutex.RUnlock()
-	return len(fake.parentArgsForCall)
+func (fake *FakeFilesystemLiveVolume) RepairCallCount() int {
+	fake.repairMutex.RLock()
+	defer fake.repairMutex.RUnlock()
+	return len(fake.repairArgsForCall)
 }
 
-func (fake *FakeFilesystemLiveVolume) ParentCalls(stub func() (volume.FilesystemLiveVolume, bool, error)) {
-	fake.parentMutex.Lock()
-	defer fake.parentMutex.Unlock()
-	fake.ParentStub = stub
+func (fake *FakeFilesystemLiveVolume) RepairCalls(stub func() error) {
+	fake.repairMutex.Lock()
+	defer fake.repairMutex.Unlock()
+	fake.RepairStub = stub
 }
 
-func (fake *FakeFilesystemLiveVolume) ParentReturns(result1 volume.FilesystemLiveVolume, result2 bool, result3 error) {
-	fake.parentMutex.Lock()
-	defer fake.parentMutex.Unlock()
-	fake.ParentStub = nil
-	fake.parentReturns = struct {
-		result1 volume.FilesystemLiveVolume
-		result2 bool
-		result3 error
-	}{result1, result2, result3}
+func (fake *FakeFilesystemLiveVolume) RepairReturns(result1 error) {
+	fake.repairMutex.Lock()
+	defer fake.repairMutex.Unlock()
+	fake.RepairStub = nil
+	fake.repairReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeFilesystemLiveVolume) ParentReturnsOnCall(i int, result1 volume.FilesystemLiveVolume, result2 bool, result3 error) {
-	fake.parentMutex.Lock()
-	defer fake.parentMutex.Unlock()
-	fake.ParentStub = nil
-	if fake.parentReturnsOnCall == nil {
-		fake.parentReturnsOnCall = make(map[int]struct {
-			result1 volume.FilesystemLiveVolume
-			result2 bool
-			result3 error
+func (fake *FakeFilesystemLiveVolume) RepairReturnsOnCall(i int, result1 error) {
+	fake.repairMutex.Lock()
+	defer fake.repairMutex.Unlock()
+	fake.RepairStub = nil
+	if fake.repairReturnsOnCall == nil {
+		fake.repairReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.parentReturnsOnCall[i] = struct {
-		result1 volume.FilesystemLiveVolume
-		result2 bool
-		result3 error
-	}{result1, result2, result3}
+	fake.repairReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) Reparent(arg1 volume.FilesystemLiveVolume) error {
+	fake.reparentMutex.Lock()
+	ret, specificReturn := fake.reparentReturnsOnCall[len(fake.reparentArgsForCall)]
+	fake.reparentArgsForCall = append(fake.reparentArgsForCall, struct {
+		arg1 volume.FilesystemLiveVolume
+	}{arg1})
+	stub := fake.ReparentStub
+	fakeReturns := fake.reparentReturns
+	fake.recordInvocation("Reparent", []interface{}{arg1})
+	fake.reparentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemLiveVolume) ReparentCallCount() int {
+	fake.reparentMutex.RLock()
+	defer fake.reparentMutex.RUnlock()
+	return len(fake.reparentArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) ReparentCalls(stub func(volume.FilesystemLiveVolume) error) {
+	fake.reparentMutex.Lock()
+	defer fake.reparentMutex.Unlock()
+	fake.ReparentStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) ReparentArgsForCall(i int) volume.FilesystemLiveVolume {
+	fake.reparentMutex.RLock()
+	defer fake.reparentMutex.RUnlock()
+	argsForCall := fake.reparentArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemLiveVolume) ReparentReturns(result1 error) {
+	fake.reparentMutex.Lock()
+	defer fake.reparentMutex.Unlock()
+	fake.ReparentStub = nil
+	fake.reparentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) ReparentReturnsOnCall(i int, result1 error) {
+	fake.reparentMutex.Lock()
+	defer fake.reparentMutex.Unlock()
+	fake.ReparentStub = nil
+	if fake.reparentReturnsOnCall == nil {
+		fake.reparentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.reparentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) Restore(arg1 volume.FilesystemSnapshot) error {
+	fake.restoreMutex.Lock()
+	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		arg1 volume.FilesystemSnapshot
+	}{arg1})
+	stub := fake.RestoreStub
+	fakeReturns := fake.restoreReturns
+	fake.recordInvocation("Restore", []interface{}{arg1})
+	fake.restoreMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemLiveVolume) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) RestoreCalls(stub func(volume.FilesystemSnapshot) error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) RestoreArgsForCall(i int) volume.FilesystemSnapshot {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	argsForCall := fake.restoreArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemLiveVolume) RestoreReturns(result1 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) RestoreReturnsOnCall(i int, result1 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	if fake.restoreReturnsOnCall == nil {
+		fake.restoreReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) SetQuota(arg1 uint64) error {
+	fake.setQuotaMutex.Lock()
+	ret, specificReturn := fake.setQuotaReturnsOnCall[len(fake.setQuotaArgsForCall)]
+	fake.setQuotaArgsForCall = append(fake.setQuotaArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	stub := fake.SetQuotaStub
+	fakeReturns := fake.setQuotaReturns
+	fake.recordInvocation("SetQuota", []interface{}{arg1})
+	fake.setQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemLiveVolume) SetQuotaCallCount() int {
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	return len(fake.setQuotaArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) SetQuotaCalls(stub func(uint64) error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) SetQuotaArgsForCall(i int) uint64 {
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	argsForCall := fake.setQuotaArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemLiveVolume) SetQuotaReturns(result1 error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = nil
+	fake.setQuotaReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) SetQuotaReturnsOnCall(i int, result1 error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = nil
+	if fake.setQuotaReturnsOnCall == nil {
+		fake.setQuotaReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setQuotaReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) SetReadOnly() error {
+	fake.setReadOnlyMutex.Lock()
+	ret, specificReturn := fake.setReadOnlyReturnsOnCall[len(fake.setReadOnlyArgsForCall)]
+	fake.setReadOnlyArgsForCall = append(fake.setReadOnlyArgsForCall, struct {
+	}{})
+	stub := fake.SetReadOnlyStub
+	fakeReturns := fake.setReadOnlyReturns
+	fake.recordInvocation("SetReadOnly", []interface{}{})
+	fake.setReadOnlyMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemLiveVolume) SetReadOnlyCallCount() int {
+	fake.setReadOnlyMutex.RLock()
+	defer fake.setReadOnlyMutex.RUnlock()
+	return len(fake.setReadOnlyArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) SetReadOnlyCalls(stub func() error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) SetReadOnlyReturns(result1 error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = nil
+	fake.setReadOnlyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) SetReadOnlyReturnsOnCall(i int, result1 error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = nil
+	if fake.setReadOnlyReturnsOnCall == nil {
+		fake.setReadOnlyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setReadOnlyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreDigest(arg1 string) error {
+	fake.storeDigestMutex.Lock()
+	ret, specificReturn := fake.storeDigestReturnsOnCall[len(fake.storeDigestArgsForCall)]
+	fake.storeDigestArgsForCall = append(fake.storeDigestArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.StoreDigestStub
+	fakeReturns := fake.storeDigestReturns
+	fake.recordInvocation("StoreDigest", []interface{}{arg1})
+	fake.storeDigestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreDigestCallCount() int {
+	fake.storeDigestMutex.RLock()
+	defer fake.storeDigestMutex.RUnlock()
+	return len(fake.storeDigestArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreDigestCalls(stub func(string) error) {
+	fake.storeDigestMutex.Lock()
+	defer fake.storeDigestMutex.Unlock()
+	fake.StoreDigestStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreDigestArgsForCall(i int) string {
+	fake.storeDigestMutex.RLock()
+	defer fake.storeDigestMutex.RUnlock()
+	argsForCall := fake.storeDigestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreDigestReturns(result1 error) {
+	fake.storeDigestMutex.Lock()
+	defer fake.storeDigestMutex.Unlock()
+	fake.StoreDigestStub = nil
+	fake.storeDigestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreDigestReturnsOnCall(i int, result1 error) {
+	fake.storeDigestMutex.Lock()
+	defer fake.storeDigestMutex.Unlock()
+	fake.StoreDigestStub = nil
+	if fake.storeDigestReturnsOnCall == nil {
+		fake.storeDigestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeDigestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreExpiresAt(arg1 time.Time) error {
+	fake.storeExpiresAtMutex.Lock()
+	ret, specificReturn := fake.storeExpiresAtReturnsOnCall[len(fake.storeExpiresAtArgsForCall)]
+	fake.storeExpiresAtArgsForCall = append(fake.storeExpiresAtArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	stub := fake.StoreExpiresAtStub
+	fakeReturns := fake.storeExpiresAtReturns
+	fake.recordInvocation("StoreExpiresAt", []interface{}{arg1})
+	fake.storeExpiresAtMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreExpiresAtCallCount() int {
+	fake.storeExpiresAtMutex.RLock()
+	defer fake.storeExpiresAtMutex.RUnlock()
+	return len(fake.storeExpiresAtArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreExpiresAtCalls(stub func(time.Time) error) {
+	fake.storeExpiresAtMutex.Lock()
+	defer fake.storeExpiresAtMutex.Unlock()
+	fake.StoreExpiresAtStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreExpiresAtArgsForCall(i int) time.Time {
+	fake.storeExpiresAtMutex.RLock()
+	defer fake.storeExpiresAtMutex.RUnlock()
+	argsForCall := fake.storeExpiresAtArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreExpiresAtReturns(result1 error) {
+	fake.storeExpiresAtMutex.Lock()
+	defer fake.storeExpiresAtMutex.Unlock()
+	fake.StoreExpiresAtStub = nil
+	fake.storeExpiresAtReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) StoreExpiresAtReturnsOnCall(i int, result1 error) {
+	fake.storeExpiresAtMutex.Lock()
+	defer fake.storeExpiresAtMutex.Unlock()
+	fake.StoreExpiresAtStub = nil
+	if fake.storeExpiresAtReturnsOnCall == nil {
+		fake.storeExpiresAtReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeExpiresAtReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
 func (fake *FakeFilesystemLiveVolume) StorePrivileged(arg1 bool) error {
@@ -508,15 +1930,16 @@ func (fake *FakeFilesystemLiveVolume) StorePrivileged(arg1 bool) error {
 	fake.storePrivilegedArgsForCall = append(fake.storePrivilegedArgsForCall, struct {
 		arg1 bool
 	}{arg1})
+	stub := fake.StorePrivilegedStub
+	fakeReturns := fake.storePrivilegedReturns
 	fake.recordInvocation("StorePrivileged", []interface{}{arg1})
 	fake.storePrivilegedMutex.Unlock()
-	if fake.StorePrivilegedStub != nil {
-		return fake.StorePrivilegedStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.storePrivilegedReturns
 	return fakeReturns.result1
 }
 
@@ -568,15 +1991,16 @@ func (fake *FakeFilesystemLiveVolume) StoreProperties(arg1 volume.Properties) er
 	fake.storePropertiesArgsForCall = append(fake.storePropertiesArgsForCall, struct {
 		arg1 volume.Properties
 	}{arg1})
+	stub := fake.StorePropertiesStub
+	fakeReturns := fake.storePropertiesReturns
 	fake.recordInvocation("StoreProperties", []interface{}{arg1})
 	fake.storePropertiesMutex.Unlock()
-	if fake.StorePropertiesStub != nil {
-		return fake.StorePropertiesStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.storePropertiesReturns
 	return fakeReturns.result1
 }
 
@@ -622,27 +2046,180 @@ func (fake *FakeFilesystemLiveVolume) StorePropertiesReturnsOnCall(i int, result
 	}{result1}
 }
 
+func (fake *FakeFilesystemLiveVolume) UploadsPath() string {
+	fake.uploadsPathMutex.Lock()
+	ret, specificReturn := fake.uploadsPathReturnsOnCall[len(fake.uploadsPathArgsForCall)]
+	fake.uploadsPathArgsForCall = append(fake.uploadsPathArgsForCall, struct {
+	}{})
+	stub := fake.UploadsPathStub
+	fakeReturns := fake.uploadsPathReturns
+	fake.recordInvocation("UploadsPath", []interface{}{})
+	fake.uploadsPathMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemLiveVolume) UploadsPathCallCount() int {
+	fake.uploadsPathMutex.RLock()
+	defer fake.uploadsPathMutex.RUnlock()
+	return len(fake.uploadsPathArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) UploadsPathCalls(stub func() string) {
+	fake.uploadsPathMutex.Lock()
+	defer fake.uploadsPathMutex.Unlock()
+	fake.UploadsPathStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) UploadsPathReturns(result1 string) {
+	fake.uploadsPathMutex.Lock()
+	defer fake.uploadsPathMutex.Unlock()
+	fake.UploadsPathStub = nil
+	fake.uploadsPathReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) UploadsPathReturnsOnCall(i int, result1 string) {
+	fake.uploadsPathMutex.Lock()
+	defer fake.uploadsPathMutex.Unlock()
+	fake.UploadsPathStub = nil
+	if fake.uploadsPathReturnsOnCall == nil {
+		fake.uploadsPathReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.uploadsPathReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeFilesystemLiveVolume) Usage() (volume.VolumeUsage, error) {
+	fake.usageMutex.Lock()
+	ret, specificReturn := fake.usageReturnsOnCall[len(fake.usageArgsForCall)]
+	fake.usageArgsForCall = append(fake.usageArgsForCall, struct {
+	}{})
+	stub := fake.UsageStub
+	fakeReturns := fake.usageReturns
+	fake.recordInvocation("Usage", []interface{}{})
+	fake.usageMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemLiveVolume) UsageCallCount() int {
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
+	return len(fake.usageArgsForCall)
+}
+
+func (fake *FakeFilesystemLiveVolume) UsageCalls(stub func() (volume.VolumeUsage, error)) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = stub
+}
+
+func (fake *FakeFilesystemLiveVolume) UsageReturns(result1 volume.VolumeUsage, result2 error) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = nil
+	fake.usageReturns = struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemLiveVolume) UsageReturnsOnCall(i int, result1 volume.VolumeUsage, result2 error) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = nil
+	if fake.usageReturnsOnCall == nil {
+		fake.usageReturnsOnCall = make(map[int]struct {
+			result1 volume.VolumeUsage
+			result2 error
+		})
+	}
+	fake.usageReturnsOnCall[i] = struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilesystemLiveVolume) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
 	fake.dataPathMutex.RLock()
 	defer fake.dataPathMutex.RUnlock()
 	fake.destroyMutex.RLock()
 	defer fake.destroyMutex.RUnlock()
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
 	fake.handleMutex.RLock()
 	defer fake.handleMutex.RUnlock()
+	fake.listSnapshotsMutex.RLock()
+	defer fake.listSnapshotsMutex.RUnlock()
+	fake.loadCreatedAtMutex.RLock()
+	defer fake.loadCreatedAtMutex.RUnlock()
+	fake.loadDigestMutex.RLock()
+	defer fake.loadDigestMutex.RUnlock()
+	fake.loadExpiresAtMutex.RLock()
+	defer fake.loadExpiresAtMutex.RUnlock()
+	fake.loadLayerMutex.RLock()
+	defer fake.loadLayerMutex.RUnlock()
 	fake.loadPrivilegedMutex.RLock()
 	defer fake.loadPrivilegedMutex.RUnlock()
 	fake.loadPropertiesMutex.RLock()
 	defer fake.loadPropertiesMutex.RUnlock()
+	fake.loadQuotaMutex.RLock()
+	defer fake.loadQuotaMutex.RUnlock()
+	fake.loadReadOnlyMutex.RLock()
+	defer fake.loadReadOnlyMutex.RUnlock()
+	fake.lookupSnapshotMutex.RLock()
+	defer fake.lookupSnapshotMutex.RUnlock()
+	fake.newSnapshotMutex.RLock()
+	defer fake.newSnapshotMutex.RUnlock()
 	fake.newSubvolumeMutex.RLock()
 	defer fake.newSubvolumeMutex.RUnlock()
 	fake.parentMutex.RLock()
 	defer fake.parentMutex.RUnlock()
+	fake.quarantineMutex.RLock()
+	defer fake.quarantineMutex.RUnlock()
+	fake.renameMutex.RLock()
+	defer fake.renameMutex.RUnlock()
+	fake.repairMutex.RLock()
+	defer fake.repairMutex.RUnlock()
+	fake.reparentMutex.RLock()
+	defer fake.reparentMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	fake.setReadOnlyMutex.RLock()
+	defer fake.setReadOnlyMutex.RUnlock()
+	fake.storeDigestMutex.RLock()
+	defer fake.storeDigestMutex.RUnlock()
+	fake.storeExpiresAtMutex.RLock()
+	defer fake.storeExpiresAtMutex.RUnlock()
 	fake.storePrivilegedMutex.RLock()
 	defer fake.storePrivilegedMutex.RUnlock()
 	fake.storePropertiesMutex.RLock()
 	defer fake.storePropertiesMutex.RUnlock()
+	fake.uploadsPathMutex.RLock()
+	defer fake.uploadsPathMutex.RUnlock()
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -3,6 +3,7 @@ package volumefakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/concourse/baggageclaim/volume"
 )
@@ -38,6 +39,54 @@ type FakeFilesystemVolume struct {
 	handleReturnsOnCall map[int]struct {
 		result1 string
 	}
+	LoadCreatedAtStub        func() (time.Time, error)
+	loadCreatedAtMutex       sync.RWMutex
+	loadCreatedAtArgsForCall []struct {
+	}
+	loadCreatedAtReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	loadCreatedAtReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
+	LoadDigestStub        func() (string, error)
+	loadDigestMutex       sync.RWMutex
+	loadDigestArgsForCall []struct {
+	}
+	loadDigestReturns struct {
+		result1 string
+		result2 error
+	}
+	loadDigestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	LoadExpiresAtStub        func() (time.Time, error)
+	loadExpiresAtMutex       sync.RWMutex
+	loadExpiresAtArgsForCall []struct {
+	}
+	loadExpiresAtReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	loadExpiresAtReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
+	LoadLayerStub        func() (string, error)
+	loadLayerMutex       sync.RWMutex
+	loadLayerArgsForCall []struct {
+	}
+	loadLayerReturns struct {
+		result1 string
+		result2 error
+	}
+	loadLayerReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	LoadPrivilegedStub        func() (bool, error)
 	loadPrivilegedMutex       sync.RWMutex
 	loadPrivilegedArgsForCall []struct {
@@ -62,6 +111,30 @@ type FakeFilesystemVolume struct {
 		result1 volume.Properties
 		result2 error
 	}
+	LoadQuotaStub        func() (uint64, error)
+	loadQuotaMutex       sync.RWMutex
+	loadQuotaArgsForCall []struct {
+	}
+	loadQuotaReturns struct {
+		result1 uint64
+		result2 error
+	}
+	loadQuotaReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
+	LoadReadOnlyStub        func() (bool, error)
+	loadReadOnlyMutex       sync.RWMutex
+	loadReadOnlyArgsForCall []struct {
+	}
+	loadReadOnlyReturns struct {
+		result1 bool
+		result2 error
+	}
+	loadReadOnlyReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	ParentStub        func() (volume.FilesystemLiveVolume, bool, error)
 	parentMutex       sync.RWMutex
 	parentArgsForCall []struct {
@@ -76,6 +149,28 @@ type FakeFilesystemVolume struct {
 		result2 bool
 		result3 error
 	}
+	SetQuotaStub        func(uint64) error
+	setQuotaMutex       sync.RWMutex
+	setQuotaArgsForCall []struct {
+		arg1 uint64
+	}
+	setQuotaReturns struct {
+		result1 error
+	}
+	setQuotaReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreExpiresAtStub        func(time.Time) error
+	storeExpiresAtMutex       sync.RWMutex
+	storeExpiresAtArgsForCall []struct {
+		arg1 time.Time
+	}
+	storeExpiresAtReturns struct {
+		result1 error
+	}
+	storeExpiresAtReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StorePrivilegedStub        func(bool) error
 	storePrivilegedMutex       sync.RWMutex
 	storePrivilegedArgsForCall []struct {
@@ -98,6 +193,18 @@ type FakeFilesystemVolume struct {
 	storePropertiesReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UsageStub        func() (volume.VolumeUsage, error)
+	usageMutex       sync.RWMutex
+	usageArgsForCall []struct {
+	}
+	usageReturns struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}
+	usageReturnsOnCall map[int]struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -107,15 +214,16 @@ func (fake *FakeFilesystemVolume) DataPath() string {
 	ret, specificReturn := fake.dataPathReturnsOnCall[len(fake.dataPathArgsForCall)]
 	fake.dataPathArgsForCall = append(fake.dataPathArgsForCall, struct {
 	}{})
+	stub := fake.DataPathStub
+	fakeReturns := fake.dataPathReturns
 	fake.recordInvocation("DataPath", []interface{}{})
 	fake.dataPathMutex.Unlock()
-	if fake.DataPathStub != nil {
-		return fake.DataPathStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.dataPathReturns
 	return fakeReturns.result1
 }
 
@@ -159,15 +267,16 @@ func (fake *FakeFilesystemVolume) Destroy() error {
 	ret, specificReturn := fake.destroyReturnsOnCall[len(fake.destroyArgsForCall)]
 	fake.destroyArgsForCall = append(fake.destroyArgsForCall, struct {
 	}{})
+	stub := fake.DestroyStub
+	fakeReturns := fake.destroyReturns
 	fake.recordInvocation("Destroy", []interface{}{})
 	fake.destroyMutex.Unlock()
-	if fake.DestroyStub != nil {
-		return fake.DestroyStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.destroyReturns
 	return fakeReturns.result1
 }
 
@@ -211,15 +320,16 @@ func (fake *FakeFilesystemVolume) Handle() string {
 	ret, specificReturn := fake.handleReturnsOnCall[len(fake.handleArgsForCall)]
 	fake.handleArgsForCall = append(fake.handleArgsForCall, struct {
 	}{})
+	stub := fake.HandleStub
+	fakeReturns := fake.handleReturns
 	fake.recordInvocation("Handle", []interface{}{})
 	fake.handleMutex.Unlock()
-	if fake.HandleStub != nil {
-		return fake.HandleStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.handleReturns
 	return fakeReturns.result1
 }
 
@@ -258,20 +368,245 @@ func (fake *FakeFilesystemVolume) HandleReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakeFilesystemVolume) LoadCreatedAt() (time.Time, error) {
+	fake.loadCreatedAtMutex.Lock()
+	ret, specificReturn := fake.loadCreatedAtReturnsOnCall[len(fake.loadCreatedAtArgsForCall)]
+	fake.loadCreatedAtArgsForCall = append(fake.loadCreatedAtArgsForCall, struct {
+	}{})
+	stub := fake.LoadCreatedAtStub
+	fakeReturns := fake.loadCreatedAtReturns
+	fake.recordInvocation("LoadCreatedAt", []interface{}{})
+	fake.loadCreatedAtMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemVolume) LoadCreatedAtCallCount() int {
+	fake.loadCreatedAtMutex.RLock()
+	defer fake.loadCreatedAtMutex.RUnlock()
+	return len(fake.loadCreatedAtArgsForCall)
+}
+
+func (fake *FakeFilesystemVolume) LoadCreatedAtCalls(stub func() (time.Time, error)) {
+	fake.loadCreatedAtMutex.Lock()
+	defer fake.loadCreatedAtMutex.Unlock()
+	fake.LoadCreatedAtStub = stub
+}
+
+func (fake *FakeFilesystemVolume) LoadCreatedAtReturns(result1 time.Time, result2 error) {
+	fake.loadCreatedAtMutex.Lock()
+	defer fake.loadCreatedAtMutex.Unlock()
+	fake.LoadCreatedAtStub = nil
+	fake.loadCreatedAtReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) LoadCreatedAtReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.loadCreatedAtMutex.Lock()
+	defer fake.loadCreatedAtMutex.Unlock()
+	fake.LoadCreatedAtStub = nil
+	if fake.loadCreatedAtReturnsOnCall == nil {
+		fake.loadCreatedAtReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+			result2 error
+		})
+	}
+	fake.loadCreatedAtReturnsOnCall[i] = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) LoadDigest() (string, error) {
+	fake.loadDigestMutex.Lock()
+	ret, specificReturn := fake.loadDigestReturnsOnCall[len(fake.loadDigestArgsForCall)]
+	fake.loadDigestArgsForCall = append(fake.loadDigestArgsForCall, struct {
+	}{})
+	stub := fake.LoadDigestStub
+	fakeReturns := fake.loadDigestReturns
+	fake.recordInvocation("LoadDigest", []interface{}{})
+	fake.loadDigestMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemVolume) LoadDigestCallCount() int {
+	fake.loadDigestMutex.RLock()
+	defer fake.loadDigestMutex.RUnlock()
+	return len(fake.loadDigestArgsForCall)
+}
+
+func (fake *FakeFilesystemVolume) LoadDigestCalls(stub func() (string, error)) {
+	fake.loadDigestMutex.Lock()
+	defer fake.loadDigestMutex.Unlock()
+	fake.LoadDigestStub = stub
+}
+
+func (fake *FakeFilesystemVolume) LoadDigestReturns(result1 string, result2 error) {
+	fake.loadDigestMutex.Lock()
+	defer fake.loadDigestMutex.Unlock()
+	fake.LoadDigestStub = nil
+	fake.loadDigestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) LoadDigestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.loadDigestMutex.Lock()
+	defer fake.loadDigestMutex.Unlock()
+	fake.LoadDigestStub = nil
+	if fake.loadDigestReturnsOnCall == nil {
+		fake.loadDigestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.loadDigestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) LoadExpiresAt() (time.Time, error) {
+	fake.loadExpiresAtMutex.Lock()
+	ret, specificReturn := fake.loadExpiresAtReturnsOnCall[len(fake.loadExpiresAtArgsForCall)]
+	fake.loadExpiresAtArgsForCall = append(fake.loadExpiresAtArgsForCall, struct {
+	}{})
+	stub := fake.LoadExpiresAtStub
+	fakeReturns := fake.loadExpiresAtReturns
+	fake.recordInvocation("LoadExpiresAt", []interface{}{})
+	fake.loadExpiresAtMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemVolume) LoadExpiresAtCallCount() int {
+	fake.loadExpiresAtMutex.RLock()
+	defer fake.loadExpiresAtMutex.RUnlock()
+	return len(fake.loadExpiresAtArgsForCall)
+}
+
+func (fake *FakeFilesystemVolume) LoadExpiresAtCalls(stub func() (time.Time, error)) {
+	fake.loadExpiresAtMutex.Lock()
+	defer fake.loadExpiresAtMutex.Unlock()
+	fake.LoadExpiresAtStub = stub
+}
+
+func (fake *FakeFilesystemVolume) LoadExpiresAtReturns(result1 time.Time, result2 error) {
+	fake.loadExpiresAtMutex.Lock()
+	defer fake.loadExpiresAtMutex.Unlock()
+	fake.LoadExpiresAtStub = nil
+	fake.loadExpiresAtReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) LoadExpiresAtReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.loadExpiresAtMutex.Lock()
+	defer fake.loadExpiresAtMutex.Unlock()
+	fake.LoadExpiresAtStub = nil
+	if fake.loadExpiresAtReturnsOnCall == nil {
+		fake.loadExpiresAtReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+			result2 error
+		})
+	}
+	fake.loadExpiresAtReturnsOnCall[i] = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) LoadLayer() (string, error) {
+	fake.loadLayerMutex.Lock()
+	ret, specificReturn := fake.loadLayerReturnsOnCall[len(fake.loadLayerArgsForCall)]
+	fake.loadLayerArgsForCall = append(fake.loadLayerArgsForCall, struct {
+	}{})
+	stub := fake.LoadLayerStub
+	fakeReturns := fake.loadLayerReturns
+	fake.recordInvocation("LoadLayer", []interface{}{})
+	fake.loadLayerMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemVolume) LoadLayerCallCount() int {
+	fake.loadLayerMutex.RLock()
+	defer fake.loadLayerMutex.RUnlock()
+	return len(fake.loadLayerArgsForCall)
+}
+
+func (fake *FakeFilesystemVolume) LoadLayerCalls(stub func() (string, error)) {
+	fake.loadLayerMutex.Lock()
+	defer fake.loadLayerMutex.Unlock()
+	fake.LoadLayerStub = stub
+}
+
+func (fake *FakeFilesystemVolume) LoadLayerReturns(result1 string, result2 error) {
+	fake.loadLayerMutex.Lock()
+	defer fake.loadLayerMutex.Unlock()
+	fake.LoadLayerStub = nil
+	fake.loadLayerReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) LoadLayerReturnsOnCall(i int, result1 string, result2 error) {
+	fake.loadLayerMutex.Lock()
+	defer fake.loadLayerMutex.Unlock()
+	fake.LoadLayerStub = nil
+	if fake.loadLayerReturnsOnCall == nil {
+		fake.loadLayerReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.loadLayerReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilesystemVolume) LoadPrivileged() (bool, error) {
 	fake.loadPrivilegedMutex.Lock()
 	ret, specificReturn := fake.loadPrivilegedReturnsOnCall[len(fake.loadPrivilegedArgsForCall)]
 	fake.loadPrivilegedArgsForCall = append(fake.loadPrivilegedArgsForCall, struct {
 	}{})
+	stub := fake.LoadPrivilegedStub
+	fakeReturns := fake.loadPrivilegedReturns
 	fake.recordInvocation("LoadPrivileged", []interface{}{})
 	fake.loadPrivilegedMutex.Unlock()
-	if fake.LoadPrivilegedStub != nil {
-		return fake.LoadPrivilegedStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.loadPrivilegedReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -318,15 +653,16 @@ func (fake *FakeFilesystemVolume) LoadProperties() (volume.Properties, error) {
 	ret, specificReturn := fake.loadPropertiesReturnsOnCall[len(fake.loadPropertiesArgsForCall)]
 	fake.loadPropertiesArgsForCall = append(fake.loadPropertiesArgsForCall, struct {
 	}{})
+	stub := fake.LoadPropertiesStub
+	fakeReturns := fake.loadPropertiesReturns
 	fake.recordInvocation("LoadProperties", []interface{}{})
 	fake.loadPropertiesMutex.Unlock()
-	if fake.LoadPropertiesStub != nil {
-		return fake.LoadPropertiesStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.loadPropertiesReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -368,20 +704,133 @@ func (fake *FakeFilesystemVolume) LoadPropertiesReturnsOnCall(i int, result1 vol
 	}{result1, result2}
 }
 
+func (fake *FakeFilesystemVolume) LoadQuota() (uint64, error) {
+	fake.loadQuotaMutex.Lock()
+	ret, specificReturn := fake.loadQuotaReturnsOnCall[len(fake.loadQuotaArgsForCall)]
+	fake.loadQuotaArgsForCall = append(fake.loadQuotaArgsForCall, struct {
+	}{})
+	stub := fake.LoadQuotaStub
+	fakeReturns := fake.loadQuotaReturns
+	fake.recordInvocation("LoadQuota", []interface{}{})
+	fake.loadQuotaMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemVolume) LoadQuotaCallCount() int {
+	fake.loadQuotaMutex.RLock()
+	defer fake.loadQuotaMutex.RUnlock()
+	return len(fake.loadQuotaArgsForCall)
+}
+
+func (fake *FakeFilesystemVolume) LoadQuotaCalls(stub func() (uint64, error)) {
+	fake.loadQuotaMutex.Lock()
+	defer fake.loadQuotaMutex.Unlock()
+	fake.LoadQuotaStub = stub
+}
+
+func (fake *FakeFilesystemVolume) LoadQuotaReturns(result1 uint64, result2 error) {
+	fake.loadQuotaMutex.Lock()
+	defer fake.loadQuotaMutex.Unlock()
+	fake.LoadQuotaStub = nil
+	fake.loadQuotaReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) LoadQuotaReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.loadQuotaMutex.Lock()
+	defer fake.loadQuotaMutex.Unlock()
+	fake.LoadQuotaStub = nil
+	if fake.loadQuotaReturnsOnCall == nil {
+		fake.loadQuotaReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.loadQuotaReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) LoadReadOnly() (bool, error) {
+	fake.loadReadOnlyMutex.Lock()
+	ret, specificReturn := fake.loadReadOnlyReturnsOnCall[len(fake.loadReadOnlyArgsForCall)]
+	fake.loadReadOnlyArgsForCall = append(fake.loadReadOnlyArgsForCall, struct {
+	}{})
+	stub := fake.LoadReadOnlyStub
+	fakeReturns := fake.loadReadOnlyReturns
+	fake.recordInvocation("LoadReadOnly", []interface{}{})
+	fake.loadReadOnlyMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemVolume) LoadReadOnlyCallCount() int {
+	fake.loadReadOnlyMutex.RLock()
+	defer fake.loadReadOnlyMutex.RUnlock()
+	return len(fake.loadReadOnlyArgsForCall)
+}
+
+func (fake *FakeFilesystemVolume) LoadReadOnlyCalls(stub func() (bool, error)) {
+	fake.loadReadOnlyMutex.Lock()
+	defer fake.loadReadOnlyMutex.Unlock()
+	fake.LoadReadOnlyStub = stub
+}
+
+func (fake *FakeFilesystemVolume) LoadReadOnlyReturns(result1 bool, result2 error) {
+	fake.loadReadOnlyMutex.Lock()
+	defer fake.loadReadOnlyMutex.Unlock()
+	fake.LoadReadOnlyStub = nil
+	fake.loadReadOnlyReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) LoadReadOnlyReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.loadReadOnlyMutex.Lock()
+	defer fake.loadReadOnlyMutex.Unlock()
+	fake.LoadReadOnlyStub = nil
+	if fake.loadReadOnlyReturnsOnCall == nil {
+		fake.loadReadOnlyReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.loadReadOnlyReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilesystemVolume) Parent() (volume.FilesystemLiveVolume, bool, error) {
 	fake.parentMutex.Lock()
 	ret, specificReturn := fake.parentReturnsOnCall[len(fake.parentArgsForCall)]
 	fake.parentArgsForCall = append(fake.parentArgsForCall, struct {
 	}{})
+	stub := fake.ParentStub
+	fakeReturns := fake.parentReturns
 	fake.recordInvocation("Parent", []interface{}{})
 	fake.parentMutex.Unlock()
-	if fake.ParentStub != nil {
-		return fake.ParentStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.parentReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -426,21 +875,144 @@ func (fake *FakeFilesystemVolume) ParentReturnsOnCall(i int, result1 volume.File
 	}{result1, result2, result3}
 }
 
+func (fake *FakeFilesystemVolume) SetQuota(arg1 uint64) error {
+	fake.setQuotaMutex.Lock()
+	ret, specificReturn := fake.setQuotaReturnsOnCall[len(fake.setQuotaArgsForCall)]
+	fake.setQuotaArgsForCall = append(fake.setQuotaArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	stub := fake.SetQuotaStub
+	fakeReturns := fake.setQuotaReturns
+	fake.recordInvocation("SetQuota", []interface{}{arg1})
+	fake.setQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemVolume) SetQuotaCallCount() int {
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	return len(fake.setQuotaArgsForCall)
+}
+
+func (fake *FakeFilesystemVolume) SetQuotaCalls(stub func(uint64) error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = stub
+}
+
+func (fake *FakeFilesystemVolume) SetQuotaArgsForCall(i int) uint64 {
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	argsForCall := fake.setQuotaArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemVolume) SetQuotaReturns(result1 error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = nil
+	fake.setQuotaReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemVolume) SetQuotaReturnsOnCall(i int, result1 error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = nil
+	if fake.setQuotaReturnsOnCall == nil {
+		fake.setQuotaReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setQuotaReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemVolume) StoreExpiresAt(arg1 time.Time) error {
+	fake.storeExpiresAtMutex.Lock()
+	ret, specificReturn := fake.storeExpiresAtReturnsOnCall[len(fake.storeExpiresAtArgsForCall)]
+	fake.storeExpiresAtArgsForCall = append(fake.storeExpiresAtArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	stub := fake.StoreExpiresAtStub
+	fakeReturns := fake.storeExpiresAtReturns
+	fake.recordInvocation("StoreExpiresAt", []interface{}{arg1})
+	fake.storeExpiresAtMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFilesystemVolume) StoreExpiresAtCallCount() int {
+	fake.storeExpiresAtMutex.RLock()
+	defer fake.storeExpiresAtMutex.RUnlock()
+	return len(fake.storeExpiresAtArgsForCall)
+}
+
+func (fake *FakeFilesystemVolume) StoreExpiresAtCalls(stub func(time.Time) error) {
+	fake.storeExpiresAtMutex.Lock()
+	defer fake.storeExpiresAtMutex.Unlock()
+	fake.StoreExpiresAtStub = stub
+}
+
+func (fake *FakeFilesystemVolume) StoreExpiresAtArgsForCall(i int) time.Time {
+	fake.storeExpiresAtMutex.RLock()
+	defer fake.storeExpiresAtMutex.RUnlock()
+	argsForCall := fake.storeExpiresAtArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFilesystemVolume) StoreExpiresAtReturns(result1 error) {
+	fake.storeExpiresAtMutex.Lock()
+	defer fake.storeExpiresAtMutex.Unlock()
+	fake.StoreExpiresAtStub = nil
+	fake.storeExpiresAtReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFilesystemVolume) StoreExpiresAtReturnsOnCall(i int, result1 error) {
+	fake.storeExpiresAtMutex.Lock()
+	defer fake.storeExpiresAtMutex.Unlock()
+	fake.StoreExpiresAtStub = nil
+	if fake.storeExpiresAtReturnsOnCall == nil {
+		fake.storeExpiresAtReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeExpiresAtReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeFilesystemVolume) StorePrivileged(arg1 bool) error {
 	fake.storePrivilegedMutex.Lock()
 	ret, specificReturn := fake.storePrivilegedReturnsOnCall[len(fake.storePrivilegedArgsForCall)]
 	fake.storePrivilegedArgsForCall = append(fake.storePrivilegedArgsForCall, struct {
 		arg1 bool
 	}{arg1})
+	stub := fake.StorePrivilegedStub
+	fakeReturns := fake.storePrivilegedReturns
 	fake.recordInvocation("StorePrivileged", []interface{}{arg1})
 	fake.storePrivilegedMutex.Unlock()
-	if fake.StorePrivilegedStub != nil {
-		return fake.StorePrivilegedStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.storePrivilegedReturns
 	return fakeReturns.result1
 }
 
@@ -492,15 +1064,16 @@ func (fake *FakeFilesystemVolume) StoreProperties(arg1 volume.Properties) error
 	fake.storePropertiesArgsForCall = append(fake.storePropertiesArgsForCall, struct {
 		arg1 volume.Properties
 	}{arg1})
+	stub := fake.StorePropertiesStub
+	fakeReturns := fake.storePropertiesReturns
 	fake.recordInvocation("StoreProperties", []interface{}{arg1})
 	fake.storePropertiesMutex.Unlock()
-	if fake.StorePropertiesStub != nil {
-		return fake.StorePropertiesStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.storePropertiesReturns
 	return fakeReturns.result1
 }
 
@@ -546,6 +1119,62 @@ func (fake *FakeFilesystemVolume) StorePropertiesReturnsOnCall(i int, result1 er
 	}{result1}
 }
 
+func (fake *FakeFilesystemVolume) Usage() (volume.VolumeUsage, error) {
+	fake.usageMutex.Lock()
+	ret, specificReturn := fake.usageReturnsOnCall[len(fake.usageArgsForCall)]
+	fake.usageArgsForCall = append(fake.usageArgsForCall, struct {
+	}{})
+	stub := fake.UsageStub
+	fakeReturns := fake.usageReturns
+	fake.recordInvocation("Usage", []interface{}{})
+	fake.usageMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeFilesystemVolume) UsageCallCount() int {
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
+	return len(fake.usageArgsForCall)
+}
+
+func (fake *FakeFilesystemVolume) UsageCalls(stub func() (volume.VolumeUsage, error)) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = stub
+}
+
+func (fake *FakeFilesystemVolume) UsageReturns(result1 volume.VolumeUsage, result2 error) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = nil
+	fake.usageReturns = struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFilesystemVolume) UsageReturnsOnCall(i int, result1 volume.VolumeUsage, result2 error) {
+	fake.usageMutex.Lock()
+	defer fake.usageMutex.Unlock()
+	fake.UsageStub = nil
+	if fake.usageReturnsOnCall == nil {
+		fake.usageReturnsOnCall = make(map[int]struct {
+			result1 volume.VolumeUsage
+			result2 error
+		})
+	}
+	fake.usageReturnsOnCall[i] = struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilesystemVolume) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -555,16 +1184,34 @@ func (fake *FakeFilesystemVolume) Invocations() map[string][][]interface{} {
 	defer fake.destroyMutex.RUnlock()
 	fake.handleMutex.RLock()
 	defer fake.handleMutex.RUnlock()
+	fake.loadCreatedAtMutex.RLock()
+	defer fake.loadCreatedAtMutex.RUnlock()
+	fake.loadDigestMutex.RLock()
+	defer fake.loadDigestMutex.RUnlock()
+	fake.loadExpiresAtMutex.RLock()
+	defer fake.loadExpiresAtMutex.RUnlock()
+	fake.loadLayerMutex.RLock()
+	defer fake.loadLayerMutex.RUnlock()
 	fake.loadPrivilegedMutex.RLock()
 	defer fake.loadPrivilegedMutex.RUnlock()
 	fake.loadPropertiesMutex.RLock()
 	defer fake.loadPropertiesMutex.RUnlock()
+	fake.loadQuotaMutex.RLock()
+	defer fake.loadQuotaMutex.RUnlock()
+	fake.loadReadOnlyMutex.RLock()
+	defer fake.loadReadOnlyMutex.RUnlock()
 	fake.parentMutex.RLock()
 	defer fake.parentMutex.RUnlock()
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	fake.storeExpiresAtMutex.RLock()
+	defer fake.storeExpiresAtMutex.RUnlock()
 	fake.storePrivilegedMutex.RLock()
 	defer fake.storePrivilegedMutex.RUnlock()
 	fake.storePropertiesMutex.RLock()
 	defer fake.storePropertiesMutex.RUnlock()
+	fake.usageMutex.RLock()
+	defer fake.usageMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
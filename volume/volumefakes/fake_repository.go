@@ -4,20 +4,48 @@ package volumefakes
 import (
 	"context"
 	"io"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/concourse/baggageclaim/volume"
 )
 
 type FakeRepository struct {
-	CreateVolumeStub        func(context.Context, string, volume.Strategy, volume.Properties, bool) (volume.Volume, error)
+	CollectOrphansStub        func(context.Context) (int, error)
+	collectOrphansMutex       sync.RWMutex
+	collectOrphansArgsForCall []struct {
+		arg1 context.Context
+	}
+	collectOrphansReturns struct {
+		result1 int
+		result2 error
+	}
+	collectOrphansReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	CreateSnapshotStub        func(context.Context, string, string) (volume.Snapshot, error)
+	createSnapshotMutex       sync.RWMutex
+	createSnapshotArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	createSnapshotReturns struct {
+		result1 volume.Snapshot
+		result2 error
+	}
+	createSnapshotReturnsOnCall map[int]struct {
+		result1 volume.Snapshot
+		result2 error
+	}
+	CreateVolumeStub        func(context.Context, string, volume.VolumeSpec) (volume.Volume, error)
 	createVolumeMutex       sync.RWMutex
 	createVolumeArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
-		arg3 volume.Strategy
-		arg4 volume.Properties
-		arg5 bool
+		arg3 volume.VolumeSpec
 	}
 	createVolumeReturns struct {
 		result1 volume.Volume
@@ -27,6 +55,31 @@ type FakeRepository struct {
 		result1 volume.Volume
 		result2 error
 	}
+	DestroyQuarantinedVolumeStub        func(context.Context, string) error
+	destroyQuarantinedVolumeMutex       sync.RWMutex
+	destroyQuarantinedVolumeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	destroyQuarantinedVolumeReturns struct {
+		result1 error
+	}
+	destroyQuarantinedVolumeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DestroySnapshotStub        func(context.Context, string, string) error
+	destroySnapshotMutex       sync.RWMutex
+	destroySnapshotArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	destroySnapshotReturns struct {
+		result1 error
+	}
+	destroySnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DestroyVolumeStub        func(context.Context, string) error
 	destroyVolumeMutex       sync.RWMutex
 	destroyVolumeArgsForCall []struct {
@@ -51,6 +104,61 @@ type FakeRepository struct {
 	destroyVolumeAndDescendantsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DiffStub        func(context.Context, string) ([]volume.Change, error)
+	diffMutex       sync.RWMutex
+	diffArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	diffReturns struct {
+		result1 []volume.Change
+		result2 error
+	}
+	diffReturnsOnCall map[int]struct {
+		result1 []volume.Change
+		result2 error
+	}
+	FsckStub        func(context.Context, volume.FsckOptions) (volume.FsckReport, error)
+	fsckMutex       sync.RWMutex
+	fsckArgsForCall []struct {
+		arg1 context.Context
+		arg2 volume.FsckOptions
+	}
+	fsckReturns struct {
+		result1 volume.FsckReport
+		result2 error
+	}
+	fsckReturnsOnCall map[int]struct {
+		result1 volume.FsckReport
+		result2 error
+	}
+	GetDigestStub        func(context.Context, string) (string, error)
+	getDigestMutex       sync.RWMutex
+	getDigestArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getDigestReturns struct {
+		result1 string
+		result2 error
+	}
+	getDigestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetOrphanStatsStub        func(context.Context) (volume.OrphanStats, error)
+	getOrphanStatsMutex       sync.RWMutex
+	getOrphanStatsArgsForCall []struct {
+		arg1 context.Context
+	}
+	getOrphanStatsReturns struct {
+		result1 volume.OrphanStats
+		result2 error
+	}
+	getOrphanStatsReturnsOnCall map[int]struct {
+		result1 volume.OrphanStats
+		result2 error
+	}
 	GetPrivilegedStub        func(context.Context, string) (bool, error)
 	getPrivilegedMutex       sync.RWMutex
 	getPrivilegedArgsForCall []struct {
@@ -65,6 +173,20 @@ type FakeRepository struct {
 		result1 bool
 		result2 error
 	}
+	GetUsageStub        func(context.Context, string) (volume.VolumeUsage, error)
+	getUsageMutex       sync.RWMutex
+	getUsageArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getUsageReturns struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}
+	getUsageReturnsOnCall map[int]struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}
 	GetVolumeStub        func(context.Context, string) (volume.Volume, bool, error)
 	getVolumeMutex       sync.RWMutex
 	getVolumeArgsForCall []struct {
@@ -81,6 +203,19 @@ type FakeRepository struct {
 		result2 bool
 		result3 error
 	}
+	ListQuarantinedVolumesStub        func(context.Context) ([]string, error)
+	listQuarantinedVolumesMutex       sync.RWMutex
+	listQuarantinedVolumesArgsForCall []struct {
+		arg1 context.Context
+	}
+	listQuarantinedVolumesReturns struct {
+		result1 []string
+		result2 error
+	}
+	listQuarantinedVolumesReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	ListVolumesStub        func(context.Context, volume.Properties) (volume.Volumes, []string, error)
 	listVolumesMutex       sync.RWMutex
 	listVolumesArgsForCall []struct {
@@ -97,6 +232,76 @@ type FakeRepository struct {
 		result2 []string
 		result3 error
 	}
+	OpenFileStub        func(context.Context, string, string) (*os.File, error)
+	openFileMutex       sync.RWMutex
+	openFileArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	openFileReturns struct {
+		result1 *os.File
+		result2 error
+	}
+	openFileReturnsOnCall map[int]struct {
+		result1 *os.File
+		result2 error
+	}
+	ReapUploadsStub        func(context.Context, time.Duration) (int, error)
+	reapUploadsMutex       sync.RWMutex
+	reapUploadsArgsForCall []struct {
+		arg1 context.Context
+		arg2 time.Duration
+	}
+	reapUploadsReturns struct {
+		result1 int
+		result2 error
+	}
+	reapUploadsReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	ReapVolumesStub        func(context.Context) (int, error)
+	reapVolumesMutex       sync.RWMutex
+	reapVolumesArgsForCall []struct {
+		arg1 context.Context
+	}
+	reapVolumesReturns struct {
+		result1 int
+		result2 error
+	}
+	reapVolumesReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	RenameVolumeStub        func(context.Context, string, string) (volume.Volume, error)
+	renameVolumeMutex       sync.RWMutex
+	renameVolumeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	renameVolumeReturns struct {
+		result1 volume.Volume
+		result2 error
+	}
+	renameVolumeReturnsOnCall map[int]struct {
+		result1 volume.Volume
+		result2 error
+	}
+	RestoreSnapshotStub        func(context.Context, string, string) error
+	restoreSnapshotMutex       sync.RWMutex
+	restoreSnapshotArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	restoreSnapshotReturns struct {
+		result1 error
+	}
+	restoreSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetPrivilegedStub        func(context.Context, string, bool) error
 	setPrivilegedMutex       sync.RWMutex
 	setPrivilegedArgsForCall []struct {
@@ -124,6 +329,31 @@ type FakeRepository struct {
 	setPropertyReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetReadOnlyStub        func(context.Context, string) error
+	setReadOnlyMutex       sync.RWMutex
+	setReadOnlyArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	setReadOnlyReturns struct {
+		result1 error
+	}
+	setReadOnlyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetTTLStub        func(context.Context, string, time.Duration) error
+	setTTLMutex       sync.RWMutex
+	setTTLArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 time.Duration
+	}
+	setTTLReturns struct {
+		result1 error
+	}
+	setTTLReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StreamInStub        func(context.Context, string, string, string, io.Reader) (bool, error)
 	streamInMutex       sync.RWMutex
 	streamInArgsForCall []struct {
@@ -141,6 +371,25 @@ type FakeRepository struct {
 		result1 bool
 		result2 error
 	}
+	StreamInResumableStub        func(context.Context, string, string, string, string, int64, io.Reader) (bool, error)
+	streamInResumableMutex       sync.RWMutex
+	streamInResumableArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 int64
+		arg7 io.Reader
+	}
+	streamInResumableReturns struct {
+		result1 bool
+		result2 error
+	}
+	streamInResumableReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	StreamOutStub        func(context.Context, string, string, string, io.Writer) error
 	streamOutMutex       sync.RWMutex
 	streamOutArgsForCall []struct {
@@ -156,6 +405,36 @@ type FakeRepository struct {
 	streamOutReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StreamOutDiffStub        func(context.Context, string, string, string, io.Writer) error
+	streamOutDiffMutex       sync.RWMutex
+	streamOutDiffArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 io.Writer
+	}
+	streamOutDiffReturns struct {
+		result1 error
+	}
+	streamOutDiffReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StreamOutOCIStub        func(context.Context, string, bool, string, io.Writer) error
+	streamOutOCIMutex       sync.RWMutex
+	streamOutOCIArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 bool
+		arg4 string
+		arg5 io.Writer
+	}
+	streamOutOCIReturns struct {
+		result1 error
+	}
+	streamOutOCIReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StreamP2pOutStub        func(context.Context, string, string, string, string) error
 	streamP2pOutMutex       sync.RWMutex
 	streamP2pOutArgsForCall []struct {
@@ -171,6 +450,61 @@ type FakeRepository struct {
 	streamP2pOutReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SubscribeStub        func(context.Context) <-chan volume.Event
+	subscribeMutex       sync.RWMutex
+	subscribeArgsForCall []struct {
+		arg1 context.Context
+	}
+	subscribeReturns struct {
+		result1 <-chan volume.Event
+	}
+	subscribeReturnsOnCall map[int]struct {
+		result1 <-chan volume.Event
+	}
+	TreeStub        func(context.Context, string, string, int) ([]volume.TreeEntry, error)
+	treeMutex       sync.RWMutex
+	treeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 int
+	}
+	treeReturns struct {
+		result1 []volume.TreeEntry
+		result2 error
+	}
+	treeReturnsOnCall map[int]struct {
+		result1 []volume.TreeEntry
+		result2 error
+	}
+	UploadOffsetStub        func(context.Context, string, string) (int64, error)
+	uploadOffsetMutex       sync.RWMutex
+	uploadOffsetArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	uploadOffsetReturns struct {
+		result1 int64
+		result2 error
+	}
+	uploadOffsetReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	VisitVolumesStub        func(context.Context, volume.VolumeQuery, func(volume.Volume) error) error
+	visitVolumesMutex       sync.RWMutex
+	visitVolumesArgsForCall []struct {
+		arg1 context.Context
+		arg2 volume.VolumeQuery
+		arg3 func(volume.Volume) error
+	}
+	visitVolumesReturns struct {
+		result1 error
+	}
+	visitVolumesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	VolumeParentStub        func(context.Context, string) (volume.Volume, bool, error)
 	volumeParentMutex       sync.RWMutex
 	volumeParentArgsForCall []struct {
@@ -191,25 +525,154 @@ type FakeRepository struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeRepository) CreateVolume(arg1 context.Context, arg2 string, arg3 volume.Strategy, arg4 volume.Properties, arg5 bool) (volume.Volume, error) {
+func (fake *FakeRepository) CollectOrphans(arg1 context.Context) (int, error) {
+	fake.collectOrphansMutex.Lock()
+	ret, specificReturn := fake.collectOrphansReturnsOnCall[len(fake.collectOrphansArgsForCall)]
+	fake.collectOrphansArgsForCall = append(fake.collectOrphansArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.CollectOrphansStub
+	fakeReturns := fake.collectOrphansReturns
+	fake.recordInvocation("CollectOrphans", []interface{}{arg1})
+	fake.collectOrphansMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) CollectOrphansCallCount() int {
+	fake.collectOrphansMutex.RLock()
+	defer fake.collectOrphansMutex.RUnlock()
+	return len(fake.collectOrphansArgsForCall)
+}
+
+func (fake *FakeRepository) CollectOrphansCalls(stub func(context.Context) (int, error)) {
+	fake.collectOrphansMutex.Lock()
+	defer fake.collectOrphansMutex.Unlock()
+	fake.CollectOrphansStub = stub
+}
+
+func (fake *FakeRepository) CollectOrphansArgsForCall(i int) context.Context {
+	fake.collectOrphansMutex.RLock()
+	defer fake.collectOrphansMutex.RUnlock()
+	argsForCall := fake.collectOrphansArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) CollectOrphansReturns(result1 int, result2 error) {
+	fake.collectOrphansMutex.Lock()
+	defer fake.collectOrphansMutex.Unlock()
+	fake.CollectOrphansStub = nil
+	fake.collectOrphansReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) CollectOrphansReturnsOnCall(i int, result1 int, result2 error) {
+	fake.collectOrphansMutex.Lock()
+	defer fake.collectOrphansMutex.Unlock()
+	fake.CollectOrphansStub = nil
+	if fake.collectOrphansReturnsOnCall == nil {
+		fake.collectOrphansReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.collectOrphansReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) CreateSnapshot(arg1 context.Context, arg2 string, arg3 string) (volume.Snapshot, error) {
+	fake.createSnapshotMutex.Lock()
+	ret, specificReturn := fake.createSnapshotReturnsOnCall[len(fake.createSnapshotArgsForCall)]
+	fake.createSnapshotArgsForCall = append(fake.createSnapshotArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.CreateSnapshotStub
+	fakeReturns := fake.createSnapshotReturns
+	fake.recordInvocation("CreateSnapshot", []interface{}{arg1, arg2, arg3})
+	fake.createSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) CreateSnapshotCallCount() int {
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	return len(fake.createSnapshotArgsForCall)
+}
+
+func (fake *FakeRepository) CreateSnapshotCalls(stub func(context.Context, string, string) (volume.Snapshot, error)) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = stub
+}
+
+func (fake *FakeRepository) CreateSnapshotArgsForCall(i int) (context.Context, string, string) {
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
+	argsForCall := fake.createSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRepository) CreateSnapshotReturns(result1 volume.Snapshot, result2 error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = nil
+	fake.createSnapshotReturns = struct {
+		result1 volume.Snapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) CreateSnapshotReturnsOnCall(i int, result1 volume.Snapshot, result2 error) {
+	fake.createSnapshotMutex.Lock()
+	defer fake.createSnapshotMutex.Unlock()
+	fake.CreateSnapshotStub = nil
+	if fake.createSnapshotReturnsOnCall == nil {
+		fake.createSnapshotReturnsOnCall = make(map[int]struct {
+			result1 volume.Snapshot
+			result2 error
+		})
+	}
+	fake.createSnapshotReturnsOnCall[i] = struct {
+		result1 volume.Snapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) CreateVolume(arg1 context.Context, arg2 string, arg3 volume.VolumeSpec) (volume.Volume, error) {
 	fake.createVolumeMutex.Lock()
 	ret, specificReturn := fake.createVolumeReturnsOnCall[len(fake.createVolumeArgsForCall)]
 	fake.createVolumeArgsForCall = append(fake.createVolumeArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-		arg3 volume.Strategy
-		arg4 volume.Properties
-		arg5 bool
-	}{arg1, arg2, arg3, arg4, arg5})
-	fake.recordInvocation("CreateVolume", []interface{}{arg1, arg2, arg3, arg4, arg5})
+		arg3 volume.VolumeSpec
+	}{arg1, arg2, arg3})
+	stub := fake.CreateVolumeStub
+	fakeReturns := fake.createVolumeReturns
+	fake.recordInvocation("CreateVolume", []interface{}{arg1, arg2, arg3})
 	fake.createVolumeMutex.Unlock()
-	if fake.CreateVolumeStub != nil {
-		return fake.CreateVolumeStub(arg1, arg2, arg3, arg4, arg5)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.createVolumeReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -219,17 +682,17 @@ func (fake *FakeRepository) CreateVolumeCallCount() int {
 	return len(fake.createVolumeArgsForCall)
 }
 
-func (fake *FakeRepository) CreateVolumeCalls(stub func(context.Context, string, volume.Strategy, volume.Properties, bool) (volume.Volume, error)) {
+func (fake *FakeRepository) CreateVolumeCalls(stub func(context.Context, string, volume.VolumeSpec) (volume.Volume, error)) {
 	fake.createVolumeMutex.Lock()
 	defer fake.createVolumeMutex.Unlock()
 	fake.CreateVolumeStub = stub
 }
 
-func (fake *FakeRepository) CreateVolumeArgsForCall(i int) (context.Context, string, volume.Strategy, volume.Properties, bool) {
+func (fake *FakeRepository) CreateVolumeArgsForCall(i int) (context.Context, string, volume.VolumeSpec) {
 	fake.createVolumeMutex.RLock()
 	defer fake.createVolumeMutex.RUnlock()
 	argsForCall := fake.createVolumeArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeRepository) CreateVolumeReturns(result1 volume.Volume, result2 error) {
@@ -258,40 +721,166 @@ func (fake *FakeRepository) CreateVolumeReturnsOnCall(i int, result1 volume.Volu
 	}{result1, result2}
 }
 
-func (fake *FakeRepository) DestroyVolume(arg1 context.Context, arg2 string) error {
-	fake.destroyVolumeMutex.Lock()
-	ret, specificReturn := fake.destroyVolumeReturnsOnCall[len(fake.destroyVolumeArgsForCall)]
-	fake.destroyVolumeArgsForCall = append(fake.destroyVolumeArgsForCall, struct {
+func (fake *FakeRepository) DestroyQuarantinedVolume(arg1 context.Context, arg2 string) error {
+	fake.destroyQuarantinedVolumeMutex.Lock()
+	ret, specificReturn := fake.destroyQuarantinedVolumeReturnsOnCall[len(fake.destroyQuarantinedVolumeArgsForCall)]
+	fake.destroyQuarantinedVolumeArgsForCall = append(fake.destroyQuarantinedVolumeArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
-	fake.recordInvocation("DestroyVolume", []interface{}{arg1, arg2})
-	fake.destroyVolumeMutex.Unlock()
-	if fake.DestroyVolumeStub != nil {
-		return fake.DestroyVolumeStub(arg1, arg2)
+	stub := fake.DestroyQuarantinedVolumeStub
+	fakeReturns := fake.destroyQuarantinedVolumeReturns
+	fake.recordInvocation("DestroyQuarantinedVolume", []interface{}{arg1, arg2})
+	fake.destroyQuarantinedVolumeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.destroyVolumeReturns
 	return fakeReturns.result1
 }
 
-func (fake *FakeRepository) DestroyVolumeCallCount() int {
-	fake.destroyVolumeMutex.RLock()
-	defer fake.destroyVolumeMutex.RUnlock()
-	return len(fake.destroyVolumeArgsForCall)
+func (fake *FakeRepository) DestroyQuarantinedVolumeCallCount() int {
+	fake.destroyQuarantinedVolumeMutex.RLock()
+	defer fake.destroyQuarantinedVolumeMutex.RUnlock()
+	return len(fake.destroyQuarantinedVolumeArgsForCall)
 }
 
-func (fake *FakeRepository) DestroyVolumeCalls(stub func(context.Context, string) error) {
-	fake.destroyVolumeMutex.Lock()
-	defer fake.destroyVolumeMutex.Unlock()
-	fake.DestroyVolumeStub = stub
+func (fake *FakeRepository) DestroyQuarantinedVolumeCalls(stub func(context.Context, string) error) {
+	fake.destroyQuarantinedVolumeMutex.Lock()
+	defer fake.destroyQuarantinedVolumeMutex.Unlock()
+	fake.DestroyQuarantinedVolumeStub = stub
 }
 
-func (fake *FakeRepository) DestroyVolumeArgsForCall(i int) (context.Context, string) {
-	fake.destroyVolumeMutex.RLock()
-	defer fake.destroyVolumeMutex.RUnlock()
+func (fake *FakeRepository) DestroyQuarantinedVolumeArgsForCall(i int) (context.Context, string) {
+	fake.destroyQuarantinedVolumeMutex.RLock()
+	defer fake.destroyQuarantinedVolumeMutex.RUnlock()
+	argsForCall := fake.destroyQuarantinedVolumeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) DestroyQuarantinedVolumeReturns(result1 error) {
+	fake.destroyQuarantinedVolumeMutex.Lock()
+	defer fake.destroyQuarantinedVolumeMutex.Unlock()
+	fake.DestroyQuarantinedVolumeStub = nil
+	fake.destroyQuarantinedVolumeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) DestroyQuarantinedVolumeReturnsOnCall(i int, result1 error) {
+	fake.destroyQuarantinedVolumeMutex.Lock()
+	defer fake.destroyQuarantinedVolumeMutex.Unlock()
+	fake.DestroyQuarantinedVolumeStub = nil
+	if fake.destroyQuarantinedVolumeReturnsOnCall == nil {
+		fake.destroyQuarantinedVolumeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.destroyQuarantinedVolumeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) DestroySnapshot(arg1 context.Context, arg2 string, arg3 string) error {
+	fake.destroySnapshotMutex.Lock()
+	ret, specificReturn := fake.destroySnapshotReturnsOnCall[len(fake.destroySnapshotArgsForCall)]
+	fake.destroySnapshotArgsForCall = append(fake.destroySnapshotArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.DestroySnapshotStub
+	fakeReturns := fake.destroySnapshotReturns
+	fake.recordInvocation("DestroySnapshot", []interface{}{arg1, arg2, arg3})
+	fake.destroySnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) DestroySnapshotCallCount() int {
+	fake.destroySnapshotMutex.RLock()
+	defer fake.destroySnapshotMutex.RUnlock()
+	return len(fake.destroySnapshotArgsForCall)
+}
+
+func (fake *FakeRepository) DestroySnapshotCalls(stub func(context.Context, string, string) error) {
+	fake.destroySnapshotMutex.Lock()
+	defer fake.destroySnapshotMutex.Unlock()
+	fake.DestroySnapshotStub = stub
+}
+
+func (fake *FakeRepository) DestroySnapshotArgsForCall(i int) (context.Context, string, string) {
+	fake.destroySnapshotMutex.RLock()
+	defer fake.destroySnapshotMutex.RUnlock()
+	argsForCall := fake.destroySnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRepository) DestroySnapshotReturns(result1 error) {
+	fake.destroySnapshotMutex.Lock()
+	defer fake.destroySnapshotMutex.Unlock()
+	fake.DestroySnapshotStub = nil
+	fake.destroySnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) DestroySnapshotReturnsOnCall(i int, result1 error) {
+	fake.destroySnapshotMutex.Lock()
+	defer fake.destroySnapshotMutex.Unlock()
+	fake.DestroySnapshotStub = nil
+	if fake.destroySnapshotReturnsOnCall == nil {
+		fake.destroySnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.destroySnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) DestroyVolume(arg1 context.Context, arg2 string) error {
+	fake.destroyVolumeMutex.Lock()
+	ret, specificReturn := fake.destroyVolumeReturnsOnCall[len(fake.destroyVolumeArgsForCall)]
+	fake.destroyVolumeArgsForCall = append(fake.destroyVolumeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DestroyVolumeStub
+	fakeReturns := fake.destroyVolumeReturns
+	fake.recordInvocation("DestroyVolume", []interface{}{arg1, arg2})
+	fake.destroyVolumeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) DestroyVolumeCallCount() int {
+	fake.destroyVolumeMutex.RLock()
+	defer fake.destroyVolumeMutex.RUnlock()
+	return len(fake.destroyVolumeArgsForCall)
+}
+
+func (fake *FakeRepository) DestroyVolumeCalls(stub func(context.Context, string) error) {
+	fake.destroyVolumeMutex.Lock()
+	defer fake.destroyVolumeMutex.Unlock()
+	fake.DestroyVolumeStub = stub
+}
+
+func (fake *FakeRepository) DestroyVolumeArgsForCall(i int) (context.Context, string) {
+	fake.destroyVolumeMutex.RLock()
+	defer fake.destroyVolumeMutex.RUnlock()
 	argsForCall := fake.destroyVolumeArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2
 }
@@ -326,15 +915,16 @@ func (fake *FakeRepository) DestroyVolumeAndDescendants(arg1 context.Context, ar
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.DestroyVolumeAndDescendantsStub
+	fakeReturns := fake.destroyVolumeAndDescendantsReturns
 	fake.recordInvocation("DestroyVolumeAndDescendants", []interface{}{arg1, arg2})
 	fake.destroyVolumeAndDescendantsMutex.Unlock()
-	if fake.DestroyVolumeAndDescendantsStub != nil {
-		return fake.DestroyVolumeAndDescendantsStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.destroyVolumeAndDescendantsReturns
 	return fakeReturns.result1
 }
 
@@ -380,6 +970,265 @@ func (fake *FakeRepository) DestroyVolumeAndDescendantsReturnsOnCall(i int, resu
 	}{result1}
 }
 
+func (fake *FakeRepository) Diff(arg1 context.Context, arg2 string) ([]volume.Change, error) {
+	fake.diffMutex.Lock()
+	ret, specificReturn := fake.diffReturnsOnCall[len(fake.diffArgsForCall)]
+	fake.diffArgsForCall = append(fake.diffArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DiffStub
+	fakeReturns := fake.diffReturns
+	fake.recordInvocation("Diff", []interface{}{arg1, arg2})
+	fake.diffMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) DiffCallCount() int {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	return len(fake.diffArgsForCall)
+}
+
+func (fake *FakeRepository) DiffCalls(stub func(context.Context, string) ([]volume.Change, error)) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = stub
+}
+
+func (fake *FakeRepository) DiffArgsForCall(i int) (context.Context, string) {
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	argsForCall := fake.diffArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) DiffReturns(result1 []volume.Change, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	fake.diffReturns = struct {
+		result1 []volume.Change
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) DiffReturnsOnCall(i int, result1 []volume.Change, result2 error) {
+	fake.diffMutex.Lock()
+	defer fake.diffMutex.Unlock()
+	fake.DiffStub = nil
+	if fake.diffReturnsOnCall == nil {
+		fake.diffReturnsOnCall = make(map[int]struct {
+			result1 []volume.Change
+			result2 error
+		})
+	}
+	fake.diffReturnsOnCall[i] = struct {
+		result1 []volume.Change
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) Fsck(arg1 context.Context, arg2 volume.FsckOptions) (volume.FsckReport, error) {
+	fake.fsckMutex.Lock()
+	ret, specificReturn := fake.fsckReturnsOnCall[len(fake.fsckArgsForCall)]
+	fake.fsckArgsForCall = append(fake.fsckArgsForCall, struct {
+		arg1 context.Context
+		arg2 volume.FsckOptions
+	}{arg1, arg2})
+	stub := fake.FsckStub
+	fakeReturns := fake.fsckReturns
+	fake.recordInvocation("Fsck", []interface{}{arg1, arg2})
+	fake.fsckMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) FsckCallCount() int {
+	fake.fsckMutex.RLock()
+	defer fake.fsckMutex.RUnlock()
+	return len(fake.fsckArgsForCall)
+}
+
+func (fake *FakeRepository) FsckCalls(stub func(context.Context, volume.FsckOptions) (volume.FsckReport, error)) {
+	fake.fsckMutex.Lock()
+	defer fake.fsckMutex.Unlock()
+	fake.FsckStub = stub
+}
+
+func (fake *FakeRepository) FsckArgsForCall(i int) (context.Context, volume.FsckOptions) {
+	fake.fsckMutex.RLock()
+	defer fake.fsckMutex.RUnlock()
+	argsForCall := fake.fsckArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) FsckReturns(result1 volume.FsckReport, result2 error) {
+	fake.fsckMutex.Lock()
+	defer fake.fsckMutex.Unlock()
+	fake.FsckStub = nil
+	fake.fsckReturns = struct {
+		result1 volume.FsckReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) FsckReturnsOnCall(i int, result1 volume.FsckReport, result2 error) {
+	fake.fsckMutex.Lock()
+	defer fake.fsckMutex.Unlock()
+	fake.FsckStub = nil
+	if fake.fsckReturnsOnCall == nil {
+		fake.fsckReturnsOnCall = make(map[int]struct {
+			result1 volume.FsckReport
+			result2 error
+		})
+	}
+	fake.fsckReturnsOnCall[i] = struct {
+		result1 volume.FsckReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetDigest(arg1 context.Context, arg2 string) (string, error) {
+	fake.getDigestMutex.Lock()
+	ret, specificReturn := fake.getDigestReturnsOnCall[len(fake.getDigestArgsForCall)]
+	fake.getDigestArgsForCall = append(fake.getDigestArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetDigestStub
+	fakeReturns := fake.getDigestReturns
+	fake.recordInvocation("GetDigest", []interface{}{arg1, arg2})
+	fake.getDigestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetDigestCallCount() int {
+	fake.getDigestMutex.RLock()
+	defer fake.getDigestMutex.RUnlock()
+	return len(fake.getDigestArgsForCall)
+}
+
+func (fake *FakeRepository) GetDigestCalls(stub func(context.Context, string) (string, error)) {
+	fake.getDigestMutex.Lock()
+	defer fake.getDigestMutex.Unlock()
+	fake.GetDigestStub = stub
+}
+
+func (fake *FakeRepository) GetDigestArgsForCall(i int) (context.Context, string) {
+	fake.getDigestMutex.RLock()
+	defer fake.getDigestMutex.RUnlock()
+	argsForCall := fake.getDigestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) GetDigestReturns(result1 string, result2 error) {
+	fake.getDigestMutex.Lock()
+	defer fake.getDigestMutex.Unlock()
+	fake.GetDigestStub = nil
+	fake.getDigestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetDigestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getDigestMutex.Lock()
+	defer fake.getDigestMutex.Unlock()
+	fake.GetDigestStub = nil
+	if fake.getDigestReturnsOnCall == nil {
+		fake.getDigestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getDigestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetOrphanStats(arg1 context.Context) (volume.OrphanStats, error) {
+	fake.getOrphanStatsMutex.Lock()
+	ret, specificReturn := fake.getOrphanStatsReturnsOnCall[len(fake.getOrphanStatsArgsForCall)]
+	fake.getOrphanStatsArgsForCall = append(fake.getOrphanStatsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetOrphanStatsStub
+	fakeReturns := fake.getOrphanStatsReturns
+	fake.recordInvocation("GetOrphanStats", []interface{}{arg1})
+	fake.getOrphanStatsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetOrphanStatsCallCount() int {
+	fake.getOrphanStatsMutex.RLock()
+	defer fake.getOrphanStatsMutex.RUnlock()
+	return len(fake.getOrphanStatsArgsForCall)
+}
+
+func (fake *FakeRepository) GetOrphanStatsCalls(stub func(context.Context) (volume.OrphanStats, error)) {
+	fake.getOrphanStatsMutex.Lock()
+	defer fake.getOrphanStatsMutex.Unlock()
+	fake.GetOrphanStatsStub = stub
+}
+
+func (fake *FakeRepository) GetOrphanStatsArgsForCall(i int) context.Context {
+	fake.getOrphanStatsMutex.RLock()
+	defer fake.getOrphanStatsMutex.RUnlock()
+	argsForCall := fake.getOrphanStatsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) GetOrphanStatsReturns(result1 volume.OrphanStats, result2 error) {
+	fake.getOrphanStatsMutex.Lock()
+	defer fake.getOrphanStatsMutex.Unlock()
+	fake.GetOrphanStatsStub = nil
+	fake.getOrphanStatsReturns = struct {
+		result1 volume.OrphanStats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetOrphanStatsReturnsOnCall(i int, result1 volume.OrphanStats, result2 error) {
+	fake.getOrphanStatsMutex.Lock()
+	defer fake.getOrphanStatsMutex.Unlock()
+	fake.GetOrphanStatsStub = nil
+	if fake.getOrphanStatsReturnsOnCall == nil {
+		fake.getOrphanStatsReturnsOnCall = make(map[int]struct {
+			result1 volume.OrphanStats
+			result2 error
+		})
+	}
+	fake.getOrphanStatsReturnsOnCall[i] = struct {
+		result1 volume.OrphanStats
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) GetPrivileged(arg1 context.Context, arg2 string) (bool, error) {
 	fake.getPrivilegedMutex.Lock()
 	ret, specificReturn := fake.getPrivilegedReturnsOnCall[len(fake.getPrivilegedArgsForCall)]
@@ -387,15 +1236,16 @@ func (fake *FakeRepository) GetPrivileged(arg1 context.Context, arg2 string) (bo
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.GetPrivilegedStub
+	fakeReturns := fake.getPrivilegedReturns
 	fake.recordInvocation("GetPrivileged", []interface{}{arg1, arg2})
 	fake.getPrivilegedMutex.Unlock()
-	if fake.GetPrivilegedStub != nil {
-		return fake.GetPrivilegedStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getPrivilegedReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -444,6 +1294,71 @@ func (fake *FakeRepository) GetPrivilegedReturnsOnCall(i int, result1 bool, resu
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) GetUsage(arg1 context.Context, arg2 string) (volume.VolumeUsage, error) {
+	fake.getUsageMutex.Lock()
+	ret, specificReturn := fake.getUsageReturnsOnCall[len(fake.getUsageArgsForCall)]
+	fake.getUsageArgsForCall = append(fake.getUsageArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetUsageStub
+	fakeReturns := fake.getUsageReturns
+	fake.recordInvocation("GetUsage", []interface{}{arg1, arg2})
+	fake.getUsageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetUsageCallCount() int {
+	fake.getUsageMutex.RLock()
+	defer fake.getUsageMutex.RUnlock()
+	return len(fake.getUsageArgsForCall)
+}
+
+func (fake *FakeRepository) GetUsageCalls(stub func(context.Context, string) (volume.VolumeUsage, error)) {
+	fake.getUsageMutex.Lock()
+	defer fake.getUsageMutex.Unlock()
+	fake.GetUsageStub = stub
+}
+
+func (fake *FakeRepository) GetUsageArgsForCall(i int) (context.Context, string) {
+	fake.getUsageMutex.RLock()
+	defer fake.getUsageMutex.RUnlock()
+	argsForCall := fake.getUsageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) GetUsageReturns(result1 volume.VolumeUsage, result2 error) {
+	fake.getUsageMutex.Lock()
+	defer fake.getUsageMutex.Unlock()
+	fake.GetUsageStub = nil
+	fake.getUsageReturns = struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetUsageReturnsOnCall(i int, result1 volume.VolumeUsage, result2 error) {
+	fake.getUsageMutex.Lock()
+	defer fake.getUsageMutex.Unlock()
+	fake.GetUsageStub = nil
+	if fake.getUsageReturnsOnCall == nil {
+		fake.getUsageReturnsOnCall = make(map[int]struct {
+			result1 volume.VolumeUsage
+			result2 error
+		})
+	}
+	fake.getUsageReturnsOnCall[i] = struct {
+		result1 volume.VolumeUsage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) GetVolume(arg1 context.Context, arg2 string) (volume.Volume, bool, error) {
 	fake.getVolumeMutex.Lock()
 	ret, specificReturn := fake.getVolumeReturnsOnCall[len(fake.getVolumeArgsForCall)]
@@ -451,15 +1366,16 @@ func (fake *FakeRepository) GetVolume(arg1 context.Context, arg2 string) (volume
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.GetVolumeStub
+	fakeReturns := fake.getVolumeReturns
 	fake.recordInvocation("GetVolume", []interface{}{arg1, arg2})
 	fake.getVolumeMutex.Unlock()
-	if fake.GetVolumeStub != nil {
-		return fake.GetVolumeStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.getVolumeReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -511,6 +1427,70 @@ func (fake *FakeRepository) GetVolumeReturnsOnCall(i int, result1 volume.Volume,
 	}{result1, result2, result3}
 }
 
+func (fake *FakeRepository) ListQuarantinedVolumes(arg1 context.Context) ([]string, error) {
+	fake.listQuarantinedVolumesMutex.Lock()
+	ret, specificReturn := fake.listQuarantinedVolumesReturnsOnCall[len(fake.listQuarantinedVolumesArgsForCall)]
+	fake.listQuarantinedVolumesArgsForCall = append(fake.listQuarantinedVolumesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListQuarantinedVolumesStub
+	fakeReturns := fake.listQuarantinedVolumesReturns
+	fake.recordInvocation("ListQuarantinedVolumes", []interface{}{arg1})
+	fake.listQuarantinedVolumesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) ListQuarantinedVolumesCallCount() int {
+	fake.listQuarantinedVolumesMutex.RLock()
+	defer fake.listQuarantinedVolumesMutex.RUnlock()
+	return len(fake.listQuarantinedVolumesArgsForCall)
+}
+
+func (fake *FakeRepository) ListQuarantinedVolumesCalls(stub func(context.Context) ([]string, error)) {
+	fake.listQuarantinedVolumesMutex.Lock()
+	defer fake.listQuarantinedVolumesMutex.Unlock()
+	fake.ListQuarantinedVolumesStub = stub
+}
+
+func (fake *FakeRepository) ListQuarantinedVolumesArgsForCall(i int) context.Context {
+	fake.listQuarantinedVolumesMutex.RLock()
+	defer fake.listQuarantinedVolumesMutex.RUnlock()
+	argsForCall := fake.listQuarantinedVolumesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) ListQuarantinedVolumesReturns(result1 []string, result2 error) {
+	fake.listQuarantinedVolumesMutex.Lock()
+	defer fake.listQuarantinedVolumesMutex.Unlock()
+	fake.ListQuarantinedVolumesStub = nil
+	fake.listQuarantinedVolumesReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListQuarantinedVolumesReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listQuarantinedVolumesMutex.Lock()
+	defer fake.listQuarantinedVolumesMutex.Unlock()
+	fake.ListQuarantinedVolumesStub = nil
+	if fake.listQuarantinedVolumesReturnsOnCall == nil {
+		fake.listQuarantinedVolumesReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listQuarantinedVolumesReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) ListVolumes(arg1 context.Context, arg2 volume.Properties) (volume.Volumes, []string, error) {
 	fake.listVolumesMutex.Lock()
 	ret, specificReturn := fake.listVolumesReturnsOnCall[len(fake.listVolumesArgsForCall)]
@@ -518,15 +1498,16 @@ func (fake *FakeRepository) ListVolumes(arg1 context.Context, arg2 volume.Proper
 		arg1 context.Context
 		arg2 volume.Properties
 	}{arg1, arg2})
+	stub := fake.ListVolumesStub
+	fakeReturns := fake.listVolumesReturns
 	fake.recordInvocation("ListVolumes", []interface{}{arg1, arg2})
 	fake.listVolumesMutex.Unlock()
-	if fake.ListVolumesStub != nil {
-		return fake.ListVolumesStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.listVolumesReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -578,24 +1559,349 @@ func (fake *FakeRepository) ListVolumesReturnsOnCall(i int, result1 volume.Volum
 	}{result1, result2, result3}
 }
 
-func (fake *FakeRepository) SetPrivileged(arg1 context.Context, arg2 string, arg3 bool) error {
-	fake.setPrivilegedMutex.Lock()
-	ret, specificReturn := fake.setPrivilegedReturnsOnCall[len(fake.setPrivilegedArgsForCall)]
-	fake.setPrivilegedArgsForCall = append(fake.setPrivilegedArgsForCall, struct {
+func (fake *FakeRepository) OpenFile(arg1 context.Context, arg2 string, arg3 string) (*os.File, error) {
+	fake.openFileMutex.Lock()
+	ret, specificReturn := fake.openFileReturnsOnCall[len(fake.openFileArgsForCall)]
+	fake.openFileArgsForCall = append(fake.openFileArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-		arg3 bool
+		arg3 string
 	}{arg1, arg2, arg3})
-	fake.recordInvocation("SetPrivileged", []interface{}{arg1, arg2, arg3})
-	fake.setPrivilegedMutex.Unlock()
-	if fake.SetPrivilegedStub != nil {
-		return fake.SetPrivilegedStub(arg1, arg2, arg3)
+	stub := fake.OpenFileStub
+	fakeReturns := fake.openFileReturns
+	fake.recordInvocation("OpenFile", []interface{}{arg1, arg2, arg3})
+	fake.openFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.setPrivilegedReturns
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) OpenFileCallCount() int {
+	fake.openFileMutex.RLock()
+	defer fake.openFileMutex.RUnlock()
+	return len(fake.openFileArgsForCall)
+}
+
+func (fake *FakeRepository) OpenFileCalls(stub func(context.Context, string, string) (*os.File, error)) {
+	fake.openFileMutex.Lock()
+	defer fake.openFileMutex.Unlock()
+	fake.OpenFileStub = stub
+}
+
+func (fake *FakeRepository) OpenFileArgsForCall(i int) (context.Context, string, string) {
+	fake.openFileMutex.RLock()
+	defer fake.openFileMutex.RUnlock()
+	argsForCall := fake.openFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRepository) OpenFileReturns(result1 *os.File, result2 error) {
+	fake.openFileMutex.Lock()
+	defer fake.openFileMutex.Unlock()
+	fake.OpenFileStub = nil
+	fake.openFileReturns = struct {
+		result1 *os.File
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) OpenFileReturnsOnCall(i int, result1 *os.File, result2 error) {
+	fake.openFileMutex.Lock()
+	defer fake.openFileMutex.Unlock()
+	fake.OpenFileStub = nil
+	if fake.openFileReturnsOnCall == nil {
+		fake.openFileReturnsOnCall = make(map[int]struct {
+			result1 *os.File
+			result2 error
+		})
+	}
+	fake.openFileReturnsOnCall[i] = struct {
+		result1 *os.File
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ReapUploads(arg1 context.Context, arg2 time.Duration) (int, error) {
+	fake.reapUploadsMutex.Lock()
+	ret, specificReturn := fake.reapUploadsReturnsOnCall[len(fake.reapUploadsArgsForCall)]
+	fake.reapUploadsArgsForCall = append(fake.reapUploadsArgsForCall, struct {
+		arg1 context.Context
+		arg2 time.Duration
+	}{arg1, arg2})
+	stub := fake.ReapUploadsStub
+	fakeReturns := fake.reapUploadsReturns
+	fake.recordInvocation("ReapUploads", []interface{}{arg1, arg2})
+	fake.reapUploadsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) ReapUploadsCallCount() int {
+	fake.reapUploadsMutex.RLock()
+	defer fake.reapUploadsMutex.RUnlock()
+	return len(fake.reapUploadsArgsForCall)
+}
+
+func (fake *FakeRepository) ReapUploadsCalls(stub func(context.Context, time.Duration) (int, error)) {
+	fake.reapUploadsMutex.Lock()
+	defer fake.reapUploadsMutex.Unlock()
+	fake.ReapUploadsStub = stub
+}
+
+func (fake *FakeRepository) ReapUploadsArgsForCall(i int) (context.Context, time.Duration) {
+	fake.reapUploadsMutex.RLock()
+	defer fake.reapUploadsMutex.RUnlock()
+	argsForCall := fake.reapUploadsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) ReapUploadsReturns(result1 int, result2 error) {
+	fake.reapUploadsMutex.Lock()
+	defer fake.reapUploadsMutex.Unlock()
+	fake.ReapUploadsStub = nil
+	fake.reapUploadsReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ReapUploadsReturnsOnCall(i int, result1 int, result2 error) {
+	fake.reapUploadsMutex.Lock()
+	defer fake.reapUploadsMutex.Unlock()
+	fake.ReapUploadsStub = nil
+	if fake.reapUploadsReturnsOnCall == nil {
+		fake.reapUploadsReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.reapUploadsReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ReapVolumes(arg1 context.Context) (int, error) {
+	fake.reapVolumesMutex.Lock()
+	ret, specificReturn := fake.reapVolumesReturnsOnCall[len(fake.reapVolumesArgsForCall)]
+	fake.reapVolumesArgsForCall = append(fake.reapVolumesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ReapVolumesStub
+	fakeReturns := fake.reapVolumesReturns
+	fake.recordInvocation("ReapVolumes", []interface{}{arg1})
+	fake.reapVolumesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) ReapVolumesCallCount() int {
+	fake.reapVolumesMutex.RLock()
+	defer fake.reapVolumesMutex.RUnlock()
+	return len(fake.reapVolumesArgsForCall)
+}
+
+func (fake *FakeRepository) ReapVolumesCalls(stub func(context.Context) (int, error)) {
+	fake.reapVolumesMutex.Lock()
+	defer fake.reapVolumesMutex.Unlock()
+	fake.ReapVolumesStub = stub
+}
+
+func (fake *FakeRepository) ReapVolumesArgsForCall(i int) context.Context {
+	fake.reapVolumesMutex.RLock()
+	defer fake.reapVolumesMutex.RUnlock()
+	argsForCall := fake.reapVolumesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) ReapVolumesReturns(result1 int, result2 error) {
+	fake.reapVolumesMutex.Lock()
+	defer fake.reapVolumesMutex.Unlock()
+	fake.ReapVolumesStub = nil
+	fake.reapVolumesReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ReapVolumesReturnsOnCall(i int, result1 int, result2 error) {
+	fake.reapVolumesMutex.Lock()
+	defer fake.reapVolumesMutex.Unlock()
+	fake.ReapVolumesStub = nil
+	if fake.reapVolumesReturnsOnCall == nil {
+		fake.reapVolumesReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.reapVolumesReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) RenameVolume(arg1 context.Context, arg2 string, arg3 string) (volume.Volume, error) {
+	fake.renameVolumeMutex.Lock()
+	ret, specificReturn := fake.renameVolumeReturnsOnCall[len(fake.renameVolumeArgsForCall)]
+	fake.renameVolumeArgsForCall = append(fake.renameVolumeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.RenameVolumeStub
+	fakeReturns := fake.renameVolumeReturns
+	fake.recordInvocation("RenameVolume", []interface{}{arg1, arg2, arg3})
+	fake.renameVolumeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) RenameVolumeCallCount() int {
+	fake.renameVolumeMutex.RLock()
+	defer fake.renameVolumeMutex.RUnlock()
+	return len(fake.renameVolumeArgsForCall)
+}
+
+func (fake *FakeRepository) RenameVolumeCalls(stub func(context.Context, string, string) (volume.Volume, error)) {
+	fake.renameVolumeMutex.Lock()
+	defer fake.renameVolumeMutex.Unlock()
+	fake.RenameVolumeStub = stub
+}
+
+func (fake *FakeRepository) RenameVolumeArgsForCall(i int) (context.Context, string, string) {
+	fake.renameVolumeMutex.RLock()
+	defer fake.renameVolumeMutex.RUnlock()
+	argsForCall := fake.renameVolumeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRepository) RenameVolumeReturns(result1 volume.Volume, result2 error) {
+	fake.renameVolumeMutex.Lock()
+	defer fake.renameVolumeMutex.Unlock()
+	fake.RenameVolumeStub = nil
+	fake.renameVolumeReturns = struct {
+		result1 volume.Volume
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) RenameVolumeReturnsOnCall(i int, result1 volume.Volume, result2 error) {
+	fake.renameVolumeMutex.Lock()
+	defer fake.renameVolumeMutex.Unlock()
+	fake.RenameVolumeStub = nil
+	if fake.renameVolumeReturnsOnCall == nil {
+		fake.renameVolumeReturnsOnCall = make(map[int]struct {
+			result1 volume.Volume
+			result2 error
+		})
+	}
+	fake.renameVolumeReturnsOnCall[i] = struct {
+		result1 volume.Volume
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) RestoreSnapshot(arg1 context.Context, arg2 string, arg3 string) error {
+	fake.restoreSnapshotMutex.Lock()
+	ret, specificReturn := fake.restoreSnapshotReturnsOnCall[len(fake.restoreSnapshotArgsForCall)]
+	fake.restoreSnapshotArgsForCall = append(fake.restoreSnapshotArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.RestoreSnapshotStub
+	fakeReturns := fake.restoreSnapshotReturns
+	fake.recordInvocation("RestoreSnapshot", []interface{}{arg1, arg2, arg3})
+	fake.restoreSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) RestoreSnapshotCallCount() int {
+	fake.restoreSnapshotMutex.RLock()
+	defer fake.restoreSnapshotMutex.RUnlock()
+	return len(fake.restoreSnapshotArgsForCall)
+}
+
+func (fake *FakeRepository) RestoreSnapshotCalls(stub func(context.Context, string, string) error) {
+	fake.restoreSnapshotMutex.Lock()
+	defer fake.restoreSnapshotMutex.Unlock()
+	fake.RestoreSnapshotStub = stub
+}
+
+func (fake *FakeRepository) RestoreSnapshotArgsForCall(i int) (context.Context, string, string) {
+	fake.restoreSnapshotMutex.RLock()
+	defer fake.restoreSnapshotMutex.RUnlock()
+	argsForCall := fake.restoreSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRepository) RestoreSnapshotReturns(result1 error) {
+	fake.restoreSnapshotMutex.Lock()
+	defer fake.restoreSnapshotMutex.Unlock()
+	fake.RestoreSnapshotStub = nil
+	fake.restoreSnapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) RestoreSnapshotReturnsOnCall(i int, result1 error) {
+	fake.restoreSnapshotMutex.Lock()
+	defer fake.restoreSnapshotMutex.Unlock()
+	fake.RestoreSnapshotStub = nil
+	if fake.restoreSnapshotReturnsOnCall == nil {
+		fake.restoreSnapshotReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreSnapshotReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) SetPrivileged(arg1 context.Context, arg2 string, arg3 bool) error {
+	fake.setPrivilegedMutex.Lock()
+	ret, specificReturn := fake.setPrivilegedReturnsOnCall[len(fake.setPrivilegedArgsForCall)]
+	fake.setPrivilegedArgsForCall = append(fake.setPrivilegedArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 bool
+	}{arg1, arg2, arg3})
+	stub := fake.SetPrivilegedStub
+	fakeReturns := fake.setPrivilegedReturns
+	fake.recordInvocation("SetPrivileged", []interface{}{arg1, arg2, arg3})
+	fake.setPrivilegedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
 }
 
 func (fake *FakeRepository) SetPrivilegedCallCount() int {
@@ -649,15 +1955,16 @@ func (fake *FakeRepository) SetProperty(arg1 context.Context, arg2 string, arg3
 		arg3 string
 		arg4 string
 	}{arg1, arg2, arg3, arg4})
+	stub := fake.SetPropertyStub
+	fakeReturns := fake.setPropertyReturns
 	fake.recordInvocation("SetProperty", []interface{}{arg1, arg2, arg3, arg4})
 	fake.setPropertyMutex.Unlock()
-	if fake.SetPropertyStub != nil {
-		return fake.SetPropertyStub(arg1, arg2, arg3, arg4)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.setPropertyReturns
 	return fakeReturns.result1
 }
 
@@ -703,6 +2010,131 @@ func (fake *FakeRepository) SetPropertyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRepository) SetReadOnly(arg1 context.Context, arg2 string) error {
+	fake.setReadOnlyMutex.Lock()
+	ret, specificReturn := fake.setReadOnlyReturnsOnCall[len(fake.setReadOnlyArgsForCall)]
+	fake.setReadOnlyArgsForCall = append(fake.setReadOnlyArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SetReadOnlyStub
+	fakeReturns := fake.setReadOnlyReturns
+	fake.recordInvocation("SetReadOnly", []interface{}{arg1, arg2})
+	fake.setReadOnlyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) SetReadOnlyCallCount() int {
+	fake.setReadOnlyMutex.RLock()
+	defer fake.setReadOnlyMutex.RUnlock()
+	return len(fake.setReadOnlyArgsForCall)
+}
+
+func (fake *FakeRepository) SetReadOnlyCalls(stub func(context.Context, string) error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = stub
+}
+
+func (fake *FakeRepository) SetReadOnlyArgsForCall(i int) (context.Context, string) {
+	fake.setReadOnlyMutex.RLock()
+	defer fake.setReadOnlyMutex.RUnlock()
+	argsForCall := fake.setReadOnlyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) SetReadOnlyReturns(result1 error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = nil
+	fake.setReadOnlyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) SetReadOnlyReturnsOnCall(i int, result1 error) {
+	fake.setReadOnlyMutex.Lock()
+	defer fake.setReadOnlyMutex.Unlock()
+	fake.SetReadOnlyStub = nil
+	if fake.setReadOnlyReturnsOnCall == nil {
+		fake.setReadOnlyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setReadOnlyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) SetTTL(arg1 context.Context, arg2 string, arg3 time.Duration) error {
+	fake.setTTLMutex.Lock()
+	ret, specificReturn := fake.setTTLReturnsOnCall[len(fake.setTTLArgsForCall)]
+	fake.setTTLArgsForCall = append(fake.setTTLArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 time.Duration
+	}{arg1, arg2, arg3})
+	stub := fake.SetTTLStub
+	fakeReturns := fake.setTTLReturns
+	fake.recordInvocation("SetTTL", []interface{}{arg1, arg2, arg3})
+	fake.setTTLMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) SetTTLCallCount() int {
+	fake.setTTLMutex.RLock()
+	defer fake.setTTLMutex.RUnlock()
+	return len(fake.setTTLArgsForCall)
+}
+
+func (fake *FakeRepository) SetTTLCalls(stub func(context.Context, string, time.Duration) error) {
+	fake.setTTLMutex.Lock()
+	defer fake.setTTLMutex.Unlock()
+	fake.SetTTLStub = stub
+}
+
+func (fake *FakeRepository) SetTTLArgsForCall(i int) (context.Context, string, time.Duration) {
+	fake.setTTLMutex.RLock()
+	defer fake.setTTLMutex.RUnlock()
+	argsForCall := fake.setTTLArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRepository) SetTTLReturns(result1 error) {
+	fake.setTTLMutex.Lock()
+	defer fake.setTTLMutex.Unlock()
+	fake.SetTTLStub = nil
+	fake.setTTLReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) SetTTLReturnsOnCall(i int, result1 error) {
+	fake.setTTLMutex.Lock()
+	defer fake.setTTLMutex.Unlock()
+	fake.SetTTLStub = nil
+	if fake.setTTLReturnsOnCall == nil {
+		fake.setTTLReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setTTLReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRepository) StreamIn(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 io.Reader) (bool, error) {
 	fake.streamInMutex.Lock()
 	ret, specificReturn := fake.streamInReturnsOnCall[len(fake.streamInArgsForCall)]
@@ -713,15 +2145,16 @@ func (fake *FakeRepository) StreamIn(arg1 context.Context, arg2 string, arg3 str
 		arg4 string
 		arg5 io.Reader
 	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.StreamInStub
+	fakeReturns := fake.streamInReturns
 	fake.recordInvocation("StreamIn", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.streamInMutex.Unlock()
-	if fake.StreamInStub != nil {
-		return fake.StreamInStub(arg1, arg2, arg3, arg4, arg5)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.streamInReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -770,6 +2203,76 @@ func (fake *FakeRepository) StreamInReturnsOnCall(i int, result1 bool, result2 e
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) StreamInResumable(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 string, arg6 int64, arg7 io.Reader) (bool, error) {
+	fake.streamInResumableMutex.Lock()
+	ret, specificReturn := fake.streamInResumableReturnsOnCall[len(fake.streamInResumableArgsForCall)]
+	fake.streamInResumableArgsForCall = append(fake.streamInResumableArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 int64
+		arg7 io.Reader
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+	stub := fake.StreamInResumableStub
+	fakeReturns := fake.streamInResumableReturns
+	fake.recordInvocation("StreamInResumable", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+	fake.streamInResumableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) StreamInResumableCallCount() int {
+	fake.streamInResumableMutex.RLock()
+	defer fake.streamInResumableMutex.RUnlock()
+	return len(fake.streamInResumableArgsForCall)
+}
+
+func (fake *FakeRepository) StreamInResumableCalls(stub func(context.Context, string, string, string, string, int64, io.Reader) (bool, error)) {
+	fake.streamInResumableMutex.Lock()
+	defer fake.streamInResumableMutex.Unlock()
+	fake.StreamInResumableStub = stub
+}
+
+func (fake *FakeRepository) StreamInResumableArgsForCall(i int) (context.Context, string, string, string, string, int64, io.Reader) {
+	fake.streamInResumableMutex.RLock()
+	defer fake.streamInResumableMutex.RUnlock()
+	argsForCall := fake.streamInResumableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+}
+
+func (fake *FakeRepository) StreamInResumableReturns(result1 bool, result2 error) {
+	fake.streamInResumableMutex.Lock()
+	defer fake.streamInResumableMutex.Unlock()
+	fake.StreamInResumableStub = nil
+	fake.streamInResumableReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) StreamInResumableReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.streamInResumableMutex.Lock()
+	defer fake.streamInResumableMutex.Unlock()
+	fake.StreamInResumableStub = nil
+	if fake.streamInResumableReturnsOnCall == nil {
+		fake.streamInResumableReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.streamInResumableReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) StreamOut(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 io.Writer) error {
 	fake.streamOutMutex.Lock()
 	ret, specificReturn := fake.streamOutReturnsOnCall[len(fake.streamOutArgsForCall)]
@@ -780,15 +2283,16 @@ func (fake *FakeRepository) StreamOut(arg1 context.Context, arg2 string, arg3 st
 		arg4 string
 		arg5 io.Writer
 	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.StreamOutStub
+	fakeReturns := fake.streamOutReturns
 	fake.recordInvocation("StreamOut", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.streamOutMutex.Unlock()
-	if fake.StreamOutStub != nil {
-		return fake.StreamOutStub(arg1, arg2, arg3, arg4, arg5)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.streamOutReturns
 	return fakeReturns.result1
 }
 
@@ -834,6 +2338,136 @@ func (fake *FakeRepository) StreamOutReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRepository) StreamOutDiff(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 io.Writer) error {
+	fake.streamOutDiffMutex.Lock()
+	ret, specificReturn := fake.streamOutDiffReturnsOnCall[len(fake.streamOutDiffArgsForCall)]
+	fake.streamOutDiffArgsForCall = append(fake.streamOutDiffArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 io.Writer
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.StreamOutDiffStub
+	fakeReturns := fake.streamOutDiffReturns
+	fake.recordInvocation("StreamOutDiff", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.streamOutDiffMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) StreamOutDiffCallCount() int {
+	fake.streamOutDiffMutex.RLock()
+	defer fake.streamOutDiffMutex.RUnlock()
+	return len(fake.streamOutDiffArgsForCall)
+}
+
+func (fake *FakeRepository) StreamOutDiffCalls(stub func(context.Context, string, string, string, io.Writer) error) {
+	fake.streamOutDiffMutex.Lock()
+	defer fake.streamOutDiffMutex.Unlock()
+	fake.StreamOutDiffStub = stub
+}
+
+func (fake *FakeRepository) StreamOutDiffArgsForCall(i int) (context.Context, string, string, string, io.Writer) {
+	fake.streamOutDiffMutex.RLock()
+	defer fake.streamOutDiffMutex.RUnlock()
+	argsForCall := fake.streamOutDiffArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeRepository) StreamOutDiffReturns(result1 error) {
+	fake.streamOutDiffMutex.Lock()
+	defer fake.streamOutDiffMutex.Unlock()
+	fake.StreamOutDiffStub = nil
+	fake.streamOutDiffReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) StreamOutDiffReturnsOnCall(i int, result1 error) {
+	fake.streamOutDiffMutex.Lock()
+	defer fake.streamOutDiffMutex.Unlock()
+	fake.StreamOutDiffStub = nil
+	if fake.streamOutDiffReturnsOnCall == nil {
+		fake.streamOutDiffReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamOutDiffReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) StreamOutOCI(arg1 context.Context, arg2 string, arg3 bool, arg4 string, arg5 io.Writer) error {
+	fake.streamOutOCIMutex.Lock()
+	ret, specificReturn := fake.streamOutOCIReturnsOnCall[len(fake.streamOutOCIArgsForCall)]
+	fake.streamOutOCIArgsForCall = append(fake.streamOutOCIArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 bool
+		arg4 string
+		arg5 io.Writer
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.StreamOutOCIStub
+	fakeReturns := fake.streamOutOCIReturns
+	fake.recordInvocation("StreamOutOCI", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.streamOutOCIMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) StreamOutOCICallCount() int {
+	fake.streamOutOCIMutex.RLock()
+	defer fake.streamOutOCIMutex.RUnlock()
+	return len(fake.streamOutOCIArgsForCall)
+}
+
+func (fake *FakeRepository) StreamOutOCICalls(stub func(context.Context, string, bool, string, io.Writer) error) {
+	fake.streamOutOCIMutex.Lock()
+	defer fake.streamOutOCIMutex.Unlock()
+	fake.StreamOutOCIStub = stub
+}
+
+func (fake *FakeRepository) StreamOutOCIArgsForCall(i int) (context.Context, string, bool, string, io.Writer) {
+	fake.streamOutOCIMutex.RLock()
+	defer fake.streamOutOCIMutex.RUnlock()
+	argsForCall := fake.streamOutOCIArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeRepository) StreamOutOCIReturns(result1 error) {
+	fake.streamOutOCIMutex.Lock()
+	defer fake.streamOutOCIMutex.Unlock()
+	fake.StreamOutOCIStub = nil
+	fake.streamOutOCIReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) StreamOutOCIReturnsOnCall(i int, result1 error) {
+	fake.streamOutOCIMutex.Lock()
+	defer fake.streamOutOCIMutex.Unlock()
+	fake.StreamOutOCIStub = nil
+	if fake.streamOutOCIReturnsOnCall == nil {
+		fake.streamOutOCIReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamOutOCIReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRepository) StreamP2pOut(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 string) error {
 	fake.streamP2pOutMutex.Lock()
 	ret, specificReturn := fake.streamP2pOutReturnsOnCall[len(fake.streamP2pOutArgsForCall)]
@@ -844,15 +2478,16 @@ func (fake *FakeRepository) StreamP2pOut(arg1 context.Context, arg2 string, arg3
 		arg4 string
 		arg5 string
 	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.StreamP2pOutStub
+	fakeReturns := fake.streamP2pOutReturns
 	fake.recordInvocation("StreamP2pOut", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.streamP2pOutMutex.Unlock()
-	if fake.StreamP2pOutStub != nil {
-		return fake.StreamP2pOutStub(arg1, arg2, arg3, arg4, arg5)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.streamP2pOutReturns
 	return fakeReturns.result1
 }
 
@@ -898,6 +2533,263 @@ func (fake *FakeRepository) StreamP2pOutReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRepository) Subscribe(arg1 context.Context) <-chan volume.Event {
+	fake.subscribeMutex.Lock()
+	ret, specificReturn := fake.subscribeReturnsOnCall[len(fake.subscribeArgsForCall)]
+	fake.subscribeArgsForCall = append(fake.subscribeArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.SubscribeStub
+	fakeReturns := fake.subscribeReturns
+	fake.recordInvocation("Subscribe", []interface{}{arg1})
+	fake.subscribeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) SubscribeCallCount() int {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	return len(fake.subscribeArgsForCall)
+}
+
+func (fake *FakeRepository) SubscribeCalls(stub func(context.Context) <-chan volume.Event) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = stub
+}
+
+func (fake *FakeRepository) SubscribeArgsForCall(i int) context.Context {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	argsForCall := fake.subscribeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) SubscribeReturns(result1 <-chan volume.Event) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = nil
+	fake.subscribeReturns = struct {
+		result1 <-chan volume.Event
+	}{result1}
+}
+
+func (fake *FakeRepository) SubscribeReturnsOnCall(i int, result1 <-chan volume.Event) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = nil
+	if fake.subscribeReturnsOnCall == nil {
+		fake.subscribeReturnsOnCall = make(map[int]struct {
+			result1 <-chan volume.Event
+		})
+	}
+	fake.subscribeReturnsOnCall[i] = struct {
+		result1 <-chan volume.Event
+	}{result1}
+}
+
+func (fake *FakeRepository) Tree(arg1 context.Context, arg2 string, arg3 string, arg4 int) ([]volume.TreeEntry, error) {
+	fake.treeMutex.Lock()
+	ret, specificReturn := fake.treeReturnsOnCall[len(fake.treeArgsForCall)]
+	fake.treeArgsForCall = append(fake.treeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.TreeStub
+	fakeReturns := fake.treeReturns
+	fake.recordInvocation("Tree", []interface{}{arg1, arg2, arg3, arg4})
+	fake.treeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) TreeCallCount() int {
+	fake.treeMutex.RLock()
+	defer fake.treeMutex.RUnlock()
+	return len(fake.treeArgsForCall)
+}
+
+func (fake *FakeRepository) TreeCalls(stub func(context.Context, string, string, int) ([]volume.TreeEntry, error)) {
+	fake.treeMutex.Lock()
+	defer fake.treeMutex.Unlock()
+	fake.TreeStub = stub
+}
+
+func (fake *FakeRepository) TreeArgsForCall(i int) (context.Context, string, string, int) {
+	fake.treeMutex.RLock()
+	defer fake.treeMutex.RUnlock()
+	argsForCall := fake.treeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeRepository) TreeReturns(result1 []volume.TreeEntry, result2 error) {
+	fake.treeMutex.Lock()
+	defer fake.treeMutex.Unlock()
+	fake.TreeStub = nil
+	fake.treeReturns = struct {
+		result1 []volume.TreeEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) TreeReturnsOnCall(i int, result1 []volume.TreeEntry, result2 error) {
+	fake.treeMutex.Lock()
+	defer fake.treeMutex.Unlock()
+	fake.TreeStub = nil
+	if fake.treeReturnsOnCall == nil {
+		fake.treeReturnsOnCall = make(map[int]struct {
+			result1 []volume.TreeEntry
+			result2 error
+		})
+	}
+	fake.treeReturnsOnCall[i] = struct {
+		result1 []volume.TreeEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) UploadOffset(arg1 context.Context, arg2 string, arg3 string) (int64, error) {
+	fake.uploadOffsetMutex.Lock()
+	ret, specificReturn := fake.uploadOffsetReturnsOnCall[len(fake.uploadOffsetArgsForCall)]
+	fake.uploadOffsetArgsForCall = append(fake.uploadOffsetArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.UploadOffsetStub
+	fakeReturns := fake.uploadOffsetReturns
+	fake.recordInvocation("UploadOffset", []interface{}{arg1, arg2, arg3})
+	fake.uploadOffsetMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) UploadOffsetCallCount() int {
+	fake.uploadOffsetMutex.RLock()
+	defer fake.uploadOffsetMutex.RUnlock()
+	return len(fake.uploadOffsetArgsForCall)
+}
+
+func (fake *FakeRepository) UploadOffsetCalls(stub func(context.Context, string, string) (int64, error)) {
+	fake.uploadOffsetMutex.Lock()
+	defer fake.uploadOffsetMutex.Unlock()
+	fake.UploadOffsetStub = stub
+}
+
+func (fake *FakeRepository) UploadOffsetArgsForCall(i int) (context.Context, string, string) {
+	fake.uploadOffsetMutex.RLock()
+	defer fake.uploadOffsetMutex.RUnlock()
+	argsForCall := fake.uploadOffsetArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRepository) UploadOffsetReturns(result1 int64, result2 error) {
+	fake.uploadOffsetMutex.Lock()
+	defer fake.uploadOffsetMutex.Unlock()
+	fake.UploadOffsetStub = nil
+	fake.uploadOffsetReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) UploadOffsetReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.uploadOffsetMutex.Lock()
+	defer fake.uploadOffsetMutex.Unlock()
+	fake.UploadOffsetStub = nil
+	if fake.uploadOffsetReturnsOnCall == nil {
+		fake.uploadOffsetReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.uploadOffsetReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) VisitVolumes(arg1 context.Context, arg2 volume.VolumeQuery, arg3 func(volume.Volume) error) error {
+	fake.visitVolumesMutex.Lock()
+	ret, specificReturn := fake.visitVolumesReturnsOnCall[len(fake.visitVolumesArgsForCall)]
+	fake.visitVolumesArgsForCall = append(fake.visitVolumesArgsForCall, struct {
+		arg1 context.Context
+		arg2 volume.VolumeQuery
+		arg3 func(volume.Volume) error
+	}{arg1, arg2, arg3})
+	stub := fake.VisitVolumesStub
+	fakeReturns := fake.visitVolumesReturns
+	fake.recordInvocation("VisitVolumes", []interface{}{arg1, arg2, arg3})
+	fake.visitVolumesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) VisitVolumesCallCount() int {
+	fake.visitVolumesMutex.RLock()
+	defer fake.visitVolumesMutex.RUnlock()
+	return len(fake.visitVolumesArgsForCall)
+}
+
+func (fake *FakeRepository) VisitVolumesCalls(stub func(context.Context, volume.VolumeQuery, func(volume.Volume) error) error) {
+	fake.visitVolumesMutex.Lock()
+	defer fake.visitVolumesMutex.Unlock()
+	fake.VisitVolumesStub = stub
+}
+
+func (fake *FakeRepository) VisitVolumesArgsForCall(i int) (context.Context, volume.VolumeQuery, func(volume.Volume) error) {
+	fake.visitVolumesMutex.RLock()
+	defer fake.visitVolumesMutex.RUnlock()
+	argsForCall := fake.visitVolumesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRepository) VisitVolumesReturns(result1 error) {
+	fake.visitVolumesMutex.Lock()
+	defer fake.visitVolumesMutex.Unlock()
+	fake.VisitVolumesStub = nil
+	fake.visitVolumesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) VisitVolumesReturnsOnCall(i int, result1 error) {
+	fake.visitVolumesMutex.Lock()
+	defer fake.visitVolumesMutex.Unlock()
+	fake.VisitVolumesStub = nil
+	if fake.visitVolumesReturnsOnCall == nil {
+		fake.visitVolumesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.visitVolumesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRepository) VolumeParent(arg1 context.Context, arg2 string) (volume.Volume, bool, error) {
 	fake.volumeParentMutex.Lock()
 	ret, specificReturn := fake.volumeParentReturnsOnCall[len(fake.volumeParentArgsForCall)]
@@ -905,15 +2797,16 @@ func (fake *FakeRepository) VolumeParent(arg1 context.Context, arg2 string) (vol
 		arg1 context.Context
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.VolumeParentStub
+	fakeReturns := fake.volumeParentReturns
 	fake.recordInvocation("VolumeParent", []interface{}{arg1, arg2})
 	fake.volumeParentMutex.Unlock()
-	if fake.VolumeParentStub != nil {
-		return fake.VolumeParentStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.volumeParentReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -968,28 +2861,76 @@ func (fake *FakeRepository) VolumeParentReturnsOnCall(i int, result1 volume.Volu
 func (fake *FakeRepository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.collectOrphansMutex.RLock()
+	defer fake.collectOrphansMutex.RUnlock()
+	fake.createSnapshotMutex.RLock()
+	defer fake.createSnapshotMutex.RUnlock()
 	fake.createVolumeMutex.RLock()
 	defer fake.createVolumeMutex.RUnlock()
+	fake.destroyQuarantinedVolumeMutex.RLock()
+	defer fake.destroyQuarantinedVolumeMutex.RUnlock()
+	fake.destroySnapshotMutex.RLock()
+	defer fake.destroySnapshotMutex.RUnlock()
 	fake.destroyVolumeMutex.RLock()
 	defer fake.destroyVolumeMutex.RUnlock()
 	fake.destroyVolumeAndDescendantsMutex.RLock()
 	defer fake.destroyVolumeAndDescendantsMutex.RUnlock()
+	fake.diffMutex.RLock()
+	defer fake.diffMutex.RUnlock()
+	fake.fsckMutex.RLock()
+	defer fake.fsckMutex.RUnlock()
+	fake.getDigestMutex.RLock()
+	defer fake.getDigestMutex.RUnlock()
+	fake.getOrphanStatsMutex.RLock()
+	defer fake.getOrphanStatsMutex.RUnlock()
 	fake.getPrivilegedMutex.RLock()
 	defer fake.getPrivilegedMutex.RUnlock()
+	fake.getUsageMutex.RLock()
+	defer fake.getUsageMutex.RUnlock()
 	fake.getVolumeMutex.RLock()
 	defer fake.getVolumeMutex.RUnlock()
+	fake.listQuarantinedVolumesMutex.RLock()
+	defer fake.listQuarantinedVolumesMutex.RUnlock()
 	fake.listVolumesMutex.RLock()
 	defer fake.listVolumesMutex.RUnlock()
+	fake.openFileMutex.RLock()
+	defer fake.openFileMutex.RUnlock()
+	fake.reapUploadsMutex.RLock()
+	defer fake.reapUploadsMutex.RUnlock()
+	fake.reapVolumesMutex.RLock()
+	defer fake.reapVolumesMutex.RUnlock()
+	fake.renameVolumeMutex.RLock()
+	defer fake.renameVolumeMutex.RUnlock()
+	fake.restoreSnapshotMutex.RLock()
+	defer fake.restoreSnapshotMutex.RUnlock()
 	fake.setPrivilegedMutex.RLock()
 	defer fake.setPrivilegedMutex.RUnlock()
 	fake.setPropertyMutex.RLock()
 	defer fake.setPropertyMutex.RUnlock()
+	fake.setReadOnlyMutex.RLock()
+	defer fake.setReadOnlyMutex.RUnlock()
+	fake.setTTLMutex.RLock()
+	defer fake.setTTLMutex.RUnlock()
 	fake.streamInMutex.RLock()
 	defer fake.streamInMutex.RUnlock()
+	fake.streamInResumableMutex.RLock()
+	defer fake.streamInResumableMutex.RUnlock()
 	fake.streamOutMutex.RLock()
 	defer fake.streamOutMutex.RUnlock()
+	fake.streamOutDiffMutex.RLock()
+	defer fake.streamOutDiffMutex.RUnlock()
+	fake.streamOutOCIMutex.RLock()
+	defer fake.streamOutOCIMutex.RUnlock()
 	fake.streamP2pOutMutex.RLock()
 	defer fake.streamP2pOutMutex.RUnlock()
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	fake.treeMutex.RLock()
+	defer fake.treeMutex.RUnlock()
+	fake.uploadOffsetMutex.RLock()
+	defer fake.uploadOffsetMutex.RUnlock()
+	fake.visitVolumesMutex.RLock()
+	defer fake.visitVolumesMutex.RUnlock()
 	fake.volumeParentMutex.RLock()
 	defer fake.volumeParentMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -9,12 +9,13 @@ import (
 )
 
 type FakeStreamer struct {
-	InStub        func(io.Reader, string, bool) (bool, error)
+	InStub        func(io.Reader, string, string, bool) (bool, error)
 	inMutex       sync.RWMutex
 	inArgsForCall []struct {
 		arg1 io.Reader
 		arg2 string
-		arg3 bool
+		arg3 string
+		arg4 bool
 	}
 	inReturns struct {
 		result1 bool
@@ -24,12 +25,47 @@ type FakeStreamer struct {
 		result1 bool
 		result2 error
 	}
-	OutStub        func(io.Writer, string, bool) error
+	InLayerStub        func(io.Reader, string, string, bool) (bool, error)
+	inLayerMutex       sync.RWMutex
+	inLayerArgsForCall []struct {
+		arg1 io.Reader
+		arg2 string
+		arg3 string
+		arg4 bool
+	}
+	inLayerReturns struct {
+		result1 bool
+		result2 error
+	}
+	inLayerReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	InResumingStub        func(io.Reader, string, string, bool, int, func(int)) (bool, error)
+	inResumingMutex       sync.RWMutex
+	inResumingArgsForCall []struct {
+		arg1 io.Reader
+		arg2 string
+		arg3 string
+		arg4 bool
+		arg5 int
+		arg6 func(int)
+	}
+	inResumingReturns struct {
+		result1 bool
+		result2 error
+	}
+	inResumingReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	OutStub        func(io.Writer, string, string, bool) error
 	outMutex       sync.RWMutex
 	outArgsForCall []struct {
 		arg1 io.Writer
 		arg2 string
-		arg3 bool
+		arg3 string
+		arg4 bool
 	}
 	outReturns struct {
 		result1 error
@@ -37,27 +73,44 @@ type FakeStreamer struct {
 	outReturnsOnCall map[int]struct {
 		result1 error
 	}
+	OutDiffStub        func(io.Writer, string, string, []volume.Change, bool) error
+	outDiffMutex       sync.RWMutex
+	outDiffArgsForCall []struct {
+		arg1 io.Writer
+		arg2 string
+		arg3 string
+		arg4 []volume.Change
+		arg5 bool
+	}
+	outDiffReturns struct {
+		result1 error
+	}
+	outDiffReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStreamer) In(arg1 io.Reader, arg2 string, arg3 bool) (bool, error) {
+func (fake *FakeStreamer) In(arg1 io.Reader, arg2 string, arg3 string, arg4 bool) (bool, error) {
 	fake.inMutex.Lock()
 	ret, specificReturn := fake.inReturnsOnCall[len(fake.inArgsForCall)]
 	fake.inArgsForCall = append(fake.inArgsForCall, struct {
 		arg1 io.Reader
 		arg2 string
-		arg3 bool
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("In", []interface{}{arg1, arg2, arg3})
+		arg3 string
+		arg4 bool
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.InStub
+	fakeReturns := fake.inReturns
+	fake.recordInvocation("In", []interface{}{arg1, arg2, arg3, arg4})
 	fake.inMutex.Unlock()
-	if fake.InStub != nil {
-		return fake.InStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.inReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -67,17 +120,17 @@ func (fake *FakeStreamer) InCallCount() int {
 	return len(fake.inArgsForCall)
 }
 
-func (fake *FakeStreamer) InCalls(stub func(io.Reader, string, bool) (bool, error)) {
+func (fake *FakeStreamer) InCalls(stub func(io.Reader, string, string, bool) (bool, error)) {
 	fake.inMutex.Lock()
 	defer fake.inMutex.Unlock()
 	fake.InStub = stub
 }
 
-func (fake *FakeStreamer) InArgsForCall(i int) (io.Reader, string, bool) {
+func (fake *FakeStreamer) InArgsForCall(i int) (io.Reader, string, string, bool) {
 	fake.inMutex.RLock()
 	defer fake.inMutex.RUnlock()
 	argsForCall := fake.inArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStreamer) InReturns(result1 bool, result2 error) {
@@ -106,23 +159,161 @@ func (fake *FakeStreamer) InReturnsOnCall(i int, result1 bool, result2 error) {
 	}{result1, result2}
 }
 
-func (fake *FakeStreamer) Out(arg1 io.Writer, arg2 string, arg3 bool) error {
+func (fake *FakeStreamer) InLayer(arg1 io.Reader, arg2 string, arg3 string, arg4 bool) (bool, error) {
+	fake.inLayerMutex.Lock()
+	ret, specificReturn := fake.inLayerReturnsOnCall[len(fake.inLayerArgsForCall)]
+	fake.inLayerArgsForCall = append(fake.inLayerArgsForCall, struct {
+		arg1 io.Reader
+		arg2 string
+		arg3 string
+		arg4 bool
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.InLayerStub
+	fakeReturns := fake.inLayerReturns
+	fake.recordInvocation("InLayer", []interface{}{arg1, arg2, arg3, arg4})
+	fake.inLayerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStreamer) InLayerCallCount() int {
+	fake.inLayerMutex.RLock()
+	defer fake.inLayerMutex.RUnlock()
+	return len(fake.inLayerArgsForCall)
+}
+
+func (fake *FakeStreamer) InLayerCalls(stub func(io.Reader, string, string, bool) (bool, error)) {
+	fake.inLayerMutex.Lock()
+	defer fake.inLayerMutex.Unlock()
+	fake.InLayerStub = stub
+}
+
+func (fake *FakeStreamer) InLayerArgsForCall(i int) (io.Reader, string, string, bool) {
+	fake.inLayerMutex.RLock()
+	defer fake.inLayerMutex.RUnlock()
+	argsForCall := fake.inLayerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStreamer) InLayerReturns(result1 bool, result2 error) {
+	fake.inLayerMutex.Lock()
+	defer fake.inLayerMutex.Unlock()
+	fake.InLayerStub = nil
+	fake.inLayerReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStreamer) InLayerReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.inLayerMutex.Lock()
+	defer fake.inLayerMutex.Unlock()
+	fake.InLayerStub = nil
+	if fake.inLayerReturnsOnCall == nil {
+		fake.inLayerReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.inLayerReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStreamer) InResuming(arg1 io.Reader, arg2 string, arg3 string, arg4 bool, arg5 int, arg6 func(int)) (bool, error) {
+	fake.inResumingMutex.Lock()
+	ret, specificReturn := fake.inResumingReturnsOnCall[len(fake.inResumingArgsForCall)]
+	fake.inResumingArgsForCall = append(fake.inResumingArgsForCall, struct {
+		arg1 io.Reader
+		arg2 string
+		arg3 string
+		arg4 bool
+		arg5 int
+		arg6 func(int)
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.InResumingStub
+	fakeReturns := fake.inResumingReturns
+	fake.recordInvocation("InResuming", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.inResumingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStreamer) InResumingCallCount() int {
+	fake.inResumingMutex.RLock()
+	defer fake.inResumingMutex.RUnlock()
+	return len(fake.inResumingArgsForCall)
+}
+
+func (fake *FakeStreamer) InResumingCalls(stub func(io.Reader, string, string, bool, int, func(int)) (bool, error)) {
+	fake.inResumingMutex.Lock()
+	defer fake.inResumingMutex.Unlock()
+	fake.InResumingStub = stub
+}
+
+func (fake *FakeStreamer) InResumingArgsForCall(i int) (io.Reader, string, string, bool, int, func(int)) {
+	fake.inResumingMutex.RLock()
+	defer fake.inResumingMutex.RUnlock()
+	argsForCall := fake.inResumingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakeStreamer) InResumingReturns(result1 bool, result2 error) {
+	fake.inResumingMutex.Lock()
+	defer fake.inResumingMutex.Unlock()
+	fake.InResumingStub = nil
+	fake.inResumingReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStreamer) InResumingReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.inResumingMutex.Lock()
+	defer fake.inResumingMutex.Unlock()
+	fake.InResumingStub = nil
+	if fake.inResumingReturnsOnCall == nil {
+		fake.inResumingReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.inResumingReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStreamer) Out(arg1 io.Writer, arg2 string, arg3 string, arg4 bool) error {
 	fake.outMutex.Lock()
 	ret, specificReturn := fake.outReturnsOnCall[len(fake.outArgsForCall)]
 	fake.outArgsForCall = append(fake.outArgsForCall, struct {
 		arg1 io.Writer
 		arg2 string
-		arg3 bool
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("Out", []interface{}{arg1, arg2, arg3})
+		arg3 string
+		arg4 bool
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.OutStub
+	fakeReturns := fake.outReturns
+	fake.recordInvocation("Out", []interface{}{arg1, arg2, arg3, arg4})
 	fake.outMutex.Unlock()
-	if fake.OutStub != nil {
-		return fake.OutStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.outReturns
 	return fakeReturns.result1
 }
 
@@ -132,17 +323,17 @@ func (fake *FakeStreamer) OutCallCount() int {
 	return len(fake.outArgsForCall)
 }
 
-func (fake *FakeStreamer) OutCalls(stub func(io.Writer, string, bool) error) {
+func (fake *FakeStreamer) OutCalls(stub func(io.Writer, string, string, bool) error) {
 	fake.outMutex.Lock()
 	defer fake.outMutex.Unlock()
 	fake.OutStub = stub
 }
 
-func (fake *FakeStreamer) OutArgsForCall(i int) (io.Writer, string, bool) {
+func (fake *FakeStreamer) OutArgsForCall(i int) (io.Writer, string, string, bool) {
 	fake.outMutex.RLock()
 	defer fake.outMutex.RUnlock()
 	argsForCall := fake.outArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStreamer) OutReturns(result1 error) {
@@ -168,13 +359,89 @@ func (fake *FakeStreamer) OutReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeStreamer) OutDiff(arg1 io.Writer, arg2 string, arg3 string, arg4 []volume.Change, arg5 bool) error {
+	var arg4Copy []volume.Change
+	if arg4 != nil {
+		arg4Copy = make([]volume.Change, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.outDiffMutex.Lock()
+	ret, specificReturn := fake.outDiffReturnsOnCall[len(fake.outDiffArgsForCall)]
+	fake.outDiffArgsForCall = append(fake.outDiffArgsForCall, struct {
+		arg1 io.Writer
+		arg2 string
+		arg3 string
+		arg4 []volume.Change
+		arg5 bool
+	}{arg1, arg2, arg3, arg4Copy, arg5})
+	stub := fake.OutDiffStub
+	fakeReturns := fake.outDiffReturns
+	fake.recordInvocation("OutDiff", []interface{}{arg1, arg2, arg3, arg4Copy, arg5})
+	fake.outDiffMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStreamer) OutDiffCallCount() int {
+	fake.outDiffMutex.RLock()
+	defer fake.outDiffMutex.RUnlock()
+	return len(fake.outDiffArgsForCall)
+}
+
+func (fake *FakeStreamer) OutDiffCalls(stub func(io.Writer, string, string, []volume.Change, bool) error) {
+	fake.outDiffMutex.Lock()
+	defer fake.outDiffMutex.Unlock()
+	fake.OutDiffStub = stub
+}
+
+func (fake *FakeStreamer) OutDiffArgsForCall(i int) (io.Writer, string, string, []volume.Change, bool) {
+	fake.outDiffMutex.RLock()
+	defer fake.outDiffMutex.RUnlock()
+	argsForCall := fake.outDiffArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeStreamer) OutDiffReturns(result1 error) {
+	fake.outDiffMutex.Lock()
+	defer fake.outDiffMutex.Unlock()
+	fake.OutDiffStub = nil
+	fake.outDiffReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStreamer) OutDiffReturnsOnCall(i int, result1 error) {
+	fake.outDiffMutex.Lock()
+	defer fake.outDiffMutex.Unlock()
+	fake.OutDiffStub = nil
+	if fake.outDiffReturnsOnCall == nil {
+		fake.outDiffReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.outDiffReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStreamer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.inMutex.RLock()
 	defer fake.inMutex.RUnlock()
+	fake.inLayerMutex.RLock()
+	defer fake.inLayerMutex.RUnlock()
+	fake.inResumingMutex.RLock()
+	defer fake.inResumingMutex.RUnlock()
 	fake.outMutex.RLock()
 	defer fake.outMutex.RUnlock()
+	fake.outDiffMutex.RLock()
+	defer fake.outDiffMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
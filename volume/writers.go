@@ -0,0 +1,58 @@
+package volume
+
+import "sync"
+
+// writerCount counts the stream-ins in progress to each volume, so that
+// operations which must not overlap with them, e.g. sealing the volume, can
+// wait for them to finish. Writers are added while holding the volume's lock,
+// so a waiter holding it too cannot be overtaken by new ones.
+type writerCount struct {
+	mutex  sync.Mutex
+	idle   *sync.Cond
+	counts map[string]int
+}
+
+func newWriterCount() *writerCount {
+	writers := &writerCount{
+		counts: map[string]int{},
+	}
+
+	writers.idle = sync.NewCond(&writers.mutex)
+
+	return writers
+}
+
+func (writers *writerCount) add(handle string) {
+	writers.mutex.Lock()
+	writers.counts[handle]++
+	writers.mutex.Unlock()
+}
+
+func (writers *writerCount) done(handle string) {
+	writers.mutex.Lock()
+	defer writers.mutex.Unlock()
+
+	writers.counts[handle]--
+	if writers.counts[handle] == 0 {
+		delete(writers.counts, handle)
+		writers.idle.Broadcast()
+	}
+}
+
+// writing reports whether there are writers to the volume.
+func (writers *writerCount) writing(handle string) bool {
+	writers.mutex.Lock()
+	defer writers.mutex.Unlock()
+
+	return writers.counts[handle] > 0
+}
+
+// wait returns once there are no writers to the volume.
+func (writers *writerCount) wait(handle string) {
+	writers.mutex.Lock()
+	defer writers.mutex.Unlock()
+
+	for writers.counts[handle] > 0 {
+		writers.idle.Wait()
+	}
+}